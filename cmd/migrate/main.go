@@ -19,6 +19,7 @@
 //	go run cmd/migrate/main.go steps -steps -1       # Run 1 step backward
 //	go run cmd/migrate/main.go info                  # Show detailed migration information
 //	go run cmd/migrate/main.go create -name "add_users" -db postgres  # Create new migration
+//	go run cmd/migrate/main.go rehydrate -project ID -from 2025-01-01 -to 2025-01-31  # Restore archived telemetry
 package main
 
 import (
@@ -30,10 +31,14 @@ import (
 	"log"
 	"os"
 	"strings"
+	"time"
 
+	"brokle/internal/app"
 	"brokle/internal/config"
+	"brokle/internal/core/domain/observability"
 	"brokle/internal/migration"
 	"brokle/internal/seeder"
+	"brokle/pkg/logging"
 )
 
 // MigrateFlags holds all parsed command-line flags
@@ -45,6 +50,10 @@ type MigrateFlags struct {
 	DryRun   bool
 	Reset    bool
 	Verbose  bool
+	Project  string
+	Signal   string
+	From     string
+	To       string
 }
 
 // parseFlags parses flags from arguments, supporting flags before or after the command
@@ -71,6 +80,10 @@ func parseFlags(args []string) (*MigrateFlags, string, error) {
 	fs.BoolVar(&flags.DryRun, "dry-run", false, "Show what would be migrated without executing")
 	fs.BoolVar(&flags.Reset, "reset", false, "Reset existing data before seeding")
 	fs.BoolVar(&flags.Verbose, "verbose", false, "Verbose output for seeding")
+	fs.StringVar(&flags.Project, "project", "", "Project ID for rehydrate command")
+	fs.StringVar(&flags.Signal, "signal", "", "Comma-separated signals for rehydrate: traces, metrics, logs, genai (default: all)")
+	fs.StringVar(&flags.From, "from", "", "Start date (YYYY-MM-DD or RFC3339) for rehydrate command")
+	fs.StringVar(&flags.To, "to", "", "End date (YYYY-MM-DD or RFC3339, inclusive) for rehydrate command")
 
 	// Parse all arguments - fs.Parse will stop at the first non-flag arg
 	if err := fs.Parse(args); err != nil {
@@ -114,6 +127,14 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	// Rehydration goes through the application services, not the migration manager
+	if command == "rehydrate" {
+		if err := runRehydrate(context.Background(), cfg, flags); err != nil {
+			log.Fatalf("Rehydration failed: %v", err)
+		}
+		return
+	}
+
 	// Parse database selection
 	databases, err := parseDatabaseSelection(flags.Database)
 	if err != nil {
@@ -533,6 +554,7 @@ func printUsage() {
 	fmt.Println("  seed-rbac             Seed RBAC data only (permissions and roles)")
 	fmt.Println("  seed-pricing          Seed provider pricing data only")
 	fmt.Println("  seed-templates        Seed dashboard templates only")
+	fmt.Println("  rehydrate             Restore archived S3 telemetry into ClickHouse")
 	fmt.Println()
 	fmt.Println("FLAGS:")
 	fmt.Println("  -db string           Database to target: all, postgres, clickhouse (default: all)")
@@ -542,6 +564,10 @@ func printUsage() {
 	fmt.Println("  -dry-run             Show what would happen without executing")
 	fmt.Println("  -reset               Reset existing data before seeding (DANGEROUS)")
 	fmt.Println("  -verbose             Verbose output for seeding operations")
+	fmt.Println("  -project string      Project ID for rehydrate command")
+	fmt.Println("  -signal string       Signals to rehydrate: traces,metrics,logs,genai (default: all)")
+	fmt.Println("  -from string         Rehydrate start date (YYYY-MM-DD or RFC3339)")
+	fmt.Println("  -to string           Rehydrate end date, inclusive (YYYY-MM-DD or RFC3339)")
 	fmt.Println()
 	fmt.Println("EXAMPLES:")
 	fmt.Println("  migrate up                              # Run all pending migrations")
@@ -563,6 +589,8 @@ func printUsage() {
 	fmt.Println("  migrate seed-pricing -reset             # Reset and reseed pricing")
 	fmt.Println("  migrate seed-templates                  # Seed dashboard templates only")
 	fmt.Println("  migrate seed-templates -reset -verbose  # Reset and reseed templates")
+	fmt.Println("  migrate rehydrate -project ID -from 2025-01-01 -to 2025-01-31 -dry-run  # Count archived records")
+	fmt.Println("  migrate rehydrate -project ID -signal traces -from 2025-01-01 -to 2025-01-07")
 	fmt.Println()
 	fmt.Println("NOTE:")
 	fmt.Println("  Flags can be placed before or after the command:")
@@ -696,3 +724,88 @@ func runTemplateSeeding(ctx context.Context, cfg *config.Config, reset, dryRun,
 	// Run template seeding
 	return s.SeedTemplates(ctx, options)
 }
+
+// runRehydrate restores archived telemetry of a project from S3 into ClickHouse
+func runRehydrate(ctx context.Context, cfg *config.Config, flags *MigrateFlags) error {
+	if flags.Project == "" {
+		return errors.New("project ID is required for rehydrate command (use -project flag)")
+	}
+	from, err := parseRehydrateTime(flags.From, false)
+	if err != nil {
+		return fmt.Errorf("invalid -from: %w", err)
+	}
+	to, err := parseRehydrateTime(flags.To, true)
+	if err != nil {
+		return fmt.Errorf("invalid -to: %w", err)
+	}
+
+	var signals []string
+	if flags.Signal != "" {
+		for _, signal := range strings.Split(flags.Signal, ",") {
+			signals = append(signals, strings.TrimSpace(signal))
+		}
+	}
+
+	logger := logging.NewLoggerWithFormat(logging.ParseLevel(cfg.Logging.Level), cfg.Logging.Format)
+	core, err := app.ProvideCore(cfg, logger)
+	if err != nil {
+		return fmt.Errorf("failed to initialize application core: %w", err)
+	}
+	defer func() {
+		_ = core.Databases.Postgres.Close()
+		_ = core.Databases.ClickHouse.Close()
+		_ = core.Databases.Redis.Close()
+	}()
+
+	services := app.ProvideWorkerServices(core)
+	rehydrationService := services.Observability.RehydrationService
+	if rehydrationService == nil {
+		return errors.New("blob storage is not configured (set BLOB_STORAGE_PROVIDER and BLOB_STORAGE_BUCKET_NAME)")
+	}
+
+	if flags.DryRun {
+		fmt.Println("🔍 DRY RUN: counting archived records without writing")
+	}
+
+	result, err := rehydrationService.Rehydrate(ctx, &observability.RehydrateRequest{
+		ProjectID: flags.Project,
+		Signals:   signals,
+		From:      from,
+		To:        to,
+		DryRun:    flags.DryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Files scanned:        %d\n", result.FilesScanned)
+	fmt.Printf("Records read:         %d\n", result.RecordsRead)
+	fmt.Printf("Records restored:     %d\n", result.RecordsRestored)
+	fmt.Printf("Duplicates skipped:   %d\n", result.RecordsDuplicate)
+	fmt.Printf("Outside date range:   %d\n", result.RecordsOutOfRange)
+	fmt.Printf("Records failed:       %d\n", result.RecordsFailed)
+	if result.RecordsFailed > 0 {
+		fmt.Println("⚠️  Rehydration completed with failures, check logs for details")
+		return nil
+	}
+	fmt.Println("✅ Rehydration completed successfully")
+	return nil
+}
+
+// parseRehydrateTime accepts RFC3339 or a UTC date; a date used as end bound covers the whole day
+func parseRehydrateTime(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, errors.New("value is required")
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected YYYY-MM-DD or RFC3339, got %q", value)
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return t, nil
+}
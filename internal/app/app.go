@@ -37,7 +37,7 @@ func NewServer(cfg *config.Config) (*App, error) {
 	}
 
 	core.Services = ProvideServerServices(core)
-	core.Enterprise = ProvideEnterpriseServices(cfg, logger)
+	core.Enterprise = ProvideEnterpriseServices(core)
 
 	server, err := ProvideServer(core)
 	if err != nil {
@@ -163,6 +163,14 @@ func (a *App) Start() error {
 			a.logger.Info("Manual trigger worker started")
 		}
 
+		if a.providers.Workers.ExperimentRunWorker != nil {
+			if err := a.providers.Workers.ExperimentRunWorker.Start(context.Background()); err != nil {
+				a.logger.Error("Failed to start experiment run worker", "error", err)
+				return err
+			}
+			a.logger.Info("Experiment run worker started")
+		}
+
 		// Start usage aggregation worker (syncs ClickHouse usage to PostgreSQL billing)
 		if a.providers.Workers.UsageAggregationWorker != nil {
 			a.providers.Workers.UsageAggregationWorker.Start()
@@ -180,6 +188,36 @@ func (a *App) Start() error {
 			a.providers.Workers.LockExpiryWorker.Start()
 			a.logger.Info("Annotation lock expiry worker started")
 		}
+
+		// Start retention worker (purges telemetry past each project's retention policy)
+		if a.providers.Workers.RetentionWorker != nil {
+			a.providers.Workers.RetentionWorker.Start()
+			a.logger.Info("Retention worker started")
+		}
+
+		// Start notification worker before the alert worker that queues into it
+		if a.providers.Workers.NotificationWorker != nil {
+			a.providers.Workers.NotificationWorker.Start()
+			a.logger.Info("Notification worker started")
+		}
+
+		// Start alert worker (evaluates query-based alert rules)
+		if a.providers.Workers.AlertWorker != nil {
+			a.providers.Workers.AlertWorker.Start()
+			a.logger.Info("Alert worker started")
+		}
+
+		// Start webhook delivery worker (sends queued subscription events)
+		if a.providers.Workers.WebhookDeliveryWorker != nil {
+			a.providers.Workers.WebhookDeliveryWorker.Start()
+			a.logger.Info("Webhook delivery worker started")
+		}
+
+		// Start data subject worker (processes GDPR erasure and export requests)
+		if a.providers.Workers.DataSubjectWorker != nil {
+			a.providers.Workers.DataSubjectWorker.Start()
+			a.logger.Info("Data subject worker started")
+		}
 	}
 
 	return nil
@@ -237,6 +275,9 @@ func (a *App) doShutdown(ctx context.Context) error {
 				if a.providers.Workers.ManualTriggerWorker != nil {
 					a.providers.Workers.ManualTriggerWorker.Stop()
 				}
+				if a.providers.Workers.ExperimentRunWorker != nil {
+					a.providers.Workers.ExperimentRunWorker.Stop()
+				}
 				if a.providers.Workers.UsageAggregationWorker != nil {
 					a.providers.Workers.UsageAggregationWorker.Stop()
 				}
@@ -246,6 +287,21 @@ func (a *App) doShutdown(ctx context.Context) error {
 				if a.providers.Workers.LockExpiryWorker != nil {
 					a.providers.Workers.LockExpiryWorker.Stop()
 				}
+				if a.providers.Workers.RetentionWorker != nil {
+					a.providers.Workers.RetentionWorker.Stop()
+				}
+				if a.providers.Workers.AlertWorker != nil {
+					a.providers.Workers.AlertWorker.Stop()
+				}
+				if a.providers.Workers.WebhookDeliveryWorker != nil {
+					a.providers.Workers.WebhookDeliveryWorker.Stop()
+				}
+				if a.providers.Workers.DataSubjectWorker != nil {
+					a.providers.Workers.DataSubjectWorker.Stop()
+				}
+				if a.providers.Workers.NotificationWorker != nil {
+					a.providers.Workers.NotificationWorker.Stop()
+				}
 			}
 		}()
 	}
@@ -26,6 +26,7 @@ import (
 	promptDomain "brokle/internal/core/domain/prompt"
 	storageDomain "brokle/internal/core/domain/storage"
 	"brokle/internal/core/domain/user"
+	webhookDomain "brokle/internal/core/domain/webhook"
 	analyticsService "brokle/internal/core/services/analytics"
 	annotationService "brokle/internal/core/services/annotation"
 	authService "brokle/internal/core/services/auth"
@@ -41,6 +42,7 @@ import (
 	registrationService "brokle/internal/core/services/registration"
 	storageService "brokle/internal/core/services/storage"
 	userService "brokle/internal/core/services/user"
+	webhookService "brokle/internal/core/services/webhook"
 	eeAnalytics "brokle/internal/ee/analytics"
 	"brokle/internal/ee/compliance"
 	"brokle/internal/ee/rbac"
@@ -60,6 +62,7 @@ import (
 	promptRepo "brokle/internal/infrastructure/repository/prompt"
 	storageRepo "brokle/internal/infrastructure/repository/storage"
 	userRepo "brokle/internal/infrastructure/repository/user"
+	webhookRepo "brokle/internal/infrastructure/repository/webhook"
 	"brokle/internal/infrastructure/storage"
 	"brokle/internal/infrastructure/streams"
 	grpcTransport "brokle/internal/transport/grpc"
@@ -113,9 +116,15 @@ type WorkerContainer struct {
 	EvaluatorWorker          *evaluationWorker.EvaluatorWorker
 	EvaluationWorker         *evaluationWorker.EvaluationWorker
 	ManualTriggerWorker      *evaluationWorker.ManualTriggerWorker
+	ExperimentRunWorker      *evaluationWorker.ExperimentRunWorker
 	UsageAggregationWorker   *workers.UsageAggregationWorker
 	ContractExpirationWorker *workers.ContractExpirationWorker
 	LockExpiryWorker         *annotationWorker.LockExpiryWorker
+	RetentionWorker          *workers.RetentionWorker
+	NotificationWorker       *workers.NotificationWorker
+	AlertWorker              *workers.AlertWorker
+	WebhookDeliveryWorker    *workers.WebhookDeliveryWorker
+	DataSubjectWorker        *workers.DataSubjectWorker
 }
 
 type RepositoryContainer struct {
@@ -132,6 +141,7 @@ type RepositoryContainer struct {
 	Evaluation    *EvaluationRepositories
 	Dashboard     *DashboardRepositories
 	Annotation    *AnnotationRepositories
+	Webhook       *WebhookRepositories
 }
 
 type ServiceContainer struct {
@@ -153,6 +163,7 @@ type ServiceContainer struct {
 	Dashboard           *DashboardServices
 	Annotation          *AnnotationServices
 	Comment             commentDomain.Service
+	Webhook             webhookDomain.Service
 }
 
 type EnterpriseContainer struct {
@@ -176,6 +187,12 @@ type AuthRepositories struct {
 	Permission         auth.PermissionRepository
 	RolePermission     auth.RolePermissionRepository
 	AuditLog           auth.AuditLogRepository
+	RateLimitPolicy    auth.RateLimitPolicyRepository
+	RateLimiter        auth.RateLimiter
+	MFA                auth.MFARepository
+	SSOConnection      auth.SSOConnectionRepository
+	SCIM               auth.SCIMRepository
+	ProjectMember      auth.ProjectMemberRepository
 }
 
 type OrganizationRepositories struct {
@@ -195,6 +212,17 @@ type ObservabilityRepositories struct {
 	GenAIEvents            observability.GenAIEventsRepository
 	TelemetryDeduplication observability.TelemetryDeduplicationRepository
 	FilterPreset           observability.FilterPresetRepository
+	RetentionPolicy        observability.RetentionPolicyRepository
+	RetentionPurge         observability.RetentionPurgeRepository
+	AlertRule              observability.AlertRuleRepository
+	AlertEvent             observability.AlertEventRepository
+	SamplingPolicy         observability.SamplingPolicyRepository
+	TailSamplingBuffer     observability.TailSamplingBuffer
+	RedactionPolicy        observability.RedactionPolicyRepository
+	TraceShareLink         observability.TraceShareLinkRepository
+	DataSubjectRequest     observability.DataSubjectRequestRepository
+	DataSubjectTelemetry   observability.DataSubjectTelemetryRepository
+	DataSubjectRecord      observability.DataSubjectRecordRepository
 }
 
 type StorageRepositories struct {
@@ -211,6 +239,7 @@ type BillingRepositories struct {
 	OrganizationBilling billing.OrganizationBillingRepository
 	UsageBudget         billing.UsageBudgetRepository
 	UsageAlert          billing.UsageAlertRepository
+	EnforcementState    billing.EnforcementStateRepository
 	// Enterprise custom pricing repositories
 	Contract        billing.ContractRepository
 	VolumeTier      billing.VolumeDiscountTierRepository
@@ -262,6 +291,11 @@ type AnnotationRepositories struct {
 	Assignment annotationDomain.AssignmentRepository
 }
 
+type WebhookRepositories struct {
+	Subscription webhookDomain.SubscriptionRepository
+	Delivery     webhookDomain.DeliveryRepository
+}
+
 type UserServices struct {
 	User    user.UserService
 	Profile user.ProfileService
@@ -272,12 +306,16 @@ type AuthServices struct {
 	JWT                 auth.JWTService
 	Sessions            auth.SessionService
 	APIKey              auth.APIKeyService
+	RateLimit           auth.RateLimitService
 	Role                auth.RoleService
 	Permission          auth.PermissionService
 	OrganizationMembers auth.OrganizationMemberService
 	BlacklistedTokens   auth.BlacklistedTokenService
 	Scope               auth.ScopeService
+	AuditLogs           auth.AuditLogService
 	OAuthProvider       *authService.OAuthProviderService
+	SCIM                auth.SCIMService // Needs the member service; set by ProvideServerServices
+	ProjectAccess       auth.ProjectAccessService
 }
 
 type BillingServices struct {
@@ -285,6 +323,7 @@ type BillingServices struct {
 	// Usage-based billing services (Spans + GB + Scores)
 	BillableUsage billing.BillableUsageService
 	Budget        billing.BudgetService
+	Enforcement   billing.EnforcementService // nil when budget enforcement is disabled
 	// Enterprise custom pricing services
 	Pricing  billing.PricingService
 	Contract billing.ContractService
@@ -385,6 +424,9 @@ func ProvideWorkers(core *CoreContainer) (*WorkerContainer, error) {
 		core.Services.Observability.GenAIEventsService,
 		core.Services.Observability.ArchiveService, // S3 raw telemetry archival (nil if disabled)
 		&core.Config.Archive,                       // Archive config
+		core.Services.Webhook,                      // trace.created webhooks
+		core.Services.Observability.SamplingService, // Head/tail sampling policies
+		&core.Config.Sampling,
 	)
 
 	// Create evaluator worker using config
@@ -421,14 +463,16 @@ func ProvideWorkers(core *CoreContainer) (*WorkerContainer, error) {
 	builtinScorer := evaluationWorker.NewBuiltinScorer(core.Logger)
 	regexScorer := evaluationWorker.NewRegexScorer(core.Logger)
 
-	// LLM scorer requires credentials and execution services
-	var llmScorer evaluationWorker.Scorer
+	// LLM and pairwise scorers require credentials and execution services
+	var llmScorer, pairwiseScorer evaluationWorker.Scorer
 	if core.Services.Credentials != nil && core.Services.Prompt != nil {
-		llmScorer = evaluationWorker.NewLLMScorer(
+		judge := evaluationWorker.NewLLMScorer(
 			core.Services.Credentials.ProviderCredential,
 			core.Services.Prompt.Execution,
 			core.Logger,
 		)
+		llmScorer = judge
+		pairwiseScorer = evaluationWorker.NewPairwiseScorer(judge, core.Logger)
 		core.Logger.Info("LLM scorer initialized for evaluation worker")
 	} else {
 		core.Logger.Warn("LLM scorer disabled: credentials or prompt services not available")
@@ -452,6 +496,7 @@ func ProvideWorkers(core *CoreContainer) (*WorkerContainer, error) {
 		llmScorer,
 		builtinScorer,
 		regexScorer,
+		pairwiseScorer,
 		core.Logger,
 		evalWorkerConfig,
 	)
@@ -469,10 +514,52 @@ func ProvideWorkers(core *CoreContainer) (*WorkerContainer, error) {
 		core.Databases.Redis,
 		core.Services.Observability.TraceService,
 		core.Services.Evaluation.EvaluatorExecution,
+		core.Services.Evaluation.ExperimentItem,
 		core.Logger,
 		manualTriggerWorkerConfig,
 	)
 
+	// Experiment run worker executes dashboard experiments and needs credentials to call models
+	var experimentRunWorker *evaluationWorker.ExperimentRunWorker
+	if core.Services.Credentials != nil && core.Services.Prompt != nil {
+		runnerCfg := core.Config.Workers.ExperimentRunner
+		experimentRunWorker = evaluationWorker.NewExperimentRunWorker(
+			core.Databases.Redis,
+			core.Services.Evaluation.Experiment,
+			core.Services.Evaluation.ExperimentWizard,
+			core.Services.Evaluation.ExperimentItem,
+			core.Services.Evaluation.DatasetItem,
+			core.Services.Evaluation.DatasetVersion,
+			core.Services.Prompt.Prompt,
+			core.Services.Prompt.Execution,
+			core.Services.Credentials.ProviderCredential,
+			core.Repos.Organization.Project,
+			llmScorer,
+			builtinScorer,
+			regexScorer,
+			core.Logger,
+			&evaluationWorker.ExperimentRunWorkerConfig{
+				ConsumerGroup:     "experiment-run-workers",
+				ConsumerID:        "experiment-run-" + ulid.New().String(),
+				BlockDuration:     time.Second,
+				MaxConcurrentRuns: runnerCfg.MaxConcurrentRuns,
+				ItemConcurrency:   runnerCfg.ItemConcurrency,
+				MaxRetries:        runnerCfg.MaxRetries,
+				RetryBackoff:      time.Duration(runnerCfg.RetryBackoffMs) * time.Millisecond,
+				MaxRetryBackoff:   time.Duration(runnerCfg.MaxRetryBackoffMs) * time.Millisecond,
+			},
+		)
+	} else {
+		core.Logger.Warn("Experiment run worker disabled: credentials or prompt services not available")
+	}
+
+	// Create notification worker (delivers email, webhook and Slack jobs from a Redis stream)
+	emailSender, err := createEmailSender(&core.Config.External.Email, core.Logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create email sender: %w", err)
+	}
+	notificationWorker := workers.NewNotificationWorker(core.Config, core.Logger, core.Databases.Redis, emailSender)
+
 	// Create usage aggregation worker for billing (syncs ClickHouse → PostgreSQL)
 	usageAggWorker := workers.NewUsageAggregationWorker(
 		core.Config,
@@ -484,7 +571,9 @@ func ProvideWorkers(core *CoreContainer) (*WorkerContainer, error) {
 		core.Repos.Billing.UsageAlert,
 		core.Repos.Organization.Organization,
 		core.Services.Billing.Pricing, // PricingService for effective pricing and tier calculations
-		nil,                           // NotificationWorker - can be wired for email notifications
+		notificationWorker,            // Budget alert emails
+		core.Services.Webhook,         // budget.alert.triggered webhooks
+		core.Services.Billing.Enforcement,
 	)
 
 	// Create contract expiration worker (daily job to expire contracts past end_date)
@@ -502,14 +591,61 @@ func ProvideWorkers(core *CoreContainer) (*WorkerContainer, error) {
 		core.Repos.Annotation.Item,
 	)
 
+	// Create retention worker (purges telemetry past each project's retention policy)
+	var retentionWorker *workers.RetentionWorker
+	if core.Config.Retention.Enabled {
+		retentionWorker = workers.NewRetentionWorker(
+			core.Config,
+			core.Logger,
+			core.Services.Observability.RetentionService,
+		)
+	}
+
+	// Create alert worker (evaluates alert rules, notifies through the notification worker)
+	var alertWorker *workers.AlertWorker
+	if core.Config.Alerting.Enabled {
+		alertWorker = workers.NewAlertWorker(
+			core.Config,
+			core.Logger,
+			core.Services.Observability.AlertService,
+			notificationWorker,
+		)
+	}
+
+	// Create webhook delivery worker (sends queued webhook subscription events)
+	var webhookDeliveryWorker *workers.WebhookDeliveryWorker
+	if core.Config.Webhooks.Enabled {
+		webhookDeliveryWorker = workers.NewWebhookDeliveryWorker(
+			core.Config,
+			core.Logger,
+			core.Services.Webhook,
+		)
+	}
+
+	// Create data subject worker (processes GDPR erasure and export requests)
+	var dataSubjectWorker *workers.DataSubjectWorker
+	if core.Config.DataSubjects.Enabled {
+		dataSubjectWorker = workers.NewDataSubjectWorker(
+			core.Config,
+			core.Logger,
+			core.Services.Observability.DataSubjectService,
+		)
+	}
+
 	return &WorkerContainer{
 		TelemetryConsumer:        telemetryConsumer,
 		EvaluatorWorker:          evaluatorWorker,
 		EvaluationWorker:         evalWorker,
 		ManualTriggerWorker:      manualTriggerWorker,
+		ExperimentRunWorker:      experimentRunWorker,
 		UsageAggregationWorker:   usageAggWorker,
 		ContractExpirationWorker: contractExpWorker,
 		LockExpiryWorker:         lockExpiryWorker,
+		RetentionWorker:          retentionWorker,
+		NotificationWorker:       notificationWorker,
+		AlertWorker:              alertWorker,
+		WebhookDeliveryWorker:    webhookDeliveryWorker,
+		DataSubjectWorker:        dataSubjectWorker,
 	}, nil
 }
 
@@ -541,9 +677,12 @@ func ProvideServerServices(core *CoreContainer) *ServiceContainer {
 	repos := core.Repos
 	databases := core.Databases
 
-	billingServices := ProvideBillingServices(core.Transactor, repos.Billing, repos.Organization, logger)
+	// Webhook publisher is injected into the services that emit subscription events
+	webhookSvc := ProvideWebhookService(repos.Webhook, cfg, logger)
+
+	billingServices := ProvideBillingServices(core.Transactor, repos.Billing, repos.Organization, cfg, logger)
 	analyticsServices := ProvideAnalyticsServices(repos.Analytics)
-	observabilityServices := ProvideObservabilityServices(repos.Observability, repos.Storage, analyticsServices, databases.Redis, cfg, logger)
+	observabilityServices := ProvideObservabilityServices(repos.Observability, repos.Storage, repos.Organization, analyticsServices, databases.Redis, webhookSvc, cfg, logger)
 	authServices := ProvideAuthServices(cfg, repos.User, repos.Auth, repos.Organization, databases, logger)
 	userServices := ProvideUserServices(repos.User, repos.Auth, logger)
 	orgService, memberService, projectService, invitationService, settingsService :=
@@ -562,11 +701,17 @@ func ProvideServerServices(core *CoreContainer) *ServiceContainer {
 		billingServices.BillableUsage,
 	)
 
-	promptServices := ProvidePromptServices(core.Transactor, repos.Prompt, analyticsServices.ProviderPricing, cfg, logger)
+	promptServices := ProvidePromptServices(core.Transactor, repos.Prompt, analyticsServices.ProviderPricing, webhookSvc, cfg, logger)
 
 	// Config validation ensures AI_KEY_ENCRYPTION_KEY is valid, so credentials service is guaranteed to initialize
 	credentialsServices := ProvideCredentialsServices(repos.Credentials, repos.Analytics, cfg, logger)
 
+	// Audit the mutations served over HTTP; workers keep the undecorated services
+	promptServices.Prompt = promptService.NewAuditDecorator(promptServices.Prompt, authServices.AuditLogs)
+	credentialsServices.ProviderCredential = credentialsService.NewAuditDecorator(credentialsServices.ProviderCredential, authServices.AuditLogs)
+	observabilityServices.TraceService.SetAuditLogService(authServices.AuditLogs)
+	observabilityServices.DataSubjectService.SetAuditLogService(authServices.AuditLogs)
+
 	playgroundServices := ProvidePlaygroundServices(
 		repos.Playground,
 		credentialsServices.ProviderCredential,
@@ -575,11 +720,13 @@ func ProvideServerServices(core *CoreContainer) *ServiceContainer {
 		logger,
 	)
 
-	evaluationServices := ProvideEvaluationServices(core.Transactor, repos.Evaluation, repos.Observability, observabilityServices, repos.Prompt, databases.Redis, logger)
+	evaluationServices := ProvideEvaluationServices(core.Transactor, repos.Evaluation, repos.Observability, observabilityServices, repos.Prompt, databases.Redis, webhookSvc, logger)
+	evaluationServices.Dataset = evaluationService.NewDatasetAuditDecorator(evaluationServices.Dataset, authServices.AuditLogs)
+	evaluationServices.Evaluator = evaluationService.NewEvaluatorAuditDecorator(evaluationServices.Evaluator, authServices.AuditLogs)
 
 	dashboardServices := ProvideDashboardServices(repos.Dashboard, logger)
 
-	annotationServices := ProvideAnnotationServices(core.Transactor, repos.Annotation, evaluationServices, observabilityServices, repos.Organization, logger)
+	annotationServices := ProvideAnnotationServices(core.Transactor, repos.Annotation, evaluationServices, observabilityServices, repos.Organization, webhookSvc, logger)
 
 	// Comment service for trace/span comments (with reactions support)
 	commentSvc := commentService.NewCommentService(
@@ -589,6 +736,30 @@ func ProvideServerServices(core *CoreContainer) *ServiceContainer {
 		logger,
 	)
 
+	// SCIM provisioning joins users through the member service
+	authServices.SCIM = authService.NewSCIMService(
+		repos.Auth.SCIM,
+		repos.Auth.SSOConnection,
+		repos.User.User,
+		memberService,
+		authServices.Sessions,
+		authServices.BlacklistedTokens,
+		authServices.Role,
+		authServices.OrganizationMembers,
+		logger,
+	)
+
+	// Public trace share links, signed with the JWT secret and audited per view
+	observabilityServices.ShareLinkService = observabilityService.NewShareLinkService(
+		repos.Observability.TraceShareLink,
+		observabilityServices.TraceService,
+		repos.Auth.AuditLog,
+		repos.Organization.Project,
+		cfg.Auth.JWTSecret,
+		cfg.Server.AppURL,
+		logger,
+	)
+
 	// Overview service needs projectService and credentials repo (created after other services)
 	overviewSvc := analyticsService.NewOverviewService(
 		repos.Analytics.Overview,
@@ -617,6 +788,7 @@ func ProvideServerServices(core *CoreContainer) *ServiceContainer {
 		Dashboard:           dashboardServices,
 		Annotation:          annotationServices,
 		Comment:             commentSvc,
+		Webhook:             webhookSvc,
 	}
 }
 
@@ -626,12 +798,15 @@ func ProvideWorkerServices(core *CoreContainer) *ServiceContainer {
 	repos := core.Repos
 	databases := core.Databases
 
-	billingServices := ProvideBillingServices(core.Transactor, repos.Billing, repos.Organization, logger)
+	// Webhook service publishes ingestion and evaluation events and runs deliveries
+	webhookSvc := ProvideWebhookService(repos.Webhook, cfg, logger)
+
+	billingServices := ProvideBillingServices(core.Transactor, repos.Billing, repos.Organization, cfg, logger)
 	analyticsServices := ProvideAnalyticsServices(repos.Analytics)
-	observabilityServices := ProvideObservabilityServices(repos.Observability, repos.Storage, analyticsServices, databases.Redis, cfg, logger)
+	observabilityServices := ProvideObservabilityServices(repos.Observability, repos.Storage, repos.Organization, analyticsServices, databases.Redis, webhookSvc, cfg, logger)
 
 	// Prompt services needed for LLM scorer
-	promptServices := ProvidePromptServices(core.Transactor, repos.Prompt, analyticsServices.ProviderPricing, cfg, logger)
+	promptServices := ProvidePromptServices(core.Transactor, repos.Prompt, analyticsServices.ProviderPricing, webhookSvc, cfg, logger)
 
 	// Credentials services needed for LLM scorer (optional - only if encryption key configured)
 	var credentialsServices *CredentialsServices
@@ -642,7 +817,7 @@ func ProvideWorkerServices(core *CoreContainer) *ServiceContainer {
 	}
 
 	// Evaluation services needed for evaluator worker
-	evaluationServices := ProvideEvaluationServices(core.Transactor, repos.Evaluation, repos.Observability, observabilityServices, repos.Prompt, databases.Redis, logger)
+	evaluationServices := ProvideEvaluationServices(core.Transactor, repos.Evaluation, repos.Observability, observabilityServices, repos.Prompt, databases.Redis, webhookSvc, logger)
 
 	return &ServiceContainer{
 		User:                nil, // Worker doesn't need auth/user/org services
@@ -660,6 +835,7 @@ func ProvideWorkerServices(core *CoreContainer) *ServiceContainer {
 		Billing:             billingServices,
 		Analytics:           analyticsServices,
 		Evaluation:          evaluationServices, // Needed for evaluator worker
+		Webhook:             webhookSvc,         // Needed for webhook delivery worker
 	}
 }
 
@@ -715,6 +891,7 @@ func ProvideServer(core *CoreContainer) (*ServerContainer, error) {
 		core.Logger,
 		core.Services.Auth.Auth,
 		core.Services.Auth.APIKey,
+		core.Services.Auth.RateLimit,
 		core.Services.Auth.BlacklistedTokens,
 		core.Services.Registration,       // Registration service for signup
 		core.Services.Auth.OAuthProvider, // OAuth provider for Google/GitHub signup
@@ -751,6 +928,7 @@ func ProvideServer(core *CoreContainer) (*ServerContainer, error) {
 		// Usage-based billing services
 		core.Services.Billing.BillableUsage,
 		core.Services.Billing.Budget,
+		core.Services.Billing.Enforcement,
 		// Enterprise custom pricing services
 		core.Services.Billing.Contract,
 		core.Services.Billing.Pricing,
@@ -760,6 +938,17 @@ func ProvideServer(core *CoreContainer) (*ServerContainer, error) {
 		core.Services.Annotation.Assignment,
 		// Comment service
 		core.Services.Comment,
+		// Webhook subscriptions
+		core.Services.Webhook,
+		// Enterprise SSO
+		core.Enterprise.SSO,
+		// SCIM provisioning
+		core.Services.Auth.SCIM,
+		// Audit log
+		core.Services.Auth.AuditLogs,
+		// Per-project roles
+		core.Services.Auth.ProjectAccess,
+		core.Enterprise.RBAC,
 	)
 
 	httpServer := http.NewServer(
@@ -769,7 +958,9 @@ func ProvideServer(core *CoreContainer) (*ServerContainer, error) {
 		core.Services.Auth.JWT,
 		core.Services.Auth.BlacklistedTokens,
 		core.Services.Auth.OrganizationMembers,
+		core.Services.Auth.ProjectAccess,
 		core.Services.Auth.APIKey,
+		core.Services.Auth.RateLimit,
 		core.Databases.Redis.Client,
 	)
 
@@ -779,6 +970,8 @@ func ProvideServer(core *CoreContainer) (*ServerContainer, error) {
 		core.Services.Observability.StreamProducer,
 		core.Services.Observability.DeduplicationService,
 		core.Services.Observability.OTLPConverterService,
+		core.Services.Billing.Enforcement,
+		core.Services.Observability.SamplingService,
 		slogLogger,
 	)
 
@@ -797,6 +990,7 @@ func ProvideServer(core *CoreContainer) (*ServerContainer, error) {
 
 	grpcAuthInterceptor := grpcTransport.NewAuthInterceptor(
 		core.Services.Auth.APIKey,
+		core.Services.Auth.RateLimit,
 		slogLogger,
 	)
 
@@ -826,7 +1020,7 @@ func ProvideUserRepositories(db *gorm.DB) *UserRepositories {
 	}
 }
 
-func ProvideAuthRepositories(db *gorm.DB) *AuthRepositories {
+func ProvideAuthRepositories(db *gorm.DB, redisDB *database.RedisDB) *AuthRepositories {
 	return &AuthRepositories{
 		UserSession:        authRepo.NewUserSessionRepository(db),
 		BlacklistedToken:   authRepo.NewBlacklistedTokenRepository(db),
@@ -837,6 +1031,12 @@ func ProvideAuthRepositories(db *gorm.DB) *AuthRepositories {
 		Permission:         authRepo.NewPermissionRepository(db),
 		RolePermission:     authRepo.NewRolePermissionRepository(db),
 		AuditLog:           authRepo.NewAuditLogRepository(db),
+		RateLimitPolicy:    authRepo.NewRateLimitPolicyRepository(db),
+		RateLimiter:        authRepo.NewRateLimiterRepository(redisDB),
+		MFA:                authRepo.NewMFARepository(db),
+		SSOConnection:      authRepo.NewSSOConnectionRepository(db),
+		SCIM:               authRepo.NewSCIMRepository(db),
+		ProjectMember:      authRepo.NewProjectMemberRepository(db),
 	}
 }
 
@@ -860,6 +1060,17 @@ func ProvideObservabilityRepositories(clickhouseDB *database.ClickHouseDB, postg
 		GenAIEvents:            observabilityRepo.NewGenAIEventsRepository(clickhouseDB.Conn),
 		TelemetryDeduplication: observabilityRepo.NewTelemetryDeduplicationRepository(redisDB),
 		FilterPreset:           observabilityRepo.NewFilterPresetRepository(postgresDB),
+		RetentionPolicy:        observabilityRepo.NewRetentionPolicyRepository(postgresDB),
+		RetentionPurge:         observabilityRepo.NewRetentionPurgeRepository(clickhouseDB.Conn),
+		AlertRule:              observabilityRepo.NewAlertRuleRepository(postgresDB),
+		AlertEvent:             observabilityRepo.NewAlertEventRepository(postgresDB),
+		SamplingPolicy:         observabilityRepo.NewSamplingPolicyRepository(postgresDB),
+		TailSamplingBuffer:     observabilityRepo.NewTailSamplingBufferRepository(redisDB),
+		RedactionPolicy:        observabilityRepo.NewRedactionPolicyRepository(postgresDB),
+		TraceShareLink:         observabilityRepo.NewTraceShareLinkRepository(postgresDB),
+		DataSubjectRequest:     observabilityRepo.NewDataSubjectRequestRepository(postgresDB),
+		DataSubjectTelemetry:   observabilityRepo.NewDataSubjectTelemetryRepository(clickhouseDB.Conn),
+		DataSubjectRecord:      observabilityRepo.NewDataSubjectRecordRepository(postgresDB),
 	}
 }
 
@@ -869,7 +1080,7 @@ func ProvideStorageRepositories(clickhouseDB *database.ClickHouseDB) *StorageRep
 	}
 }
 
-func ProvideBillingRepositories(db *gorm.DB, clickhouseDB *database.ClickHouseDB, logger *slog.Logger) *BillingRepositories {
+func ProvideBillingRepositories(db *gorm.DB, clickhouseDB *database.ClickHouseDB, redisDB *database.RedisDB, logger *slog.Logger) *BillingRepositories {
 	return &BillingRepositories{
 		Usage:         billingRepo.NewUsageRepository(db, logger),
 		BillingRecord: billingRepo.NewBillingRecordRepository(db, logger),
@@ -880,6 +1091,7 @@ func ProvideBillingRepositories(db *gorm.DB, clickhouseDB *database.ClickHouseDB
 		OrganizationBilling: billingRepo.NewOrganizationBillingRepository(db),
 		UsageBudget:         billingRepo.NewUsageBudgetRepository(db),
 		UsageAlert:          billingRepo.NewUsageAlertRepository(db),
+		EnforcementState:    billingRepo.NewEnforcementStateRepository(redisDB),
 		// Enterprise custom pricing repositories
 		Contract:        billingRepo.NewContractRepository(db),
 		VolumeTier:      billingRepo.NewVolumeDiscountTierRepository(db),
@@ -946,14 +1158,21 @@ func ProvideAnnotationRepositories(db *gorm.DB) *AnnotationRepositories {
 	}
 }
 
+func ProvideWebhookRepositories(db *gorm.DB) *WebhookRepositories {
+	return &WebhookRepositories{
+		Subscription: webhookRepo.NewSubscriptionRepository(db),
+		Delivery:     webhookRepo.NewDeliveryRepository(db),
+	}
+}
+
 func ProvideRepositories(dbs *DatabaseContainer, logger *slog.Logger) *RepositoryContainer {
 	return &RepositoryContainer{
 		User:          ProvideUserRepositories(dbs.Postgres.DB),
-		Auth:          ProvideAuthRepositories(dbs.Postgres.DB),
+		Auth:          ProvideAuthRepositories(dbs.Postgres.DB, dbs.Redis),
 		Organization:  ProvideOrganizationRepositories(dbs.Postgres.DB),
 		Observability: ProvideObservabilityRepositories(dbs.ClickHouse, dbs.Postgres.DB, dbs.Redis),
 		Storage:       ProvideStorageRepositories(dbs.ClickHouse),
-		Billing:       ProvideBillingRepositories(dbs.Postgres.DB, dbs.ClickHouse, logger),
+		Billing:       ProvideBillingRepositories(dbs.Postgres.DB, dbs.ClickHouse, dbs.Redis, logger),
 		Analytics:     ProvideAnalyticsRepositories(dbs.Postgres.DB, dbs.ClickHouse),
 		Prompt:        ProvidePromptRepositories(dbs.Postgres.DB, dbs.Redis),
 		Credentials:   ProvideCredentialsRepositories(dbs.Postgres.DB),
@@ -961,6 +1180,7 @@ func ProvideRepositories(dbs *DatabaseContainer, logger *slog.Logger) *Repositor
 		Evaluation:    ProvideEvaluationRepositories(dbs.Postgres.DB),
 		Dashboard:     ProvideDashboardRepositories(dbs.Postgres.DB, dbs.ClickHouse),
 		Annotation:    ProvideAnnotationRepositories(dbs.Postgres.DB),
+		Webhook:       ProvideWebhookRepositories(dbs.Postgres.DB),
 	}
 }
 
@@ -1007,6 +1227,7 @@ func ProvideAuthServices(
 	roleService := authService.NewRoleService(
 		authRepos.Role,
 		authRepos.RolePermission,
+		authRepos.Permission,
 	)
 
 	orgMemberService := authService.NewOrganizationMemberService(
@@ -1025,11 +1246,30 @@ func ProvideAuthServices(
 		jwtService,
 	)
 
-	apiKeyService := authService.NewAPIKeyService(
+	rateLimitService := authService.NewRateLimitService(
+		authRepos.RateLimitPolicy,
+		authRepos.APIKey,
+		authRepos.RateLimiter,
+		&cfg.Auth,
+		logger,
+	)
+
+	// Audit trail for mutations across services; actors come from the request context
+	auditLogService := authService.NewAuditLogService(authRepos.AuditLog, orgRepos.Project, logger)
+
+	apiKeyService := authService.NewAPIKeyAuditDecorator(authService.NewAPIKeyService(
 		authRepos.APIKey,
 		authRepos.OrganizationMember,
 		orgRepos.Project,
-	)
+		rateLimitService,
+	), auditLogService)
+
+	// TOTP secrets share the AI key encryption key; MFA endpoints report unavailable without it
+	mfaEncryptor, err := encryption.NewServiceFromBase64(cfg.Encryption.AIKeyEncryptionKey)
+	if err != nil {
+		logger.Warn("MFA disabled: encryption key unavailable", "error", err)
+		mfaEncryptor = nil
+	}
 
 	coreAuthSvc := authService.NewAuthService(
 		&cfg.Auth,
@@ -1040,6 +1280,11 @@ func ProvideAuthServices(
 		authRepos.PasswordResetToken,
 		blacklistedTokenService,
 		databases.Redis.Client,
+		authRepos.MFA,
+		authRepos.OrganizationMember,
+		orgRepos.Settings,
+		mfaEncryptor,
+		authRepos.SSOConnection,
 	)
 
 	// Audit decorator for clean separation of concerns
@@ -1051,6 +1296,15 @@ func ProvideAuthServices(
 		authRepos.Permission,
 	)
 
+	// Per-project roles override the organization role inside their project
+	projectAccessService := authService.NewProjectAccessService(
+		orgRepos.Project,
+		orgRepos.Member,
+		authRepos.ProjectMember,
+		authRepos.Role,
+		orgRepos.Settings,
+	)
+
 	frontendURL := "http://localhost:3000"
 	if url := os.Getenv("NEXT_PUBLIC_APP_URL"); url != "" {
 		frontendURL = url
@@ -1066,12 +1320,15 @@ func ProvideAuthServices(
 		JWT:                 jwtService,
 		Sessions:            sessionService,
 		APIKey:              apiKeyService,
+		RateLimit:           rateLimitService,
 		Role:                roleService,
 		Permission:          permissionService,
 		OrganizationMembers: orgMemberService,
 		BlacklistedTokens:   blacklistedTokenService,
 		Scope:               scopeService,
+		AuditLogs:           auditLogService,
 		OAuthProvider:       oauthProvider,
+		ProjectAccess:       projectAccessService,
 	}
 }
 
@@ -1090,12 +1347,12 @@ func ProvideOrganizationServices(
 	organization.InvitationService,
 	organization.OrganizationSettingsService,
 ) {
-	memberSvc := orgService.NewMemberService(
+	memberSvc := orgService.NewMemberAuditDecorator(orgService.NewMemberService(
 		orgRepos.Member,
 		orgRepos.Organization,
 		userRepos.User,
 		authServices.Role,
-	)
+	), authServices.AuditLogs)
 
 	projectSvc := orgService.NewProjectService(
 		orgRepos.Project,
@@ -1146,8 +1403,10 @@ func ProvideOrganizationServices(
 func ProvideObservabilityServices(
 	observabilityRepos *ObservabilityRepositories,
 	storageRepos *StorageRepositories,
+	orgRepos *OrganizationRepositories,
 	analyticsServices *AnalyticsServices,
 	redisDB *database.RedisDB,
+	webhookPublisher webhookDomain.Publisher,
 	cfg *config.Config,
 	logger *slog.Logger,
 ) *observabilityService.ServiceRegistry {
@@ -1183,14 +1442,30 @@ func ProvideObservabilityServices(
 		observabilityRepos.Logs,
 		observabilityRepos.GenAIEvents,
 		observabilityRepos.FilterPreset,
+		observabilityRepos.RetentionPolicy,
+		observabilityRepos.RetentionPurge,
+		observabilityRepos.AlertRule,
+		observabilityRepos.AlertEvent,
+		observabilityRepos.SamplingPolicy,
+		observabilityRepos.TailSamplingBuffer,
+		observabilityRepos.RedactionPolicy,
+		observabilityRepos.DataSubjectRequest,
+		observabilityRepos.DataSubjectTelemetry,
+		observabilityRepos.DataSubjectRecord,
+		orgRepos.Project,
 		blobStorageSvc,
 		s3Client,
 		&cfg.Archive, // Archive config for S3 raw telemetry archival
+		&cfg.BlobStorage,
 		streamProducer,
 		deduplicationService,
 		telemetryService,
 		analyticsServices.ProviderPricing,
 		&cfg.Observability,
+		&cfg.Retention,
+		&cfg.Sampling,
+		&cfg.Encryption,
+		webhookPublisher,
 		logger,
 	)
 }
@@ -1199,6 +1474,7 @@ func ProvideBillingServices(
 	transactor common.Transactor,
 	billingRepos *BillingRepositories,
 	orgRepos *OrganizationRepositories,
+	cfg *config.Config,
 	logger *slog.Logger,
 ) *BillingServices {
 	orgService := &simpleBillingOrgService{logger: logger}
@@ -1229,10 +1505,22 @@ func ProvideBillingServices(
 		logger,
 	)
 
+	// Budget enforcement (block / sample / drop payloads at ingestion)
+	var enforcementService billing.EnforcementService
+	if cfg.BudgetEnforcement.Enabled {
+		enforcementService = billingService.NewEnforcementService(
+			billingRepos.UsageBudget,
+			billingRepos.EnforcementState,
+			time.Duration(cfg.BudgetEnforcement.StateTTLMinutes)*time.Minute,
+			logger,
+		)
+	}
+
 	budgetService := billingService.NewBudgetService(
 		billingRepos.UsageBudget,
 		billingRepos.UsageAlert,
 		orgRepos.Project,
+		enforcementService,
 		logger,
 	)
 
@@ -1250,6 +1538,7 @@ func ProvideBillingServices(
 		Billing:       billingServiceImpl,
 		BillableUsage: billableUsageService,
 		Budget:        budgetService,
+		Enforcement:   enforcementService,
 		Pricing:       pricingService,
 		Contract:      contractService,
 	}
@@ -1269,6 +1558,7 @@ func ProvidePromptServices(
 	transactor common.Transactor,
 	promptRepos *PromptRepositories,
 	pricingService analytics.ProviderPricingService,
+	webhookPublisher webhookDomain.Publisher,
 	cfg *config.Config,
 	logger *slog.Logger,
 ) *PromptServices {
@@ -1286,6 +1576,7 @@ func ProvidePromptServices(
 		promptRepos.ProtectedLabel,
 		promptRepos.Cache,
 		compilerSvc,
+		webhookPublisher,
 		logger,
 	)
 
@@ -1353,6 +1644,7 @@ func ProvideEvaluationServices(
 	observabilityServices *observabilityService.ServiceRegistry,
 	promptRepos *PromptRepositories,
 	redisDB *database.RedisDB,
+	webhookPublisher webhookDomain.Publisher,
 	logger *slog.Logger,
 ) *EvaluationServices {
 	scoreConfigSvc := evaluationService.NewScoreConfigService(
@@ -1383,8 +1675,10 @@ func ProvideEvaluationServices(
 
 	experimentSvc := evaluationService.NewExperimentService(
 		evaluationRepos.Experiment,
+		evaluationRepos.ExperimentItem,
 		evaluationRepos.Dataset,
 		observabilityRepos.Score,
+		webhookPublisher,
 		logger,
 	)
 
@@ -1405,6 +1699,7 @@ func ProvideEvaluationServices(
 		evaluationRepos.DatasetVersion,
 		promptRepos.Prompt,
 		promptRepos.Version,
+		redisDB,
 		logger,
 	)
 
@@ -1463,12 +1758,26 @@ func ProvideDashboardServices(
 	}
 }
 
+func ProvideWebhookService(
+	webhookRepos *WebhookRepositories,
+	cfg *config.Config,
+	logger *slog.Logger,
+) webhookDomain.Service {
+	return webhookService.NewWebhookService(
+		webhookRepos.Subscription,
+		webhookRepos.Delivery,
+		&cfg.Webhooks,
+		logger,
+	)
+}
+
 func ProvideAnnotationServices(
 	transactor common.Transactor,
 	annotationRepos *AnnotationRepositories,
 	evaluationServices *EvaluationServices,
 	observabilityServices *observabilityService.ServiceRegistry,
 	orgRepos *OrganizationRepositories,
+	webhookPublisher webhookDomain.Publisher,
 	logger *slog.Logger,
 ) *AnnotationServices {
 	queueSvc := annotationService.NewQueueService(
@@ -1486,6 +1795,7 @@ func ProvideAnnotationServices(
 		observabilityServices.ScoreService,
 		orgRepos.Project,
 		transactor,
+		webhookPublisher,
 		logger,
 	)
 
@@ -1521,11 +1831,40 @@ func (s *simpleBillingOrgService) GetPaymentMethod(ctx context.Context, orgID ul
 	return nil, nil
 }
 
-func ProvideEnterpriseServices(cfg *config.Config, logger *slog.Logger) *EnterpriseContainer {
+func ProvideEnterpriseServices(core *CoreContainer) *EnterpriseContainer {
+	cfg := core.Config
+
+	// OIDC client secrets share the AI key encryption key, like TOTP secrets
+	ssoEncryptor, err := encryption.NewServiceFromBase64(cfg.Encryption.AIKeyEncryptionKey)
+	if err != nil {
+		ssoEncryptor = nil
+	}
+
 	return &EnterpriseContainer{
-		SSO:        sso.New(),         // Uses stub or real based on build tags
-		RBAC:       rbac.New(),        // Uses stub or real based on build tags
-		Compliance: compliance.New(),  // Uses stub or real based on build tags
+		SSO: sso.NewProvider(cfg, sso.Dependencies{ // Real when licensed, stub otherwise
+			Connections: core.Repos.Auth.SSOConnection,
+			Users:       core.Repos.User.User,
+			Members:     core.Repos.Organization.Member,
+			Roles:       core.Services.Auth.Role,
+			Permissions: core.Services.Auth.OrganizationMembers,
+			Transactor:  core.Transactor,
+			Redis:       core.Databases.Redis.Client,
+			Encryptor:   ssoEncryptor,
+			Logger:      core.Logger,
+		}),
+		RBAC: rbac.NewProvider(cfg, rbac.Dependencies{ // Real when licensed, stub otherwise
+			ProjectMembers: core.Repos.Auth.ProjectMember,
+			Projects:       core.Repos.Organization.Project,
+			Members:        core.Repos.Organization.Member,
+			Roles:          core.Repos.Auth.Role,
+			AuditLogs:      core.Services.Auth.AuditLogs,
+			Logger:         core.Logger,
+		}),
+		Compliance: compliance.NewProvider(cfg, compliance.Dependencies{ // Real when licensed, stub otherwise
+			AuditLogs:           core.Repos.Auth.AuditLog,
+			DataSubjectRequests: core.Repos.Observability.DataSubjectRequest,
+			Logger:              core.Logger,
+		}),
 		Analytics:  eeAnalytics.New(), // Uses stub or real based on build tags
 	}
 }
@@ -28,6 +28,14 @@ type AuthConfig struct {
 	RateLimitPerIP       int           `mapstructure:"rate_limit_per_ip"`
 	RateLimitEnabled     bool          `mapstructure:"rate_limit_enabled"`
 	TokenRotationEnabled bool          `mapstructure:"token_rotation_enabled"`
+
+	// Default per-API-key limits (0 = unlimited), overridable per key and project
+	APIKeyRequestsPerSecond  int64 `mapstructure:"api_key_requests_per_second"`
+	APIKeySpansPerMinute     int64 `mapstructure:"api_key_spans_per_minute"`
+	APIKeyPayloadBytesPerDay int64 `mapstructure:"api_key_payload_bytes_per_day"`
+
+	// Public API base URL for SSO callbacks and SAML metadata (e.g., https://api.example.com)
+	SSOBaseURL string `mapstructure:"sso_base_url"`
 }
 
 // Validate ensures the auth configuration is valid and complete.
@@ -86,6 +94,9 @@ func (c *AuthConfig) Validate() error {
 		if c.RateLimitWindow <= 0 {
 			return errors.New("rate_limit_window must be greater than 0 when rate limiting is enabled")
 		}
+		if c.APIKeyRequestsPerSecond < 0 || c.APIKeySpansPerMinute < 0 || c.APIKeyPayloadBytesPerDay < 0 {
+			return errors.New("api key rate limits must not be negative")
+		}
 	}
 
 	return nil
@@ -125,6 +136,9 @@ func DefaultAuthConfig() AuthConfig {
 		RateLimitPerUser: 1000,
 		RateLimitWindow:  1 * time.Hour,
 
+		// Per-API-key defaults (requests only; span and byte quotas are opt-in)
+		APIKeyRequestsPerSecond: 100,
+
 		// JWT defaults (HS256 for development ease)
 		JWTSigningMethod: "HS256",
 		JWTIssuer:        "brokle",
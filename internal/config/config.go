@@ -11,6 +11,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"regexp"
 	"strings"
 	"time"
 
@@ -34,6 +35,12 @@ type Config struct {
 	Monitoring    MonitoringConfig    `mapstructure:"monitoring"`
 	Observability ObservabilityConfig `mapstructure:"observability"`
 	Archive       ArchiveConfig       `mapstructure:"archive"`
+	Retention     RetentionConfig     `mapstructure:"retention"`
+	Alerting      AlertingConfig      `mapstructure:"alerting"`
+	Webhooks      WebhooksConfig      `mapstructure:"webhooks"`
+	DataSubjects  DataSubjectsConfig  `mapstructure:"data_subjects"`
+	BudgetEnforcement BudgetEnforcementConfig `mapstructure:"budget_enforcement"`
+	Sampling      SamplingConfig      `mapstructure:"sampling"`
 	Logging       LoggingConfig       `mapstructure:"logging"`
 	Redis         RedisConfig         `mapstructure:"redis"`
 	Workers       WorkersConfig       `mapstructure:"workers"`
@@ -236,6 +243,68 @@ type ArchiveConfig struct {
 	PathPrefix           string `mapstructure:"path_prefix"`
 	CompressionLevel     int    `mapstructure:"compression_level"`
 	DefaultRetentionDays int    `mapstructure:"default_retention_days"`
+	QueryEndpoint        string `mapstructure:"query_endpoint"`         // S3 endpoint as reachable from ClickHouse; defaults to blob storage endpoint
+	QueryNamedCollection string `mapstructure:"query_named_collection"` // ClickHouse named collection holding the S3 credentials for archive queries
+}
+
+var namedCollectionPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Validate validates archive configuration. The named collection is written into
+// query text, so it must be a plain identifier.
+func (ac *ArchiveConfig) Validate() error {
+	if ac.QueryNamedCollection != "" && !namedCollectionPattern.MatchString(ac.QueryNamedCollection) {
+		return errors.New("ARCHIVE_QUERY_NAMED_COLLECTION must be a ClickHouse identifier")
+	}
+	return nil
+}
+
+// RetentionConfig contains telemetry data retention configuration.
+// Default*Days apply to projects without an organization or project policy.
+type RetentionConfig struct {
+	Enabled                bool `mapstructure:"enabled"`
+	DryRun                 bool `mapstructure:"dry_run"`          // Only report rows that would be purged
+	IntervalMinutes        int  `mapstructure:"interval_minutes"` // Enforcement interval (default: 360)
+	DefaultSpansDays       int  `mapstructure:"default_spans_days"`
+	DefaultLogsDays        int  `mapstructure:"default_logs_days"`
+	DefaultMetricsDays     int  `mapstructure:"default_metrics_days"`
+	DefaultScoresDays      int  `mapstructure:"default_scores_days"`
+	DefaultGenAIEventsDays int  `mapstructure:"default_genai_events_days"`
+}
+
+// AlertingConfig contains query-based alert evaluation configuration.
+type AlertingConfig struct {
+	Enabled         bool `mapstructure:"enabled"`
+	IntervalSeconds int  `mapstructure:"interval_seconds"` // Evaluation interval (default: 60)
+}
+
+// WebhooksConfig contains outbound webhook subscription delivery configuration.
+type WebhooksConfig struct {
+	Enabled             bool `mapstructure:"enabled"`
+	PollIntervalSeconds int  `mapstructure:"poll_interval_seconds"` // How often due deliveries are claimed (default: 5)
+	BatchSize           int  `mapstructure:"batch_size"`            // Deliveries sent per poll (default: 50)
+	TimeoutSeconds      int  `mapstructure:"timeout_seconds"`       // Per-request timeout (default: 10)
+	MaxAttempts         int  `mapstructure:"max_attempts"`          // Attempts before a delivery is marked failed (default: 8)
+	RetryBackoffSeconds int  `mapstructure:"retry_backoff_seconds"` // Base of the exponential backoff (default: 30)
+}
+
+// DataSubjectsConfig contains GDPR data subject request processing configuration.
+type DataSubjectsConfig struct {
+	Enabled             bool `mapstructure:"enabled"`
+	PollIntervalSeconds int  `mapstructure:"poll_interval_seconds"` // How often queued requests are claimed (default: 10)
+}
+
+// BudgetEnforcementConfig contains ingestion enforcement configuration for usage budgets.
+type BudgetEnforcementConfig struct {
+	Enabled         bool `mapstructure:"enabled"`
+	StateTTLMinutes int  `mapstructure:"state_ttl_minutes"` // Cached state expires (fails open) if not refreshed (default: 15)
+}
+
+// SamplingConfig contains per-project head and tail sampling configuration.
+type SamplingConfig struct {
+	Enabled                  bool `mapstructure:"enabled"`
+	TailFlushIntervalSeconds int  `mapstructure:"tail_flush_interval_seconds"` // How often buffered traces are checked for a decision (default: 1)
+	TailFlushBatchSize       int  `mapstructure:"tail_flush_batch_size"`       // Traces decided per flush (default: 200)
+	DecisionTTLMinutes       int  `mapstructure:"decision_ttl_minutes"`        // How long a tail decision applies to late spans (default: 60)
 }
 
 // WorkersConfig contains background worker configuration.
@@ -245,6 +314,7 @@ type WorkersConfig struct {
 	UsageSyncIntervalMinutes int              `mapstructure:"usage_sync_interval_minutes"` // Billing usage sync interval (default: 5)
 	AlertDeduplicationHours  int              `mapstructure:"alert_deduplication_hours"`   // Alert deduplication window (default: 24)
 	EvaluatorWorker          EvaluatorWorkerConfig `mapstructure:"evaluator_worker"`
+	ExperimentRunner         ExperimentRunnerConfig `mapstructure:"experiment_runner"`
 }
 
 // EvaluatorWorkerConfig contains evaluator worker configuration.
@@ -258,9 +328,23 @@ type EvaluatorWorkerConfig struct {
 	EvaluatorCacheTTL  string `mapstructure:"evaluator_cache_ttl"`
 }
 
+// ExperimentRunnerConfig contains configuration for the worker that executes dashboard experiments.
+type ExperimentRunnerConfig struct {
+	MaxConcurrentRuns int `mapstructure:"max_concurrent_runs"`  // Experiments executed at once per instance (default: 2)
+	ItemConcurrency   int `mapstructure:"item_concurrency"`     // Dataset items in flight per experiment (default: 5)
+	MaxRetries        int `mapstructure:"max_retries"`          // Retries of a provider 429 per item (default: 5)
+	RetryBackoffMs    int `mapstructure:"retry_backoff_ms"`     // First 429 backoff, doubled per retry (default: 1000)
+	MaxRetryBackoffMs int `mapstructure:"max_retry_backoff_ms"` // Cap on the 429 backoff (default: 30000)
+}
+
 // NotificationsConfig contains notification system configuration.
 type NotificationsConfig struct {
-	AlertWebhookURL string `mapstructure:"alert_webhook_url"`
+	AlertWebhookURL       string `mapstructure:"alert_webhook_url"`
+	SlackWebhookURL       string `mapstructure:"slack_webhook_url"`       // Incoming webhook for platform system alerts
+	WebhookSigningSecret  string `mapstructure:"webhook_signing_secret"`  // HMAC key for platform system alert webhooks
+	WebhookTimeoutSeconds int    `mapstructure:"webhook_timeout_seconds"` // Outbound HTTP timeout (default: 10)
+	MaxRetries            int    `mapstructure:"max_retries"`             // Attempts before dead-lettering (default: 5)
+	RetryBackoffSeconds   int    `mapstructure:"retry_backoff_seconds"`   // First retry delay, doubled per attempt (default: 30)
 }
 
 // BlobStorageConfig contains blob storage configuration for large payload offloading
@@ -321,6 +405,10 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("encryption config validation failed: %w", err)
 	}
 
+	if err := c.Archive.Validate(); err != nil {
+		return fmt.Errorf("archive config validation failed: %w", err)
+	}
+
 	return nil
 }
 
@@ -698,6 +786,84 @@ func Load() (*Config, error) {
 	viper.BindEnv("archive.compression_level", "ARCHIVE_COMPRESSION_LEVEL")
 	//nolint:errcheck
 	viper.BindEnv("archive.default_retention_days", "ARCHIVE_DEFAULT_RETENTION_DAYS")
+	//nolint:errcheck
+	viper.BindEnv("archive.query_endpoint", "ARCHIVE_QUERY_ENDPOINT")
+	//nolint:errcheck
+	viper.BindEnv("archive.query_named_collection", "ARCHIVE_QUERY_NAMED_COLLECTION")
+
+	// Retention configuration (ClickHouse telemetry purge worker)
+	//nolint:errcheck
+	viper.BindEnv("retention.enabled", "RETENTION_ENABLED")
+	//nolint:errcheck
+	viper.BindEnv("retention.dry_run", "RETENTION_DRY_RUN")
+	//nolint:errcheck
+	viper.BindEnv("retention.interval_minutes", "RETENTION_INTERVAL_MINUTES")
+	//nolint:errcheck
+	viper.BindEnv("retention.default_spans_days", "RETENTION_DEFAULT_SPANS_DAYS")
+	//nolint:errcheck
+	viper.BindEnv("retention.default_logs_days", "RETENTION_DEFAULT_LOGS_DAYS")
+	//nolint:errcheck
+	viper.BindEnv("retention.default_metrics_days", "RETENTION_DEFAULT_METRICS_DAYS")
+	//nolint:errcheck
+	viper.BindEnv("retention.default_scores_days", "RETENTION_DEFAULT_SCORES_DAYS")
+	//nolint:errcheck
+	viper.BindEnv("retention.default_genai_events_days", "RETENTION_DEFAULT_GENAI_EVENTS_DAYS")
+
+	// Notification delivery configuration
+	//nolint:errcheck
+	viper.BindEnv("notifications.alert_webhook_url", "NOTIFICATIONS_ALERT_WEBHOOK_URL")
+	//nolint:errcheck
+	viper.BindEnv("notifications.slack_webhook_url", "NOTIFICATIONS_SLACK_WEBHOOK_URL")
+	//nolint:errcheck
+	viper.BindEnv("notifications.webhook_signing_secret", "NOTIFICATIONS_WEBHOOK_SIGNING_SECRET")
+	//nolint:errcheck
+	viper.BindEnv("notifications.webhook_timeout_seconds", "NOTIFICATIONS_WEBHOOK_TIMEOUT_SECONDS")
+	//nolint:errcheck
+	viper.BindEnv("notifications.max_retries", "NOTIFICATIONS_MAX_RETRIES")
+	//nolint:errcheck
+	viper.BindEnv("notifications.retry_backoff_seconds", "NOTIFICATIONS_RETRY_BACKOFF_SECONDS")
+
+	// Alerting configuration (alert rule evaluation worker)
+	//nolint:errcheck
+	viper.BindEnv("alerting.enabled", "ALERTING_ENABLED")
+	//nolint:errcheck
+	viper.BindEnv("alerting.interval_seconds", "ALERTING_INTERVAL_SECONDS")
+
+	// Webhook subscription delivery configuration
+	//nolint:errcheck
+	viper.BindEnv("webhooks.enabled", "WEBHOOKS_ENABLED")
+	//nolint:errcheck
+	viper.BindEnv("webhooks.poll_interval_seconds", "WEBHOOKS_POLL_INTERVAL_SECONDS")
+	//nolint:errcheck
+	viper.BindEnv("webhooks.batch_size", "WEBHOOKS_BATCH_SIZE")
+	//nolint:errcheck
+	viper.BindEnv("webhooks.timeout_seconds", "WEBHOOKS_TIMEOUT_SECONDS")
+	//nolint:errcheck
+	viper.BindEnv("webhooks.max_attempts", "WEBHOOKS_MAX_ATTEMPTS")
+	//nolint:errcheck
+	viper.BindEnv("webhooks.retry_backoff_seconds", "WEBHOOKS_RETRY_BACKOFF_SECONDS")
+
+	// GDPR data subject request configuration
+	//nolint:errcheck
+	viper.BindEnv("data_subjects.enabled", "DATA_SUBJECTS_ENABLED")
+	//nolint:errcheck
+	viper.BindEnv("data_subjects.poll_interval_seconds", "DATA_SUBJECTS_POLL_INTERVAL_SECONDS")
+
+	// Budget enforcement configuration
+	//nolint:errcheck
+	viper.BindEnv("budget_enforcement.enabled", "BUDGET_ENFORCEMENT_ENABLED")
+	//nolint:errcheck
+	viper.BindEnv("budget_enforcement.state_ttl_minutes", "BUDGET_ENFORCEMENT_STATE_TTL_MINUTES")
+
+	// Head and tail sampling configuration
+	//nolint:errcheck
+	viper.BindEnv("sampling.enabled", "SAMPLING_ENABLED")
+	//nolint:errcheck
+	viper.BindEnv("sampling.tail_flush_interval_seconds", "SAMPLING_TAIL_FLUSH_INTERVAL_SECONDS")
+	//nolint:errcheck
+	viper.BindEnv("sampling.tail_flush_batch_size", "SAMPLING_TAIL_FLUSH_BATCH_SIZE")
+	//nolint:errcheck
+	viper.BindEnv("sampling.decision_ttl_minutes", "SAMPLING_DECISION_TTL_MINUTES")
 
 	//nolint:errcheck
 	viper.BindEnv("external.stripe.publishable_key", "STRIPE_PUBLISHABLE_KEY")
@@ -756,6 +922,12 @@ func Load() (*Config, error) {
 	//nolint:errcheck
 	viper.BindEnv("auth.rate_limit_window", "RATE_LIMIT_WINDOW")
 	//nolint:errcheck
+	viper.BindEnv("auth.api_key_requests_per_second", "RATE_LIMIT_API_KEY_REQUESTS_PER_SECOND")
+	//nolint:errcheck
+	viper.BindEnv("auth.api_key_spans_per_minute", "RATE_LIMIT_API_KEY_SPANS_PER_MINUTE")
+	//nolint:errcheck
+	viper.BindEnv("auth.api_key_payload_bytes_per_day", "RATE_LIMIT_API_KEY_BYTES_PER_DAY")
+	//nolint:errcheck
 	viper.BindEnv("auth.jwt_signing_method", "JWT_SIGNING_METHOD")
 	//nolint:errcheck
 	viper.BindEnv("auth.jwt_issuer", "JWT_ISSUER")
@@ -787,6 +959,8 @@ func Load() (*Config, error) {
 	viper.BindEnv("auth.github_client_secret", "GITHUB_CLIENT_SECRET")
 	//nolint:errcheck
 	viper.BindEnv("auth.github_redirect_url", "GITHUB_REDIRECT_URL")
+	//nolint:errcheck
+	viper.BindEnv("auth.sso_base_url", "SSO_BASE_URL")
 
 	// Database configuration (granular environment variables)
 	//nolint:errcheck
@@ -952,6 +1126,9 @@ func setDefaults() {
 	viper.SetDefault("auth.rate_limit_per_ip", 100)
 	viper.SetDefault("auth.rate_limit_per_user", 1000)
 	viper.SetDefault("auth.rate_limit_window", "1h")
+	viper.SetDefault("auth.api_key_requests_per_second", 100)
+	viper.SetDefault("auth.api_key_spans_per_minute", 0)     // 0 = unlimited
+	viper.SetDefault("auth.api_key_payload_bytes_per_day", 0) // 0 = unlimited
 	viper.SetDefault("auth.jwt_signing_method", "HS256") // HS256 for development ease
 	viper.SetDefault("auth.jwt_issuer", "brokle")
 	viper.SetDefault("auth.jwt_secret", "") // Must be set in environment for HS256
@@ -967,6 +1144,7 @@ func setDefaults() {
 	viper.SetDefault("auth.github_client_id", "")
 	viper.SetDefault("auth.github_client_secret", "")
 	viper.SetDefault("auth.github_redirect_url", "")
+	viper.SetDefault("auth.sso_base_url", "http://localhost:8080")
 
 	// Logging defaults
 	viper.SetDefault("logging.level", "info")
@@ -1060,6 +1238,43 @@ func setDefaults() {
 	viper.SetDefault("archive.compression_level", 3)
 	viper.SetDefault("archive.default_retention_days", 2555)
 
+	// Retention defaults match the TTLs the signal tables were created with
+	viper.SetDefault("retention.enabled", true)
+	viper.SetDefault("retention.dry_run", false)
+	viper.SetDefault("retention.interval_minutes", 360)
+	viper.SetDefault("retention.default_spans_days", 365)
+	viper.SetDefault("retention.default_logs_days", 90)
+	viper.SetDefault("retention.default_metrics_days", 365)
+	viper.SetDefault("retention.default_scores_days", 365)
+	viper.SetDefault("retention.default_genai_events_days", 365)
+
+	viper.SetDefault("notifications.webhook_timeout_seconds", 10)
+	viper.SetDefault("notifications.max_retries", 5)
+	viper.SetDefault("notifications.retry_backoff_seconds", 30)
+
+	viper.SetDefault("alerting.enabled", true)
+	viper.SetDefault("alerting.interval_seconds", 60)
+
+	viper.SetDefault("webhooks.enabled", true)
+	viper.SetDefault("webhooks.poll_interval_seconds", 5)
+	viper.SetDefault("webhooks.batch_size", 50)
+	viper.SetDefault("webhooks.timeout_seconds", 10)
+	viper.SetDefault("webhooks.max_attempts", 8)
+	viper.SetDefault("webhooks.retry_backoff_seconds", 30)
+
+	viper.SetDefault("data_subjects.enabled", true)
+	viper.SetDefault("data_subjects.poll_interval_seconds", 10)
+
+	// Budget enforcement defaults
+	viper.SetDefault("budget_enforcement.enabled", true)
+	viper.SetDefault("budget_enforcement.state_ttl_minutes", 15)
+
+	// Sampling defaults
+	viper.SetDefault("sampling.enabled", true)
+	viper.SetDefault("sampling.tail_flush_interval_seconds", 1)
+	viper.SetDefault("sampling.tail_flush_batch_size", 200)
+	viper.SetDefault("sampling.decision_ttl_minutes", 60)
+
 	// Encryption defaults (must be set in production via AI_KEY_ENCRYPTION_KEY env var)
 	viper.SetDefault("encryption.ai_key_encryption_key", "")
 
@@ -1071,6 +1286,13 @@ func setDefaults() {
 	viper.SetDefault("workers.evaluator_worker.discovery_interval", "30s")
 	viper.SetDefault("workers.evaluator_worker.max_streams_per_read", 10)
 	viper.SetDefault("workers.evaluator_worker.evaluator_cache_ttl", "30s")
+
+	// Experiment runner defaults
+	viper.SetDefault("workers.experiment_runner.max_concurrent_runs", 2)
+	viper.SetDefault("workers.experiment_runner.item_concurrency", 5)
+	viper.SetDefault("workers.experiment_runner.max_retries", 5)
+	viper.SetDefault("workers.experiment_runner.retry_backoff_ms", 1000)
+	viper.SetDefault("workers.experiment_runner.max_retry_backoff_ms", 30000)
 }
 
 // GetServerAddress returns the server address string.
@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"context"
+
+	"brokle/pkg/ulid"
+)
+
+// AuditExportFormat is the file format of an audit log export.
+type AuditExportFormat string
+
+const (
+	AuditExportCSV  AuditExportFormat = "csv"
+	AuditExportJSON AuditExportFormat = "json"
+)
+
+// IsValid reports whether the format is supported.
+func (f AuditExportFormat) IsValid() bool {
+	return f == AuditExportCSV || f == AuditExportJSON
+}
+
+// AuditActor identifies who performed a request. The HTTP auth middlewares
+// attach it to the request context so services can attribute mutations.
+type AuditActor struct {
+	UserID    *ulid.ULID
+	APIKeyID  *ulid.ULID
+	IPAddress string
+	UserAgent string
+}
+
+type auditActorKey struct{}
+
+// WithAuditActor returns a context carrying the actor of the current request.
+func WithAuditActor(ctx context.Context, actor AuditActor) context.Context {
+	return context.WithValue(ctx, auditActorKey{}, actor)
+}
+
+// AuditActorFromContext returns the actor attached by WithAuditActor.
+func AuditActorFromContext(ctx context.Context) (AuditActor, bool) {
+	actor, ok := ctx.Value(auditActorKey{}).(AuditActor)
+	return actor, ok
+}
+
+// AuditEvent describes a mutation to record. Before and After are snapshots of
+// the resource (nil on create and delete respectively); the audit log stores the
+// fields that differ between them.
+type AuditEvent struct {
+	Before         interface{}
+	After          interface{}
+	Metadata       map[string]interface{}
+	UserID         *ulid.ULID // Acting user when the caller knows it; defaults to the context actor
+	OrganizationID *ulid.ULID // Resolved from ProjectID when nil
+	ProjectID      *ulid.ULID
+	Action         string
+	Resource       string
+	ResourceID     string
+}
+
+// AuditChange is one changed field of an audited resource.
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
@@ -6,12 +6,14 @@ package auth
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
 
 	"brokle/pkg/ulid"
 
+	"github.com/lib/pq"
 	"gorm.io/gorm"
 )
 
@@ -78,6 +80,7 @@ type OrganizationRepository interface {
 // Security: Full key is hashed with SHA-256 (deterministic, enables O(1) lookup)
 // Organization is derived via projects.organization_id (no redundant storage)
 // Status: Determined by deleted_at (soft delete) and expires_at (expiration)
+// Access: Limited to Scopes, a subset of APIKeyScopes enforced on every SDK route
 type APIKey struct {
 	CreatedAt  time.Time      `json:"created_at"`
 	UpdatedAt  time.Time      `json:"updated_at"`
@@ -87,6 +90,7 @@ type APIKey struct {
 	KeyHash    string         `json:"-" gorm:"size:255;unique;not null;index"`
 	KeyPreview string         `json:"key_preview" gorm:"size:50;not null"`
 	Name       string         `json:"name" gorm:"size:255;not null"`
+	Scopes     pq.StringArray `json:"scopes" gorm:"type:text[];not null" swaggertype:"array,string"`
 	ID         ulid.ULID      `json:"id" gorm:"type:char(26);primaryKey"`
 	ProjectID  ulid.ULID      `json:"project_id" gorm:"type:char(26);not null;index"`
 	UserID     ulid.ULID      `json:"user_id" gorm:"type:char(26);not null;index"`
@@ -116,14 +120,17 @@ type OrganizationMember struct {
 	RoleID         ulid.ULID  `json:"role_id" gorm:"type:char(26);not null"`
 }
 
-// ProjectMember represents user membership in a project with a single role (future)
+// ProjectMember assigns a user a single role in a project. Within that project
+// the role replaces the user's organization role.
 type ProjectMember struct {
-	JoinedAt  time.Time `json:"joined_at" gorm:"default:CURRENT_TIMESTAMP"`
-	Role      *Role     `json:"role,omitempty" gorm:"foreignKey:RoleID"`
-	Status    string    `json:"status" gorm:"size:20;default:active"`
-	UserID    ulid.ULID `json:"user_id" gorm:"type:char(26);primaryKey"`
-	ProjectID ulid.ULID `json:"project_id" gorm:"type:char(26);primaryKey"`
-	RoleID    ulid.ULID `json:"role_id" gorm:"type:char(26);not null"`
+	JoinedAt   time.Time  `json:"joined_at" gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt  time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+	AssignedBy *ulid.ULID `json:"assigned_by,omitempty" gorm:"type:char(26)"`
+	Role       *Role      `json:"role,omitempty" gorm:"foreignKey:RoleID"`
+	Status     string     `json:"status" gorm:"size:20;default:active"`
+	UserID     ulid.ULID  `json:"user_id" gorm:"type:char(26);primaryKey"`
+	ProjectID  ulid.ULID  `json:"project_id" gorm:"type:char(26);primaryKey"`
+	RoleID     ulid.ULID  `json:"role_id" gorm:"type:char(26);not null"`
 }
 
 // Scope constants for roles
@@ -289,15 +296,18 @@ type RolePermission struct {
 // AuditLog represents an audit log entry for compliance.
 type AuditLog struct {
 	CreatedAt      time.Time  `json:"created_at"`
-	UserID         *ulid.ULID `json:"user_id,omitempty" gorm:"type:char(26)"`
-	OrganizationID *ulid.ULID `json:"organization_id,omitempty" gorm:"type:char(26)"`
-	Action         string     `json:"action" gorm:"size:255;not null"`
-	Resource       string     `json:"resource" gorm:"size:255"`
-	ResourceID     string     `json:"resource_id" gorm:"size:255"`
-	Metadata       string     `json:"metadata" gorm:"type:jsonb"`
-	IPAddress      string     `json:"ip_address" gorm:"size:45"`
-	UserAgent      string     `json:"user_agent" gorm:"type:text"`
-	ID             ulid.ULID  `json:"id" gorm:"type:char(26);primaryKey"`
+	UserID         *ulid.ULID      `json:"user_id,omitempty" gorm:"type:char(26)"`
+	APIKeyID       *ulid.ULID      `json:"api_key_id,omitempty" gorm:"type:char(26)"`
+	OrganizationID *ulid.ULID      `json:"organization_id,omitempty" gorm:"type:char(26)"`
+	ProjectID      *ulid.ULID      `json:"project_id,omitempty" gorm:"type:char(26)"`
+	Action         string          `json:"action" gorm:"size:255;not null"`
+	Resource       string          `json:"resource" gorm:"size:255"`
+	ResourceID     string          `json:"resource_id" gorm:"size:255"`
+	Metadata       string          `json:"metadata" gorm:"type:jsonb"`
+	Changes        json.RawMessage `json:"changes,omitempty" gorm:"type:jsonb"` // {"field": {"before": ..., "after": ...}}
+	IPAddress      string          `json:"ip_address" gorm:"size:45"`
+	UserAgent      string          `json:"user_agent" gorm:"type:text"`
+	ID             ulid.ULID       `json:"id" gorm:"type:char(26);primaryKey"`
 }
 
 // Request/Response DTOs
@@ -313,6 +323,12 @@ type LoginResponse struct {
 	RefreshToken string `json:"refresh_token"`
 	TokenType    string `json:"token_type"` // Always "Bearer"
 	ExpiresIn    int64  `json:"expires_in"` // Seconds until expiration
+
+	// Set instead of tokens when a second factor is needed; complete with VerifyMFAChallenge
+	MFAEnrollment *MFAEnrollment `json:"mfa_enrollment,omitempty"` // Enrolment required before the first login
+	MFAToken      string         `json:"mfa_token,omitempty"`
+	MFARequired   bool           `json:"mfa_required,omitempty"`
+	RecoveryCodes []string       `json:"recovery_codes,omitempty"` // Returned once, when enrolment completes at login
 }
 
 type AuthUser struct {
@@ -327,6 +343,7 @@ type AuthUser struct {
 type CreateAPIKeyRequest struct {
 	ExpiresAt *time.Time `json:"expires_at,omitempty"`
 	Name      string     `json:"name" validate:"required,min=1,max=100"`
+	Scopes    []string   `json:"scopes,omitempty"`
 	ProjectID ulid.ULID  `json:"project_id" validate:"required"`
 }
 
@@ -338,6 +355,7 @@ type CreateAPIKeyResponse struct {
 	Key        string     `json:"key" example:"bk_AbCdEfGhIjKlMnOpQrStUvWxYz0123456789AbCd"`
 	KeyPreview string     `json:"key_preview" example:"bk_AbCd...AbCd"`
 	ProjectID  string     `json:"project_id" example:"proj_01234567890123456789012345"`
+	Scopes     []string   `json:"scopes" example:"traces:create"`
 }
 
 type RefreshTokenRequest struct {
@@ -349,9 +367,20 @@ type RefreshTokenRequest struct {
 type AuthContext struct {
 	APIKeyID  *ulid.ULID `json:"api_key_id,omitempty"`
 	SessionID *ulid.ULID `json:"session_id,omitempty"`
+	Scopes    []string   `json:"scopes,omitempty"` // API key scopes (empty for sessions)
 	UserID    ulid.ULID  `json:"user_id"`
 }
 
+// HasAPIKeyScope checks if the API key behind this context was granted a scope
+func (ac *AuthContext) HasAPIKeyScope(scope string) bool {
+	for _, s := range ac.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
 // Deprecated: StandardPermissions removed
 // Use scope-based permissions instead (see seeds/dev.yaml for full list)
 // Organization-level: organizations:*, members:*, billing:*, settings:*, etc.
@@ -423,7 +452,7 @@ func NewUserTimestampBlacklistedToken(userID ulid.ULID, blacklistTimestamp int64
 	}
 }
 
-func NewAPIKey(userID, projectID ulid.ULID, name, keyHash, keyPreview string, expiresAt *time.Time) *APIKey {
+func NewAPIKey(userID, projectID ulid.ULID, name, keyHash, keyPreview string, scopes []string, expiresAt *time.Time) *APIKey {
 	return &APIKey{
 		ID:         ulid.New(),
 		KeyHash:    keyHash,
@@ -431,6 +460,7 @@ func NewAPIKey(userID, projectID ulid.ULID, name, keyHash, keyPreview string, ex
 		ProjectID:  projectID,
 		UserID:     userID,
 		Name:       name,
+		Scopes:     pq.StringArray(scopes),
 		ExpiresAt:  expiresAt,
 		CreatedAt:  time.Now(),
 		UpdatedAt:  time.Now(),
@@ -609,6 +639,15 @@ func (k *APIKey) MarkAsUsed() {
 	k.UpdatedAt = now
 }
 
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
 func (r *Role) AddPermission(permissionID ulid.ULID, grantedBy *ulid.ULID) *RolePermission {
 	return &RolePermission{
 		RoleID:       r.ID,
@@ -620,18 +659,21 @@ func (r *Role) AddPermission(permissionID ulid.ULID, grantedBy *ulid.ULID) *Role
 
 // RBAC Request/Response DTOs
 
-// CreateRoleRequest represents a request to create a new role
+// CreateRoleRequest represents a request to create a new role. Custom role
+// permissions are picked from the catalogue by ID or by name.
 type CreateRoleRequest struct {
 	ScopeType     string      `json:"scope_type" validate:"required,oneof=system organization project environment"`
 	Name          string      `json:"name" validate:"required,min=1,max=100"`
 	Description   string      `json:"description,omitempty"`
 	PermissionIDs []ulid.ULID `json:"permission_ids,omitempty"`
+	Permissions   []string    `json:"permissions,omitempty" example:"prompts:read,prompts:update"`
 }
 
 // UpdateRoleRequest represents a request to update an existing role
 type UpdateRoleRequest struct {
 	Description   *string     `json:"description,omitempty"`
 	PermissionIDs []ulid.ULID `json:"permission_ids,omitempty"`
+	Permissions   []string    `json:"permissions,omitempty"`
 }
 
 // CreatePermissionRequest represents a request to create a new permission
@@ -19,7 +19,8 @@ var (
 	ErrSessionExpired  = errors.New("session expired")
 
 	// API Key errors
-	ErrAPIKeyNotFound = errors.New("api key not found")
-	ErrAPIKeyInvalid  = errors.New("api key invalid")
-	ErrAPIKeyExpired  = errors.New("api key expired")
+	ErrAPIKeyNotFound     = errors.New("api key not found")
+	ErrAPIKeyInvalid      = errors.New("api key invalid")
+	ErrAPIKeyExpired      = errors.New("api key expired")
+	ErrInvalidAPIKeyScope = errors.New("invalid api key scope")
 )
@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/lib/pq"
+
+	"brokle/pkg/ulid"
+)
+
+// MFA limits
+const (
+	MFARecoveryCodeCount    = 10
+	MFAChallengeTTL         = 5 * time.Minute
+	MFAChallengeMaxAttempts = 5
+	MFAEnrollmentIssuer     = "Brokle"
+	MFAValidationSkew       = 1 // Accept codes one step before and after the current one
+)
+
+// UserMFA is a user's TOTP enrolment. It is pending until the first code is verified.
+type UserMFA struct {
+	UserID             ulid.ULID      `json:"user_id" gorm:"type:char(26);primaryKey"`
+	SecretEncrypted    string         `json:"-" gorm:"type:text;not null"`
+	RecoveryCodeHashes pq.StringArray `json:"-" gorm:"type:text[];not null"`
+	Enabled            bool           `json:"enabled" gorm:"not null;default:false"`
+	EnabledAt          *time.Time     `json:"enabled_at,omitempty"`
+	LastUsedStep       int64          `json:"-" gorm:"not null;default:0"` // Rejects replays of an accepted code
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+}
+
+// TableName returns the table name for UserMFA
+func (UserMFA) TableName() string {
+	return "user_mfa"
+}
+
+// MFAStatus describes a user's MFA state.
+type MFAStatus struct {
+	EnabledAt              *time.Time `json:"enabled_at,omitempty"`
+	RecoveryCodesRemaining int        `json:"recovery_codes_remaining"`
+	Enabled                bool       `json:"enabled"`
+	Required               bool       `json:"required"` // An organization of the user requires MFA
+}
+
+// MFAEnrollment is the secret to add to an authenticator app.
+type MFAEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"` // otpauth:// URI to render as a QR code
+}
+
+// MFARecoveryCodes are single-use codes that replace a TOTP code. They are shown once.
+type MFARecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// MFACodeRequest carries a TOTP code, or a recovery code where accepted.
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// MFAChallengeRequest completes a login that requires a second factor.
+type MFAChallengeRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required"` // TOTP code or recovery code
+}
+
+// MFARepository defines the interface for MFA enrolment data access.
+type MFARepository interface {
+	GetByUserID(ctx context.Context, userID ulid.ULID) (*UserMFA, error)
+	Save(ctx context.Context, mfa *UserMFA) error
+	Delete(ctx context.Context, userID ulid.ULID) error
+}
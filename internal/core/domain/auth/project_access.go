@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"context"
+
+	"brokle/pkg/ulid"
+)
+
+// PermissionSource tells where a user's permissions in a project come from.
+type PermissionSource string
+
+const (
+	// PermissionSourceOwner: organization owners keep their organization role in
+	// every project, so a project assignment can never lock them out.
+	PermissionSourceOwner PermissionSource = "organization_owner"
+	// PermissionSourceProjectRole: the project assignment replaces the organization role.
+	PermissionSourceProjectRole PermissionSource = "project_role"
+	// PermissionSourceOrganizationRole: no assignment, the organization role applies.
+	PermissionSourceOrganizationRole PermissionSource = "organization_role"
+	// PermissionSourceUnassigned: no assignment, and the organization only grants
+	// access to assigned projects (see organization.SettingAssignedProjectsOnly).
+	PermissionSourceUnassigned PermissionSource = "unassigned"
+	// PermissionSourceNone: not an active member of the project's organization.
+	// Project assignments only apply to organization members.
+	PermissionSourceNone PermissionSource = "none"
+)
+
+// ProjectAccess is the role a user acts with in a project and what it grants.
+type ProjectAccess struct {
+	UserID         ulid.ULID        `json:"user_id"`
+	ProjectID      ulid.ULID        `json:"project_id"`
+	OrganizationID ulid.ULID        `json:"organization_id"`
+	Source         PermissionSource `json:"source"`
+	Role           *Role            `json:"role,omitempty"`
+	Permissions    []string         `json:"permissions"`
+}
+
+// Allows returns the granted permission matching resource:action, wildcards
+// included, or false when none does.
+func (a *ProjectAccess) Allows(permission string) (string, bool) {
+	resource, action, err := ParseResourceAction(permission)
+	if err != nil {
+		return "", false
+	}
+	for _, name := range a.Permissions {
+		grantedResource, grantedAction, err := ParseResourceAction(name)
+		if err != nil {
+			continue
+		}
+		granted := Permission{Resource: grantedResource, Action: grantedAction}
+		if granted.MatchesResourceAction(resource, action) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// PermissionSimulation explains whether a user holds a permission in a project.
+type PermissionSimulation struct {
+	UserID            ulid.ULID        `json:"user_id"`
+	ProjectID         ulid.ULID        `json:"project_id"`
+	OrganizationID    ulid.ULID        `json:"organization_id"`
+	Permission        string           `json:"permission"`
+	Allowed           bool             `json:"allowed"`
+	Source            PermissionSource `json:"source"`
+	RoleID            *ulid.ULID       `json:"role_id,omitempty"`
+	RoleName          string           `json:"role_name,omitempty"`
+	RoleScope         string           `json:"role_scope,omitempty"`
+	MatchedPermission string           `json:"matched_permission,omitempty"` // The grant that allowed it, e.g. "traces:*"
+	Reason            string           `json:"reason"`
+}
+
+// AssignProjectRoleRequest assigns a user a role in a project.
+type AssignProjectRoleRequest struct {
+	RoleID ulid.ULID `json:"role_id" binding:"required"`
+}
+
+// ProjectMemberRepository persists project role assignments.
+type ProjectMemberRepository interface {
+	// Upsert creates the assignment or replaces the role of an existing one.
+	Upsert(ctx context.Context, member *ProjectMember) error
+	// GetByUserAndProject preloads the role with its permissions.
+	GetByUserAndProject(ctx context.Context, userID, projectID ulid.ULID) (*ProjectMember, error)
+	GetByProjectID(ctx context.Context, projectID ulid.ULID) ([]*ProjectMember, error)
+	Delete(ctx context.Context, userID, projectID ulid.ULID) error
+}
+
+// ProjectAccessService resolves a user's effective permissions in a project.
+type ProjectAccessService interface {
+	// GetProjectAccess returns a not found error when the project does not exist.
+	GetProjectAccess(ctx context.Context, userID, projectID ulid.ULID) (*ProjectAccess, error)
+	CheckUserProjectPermissions(ctx context.Context, userID, projectID ulid.ULID, permissions []string) (map[string]bool, error)
+	SimulatePermission(ctx context.Context, userID, projectID ulid.ULID, permission string) (*PermissionSimulation, error)
+}
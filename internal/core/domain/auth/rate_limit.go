@@ -0,0 +1,189 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"brokle/pkg/ulid"
+)
+
+// RateLimitDimension identifies what a rate limit counts.
+type RateLimitDimension string
+
+const (
+	RateLimitRequests RateLimitDimension = "requests" // SDK requests per second
+	RateLimitSpans    RateLimitDimension = "spans"    // Ingested spans per minute
+	RateLimitBytes    RateLimitDimension = "bytes"    // Ingested payload bytes per day
+)
+
+// RateLimitDimensions lists every dimension in evaluation order.
+var RateLimitDimensions = []RateLimitDimension{RateLimitRequests, RateLimitSpans, RateLimitBytes}
+
+// Window returns the sliding window a dimension is counted over.
+func (d RateLimitDimension) Window() time.Duration {
+	switch d {
+	case RateLimitSpans:
+		return time.Minute
+	case RateLimitBytes:
+		return 24 * time.Hour
+	default:
+		return time.Second
+	}
+}
+
+// RateLimitScope is the subject a limit applies to.
+type RateLimitScope string
+
+const (
+	RateLimitScopeAPIKey  RateLimitScope = "api_key"
+	RateLimitScopeProject RateLimitScope = "project"
+)
+
+// RateLimitPolicy overrides rate limits for a project (APIKeyID nil) or one of its API keys.
+// Nil limits fall back to the configured defaults for keys and to unlimited for projects;
+// a limit of 0 is unlimited.
+type RateLimitPolicy struct {
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+	APIKeyID           *ulid.ULID `json:"api_key_id,omitempty" gorm:"type:char(26)"`
+	RequestsPerSecond  *int64     `json:"requests_per_second,omitempty"`
+	SpansPerMinute     *int64     `json:"spans_per_minute,omitempty"`
+	PayloadBytesPerDay *int64     `json:"payload_bytes_per_day,omitempty"`
+	ID                 ulid.ULID  `json:"id" gorm:"type:char(26);primaryKey"`
+	ProjectID          ulid.ULID  `json:"project_id" gorm:"type:char(26);not null"`
+}
+
+// TableName returns the table name for RateLimitPolicy
+func (RateLimitPolicy) TableName() string {
+	return "rate_limit_policies"
+}
+
+// RateLimits are the effective limits for one scope. A limit of 0 is unlimited.
+type RateLimits struct {
+	RequestsPerSecond  int64 `json:"requests_per_second"`
+	SpansPerMinute     int64 `json:"spans_per_minute"`
+	PayloadBytesPerDay int64 `json:"payload_bytes_per_day"`
+}
+
+// Limit returns the limit for a dimension.
+func (l RateLimits) Limit(dimension RateLimitDimension) int64 {
+	switch dimension {
+	case RateLimitRequests:
+		return l.RequestsPerSecond
+	case RateLimitSpans:
+		return l.SpansPerMinute
+	case RateLimitBytes:
+		return l.PayloadBytesPerDay
+	}
+	return 0
+}
+
+// Apply overrides limits set on the policy.
+func (l RateLimits) Apply(policy *RateLimitPolicy) RateLimits {
+	if policy == nil {
+		return l
+	}
+	if policy.RequestsPerSecond != nil {
+		l.RequestsPerSecond = *policy.RequestsPerSecond
+	}
+	if policy.SpansPerMinute != nil {
+		l.SpansPerMinute = *policy.SpansPerMinute
+	}
+	if policy.PayloadBytesPerDay != nil {
+		l.PayloadBytesPerDay = *policy.PayloadBytesPerDay
+	}
+	return l
+}
+
+// RateLimitCost is what a request consumes from each dimension.
+type RateLimitCost struct {
+	Requests int64
+	Spans    int64
+	Bytes    int64
+}
+
+// Of returns the cost for a dimension.
+func (c RateLimitCost) Of(dimension RateLimitDimension) int64 {
+	switch dimension {
+	case RateLimitRequests:
+		return c.Requests
+	case RateLimitSpans:
+		return c.Spans
+	case RateLimitBytes:
+		return c.Bytes
+	}
+	return 0
+}
+
+// RateLimitBucket is one sliding window counter checked by a RateLimiter.
+type RateLimitBucket struct {
+	Key    string
+	Window time.Duration
+	Limit  int64
+	Cost   int64
+}
+
+// RateLimitBucketState is the state of a bucket after a check.
+type RateLimitBucketState struct {
+	Current  int64 // Count in the current fixed window
+	Previous int64 // Count in the previous fixed window
+}
+
+// RateLimitDecision is the outcome of a rate limit check. When several limits apply,
+// it describes the one that denied the request, or the one closest to its limit.
+type RateLimitDecision struct {
+	ResetAt    time.Time          `json:"reset_at"`
+	Dimension  RateLimitDimension `json:"dimension,omitempty"`
+	Scope      RateLimitScope     `json:"scope,omitempty"`
+	Limit      int64              `json:"limit"`
+	Remaining  int64              `json:"remaining"`
+	RetryAfter time.Duration      `json:"retry_after,omitempty"`
+	Allowed    bool               `json:"allowed"`
+}
+
+// Limited returns true if a limit applied to the request.
+func (d *RateLimitDecision) Limited() bool {
+	return d != nil && d.Limit > 0
+}
+
+// RateLimitUsage is the current consumption of one limit.
+type RateLimitUsage struct {
+	Scope         RateLimitScope     `json:"scope"`
+	Dimension     RateLimitDimension `json:"dimension"`
+	Limit         int64              `json:"limit"`
+	Used          int64              `json:"used"`
+	Remaining     int64              `json:"remaining"`
+	WindowSeconds int64              `json:"window_seconds"`
+}
+
+// UpdateRateLimitsRequest replaces the limit overrides of a project or API key.
+// Omitted limits use the default; 0 is unlimited.
+type UpdateRateLimitsRequest struct {
+	RequestsPerSecond  *int64 `json:"requests_per_second,omitempty" binding:"omitempty,min=0"`
+	SpansPerMinute     *int64 `json:"spans_per_minute,omitempty" binding:"omitempty,min=0"`
+	PayloadBytesPerDay *int64 `json:"payload_bytes_per_day,omitempty" binding:"omitempty,min=0"`
+}
+
+// RateLimitsResponse describes the limits of a project or API key.
+type RateLimitsResponse struct {
+	Policy    *RateLimitPolicy `json:"policy,omitempty"`
+	Effective RateLimits       `json:"effective"`
+	Usage     []RateLimitUsage `json:"usage"`
+}
+
+// RateLimitPolicyRepository defines the interface for rate limit policy data access.
+type RateLimitPolicyRepository interface {
+	// Get returns the project policy when apiKeyID is nil, otherwise the key's policy
+	Get(ctx context.Context, projectID ulid.ULID, apiKeyID *ulid.ULID) (*RateLimitPolicy, error)
+	Save(ctx context.Context, policy *RateLimitPolicy) error
+	Delete(ctx context.Context, projectID ulid.ULID, apiKeyID *ulid.ULID) error
+}
+
+// RateLimiter counts usage in sliding windows shared across server replicas.
+type RateLimiter interface {
+	// Allow consumes every bucket's cost only if all buckets stay within their limit.
+	// Returns whether the cost was consumed and the state of each bucket before consumption.
+	Allow(ctx context.Context, now time.Time, buckets []RateLimitBucket) (bool, []RateLimitBucketState, error)
+	// Usage returns the state of the buckets without consuming.
+	Usage(ctx context.Context, now time.Time, buckets []RateLimitBucket) ([]RateLimitBucketState, error)
+}
@@ -125,6 +125,8 @@ type RoleRepository interface {
 
 	// Statistics
 	GetRoleStatistics(ctx context.Context) (*RoleStatistics, error)
+	// CountAssignments counts the organization members and project assignments holding the role
+	CountAssignments(ctx context.Context, roleID ulid.ULID) (int64, error)
 
 	// Bulk operations
 	BulkCreate(ctx context.Context, roles []*Role) error
@@ -258,6 +260,8 @@ type AuditLogRepository interface {
 
 	// Advanced queries
 	Search(ctx context.Context, filters *AuditLogFilters) ([]*AuditLog, int, error)
+	// StreamByFilters walks every matching log oldest first in batches, ignoring pagination
+	StreamByFilters(ctx context.Context, filters *AuditLogFilters, batchSize int, fn func(batch []*AuditLog) error) error
 
 	// Statistics
 	GetAuditLogStats(ctx context.Context) (*AuditLogStats, error)
@@ -273,6 +277,7 @@ type AuditLogFilters struct {
 	// Domain filters
 	UserID         *ulid.ULID `json:"user_id,omitempty"`
 	OrganizationID *ulid.ULID `json:"organization_id,omitempty"`
+	ProjectID      *ulid.ULID `json:"project_id,omitempty"`
 	Action         *string    `json:"action,omitempty"`
 	Resource       *string    `json:"resource,omitempty"`
 	ResourceID     *string    `json:"resource_id,omitempty"`
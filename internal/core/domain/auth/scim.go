@@ -0,0 +1,299 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"brokle/pkg/ulid"
+)
+
+// SCIM 2.0 schema URNs (RFC 7643, RFC 7644)
+const (
+	SCIMSchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SCIMSchemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SCIMSchemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SCIMSchemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SCIMSchemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+	SCIMSchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	SCIMSchemaResourceType          = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+)
+
+// SCIM provisioning settings
+const (
+	SCIMTokenPrefix       = "bk_scim_"
+	SCIMTokenSecretLength = 40
+	// SCIMDefaultRole is the organization role of provisioned users who are in no role-mapped group
+	SCIMDefaultRole    = "viewer"
+	SCIMMaxResults     = 100
+	scimTokenPreviewed = 4
+)
+
+// SCIMToken is an organization-scoped bearer token used by an identity provider to
+// provision users and groups. Only the SHA-256 hash of the token is stored.
+type SCIMToken struct {
+	ID             ulid.ULID  `json:"id" gorm:"column:id;type:char(26);primaryKey"`
+	OrganizationID ulid.ULID  `json:"organization_id" gorm:"column:organization_id;type:char(26);not null"`
+	Name           string     `json:"name" gorm:"column:name;size:100;not null"`
+	TokenHash      string     `json:"-" gorm:"column:token_hash;size:64;not null;uniqueIndex"`
+	TokenPreview   string     `json:"token_preview" gorm:"column:token_preview;size:20;not null"`
+	CreatedBy      ulid.ULID  `json:"created_by" gorm:"column:created_by;type:char(26);not null"` // Recorded as the actor of SCIM membership changes
+	LastUsedAt     *time.Time `json:"last_used_at,omitempty" gorm:"column:last_used_at"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+}
+
+// TableName returns the database table name for SCIMToken
+func (SCIMToken) TableName() string {
+	return "scim_tokens"
+}
+
+// SCIMUserLink marks a user as provisioned into an organization by SCIM. The user
+// keeps one global account; Active is the organization-level SCIM state.
+type SCIMUserLink struct {
+	OrganizationID ulid.ULID `json:"organization_id" gorm:"column:organization_id;type:char(26);primaryKey"`
+	UserID         ulid.ULID `json:"user_id" gorm:"column:user_id;type:char(26);primaryKey"`
+	ExternalID     string    `json:"external_id,omitempty" gorm:"column:external_id;size:255"`
+	Active         bool      `json:"active" gorm:"column:active;not null;default:true"`
+	CreatedAt      time.Time `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt      time.Time `json:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// TableName returns the database table name for SCIMUserLink
+func (SCIMUserLink) TableName() string {
+	return "scim_users"
+}
+
+// SCIMGroup is an identity provider group. Members of a group mapped to a role get
+// that organization role; with several, the role with the most permissions wins.
+type SCIMGroup struct {
+	ID             ulid.ULID  `json:"id" gorm:"column:id;type:char(26);primaryKey"`
+	OrganizationID ulid.ULID  `json:"organization_id" gorm:"column:organization_id;type:char(26);not null"`
+	DisplayName    string     `json:"display_name" gorm:"column:display_name;size:255;not null"`
+	ExternalID     string     `json:"external_id,omitempty" gorm:"column:external_id;size:255"`
+	RoleID         *ulid.ULID `json:"role_id,omitempty" gorm:"column:role_id;type:char(26)"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt      time.Time  `json:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// TableName returns the database table name for SCIMGroup
+func (SCIMGroup) TableName() string {
+	return "scim_groups"
+}
+
+// SCIMGroupMember is a provisioned user's membership in a SCIM group
+type SCIMGroupMember struct {
+	GroupID   ulid.ULID `json:"group_id" gorm:"column:group_id;type:char(26);primaryKey"`
+	UserID    ulid.ULID `json:"user_id" gorm:"column:user_id;type:char(26);primaryKey"`
+	CreatedAt time.Time `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+}
+
+// TableName returns the database table name for SCIMGroupMember
+func (SCIMGroupMember) TableName() string {
+	return "scim_group_members"
+}
+
+// SCIMListFilter narrows SCIM list queries; the zero value lists everything
+type SCIMListFilter struct {
+	Email       string // userName eq
+	ExternalID  string // externalId eq
+	DisplayName string // displayName eq (groups)
+	Offset      int
+	Limit       int
+}
+
+// CreateSCIMTokenRequest is the request to issue a SCIM token
+type CreateSCIMTokenRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+// SCIMTokenResponse is a newly issued token; Token is only returned once
+type SCIMTokenResponse struct {
+	*SCIMToken
+	Token string `json:"token"`
+}
+
+// SetSCIMGroupRoleRequest maps a group to an organization role; a null role_id removes the mapping
+type SetSCIMGroupRoleRequest struct {
+	RoleID *ulid.ULID `json:"role_id"`
+}
+
+// SCIMName is the SCIM name complex attribute
+type SCIMName struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// SCIMEmail is an entry of the SCIM emails attribute
+type SCIMEmail struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// SCIMReference refers to another resource, such as a group member or a user's group
+type SCIMReference struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+}
+
+// SCIMMeta is the SCIM resource metadata
+type SCIMMeta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+}
+
+// SCIMUser is the SCIM User resource. userName is the user's email address.
+type SCIMUser struct {
+	Schemas     []string        `json:"schemas"`
+	ID          string          `json:"id,omitempty"`
+	ExternalID  string          `json:"externalId,omitempty"`
+	UserName    string          `json:"userName"`
+	Name        *SCIMName       `json:"name,omitempty"`
+	DisplayName string          `json:"displayName,omitempty"`
+	Emails      []SCIMEmail     `json:"emails,omitempty"`
+	Active      *bool           `json:"active,omitempty"`
+	Groups      []SCIMReference `json:"groups,omitempty"`
+	Meta        *SCIMMeta       `json:"meta,omitempty"`
+}
+
+// Email returns the userName, falling back to the primary or first email
+func (u *SCIMUser) Email() string {
+	if u.UserName != "" {
+		return u.UserName
+	}
+	for _, email := range u.Emails {
+		if email.Primary {
+			return email.Value
+		}
+	}
+	if len(u.Emails) > 0 {
+		return u.Emails[0].Value
+	}
+	return ""
+}
+
+// SCIMGroupResource is the SCIM Group resource
+type SCIMGroupResource struct {
+	Schemas     []string        `json:"schemas"`
+	ID          string          `json:"id,omitempty"`
+	ExternalID  string          `json:"externalId,omitempty"`
+	DisplayName string          `json:"displayName"`
+	Members     []SCIMReference `json:"members"`
+	Meta        *SCIMMeta       `json:"meta,omitempty"`
+}
+
+// SCIMListResponse is a page of SCIM resources
+type SCIMListResponse struct {
+	Schemas      []string    `json:"schemas"`
+	TotalResults int64       `json:"totalResults"`
+	StartIndex   int         `json:"startIndex"`
+	ItemsPerPage int         `json:"itemsPerPage"`
+	Resources    interface{} `json:"Resources"`
+}
+
+// SCIMPatchRequest is a SCIM PATCH request body
+type SCIMPatchRequest struct {
+	Schemas    []string             `json:"schemas"`
+	Operations []SCIMPatchOperation `json:"Operations"`
+}
+
+// SCIMPatchOperation is one add, remove or replace operation. Value is kept raw
+// because identity providers differ in how they encode it (e.g. "False" for false).
+type SCIMPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// SCIMError is the SCIM error response body
+type SCIMError struct {
+	Schemas  []string `json:"schemas"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+	Status   string   `json:"status"`
+}
+
+// NewSCIMError builds an error response for an HTTP status
+func NewSCIMError(status int, scimType, detail string) *SCIMError {
+	return &SCIMError{
+		Schemas:  []string{SCIMSchemaError},
+		ScimType: scimType,
+		Detail:   detail,
+		Status:   fmt.Sprintf("%d", status),
+	}
+}
+
+// GenerateSCIMToken returns a new bk_scim_{secret} token and its display preview
+func GenerateSCIMToken() (token, preview string, err error) {
+	secret, err := generateSecureSecret(SCIMTokenSecretLength)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate SCIM token secret: %w", err)
+	}
+	token = SCIMTokenPrefix + secret
+	preview = SCIMTokenPrefix + secret[:scimTokenPreviewed] + "..." + secret[len(secret)-scimTokenPreviewed:]
+	return token, preview, nil
+}
+
+// SCIMRepository defines data access for SCIM tokens, provisioned users and groups
+type SCIMRepository interface {
+	// Tokens
+	CreateToken(ctx context.Context, token *SCIMToken) error
+	GetTokenByHash(ctx context.Context, tokenHash string) (*SCIMToken, error)
+	ListTokens(ctx context.Context, orgID ulid.ULID) ([]*SCIMToken, error)
+	DeleteToken(ctx context.Context, orgID, tokenID ulid.ULID) error
+	UpdateTokenLastUsed(ctx context.Context, tokenID ulid.ULID, at time.Time) error
+
+	// Provisioned users
+	GetUserLink(ctx context.Context, orgID, userID ulid.ULID) (*SCIMUserLink, error)
+	SaveUserLink(ctx context.Context, link *SCIMUserLink) error
+	DeleteUserLink(ctx context.Context, orgID, userID ulid.ULID) error
+	ListUserLinks(ctx context.Context, orgID ulid.ULID, filter SCIMListFilter) ([]*SCIMUserLink, int64, error)
+
+	// Groups
+	CreateGroup(ctx context.Context, group *SCIMGroup) error
+	GetGroup(ctx context.Context, orgID, groupID ulid.ULID) (*SCIMGroup, error)
+	UpdateGroup(ctx context.Context, group *SCIMGroup) error
+	DeleteGroup(ctx context.Context, orgID, groupID ulid.ULID) error
+	ListGroups(ctx context.Context, orgID ulid.ULID, filter SCIMListFilter) ([]*SCIMGroup, int64, error)
+	HasRoleMappings(ctx context.Context, orgID ulid.ULID) (bool, error)
+
+	// Group membership
+	ListGroupMembers(ctx context.Context, groupID ulid.ULID) ([]ulid.ULID, error)
+	AddGroupMembers(ctx context.Context, groupID ulid.ULID, userIDs []ulid.ULID) error
+	RemoveGroupMembers(ctx context.Context, groupID ulid.ULID, userIDs []ulid.ULID) error
+	ListUserGroups(ctx context.Context, orgID, userID ulid.ULID) ([]*SCIMGroup, error)
+	RemoveUserFromGroups(ctx context.Context, orgID, userID ulid.ULID) error
+}
+
+// SCIMService provisions organization members from an identity provider. Calls
+// are made on behalf of the authenticated token's organization.
+type SCIMService interface {
+	// Token management (organization settings); the caller needs settings:security in the organization
+	CreateToken(ctx context.Context, orgID, createdBy ulid.ULID, req *CreateSCIMTokenRequest) (*SCIMTokenResponse, error)
+	ListTokens(ctx context.Context, orgID, userID ulid.ULID) ([]*SCIMToken, error)
+	DeleteToken(ctx context.Context, orgID, tokenID, userID ulid.ULID) error
+	Authenticate(ctx context.Context, bearerToken string) (*SCIMToken, error)
+
+	// Group role mappings (organization settings); the caller needs settings:security in the organization
+	ListGroupMappings(ctx context.Context, orgID, userID ulid.ULID) ([]*SCIMGroup, error)
+	SetGroupRole(ctx context.Context, orgID, groupID ulid.ULID, roleID *ulid.ULID, updatedBy ulid.ULID) (*SCIMGroup, error)
+
+	// /Users
+	ListUsers(ctx context.Context, token *SCIMToken, filter string, startIndex, count int) (*SCIMListResponse, error)
+	GetUser(ctx context.Context, token *SCIMToken, id string) (*SCIMUser, error)
+	CreateUser(ctx context.Context, token *SCIMToken, user *SCIMUser) (*SCIMUser, error)
+	ReplaceUser(ctx context.Context, token *SCIMToken, id string, user *SCIMUser) (*SCIMUser, error)
+	PatchUser(ctx context.Context, token *SCIMToken, id string, req *SCIMPatchRequest) (*SCIMUser, error)
+	DeleteUser(ctx context.Context, token *SCIMToken, id string) error
+
+	// /Groups
+	ListGroups(ctx context.Context, token *SCIMToken, filter string, startIndex, count int) (*SCIMListResponse, error)
+	GetGroup(ctx context.Context, token *SCIMToken, id string) (*SCIMGroupResource, error)
+	CreateGroup(ctx context.Context, token *SCIMToken, group *SCIMGroupResource) (*SCIMGroupResource, error)
+	ReplaceGroup(ctx context.Context, token *SCIMToken, id string, group *SCIMGroupResource) (*SCIMGroupResource, error)
+	PatchGroup(ctx context.Context, token *SCIMToken, id string, req *SCIMPatchRequest) (*SCIMGroupResource, error)
+	DeleteGroup(ctx context.Context, token *SCIMToken, id string) error
+}
@@ -1,6 +1,8 @@
 package auth
 
 import (
+	"fmt"
+
 	"brokle/pkg/ulid"
 )
 
@@ -140,15 +142,77 @@ var ScopeCategories = []ScopeCategory{
 		DisplayName: "Observability",
 		Description: "Traces, analytics, and monitoring",
 		Level:       ScopeLevelProject,
-		Scopes:      []string{"traces:read", "traces:create", "traces:delete", "traces:export", "traces:share", "analytics:read", "analytics:export", "analytics:dashboards", "analytics:admin", "costs:read", "costs:export"},
+		Scopes:      []string{"traces:read", "traces:create", "traces:delete", "traces:export", "traces:share", "metrics:create", "logs:create", "analytics:read", "analytics:export", "analytics:dashboards", "analytics:admin", "costs:read", "costs:export"},
+	},
+	{
+		Name:        "prompts",
+		DisplayName: "Prompts",
+		Description: "Prompt management and runtime prompt fetching",
+		Level:       ScopeLevelProject,
+		Scopes:      []string{"prompts:read", "prompts:create", "prompts:update", "prompts:delete"},
+	},
+	{
+		Name:        "evaluation",
+		DisplayName: "Evaluation",
+		Description: "Scores, datasets, experiments, and annotation queues",
+		Level:       ScopeLevelProject,
+		Scopes:      []string{"scores:create", "datasets:read", "datasets:write", "experiments:read", "experiments:write", "annotations:read", "annotations:write", "playground:execute"},
 	},
 }
 
+// APIKeyScopes are the catalogue scopes that can be granted to an API key.
+// Each SDK route requires one of them; keys created without an explicit scope
+// set receive all of them.
+var APIKeyScopes = []string{
+	"traces:create", "traces:read",
+	"metrics:create", "logs:create",
+	"prompts:read", "prompts:create",
+	"scores:create",
+	"datasets:read", "datasets:write",
+	"experiments:read", "experiments:write",
+	"annotations:read", "annotations:write",
+	"playground:execute",
+}
+
+// IsAPIKeyScope checks if a scope can be granted to an API key
+func IsAPIKeyScope(scope string) bool {
+	for _, s := range APIKeyScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeAPIKeyScopes validates requested API key scopes and returns them
+// deduplicated in catalogue order. An empty request grants all API key scopes.
+func NormalizeAPIKeyScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return append([]string(nil), APIKeyScopes...), nil
+	}
+
+	requested := make(map[string]bool, len(scopes))
+	for _, scope := range scopes {
+		if !IsAPIKeyScope(scope) {
+			return nil, fmt.Errorf("%w: %q cannot be granted to an API key", ErrInvalidAPIKeyScope, scope)
+		}
+		requested[scope] = true
+	}
+
+	normalized := make([]string, 0, len(requested))
+	for _, scope := range APIKeyScopes {
+		if requested[scope] {
+			normalized = append(normalized, scope)
+		}
+	}
+	return normalized, nil
+}
+
 // GetScopeLevel determines the level of a scope from its name
 // This is a helper for validation and UI purposes
 func GetScopeLevel(scopeName string) ScopeLevel {
 	// Project-level scope prefixes
-	projectPrefixes := []string{"traces:", "analytics:", "costs:"}
+	projectPrefixes := []string{"traces:", "metrics:", "logs:", "analytics:", "costs:", "prompts:", "scores:", "datasets:", "experiments:", "annotations:", "playground:"}
 
 	for _, prefix := range projectPrefixes {
 		if len(scopeName) > len(prefix) && scopeName[:len(prefix)] == prefix {
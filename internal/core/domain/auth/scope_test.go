@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeAPIKeyScopes(t *testing.T) {
+	t.Run("defaults to all API key scopes", func(t *testing.T) {
+		scopes, err := NormalizeAPIKeyScopes(nil)
+		require.NoError(t, err)
+		assert.Equal(t, APIKeyScopes, scopes)
+
+		// The default must be a copy
+		scopes[0] = "changed"
+		assert.NotEqual(t, "changed", APIKeyScopes[0])
+	})
+
+	t.Run("dedupes in catalogue order", func(t *testing.T) {
+		scopes, err := NormalizeAPIKeyScopes([]string{"prompts:read", "traces:create", "prompts:read"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"traces:create", "prompts:read"}, scopes)
+	})
+
+	t.Run("rejects scopes outside the API key catalogue", func(t *testing.T) {
+		for _, scope := range []string{"traces:delete", "members:invite", "prompts"} {
+			_, err := NormalizeAPIKeyScopes([]string{"traces:create", scope})
+			assert.True(t, errors.Is(err, ErrInvalidAPIKeyScope), scope)
+		}
+	})
+}
+
+func TestAPIKeyScopes_InCatalogue(t *testing.T) {
+	catalogue := make(map[string]bool)
+	for _, category := range ScopeCategories {
+		for _, scope := range category.Scopes {
+			catalogue[scope] = true
+		}
+	}
+
+	for _, scope := range APIKeyScopes {
+		assert.True(t, catalogue[scope], "%s missing from ScopeCategories", scope)
+		assert.Equal(t, ScopeLevelProject, GetScopeLevel(scope), scope)
+	}
+}
+
+func TestAuthContext_HasAPIKeyScope(t *testing.T) {
+	ingestion := &AuthContext{Scopes: []string{"traces:create"}}
+	assert.True(t, ingestion.HasAPIKeyScope("traces:create"))
+	assert.False(t, ingestion.HasAPIKeyScope("traces:read"))
+	assert.False(t, ingestion.HasAPIKeyScope("prompts:read"))
+
+	assert.False(t, (&AuthContext{}).HasAPIKeyScope("traces:create"))
+}
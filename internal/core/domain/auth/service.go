@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"brokle/pkg/pagination"
@@ -15,6 +16,7 @@ type AuthService interface {
 	GenerateTokensForUser(ctx context.Context, userID ulid.ULID) (*LoginResponse, error) // Generate tokens without password validation
 	Logout(ctx context.Context, jti string, userID ulid.ULID) error
 	RefreshToken(ctx context.Context, req *RefreshTokenRequest) (*LoginResponse, error)
+	CheckSocialLoginAllowed(ctx context.Context, email string) error // Google and GitHub login, like password login, is refused where SSO is enforced
 
 	// OAuth session management (for two-step OAuth signup)
 	CreateOAuthSession(ctx context.Context, session interface{}) (string, error)
@@ -47,6 +49,18 @@ type AuthService interface {
 	// Authentication context
 	GetAuthContext(ctx context.Context, token string) (*AuthContext, error)
 	ValidateAuthToken(ctx context.Context, token string) (*AuthContext, error)
+
+	// Multi-factor authentication
+	CreateMFAChallenge(ctx context.Context, userID ulid.ULID, deviceInfo map[string]interface{}) (*LoginResponse, error) // Returns nil when the user needs no second factor
+	GetMFAChallenge(ctx context.Context, mfaToken string) (*LoginResponse, error) // Pending challenge with its enrolment, for OAuth logins
+	GetMFAChallengeUserID(ctx context.Context, mfaToken string) (ulid.ULID, error) // Owner of a pending challenge, for auditing failed codes
+	VerifyMFAChallenge(ctx context.Context, req *MFAChallengeRequest) (*LoginResponse, error)
+	GetMFAStatus(ctx context.Context, userID ulid.ULID) (*MFAStatus, error)
+	BeginMFAEnrollment(ctx context.Context, userID ulid.ULID) (*MFAEnrollment, error)
+	ConfirmMFAEnrollment(ctx context.Context, userID ulid.ULID, code string) (*MFARecoveryCodes, error)
+	RegenerateMFARecoveryCodes(ctx context.Context, userID ulid.ULID, code string) (*MFARecoveryCodes, error)
+	DisableMFA(ctx context.Context, userID ulid.ULID, code string) error
+	ResetMFA(ctx context.Context, adminID, orgID, userID ulid.ULID) error // Admin reset for a member who lost their device
 }
 
 // SessionService defines the session management service interface.
@@ -89,6 +103,21 @@ type APIKeyService interface {
 	CountAPIKeys(ctx context.Context, filters *APIKeyFilters) (int64, error)
 }
 
+// RateLimitService defines per-API-key and per-project rate limiting and quotas.
+// Limits are counted in Redis so they hold across server replicas.
+type RateLimitService interface {
+	// Check consumes cost from the key's and the project's limits, or from none if any is exceeded
+	Check(ctx context.Context, projectID, keyID ulid.ULID, cost RateLimitCost) (*RateLimitDecision, error)
+	// EnforcesPayloadQuota returns true if a payload bytes quota applies to the key or its project
+	EnforcesPayloadQuota(ctx context.Context, projectID, keyID ulid.ULID) bool
+
+	// Limit configuration and usage
+	GetProjectLimits(ctx context.Context, projectID ulid.ULID) (*RateLimitsResponse, error)
+	UpdateProjectLimits(ctx context.Context, projectID ulid.ULID, req *UpdateRateLimitsRequest) (*RateLimitsResponse, error)
+	GetAPIKeyLimits(ctx context.Context, projectID, keyID ulid.ULID) (*RateLimitsResponse, error)
+	UpdateAPIKeyLimits(ctx context.Context, projectID, keyID ulid.ULID, req *UpdateRateLimitsRequest) (*RateLimitsResponse, error)
+}
+
 // RoleService defines both system template and custom scoped role management service interface.
 type RoleService interface {
 	// System template role management
@@ -137,6 +166,8 @@ type OrganizationMemberService interface {
 	GetUserPermissionsInOrganization(ctx context.Context, userID, orgID ulid.ULID) ([]string, error)
 	CheckUserPermission(ctx context.Context, userID ulid.ULID, permission string) (bool, error)
 	CheckUserPermissions(ctx context.Context, userID ulid.ULID, permissions []string) (map[string]bool, error)
+	// RequireOrganizationPermission fails unless the user is an active member holding the permission in that organization
+	RequireOrganizationPermission(ctx context.Context, userID, orgID ulid.ULID, permission string) error
 
 	// Status management
 	ActivateMember(ctx context.Context, userID, orgID ulid.ULID) error
@@ -229,12 +260,16 @@ type AuditLogService interface {
 	LogUserAction(ctx context.Context, userID *ulid.ULID, action, resource, resourceID string, metadata map[string]interface{}, ipAddress, userAgent string) error
 	LogSystemAction(ctx context.Context, action, resource, resourceID string, metadata map[string]interface{}) error
 	LogSecurityEvent(ctx context.Context, userID *ulid.ULID, event, description string, metadata map[string]interface{}, ipAddress, userAgent string) error
+	// Record writes the audit entry of a mutation, attributed to the actor in the context.
+	// Failures are logged and never fail the audited operation.
+	Record(ctx context.Context, event *AuditEvent)
 
 	// Audit log queries
 	GetUserAuditLogs(ctx context.Context, userID ulid.ULID, limit, offset int) ([]*AuditLog, error)
 	GetOrganizationAuditLogs(ctx context.Context, orgID ulid.ULID, limit, offset int) ([]*AuditLog, error)
 	GetResourceAuditLogs(ctx context.Context, resource, resourceID string, limit, offset int) ([]*AuditLog, error)
 	SearchAuditLogs(ctx context.Context, filters *AuditLogFilters) ([]*AuditLog, int, error)
+	ExportAuditLogs(ctx context.Context, filters *AuditLogFilters, format AuditExportFormat, w io.Writer) error
 
 	// Audit log maintenance
 	CleanupOldAuditLogs(ctx context.Context, olderThan time.Time) error
@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"brokle/pkg/ulid"
+)
+
+// SSOProtocol is the protocol an SSO connection speaks with the identity provider.
+type SSOProtocol string
+
+const (
+	SSOProtocolOIDC SSOProtocol = "oidc"
+	SSOProtocolSAML SSOProtocol = "saml"
+)
+
+// SSO defaults
+const (
+	SSODefaultRole              = "viewer"
+	SSODomainVerificationPrefix = "brokle-domain-verification="
+	SSOLoginStateTTL            = 10 * time.Minute
+)
+
+// SSO attribute mapping keys
+const (
+	SSOAttributeEmail     = "email"
+	SSOAttributeFirstName = "first_name"
+	SSOAttributeLastName  = "last_name"
+)
+
+// SSOConnection is an organization's identity provider configuration. Users whose email
+// domain matches a verified connection join the organization with DefaultRole on first login.
+type SSOConnection struct {
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	DomainVerifiedAt *time.Time `json:"domain_verified_at,omitempty"`
+
+	// OIDC
+	OIDCIssuer                string `json:"oidc_issuer,omitempty" gorm:"column:oidc_issuer;size:500"`
+	OIDCClientID              string `json:"oidc_client_id,omitempty" gorm:"column:oidc_client_id;size:255"`
+	OIDCClientSecretEncrypted string `json:"-" gorm:"column:oidc_client_secret_encrypted;type:text"`
+
+	// SAML (Brokle is the service provider)
+	SAMLIdPEntityID    string `json:"saml_idp_entity_id,omitempty" gorm:"column:saml_idp_entity_id;size:500"`
+	SAMLIdPSSOURL      string `json:"saml_idp_sso_url,omitempty" gorm:"column:saml_idp_sso_url;size:1000"`
+	SAMLIdPCertificate string `json:"saml_idp_certificate,omitempty" gorm:"column:saml_idp_certificate;type:text"` // PEM, verifies assertion signatures
+
+	AttributeMapping  map[string]string `json:"attribute_mapping" gorm:"type:jsonb;serializer:json;default:'{}'"` // SSOAttribute* key -> IdP claim or attribute name
+	Protocol          SSOProtocol       `json:"protocol" gorm:"size:10;not null"`
+	Domain            string            `json:"domain" gorm:"size:255;not null;uniqueIndex"`
+	VerificationToken string            `json:"verification_token" gorm:"size:64;not null"` // Published as a DNS TXT record
+	DefaultRole       string            `json:"default_role" gorm:"size:50;not null"`
+	ID                ulid.ULID         `json:"id" gorm:"type:char(26);primaryKey"`
+	OrganizationID    ulid.ULID         `json:"organization_id" gorm:"type:char(26);not null;uniqueIndex"`
+	Enabled           bool              `json:"enabled" gorm:"not null;default:true"`
+	EnforceSSO        bool              `json:"enforce_sso" gorm:"column:enforce_sso;not null;default:false"` // Blocks password login for the domain
+}
+
+// TableName returns the table name for SSOConnection
+func (SSOConnection) TableName() string {
+	return "sso_connections"
+}
+
+// IsActive reports whether the connection can be used to log in
+func (c *SSOConnection) IsActive() bool {
+	return c.Enabled && c.DomainVerifiedAt != nil
+}
+
+// EnforcesSSO reports whether password login is blocked for the connection's domain
+func (c *SSOConnection) EnforcesSSO() bool {
+	return c.IsActive() && c.EnforceSSO
+}
+
+// DNSRecord is the TXT record value proving control of the domain
+func (c *SSOConnection) DNSRecord() string {
+	return SSODomainVerificationPrefix + c.VerificationToken
+}
+
+// Attribute returns the IdP claim or attribute name mapped to key, or fallback
+func (c *SSOConnection) Attribute(key, fallback string) string {
+	if name := c.AttributeMapping[key]; name != "" {
+		return name
+	}
+	return fallback
+}
+
+// EmailDomain returns the lower-cased domain of an email address, or "" if it has none
+func EmailDomain(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 || at == len(email)-1 {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(email[at+1:]))
+}
+
+// SSOConnectionRequest creates or replaces an organization's SSO connection.
+type SSOConnectionRequest struct {
+	Enabled            *bool             `json:"enabled,omitempty"`
+	AttributeMapping   map[string]string `json:"attribute_mapping,omitempty"`
+	Protocol           SSOProtocol       `json:"protocol" binding:"required,oneof=oidc saml"`
+	Domain             string            `json:"domain" binding:"required"`
+	DefaultRole        string            `json:"default_role,omitempty" binding:"omitempty,oneof=admin developer viewer"`
+	OIDCIssuer         string            `json:"oidc_issuer,omitempty"`
+	OIDCClientID       string            `json:"oidc_client_id,omitempty"`
+	OIDCClientSecret   string            `json:"oidc_client_secret,omitempty"` // Write-only; empty keeps the stored secret
+	SAMLIdPEntityID    string            `json:"saml_idp_entity_id,omitempty"`
+	SAMLIdPSSOURL      string            `json:"saml_idp_sso_url,omitempty"`
+	SAMLIdPCertificate string            `json:"saml_idp_certificate,omitempty"`
+	EnforceSSO         bool              `json:"enforce_sso"`
+}
+
+// SSOConnectionResponse is a connection with the values an admin needs to set it up.
+type SSOConnectionResponse struct {
+	*SSOConnection
+	DNSRecord     string `json:"dns_record"`                // TXT record to publish on the domain
+	CallbackURL   string `json:"callback_url"`              // OIDC redirect URI or SAML assertion consumer service URL
+	SPEntityID    string `json:"sp_entity_id,omitempty"`    // SAML only
+	SPMetadataURL string `json:"sp_metadata_url,omitempty"` // SAML only
+}
+
+// SSOConnectionRepository defines the interface for SSO connection data access.
+type SSOConnectionRepository interface {
+	GetByID(ctx context.Context, id ulid.ULID) (*SSOConnection, error)
+	GetByOrganizationID(ctx context.Context, orgID ulid.ULID) (*SSOConnection, error)
+	GetByDomain(ctx context.Context, domain string) (*SSOConnection, error)
+	Save(ctx context.Context, connection *SSOConnection) error
+	Delete(ctx context.Context, orgID ulid.ULID) error
+}
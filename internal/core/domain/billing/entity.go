@@ -1,12 +1,14 @@
 package billing
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/lib/pq"
 	"github.com/shopspring/decimal"
 	"gorm.io/datatypes"
 
+	"brokle/pkg/sampling"
 	"brokle/pkg/ulid"
 )
 
@@ -386,11 +388,136 @@ type UsageBudget struct {
 	// Alert thresholds (flexible array of percentages, e.g., [50, 80, 100])
 	AlertThresholds pq.Int64Array `json:"alert_thresholds" gorm:"column:alert_thresholds;type:integer[];default:'{50,80,100}'" swaggertype:"array,integer"`
 
+	// Enforcement applied to ingestion once any limit is reached (none = alerts only)
+	EnforcementAction        EnforcementAction `json:"enforcement_action" db:"enforcement_action" gorm:"default:'none'"`
+	EnforcementSamplePercent *int              `json:"enforcement_sample_percent,omitempty" db:"enforcement_sample_percent"` // Share of traces kept when action is sample
+
 	IsActive  bool      `json:"is_active" db:"is_active"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
 
+// ExceededDimension returns the first dimension whose usage has reached its limit.
+func (b *UsageBudget) ExceededDimension() (AlertDimension, bool) {
+	if b.SpanLimit != nil && *b.SpanLimit > 0 && b.CurrentSpans >= *b.SpanLimit {
+		return AlertDimensionSpans, true
+	}
+	if b.BytesLimit != nil && *b.BytesLimit > 0 && b.CurrentBytes >= *b.BytesLimit {
+		return AlertDimensionBytes, true
+	}
+	if b.ScoreLimit != nil && *b.ScoreLimit > 0 && b.CurrentScores >= *b.ScoreLimit {
+		return AlertDimensionScores, true
+	}
+	if b.CostLimit != nil && b.CostLimit.IsPositive() && b.CurrentCost.GreaterThanOrEqual(*b.CostLimit) {
+		return AlertDimensionCost, true
+	}
+	return "", false
+}
+
+// EnforcementAction is what ingestion does once a budget limit is reached
+type EnforcementAction string
+
+const (
+	EnforcementActionNone         EnforcementAction = "none"          // Alerts only
+	EnforcementActionBlock        EnforcementAction = "block"         // Reject OTLP requests with 429
+	EnforcementActionSample       EnforcementAction = "sample"        // Keep a share of traces
+	EnforcementActionDropPayloads EnforcementAction = "drop_payloads" // Keep spans, drop input/output bodies
+)
+
+// IsValid checks if the action is a valid EnforcementAction value
+func (a EnforcementAction) IsValid() bool {
+	switch a {
+	case EnforcementActionNone, EnforcementActionBlock, EnforcementActionSample, EnforcementActionDropPayloads:
+		return true
+	default:
+		return false
+	}
+}
+
+// IngestionEnforcement is the ingestion policy in effect for an organization or project,
+// derived from budgets whose limits have been reached. Cached in Redis for OTLP handlers.
+type IngestionEnforcement struct {
+	Block         bool           `json:"block,omitempty"`
+	SamplePercent int            `json:"sample_percent,omitempty"` // 0 = no sampling, 1-99 = share of traces kept
+	DropPayloads  bool           `json:"drop_payloads,omitempty"`
+	BudgetID      ulid.ULID      `json:"budget_id"` // Budget behind the strictest action, for client-facing reasons
+	BudgetName    string         `json:"budget_name"`
+	Dimension     AlertDimension `json:"dimension"`
+	EvaluatedAt   time.Time      `json:"evaluated_at"`
+}
+
+// Blocks reports whether ingestion must be rejected.
+func (e *IngestionEnforcement) Blocks() bool {
+	return e != nil && e.Block
+}
+
+// Samples reports whether only a share of traces is kept.
+func (e *IngestionEnforcement) Samples() bool {
+	return e != nil && !e.Block && e.SamplePercent > 0 && e.SamplePercent < 100
+}
+
+// Restricts reports whether any action applies.
+func (e *IngestionEnforcement) Restricts() bool {
+	return e != nil && (e.Block || e.Samples() || e.DropPayloads)
+}
+
+// budgetSamplingSalt keeps budget sampling independent of the project's head and tail sampling.
+const budgetSamplingSalt = "budget"
+
+// KeepTrace decides deterministically by trace ID, so every span of a trace
+// gets the same decision across batches and transports.
+func (e *IngestionEnforcement) KeepTrace(traceID string) bool {
+	if !e.Samples() {
+		return true
+	}
+	return sampling.KeepTrace(traceID, budgetSamplingSalt, float64(e.SamplePercent)/100)
+}
+
+// Reason is the client-facing explanation for the enforcement.
+func (e *IngestionEnforcement) Reason() string {
+	if e == nil {
+		return ""
+	}
+	return fmt.Sprintf("budget '%s' has reached its %s limit", e.BudgetName, e.Dimension)
+}
+
+// Merge combines two policies, keeping the strictest of each action.
+// The budget reported is the one behind the strictest action.
+func (e *IngestionEnforcement) Merge(other *IngestionEnforcement) *IngestionEnforcement {
+	if !other.Restricts() {
+		return e
+	}
+	if !e.Restricts() {
+		return other
+	}
+
+	merged := *e
+	if other.enforcementRank() > e.enforcementRank() {
+		merged.BudgetID = other.BudgetID
+		merged.BudgetName = other.BudgetName
+		merged.Dimension = other.Dimension
+	}
+	merged.Block = e.Block || other.Block
+	merged.DropPayloads = e.DropPayloads || other.DropPayloads
+	if other.Samples() && (!e.Samples() || other.SamplePercent < e.SamplePercent) {
+		merged.SamplePercent = other.SamplePercent
+	}
+	return &merged
+}
+
+func (e *IngestionEnforcement) enforcementRank() int {
+	switch {
+	case e.Block:
+		return 3
+	case e.Samples():
+		return 2
+	case e.DropPayloads:
+		return 1
+	default:
+		return 0
+	}
+}
+
 type AlertDimension string
 
 const (
@@ -100,6 +100,18 @@ type UsageBudgetRepository interface {
 	Delete(ctx context.Context, id ulid.ULID) error
 }
 
+// EnforcementStateRepository caches evaluated ingestion enforcement (Redis)
+// Scope is OrganizationEnforcementScope for organization-level budgets or a project ID.
+type EnforcementStateRepository interface {
+	// Replace atomically swaps all cached state for an organization; an empty map clears it
+	Replace(ctx context.Context, orgID ulid.ULID, states map[string]*IngestionEnforcement, ttl time.Duration) error
+	// Get returns the organization-level and project-level state (nil when unrestricted)
+	Get(ctx context.Context, orgID, projectID ulid.ULID) (orgState, projectState *IngestionEnforcement, err error)
+}
+
+// OrganizationEnforcementScope is the state key for organization-level budgets
+const OrganizationEnforcementScope = "org"
+
 // UsageAlertRepository handles alert history (PostgreSQL)
 type UsageAlertRepository interface {
 	GetByID(ctx context.Context, id ulid.ULID) (*UsageAlert, error)
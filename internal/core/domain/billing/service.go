@@ -112,6 +112,17 @@ type BudgetService interface {
 	AcknowledgeAlert(ctx context.Context, orgID, alertID ulid.ULID) error
 }
 
+// EnforcementService turns reached budget limits into ingestion actions
+// (block, sample, drop payloads) and answers cheap per-request checks
+type EnforcementService interface {
+	// Refresh re-evaluates the organization's active budgets and caches the result
+	Refresh(ctx context.Context, orgID ulid.ULID) error
+
+	// GetIngestionEnforcement returns the combined policy for a project, or nil when unrestricted.
+	// Fails open: lookup errors are logged and treated as unrestricted.
+	GetIngestionEnforcement(ctx context.Context, orgID, projectID ulid.ULID) *IngestionEnforcement
+}
+
 // ============================================================================
 // Enterprise Custom Pricing Services
 // ============================================================================
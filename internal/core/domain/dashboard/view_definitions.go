@@ -68,7 +68,8 @@ type ViewDefinition struct {
 	Dimensions  map[string]DimensionConfig  `json:"dimensions"`
 }
 
-// TracesViewDefinition returns the view definition for traces (root spans)
+// TracesViewDefinition returns the view definition for traces (root spans).
+// Counts and sums are weighted by 1 / sample_rate so sampled-out traces still count.
 func TracesViewDefinition() *ViewDefinition {
 	return &ViewDefinition{
 		Name:        ViewTypeTraces,
@@ -81,7 +82,7 @@ func TracesViewDefinition() *ViewDefinition {
 				ID:          "count",
 				Label:       "Count",
 				Description: "Total number of traces",
-				SQL:         "count()",
+				SQL:         "toUInt64(round(sum(1 / sample_rate)))",
 				Type:        MeasureTypeCount,
 				Unit:        UnitTypeCount,
 			},
@@ -129,7 +130,7 @@ func TracesViewDefinition() *ViewDefinition {
 				ID:              "total_cost",
 				Label:           "Total Cost",
 				Description:     "Sum of all trace costs",
-				SQL:             "sum(total_cost)",
+				SQL:             "sum(toFloat64(total_cost) / sample_rate)",
 				BaseColumn:      "total_cost",
 				Type:            MeasureTypeSum,
 				Unit:            UnitTypeUSD,
@@ -149,7 +150,7 @@ func TracesViewDefinition() *ViewDefinition {
 				ID:              "total_input_tokens",
 				Label:           "Total Input Tokens",
 				Description:     "Sum of input tokens",
-				SQL:             "sum(usage_details['input_tokens'])",
+				SQL:             "toUInt64(round(sum(usage_details['input_tokens'] / sample_rate)))",
 				Type:            MeasureTypeSum,
 				Unit:            UnitTypeTokens,
 				HistogramColumn: "usage_details['input_tokens']",
@@ -158,7 +159,7 @@ func TracesViewDefinition() *ViewDefinition {
 				ID:              "total_output_tokens",
 				Label:           "Total Output Tokens",
 				Description:     "Sum of output tokens",
-				SQL:             "sum(usage_details['output_tokens'])",
+				SQL:             "toUInt64(round(sum(usage_details['output_tokens'] / sample_rate)))",
 				Type:            MeasureTypeSum,
 				Unit:            UnitTypeTokens,
 				HistogramColumn: "usage_details['output_tokens']",
@@ -167,7 +168,7 @@ func TracesViewDefinition() *ViewDefinition {
 				ID:              "total_tokens",
 				Label:           "Total Tokens",
 				Description:     "Sum of all tokens",
-				SQL:             "sum(usage_details['input_tokens'] + usage_details['output_tokens'])",
+				SQL:             "toUInt64(round(sum((usage_details['input_tokens'] + usage_details['output_tokens']) / sample_rate)))",
 				Type:            MeasureTypeSum,
 				Unit:            UnitTypeTokens,
 				HistogramColumn: "usage_details['input_tokens'] + usage_details['output_tokens']",
@@ -176,7 +177,7 @@ func TracesViewDefinition() *ViewDefinition {
 				ID:          "error_count",
 				Label:       "Error Count",
 				Description: "Number of traces with errors",
-				SQL:         "countIf(status_code = 2)",
+				SQL:         "toUInt64(round(sumIf(1 / sample_rate, status_code = 2)))",
 				Type:        MeasureTypeCount,
 				Unit:        UnitTypeCount,
 			},
@@ -184,7 +185,7 @@ func TracesViewDefinition() *ViewDefinition {
 				ID:          "error_rate",
 				Label:       "Error Rate",
 				Description: "Percentage of traces with errors",
-				SQL:         "if(count() = 0, null, countIf(status_code = 2) * 100.0 / count())",
+				SQL:         "if(count() = 0, null, sumIf(1 / sample_rate, status_code = 2) * 100.0 / sum(1 / sample_rate))",
 				Type:        MeasureTypeRate,
 				Unit:        UnitTypePercent,
 			},
@@ -301,7 +302,7 @@ func SpansViewDefinition() *ViewDefinition {
 				ID:          "count",
 				Label:       "Count",
 				Description: "Total number of spans",
-				SQL:         "count()",
+				SQL:         "toUInt64(round(sum(1 / sample_rate)))",
 				Type:        MeasureTypeCount,
 				Unit:        UnitTypeCount,
 			},
@@ -349,7 +350,7 @@ func SpansViewDefinition() *ViewDefinition {
 				ID:              "total_cost",
 				Label:           "Total Cost",
 				Description:     "Sum of all span costs",
-				SQL:             "sum(total_cost)",
+				SQL:             "sum(toFloat64(total_cost) / sample_rate)",
 				BaseColumn:      "total_cost",
 				Type:            MeasureTypeSum,
 				Unit:            UnitTypeUSD,
@@ -369,7 +370,7 @@ func SpansViewDefinition() *ViewDefinition {
 				ID:              "total_tokens",
 				Label:           "Total Tokens",
 				Description:     "Sum of all tokens",
-				SQL:             "sum(usage_details['input_tokens'] + usage_details['output_tokens'])",
+				SQL:             "toUInt64(round(sum((usage_details['input_tokens'] + usage_details['output_tokens']) / sample_rate)))",
 				Type:            MeasureTypeSum,
 				Unit:            UnitTypeTokens,
 				HistogramColumn: "usage_details['input_tokens'] + usage_details['output_tokens']",
@@ -378,7 +379,7 @@ func SpansViewDefinition() *ViewDefinition {
 				ID:          "error_count",
 				Label:       "Error Count",
 				Description: "Number of spans with errors",
-				SQL:         "countIf(status_code = 2)",
+				SQL:         "toUInt64(round(sumIf(1 / sample_rate, status_code = 2)))",
 				Type:        MeasureTypeCount,
 				Unit:        UnitTypeCount,
 			},
@@ -386,7 +387,7 @@ func SpansViewDefinition() *ViewDefinition {
 				ID:          "error_rate",
 				Label:       "Error Rate",
 				Description: "Percentage of spans with errors",
-				SQL:         "if(count() = 0, null, countIf(status_code = 2) * 100.0 / count())",
+				SQL:         "if(count() = 0, null, sumIf(1 / sample_rate, status_code = 2) * 100.0 / sum(1 / sample_rate))",
 				Type:        MeasureTypeRate,
 				Unit:        UnitTypePercent,
 			},
@@ -0,0 +1,500 @@
+package evaluation
+
+import (
+	"math"
+	"math/rand/v2"
+	"sort"
+)
+
+// SignificanceLevel is the p-value threshold below which a paired comparison
+// is reported as significant.
+const SignificanceLevel = 0.05
+
+const (
+	bootstrapResamples = 2000
+	// Fixed seed so repeated comparisons of the same experiments report the same interval
+	bootstrapSeed = 0x6272_6f6b_6c65
+
+	// Below this many discordant pairs McNemar uses the exact binomial test
+	mcnemarExactLimit = 25
+	// Up to this many non-zero differences without ties Wilcoxon uses the exact distribution
+	wilcoxonExactLimit = 25
+)
+
+// Paired tests reported in PairedComparison.Test
+const (
+	PairedTestT             = "paired_t_test"
+	PairedTestMcNemar       = "mcnemar"
+	PairedTestMcNemarBowker = "mcnemar_bowker"
+)
+
+// ConfidenceInterval is a bootstrap percentile interval of the mean paired difference.
+type ConfidenceInterval struct {
+	Level float64 `json:"level"`
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+}
+
+// PairedComparison compares a score against the baseline item by item, over
+// the dataset items scored in both experiments. Higher scores are treated as
+// better: a win is an item that scored higher than in the baseline.
+type PairedComparison struct {
+	Type        ScoreDiffType `json:"type"`
+	Test        string        `json:"test"`
+	PairedItems int           `json:"paired_items"`
+
+	// Mean of current minus baseline; the pass rate difference for BOOLEAN
+	MeanDifference     float64             `json:"mean_difference"`
+	ConfidenceInterval *ConfidenceInterval `json:"confidence_interval,omitempty"`
+
+	// PValue is the p-value of Test; WilcoxonPValue backs up the t-test for NUMERIC
+	PValue         *float64 `json:"p_value,omitempty"`
+	WilcoxonPValue *float64 `json:"wilcoxon_p_value,omitempty"`
+
+	Wins    int `json:"wins"`
+	Ties    int `json:"ties"`
+	Losses  int `json:"losses"`
+	Changed int `json:"changed,omitempty"` // CATEGORICAL items whose category changed
+
+	Significant           bool `json:"significant"`
+	SignificantRegression bool `json:"significant_regression"`
+}
+
+// ComparePairedNumeric compares numeric scores of the same items. The result
+// is significant when both the paired t-test and the Wilcoxon signed-rank test
+// reject equal means, so a few outliers cannot carry it alone.
+func ComparePairedNumeric(baseline, current []float64) *PairedComparison {
+	n := min(len(baseline), len(current))
+	diffs := make([]float64, n)
+	for i := range diffs {
+		diffs[i] = current[i] - baseline[i]
+	}
+
+	comparison := &PairedComparison{
+		Type:        ScoreDiffTypeNumeric,
+		Test:        PairedTestT,
+		PairedItems: n,
+	}
+	comparison.Wins, comparison.Ties, comparison.Losses = countOutcomes(diffs)
+	if n == 0 {
+		return comparison
+	}
+
+	comparison.MeanDifference = mean(diffs)
+	if n < 2 {
+		return comparison
+	}
+
+	comparison.ConfidenceInterval = bootstrapMeanInterval(diffs, 1-SignificanceLevel)
+	tP := pairedTTestPValue(diffs)
+	wP := wilcoxonSignedRankPValue(diffs)
+	comparison.PValue = &tP
+	comparison.WilcoxonPValue = &wP
+	comparison.Significant = tP < SignificanceLevel && wP < SignificanceLevel
+	comparison.SignificantRegression = comparison.Significant && comparison.MeanDifference < 0
+
+	return comparison
+}
+
+// ComparePairedBoolean compares pass/fail scores of the same items with McNemar's test
+func ComparePairedBoolean(baseline, current []bool) *PairedComparison {
+	n := min(len(baseline), len(current))
+	diffs := make([]float64, n)
+	for i := range diffs {
+		diffs[i] = boolToFloat(current[i]) - boolToFloat(baseline[i])
+	}
+
+	comparison := &PairedComparison{
+		Type:        ScoreDiffTypeBoolean,
+		Test:        PairedTestMcNemar,
+		PairedItems: n,
+	}
+	comparison.Wins, comparison.Ties, comparison.Losses = countOutcomes(diffs)
+	if n == 0 {
+		return comparison
+	}
+
+	comparison.MeanDifference = mean(diffs)
+	if n < 2 {
+		return comparison
+	}
+
+	comparison.ConfidenceInterval = bootstrapMeanInterval(diffs, 1-SignificanceLevel)
+	p := mcNemarPValue(comparison.Losses, comparison.Wins)
+	comparison.PValue = &p
+	comparison.Significant = p < SignificanceLevel
+	comparison.SignificantRegression = comparison.Significant && comparison.MeanDifference < 0
+
+	return comparison
+}
+
+// ComparePairedCategorical tests whether categories shifted between the
+// experiments with the McNemar-Bowker symmetry test. Categories carry no
+// order, so a shift is never flagged as a regression.
+func ComparePairedCategorical(baseline, current []string) *PairedComparison {
+	n := min(len(baseline), len(current))
+	comparison := &PairedComparison{
+		Type:        ScoreDiffTypeCategorical,
+		Test:        PairedTestMcNemarBowker,
+		PairedItems: n,
+	}
+
+	transitions := make(map[[2]string]int)
+	for i := 0; i < n; i++ {
+		if baseline[i] == current[i] {
+			comparison.Ties++
+			continue
+		}
+		comparison.Changed++
+		transitions[[2]string{baseline[i], current[i]}]++
+	}
+	if n < 2 {
+		return comparison
+	}
+
+	var statistic float64
+	df := 0
+	for pair, forward := range transitions {
+		backward := transitions[[2]string{pair[1], pair[0]}]
+		if pair[0] > pair[1] && backward > 0 {
+			continue // counted with its reverse
+		}
+		statistic += float64((forward-backward)*(forward-backward)) / float64(forward+backward)
+		df++
+	}
+
+	p := 1.0
+	if df > 0 {
+		p = chiSquareSurvival(statistic, float64(df))
+	}
+	comparison.PValue = &p
+	comparison.Significant = p < SignificanceLevel
+
+	return comparison
+}
+
+func countOutcomes(diffs []float64) (wins, ties, losses int) {
+	for _, d := range diffs {
+		switch {
+		case d > 0:
+			wins++
+		case d < 0:
+			losses++
+		default:
+			ties++
+		}
+	}
+	return wins, ties, losses
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func mean(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// bootstrapMeanInterval resamples the differences to estimate a percentile
+// confidence interval of their mean
+func bootstrapMeanInterval(diffs []float64, level float64) *ConfidenceInterval {
+	rng := rand.New(rand.NewPCG(bootstrapSeed, uint64(len(diffs))))
+	means := make([]float64, bootstrapResamples)
+	for i := range means {
+		var sum float64
+		for range diffs {
+			sum += diffs[rng.IntN(len(diffs))]
+		}
+		means[i] = sum / float64(len(diffs))
+	}
+	sort.Float64s(means)
+
+	tail := (1 - level) / 2
+	return &ConfidenceInterval{
+		Level: level,
+		Lower: quantile(means, tail),
+		Upper: quantile(means, 1-tail),
+	}
+}
+
+// quantile interpolates linearly between the closest ranks of sorted values
+func quantile(sorted []float64, q float64) float64 {
+	pos := q * float64(len(sorted)-1)
+	lower := int(math.Floor(pos))
+	upper := int(math.Ceil(pos))
+	frac := pos - float64(lower)
+	return sorted[lower]*(1-frac) + sorted[upper]*frac
+}
+
+// pairedTTestPValue returns the two-sided p-value of a one-sample t-test of
+// the differences against zero
+func pairedTTestPValue(diffs []float64) float64 {
+	n := float64(len(diffs))
+	m := mean(diffs)
+
+	var ss float64
+	for _, d := range diffs {
+		ss += (d - m) * (d - m)
+	}
+	sd := math.Sqrt(ss / (n - 1))
+	if sd == 0 {
+		// Every item moved by the same amount
+		if m == 0 {
+			return 1
+		}
+		return 0
+	}
+
+	t := m / (sd / math.Sqrt(n))
+	df := n - 1
+	return regularizedIncompleteBeta(df/(df+t*t), df/2, 0.5)
+}
+
+// wilcoxonSignedRankPValue returns the two-sided p-value of the Wilcoxon
+// signed-rank test. Zero differences are dropped; small samples without ties
+// use the exact distribution, others the normal approximation with tie and
+// continuity corrections.
+func wilcoxonSignedRankPValue(diffs []float64) float64 {
+	type rankedDiff struct {
+		abs      float64
+		positive bool
+	}
+	nonZero := make([]rankedDiff, 0, len(diffs))
+	for _, d := range diffs {
+		if d != 0 {
+			nonZero = append(nonZero, rankedDiff{abs: math.Abs(d), positive: d > 0})
+		}
+	}
+	n := len(nonZero)
+	if n == 0 {
+		return 1
+	}
+	sort.Slice(nonZero, func(i, j int) bool { return nonZero[i].abs < nonZero[j].abs })
+
+	// Average ranks over ties
+	var wPlus, tieCorrection float64
+	hasTies := false
+	for i := 0; i < n; {
+		j := i
+		for j+1 < n && nonZero[j+1].abs == nonZero[i].abs {
+			j++
+		}
+		rank := float64(i+j+2) / 2
+		for k := i; k <= j; k++ {
+			if nonZero[k].positive {
+				wPlus += rank
+			}
+		}
+		if t := float64(j - i + 1); t > 1 {
+			hasTies = true
+			tieCorrection += t*t*t - t
+		}
+		i = j + 1
+	}
+
+	if !hasTies && n <= wilcoxonExactLimit {
+		return wilcoxonExactPValue(n, int(wPlus))
+	}
+
+	fn := float64(n)
+	mu := fn * (fn + 1) / 4
+	sigma := math.Sqrt(fn*(fn+1)*(2*fn+1)/24 - tieCorrection/48)
+	if sigma == 0 {
+		return 1
+	}
+	z := math.Max(math.Abs(wPlus-mu)-0.5, 0) / sigma
+	return math.Erfc(z / math.Sqrt2)
+}
+
+// wilcoxonExactPValue counts the rank subsets of 1..n to get the exact
+// two-sided p-value of the statistic
+func wilcoxonExactPValue(n, wPlus int) float64 {
+	maxSum := n * (n + 1) / 2
+	counts := make([]float64, maxSum+1)
+	counts[0] = 1
+	for rank := 1; rank <= n; rank++ {
+		for sum := maxSum; sum >= rank; sum-- {
+			counts[sum] += counts[sum-rank]
+		}
+	}
+
+	total := math.Pow(2, float64(n))
+	var lower, upper float64
+	for sum, count := range counts {
+		if sum <= wPlus {
+			lower += count
+		}
+		if sum >= wPlus {
+			upper += count
+		}
+	}
+	return math.Min(1, 2*math.Min(lower, upper)/total)
+}
+
+// mcNemarPValue tests whether the discordant pairs split evenly between
+// losses (baseline passed, current failed) and wins
+func mcNemarPValue(losses, wins int) float64 {
+	discordant := losses + wins
+	if discordant == 0 {
+		return 1
+	}
+
+	if discordant < mcnemarExactLimit {
+		k := min(losses, wins)
+		var tail float64
+		for i := 0; i <= k; i++ {
+			tail += math.Exp(logBinomial(discordant, i) - float64(discordant)*math.Ln2)
+		}
+		return math.Min(1, 2*tail)
+	}
+
+	diff := math.Abs(float64(losses-wins)) - 1
+	return chiSquareSurvival(diff*diff/float64(discordant), 1)
+}
+
+func logBinomial(n, k int) float64 {
+	a, _ := math.Lgamma(float64(n + 1))
+	b, _ := math.Lgamma(float64(k + 1))
+	c, _ := math.Lgamma(float64(n - k + 1))
+	return a - b - c
+}
+
+// chiSquareSurvival returns P(X >= x) for a chi-square distribution
+func chiSquareSurvival(x, df float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+	return regularizedGammaQ(df/2, x/2)
+}
+
+const (
+	specialFuncMaxIter = 500
+	specialFuncEpsilon = 1e-14
+	specialFuncTiny    = 1e-300
+)
+
+// regularizedIncompleteBeta evaluates I_x(a, b) with Lentz's continued fraction
+func regularizedIncompleteBeta(x, a, b float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+
+	lgab, _ := math.Lgamma(a + b)
+	lga, _ := math.Lgamma(a)
+	lgb, _ := math.Lgamma(b)
+	front := math.Exp(lgab - lga - lgb + a*math.Log(x) + b*math.Log(1-x))
+
+	// The continued fraction converges quickly only below the mean
+	if x < (a+1)/(a+b+2) {
+		return front * betaContinuedFraction(x, a, b) / a
+	}
+	return 1 - front*betaContinuedFraction(1-x, b, a)/b
+}
+
+func betaContinuedFraction(x, a, b float64) float64 {
+	c := 1.0
+	d := 1 - (a+b)*x/(a+1)
+	if math.Abs(d) < specialFuncTiny {
+		d = specialFuncTiny
+	}
+	d = 1 / d
+	h := d
+
+	for m := 1; m <= specialFuncMaxIter; m++ {
+		fm := float64(m)
+		m2 := 2 * fm
+
+		// Even step
+		aa := fm * (b - fm) * x / ((a + m2 - 1) * (a + m2))
+		d = 1 + aa*d
+		if math.Abs(d) < specialFuncTiny {
+			d = specialFuncTiny
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < specialFuncTiny {
+			c = specialFuncTiny
+		}
+		d = 1 / d
+		h *= d * c
+
+		// Odd step
+		aa = -(a + fm) * (a + b + fm) * x / ((a + m2) * (a + m2 + 1))
+		d = 1 + aa*d
+		if math.Abs(d) < specialFuncTiny {
+			d = specialFuncTiny
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < specialFuncTiny {
+			c = specialFuncTiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+
+		if math.Abs(delta-1) < specialFuncEpsilon {
+			break
+		}
+	}
+	return h
+}
+
+// regularizedGammaQ evaluates the upper regularized incomplete gamma Q(a, x)
+func regularizedGammaQ(a, x float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+	lga, _ := math.Lgamma(a)
+	prefix := -x + a*math.Log(x) - lga
+
+	if x < a+1 {
+		// Series for P(a, x)
+		sum := 1 / a
+		term := sum
+		ap := a
+		for i := 0; i < specialFuncMaxIter; i++ {
+			ap++
+			term *= x / ap
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*specialFuncEpsilon {
+				break
+			}
+		}
+		return 1 - sum*math.Exp(prefix)
+	}
+
+	// Continued fraction for Q(a, x)
+	b := x + 1 - a
+	c := 1 / specialFuncTiny
+	d := 1 / b
+	h := d
+	for i := 1; i <= specialFuncMaxIter; i++ {
+		fi := float64(i)
+		an := -fi * (fi - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < specialFuncTiny {
+			d = specialFuncTiny
+		}
+		c = b + an/c
+		if math.Abs(c) < specialFuncTiny {
+			c = specialFuncTiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < specialFuncEpsilon {
+			break
+		}
+	}
+	return math.Exp(prefix) * h
+}
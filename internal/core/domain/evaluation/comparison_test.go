@@ -0,0 +1,132 @@
+package evaluation
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComparePairedNumeric(t *testing.T) {
+	t.Run("small noisy shift is not significant", func(t *testing.T) {
+		// 40 items whose scores move up or down by 0.3, netting a +0.02 mean shift
+		baseline := make([]float64, 40)
+		current := make([]float64, 40)
+		for i := range baseline {
+			baseline[i] = 0.5
+			switch {
+			case i < 2:
+				current[i] = 0.9
+			case i%2 == 0:
+				current[i] = 0.8
+			default:
+				current[i] = 0.2
+			}
+		}
+
+		comparison := ComparePairedNumeric(baseline, current)
+		assert.Equal(t, 40, comparison.PairedItems)
+		assert.InDelta(t, 0.02, comparison.MeanDifference, 1e-9)
+		require.NotNil(t, comparison.PValue)
+		assert.Greater(t, *comparison.PValue, 0.5)
+		assert.False(t, comparison.Significant)
+		assert.False(t, comparison.SignificantRegression)
+		require.NotNil(t, comparison.ConfidenceInterval)
+		assert.Less(t, comparison.ConfidenceInterval.Lower, 0.0)
+		assert.Greater(t, comparison.ConfidenceInterval.Upper, 0.0)
+		assert.Equal(t, 21, comparison.Wins)
+		assert.Equal(t, 19, comparison.Losses)
+	})
+
+	t.Run("consistent regression is flagged", func(t *testing.T) {
+		baseline := []float64{0.8, 0.9, 0.7, 0.85, 0.95, 0.75, 0.8, 0.9}
+		current := []float64{0.69, 0.78, 0.57, 0.71, 0.8, 0.59, 0.63, 0.72}
+
+		comparison := ComparePairedNumeric(baseline, current)
+		assert.Less(t, comparison.MeanDifference, 0.0)
+		assert.True(t, comparison.Significant)
+		assert.True(t, comparison.SignificantRegression)
+		assert.Equal(t, 8, comparison.Losses)
+		// Exact Wilcoxon: every difference negative among 8 items
+		assert.InDelta(t, 2.0/256, *comparison.WilcoxonPValue, 1e-12)
+		assert.Less(t, comparison.ConfidenceInterval.Upper, 0.0)
+	})
+
+	t.Run("identical scores", func(t *testing.T) {
+		comparison := ComparePairedNumeric([]float64{1, 0.5, 0}, []float64{1, 0.5, 0})
+		assert.Equal(t, 3, comparison.Ties)
+		assert.Equal(t, 1.0, *comparison.PValue)
+		assert.Equal(t, 1.0, *comparison.WilcoxonPValue)
+		assert.False(t, comparison.Significant)
+	})
+
+	t.Run("single item has no test", func(t *testing.T) {
+		comparison := ComparePairedNumeric([]float64{0.2}, []float64{0.9})
+		assert.Equal(t, 1, comparison.Wins)
+		assert.Nil(t, comparison.PValue)
+		assert.Nil(t, comparison.ConfidenceInterval)
+		assert.False(t, comparison.Significant)
+	})
+
+	t.Run("bootstrap interval is reproducible", func(t *testing.T) {
+		baseline := []float64{0.1, 0.4, 0.35, 0.8, 0.5}
+		current := []float64{0.3, 0.2, 0.5, 0.9, 0.45}
+		assert.Equal(t, ComparePairedNumeric(baseline, current).ConfidenceInterval, ComparePairedNumeric(baseline, current).ConfidenceInterval)
+	})
+}
+
+func TestComparePairedBoolean(t *testing.T) {
+	t.Run("exact McNemar on discordant pairs", func(t *testing.T) {
+		// 6 items fixed, none broken, 4 unchanged
+		baseline := []bool{false, false, false, false, false, false, true, true, false, true}
+		current := []bool{true, true, true, true, true, true, true, true, false, true}
+
+		comparison := ComparePairedBoolean(baseline, current)
+		assert.Equal(t, ScoreDiffTypeBoolean, comparison.Type)
+		assert.Equal(t, PairedTestMcNemar, comparison.Test)
+		assert.Equal(t, 6, comparison.Wins)
+		assert.Equal(t, 4, comparison.Ties)
+		assert.Equal(t, 0, comparison.Losses)
+		assert.InDelta(t, 0.6, comparison.MeanDifference, 1e-9)
+		assert.InDelta(t, 2.0/64, *comparison.PValue, 1e-12)
+		assert.True(t, comparison.Significant)
+		assert.False(t, comparison.SignificantRegression)
+	})
+
+	t.Run("balanced changes are not significant", func(t *testing.T) {
+		baseline := []bool{true, false, true, false, true, true}
+		current := []bool{false, true, true, false, true, true}
+
+		comparison := ComparePairedBoolean(baseline, current)
+		assert.Equal(t, 1.0, *comparison.PValue)
+		assert.False(t, comparison.Significant)
+	})
+}
+
+func TestComparePairedCategorical(t *testing.T) {
+	baseline := []string{"positive", "positive", "neutral", "negative", "positive", "neutral"}
+	current := []string{"neutral", "positive", "neutral", "negative", "neutral", "positive"}
+
+	comparison := ComparePairedCategorical(baseline, current)
+	assert.Equal(t, PairedTestMcNemarBowker, comparison.Test)
+	assert.Equal(t, 3, comparison.Ties)
+	assert.Equal(t, 3, comparison.Changed)
+	// positive->neutral twice, neutral->positive once: (2-1)^2/3 on one degree of freedom
+	assert.InDelta(t, math.Erfc(math.Sqrt(1.0/3/2)), *comparison.PValue, 1e-9)
+	assert.False(t, comparison.Significant)
+	assert.False(t, comparison.SignificantRegression)
+}
+
+func TestSignificanceDistributions(t *testing.T) {
+	// Two-sided 5% critical values of Student's t
+	assert.InDelta(t, 0.05, regularizedIncompleteBeta(9/(9+2.262157*2.262157), 4.5, 0.5), 1e-5)
+	assert.InDelta(t, 0.05, regularizedIncompleteBeta(5/(5+2.570582*2.570582), 2.5, 0.5), 1e-5)
+	assert.InDelta(t, 0.5, regularizedIncompleteBeta(0.5, 3, 3), 1e-12)
+
+	assert.InDelta(t, 0.05, chiSquareSurvival(3.841459, 1), 1e-6)
+	assert.InDelta(t, math.Exp(-1.75), chiSquareSurvival(3.5, 2), 1e-12)
+	assert.InDelta(t, 0.05, chiSquareSurvival(11.0705, 5), 1e-5)
+
+	assert.InDelta(t, 2*7.0/64, mcNemarPValue(1, 5), 1e-12)
+}
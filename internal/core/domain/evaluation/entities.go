@@ -422,6 +422,9 @@ type Experiment struct {
 	CompletedAt    *time.Time             `json:"completed_at,omitempty"`
 	CreatedAt      time.Time              `json:"created_at" gorm:"not null;autoCreateTime"`
 	UpdatedAt      time.Time              `json:"updated_at" gorm:"not null;autoUpdateTime"`
+	// CI gates, evaluated into GateVerdict when the experiment finishes
+	Gates       []ExperimentGate `json:"gates,omitempty" gorm:"type:jsonb;serializer:json;not null;default:'[]'"`
+	GateVerdict *GateVerdict     `json:"gate_verdict,omitempty" gorm:"type:jsonb;serializer:json"`
 
 	// Relationships (optional, loaded when needed)
 	Config *ExperimentConfig `json:"config,omitempty" gorm:"foreignKey:ConfigID"`
@@ -440,6 +443,7 @@ func NewExperiment(projectID ulid.ULID, name string) *Experiment {
 		Status:    ExperimentStatusPending,
 		Source:    ExperimentSourceSDK, // Default to SDK for backwards compatibility
 		Metadata:  make(map[string]interface{}),
+		Gates:     []ExperimentGate{},
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
@@ -455,6 +459,7 @@ func NewExperimentFromDashboard(projectID ulid.ULID, name string) *Experiment {
 		Status:    ExperimentStatusPending,
 		Source:    ExperimentSourceDashboard,
 		Metadata:  make(map[string]interface{}),
+		Gates:     []ExperimentGate{},
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
@@ -484,6 +489,7 @@ type CreateExperimentRequest struct {
 	DatasetID   *string                `json:"dataset_id,omitempty"`
 	Description *string                `json:"description,omitempty"`
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	Gates       []ExperimentGate       `json:"gates,omitempty"`
 }
 
 // RerunExperimentRequest is the request to create a new experiment based on an existing one.
@@ -530,6 +536,8 @@ type ExperimentResponse struct {
 	CompletedAt    *time.Time             `json:"completed_at,omitempty"`
 	CreatedAt      time.Time              `json:"created_at"`
 	UpdatedAt      time.Time              `json:"updated_at"`
+	Gates          []ExperimentGate       `json:"gates,omitempty"`
+	GateVerdict    *GateVerdict           `json:"gate_verdict,omitempty"`
 }
 
 func (e *Experiment) ToResponse() *ExperimentResponse {
@@ -562,6 +570,8 @@ func (e *Experiment) ToResponse() *ExperimentResponse {
 		CompletedAt:    e.CompletedAt,
 		CreatedAt:      e.CreatedAt,
 		UpdatedAt:      e.UpdatedAt,
+		Gates:          e.Gates,
+		GateVerdict:    e.GateVerdict,
 	}
 }
 
@@ -757,6 +767,7 @@ type ScoreDiffType string
 const (
 	ScoreDiffTypeNumeric     ScoreDiffType = "NUMERIC"
 	ScoreDiffTypeCategorical ScoreDiffType = "CATEGORICAL"
+	ScoreDiffTypeBoolean     ScoreDiffType = "BOOLEAN"
 )
 
 // ScoreDiff represents the difference between a score and its baseline.
@@ -785,6 +796,9 @@ type CompareExperimentsResponse struct {
 	Experiments map[string]*ExperimentSummary              `json:"experiments"`
 	Scores      map[string]map[string]*ScoreAggregation    `json:"scores"`      // scoreName -> experimentID -> aggregation
 	Diffs       map[string]map[string]*ScoreDiff           `json:"diffs,omitempty"` // scoreName -> experimentID -> diff (vs baseline)
+	Comparisons map[string]map[string]*PairedComparison    `json:"comparisons,omitempty"` // scoreName -> experimentID -> paired comparison (vs baseline)
+	// SignificantRegression is set when any score regressed significantly against the baseline
+	SignificantRegression bool `json:"significant_regression"`
 }
 
 // CalculateDiff computes the difference between two score aggregations.
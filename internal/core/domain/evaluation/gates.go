@@ -0,0 +1,183 @@
+package evaluation
+
+import (
+	"fmt"
+	"time"
+
+	"brokle/pkg/ulid"
+)
+
+// MaxExperimentGates bounds the gates of one experiment
+const MaxExperimentGates = 50
+
+// GateMetric is the experiment metric an evaluation gate asserts on.
+type GateMetric string
+
+const (
+	// GateMetricScoreMean is the mean of a score over the experiment's items
+	GateMetricScoreMean GateMetric = "score_mean"
+	// GateMetricScoreRate is the fraction of scored items whose score equals MatchValue
+	GateMetricScoreRate GateMetric = "score_rate"
+	// GateMetricCostPerItem is the mean "cost" recorded in item metadata
+	GateMetricCostPerItem GateMetric = "cost_per_item"
+	// GateMetricErrorRate is the fraction of processed items that failed
+	GateMetricErrorRate GateMetric = "error_rate"
+	// GateMetricNoRegression requires no significant regression against a baseline experiment
+	GateMetricNoRegression GateMetric = "no_regression"
+)
+
+// GateOperator compares a gate's metric with its threshold.
+type GateOperator string
+
+const (
+	GateOperatorGTE GateOperator = ">="
+	GateOperatorGT  GateOperator = ">"
+	GateOperatorLTE GateOperator = "<="
+	GateOperatorLT  GateOperator = "<"
+	GateOperatorEQ  GateOperator = "=="
+)
+
+// ExperimentGate is a pass/fail assertion evaluated when an experiment finishes,
+// e.g. "faithfulness mean >= 0.8" or "no more than 2% of items with json_valid = 0".
+type ExperimentGate struct {
+	Name      string       `json:"name,omitempty"`
+	Metric    GateMetric   `json:"metric"`
+	ScoreName string       `json:"score_name,omitempty"` // score_mean, score_rate; optional for no_regression
+	Operator  GateOperator `json:"operator,omitempty"`   // not used by no_regression
+	Threshold float64      `json:"threshold"`
+	// MatchValue is the score value counted by score_rate, e.g. 0 for failed json_valid checks
+	MatchValue           *float64 `json:"match_value,omitempty"`
+	BaselineExperimentID *string  `json:"baseline_experiment_id,omitempty"` // no_regression
+}
+
+// Validate checks that the gate has what its metric needs
+func (g *ExperimentGate) Validate() *ValidationError {
+	switch g.Metric {
+	case GateMetricScoreMean, GateMetricScoreRate:
+		if g.ScoreName == "" {
+			return &ValidationError{Field: "score_name", Message: fmt.Sprintf("score_name is required for %s gates", g.Metric)}
+		}
+		if g.Metric == GateMetricScoreRate && g.MatchValue == nil {
+			return &ValidationError{Field: "match_value", Message: "match_value is required for score_rate gates"}
+		}
+	case GateMetricCostPerItem, GateMetricErrorRate:
+	case GateMetricNoRegression:
+		if g.BaselineExperimentID == nil {
+			return &ValidationError{Field: "baseline_experiment_id", Message: "baseline_experiment_id is required for no_regression gates"}
+		}
+		if _, err := ulid.Parse(*g.BaselineExperimentID); err != nil {
+			return &ValidationError{Field: "baseline_experiment_id", Message: "must be a valid ULID"}
+		}
+		return nil
+	default:
+		return &ValidationError{Field: "metric", Message: "metric must be one of score_mean, score_rate, cost_per_item, error_rate, no_regression"}
+	}
+
+	switch g.Operator {
+	case GateOperatorGTE, GateOperatorGT, GateOperatorLTE, GateOperatorLT, GateOperatorEQ:
+	default:
+		return &ValidationError{Field: "operator", Message: "operator must be one of >=, >, <=, <, =="}
+	}
+	return nil
+}
+
+// DisplayName returns the gate's name, or a readable form of its assertion
+func (g *ExperimentGate) DisplayName() string {
+	if g.Name != "" {
+		return g.Name
+	}
+	switch g.Metric {
+	case GateMetricScoreMean:
+		return fmt.Sprintf("%s mean %s %g", g.ScoreName, g.Operator, g.Threshold)
+	case GateMetricScoreRate:
+		return fmt.Sprintf("rate of %s = %g %s %g", g.ScoreName, derefFloat(g.MatchValue), g.Operator, g.Threshold)
+	case GateMetricNoRegression:
+		target := "any score"
+		if g.ScoreName != "" {
+			target = g.ScoreName
+		}
+		return fmt.Sprintf("no significant regression of %s vs %s", target, derefString(g.BaselineExperimentID))
+	default:
+		return fmt.Sprintf("%s %s %g", g.Metric, g.Operator, g.Threshold)
+	}
+}
+
+// Check compares an actual metric value with the gate's threshold
+func (g *ExperimentGate) Check(actual float64) bool {
+	switch g.Operator {
+	case GateOperatorGTE:
+		return actual >= g.Threshold
+	case GateOperatorGT:
+		return actual > g.Threshold
+	case GateOperatorLTE:
+		return actual <= g.Threshold
+	case GateOperatorLT:
+		return actual < g.Threshold
+	case GateOperatorEQ:
+		return actual == g.Threshold
+	default:
+		return false
+	}
+}
+
+// ValidateGates validates a set of gates, reporting the first invalid one
+func ValidateGates(gates []ExperimentGate) *ValidationError {
+	if len(gates) > MaxExperimentGates {
+		return &ValidationError{Field: "gates", Message: fmt.Sprintf("at most %d gates are allowed", MaxExperimentGates)}
+	}
+	for i := range gates {
+		if verr := gates[i].Validate(); verr != nil {
+			return &ValidationError{Field: fmt.Sprintf("gates[%d].%s", i, verr.Field), Message: verr.Message}
+		}
+	}
+	return nil
+}
+
+// GateVerdictStatus is the outcome of an experiment's gates.
+type GateVerdictStatus string
+
+const (
+	// GateVerdictPending means the experiment has not finished yet
+	GateVerdictPending GateVerdictStatus = "pending"
+	GateVerdictPassed  GateVerdictStatus = "passed"
+	GateVerdictFailed  GateVerdictStatus = "failed"
+)
+
+// GateResult is the outcome of one gate.
+type GateResult struct {
+	Gate    ExperimentGate `json:"gate"`
+	Name    string         `json:"name"`
+	Passed  bool           `json:"passed"`
+	Actual  *float64       `json:"actual,omitempty"`
+	Message string         `json:"message"`
+}
+
+// GateVerdict is the machine-readable pass/fail verdict of an experiment's gates.
+type GateVerdict struct {
+	ExperimentID   string            `json:"experiment_id"`
+	ExperimentName string            `json:"experiment_name"`
+	Status         GateVerdictStatus `json:"status"`
+	Passed         bool              `json:"passed"`
+	Reason         string            `json:"reason,omitempty"`
+	Results        []GateResult      `json:"results"`
+	EvaluatedAt    *time.Time        `json:"evaluated_at,omitempty"`
+}
+
+// SetExperimentGatesRequest replaces the gates of an experiment.
+type SetExperimentGatesRequest struct {
+	Gates []ExperimentGate `json:"gates"`
+}
+
+func derefFloat(v *float64) float64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
+func derefString(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
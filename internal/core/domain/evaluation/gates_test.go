@@ -0,0 +1,79 @@
+package evaluation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExperimentGate_Validate(t *testing.T) {
+	zero := 0.0
+	baseline := "01HQXYZ0000000000000000000"
+	invalid := "not-a-ulid"
+
+	tests := []struct {
+		name  string
+		gate  ExperimentGate
+		field string
+	}{
+		{"score mean", ExperimentGate{Metric: GateMetricScoreMean, ScoreName: "faithfulness", Operator: GateOperatorGTE, Threshold: 0.8}, ""},
+		{"score mean without score", ExperimentGate{Metric: GateMetricScoreMean, Operator: GateOperatorGTE}, "score_name"},
+		{"score rate", ExperimentGate{Metric: GateMetricScoreRate, ScoreName: "json_valid", Operator: GateOperatorLTE, Threshold: 0.02, MatchValue: &zero}, ""},
+		{"score rate without match value", ExperimentGate{Metric: GateMetricScoreRate, ScoreName: "json_valid", Operator: GateOperatorLTE}, "match_value"},
+		{"cost without operator", ExperimentGate{Metric: GateMetricCostPerItem, Threshold: 0.002}, "operator"},
+		{"no regression", ExperimentGate{Metric: GateMetricNoRegression, BaselineExperimentID: &baseline}, ""},
+		{"no regression without baseline", ExperimentGate{Metric: GateMetricNoRegression}, "baseline_experiment_id"},
+		{"no regression with invalid baseline", ExperimentGate{Metric: GateMetricNoRegression, BaselineExperimentID: &invalid}, "baseline_experiment_id"},
+		{"unknown metric", ExperimentGate{Metric: "latency", Operator: GateOperatorLT}, "metric"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verr := tt.gate.Validate()
+			if tt.field == "" {
+				assert.Nil(t, verr)
+				return
+			}
+			require.NotNil(t, verr)
+			assert.Equal(t, tt.field, verr.Field)
+		})
+	}
+}
+
+func TestValidateGates(t *testing.T) {
+	gates := []ExperimentGate{
+		{Metric: GateMetricErrorRate, Operator: GateOperatorLT, Threshold: 0.05},
+		{Metric: GateMetricScoreMean, Operator: GateOperatorGTE},
+	}
+	verr := ValidateGates(gates)
+	require.NotNil(t, verr)
+	assert.Equal(t, "gates[1].score_name", verr.Field)
+
+	assert.Nil(t, ValidateGates(nil))
+	assert.NotNil(t, ValidateGates(make([]ExperimentGate, MaxExperimentGates+1)))
+}
+
+func TestExperimentGate_Check(t *testing.T) {
+	gate := ExperimentGate{Operator: GateOperatorGTE, Threshold: 0.8}
+	assert.True(t, gate.Check(0.8))
+	assert.False(t, gate.Check(0.79))
+
+	gate.Operator = GateOperatorLT
+	assert.True(t, gate.Check(0.79))
+	assert.False(t, gate.Check(0.8))
+
+	gate.Operator = GateOperatorEQ
+	assert.True(t, gate.Check(0.8))
+
+	gate.Operator = ""
+	assert.False(t, gate.Check(0.8))
+}
+
+func TestExperimentGate_DisplayName(t *testing.T) {
+	zero := 0.0
+	assert.Equal(t, "faithfulness mean >= 0.8", (&ExperimentGate{Metric: GateMetricScoreMean, ScoreName: "faithfulness", Operator: GateOperatorGTE, Threshold: 0.8}).DisplayName())
+	assert.Equal(t, "rate of json_valid = 0 <= 0.02", (&ExperimentGate{Metric: GateMetricScoreRate, ScoreName: "json_valid", Operator: GateOperatorLTE, Threshold: 0.02, MatchValue: &zero}).DisplayName())
+	assert.Equal(t, "cost_per_item <= 0.002", (&ExperimentGate{Metric: GateMetricCostPerItem, Operator: GateOperatorLTE, Threshold: 0.002}).DisplayName())
+	assert.Equal(t, "budget", (&ExperimentGate{Name: "budget", Metric: GateMetricCostPerItem}).DisplayName())
+}
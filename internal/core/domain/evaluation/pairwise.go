@@ -0,0 +1,282 @@
+package evaluation
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"brokle/pkg/ulid"
+)
+
+const (
+	// DefaultPairwiseScoreName is the score written by pairwise evaluators without a score_name
+	DefaultPairwiseScoreName = "preference"
+	// MaxPairwiseExperiments bounds the experiments judged in one pairwise trigger
+	MaxPairwiseExperiments = 10
+	// MaxPairwiseSpanPairs bounds the span pairs judged in one pairwise trigger
+	MaxPairwiseSpanPairs = 1000
+
+	// EloInitialRating is the rating every contestant starts from
+	EloInitialRating = 1000.0
+	// EloKFactor is the maximum rating change of one match
+	EloKFactor = 32.0
+
+	bradleyTerryMaxIterations = 1000
+	bradleyTerryTolerance     = 1e-9
+)
+
+// PairwiseOutcome is the categorical value of a pairwise score, from the
+// point of view of the scored output.
+type PairwiseOutcome string
+
+const (
+	PairwiseOutcomeWin  PairwiseOutcome = "win"
+	PairwiseOutcomeLose PairwiseOutcome = "lose"
+	PairwiseOutcomeTie  PairwiseOutcome = "tie"
+)
+
+// Invert returns the outcome seen from the opponent's side
+func (o PairwiseOutcome) Invert() PairwiseOutcome {
+	switch o {
+	case PairwiseOutcomeWin:
+		return PairwiseOutcomeLose
+	case PairwiseOutcomeLose:
+		return PairwiseOutcomeWin
+	default:
+		return o
+	}
+}
+
+// IsValid reports whether the outcome is win, lose or tie
+func (o PairwiseOutcome) IsValid() bool {
+	switch o {
+	case PairwiseOutcomeWin, PairwiseOutcomeLose, PairwiseOutcomeTie:
+		return true
+	}
+	return false
+}
+
+// PairwiseSpanPair is two spans whose outputs are judged against each other.
+type PairwiseSpanPair struct {
+	SpanA string `json:"span_a"`
+	SpanB string `json:"span_b"`
+}
+
+// PairwiseTriggerRequest starts a pairwise evaluator on either the experiments'
+// outputs for shared dataset items (every pair of experiments) or explicit span pairs.
+type PairwiseTriggerRequest struct {
+	ExperimentIDs []string           `json:"experiment_ids,omitempty"`
+	SpanPairs     []PairwiseSpanPair `json:"span_pairs,omitempty"`
+	SampleLimit   int                `json:"sample_limit,omitempty"` // Optional: max pairs to judge (default: 1000)
+}
+
+func (r *PairwiseTriggerRequest) Validate() *ValidationError {
+	if len(r.ExperimentIDs) > 0 && len(r.SpanPairs) > 0 {
+		return &ValidationError{Field: "experiment_ids", Message: "provide either experiment_ids or span_pairs, not both"}
+	}
+	if r.SampleLimit < 0 {
+		return &ValidationError{Field: "sample_limit", Message: "sample_limit must not be negative"}
+	}
+
+	if len(r.SpanPairs) > 0 {
+		if len(r.SpanPairs) > MaxPairwiseSpanPairs {
+			return &ValidationError{Field: "span_pairs", Message: fmt.Sprintf("at most %d span pairs are allowed", MaxPairwiseSpanPairs)}
+		}
+		for i, pair := range r.SpanPairs {
+			if pair.SpanA == "" || pair.SpanB == "" {
+				return &ValidationError{Field: fmt.Sprintf("span_pairs[%d]", i), Message: "span_a and span_b are required"}
+			}
+			if pair.SpanA == pair.SpanB {
+				return &ValidationError{Field: fmt.Sprintf("span_pairs[%d]", i), Message: "span_a and span_b must differ"}
+			}
+		}
+		return nil
+	}
+
+	if len(r.ExperimentIDs) < 2 {
+		return &ValidationError{Field: "experiment_ids", Message: "at least 2 experiments or one span pair are required"}
+	}
+	if len(r.ExperimentIDs) > MaxPairwiseExperiments {
+		return &ValidationError{Field: "experiment_ids", Message: fmt.Sprintf("at most %d experiments are allowed", MaxPairwiseExperiments)}
+	}
+	seen := make(map[string]struct{}, len(r.ExperimentIDs))
+	for _, id := range r.ExperimentIDs {
+		if _, err := ulid.Parse(id); err != nil {
+			return &ValidationError{Field: "experiment_ids", Message: fmt.Sprintf("invalid experiment ID: %s", id)}
+		}
+		if _, dup := seen[id]; dup {
+			return &ValidationError{Field: "experiment_ids", Message: fmt.Sprintf("duplicate experiment ID: %s", id)}
+		}
+		seen[id] = struct{}{}
+	}
+	return nil
+}
+
+// PairwiseLeaderboardRequest ranks experiments by the pairwise scores between them.
+type PairwiseLeaderboardRequest struct {
+	ExperimentIDs []string `json:"experiment_ids" binding:"required,min=2,max=20"`
+	ScoreName     string   `json:"score_name,omitempty"` // Default: preference
+}
+
+// PairwiseMatch is one judged pair; Outcome is from A's point of view.
+type PairwiseMatch struct {
+	A       string
+	B       string
+	Outcome PairwiseOutcome
+}
+
+// LeaderboardEntry is one contestant of a pairwise leaderboard.
+type LeaderboardEntry struct {
+	Rank           int     `json:"rank"`
+	ExperimentID   string  `json:"experiment_id"`
+	ExperimentName string  `json:"experiment_name,omitempty"`
+	Matches        int     `json:"matches"`
+	Wins           int     `json:"wins"`
+	Losses         int     `json:"losses"`
+	Ties           int     `json:"ties"`
+	WinRate        float64 `json:"win_rate"` // Ties count as half a win
+	Elo            float64 `json:"elo"`
+	// BradleyTerry is the order-independent maximum likelihood strength on the Elo scale
+	BradleyTerry float64 `json:"bradley_terry"`
+}
+
+// PairwiseLeaderboard ranks experiments by their Bradley-Terry rating.
+type PairwiseLeaderboard struct {
+	ScoreName string              `json:"score_name"`
+	Matches   int                 `json:"matches"`
+	Entries   []*LeaderboardEntry `json:"entries"`
+}
+
+// ComputePairwiseLeaderboard rates the contestants from their matches. Elo is
+// updated match by match in the given order; Bradley-Terry is fitted over all
+// matches at once, counting ties as half a win for each side.
+func ComputePairwiseLeaderboard(contestants []string, matches []PairwiseMatch) []*LeaderboardEntry {
+	entries := make(map[string]*LeaderboardEntry, len(contestants))
+	order := make([]string, 0, len(contestants))
+	add := func(id string) {
+		if _, ok := entries[id]; !ok {
+			entries[id] = &LeaderboardEntry{ExperimentID: id, Elo: EloInitialRating}
+			order = append(order, id)
+		}
+	}
+	for _, id := range contestants {
+		add(id)
+	}
+
+	for _, match := range matches {
+		if match.A == match.B || !match.Outcome.IsValid() {
+			continue
+		}
+		add(match.A)
+		add(match.B)
+		a, b := entries[match.A], entries[match.B]
+		a.Matches++
+		b.Matches++
+
+		var scoreA float64
+		switch match.Outcome {
+		case PairwiseOutcomeWin:
+			a.Wins++
+			b.Losses++
+			scoreA = 1
+		case PairwiseOutcomeLose:
+			a.Losses++
+			b.Wins++
+		default:
+			a.Ties++
+			b.Ties++
+			scoreA = 0.5
+		}
+
+		expectedA := 1 / (1 + math.Pow(10, (b.Elo-a.Elo)/400))
+		delta := EloKFactor * (scoreA - expectedA)
+		a.Elo += delta
+		b.Elo -= delta
+	}
+
+	strengths := bradleyTerry(order, matches)
+	result := make([]*LeaderboardEntry, 0, len(order))
+	for _, id := range order {
+		entry := entries[id]
+		if entry.Matches > 0 {
+			entry.WinRate = (float64(entry.Wins) + 0.5*float64(entry.Ties)) / float64(entry.Matches)
+		}
+		entry.BradleyTerry = EloInitialRating + 400*math.Log10(strengths[id])
+		result = append(result, entry)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].BradleyTerry != result[j].BradleyTerry {
+			return result[i].BradleyTerry > result[j].BradleyTerry
+		}
+		return result[i].Elo > result[j].Elo
+	})
+	for i, entry := range result {
+		entry.Rank = i + 1
+	}
+	return result
+}
+
+// bradleyTerry fits strengths with the MM algorithm (Hunter, 2004). Each
+// contestant also ties once with a virtual opponent of strength 1, which keeps
+// undefeated and winless contestants finite and anchors the scale at 1.
+func bradleyTerry(contestants []string, matches []PairwiseMatch) map[string]float64 {
+	index := make(map[string]int, len(contestants))
+	for i, id := range contestants {
+		index[id] = i
+	}
+
+	n := len(contestants)
+	wins := make([]float64, n)
+	games := make([][]float64, n)
+	for i := range games {
+		games[i] = make([]float64, n)
+		wins[i] = 0.5 // virtual tie
+	}
+	for _, match := range matches {
+		if match.A == match.B || !match.Outcome.IsValid() {
+			continue
+		}
+		a, b := index[match.A], index[match.B]
+		games[a][b]++
+		games[b][a]++
+		switch match.Outcome {
+		case PairwiseOutcomeWin:
+			wins[a]++
+		case PairwiseOutcomeLose:
+			wins[b]++
+		default:
+			wins[a] += 0.5
+			wins[b] += 0.5
+		}
+	}
+
+	strength := make([]float64, n)
+	for i := range strength {
+		strength[i] = 1
+	}
+	next := make([]float64, n)
+	for iter := 0; iter < bradleyTerryMaxIterations; iter++ {
+		maxChange := 0.0
+		for i := 0; i < n; i++ {
+			denominator := 1 / (strength[i] + 1) // virtual opponent
+			for j := 0; j < n; j++ {
+				if games[i][j] > 0 {
+					denominator += games[i][j] / (strength[i] + strength[j])
+				}
+			}
+			next[i] = wins[i] / denominator
+			maxChange = math.Max(maxChange, math.Abs(next[i]-strength[i])/strength[i])
+		}
+		copy(strength, next)
+		if maxChange < bradleyTerryTolerance {
+			break
+		}
+	}
+
+	result := make(map[string]float64, n)
+	for i, id := range contestants {
+		result[id] = strength[i]
+	}
+	return result
+}
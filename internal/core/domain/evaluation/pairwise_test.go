@@ -0,0 +1,119 @@
+package evaluation
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPairwiseTriggerRequest_Validate(t *testing.T) {
+	expA := "01HQXYZ0000000000000000000"
+	expB := "01HQXYZ0000000000000000001"
+
+	tests := []struct {
+		name  string
+		req   PairwiseTriggerRequest
+		field string
+	}{
+		{"two experiments", PairwiseTriggerRequest{ExperimentIDs: []string{expA, expB}}, ""},
+		{"span pair", PairwiseTriggerRequest{SpanPairs: []PairwiseSpanPair{{SpanA: "a1", SpanB: "b1"}}}, ""},
+		{"nothing to compare", PairwiseTriggerRequest{}, "experiment_ids"},
+		{"single experiment", PairwiseTriggerRequest{ExperimentIDs: []string{expA}}, "experiment_ids"},
+		{"duplicate experiment", PairwiseTriggerRequest{ExperimentIDs: []string{expA, expA}}, "experiment_ids"},
+		{"invalid experiment", PairwiseTriggerRequest{ExperimentIDs: []string{expA, "not-a-ulid"}}, "experiment_ids"},
+		{"both sources", PairwiseTriggerRequest{ExperimentIDs: []string{expA, expB}, SpanPairs: []PairwiseSpanPair{{SpanA: "a1", SpanB: "b1"}}}, "experiment_ids"},
+		{"span compared with itself", PairwiseTriggerRequest{SpanPairs: []PairwiseSpanPair{{SpanA: "a1", SpanB: "a1"}}}, "span_pairs[0]"},
+		{"span pair without b", PairwiseTriggerRequest{SpanPairs: []PairwiseSpanPair{{SpanA: "a1"}}}, "span_pairs[0]"},
+		{"negative sample limit", PairwiseTriggerRequest{ExperimentIDs: []string{expA, expB}, SampleLimit: -1}, "sample_limit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verr := tt.req.Validate()
+			if tt.field == "" {
+				assert.Nil(t, verr)
+				return
+			}
+			require.NotNil(t, verr)
+			assert.Equal(t, tt.field, verr.Field)
+		})
+	}
+}
+
+func TestPairwiseOutcome_Invert(t *testing.T) {
+	assert.Equal(t, PairwiseOutcomeLose, PairwiseOutcomeWin.Invert())
+	assert.Equal(t, PairwiseOutcomeWin, PairwiseOutcomeLose.Invert())
+	assert.Equal(t, PairwiseOutcomeTie, PairwiseOutcomeTie.Invert())
+	assert.False(t, PairwiseOutcome("draw").IsValid())
+}
+
+func TestComputePairwiseLeaderboard(t *testing.T) {
+	matches := []PairwiseMatch{
+		{A: "a", B: "b", Outcome: PairwiseOutcomeWin},
+		{A: "a", B: "b", Outcome: PairwiseOutcomeWin},
+		{A: "a", B: "b", Outcome: PairwiseOutcomeTie},
+		{A: "b", B: "c", Outcome: PairwiseOutcomeWin},
+		{A: "c", B: "a", Outcome: PairwiseOutcomeLose},
+		{A: "x", B: "x", Outcome: PairwiseOutcomeWin},   // ignored: self match
+		{A: "a", B: "c", Outcome: PairwiseOutcome("?")}, // ignored: invalid outcome
+	}
+
+	entries := ComputePairwiseLeaderboard([]string{"c", "b", "a"}, matches)
+	require.Len(t, entries, 3)
+
+	assert.Equal(t, []string{"a", "b", "c"}, []string{entries[0].ExperimentID, entries[1].ExperimentID, entries[2].ExperimentID})
+	for i, entry := range entries {
+		assert.Equal(t, i+1, entry.Rank)
+	}
+
+	a := entries[0]
+	assert.Equal(t, 4, a.Matches)
+	assert.Equal(t, 3, a.Wins)
+	assert.Equal(t, 0, a.Losses)
+	assert.Equal(t, 1, a.Ties)
+	assert.InDelta(t, 0.875, a.WinRate, 1e-9)
+
+	// Elo is zero-sum and Bradley-Terry stays finite for an undefeated contestant
+	total := 0.0
+	for _, entry := range entries {
+		total += entry.Elo
+		assert.False(t, math.IsInf(entry.BradleyTerry, 0) || math.IsNaN(entry.BradleyTerry))
+	}
+	assert.InDelta(t, 3*EloInitialRating, total, 1e-9)
+	assert.Greater(t, entries[0].BradleyTerry, entries[1].BradleyTerry)
+	assert.Greater(t, entries[1].BradleyTerry, entries[2].BradleyTerry)
+}
+
+func TestComputePairwiseLeaderboard_NoMatches(t *testing.T) {
+	entries := ComputePairwiseLeaderboard([]string{"a", "b"}, nil)
+	require.Len(t, entries, 2)
+	for _, entry := range entries {
+		assert.Equal(t, 0, entry.Matches)
+		assert.Equal(t, EloInitialRating, entry.Elo)
+		assert.InDelta(t, EloInitialRating, entry.BradleyTerry, 1e-9)
+	}
+}
+
+func TestComputePairwiseLeaderboard_BradleyTerryIgnoresOrder(t *testing.T) {
+	matches := []PairwiseMatch{
+		{A: "a", B: "b", Outcome: PairwiseOutcomeWin},
+		{A: "a", B: "b", Outcome: PairwiseOutcomeLose},
+		{A: "a", B: "b", Outcome: PairwiseOutcomeWin},
+	}
+	reversed := []PairwiseMatch{matches[2], matches[1], matches[0]}
+
+	byID := func(entries []*LeaderboardEntry) map[string]float64 {
+		result := make(map[string]float64)
+		for _, entry := range entries {
+			result[entry.ExperimentID] = entry.BradleyTerry
+		}
+		return result
+	}
+	first := byID(ComputePairwiseLeaderboard([]string{"a", "b"}, matches))
+	second := byID(ComputePairwiseLeaderboard([]string{"a", "b"}, reversed))
+	assert.InDelta(t, first["a"], second["a"], 1e-6)
+	assert.InDelta(t, first["b"], second["b"], 1e-6)
+	assert.Greater(t, first["a"], first["b"])
+}
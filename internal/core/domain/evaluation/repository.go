@@ -81,6 +81,11 @@ type ExperimentRepository interface {
 	IncrementCountersAndUpdateStatus(ctx context.Context, id, projectID ulid.ULID, completed, failed int) (bool, error)
 	// GetProgress gets minimal experiment data for progress polling
 	GetProgress(ctx context.Context, id, projectID ulid.ULID) (*Experiment, error)
+	// UpdateStatus writes only the status and run timestamps, leaving the counters
+	// to concurrent IncrementCountersAndUpdateStatus calls
+	UpdateStatus(ctx context.Context, experiment *Experiment, projectID ulid.ULID) error
+	// UpdateGates writes only the gates and the gate verdict
+	UpdateGates(ctx context.Context, experiment *Experiment, projectID ulid.ULID) error
 }
 
 type ExperimentItemRepository interface {
@@ -88,6 +93,10 @@ type ExperimentItemRepository interface {
 	CreateBatch(ctx context.Context, items []*ExperimentItem) error
 	List(ctx context.Context, experimentID ulid.ULID, limit, offset int) ([]*ExperimentItem, int64, error)
 	CountByExperiment(ctx context.Context, experimentID ulid.ULID) (int64, error)
+	// ListDatasetItemIDs returns the dataset items that already have a result in the experiment
+	ListDatasetItemIDs(ctx context.Context, experimentID ulid.ULID) ([]ulid.ULID, error)
+	// ListDatasetItemLinks returns the ID, experiment and dataset item of the experiments' items
+	ListDatasetItemLinks(ctx context.Context, experimentIDs []ulid.ULID) ([]*ExperimentItem, error)
 }
 
 // ExperimentConfigRepository handles persistence for experiment configurations created via the wizard.
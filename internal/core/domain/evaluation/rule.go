@@ -39,6 +39,10 @@ const (
 	ScorerTypeLLM     ScorerType = "llm"
 	ScorerTypeBuiltin ScorerType = "builtin"
 	ScorerTypeRegex   ScorerType = "regex"
+
+	// ScorerTypePairwise judges two outputs for the same input against each other.
+	// Pairwise evaluators are triggered on experiment or span pairs, never on span completion.
+	ScorerTypePairwise ScorerType = "pairwise"
 )
 
 // FilterClause represents a single filter condition for matching spans.
@@ -132,9 +136,13 @@ func (e *Evaluator) Validate() []ValidationError {
 	}
 
 	switch e.ScorerType {
-	case ScorerTypeLLM, ScorerTypeBuiltin, ScorerTypeRegex:
+	case ScorerTypeLLM, ScorerTypeBuiltin, ScorerTypeRegex, ScorerTypePairwise:
 	default:
-		errors = append(errors, ValidationError{Field: "scorer_type", Message: "invalid scorer type, must be llm, builtin, or regex"})
+		errors = append(errors, ValidationError{Field: "scorer_type", Message: "invalid scorer type, must be llm, builtin, regex, or pairwise"})
+	}
+
+	if e.ScorerType == ScorerTypePairwise && e.Status == EvaluatorStatusActive {
+		errors = append(errors, ValidationError{Field: "status", Message: "pairwise evaluators cannot be activated, trigger them on experiment or span pairs"})
 	}
 
 	if e.ScorerConfig == nil {
@@ -155,7 +163,7 @@ type CreateEvaluatorRequest struct {
 	Filter          []FilterClause   `json:"filter,omitempty"`
 	SpanNames       []string         `json:"span_names,omitempty"`
 	SamplingRate    *float64         `json:"sampling_rate,omitempty"`
-	ScorerType      ScorerType       `json:"scorer_type" binding:"required,oneof=llm builtin regex"`
+	ScorerType      ScorerType       `json:"scorer_type" binding:"required,oneof=llm builtin regex pairwise"`
 	ScorerConfig    map[string]any   `json:"scorer_config" binding:"required"`
 	VariableMapping []VariableMap    `json:"variable_mapping,omitempty"`
 }
@@ -169,7 +177,7 @@ type UpdateEvaluatorRequest struct {
 	Filter          []FilterClause   `json:"filter,omitempty"`
 	SpanNames       []string         `json:"span_names,omitempty"`
 	SamplingRate    *float64         `json:"sampling_rate,omitempty"`
-	ScorerType      *ScorerType      `json:"scorer_type,omitempty" binding:"omitempty,oneof=llm builtin regex"`
+	ScorerType      *ScorerType      `json:"scorer_type,omitempty" binding:"omitempty,oneof=llm builtin regex pairwise"`
 	ScorerConfig    map[string]any   `json:"scorer_config,omitempty"`
 	VariableMapping []VariableMap    `json:"variable_mapping,omitempty"`
 }
@@ -269,7 +277,7 @@ type LLMScorerConfig struct {
 // Builtin Scorer Configuration Types
 
 type BuiltinScorerConfig struct {
-	ScorerName string         `json:"scorer_name"` // contains, json_valid, length_check
+	ScorerName string         `json:"scorer_name"` // contains, json_valid, length_check, bleu, json_schema, tool_call, ...
 	Config     map[string]any `json:"config"`      // Scorer-specific configuration
 }
 
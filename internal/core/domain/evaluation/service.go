@@ -68,6 +68,9 @@ type ExperimentService interface {
 	// CompareExperiments compares score metrics across multiple experiments
 	CompareExperiments(ctx context.Context, projectID ulid.ULID, experimentIDs []ulid.ULID, baselineID *ulid.ULID) (*CompareExperimentsResponse, error)
 
+	// GetPairwiseLeaderboard ranks experiments by the pairwise judge scores between them
+	GetPairwiseLeaderboard(ctx context.Context, projectID ulid.ULID, req *PairwiseLeaderboardRequest) (*PairwiseLeaderboard, error)
+
 	// Rerun creates a new experiment based on an existing one, using the same dataset.
 	// The new experiment starts in pending status ready for SDK to run with a new task function.
 	Rerun(ctx context.Context, sourceID ulid.ULID, projectID ulid.ULID, req *RerunExperimentRequest) (*Experiment, error)
@@ -86,11 +89,20 @@ type ExperimentService interface {
 	// GetMetrics returns comprehensive metrics for an experiment including progress,
 	// performance, and score aggregations from ClickHouse.
 	GetMetrics(ctx context.Context, projectID, experimentID ulid.ULID) (*ExperimentMetricsResponse, error)
+
+	// SetGates replaces the CI gates of an experiment. A finished experiment is
+	// evaluated against the new gates right away.
+	SetGates(ctx context.Context, id ulid.ULID, projectID ulid.ULID, gates []ExperimentGate) (*Experiment, error)
+	// GetVerdict returns the pass/fail verdict of the experiment's gates, pending
+	// until the experiment finishes.
+	GetVerdict(ctx context.Context, id ulid.ULID, projectID ulid.ULID) (*GateVerdict, error)
 }
 
 type ExperimentItemService interface {
 	CreateBatch(ctx context.Context, experimentID ulid.ULID, projectID ulid.ULID, req *CreateExperimentItemsBatchRequest) (int, error)
 	List(ctx context.Context, experimentID ulid.ULID, projectID ulid.ULID, limit, offset int) ([]*ExperimentItem, int64, error)
+	// ListDatasetItemIDs returns the dataset items that already have a result in the experiment
+	ListDatasetItemIDs(ctx context.Context, experimentID ulid.ULID, projectID ulid.ULID) ([]ulid.ULID, error)
 }
 
 // ExperimentWizardService handles the creation and configuration of experiments via the dashboard wizard.
@@ -110,6 +122,14 @@ type ExperimentWizardService interface {
 
 	// GetExperimentConfig returns the config for a specific experiment.
 	GetExperimentConfig(ctx context.Context, experimentID ulid.ULID, projectID ulid.ULID) (*ExperimentConfig, error)
+
+	// CancelExperiment stops a pending or running wizard experiment. Items already
+	// executed are kept.
+	CancelExperiment(ctx context.Context, experimentID ulid.ULID, projectID ulid.ULID) (*Experiment, error)
+
+	// ResumeExperiment queues a pending, cancelled or failed wizard experiment for
+	// execution. Dataset items that already have a result are skipped.
+	ResumeExperiment(ctx context.Context, experimentID ulid.ULID, projectID ulid.ULID) (*Experiment, error)
 }
 
 type EvaluatorService interface {
@@ -125,6 +145,10 @@ type EvaluatorService interface {
 	// TriggerEvaluator starts a manual evaluation of the evaluator against matching spans
 	TriggerEvaluator(ctx context.Context, evaluatorID ulid.ULID, projectID ulid.ULID, opts *TriggerOptions) (*TriggerResponse, error)
 
+	// TriggerPairwise starts a pairwise evaluator on every pair of the experiments'
+	// outputs for shared dataset items, or on explicit span pairs
+	TriggerPairwise(ctx context.Context, evaluatorID ulid.ULID, projectID ulid.ULID, req *PairwiseTriggerRequest) (*TriggerResponse, error)
+
 	// TestEvaluator executes an evaluator against sample spans for testing/preview without persisting scores.
 	// This allows users to validate evaluator configuration before activation.
 	TestEvaluator(ctx context.Context, evaluatorID ulid.ULID, projectID ulid.ULID, req *TestEvaluatorRequest) (*TestEvaluatorResponse, error)
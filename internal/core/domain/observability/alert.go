@@ -0,0 +1,355 @@
+package observability
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"brokle/internal/core/domain/webhook"
+)
+
+// AlertState is the evaluation state of an alert rule.
+type AlertState string
+
+const (
+	AlertStateOK       AlertState = "ok"       // condition not met
+	AlertStatePending  AlertState = "pending"  // condition met, waiting for the pending period
+	AlertStateFiring   AlertState = "firing"   // condition met for at least the pending period
+	AlertStateResolved AlertState = "resolved" // condition cleared after firing
+)
+
+// AlertChannelType identifies a notification channel for alert deliveries.
+type AlertChannelType string
+
+const (
+	AlertChannelEmail   AlertChannelType = "email"
+	AlertChannelWebhook AlertChannelType = "webhook"
+	AlertChannelSlack   AlertChannelType = "slack"
+)
+
+// Alert rule limits
+const (
+	AlertRuleNameMaxLength     = 255
+	AlertRuleDescMaxLength     = 1000
+	AlertRuleMaxChannels       = 10
+	AlertRuleMinWindowMinutes  = 1
+	AlertRuleMaxWindowMinutes  = 7 * 24 * 60
+	AlertRuleMaxPendingMinutes = 24 * 60
+	AlertRuleMaxSilenceMinutes = 30 * 24 * 60
+	AlertHistoryDefaultLimit   = 50
+	AlertHistoryMaxLimit       = 500
+)
+
+// AlertChannel is a single notification target.
+// Target is an email address, a webhook URL, or a Slack incoming webhook URL.
+type AlertChannel struct {
+	Type   AlertChannelType `json:"type"`
+	Target string           `json:"target"`
+}
+
+// AlertChannels is a JSONB-backed list of notification channels.
+type AlertChannels []AlertChannel
+
+// Scan implements sql.Scanner for AlertChannels.
+func (c *AlertChannels) Scan(value interface{}) error {
+	if value == nil {
+		*c = nil
+		return nil
+	}
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return errors.New("unsupported type for alert channels")
+	}
+	return json.Unmarshal(data, c)
+}
+
+// Value implements driver.Valuer for AlertChannels.
+func (c AlertChannels) Value() (driver.Value, error) {
+	if c == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// AlertRule evaluates an aggregation over spans matched by a saved filter preset or a
+// filter expression, and fires when the value crosses a threshold.
+// Example: avg(has_error) > 0.05 over 10 minutes, quantile(0.95)(duration_ms) > 8000.
+type AlertRule struct {
+	ID              string         `json:"id" gorm:"primaryKey;column:id"`
+	ProjectID       string         `json:"project_id" gorm:"column:project_id;not null"`
+	Name            string         `json:"name" gorm:"column:name;not null"`
+	Description     *string        `json:"description,omitempty" gorm:"column:description"`
+	FilterPresetID  *string        `json:"filter_preset_id,omitempty" gorm:"column:filter_preset_id"` // Takes precedence over Filter
+	Filter          string         `json:"filter,omitempty" gorm:"column:filter;not null;default:''"` // Span query expression
+	Aggregation     string         `json:"aggregation" gorm:"column:aggregation;not null"`            // Span aggregate expression
+	Operator        FilterOperator `json:"operator" gorm:"column:operator;not null"`                  // >, <, >=, <=
+	Threshold       float64        `json:"threshold" gorm:"column:threshold;not null"`
+	WindowMinutes   int            `json:"window_minutes" gorm:"column:window_minutes;not null"`
+	PendingMinutes  int            `json:"pending_minutes" gorm:"column:pending_minutes;not null;default:0"`
+	Channels        AlertChannels  `json:"channels" gorm:"column:channels;type:jsonb;not null" swaggertype:"array,object"`
+	Enabled         bool           `json:"enabled" gorm:"column:enabled;not null;default:true"`
+	State           AlertState     `json:"state" gorm:"column:state;not null;default:ok"`
+	StateChangedAt  *time.Time     `json:"state_changed_at,omitempty" gorm:"column:state_changed_at"`
+	LastValue       *float64       `json:"last_value,omitempty" gorm:"column:last_value"`
+	LastEvaluatedAt *time.Time     `json:"last_evaluated_at,omitempty" gorm:"column:last_evaluated_at"`
+	SilencedUntil   *time.Time     `json:"silenced_until,omitempty" gorm:"column:silenced_until"`
+	SilenceReason   *string        `json:"silence_reason,omitempty" gorm:"column:silence_reason"`
+	WebhookSecret   string         `json:"-" gorm:"column:webhook_secret;not null;default:''"` // Signs webhook channel deliveries; only returned on create and rotate
+	CreatedBy       *string        `json:"created_by,omitempty" gorm:"column:created_by"`
+	CreatedAt       time.Time      `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt       time.Time      `json:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// TableName returns the database table name for AlertRule.
+func (AlertRule) TableName() string {
+	return "alert_rules"
+}
+
+// IsSilenced returns true while notifications for the rule are suppressed.
+func (r *AlertRule) IsSilenced(now time.Time) bool {
+	return r.SilencedUntil != nil && now.Before(*r.SilencedUntil)
+}
+
+// Breached returns true when the value crosses the rule threshold.
+// A missing value (no matching spans for avg/quantile) never breaches.
+func (r *AlertRule) Breached(value *float64) bool {
+	if value == nil {
+		return false
+	}
+	switch r.Operator {
+	case FilterOpGreaterThan:
+		return *value > r.Threshold
+	case FilterOpGreaterOrEqual:
+		return *value >= r.Threshold
+	case FilterOpLessThan:
+		return *value < r.Threshold
+	case FilterOpLessOrEqual:
+		return *value <= r.Threshold
+	}
+	return false
+}
+
+// NextAlertState computes the state after an evaluation. pendingSince is when the rule
+// entered its current state; the rule fires once the condition has held for pendingFor.
+// A resolved rule stays resolved until the condition is met again.
+func NextAlertState(current AlertState, breached bool, pendingSince *time.Time, pendingFor time.Duration, now time.Time) AlertState {
+	if !breached {
+		switch current {
+		case AlertStateFiring, AlertStateResolved:
+			return AlertStateResolved
+		default:
+			return AlertStateOK
+		}
+	}
+
+	switch current {
+	case AlertStateFiring:
+		return AlertStateFiring
+	case AlertStatePending:
+		if pendingSince == nil || now.Sub(*pendingSince) >= pendingFor {
+			return AlertStateFiring
+		}
+		return AlertStatePending
+	default:
+		if pendingFor <= 0 {
+			return AlertStateFiring
+		}
+		return AlertStatePending
+	}
+}
+
+// AlertEvent records a state transition of an alert rule and whether it was notified.
+type AlertEvent struct {
+	ID            string     `json:"id" gorm:"primaryKey;column:id"`
+	RuleID        string     `json:"rule_id" gorm:"column:rule_id;not null"`
+	ProjectID     string     `json:"project_id" gorm:"column:project_id;not null"`
+	State         AlertState `json:"state" gorm:"column:state;not null"`
+	PreviousState AlertState `json:"previous_state" gorm:"column:previous_state;not null"`
+	Value         *float64   `json:"value,omitempty" gorm:"column:value"`
+	Threshold     float64    `json:"threshold" gorm:"column:threshold;not null"`
+	Silenced      bool       `json:"silenced" gorm:"column:silenced;not null;default:false"`
+	Notified      bool       `json:"notified" gorm:"column:notified;not null;default:false"`
+	CreatedAt     time.Time  `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+}
+
+// TableName returns the database table name for AlertEvent.
+func (AlertEvent) TableName() string {
+	return "alert_events"
+}
+
+// AlertNotification is a transition to deliver to the rule's channels.
+type AlertNotification struct {
+	Rule  *AlertRule
+	Event *AlertEvent
+}
+
+// AlertEvaluationSummary reports the outcome of one evaluation pass.
+type AlertEvaluationSummary struct {
+	RulesEvaluated int                  `json:"rules_evaluated"`
+	RulesFailed    int                  `json:"rules_failed"`
+	Transitions    int                  `json:"transitions"`
+	Notifications  []*AlertNotification `json:"-"`
+}
+
+// CreateAlertRuleRequest represents the request to create an alert rule.
+type CreateAlertRuleRequest struct {
+	Name           string         `json:"name" validate:"required,min=1,max=255"`
+	Description    *string        `json:"description,omitempty" validate:"omitempty,max=1000"`
+	FilterPresetID *string        `json:"filter_preset_id,omitempty"`
+	Filter         string         `json:"filter,omitempty"`
+	Aggregation    string         `json:"aggregation" validate:"required"`
+	Operator       FilterOperator `json:"operator" validate:"required"`
+	Threshold      float64        `json:"threshold"`
+	WindowMinutes  int            `json:"window_minutes" validate:"required"`
+	PendingMinutes int            `json:"pending_minutes,omitempty"`
+	Channels       []AlertChannel `json:"channels"`
+	Enabled        *bool          `json:"enabled,omitempty"` // default true
+}
+
+// UpdateAlertRuleRequest represents the request to update an alert rule.
+// FilterPresetID set to an empty string detaches the preset.
+type UpdateAlertRuleRequest struct {
+	Name           *string         `json:"name,omitempty"`
+	Description    *string         `json:"description,omitempty"`
+	FilterPresetID *string         `json:"filter_preset_id,omitempty"`
+	Filter         *string         `json:"filter,omitempty"`
+	Aggregation    *string         `json:"aggregation,omitempty"`
+	Operator       *FilterOperator `json:"operator,omitempty"`
+	Threshold      *float64        `json:"threshold,omitempty"`
+	WindowMinutes  *int            `json:"window_minutes,omitempty"`
+	PendingMinutes *int            `json:"pending_minutes,omitempty"`
+	Channels       []AlertChannel  `json:"channels,omitempty"`
+	Enabled        *bool           `json:"enabled,omitempty"`
+}
+
+// SilenceAlertRuleRequest suppresses notifications for a rule for a duration.
+// The rule keeps evaluating and recording history while silenced.
+type SilenceAlertRuleRequest struct {
+	DurationMinutes int     `json:"duration_minutes" validate:"required"`
+	Reason          *string `json:"reason,omitempty"`
+}
+
+// AlertRuleRepository defines the interface for alert rule persistence.
+type AlertRuleRepository interface {
+	Create(ctx context.Context, rule *AlertRule) error
+	GetByID(ctx context.Context, id string) (*AlertRule, error)
+	Update(ctx context.Context, rule *AlertRule) error
+	Delete(ctx context.Context, id string) error
+	ListByProject(ctx context.Context, projectID string) ([]*AlertRule, error)
+	ListEnabled(ctx context.Context) ([]*AlertRule, error)
+	ExistsByName(ctx context.Context, projectID, name string, excludeID *string) (bool, error)
+	// UpdateEvaluation persists only the evaluation columns, so concurrent edits are kept.
+	UpdateEvaluation(ctx context.Context, rule *AlertRule) error
+}
+
+// AlertEventRepository defines the interface for alert history persistence.
+type AlertEventRepository interface {
+	Create(ctx context.Context, event *AlertEvent) error
+	ListByRule(ctx context.Context, ruleID string, limit, offset int) ([]*AlertEvent, int64, error)
+}
+
+// ValidateAlertRule validates a rule after a create or update request is applied.
+// Filter and aggregation syntax are checked by the service, which owns the parsers.
+func ValidateAlertRule(rule *AlertRule) []ValidationError {
+	var errs []ValidationError
+
+	if rule.Name == "" {
+		errs = append(errs, ValidationError{Field: "name", Message: "name is required"})
+	} else if len(rule.Name) > AlertRuleNameMaxLength {
+		errs = append(errs, ValidationError{Field: "name", Message: "name is too long"})
+	}
+
+	if rule.Description != nil && len(*rule.Description) > AlertRuleDescMaxLength {
+		errs = append(errs, ValidationError{Field: "description", Message: "description is too long"})
+	}
+
+	if len(rule.Filter) > SpanQueryMaxFilterLen {
+		errs = append(errs, ValidationError{Field: "filter", Message: "filter expression too long"})
+	}
+
+	if rule.Aggregation == "" {
+		errs = append(errs, ValidationError{Field: "aggregation", Message: "aggregation is required"})
+	}
+
+	if !rule.Operator.IsComparisonOperator() {
+		errs = append(errs, ValidationError{Field: "operator", Message: "operator must be one of >, <, >=, <="})
+	}
+
+	if rule.WindowMinutes < AlertRuleMinWindowMinutes || rule.WindowMinutes > AlertRuleMaxWindowMinutes {
+		errs = append(errs, ValidationError{Field: "window_minutes", Message: "window_minutes must be between 1 and 10080"})
+	}
+
+	if rule.PendingMinutes < 0 || rule.PendingMinutes > AlertRuleMaxPendingMinutes {
+		errs = append(errs, ValidationError{Field: "pending_minutes", Message: "pending_minutes must be between 0 and 1440"})
+	}
+
+	if len(rule.Channels) > AlertRuleMaxChannels {
+		errs = append(errs, ValidationError{Field: "channels", Message: "too many channels"})
+	}
+	for _, channel := range rule.Channels {
+		if msg := validateAlertChannel(channel); msg != "" {
+			errs = append(errs, ValidationError{Field: "channels", Message: msg})
+			break
+		}
+	}
+
+	return errs
+}
+
+func validateAlertChannel(channel AlertChannel) string {
+	if channel.Target == "" {
+		return "channel target is required"
+	}
+	switch channel.Type {
+	case AlertChannelEmail:
+		if _, err := mail.ParseAddress(channel.Target); err != nil {
+			return "email channel target must be an email address"
+		}
+	case AlertChannelWebhook:
+		u, err := url.Parse(channel.Target)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return "webhook channel target must be an http(s) URL"
+		}
+		if isInternalHost(u) {
+			return "webhook channel target must not point to an internal address"
+		}
+	case AlertChannelSlack:
+		// Rules post through their own incoming webhook; channel names would go through the platform's
+		u, err := url.Parse(channel.Target)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return "slack channel target must be an incoming webhook URL"
+		}
+		if isInternalHost(u) {
+			return "slack channel target must not point to an internal address"
+		}
+	default:
+		return "channel type must be email, webhook or slack"
+	}
+	return ""
+}
+
+// isInternalHost reports whether the URL names localhost or a non-public IP address.
+// Hostnames are resolved again when delivering, so DNS changes are caught there.
+func isInternalHost(u *url.URL) bool {
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	ip, err := netip.ParseAddr(host)
+	return err == nil && !webhook.IsPublicAddress(ip)
+}
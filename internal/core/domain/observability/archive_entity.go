@@ -13,6 +13,7 @@ type RawTelemetryRecord struct {
 	SpanID      string    `parquet:"span_id" json:"span_id"`
 	SpanJSONRaw string    `parquet:"span_json_raw" json:"span_json_raw"` // Full event as JSON (sufficient for replay)
 	ArchivedAt  time.Time `parquet:"archived_at,timestamp(microsecond)" json:"archived_at"`
+	EventType   string    `parquet:"event_type,optional" json:"event_type,omitempty"` // TelemetryEventType; empty in files archived before it was recorded
 }
 
 // SignalType constants for archive records
@@ -36,3 +37,30 @@ type ArchiveBatchResult struct {
 	FileSizeBytes int64     `json:"file_size_bytes"`
 	ArchivedAt    time.Time `json:"archived_at"`
 }
+
+// Rehydration range limits. The API restores synchronously within the request, so it
+// accepts short ranges only; longer ranges go through `migrate rehydrate`.
+const (
+	MaxRehydrateDays    = 366
+	MaxAPIRehydrateDays = 7
+)
+
+// RehydrateRequest selects archived Parquet files to restore into ClickHouse.
+type RehydrateRequest struct {
+	ProjectID string    `json:"-"`
+	Signals   []string  `json:"signals,omitempty"` // traces, metrics, logs, genai; empty restores all
+	From      time.Time `json:"from" binding:"required"`
+	To        time.Time `json:"to" binding:"required"`
+	DryRun    bool      `json:"dry_run"`
+}
+
+// RehydrateResult summarizes a rehydration run.
+type RehydrateResult struct {
+	DryRun            bool `json:"dry_run"`
+	FilesScanned      int  `json:"files_scanned"`
+	RecordsRead       int  `json:"records_read"`
+	RecordsRestored   int  `json:"records_restored"`
+	RecordsDuplicate  int  `json:"records_duplicate"` // Spans that still exist in ClickHouse
+	RecordsOutOfRange int  `json:"records_out_of_range"`
+	RecordsFailed     int  `json:"records_failed"`
+}
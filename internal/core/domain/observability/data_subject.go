@@ -0,0 +1,248 @@
+package observability
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+
+	"brokle/internal/core/domain/comment"
+	"brokle/internal/core/domain/evaluation"
+	"brokle/pkg/ulid"
+)
+
+// Data subject request bounds.
+const (
+	DataSubjectMaxUserIDLength = 256
+	DataSubjectMaxAttempts     = 3
+	DataSubjectLease           = time.Hour       // Renewed after every stage
+	DataSubjectRetryDelay      = 5 * time.Minute // Before a failed attempt is retried
+	DataSubjectMaxExportTraces = 5000            // Bundles list at most this many traces; the rest sets truncated
+	DataSubjectResponseDays    = 30              // GDPR Art. 12(3): requests are answered within one month
+)
+
+// DataSubjectRequestType is the GDPR right exercised by a request.
+type DataSubjectRequestType string
+
+const (
+	DataSubjectRequestErasure DataSubjectRequestType = "erasure" // Art. 17 right to erasure
+	DataSubjectRequestExport  DataSubjectRequestType = "export"  // Art. 15/20 right of access and portability
+)
+
+// IsValid returns true if the request type is known.
+func (t DataSubjectRequestType) IsValid() bool {
+	return t == DataSubjectRequestErasure || t == DataSubjectRequestExport
+}
+
+// DataSubjectRequestStatus tracks a request through the worker.
+type DataSubjectRequestStatus string
+
+const (
+	DataSubjectStatusPending   DataSubjectRequestStatus = "pending"
+	DataSubjectStatusRunning   DataSubjectRequestStatus = "running"
+	DataSubjectStatusCompleted DataSubjectRequestStatus = "completed"
+	DataSubjectStatusFailed    DataSubjectRequestStatus = "failed"
+)
+
+// DataSubjectStage is a step of request processing. Stages run in order and each
+// is recorded when it completes, so a request resumed after a crash skips them.
+type DataSubjectStage string
+
+const (
+	DataSubjectStageDiscover     DataSubjectStage = "discover"      // Find the user's traces in ClickHouse and the archive
+	DataSubjectStageComments     DataSubjectStage = "comments"      // Comments on those traces
+	DataSubjectStageDatasetItems DataSubjectStage = "dataset_items" // Dataset items sourced from those traces
+	DataSubjectStageArchive      DataSubjectStage = "archive"       // Archived Parquet records of those traces
+	DataSubjectStageTelemetry    DataSubjectStage = "telemetry"     // Spans, scores, logs and GenAI events in ClickHouse
+)
+
+// DataSubjectStages returns the stages in processing order. ClickHouse goes last
+// because it is where traces are discovered: erasing it earlier would hide the
+// traces from a retried request.
+func DataSubjectStages() []DataSubjectStage {
+	return []DataSubjectStage{
+		DataSubjectStageDiscover,
+		DataSubjectStageComments,
+		DataSubjectStageDatasetItems,
+		DataSubjectStageArchive,
+		DataSubjectStageTelemetry,
+	}
+}
+
+// DataSubjectCounts counts the records erased or exported for a data subject.
+type DataSubjectCounts struct {
+	Traces         int    `json:"traces"`
+	Spans          uint64 `json:"spans"`
+	Scores         uint64 `json:"scores"`
+	Logs           uint64 `json:"logs"`
+	GenAIEvents    uint64 `json:"genai_events"`
+	Comments       int64  `json:"comments"`
+	DatasetItems   int64  `json:"dataset_items"`
+	ArchiveFiles   int    `json:"archive_files"` // Parquet objects rewritten or deleted (erasure) or read (export)
+	ArchiveRecords int    `json:"archive_records"`
+}
+
+// DataSubjectProgress is the persisted progress of a request.
+type DataSubjectProgress struct {
+	CompletedStages     []DataSubjectStage `json:"completed_stages"`
+	CurrentStage        DataSubjectStage   `json:"current_stage,omitempty"`
+	ArchiveFilesScanned int                `json:"archive_files_scanned"`
+	Counts              DataSubjectCounts  `json:"counts"`
+}
+
+// Completed returns true if the stage already ran.
+func (p *DataSubjectProgress) Completed(stage DataSubjectStage) bool {
+	for _, done := range p.CompletedStages {
+		if done == stage {
+			return true
+		}
+	}
+	return false
+}
+
+// Percent returns the share of stages completed.
+func (p *DataSubjectProgress) Percent() float64 {
+	return float64(len(p.CompletedStages)) / float64(len(DataSubjectStages())) * 100
+}
+
+// DataSubjectCertificate is issued when a request completes. Digest is the hex
+// SHA-256 of the certificate's JSON encoding with an empty digest, so a copy handed
+// to the data subject can be checked against the stored one.
+type DataSubjectCertificate struct {
+	RequestID      ulid.ULID              `json:"request_id"`
+	ProjectID      ulid.ULID              `json:"project_id"`
+	UserID         string                 `json:"user_id"`
+	Type           DataSubjectRequestType `json:"type"`
+	RequestedAt    time.Time              `json:"requested_at"`
+	CompletedAt    time.Time              `json:"completed_at"`
+	Counts         DataSubjectCounts      `json:"counts"`
+	ArchiveScanned bool                   `json:"archive_scanned"` // False when no archive storage is configured
+	Truncated      bool                   `json:"truncated,omitempty"`
+	Digest         string                 `json:"digest"`
+}
+
+// ComputeDigest returns the digest of the certificate's other fields.
+func (c DataSubjectCertificate) ComputeDigest() string {
+	c.Digest = ""
+	raw, _ := json.Marshal(c) // Plain values always encode
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// DataSubjectRequest asks for everything stored about one end user (the user.id
+// span attribute) in a project to be erased or exported. Requests are processed
+// asynchronously by the worker.
+type DataSubjectRequest struct {
+	ID             ulid.ULID                `json:"id" gorm:"column:id;type:char(26);primaryKey"`
+	ProjectID      ulid.ULID                `json:"project_id" gorm:"column:project_id;type:char(26);not null"`
+	UserID         string                   `json:"user_id" gorm:"column:user_id;size:256;not null"`
+	Type           DataSubjectRequestType   `json:"type" gorm:"column:type;size:20;not null"`
+	Status         DataSubjectRequestStatus `json:"status" gorm:"column:status;size:20;not null;default:'pending'"`
+	Progress       DataSubjectProgress      `json:"progress" gorm:"column:progress;type:jsonb;serializer:json;not null"`
+	Certificate    *DataSubjectCertificate  `json:"certificate,omitempty" gorm:"column:certificate;type:jsonb;serializer:json"`
+	TraceIDs       pq.StringArray           `json:"-" gorm:"column:trace_ids;type:text[]"` // Found by the discover stage
+	Bundle         []byte                   `json:"-" gorm:"column:bundle;type:bytea"`     // Gzipped export bundle
+	Error          *string                  `json:"error,omitempty" gorm:"column:error"`
+	Attempts       int                      `json:"attempts" gorm:"column:attempts;not null;default:0"`
+	RequestedBy    *ulid.ULID               `json:"requested_by,omitempty" gorm:"column:requested_by;type:char(26)"`
+	LeaseExpiresAt *time.Time               `json:"-" gorm:"column:lease_expires_at"`
+	StartedAt      *time.Time               `json:"started_at,omitempty" gorm:"column:started_at"`
+	CompletedAt    *time.Time               `json:"completed_at,omitempty" gorm:"column:completed_at"`
+	CreatedAt      time.Time                `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt      time.Time                `json:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// TableName returns the database table name for DataSubjectRequest.
+func (DataSubjectRequest) TableName() string {
+	return "data_subject_requests"
+}
+
+// DueBy returns the date the request must be answered by.
+func (r *DataSubjectRequest) DueBy() time.Time {
+	return r.CreatedAt.AddDate(0, 0, DataSubjectResponseDays)
+}
+
+// CreateDataSubjectRequest starts an erasure or export for an end user.
+type CreateDataSubjectRequest struct {
+	UserID string `json:"user_id" binding:"required" example:"customer-42"`
+}
+
+// DataSubjectRequestResponse adds derived progress fields to a request.
+type DataSubjectRequestResponse struct {
+	*DataSubjectRequest
+	ProgressPct float64   `json:"progress_pct"`
+	DueBy       time.Time `json:"due_by"`
+}
+
+// ToResponse creates a response with derived fields.
+func (r *DataSubjectRequest) ToResponse() *DataSubjectRequestResponse {
+	return &DataSubjectRequestResponse{
+		DataSubjectRequest: r,
+		ProgressPct:        r.Progress.Percent(),
+		DueBy:              r.DueBy(),
+	}
+}
+
+// DataSubjectArchivedRecord is an archived record included in an export bundle.
+type DataSubjectArchivedRecord struct {
+	SignalType string    `json:"signal_type"`
+	EventType  string    `json:"event_type,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
+	TraceID    string    `json:"trace_id"`
+	SpanID     string    `json:"span_id,omitempty"`
+	Payload    string    `json:"payload"` // The archived event as JSON
+}
+
+// DataSubjectExport is the JSON bundle produced by an export request.
+type DataSubjectExport struct {
+	RequestID       ulid.ULID                    `json:"request_id"`
+	ProjectID       ulid.ULID                    `json:"project_id"`
+	UserID          string                       `json:"user_id"`
+	GeneratedAt     time.Time                    `json:"generated_at"`
+	TraceIDs        []string                     `json:"trace_ids"`
+	Spans           []*Span                      `json:"spans"`
+	Scores          []*Score                     `json:"scores"`
+	Logs            []*Log                       `json:"logs"`
+	Comments        []*comment.Comment           `json:"comments"`
+	DatasetItems    []*evaluation.DatasetItem    `json:"dataset_items"`
+	ArchivedRecords []*DataSubjectArchivedRecord `json:"archived_records"`
+	Truncated       bool                         `json:"truncated"`
+}
+
+// DataSubjectRequestRepository persists data subject requests.
+type DataSubjectRequestRepository interface {
+	Create(ctx context.Context, req *DataSubjectRequest) error
+	// GetByID omits the export bundle.
+	GetByID(ctx context.Context, id, projectID ulid.ULID) (*DataSubjectRequest, error)
+	ListByProject(ctx context.Context, projectID ulid.ULID, limit, offset int) ([]*DataSubjectRequest, int64, error)
+	GetBundle(ctx context.Context, id, projectID ulid.ULID) ([]byte, error)
+	Update(ctx context.Context, req *DataSubjectRequest) error
+	// ClaimNext leases the oldest pending request, or a running one whose lease
+	// expired. It returns gorm.ErrRecordNotFound when there is none.
+	ClaimNext(ctx context.Context, now time.Time, lease time.Duration) (*DataSubjectRequest, error)
+	// CountOverdueErasures counts erasure requests created before the cutoff that
+	// have not completed, ignoring users whose data a later erasure did remove.
+	CountOverdueErasures(ctx context.Context, createdBefore time.Time) (int64, error)
+}
+
+// DataSubjectTelemetryRepository finds and erases an end user's telemetry in ClickHouse.
+type DataSubjectTelemetryRepository interface {
+	ListTraceIDs(ctx context.Context, projectID, userID string) ([]string, error)
+	ListLogs(ctx context.Context, projectID string, traceIDs []string) ([]*Log, error)
+	// Count and Delete cover the traces' spans, scores and logs, and GenAI events
+	// of the traces or the user.
+	Count(ctx context.Context, projectID, userID string, traceIDs []string) (*DataSubjectCounts, error)
+	Delete(ctx context.Context, projectID, userID string, traceIDs []string) error
+}
+
+// DataSubjectRecordRepository finds and erases PostgreSQL records derived from traces.
+type DataSubjectRecordRepository interface {
+	ListComments(ctx context.Context, projectID ulid.ULID, traceIDs []string) ([]*comment.Comment, error)
+	// DeleteComments hard-deletes the comments with their replies and reactions.
+	DeleteComments(ctx context.Context, projectID ulid.ULID, traceIDs []string) (int64, error)
+	ListDatasetItems(ctx context.Context, projectID ulid.ULID, traceIDs []string) ([]*evaluation.DatasetItem, error)
+	DeleteDatasetItems(ctx context.Context, projectID ulid.ULID, traceIDs []string) (int64, error)
+}
@@ -107,6 +107,7 @@ type Span struct {
 	SpanKind   uint8    `json:"span_kind" db:"span_kind"`
 	Tags       []string `json:"tags,omitempty" db:"tags"`
 	Bookmarked bool     `json:"bookmarked,omitempty" db:"bookmarked"`
+	SampleRate float64  `json:"sample_rate,omitempty" db:"sample_rate"` // Probability the span was kept with by sampling
 }
 
 // Score represents a quality evaluation score linked to traces and spans
@@ -469,6 +470,14 @@ func (s *Span) CalculateDuration() {
 	}
 }
 
+// GetSampleRate returns the recorded sample rate, treating unset as unsampled.
+func (s *Span) GetSampleRate() float64 {
+	if s.SampleRate <= 0 || s.SampleRate > 1 {
+		return 1
+	}
+	return s.SampleRate
+}
+
 func (s *Span) GetTotalCost() decimal.Decimal {
 	if s.TotalCost != nil {
 		return *s.TotalCost
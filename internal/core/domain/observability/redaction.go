@@ -0,0 +1,154 @@
+package observability
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"regexp"
+	"time"
+
+	"github.com/lib/pq"
+
+	"brokle/pkg/pii"
+	"brokle/pkg/ulid"
+)
+
+// Redaction policy bounds enforced on updates.
+const (
+	RedactionMaxPatterns      = 20
+	RedactionMaxPatternLength = 500
+	RedactionMaxAttributeKeys = 100
+)
+
+// RedactionCountAttribute is the span attribute recording how many values were redacted.
+const RedactionCountAttribute = "brokle.redaction.count"
+
+// RedactionPolicy configures PII redaction of a project's spans at OTLP ingestion.
+//
+// Detectors and custom patterns scan every string attribute (including gen_ai
+// input/output messages) of the span, its events, links, resource and scope.
+// DenyAttributes are redacted whole; AllowAttributes are never scanned.
+type RedactionPolicy struct {
+	ID              ulid.ULID      `json:"id" gorm:"column:id;type:char(26);primaryKey"`
+	ProjectID       ulid.ULID      `json:"project_id" gorm:"column:project_id;type:char(26);not null"`
+	Enabled         bool           `json:"enabled" gorm:"column:enabled;not null;default:false"`
+	Mode            pii.Mode       `json:"mode" gorm:"column:mode;not null;default:'mask'" swaggertype:"string" enums:"mask,hash,drop"`
+	Detectors       pq.StringArray `json:"detectors" gorm:"column:detectors;type:text[]" swaggertype:"array,string"`
+	CustomPatterns  []pii.Pattern  `json:"custom_patterns" gorm:"column:custom_patterns;type:jsonb;serializer:json;default:'[]'"`
+	AllowAttributes pq.StringArray `json:"allow_attributes" gorm:"column:allow_attributes;type:text[]" swaggertype:"array,string"` // Trailing * matches a prefix
+	DenyAttributes  pq.StringArray `json:"deny_attributes" gorm:"column:deny_attributes;type:text[]" swaggertype:"array,string"`   // Trailing * matches a prefix
+	CreatedAt       time.Time      `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt       time.Time      `json:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// TableName returns the database table name for RedactionPolicy.
+func (RedactionPolicy) TableName() string {
+	return "redaction_policies"
+}
+
+// DefaultRedactionPolicy returns the policy in effect for projects without one: store spans verbatim.
+func DefaultRedactionPolicy(projectID ulid.ULID) *RedactionPolicy {
+	return &RedactionPolicy{
+		ProjectID:       projectID,
+		Mode:            pii.ModeMask,
+		Detectors:       AllRedactionDetectors(),
+		CustomPatterns:  []pii.Pattern{},
+		AllowAttributes: pq.StringArray{},
+		DenyAttributes:  pq.StringArray{},
+	}
+}
+
+// Redacts returns true if the policy changes ingested spans.
+func (p *RedactionPolicy) Redacts() bool {
+	return p != nil && p.Enabled
+}
+
+// RedactorConfig returns the redactor configuration of the policy. Hashes are keyed
+// by HMAC(secret, project ID), so hashed values cannot be correlated across projects
+// or recomputed by anyone who only knows the project ID.
+func (p *RedactionPolicy) RedactorConfig(secret []byte) pii.Config {
+	detectors := make([]pii.Detector, len(p.Detectors))
+	for i, detector := range p.Detectors {
+		detectors[i] = pii.Detector(detector)
+	}
+	return pii.Config{
+		Mode:      p.Mode,
+		Detectors: detectors,
+		Patterns:  p.CustomPatterns,
+		Allow:     p.AllowAttributes,
+		Deny:      p.DenyAttributes,
+		HashKey:   redactionHashKey(secret, p.ProjectID),
+	}
+}
+
+func redactionHashKey(secret []byte, projectID ulid.ULID) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("redaction:"))
+	mac.Write([]byte(projectID.String()))
+	return mac.Sum(nil)
+}
+
+// AllRedactionDetectors returns the names of every built-in detector.
+func AllRedactionDetectors() pq.StringArray {
+	detectors := make(pq.StringArray, len(pii.Detectors))
+	for i, detector := range pii.Detectors {
+		detectors[i] = string(detector)
+	}
+	return detectors
+}
+
+// UpdateRedactionPolicyRequest replaces a project's redaction policy.
+// Omitted detectors enable every built-in detector; an empty list disables them all.
+type UpdateRedactionPolicyRequest struct {
+	Enabled         bool          `json:"enabled"`
+	Mode            *pii.Mode     `json:"mode,omitempty" swaggertype:"string" enums:"mask,hash,drop" example:"mask"`
+	Detectors       []string      `json:"detectors,omitempty" example:"email,phone,credit_card"`
+	CustomPatterns  []pii.Pattern `json:"custom_patterns,omitempty"`
+	AllowAttributes []string      `json:"allow_attributes,omitempty" example:"service.name"`
+	DenyAttributes  []string      `json:"deny_attributes,omitempty" example:"enduser.*"`
+}
+
+// ValidateRedactionPolicy validates a policy after an update request is applied.
+func ValidateRedactionPolicy(policy *RedactionPolicy) []ValidationError {
+	var errs []ValidationError
+
+	if !policy.Mode.IsValid() {
+		errs = append(errs, ValidationError{Field: "mode", Message: "mode must be mask, hash or drop"})
+	}
+	for _, detector := range policy.Detectors {
+		if !pii.Detector(detector).IsValid() {
+			errs = append(errs, ValidationError{Field: "detectors", Message: "unknown detector " + detector})
+		}
+	}
+	if len(policy.CustomPatterns) > RedactionMaxPatterns {
+		errs = append(errs, ValidationError{Field: "custom_patterns", Message: "too many custom patterns"})
+	}
+	for _, pattern := range policy.CustomPatterns {
+		if pattern.Name == "" {
+			errs = append(errs, ValidationError{Field: "custom_patterns", Message: "custom patterns need a name"})
+			continue
+		}
+		if len(pattern.Pattern) == 0 || len(pattern.Pattern) > RedactionMaxPatternLength {
+			errs = append(errs, ValidationError{Field: "custom_patterns", Message: "pattern " + pattern.Name + " must be 1 to 500 characters"})
+			continue
+		}
+		if _, err := regexp.Compile(pattern.Pattern); err != nil {
+			errs = append(errs, ValidationError{Field: "custom_patterns", Message: "pattern " + pattern.Name + ": " + err.Error()})
+		}
+	}
+	if len(policy.AllowAttributes) > RedactionMaxAttributeKeys {
+		errs = append(errs, ValidationError{Field: "allow_attributes", Message: "too many allowed attributes"})
+	}
+	if len(policy.DenyAttributes) > RedactionMaxAttributeKeys {
+		errs = append(errs, ValidationError{Field: "deny_attributes", Message: "too many denied attributes"})
+	}
+
+	return errs
+}
+
+// RedactionPolicyRepository defines the interface for redaction policy persistence.
+type RedactionPolicyRepository interface {
+	GetByProject(ctx context.Context, projectID ulid.ULID) (*RedactionPolicy, error)
+	Upsert(ctx context.Context, policy *RedactionPolicy) error
+	DeleteByProject(ctx context.Context, projectID ulid.ULID) error
+}
@@ -11,6 +11,8 @@ import (
 type TraceRepository interface {
 	InsertSpan(ctx context.Context, span *Span) error
 	InsertSpanBatch(ctx context.Context, spans []*Span) error
+	// GetExistingSpanIDs returns which of the given span IDs are already stored for the project.
+	GetExistingSpanIDs(ctx context.Context, projectID string, spanIDs []string) (map[string]struct{}, error)
 	DeleteSpan(ctx context.Context, spanID string) error
 	GetSpan(ctx context.Context, spanID string) (*Span, error)
 	GetSpansByTraceID(ctx context.Context, traceID string) ([]*Span, error)
@@ -46,6 +48,11 @@ type TraceRepository interface {
 
 	QuerySpansByExpression(ctx context.Context, query string, args []interface{}) ([]*Span, error)
 	CountSpansByExpression(ctx context.Context, query string, args []interface{}) (int64, error)
+	// QueryArchivedSpansByExpression runs a query over archived Parquet files that selects span_json_raw.
+	QueryArchivedSpansByExpression(ctx context.Context, query string, args []interface{}) ([]*Span, error)
+	// AggregateSpansByExpression runs an aggregation query built by SpanQueryBuilder.BuildAggregateQuery
+	// and keys group and aggregate columns by groupKeys and valueKeys.
+	AggregateSpansByExpression(ctx context.Context, query string, args []interface{}, bucketed bool, groupKeys, valueKeys []string) ([]*SpanAggregateRow, error)
 
 	// DiscoverAttributes extracts unique attribute keys from span_attributes and resource_attributes.
 	// Returns attribute keys with occurrence counts, useful for populating filter UI autocomplete.
@@ -79,6 +86,9 @@ type ScoreRepository interface {
 
 	// Returns: scoreName -> experimentID -> aggregation
 	GetAggregationsByExperiments(ctx context.Context, projectID string, experimentIDs []string) (map[string]map[string]*ScoreAggregation, error)
+
+	// Returns the scores of every experiment item, for paired comparisons and pairwise leaderboards
+	GetItemScoresByExperiments(ctx context.Context, projectID string, experimentIDs []string) ([]*Score, error)
 }
 
 type ScoreAggregation struct {
@@ -222,6 +232,11 @@ type TraceFilter struct {
 	SearchType *string  // Search type: "id", "content", "all"
 	Statuses   []string // Status filter: "ok", "error", "unset" (inclusion)
 	StatusesNot []string // Status exclusion filter: "ok", "error", "unset"
+
+	// Span query filter expression, including trace predicates (HAS, COUNT, DESCENDANT, CHILD, SIBLING)
+	Filter          *string
+	FilterCondition string        // Compiled from Filter by the service; a trace_id condition
+	FilterArgs      []interface{} // Arguments for FilterCondition
 }
 
 type SpanFilter struct {
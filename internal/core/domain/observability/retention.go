@@ -0,0 +1,183 @@
+package observability
+
+import (
+	"context"
+	"time"
+
+	"brokle/pkg/ulid"
+)
+
+// Retention bounds enforced on policy updates.
+const (
+	MinRetentionDays = 1
+	MaxRetentionDays = 3650
+)
+
+// RetentionSignal identifies a telemetry signal with its own retention period.
+type RetentionSignal string
+
+const (
+	RetentionSignalSpans       RetentionSignal = "spans"
+	RetentionSignalLogs        RetentionSignal = "logs"
+	RetentionSignalMetrics     RetentionSignal = "metrics"
+	RetentionSignalScores      RetentionSignal = "scores"
+	RetentionSignalGenAIEvents RetentionSignal = "genai_events"
+)
+
+// AllRetentionSignals returns every signal covered by retention policies.
+func AllRetentionSignals() []RetentionSignal {
+	return []RetentionSignal{
+		RetentionSignalSpans,
+		RetentionSignalLogs,
+		RetentionSignalMetrics,
+		RetentionSignalScores,
+		RetentionSignalGenAIEvents,
+	}
+}
+
+// RetentionSource describes where an effective retention period came from.
+type RetentionSource string
+
+const (
+	RetentionSourceProject      RetentionSource = "project"
+	RetentionSourceOrganization RetentionSource = "organization"
+	RetentionSourceDefault      RetentionSource = "default"
+)
+
+// RetentionPolicy stores retention days per signal for an organization (ProjectID nil)
+// or a single project. Nil day values inherit from the next level up.
+type RetentionPolicy struct {
+	ID              ulid.ULID  `json:"id" gorm:"column:id;type:char(26);primaryKey"`
+	OrganizationID  ulid.ULID  `json:"organization_id" gorm:"column:organization_id;type:char(26);not null"`
+	ProjectID       *ulid.ULID `json:"project_id,omitempty" gorm:"column:project_id;type:char(26)"` // nil for organization default
+	SpansDays       *int       `json:"spans_days,omitempty" gorm:"column:spans_days"`
+	LogsDays        *int       `json:"logs_days,omitempty" gorm:"column:logs_days"`
+	MetricsDays     *int       `json:"metrics_days,omitempty" gorm:"column:metrics_days"`
+	ScoresDays      *int       `json:"scores_days,omitempty" gorm:"column:scores_days"`
+	GenAIEventsDays *int       `json:"genai_events_days,omitempty" gorm:"column:genai_events_days"`
+	CreatedAt       time.Time  `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt       time.Time  `json:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// TableName returns the database table name for RetentionPolicy.
+func (RetentionPolicy) TableName() string {
+	return "data_retention_policies"
+}
+
+// DaysFor returns the configured days for a signal, or nil when inherited.
+func (p *RetentionPolicy) DaysFor(signal RetentionSignal) *int {
+	if p == nil {
+		return nil
+	}
+	switch signal {
+	case RetentionSignalSpans:
+		return p.SpansDays
+	case RetentionSignalLogs:
+		return p.LogsDays
+	case RetentionSignalMetrics:
+		return p.MetricsDays
+	case RetentionSignalScores:
+		return p.ScoresDays
+	case RetentionSignalGenAIEvents:
+		return p.GenAIEventsDays
+	}
+	return nil
+}
+
+// UpdateRetentionPolicyRequest replaces the retention days of a policy.
+// Omitted or null fields inherit from the organization or system default.
+type UpdateRetentionPolicyRequest struct {
+	SpansDays       *int `json:"spans_days,omitempty" example:"30"`
+	LogsDays        *int `json:"logs_days,omitempty" example:"30"`
+	MetricsDays     *int `json:"metrics_days,omitempty" example:"730"`
+	ScoresDays      *int `json:"scores_days,omitempty" example:"730"`
+	GenAIEventsDays *int `json:"genai_events_days,omitempty" example:"30"`
+}
+
+// Validate returns the first field outside the allowed range, or "" when valid.
+func (r *UpdateRetentionPolicyRequest) Validate() string {
+	fields := []struct {
+		name string
+		days *int
+	}{
+		{"spans_days", r.SpansDays},
+		{"logs_days", r.LogsDays},
+		{"metrics_days", r.MetricsDays},
+		{"scores_days", r.ScoresDays},
+		{"genai_events_days", r.GenAIEventsDays},
+	}
+	for _, f := range fields {
+		if f.days != nil && (*f.days < MinRetentionDays || *f.days > MaxRetentionDays) {
+			return f.name
+		}
+	}
+	return ""
+}
+
+// SignalRetention is the resolved retention for one signal.
+type SignalRetention struct {
+	Signal RetentionSignal `json:"signal"`
+	Days   int             `json:"days"`
+	Source RetentionSource `json:"source"`
+}
+
+// EffectiveRetention is the resolved retention for an organization or project.
+type EffectiveRetention struct {
+	OrganizationID string             `json:"organization_id"`
+	ProjectID      string             `json:"project_id,omitempty"`
+	Policy         *RetentionPolicy   `json:"policy,omitempty"` // Policy stored at this level, if any
+	Signals        []*SignalRetention `json:"signals"`
+}
+
+// DaysFor returns the resolved days for a signal.
+func (e *EffectiveRetention) DaysFor(signal RetentionSignal) int {
+	for _, s := range e.Signals {
+		if s.Signal == signal {
+			return s.Days
+		}
+	}
+	return 0
+}
+
+// RetentionPurgeEstimate reports rows older than a policy's cutoff for one signal.
+type RetentionPurgeEstimate struct {
+	ProjectID     string          `json:"project_id"`
+	Signal        RetentionSignal `json:"signal"`
+	RetentionDays int             `json:"retention_days"`
+	Source        RetentionSource `json:"source"`
+	Cutoff        time.Time       `json:"cutoff"`
+	Rows          uint64          `json:"rows"`
+}
+
+// RetentionPurgeResult summarizes a purge of one signal for one project.
+type RetentionPurgeResult struct {
+	PartitionsDropped int `json:"partitions_dropped"`
+	DeleteMutations   int `json:"delete_mutations"`
+}
+
+// RetentionPolicyRepository defines the interface for retention policy persistence.
+type RetentionPolicyRepository interface {
+	GetByOrganization(ctx context.Context, orgID ulid.ULID) (*RetentionPolicy, error)
+	GetByProject(ctx context.Context, projectID ulid.ULID) (*RetentionPolicy, error)
+	List(ctx context.Context) ([]*RetentionPolicy, error)
+	Upsert(ctx context.Context, policy *RetentionPolicy) error
+	DeleteByProject(ctx context.Context, projectID ulid.ULID) error
+}
+
+// RetentionPurgeRepository purges expired telemetry from ClickHouse.
+// A signal may span several tables (metrics); counts are summed across them.
+type RetentionPurgeRepository interface {
+	ListProjectsWithDataBefore(ctx context.Context, signal RetentionSignal, cutoff time.Time) ([]string, error)
+	CountBefore(ctx context.Context, signal RetentionSignal, projectID string, cutoff time.Time) (uint64, error)
+	PurgeBefore(ctx context.Context, signal RetentionSignal, projectID string, cutoff time.Time) (*RetentionPurgeResult, error)
+}
+
+// RetentionRunSummary summarizes one enforcement pass of the retention worker.
+type RetentionRunSummary struct {
+	DryRun            bool                      `json:"dry_run"`
+	ProjectsChecked   int                       `json:"projects_checked"`
+	RowsExpired       uint64                    `json:"rows_expired"`
+	PartitionsDropped int                       `json:"partitions_dropped"`
+	DeleteMutations   int                       `json:"delete_mutations"`
+	Estimates         []*RetentionPurgeEstimate `json:"estimates,omitempty"`
+}
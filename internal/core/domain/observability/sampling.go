@@ -0,0 +1,264 @@
+package observability
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
+
+	"brokle/pkg/sampling"
+	"brokle/pkg/ulid"
+)
+
+// Sampling policy bounds enforced on updates.
+const (
+	SamplingDefaultDecisionWaitSeconds = 30
+	SamplingMaxDecisionWaitSeconds     = 300
+	SamplingMaxKeepTags                = 20
+)
+
+// TraceTagsAttribute is the span attribute SDKs use to tag a trace.
+// The value is a JSON array of strings or a comma-separated list.
+const TraceTagsAttribute = "brokle.trace.tags"
+
+// Salts keep head and tail sampling decisions independent for the same trace ID.
+const (
+	headSamplingSalt = "head"
+	tailSamplingSalt = "tail"
+)
+
+// SamplingPolicy configures trace sampling for a project.
+//
+// Head sampling keeps HeadSampleRate of traces at OTLP ingestion, keyed on the trace ID.
+// Tail sampling buffers the remaining spans of each trace for DecisionWaitSeconds, then
+// keeps the trace if any keep rule matches, or with probability TailSampleRate otherwise.
+// Kept spans record the product of the rates applied so aggregates can be re-weighted.
+type SamplingPolicy struct {
+	ID                  ulid.ULID        `json:"id" gorm:"column:id;type:char(26);primaryKey"`
+	ProjectID           ulid.ULID        `json:"project_id" gorm:"column:project_id;type:char(26);not null"`
+	HeadSampleRate      float64          `json:"head_sample_rate" gorm:"column:head_sample_rate;not null;default:1"`
+	TailEnabled         bool             `json:"tail_enabled" gorm:"column:tail_enabled;not null;default:false"`
+	DecisionWaitSeconds int              `json:"decision_wait_seconds" gorm:"column:decision_wait_seconds;not null;default:30"`
+	TailSampleRate      float64          `json:"tail_sample_rate" gorm:"column:tail_sample_rate;not null;default:1"` // For traces matching no keep rule
+	KeepErrors          bool             `json:"keep_errors" gorm:"column:keep_errors;not null"`
+	KeepMinCost         *decimal.Decimal `json:"keep_min_cost,omitempty" gorm:"column:keep_min_cost;type:decimal(18,6)"`
+	KeepMinDurationMs   *int64           `json:"keep_min_duration_ms,omitempty" gorm:"column:keep_min_duration_ms"`
+	KeepTags            pq.StringArray   `json:"keep_tags" gorm:"column:keep_tags;type:text[]" swaggertype:"array,string"`
+	KeepFilter          string           `json:"keep_filter,omitempty" gorm:"column:keep_filter;not null;default:''"` // Span query expression
+	CreatedAt           time.Time        `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt           time.Time        `json:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// TableName returns the database table name for SamplingPolicy.
+func (SamplingPolicy) TableName() string {
+	return "sampling_policies"
+}
+
+// DefaultSamplingPolicy returns the policy in effect for projects without one: keep everything.
+func DefaultSamplingPolicy(projectID ulid.ULID) *SamplingPolicy {
+	return &SamplingPolicy{
+		ProjectID:           projectID,
+		HeadSampleRate:      1,
+		DecisionWaitSeconds: SamplingDefaultDecisionWaitSeconds,
+		TailSampleRate:      1,
+		KeepErrors:          true,
+		KeepTags:            pq.StringArray{},
+	}
+}
+
+// HeadSamples returns true if head sampling drops any traces.
+func (p *SamplingPolicy) HeadSamples() bool {
+	return p != nil && p.HeadSampleRate < 1
+}
+
+// TailSamples returns true if spans must be buffered for a tail decision.
+func (p *SamplingPolicy) TailSamples() bool {
+	return p != nil && p.TailEnabled
+}
+
+// KeepHead decides head sampling for a trace.
+func (p *SamplingPolicy) KeepHead(traceID string) bool {
+	if !p.HeadSamples() {
+		return true
+	}
+	return sampling.KeepTrace(traceID, headSamplingSalt, p.HeadSampleRate)
+}
+
+// DecisionWait returns how long spans of a trace are buffered before the tail decision.
+func (p *SamplingPolicy) DecisionWait() time.Duration {
+	seconds := p.DecisionWaitSeconds
+	if seconds <= 0 {
+		seconds = SamplingDefaultDecisionWaitSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// TailDecision is the outcome of tail sampling for one trace.
+type TailDecision struct {
+	Keep       bool    `json:"keep"`
+	SampleRate float64 `json:"sample_rate"`      // Probability the trace was kept with; 1 when a rule matched
+	Reason     string  `json:"reason,omitempty"` // Keep rule that matched: error, cost, duration, tag, filter, sampled
+}
+
+// DecideTail applies the keep rules to a buffered trace. matchesFilter evaluates
+// KeepFilter against the spans and is only called when a filter is set.
+func (p *SamplingPolicy) DecideTail(traceID string, spans []*Span, matchesFilter func([]*Span) bool) *TailDecision {
+	if reason := p.keepReason(spans, matchesFilter); reason != "" {
+		return &TailDecision{Keep: true, SampleRate: 1, Reason: reason}
+	}
+
+	if p.TailSampleRate >= 1 || sampling.KeepTrace(traceID, tailSamplingSalt, p.TailSampleRate) {
+		return &TailDecision{Keep: true, SampleRate: math.Min(p.TailSampleRate, 1), Reason: "sampled"}
+	}
+	return &TailDecision{Keep: false, SampleRate: p.TailSampleRate}
+}
+
+func (p *SamplingPolicy) keepReason(spans []*Span, matchesFilter func([]*Span) bool) string {
+	var (
+		totalCost decimal.Decimal
+		start     time.Time
+		end       time.Time
+	)
+
+	for _, span := range spans {
+		if p.KeepErrors && (span.HasError || span.StatusCode == 2) {
+			return "error"
+		}
+		if span.TotalCost != nil {
+			totalCost = totalCost.Add(*span.TotalCost)
+		}
+		if start.IsZero() || span.StartTime.Before(start) {
+			start = span.StartTime
+		}
+		if span.EndTime != nil && span.EndTime.After(end) {
+			end = *span.EndTime
+		}
+		if len(p.KeepTags) > 0 && hasAnyTag(span, p.KeepTags) {
+			return "tag"
+		}
+	}
+
+	if p.KeepMinCost != nil && totalCost.GreaterThanOrEqual(*p.KeepMinCost) {
+		return "cost"
+	}
+	if p.KeepMinDurationMs != nil && !end.IsZero() && end.Sub(start).Milliseconds() >= *p.KeepMinDurationMs {
+		return "duration"
+	}
+	if p.KeepFilter != "" && matchesFilter != nil && matchesFilter(spans) {
+		return "filter"
+	}
+	return ""
+}
+
+// SpanTags returns the platform tags and SDK trace tags of a span.
+func SpanTags(span *Span) []string {
+	tags := append([]string{}, span.Tags...)
+
+	raw := strings.TrimSpace(span.SpanAttributes[TraceTagsAttribute])
+	if raw == "" {
+		return tags
+	}
+
+	var list []string
+	if strings.HasPrefix(raw, "[") && json.Unmarshal([]byte(raw), &list) == nil {
+		return append(tags, list...)
+	}
+	for _, tag := range strings.Split(raw, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func hasAnyTag(span *Span, want []string) bool {
+	for _, tag := range SpanTags(span) {
+		for _, w := range want {
+			if tag == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// UpdateSamplingPolicyRequest replaces a project's sampling policy.
+type UpdateSamplingPolicyRequest struct {
+	HeadSampleRate      *float64         `json:"head_sample_rate,omitempty" example:"0.25"`
+	TailEnabled         bool             `json:"tail_enabled"`
+	DecisionWaitSeconds *int             `json:"decision_wait_seconds,omitempty" example:"30"`
+	TailSampleRate      *float64         `json:"tail_sample_rate,omitempty" example:"0.1"`
+	KeepErrors          *bool            `json:"keep_errors,omitempty"`
+	KeepMinCost         *decimal.Decimal `json:"keep_min_cost,omitempty" swaggertype:"number"`
+	KeepMinDurationMs   *int64           `json:"keep_min_duration_ms,omitempty" example:"10000"`
+	KeepTags            []string         `json:"keep_tags,omitempty"`
+	KeepFilter          string           `json:"keep_filter,omitempty" example:"gen_ai.request.model=\"gpt-4o\""`
+}
+
+// ValidateSamplingPolicy validates a policy after an update request is applied.
+// Filter syntax is checked by the service, which owns the parser.
+func ValidateSamplingPolicy(policy *SamplingPolicy) []ValidationError {
+	var errs []ValidationError
+
+	if policy.HeadSampleRate <= 0 || policy.HeadSampleRate > 1 {
+		errs = append(errs, ValidationError{Field: "head_sample_rate", Message: "head_sample_rate must be greater than 0 and at most 1"})
+	}
+	if policy.TailSampleRate <= 0 || policy.TailSampleRate > 1 {
+		errs = append(errs, ValidationError{Field: "tail_sample_rate", Message: "tail_sample_rate must be greater than 0 and at most 1"})
+	}
+	if policy.DecisionWaitSeconds < 1 || policy.DecisionWaitSeconds > SamplingMaxDecisionWaitSeconds {
+		errs = append(errs, ValidationError{Field: "decision_wait_seconds", Message: "decision_wait_seconds must be between 1 and 300"})
+	}
+	if policy.KeepMinCost != nil && policy.KeepMinCost.IsNegative() {
+		errs = append(errs, ValidationError{Field: "keep_min_cost", Message: "keep_min_cost must not be negative"})
+	}
+	if policy.KeepMinDurationMs != nil && *policy.KeepMinDurationMs < 0 {
+		errs = append(errs, ValidationError{Field: "keep_min_duration_ms", Message: "keep_min_duration_ms must not be negative"})
+	}
+	if len(policy.KeepTags) > SamplingMaxKeepTags {
+		errs = append(errs, ValidationError{Field: "keep_tags", Message: "too many keep tags"})
+	}
+	if len(policy.KeepFilter) > SpanQueryMaxFilterLen {
+		errs = append(errs, ValidationError{Field: "keep_filter", Message: "filter expression too long"})
+	}
+
+	return errs
+}
+
+// SamplingPolicyRepository defines the interface for sampling policy persistence.
+type SamplingPolicyRepository interface {
+	GetByProject(ctx context.Context, projectID ulid.ULID) (*SamplingPolicy, error)
+	Upsert(ctx context.Context, policy *SamplingPolicy) error
+	DeleteByProject(ctx context.Context, projectID ulid.ULID) error
+}
+
+// BufferedSpan is a converted span event held for a tail sampling decision.
+type BufferedSpan struct {
+	OrganizationID ulid.ULID      `json:"organization_id"`
+	EventID        ulid.ULID      `json:"event_id"`
+	Payload        map[string]any `json:"payload"`
+}
+
+// BufferedTrace is a trace whose decision wait has elapsed.
+type BufferedTrace struct {
+	ProjectID ulid.ULID
+	TraceID   string
+	Spans     []*BufferedSpan
+}
+
+// TailSamplingBuffer holds spans of undecided traces and the decisions made for them (Redis).
+type TailSamplingBuffer interface {
+	// Add appends spans to a trace's buffer. The decision time is set by the first call for a trace.
+	Add(ctx context.Context, projectID ulid.ULID, traceID string, spans []*BufferedSpan, decideAt time.Time) error
+	// ClaimDue removes and returns up to limit traces whose decision time has passed.
+	// A trace is claimed by exactly one caller.
+	ClaimDue(ctx context.Context, now time.Time, limit int) ([]*BufferedTrace, error)
+	// SetDecision records a decision so late spans of the trace skip buffering.
+	SetDecision(ctx context.Context, projectID ulid.ULID, traceID string, decision *TailDecision, ttl time.Duration) error
+	// GetDecisions returns recorded decisions keyed by trace ID; undecided traces are absent.
+	GetDecisions(ctx context.Context, projectID ulid.ULID, traceIDs []string) (map[string]*TailDecision, error)
+}
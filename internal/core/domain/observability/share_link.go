@@ -0,0 +1,110 @@
+package observability
+
+import (
+	"context"
+	"time"
+
+	"brokle/pkg/ulid"
+)
+
+// ShareResourceType identifies what a share link exposes.
+type ShareResourceType string
+
+const (
+	ShareResourceTrace   ShareResourceType = "trace"
+	ShareResourceSession ShareResourceType = "session"
+)
+
+// Share link limits
+const (
+	ShareLinkMaxExpiryHours   = 90 * 24
+	ShareLinkMaxSessionTraces = 100
+	// ShareRedactedValue replaces span inputs and outputs on redacted links.
+	ShareRedactedValue = "[REDACTED]"
+)
+
+// TraceShareLink grants read-only access to a single trace or session without login.
+// The link token is signed with the server secret, so it is not stored; revoking the
+// link or letting it expire invalidates every copy of the URL.
+type TraceShareLink struct {
+	ID            ulid.ULID         `json:"id" gorm:"column:id;type:char(26);primaryKey"`
+	ProjectID     ulid.ULID         `json:"project_id" gorm:"column:project_id;type:char(26);not null"`
+	ResourceType  ShareResourceType `json:"resource_type" gorm:"column:resource_type;size:20;not null"`
+	ResourceID    string            `json:"resource_id" gorm:"column:resource_id;size:255;not null"` // Trace ID or session ID
+	RedactInputs  bool              `json:"redact_inputs" gorm:"column:redact_inputs;not null;default:false"`
+	RedactOutputs bool              `json:"redact_outputs" gorm:"column:redact_outputs;not null;default:false"`
+	ExpiresAt     *time.Time        `json:"expires_at,omitempty" gorm:"column:expires_at"`
+	RevokedAt     *time.Time        `json:"revoked_at,omitempty" gorm:"column:revoked_at"`
+	CreatedBy     ulid.ULID         `json:"created_by" gorm:"column:created_by;type:char(26);not null"`
+	ViewCount     int64             `json:"view_count" gorm:"column:view_count;not null;default:0"`
+	LastViewedAt  *time.Time        `json:"last_viewed_at,omitempty" gorm:"column:last_viewed_at"`
+	CreatedAt     time.Time         `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt     time.Time         `json:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// TableName returns the database table name for TraceShareLink.
+func (TraceShareLink) TableName() string {
+	return "trace_share_links"
+}
+
+// IsActive returns true if the link is neither revoked nor expired.
+func (l *TraceShareLink) IsActive(now time.Time) bool {
+	if l.RevokedAt != nil {
+		return false
+	}
+	return l.ExpiresAt == nil || now.Before(*l.ExpiresAt)
+}
+
+// Redacts returns true if span inputs or outputs are hidden from viewers.
+func (l *TraceShareLink) Redacts() bool {
+	return l.RedactInputs || l.RedactOutputs
+}
+
+// CreateShareLinkRequest is the request to share a trace or session.
+type CreateShareLinkRequest struct {
+	ResourceType   ShareResourceType `json:"resource_type" binding:"required,oneof=trace session"`
+	ResourceID     string            `json:"resource_id" binding:"required"`
+	ExpiresInHours *int              `json:"expires_in_hours,omitempty" binding:"omitempty,min=1"` // Omit for a link that never expires
+	RedactInputs   bool              `json:"redact_inputs"`
+	RedactOutputs  bool              `json:"redact_outputs"`
+}
+
+// ShareLinkResponse is a share link with its public URL.
+type ShareLinkResponse struct {
+	*TraceShareLink
+	Token  string `json:"token"`
+	URL    string `json:"url,omitempty"` // Empty when the server has no APP_URL
+	Active bool   `json:"active"`
+}
+
+// SharedTrace is a trace as rendered on a share link.
+type SharedTrace struct {
+	Trace *TraceSummary `json:"trace"`
+	Spans []*Span       `json:"spans"`
+}
+
+// SharedView is the read-only payload served to share link viewers.
+type SharedView struct {
+	ResourceType  ShareResourceType `json:"resource_type"`
+	ResourceID    string            `json:"resource_id"`
+	ExpiresAt     *time.Time        `json:"expires_at,omitempty"`
+	RedactInputs  bool              `json:"redact_inputs"`
+	RedactOutputs bool              `json:"redact_outputs"`
+	Traces        []*SharedTrace    `json:"traces"`
+}
+
+// ShareViewer describes an anonymous share link viewer for the audit log.
+type ShareViewer struct {
+	IPAddress string
+	UserAgent string
+}
+
+// TraceShareLinkRepository defines the interface for share link data access.
+type TraceShareLinkRepository interface {
+	Create(ctx context.Context, link *TraceShareLink) error
+	GetByID(ctx context.Context, id ulid.ULID) (*TraceShareLink, error)
+	ListByProject(ctx context.Context, projectID ulid.ULID) ([]*TraceShareLink, error)
+	Revoke(ctx context.Context, id ulid.ULID, at time.Time) error
+	// RecordView increments the view counter atomically.
+	RecordView(ctx context.Context, id ulid.ULID, at time.Time) error
+}
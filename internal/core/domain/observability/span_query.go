@@ -2,6 +2,8 @@ package observability
 
 import (
 	"errors"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -13,6 +15,22 @@ type SpanQueryRequest struct {
 	EndTime   *time.Time `json:"end_time,omitempty"`
 	Limit int `json:"limit,omitempty"` // default 100, max 10000
 	Page  int `json:"page,omitempty"`  // 1-indexed page number
+	Source SpanQuerySource `json:"source,omitempty"` // hot (default) or archive
+}
+
+// SpanQuerySource selects where a span query reads from.
+type SpanQuerySource string
+
+const (
+	// SpanQuerySourceHot queries spans stored in ClickHouse.
+	SpanQuerySourceHot SpanQuerySource = "hot"
+	// SpanQuerySourceArchive queries archived Parquet files in S3 via the ClickHouse s3() table function.
+	SpanQuerySourceArchive SpanQuerySource = "archive"
+)
+
+// IsValid returns true for known sources; empty means hot.
+func (s SpanQuerySource) IsValid() bool {
+	return s == "" || s == SpanQuerySourceHot || s == SpanQuerySourceArchive
 }
 
 // SpanQueryResponse represents the response containing queried spans.
@@ -28,6 +46,10 @@ const (
 	SpanQueryMaxLimit     = 10000
 	SpanQueryMaxClauses   = 20
 	SpanQueryMaxFilterLen = 2000
+	// SpanQueryMaxArchiveDays bounds the time range of archive queries, which scan S3 files.
+	SpanQueryMaxArchiveDays = 366
+	// SpanQueryMaxTraceDepth bounds how many ancestor levels a DESCENDANT predicate walks.
+	SpanQueryMaxTraceDepth = 32
 )
 
 // SpanSelectFields defines the columns selected when querying spans.
@@ -74,6 +96,55 @@ type ConditionNode struct {
 
 func (c *ConditionNode) isFilterNode() {}
 
+// TraceNode represents a trace-level structural predicate. It matches every span
+// of a trace whose span tree satisfies the relation.
+// Examples: HAS(span.type=tool), COUNT(has_error=true) >= 2, DESCENDANT(span_name=agent, span.type=tool)
+type TraceNode struct {
+	Relation TraceRelation
+	Left     FilterNode     // Span predicate (the counted span, ancestor, parent, or first sibling)
+	Right    FilterNode     // Related span predicate; nil for HAS and COUNT
+	Operator FilterOperator // Comparison operator for COUNT
+	Count    int64          // Comparison value for COUNT
+}
+
+func (t *TraceNode) isFilterNode() {}
+
+// TraceRelation represents a structural relation between spans of the same trace.
+type TraceRelation string
+
+const (
+	TraceRelationHas        TraceRelation = "HAS"        // some span matches
+	TraceRelationCount      TraceRelation = "COUNT"      // number of matching spans compares to a value
+	TraceRelationDescendant TraceRelation = "DESCENDANT" // a span matching Right is nested anywhere below a span matching Left
+	TraceRelationChild      TraceRelation = "CHILD"      // a span matching Right is a direct child of a span matching Left
+	TraceRelationSibling    TraceRelation = "SIBLING"    // distinct spans matching Left and Right share a parent
+)
+
+// IsBinary returns true for relations that compare two span predicates.
+func (r TraceRelation) IsBinary() bool {
+	return r == TraceRelationDescendant || r == TraceRelationChild || r == TraceRelationSibling
+}
+
+// ParseTraceRelation returns the relation for a function name, case-insensitively.
+func ParseTraceRelation(name string) (TraceRelation, bool) {
+	switch r := TraceRelation(strings.ToUpper(name)); r {
+	case TraceRelationHas, TraceRelationCount, TraceRelationDescendant, TraceRelationChild, TraceRelationSibling:
+		return r, true
+	}
+	return "", false
+}
+
+// HasTracePredicate returns true if the filter tree contains a trace predicate.
+func HasTracePredicate(node FilterNode) bool {
+	switch n := node.(type) {
+	case *BinaryNode:
+		return HasTracePredicate(n.Left) || HasTracePredicate(n.Right)
+	case *TraceNode:
+		return true
+	}
+	return false
+}
+
 // LogicOperator represents logical operators for combining conditions.
 type LogicOperator string
 
@@ -268,6 +339,17 @@ func ValidateSpanQueryRequest(req *SpanQueryRequest) []ValidationError {
 		}
 	}
 
+	// Source validation: archive queries must be bounded to limit the files scanned
+	if !req.Source.IsValid() {
+		errs = append(errs, ValidationError{Field: "source", Message: "source must be hot or archive"})
+	} else if req.Source == SpanQuerySourceArchive {
+		if req.StartTime == nil || req.EndTime == nil {
+			errs = append(errs, ValidationError{Field: "start_time", Message: "start_time and end_time are required for archive queries"})
+		} else if req.EndTime.Sub(*req.StartTime) > time.Duration(SpanQueryMaxArchiveDays)*24*time.Hour {
+			errs = append(errs, ValidationError{Field: "end_time", Message: "archive query range exceeds maximum allowed"})
+		}
+	}
+
 	return errs
 }
 
@@ -394,3 +476,166 @@ func NormalizeAttributeDiscoveryRequest(req *AttributeDiscoveryRequest) {
 		req.Sources = []AttributeSource{AttributeSourceSpan, AttributeSourceResource}
 	}
 }
+
+// AggregateFunction is an aggregate supported by span aggregation queries.
+type AggregateFunction string
+
+const (
+	AggregateCount    AggregateFunction = "count"
+	AggregateSum      AggregateFunction = "sum"
+	AggregateAvg      AggregateFunction = "avg"
+	AggregateMin      AggregateFunction = "min"
+	AggregateMax      AggregateFunction = "max"
+	AggregateUniq     AggregateFunction = "uniq"
+	AggregateQuantile AggregateFunction = "quantile"
+)
+
+// SpanAggregation is a parsed aggregation expression such as quantile(0.95)(duration).
+type SpanAggregation struct {
+	Expression string // Original expression, used as the result key
+	Function   AggregateFunction
+	Field      string  // Empty for count()
+	Quantile   float64 // Only for quantile
+}
+
+// AggregatableColumns maps numeric span fields to ClickHouse expressions.
+// Other fields resolve like filter fields and are converted with toFloat64OrNull.
+var AggregatableColumns = map[string]string{
+	"duration":      "duration_nano",
+	"duration_nano": "duration_nano",
+	"duration_ms":   "duration_nano / 1000000",
+	"total_cost":    "total_cost",
+	"tokens.input":  "usage_details['input']",
+	"tokens.output": "usage_details['output']",
+	"tokens.total":  "usage_details['total']",
+	"has_error":     "toUInt8(has_error)", // avg(has_error) is the error rate
+}
+
+// SpanAggregateRequest is an SDK request for aggregating spans matched by a filter expression.
+type SpanAggregateRequest struct {
+	Filter       string     `json:"filter,omitempty"`       // Optional; empty aggregates all spans
+	Aggregations []string   `json:"aggregations"`           // e.g. count(), sum(total_cost), quantile(0.95)(duration)
+	GroupBy      []string   `json:"group_by,omitempty"`     // e.g. service_name, model_name, gen_ai.system
+	Interval     string     `json:"interval,omitempty"`     // Time bucket: 1m, 5m, 1h, 1d, 1w
+	StartTime    *time.Time `json:"start_time,omitempty"`
+	EndTime      *time.Time `json:"end_time,omitempty"`
+	Limit        int        `json:"limit,omitempty"` // default 1000, max 10000 rows
+}
+
+// SpanAggregateRow is one result row: a time bucket and group combination with its values.
+// Values are nil when the aggregate has no numeric input (e.g. avg over missing attributes).
+type SpanAggregateRow struct {
+	Bucket *time.Time          `json:"bucket,omitempty"`
+	Groups map[string]string   `json:"groups,omitempty"`
+	Values map[string]*float64 `json:"values"`
+}
+
+// SpanAggregateResponse contains aggregation results ordered by bucket, then first aggregation.
+type SpanAggregateResponse struct {
+	Rows      []*SpanAggregateRow `json:"rows"`
+	Truncated bool                `json:"truncated"` // More rows than limit were available
+}
+
+// Aggregation request defaults and limits
+const (
+	SpanAggregateDefaultLimit     = 1000
+	SpanAggregateMaxLimit         = 10000
+	SpanAggregateMaxFunctions     = 10
+	SpanAggregateMaxGroupBy       = 5
+	SpanAggregateMinInterval      = time.Minute
+	SpanAggregateMaxExpressionLen = 200
+)
+
+// ValidateSpanAggregateRequest validates the aggregation request parameters.
+func ValidateSpanAggregateRequest(req *SpanAggregateRequest) []ValidationError {
+	var errs []ValidationError
+
+	if len(req.Filter) > SpanQueryMaxFilterLen {
+		errs = append(errs, ValidationError{Field: "filter", Message: "filter expression too long"})
+	}
+
+	if len(req.Aggregations) == 0 {
+		errs = append(errs, ValidationError{Field: "aggregations", Message: "at least one aggregation is required"})
+	} else if len(req.Aggregations) > SpanAggregateMaxFunctions {
+		errs = append(errs, ValidationError{Field: "aggregations", Message: "too many aggregations"})
+	}
+
+	if len(req.GroupBy) > SpanAggregateMaxGroupBy {
+		errs = append(errs, ValidationError{Field: "group_by", Message: "too many group by fields"})
+	}
+
+	// Results are keyed by expression and field, so duplicates would collide
+	if hasDuplicates(req.Aggregations) {
+		errs = append(errs, ValidationError{Field: "aggregations", Message: "duplicate aggregation"})
+	}
+	if hasDuplicates(req.GroupBy) {
+		errs = append(errs, ValidationError{Field: "group_by", Message: "duplicate group by field"})
+	}
+
+	if req.Limit < 0 {
+		errs = append(errs, ValidationError{Field: "limit", Message: "limit must be non-negative"})
+	} else if req.Limit > SpanAggregateMaxLimit {
+		errs = append(errs, ValidationError{Field: "limit", Message: "limit exceeds maximum allowed"})
+	}
+
+	if req.Interval != "" {
+		if interval, err := ParseAggregateInterval(req.Interval); err != nil {
+			errs = append(errs, ValidationError{Field: "interval", Message: "interval must be a duration such as 5m, 1h or 1d"})
+		} else if interval < SpanAggregateMinInterval {
+			errs = append(errs, ValidationError{Field: "interval", Message: "interval must be at least 1m"})
+		}
+	}
+
+	if req.StartTime != nil && req.EndTime != nil && req.EndTime.Before(*req.StartTime) {
+		errs = append(errs, ValidationError{Field: "end_time", Message: "end_time must be after start_time"})
+	}
+
+	return errs
+}
+
+func hasDuplicates(values []string) bool {
+	seen := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		if _, ok := seen[v]; ok {
+			return true
+		}
+		seen[v] = struct{}{}
+	}
+	return false
+}
+
+// NormalizeSpanAggregateRequest applies defaults to the request.
+func NormalizeSpanAggregateRequest(req *SpanAggregateRequest) {
+	if req.Limit == 0 {
+		req.Limit = SpanAggregateDefaultLimit
+	}
+	if req.Limit > SpanAggregateMaxLimit {
+		req.Limit = SpanAggregateMaxLimit
+	}
+}
+
+// ParseAggregateInterval parses a time bucket interval. Besides Go durations (5m, 1h)
+// it accepts whole days and weeks (1d, 1w).
+func ParseAggregateInterval(interval string) (time.Duration, error) {
+	if n := len(interval); n > 1 {
+		unit := time.Duration(0)
+		switch interval[n-1] {
+		case 'd':
+			unit = 24 * time.Hour
+		case 'w':
+			unit = 7 * 24 * time.Hour
+		}
+		if unit > 0 {
+			count, err := strconv.Atoi(interval[:n-1])
+			if err != nil || count <= 0 {
+				return 0, ErrInvalidValue
+			}
+			return time.Duration(count) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(interval)
+	if err != nil || d <= 0 {
+		return 0, ErrInvalidValue
+	}
+	return d, nil
+}
@@ -309,6 +309,12 @@ func (os *OrganizationSettings) SetValue(value interface{}) error {
 	return nil
 }
 
+// Well-known organization setting keys
+const (
+	SettingRequireMFA           = "security.require_mfa"            // bool; members must use a second factor to log in
+	SettingAssignedProjectsOnly = "security.assigned_projects_only" // bool; members without a project role get no access to that project
+)
+
 // OrganizationWithProjectsAndRole represents an organization with its projects and the user's role
 type OrganizationWithProjectsAndRole struct {
 	Organization *Organization
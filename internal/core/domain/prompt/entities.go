@@ -312,6 +312,7 @@ type ExecutePromptResponse struct {
 	Response       *LLMResponse           `json:"response,omitempty"`
 	LatencyMs      int64                  `json:"latency_ms"`
 	Error          string                 `json:"error,omitempty"`
+	RateLimited    bool                   `json:"rate_limited,omitempty"` // provider answered 429; safe to retry
 }
 
 type LLMResponse struct {
@@ -45,6 +45,9 @@ var (
 	ErrExecutionFailed    = errors.New("prompt execution failed")
 	ErrProviderNotFound   = errors.New("LLM provider not found")
 	ErrInvalidModelConfig = errors.New("invalid model configuration")
+
+	// ErrProviderRateLimited is returned when the provider answers 429; the call can be retried after a backoff
+	ErrProviderRateLimited = errors.New("LLM provider rate limit exceeded")
 )
 
 // Error codes for structured API responses
@@ -100,6 +103,10 @@ func NewExecutionFailedError(details string) error {
 	return fmt.Errorf("%w: %s", ErrExecutionFailed, details)
 }
 
+func NewProviderRateLimitedError(provider, details string) error {
+	return fmt.Errorf("%w: %s: %s", ErrProviderRateLimited, provider, details)
+}
+
 func NewUnsupportedDialectError(dialect string) error {
 	return fmt.Errorf("%w: %s", ErrUnsupportedDialect, dialect)
 }
@@ -141,6 +148,10 @@ func IsConflictError(err error) bool {
 		errors.Is(err, ErrLabelAlreadyExists)
 }
 
+func IsRateLimitError(err error) bool {
+	return errors.Is(err, ErrProviderRateLimited)
+}
+
 func IsForbiddenError(err error) bool {
 	return errors.Is(err, ErrLabelProtected) ||
 		errors.Is(err, ErrLatestLabelReserved) ||
@@ -0,0 +1,331 @@
+// Package webhook provides domain entities for outbound webhook subscriptions.
+// Projects register HTTPS endpoints that receive signed platform events
+// (traces, scores, experiments, annotations, prompt labels and budget alerts).
+package webhook
+
+import (
+	"encoding/json"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"brokle/pkg/ulid"
+)
+
+// ============================================================================
+// Event Types
+// ============================================================================
+
+// EventType identifies a platform event that can be delivered to a subscription.
+type EventType string
+
+const (
+	EventTraceCreated            EventType = "trace.created"
+	EventScoreCreated            EventType = "score.created"
+	EventExperimentCompleted     EventType = "experiment.completed"
+	EventAnnotationItemCompleted EventType = "annotation.item.completed"
+	EventPromptLabelMoved        EventType = "prompt.label.moved"
+	EventBudgetAlertTriggered    EventType = "budget.alert.triggered"
+
+	// EventPing is sent by the test endpoint to every subscription regardless of its filter.
+	EventPing EventType = "ping"
+)
+
+// AllEventTypes returns the event types a subscription can filter on.
+func AllEventTypes() []EventType {
+	return []EventType{
+		EventTraceCreated,
+		EventScoreCreated,
+		EventExperimentCompleted,
+		EventAnnotationItemCompleted,
+		EventPromptLabelMoved,
+		EventBudgetAlertTriggered,
+	}
+}
+
+// IsValid checks if the event type can be subscribed to.
+func (e EventType) IsValid() bool {
+	for _, t := range AllEventTypes() {
+		if e == t {
+			return true
+		}
+	}
+	return false
+}
+
+// DeliveryStatus represents the lifecycle of a single delivery.
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending   DeliveryStatus = "pending"
+	DeliveryStatusSucceeded DeliveryStatus = "succeeded"
+	DeliveryStatusFailed    DeliveryStatus = "failed"
+)
+
+// IsValid checks if the status is a valid DeliveryStatus value.
+func (s DeliveryStatus) IsValid() bool {
+	switch s {
+	case DeliveryStatusPending, DeliveryStatusSucceeded, DeliveryStatusFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// MaxEventTypes bounds the filter list; it is larger than the event catalogue to allow growth.
+const MaxEventTypes = 32
+
+// ============================================================================
+// Subscription Entity
+// ============================================================================
+
+// Subscription is a project's registration of an endpoint for a set of event types.
+type Subscription struct {
+	ID          ulid.ULID  `json:"id" gorm:"type:char(26);primaryKey"`
+	ProjectID   ulid.ULID  `json:"project_id" gorm:"type:char(26);not null;index"`
+	Name        string     `json:"name" gorm:"type:varchar(255);not null"`
+	Description *string    `json:"description,omitempty" gorm:"type:text"`
+	URL         string     `json:"url" gorm:"type:text;not null"`
+	Secret      string     `json:"-" gorm:"type:varchar(100);not null"` // Only returned on create and rotate
+	EventTypes  []string   `json:"event_types" gorm:"type:jsonb;serializer:json;default:'[]'"`
+	Enabled     bool       `json:"enabled" gorm:"not null;default:true"`
+	CreatedBy   *ulid.ULID `json:"created_by,omitempty" gorm:"type:char(26)"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"not null;autoCreateTime"`
+	UpdatedAt   time.Time  `json:"updated_at" gorm:"not null;autoUpdateTime"`
+}
+
+// TableName returns the database table name for GORM.
+func (Subscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// NewSubscription creates an enabled subscription with the given signing secret.
+func NewSubscription(projectID ulid.ULID, name, endpoint, secret string, eventTypes []string) *Subscription {
+	now := time.Now()
+	return &Subscription{
+		ID:         ulid.New(),
+		ProjectID:  projectID,
+		Name:       name,
+		URL:        endpoint,
+		Secret:     secret,
+		EventTypes: eventTypes,
+		Enabled:    true,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// Subscribes reports whether the subscription should receive the event type.
+func (s *Subscription) Subscribes(eventType EventType) bool {
+	if !s.Enabled {
+		return false
+	}
+	if eventType == EventPing {
+		return true
+	}
+	for _, t := range s.EventTypes {
+		if EventType(t) == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate validates the subscription's user-supplied fields.
+func (s *Subscription) Validate() []ValidationError {
+	var errors []ValidationError
+
+	if s.Name == "" {
+		errors = append(errors, ValidationError{Field: "name", Message: "name is required"})
+	}
+	if len(s.Name) > 255 {
+		errors = append(errors, ValidationError{Field: "name", Message: "name must be 255 characters or less"})
+	}
+	if msg := validateEndpoint(s.URL); msg != "" {
+		errors = append(errors, ValidationError{Field: "url", Message: msg})
+	}
+	errors = append(errors, ValidateEventTypes(s.EventTypes)...)
+
+	return errors
+}
+
+// ValidateEventTypes checks an event filter list.
+func ValidateEventTypes(eventTypes []string) []ValidationError {
+	if len(eventTypes) == 0 {
+		return []ValidationError{{Field: "event_types", Message: "at least one event type is required"}}
+	}
+	if len(eventTypes) > MaxEventTypes {
+		return []ValidationError{{Field: "event_types", Message: "too many event types"}}
+	}
+	for _, t := range eventTypes {
+		if !EventType(t).IsValid() {
+			return []ValidationError{{Field: "event_types", Message: "unknown event type: " + t}}
+		}
+	}
+	return nil
+}
+
+func validateEndpoint(endpoint string) string {
+	if endpoint == "" {
+		return "url is required"
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Hostname() == "" {
+		return "url must be an absolute URL"
+	}
+	if u.Scheme != "https" {
+		return "url must use https"
+	}
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return "url must not point to an internal address"
+	}
+	if ip, err := netip.ParseAddr(host); err == nil && !IsPublicAddress(ip) {
+		return "url must not point to an internal address"
+	}
+	return ""
+}
+
+// internalPrefixes are non-public ranges that the netip predicates do not cover.
+var internalPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // "This" network
+	netip.MustParsePrefix("100.64.0.0/10"),  // Carrier-grade NAT, also used for cloud metadata
+	netip.MustParsePrefix("192.0.0.0/24"),   // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"),  // Benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),    // Reserved
+	netip.MustParsePrefix("64:ff9b::/96"),   // NAT64, can embed any IPv4 address
+	netip.MustParsePrefix("64:ff9b:1::/48"), // Local-use NAT64
+	netip.MustParsePrefix("2001:db8::/32"),  // Documentation
+	netip.MustParsePrefix("2002::/16"),      // 6to4, can embed any IPv4 address
+	netip.MustParsePrefix("fec0::/10"),      // Deprecated site-local
+}
+
+// IsPublicAddress reports whether deliveries may connect to ip. Loopback, private,
+// link-local (which includes cloud metadata endpoints) and other internal ranges are
+// refused so that a subscription cannot reach the platform's own network.
+func IsPublicAddress(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return false
+	}
+	for _, prefix := range internalPrefixes {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidationError describes a single invalid field.
+type ValidationError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ============================================================================
+// Delivery Entity
+// ============================================================================
+
+// Delivery is one event sent (or to be sent) to one subscription, with the
+// outcome of the latest attempt.
+type Delivery struct {
+	ID             ulid.ULID       `json:"id" gorm:"type:char(26);primaryKey"`
+	SubscriptionID ulid.ULID       `json:"subscription_id" gorm:"type:char(26);not null;index"`
+	ProjectID      ulid.ULID       `json:"project_id" gorm:"type:char(26);not null"`
+	EventID        ulid.ULID       `json:"event_id" gorm:"type:char(26);not null"` // Shared by all deliveries of one event, kept on redelivery
+	EventType      EventType       `json:"event_type" gorm:"type:varchar(50);not null"`
+	Payload        json.RawMessage `json:"payload" gorm:"type:jsonb;serializer:json;not null"`
+	Status         DeliveryStatus  `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
+	Attempts       int             `json:"attempts" gorm:"not null;default:0"`
+	ResponseCode   *int            `json:"response_code,omitempty"`
+	Error          *string         `json:"error,omitempty" gorm:"type:text"`
+	DurationMs     *int64          `json:"duration_ms,omitempty"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"` // Nil once the delivery is terminal
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at" gorm:"not null;autoCreateTime"`
+	UpdatedAt      time.Time       `json:"updated_at" gorm:"not null;autoUpdateTime"`
+}
+
+// TableName returns the database table name for GORM.
+func (Delivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// NewDelivery creates a pending delivery that is due immediately.
+func NewDelivery(sub *Subscription, event *Event, payload json.RawMessage) *Delivery {
+	now := time.Now()
+	return &Delivery{
+		ID:             ulid.New(),
+		SubscriptionID: sub.ID,
+		ProjectID:      sub.ProjectID,
+		EventID:        event.ID,
+		EventType:      event.Type,
+		Payload:        payload,
+		Status:         DeliveryStatusPending,
+		NextAttemptAt:  &now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+// DeliveryFilter narrows a subscription's delivery log.
+type DeliveryFilter struct {
+	Status    *DeliveryStatus
+	EventType *EventType
+}
+
+// DeliverySummary reports the outcome of one delivery pass.
+type DeliverySummary struct {
+	Claimed   int
+	Succeeded int
+	Retried   int
+	Failed    int
+}
+
+// ============================================================================
+// Event Envelope
+// ============================================================================
+
+// Event is the JSON envelope POSTed to subscribers.
+type Event struct {
+	ID        ulid.ULID   `json:"id"`
+	Type      EventType   `json:"type"`
+	ProjectID ulid.ULID   `json:"project_id"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// NewEvent creates an event envelope stamped with the current time.
+func NewEvent(projectID ulid.ULID, eventType EventType, data interface{}) *Event {
+	return &Event{
+		ID:        ulid.New(),
+		Type:      eventType,
+		ProjectID: projectID,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	}
+}
+
+// ============================================================================
+// Request Types
+// ============================================================================
+
+// CreateSubscriptionRequest is the input for registering a webhook endpoint.
+type CreateSubscriptionRequest struct {
+	Name        string   `json:"name" binding:"required,max=255"`
+	Description *string  `json:"description,omitempty"`
+	URL         string   `json:"url" binding:"required,url"`
+	EventTypes  []string `json:"event_types" binding:"required,min=1"`
+	Enabled     *bool    `json:"enabled,omitempty"`
+}
+
+// UpdateSubscriptionRequest is the input for editing a webhook endpoint.
+// Nil fields are left unchanged.
+type UpdateSubscriptionRequest struct {
+	Name        *string  `json:"name,omitempty" binding:"omitempty,max=255"`
+	Description *string  `json:"description,omitempty"`
+	URL         *string  `json:"url,omitempty" binding:"omitempty,url"`
+	EventTypes  []string `json:"event_types,omitempty"`
+	Enabled     *bool    `json:"enabled,omitempty"`
+}
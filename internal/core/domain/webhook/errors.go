@@ -0,0 +1,9 @@
+package webhook
+
+import "errors"
+
+var (
+	ErrSubscriptionNotFound = errors.New("webhook subscription not found")
+	ErrSubscriptionExists   = errors.New("webhook subscription with this name already exists")
+	ErrDeliveryNotFound     = errors.New("webhook delivery not found")
+)
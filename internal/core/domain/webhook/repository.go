@@ -0,0 +1,41 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"brokle/pkg/ulid"
+)
+
+// SubscriptionRepository defines the interface for webhook subscription data access.
+type SubscriptionRepository interface {
+	Create(ctx context.Context, sub *Subscription) error
+	GetByID(ctx context.Context, id, projectID ulid.ULID) (*Subscription, error)
+	Update(ctx context.Context, sub *Subscription) error
+	Delete(ctx context.Context, id, projectID ulid.ULID) error
+	List(ctx context.Context, projectID ulid.ULID) ([]*Subscription, error)
+	ExistsByName(ctx context.Context, projectID ulid.ULID, name string) (bool, error)
+
+	// ListEnabledByProject returns enabled subscriptions for event fan-out.
+	ListEnabledByProject(ctx context.Context, projectID ulid.ULID) ([]*Subscription, error)
+
+	// ListEnabledByOrganization returns enabled subscriptions across all of an
+	// organization's projects, for organization-scoped events such as budget alerts.
+	ListEnabledByOrganization(ctx context.Context, orgID ulid.ULID) ([]*Subscription, error)
+
+	// GetByIDs loads subscriptions regardless of project, for the delivery worker.
+	GetByIDs(ctx context.Context, ids []ulid.ULID) ([]*Subscription, error)
+}
+
+// DeliveryRepository defines the interface for webhook delivery log access.
+type DeliveryRepository interface {
+	CreateBatch(ctx context.Context, deliveries []*Delivery) error
+	GetByID(ctx context.Context, id, subscriptionID ulid.ULID) (*Delivery, error)
+	Update(ctx context.Context, delivery *Delivery) error
+	List(ctx context.Context, subscriptionID ulid.ULID, filter *DeliveryFilter, offset, limit int) ([]*Delivery, int64, error)
+
+	// ClaimDue locks up to limit pending deliveries whose next attempt is due and
+	// pushes their next_attempt_at forward by lease, so concurrent workers skip them.
+	// A worker that dies mid-delivery leaves the row to be retried after the lease.
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*Delivery, error)
+}
@@ -0,0 +1,44 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"brokle/pkg/ulid"
+)
+
+// Publisher fans platform events out to matching subscriptions.
+// Publishing never fails the caller: lookup and persistence errors are logged
+// and the event is dropped, so a webhook outage cannot break ingestion or CRUD.
+type Publisher interface {
+	Publish(ctx context.Context, projectID ulid.ULID, eventType EventType, data interface{})
+
+	// PublishToOrganization delivers an organization-scoped event to the
+	// subscriptions of every project in the organization.
+	PublishToOrganization(ctx context.Context, orgID ulid.ULID, eventType EventType, data interface{})
+}
+
+// Service defines the webhook subscription management and delivery interface.
+type Service interface {
+	Publisher
+
+	Create(ctx context.Context, projectID ulid.ULID, userID *ulid.ULID, req *CreateSubscriptionRequest) (*Subscription, error)
+	Get(ctx context.Context, projectID, id ulid.ULID) (*Subscription, error)
+	List(ctx context.Context, projectID ulid.ULID) ([]*Subscription, error)
+	Update(ctx context.Context, projectID, id ulid.ULID, req *UpdateSubscriptionRequest) (*Subscription, error)
+	Delete(ctx context.Context, projectID, id ulid.ULID) error
+
+	// RotateSecret replaces the signing secret. The returned subscription carries the new secret.
+	RotateSecret(ctx context.Context, projectID, id ulid.ULID) (*Subscription, error)
+
+	// SendTest queues a ping event to the subscription and returns its delivery.
+	SendTest(ctx context.Context, projectID, id ulid.ULID) (*Delivery, error)
+
+	ListDeliveries(ctx context.Context, projectID, subscriptionID ulid.ULID, filter *DeliveryFilter, page, limit int) ([]*Delivery, int64, error)
+
+	// Redeliver queues a new delivery with the original event payload.
+	Redeliver(ctx context.Context, projectID, subscriptionID, deliveryID ulid.ULID) (*Delivery, error)
+
+	// ProcessDueDeliveries sends one batch of due deliveries and records their outcome.
+	ProcessDueDeliveries(ctx context.Context, now time.Time) (*DeliverySummary, error)
+}
@@ -0,0 +1,43 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+)
+
+// Headers sent with every outbound webhook.
+const (
+	SignatureHeader = "X-Brokle-Signature"
+	TimestampHeader = "X-Brokle-Timestamp"
+	EventHeader     = "X-Brokle-Event"
+	DeliveryHeader  = "X-Brokle-Delivery"
+)
+
+const (
+	SecretPrefix = "whsec_"
+	secretBytes  = 32
+)
+
+// SignPayload returns the signature header value for a webhook body.
+// Receivers recompute HMAC-SHA256(secret, "<timestamp>.<body>") and compare in constant time.
+func SignPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// GenerateSecret returns a new random signing secret.
+// Format: whsec_{64_hex_chars}
+func GenerateSecret() (string, error) {
+	b := make([]byte, secretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return SecretPrefix + hex.EncodeToString(b), nil
+}
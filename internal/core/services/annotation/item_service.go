@@ -13,6 +13,7 @@ import (
 	"brokle/internal/core/domain/evaluation"
 	"brokle/internal/core/domain/observability"
 	"brokle/internal/core/domain/organization"
+	"brokle/internal/core/domain/webhook"
 	appErrors "brokle/pkg/errors"
 	"brokle/pkg/ulid"
 )
@@ -25,6 +26,7 @@ type itemService struct {
 	scoreService       observability.ScoreService
 	projectRepo        organization.ProjectRepository
 	transactor         common.Transactor
+	publisher          webhook.Publisher // Optional: emits annotation.item.completed
 	logger             *slog.Logger
 }
 
@@ -37,6 +39,7 @@ func NewItemService(
 	scoreService observability.ScoreService,
 	projectRepo organization.ProjectRepository,
 	transactor common.Transactor,
+	publisher webhook.Publisher,
 	logger *slog.Logger,
 ) annotation.ItemService {
 	return &itemService{
@@ -47,6 +50,7 @@ func NewItemService(
 		scoreService:       scoreService,
 		projectRepo:        projectRepo,
 		transactor:         transactor,
+		publisher:          publisher,
 		logger:             logger,
 	}
 }
@@ -224,6 +228,20 @@ func (s *itemService) Complete(ctx context.Context, itemID, queueID, projectID,
 		"scores_count", len(req.Scores),
 	)
 
+	if s.publisher != nil {
+		now := time.Now()
+		item.Status = annotation.ItemStatusCompleted
+		item.AnnotatorUserID = &userID
+		item.CompletedAt = &now
+
+		s.publisher.Publish(ctx, projectID, webhook.EventAnnotationItemCompleted, map[string]interface{}{
+			"item":       item,
+			"queue_id":   queue.ID,
+			"queue_name": queue.Name,
+			"scores":     req.Scores,
+		})
+	}
+
 	return nil
 }
 
@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"context"
+
+	authDomain "brokle/internal/core/domain/auth"
+	"brokle/pkg/ulid"
+)
+
+const auditResourceAPIKey = "api_key"
+
+// apiKeyAuditDecorator records API key creation and deletion in the audit log.
+// Read and validation methods pass straight through to the wrapped service.
+type apiKeyAuditDecorator struct {
+	authDomain.APIKeyService
+	auditLogs authDomain.AuditLogService
+}
+
+// NewAPIKeyAuditDecorator wraps an APIKeyService with audit logging
+func NewAPIKeyAuditDecorator(service authDomain.APIKeyService, auditLogs authDomain.AuditLogService) authDomain.APIKeyService {
+	return &apiKeyAuditDecorator{
+		APIKeyService: service,
+		auditLogs:     auditLogs,
+	}
+}
+
+func (d *apiKeyAuditDecorator) CreateAPIKey(ctx context.Context, userID ulid.ULID, req *authDomain.CreateAPIKeyRequest) (*authDomain.CreateAPIKeyResponse, error) {
+	resp, err := d.APIKeyService.CreateAPIKey(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// The response carries the full key; only the preview is audited
+	d.auditLogs.Record(ctx, &authDomain.AuditEvent{
+		UserID:     &userID,
+		ProjectID:  &req.ProjectID,
+		Action:     "api_key.created",
+		Resource:   auditResourceAPIKey,
+		ResourceID: resp.ID,
+		After: map[string]interface{}{
+			"name":        resp.Name,
+			"key_preview": resp.KeyPreview,
+			"scopes":      resp.Scopes,
+			"expires_at":  resp.ExpiresAt,
+		},
+	})
+	return resp, nil
+}
+
+func (d *apiKeyAuditDecorator) DeleteAPIKey(ctx context.Context, keyID ulid.ULID, projectID ulid.ULID) error {
+	before, _ := d.APIKeyService.GetAPIKey(ctx, keyID)
+	if err := d.APIKeyService.DeleteAPIKey(ctx, keyID, projectID); err != nil {
+		return err
+	}
+
+	d.auditLogs.Record(ctx, &authDomain.AuditEvent{
+		ProjectID:  &projectID,
+		Action:     "api_key.deleted",
+		Resource:   auditResourceAPIKey,
+		ResourceID: keyID.String(),
+		Before:     before,
+	})
+	return nil
+}
@@ -19,6 +19,7 @@ type apiKeyService struct {
 	apiKeyRepo             authDomain.APIKeyRepository
 	organizationMemberRepo authDomain.OrganizationMemberRepository
 	projectRepo            orgDomain.ProjectRepository
+	rateLimitService       authDomain.RateLimitService
 }
 
 // NewAPIKeyService creates a new API key service instance
@@ -26,11 +27,13 @@ func NewAPIKeyService(
 	apiKeyRepo authDomain.APIKeyRepository,
 	organizationMemberRepo authDomain.OrganizationMemberRepository,
 	projectRepo orgDomain.ProjectRepository,
+	rateLimitService authDomain.RateLimitService,
 ) authDomain.APIKeyService {
 	return &apiKeyService{
 		apiKeyRepo:             apiKeyRepo,
 		organizationMemberRepo: organizationMemberRepo,
 		projectRepo:            projectRepo,
+		rateLimitService:       rateLimitService,
 	}
 }
 
@@ -54,6 +57,12 @@ func (s *apiKeyService) CreateAPIKey(ctx context.Context, userID ulid.ULID, req
 	// Create key preview for display (bk_...xyz)
 	keyPreview := authDomain.CreateKeyPreview(fullKey)
 
+	// Restrict the key to the requested scopes (all API key scopes when none are given)
+	scopes, err := authDomain.NormalizeAPIKeyScopes(req.Scopes)
+	if err != nil {
+		return nil, appErrors.NewValidationError("scopes", err.Error())
+	}
+
 	// Create API key entity (project_id stored in database, not in key)
 	apiKeyEntity := authDomain.NewAPIKey(
 		userID,
@@ -61,6 +70,7 @@ func (s *apiKeyService) CreateAPIKey(ctx context.Context, userID ulid.ULID, req
 		req.Name,
 		keyHash, // SHA-256 hash of full key (deterministic, enables O(1) lookup)
 		keyPreview,
+		scopes,
 		req.ExpiresAt,
 	)
 
@@ -76,6 +86,7 @@ func (s *apiKeyService) CreateAPIKey(ctx context.Context, userID ulid.ULID, req
 		Key:        fullKey, // Full key - only returned once
 		KeyPreview: apiKeyEntity.KeyPreview,
 		ProjectID:  apiKeyEntity.ProjectID.String(),
+		Scopes:     apiKeyEntity.Scopes,
 		CreatedAt:  apiKeyEntity.CreatedAt,
 		ExpiresAt:  apiKeyEntity.ExpiresAt,
 	}, nil
@@ -171,6 +182,7 @@ func (s *apiKeyService) ValidateAPIKey(ctx context.Context, fullKey string) (*au
 	authContext := &authDomain.AuthContext{
 		UserID:   apiKey.UserID,
 		APIKeyID: &apiKey.ID,
+		Scopes:   apiKey.Scopes,
 	}
 
 	// Update last used timestamp (async, don't block validation)
@@ -199,11 +211,19 @@ func (s *apiKeyService) ValidateAPIKey(ctx context.Context, fullKey string) (*au
 	}, nil
 }
 
-// CheckRateLimit checks if the API key has exceeded rate limits
+// CheckRateLimit checks if the API key has exceeded rate limits, consuming one request
+// Transport layers call RateLimitService.Check directly to also get headers and quotas
 func (s *apiKeyService) CheckRateLimit(ctx context.Context, keyID ulid.ULID) (bool, error) {
-	// TODO: Implement rate limiting logic with Redis
-	// For now, always allow requests
-	return true, nil
+	apiKey, err := s.apiKeyRepo.GetByID(ctx, keyID)
+	if err != nil {
+		return false, fmt.Errorf("get API key: %w", err)
+	}
+
+	decision, err := s.rateLimitService.Check(ctx, apiKey.ProjectID, apiKey.ID, authDomain.RateLimitCost{Requests: 1})
+	if err != nil {
+		return false, err
+	}
+	return decision.Allowed, nil
 }
 
 // GetAPIKeyContext creates an AuthContext from an API key
@@ -216,21 +236,21 @@ func (s *apiKeyService) GetAPIKeyContext(ctx context.Context, keyID ulid.ULID) (
 	return &authDomain.AuthContext{
 		UserID:   apiKey.UserID,
 		APIKeyID: &apiKey.ID,
+		Scopes:   apiKey.Scopes,
 	}, nil
 }
 
 // CanAPIKeyAccessResource checks if an API key can access a specific resource
-// Note: All non-deleted, non-expired API keys have full access to their project
-// Access control should be handled at the organization RBAC level
+// The resource is an API key scope (e.g. "traces:create") and is only accessible
+// within the key's own project
 func (s *apiKeyService) CanAPIKeyAccessResource(ctx context.Context, keyID ulid.ULID, resource string) (bool, error) {
 	apiKey, err := s.apiKeyRepo.GetByID(ctx, keyID)
 	if err != nil {
 		return false, fmt.Errorf("get API key: %w", err)
 	}
 
-	// All API keys have full access to their project (deleted keys filtered by GORM)
-	// Fine-grained permissions handled by organization RBAC
-	return !apiKey.IsExpired(), nil
+	// Deleted keys filtered by GORM
+	return !apiKey.IsExpired() && apiKey.HasScope(resource), nil
 }
 
 // Scoped access methods
@@ -181,6 +181,10 @@ func (a *auditDecorator) GetAuthContext(ctx context.Context, token string) (*aut
 }
 
 // OAuth session methods - delegate without audit
+func (a *auditDecorator) CheckSocialLoginAllowed(ctx context.Context, email string) error {
+	return a.authService.CheckSocialLoginAllowed(ctx, email)
+}
+
 func (a *auditDecorator) CreateOAuthSession(ctx context.Context, session interface{}) (string, error) {
 	return a.authService.CreateOAuthSession(ctx, session)
 }
@@ -206,3 +210,141 @@ func (a *auditDecorator) CreateLoginTokenSession(ctx context.Context, accessToke
 func (a *auditDecorator) GetLoginTokenSession(ctx context.Context, sessionID string) (map[string]interface{}, error) {
 	return a.authService.GetLoginTokenSession(ctx, sessionID)
 }
+
+// VerifyMFAChallenge handles the second login step with audit logging
+func (a *auditDecorator) VerifyMFAChallenge(ctx context.Context, req *authDomain.MFAChallengeRequest) (*authDomain.LoginResponse, error) {
+	// Looked up first: the challenge is deleted once it succeeds or runs out of attempts
+	var challengeUserID *ulid.ULID
+	if id, lookupErr := a.authService.GetMFAChallengeUserID(ctx, req.MFAToken); lookupErr == nil {
+		challengeUserID = &id
+	}
+
+	resp, err := a.authService.VerifyMFAChallenge(ctx, req)
+
+	if err != nil {
+		resourceID := ""
+		if challengeUserID != nil {
+			resourceID = challengeUserID.String()
+		}
+		auditLog := authDomain.NewAuditLog(challengeUserID, nil, "auth.mfa.verify.failed", "user", resourceID,
+			fmt.Sprintf(`{"reason": "%s"}`, mfaFailureReason(err)), "", "")
+		if createErr := a.auditRepo.Create(ctx, auditLog); createErr != nil {
+			a.logger.Error("Failed to create MFA failure audit log", "error", createErr)
+		}
+		return resp, err
+	}
+
+	var userID *ulid.ULID
+	if authCtx, ctxErr := a.authService.GetAuthContext(ctx, resp.AccessToken); ctxErr == nil {
+		userID = &authCtx.UserID
+	}
+	action := "auth.mfa.verify.success"
+	if len(resp.RecoveryCodes) > 0 {
+		// Enrolment required by an organization, completed at login
+		action = "auth.mfa.enrolled"
+	}
+	resourceID := ""
+	if userID != nil {
+		resourceID = userID.String()
+	}
+	auditLog := authDomain.NewAuditLog(userID, nil, action, "user", resourceID, `{}`, "", "")
+	if createErr := a.auditRepo.Create(ctx, auditLog); createErr != nil {
+		a.logger.Error("Failed to create MFA success audit log", "error", createErr)
+	}
+
+	return resp, err
+}
+
+// ConfirmMFAEnrollment handles MFA enrolment with audit logging
+func (a *auditDecorator) ConfirmMFAEnrollment(ctx context.Context, userID ulid.ULID, code string) (*authDomain.MFARecoveryCodes, error) {
+	codes, err := a.authService.ConfirmMFAEnrollment(ctx, userID, code)
+
+	action, metadata := "auth.mfa.enrolled", `{}`
+	if err != nil {
+		action, metadata = "auth.mfa.enroll.failed", fmt.Sprintf(`{"reason": "%s"}`, mfaFailureReason(err))
+	}
+	a.auditMFA(ctx, &userID, nil, action, userID, metadata)
+
+	return codes, err
+}
+
+// RegenerateMFARecoveryCodes handles recovery code rotation with audit logging
+func (a *auditDecorator) RegenerateMFARecoveryCodes(ctx context.Context, userID ulid.ULID, code string) (*authDomain.MFARecoveryCodes, error) {
+	codes, err := a.authService.RegenerateMFARecoveryCodes(ctx, userID, code)
+
+	if err != nil {
+		a.auditMFA(ctx, &userID, nil, "auth.mfa.recovery_codes.failed", userID, fmt.Sprintf(`{"reason": "%s"}`, mfaFailureReason(err)))
+	} else {
+		a.auditMFA(ctx, &userID, nil, "auth.mfa.recovery_codes.regenerated", userID, `{}`)
+	}
+
+	return codes, err
+}
+
+// DisableMFA handles MFA removal by the user with audit logging
+func (a *auditDecorator) DisableMFA(ctx context.Context, userID ulid.ULID, code string) error {
+	err := a.authService.DisableMFA(ctx, userID, code)
+
+	if err != nil {
+		a.auditMFA(ctx, &userID, nil, "auth.mfa.disable.failed", userID, fmt.Sprintf(`{"reason": "%s"}`, mfaFailureReason(err)))
+	} else {
+		a.auditMFA(ctx, &userID, nil, "auth.mfa.disabled", userID, `{}`)
+	}
+
+	return err
+}
+
+// ResetMFA handles an admin MFA reset with audit logging; the admin is the actor
+func (a *auditDecorator) ResetMFA(ctx context.Context, adminID, orgID, userID ulid.ULID) error {
+	err := a.authService.ResetMFA(ctx, adminID, orgID, userID)
+
+	if err == nil {
+		a.auditMFA(ctx, &adminID, &orgID, "auth.mfa.reset", userID, `{}`)
+	}
+
+	return err
+}
+
+func (a *auditDecorator) auditMFA(ctx context.Context, actorID, orgID *ulid.ULID, action string, userID ulid.ULID, metadata string) {
+	auditLog := authDomain.NewAuditLog(actorID, orgID, action, "user", userID.String(), metadata, "", "")
+	if createErr := a.auditRepo.Create(ctx, auditLog); createErr != nil {
+		a.logger.Error("Failed to create MFA audit log", "error", createErr, "action", action)
+	}
+}
+
+func mfaFailureReason(err error) string {
+	if appErr, ok := appErrors.IsAppError(err); ok {
+		switch appErr.Type {
+		case appErrors.UnauthorizedError, appErrors.ValidationError:
+			return "invalid_code"
+		case appErrors.ForbiddenError:
+			return "forbidden"
+		case appErrors.NotFoundError:
+			return "not_enrolled"
+		case appErrors.ConflictError:
+			return "already_enabled"
+		}
+	}
+	return "system_error"
+}
+
+// MFA methods without audit - delegate
+func (a *auditDecorator) CreateMFAChallenge(ctx context.Context, userID ulid.ULID, deviceInfo map[string]interface{}) (*authDomain.LoginResponse, error) {
+	return a.authService.CreateMFAChallenge(ctx, userID, deviceInfo)
+}
+
+func (a *auditDecorator) GetMFAChallenge(ctx context.Context, mfaToken string) (*authDomain.LoginResponse, error) {
+	return a.authService.GetMFAChallenge(ctx, mfaToken)
+}
+
+func (a *auditDecorator) GetMFAChallengeUserID(ctx context.Context, mfaToken string) (ulid.ULID, error) {
+	return a.authService.GetMFAChallengeUserID(ctx, mfaToken)
+}
+
+func (a *auditDecorator) GetMFAStatus(ctx context.Context, userID ulid.ULID) (*authDomain.MFAStatus, error) {
+	return a.authService.GetMFAStatus(ctx, userID)
+}
+
+func (a *auditDecorator) BeginMFAEnrollment(ctx context.Context, userID ulid.ULID) (*authDomain.MFAEnrollment, error) {
+	return a.authService.BeginMFAEnrollment(ctx, userID)
+}
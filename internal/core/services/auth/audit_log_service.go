@@ -0,0 +1,308 @@
+package auth
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"sort"
+	"time"
+
+	authDomain "brokle/internal/core/domain/auth"
+	orgDomain "brokle/internal/core/domain/organization"
+	appErrors "brokle/pkg/errors"
+	"brokle/pkg/ulid"
+)
+
+const auditExportBatchSize = 500
+
+// Snapshot fields that change on every write and carry no audit value
+var auditIgnoredFields = map[string]bool{
+	"updated_at": true,
+}
+
+var auditCSVHeader = []string{
+	"id", "created_at", "action", "resource", "resource_id", "organization_id", "project_id",
+	"user_id", "api_key_id", "ip_address", "user_agent", "changes", "metadata",
+}
+
+// auditLogService implements authDomain.AuditLogService
+type auditLogService struct {
+	auditRepo   authDomain.AuditLogRepository
+	projectRepo orgDomain.ProjectRepository
+	logger      *slog.Logger
+}
+
+// NewAuditLogService creates the audit log service
+func NewAuditLogService(
+	auditRepo authDomain.AuditLogRepository,
+	projectRepo orgDomain.ProjectRepository,
+	logger *slog.Logger,
+) authDomain.AuditLogService {
+	return &auditLogService{
+		auditRepo:   auditRepo,
+		projectRepo: projectRepo,
+		logger:      logger,
+	}
+}
+
+// Record writes the audit entry of a mutation with the before/after diff
+func (s *auditLogService) Record(ctx context.Context, event *authDomain.AuditEvent) {
+	metadata, err := marshalAuditMetadata(event.Metadata)
+	if err != nil {
+		s.logger.Warn("Failed to encode audit metadata", "error", err, "action", event.Action)
+		metadata = "{}"
+	}
+
+	auditLog := authDomain.NewAuditLog(event.UserID, event.OrganizationID, event.Action, event.Resource, event.ResourceID, metadata, "", "")
+	auditLog.ProjectID = event.ProjectID
+	if actor, ok := authDomain.AuditActorFromContext(ctx); ok {
+		if auditLog.UserID == nil {
+			auditLog.UserID = actor.UserID
+		}
+		auditLog.APIKeyID = actor.APIKeyID
+		auditLog.IPAddress = actor.IPAddress
+		auditLog.UserAgent = actor.UserAgent
+	}
+
+	if auditLog.OrganizationID == nil && event.ProjectID != nil {
+		if project, err := s.projectRepo.GetByID(ctx, *event.ProjectID); err == nil {
+			auditLog.OrganizationID = &project.OrganizationID
+		} else {
+			s.logger.Warn("Failed to resolve organization for audit log", "error", err, "project_id", event.ProjectID)
+		}
+	}
+
+	if auditLog.Changes, err = auditChanges(event.Before, event.After); err != nil {
+		s.logger.Warn("Failed to diff audited resource", "error", err, "action", event.Action)
+	}
+
+	if err := s.auditRepo.Create(ctx, auditLog); err != nil {
+		s.logger.Error("Failed to create audit log", "error", err, "action", event.Action, "resource_id", event.ResourceID)
+	}
+}
+
+// LogUserAction records an action performed by a user
+func (s *auditLogService) LogUserAction(ctx context.Context, userID *ulid.ULID, action, resource, resourceID string, metadata map[string]interface{}, ipAddress, userAgent string) error {
+	encoded, err := marshalAuditMetadata(metadata)
+	if err != nil {
+		return appErrors.NewValidationError("Invalid audit metadata", err.Error())
+	}
+	auditLog := authDomain.NewAuditLog(userID, nil, action, resource, resourceID, encoded, ipAddress, userAgent)
+	if err := s.auditRepo.Create(ctx, auditLog); err != nil {
+		return appErrors.NewInternalError("Failed to create audit log", err)
+	}
+	return nil
+}
+
+// LogSystemAction records an action performed by the platform itself
+func (s *auditLogService) LogSystemAction(ctx context.Context, action, resource, resourceID string, metadata map[string]interface{}) error {
+	return s.LogUserAction(ctx, nil, action, resource, resourceID, metadata, "", "")
+}
+
+// LogSecurityEvent records a security-relevant event such as a lockout
+func (s *auditLogService) LogSecurityEvent(ctx context.Context, userID *ulid.ULID, event, description string, metadata map[string]interface{}, ipAddress, userAgent string) error {
+	withDescription := map[string]interface{}{"description": description}
+	for key, value := range metadata {
+		withDescription[key] = value
+	}
+	return s.LogUserAction(ctx, userID, "security."+event, "security", "", withDescription, ipAddress, userAgent)
+}
+
+func (s *auditLogService) GetUserAuditLogs(ctx context.Context, userID ulid.ULID, limit, offset int) ([]*authDomain.AuditLog, error) {
+	return s.auditRepo.GetByUserID(ctx, userID, limit, offset)
+}
+
+func (s *auditLogService) GetOrganizationAuditLogs(ctx context.Context, orgID ulid.ULID, limit, offset int) ([]*authDomain.AuditLog, error) {
+	return s.auditRepo.GetByOrganizationID(ctx, orgID, limit, offset)
+}
+
+func (s *auditLogService) GetResourceAuditLogs(ctx context.Context, resource, resourceID string, limit, offset int) ([]*authDomain.AuditLog, error) {
+	return s.auditRepo.GetByResource(ctx, resource, resourceID, limit, offset)
+}
+
+// SearchAuditLogs lists audit logs matching the filters, newest first by default
+func (s *auditLogService) SearchAuditLogs(ctx context.Context, filters *authDomain.AuditLogFilters) ([]*authDomain.AuditLog, int, error) {
+	logs, total, err := s.auditRepo.Search(ctx, filters)
+	if err != nil {
+		return nil, 0, appErrors.NewInternalError("Failed to search audit logs", err)
+	}
+	return logs, total, nil
+}
+
+// ExportAuditLogs writes every log matching the filters, oldest first, as CSV or a JSON array
+func (s *auditLogService) ExportAuditLogs(ctx context.Context, filters *authDomain.AuditLogFilters, format authDomain.AuditExportFormat, w io.Writer) error {
+	switch format {
+	case authDomain.AuditExportCSV:
+		return s.exportCSV(ctx, filters, w)
+	case authDomain.AuditExportJSON:
+		return s.exportJSON(ctx, filters, w)
+	default:
+		return appErrors.NewValidationError("Invalid export format", "format must be csv or json")
+	}
+}
+
+func (s *auditLogService) exportCSV(ctx context.Context, filters *authDomain.AuditLogFilters, w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(auditCSVHeader); err != nil {
+		return err
+	}
+
+	err := s.auditRepo.StreamByFilters(ctx, filters, auditExportBatchSize, func(batch []*authDomain.AuditLog) error {
+		for _, log := range batch {
+			if err := writer.Write(auditCSVRecord(log)); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	})
+	if err != nil {
+		return fmt.Errorf("export audit logs: %w", err)
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func (s *auditLogService) exportJSON(ctx context.Context, filters *authDomain.AuditLogFilters, w io.Writer) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+
+	separator := "\n"
+	err := s.auditRepo.StreamByFilters(ctx, filters, auditExportBatchSize, func(batch []*authDomain.AuditLog) error {
+		for _, log := range batch {
+			encoded, err := json.Marshal(auditExportEntry(log))
+			if err != nil {
+				return err
+			}
+			if _, err := io.WriteString(w, separator); err != nil {
+				return err
+			}
+			if _, err := w.Write(encoded); err != nil {
+				return err
+			}
+			separator = ",\n"
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("export audit logs: %w", err)
+	}
+
+	_, err = io.WriteString(w, "\n]\n")
+	return err
+}
+
+func (s *auditLogService) CleanupOldAuditLogs(ctx context.Context, olderThan time.Time) error {
+	return s.auditRepo.CleanupOldLogs(ctx, olderThan)
+}
+
+func (s *auditLogService) GetAuditLogStats(ctx context.Context) (*authDomain.AuditLogStats, error) {
+	return s.auditRepo.GetAuditLogStats(ctx)
+}
+
+// auditExportEntry inlines the JSON-encoded columns so exports hold objects, not strings
+func auditExportEntry(log *authDomain.AuditLog) interface{} {
+	var metadata json.RawMessage
+	if log.Metadata != "" && json.Valid([]byte(log.Metadata)) {
+		metadata = json.RawMessage(log.Metadata)
+	}
+	return struct {
+		*authDomain.AuditLog
+		Metadata json.RawMessage `json:"metadata,omitempty"`
+	}{log, metadata}
+}
+
+func auditCSVRecord(log *authDomain.AuditLog) []string {
+	return []string{
+		log.ID.String(),
+		log.CreatedAt.UTC().Format(time.RFC3339),
+		log.Action,
+		log.Resource,
+		log.ResourceID,
+		optionalID(log.OrganizationID),
+		optionalID(log.ProjectID),
+		optionalID(log.UserID),
+		optionalID(log.APIKeyID),
+		log.IPAddress,
+		log.UserAgent,
+		string(log.Changes),
+		log.Metadata,
+	}
+}
+
+func optionalID(id *ulid.ULID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
+func marshalAuditMetadata(metadata map[string]interface{}) (string, error) {
+	if len(metadata) == 0 {
+		return "{}", nil
+	}
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+// auditChanges diffs the top-level JSON fields of two snapshots of a resource.
+// A nil side (create or delete) reports every field of the other side.
+func auditChanges(before, after interface{}) (json.RawMessage, error) {
+	beforeFields, err := auditSnapshot(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := auditSnapshot(after)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(beforeFields)+len(afterFields))
+	for key := range beforeFields {
+		keys = append(keys, key)
+	}
+	for key := range afterFields {
+		if _, ok := beforeFields[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	changes := make(map[string]authDomain.AuditChange)
+	for _, key := range keys {
+		if auditIgnoredFields[key] {
+			continue
+		}
+		if old, updated := beforeFields[key], afterFields[key]; !reflect.DeepEqual(old, updated) {
+			changes[key] = authDomain.AuditChange{Before: old, After: updated}
+		}
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(changes)
+}
+
+// auditSnapshot decodes a resource into its JSON fields, honouring json:"-" on secrets
+func auditSnapshot(resource interface{}) (map[string]interface{}, error) {
+	if resource == nil {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, fmt.Errorf("audit snapshot must be a JSON object: %w", err)
+	}
+	return fields, nil
+}
@@ -0,0 +1,214 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authDomain "brokle/internal/core/domain/auth"
+	orgDomain "brokle/internal/core/domain/organization"
+	appErrors "brokle/pkg/errors"
+	"brokle/pkg/ulid"
+)
+
+type memoryAuditLogRepository struct {
+	authDomain.AuditLogRepository
+	logs []*authDomain.AuditLog
+}
+
+func (r *memoryAuditLogRepository) Create(ctx context.Context, auditLog *authDomain.AuditLog) error {
+	r.logs = append(r.logs, auditLog)
+	return nil
+}
+
+func (r *memoryAuditLogRepository) StreamByFilters(ctx context.Context, filters *authDomain.AuditLogFilters, batchSize int, fn func(batch []*authDomain.AuditLog) error) error {
+	for start := 0; start < len(r.logs); start += batchSize {
+		end := min(start+batchSize, len(r.logs))
+		if err := fn(r.logs[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type auditProjectRepository struct {
+	orgDomain.ProjectRepository
+	projects map[ulid.ULID]*orgDomain.Project
+}
+
+func (r *auditProjectRepository) GetByID(ctx context.Context, id ulid.ULID) (*orgDomain.Project, error) {
+	project, ok := r.projects[id]
+	if !ok {
+		return nil, appErrors.NewNotFoundError("project")
+	}
+	return project, nil
+}
+
+func newTestAuditLogService(repo *memoryAuditLogRepository, projects ...*orgDomain.Project) authDomain.AuditLogService {
+	projectRepo := &auditProjectRepository{projects: make(map[ulid.ULID]*orgDomain.Project)}
+	for _, project := range projects {
+		projectRepo.projects[project.ID] = project
+	}
+	return NewAuditLogService(repo, projectRepo, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestAuditChanges(t *testing.T) {
+	type resource struct {
+		Name      string `json:"name"`
+		Secret    string `json:"-"`
+		UpdatedAt string `json:"updated_at"`
+		Status    string `json:"status"`
+	}
+
+	t.Run("reports only changed fields", func(t *testing.T) {
+		changes, err := auditChanges(
+			resource{Name: "v1", Secret: "a", UpdatedAt: "t1", Status: "active"},
+			resource{Name: "v2", Secret: "b", UpdatedAt: "t2", Status: "active"},
+		)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"name": {"before": "v1", "after": "v2"}}`, string(changes))
+	})
+
+	t.Run("create reports every field", func(t *testing.T) {
+		changes, err := auditChanges(nil, map[string]interface{}{"role_id": "r1"})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"role_id": {"before": null, "after": "r1"}}`, string(changes))
+	})
+
+	t.Run("no-op update has no diff", func(t *testing.T) {
+		changes, err := auditChanges(resource{Name: "same", UpdatedAt: "t1"}, resource{Name: "same", UpdatedAt: "t2"})
+		require.NoError(t, err)
+		assert.Nil(t, changes)
+	})
+
+	t.Run("non-object snapshots are rejected", func(t *testing.T) {
+		_, err := auditChanges([]string{"a"}, nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestAuditLogService_Record(t *testing.T) {
+	orgID := ulid.New()
+	project := &orgDomain.Project{ID: ulid.New(), OrganizationID: orgID}
+	userID := ulid.New()
+	apiKeyID := ulid.New()
+
+	t.Run("uses the request actor and resolves the organization", func(t *testing.T) {
+		repo := &memoryAuditLogRepository{}
+		service := newTestAuditLogService(repo, project)
+		ctx := authDomain.WithAuditActor(context.Background(), authDomain.AuditActor{
+			UserID:    &userID,
+			APIKeyID:  &apiKeyID,
+			IPAddress: "203.0.113.7",
+			UserAgent: "curl/8.0",
+		})
+
+		service.Record(ctx, &authDomain.AuditEvent{
+			ProjectID:  &project.ID,
+			Action:     "dataset.updated",
+			Resource:   "dataset",
+			ResourceID: "ds-1",
+			Before:     map[string]interface{}{"name": "old"},
+			After:      map[string]interface{}{"name": "new"},
+			Metadata:   map[string]interface{}{"source": "ui"},
+		})
+
+		require.Len(t, repo.logs, 1)
+		entry := repo.logs[0]
+		assert.Equal(t, &userID, entry.UserID)
+		assert.Equal(t, &apiKeyID, entry.APIKeyID)
+		assert.Equal(t, &orgID, entry.OrganizationID)
+		assert.Equal(t, &project.ID, entry.ProjectID)
+		assert.Equal(t, "203.0.113.7", entry.IPAddress)
+		assert.Equal(t, "curl/8.0", entry.UserAgent)
+		assert.JSONEq(t, `{"name": {"before": "old", "after": "new"}}`, string(entry.Changes))
+		assert.JSONEq(t, `{"source": "ui"}`, entry.Metadata)
+	})
+
+	t.Run("explicit actor wins over the request actor", func(t *testing.T) {
+		repo := &memoryAuditLogRepository{}
+		service := newTestAuditLogService(repo)
+		actorID := ulid.New()
+		ctx := authDomain.WithAuditActor(context.Background(), authDomain.AuditActor{UserID: &userID})
+
+		service.Record(ctx, &authDomain.AuditEvent{
+			UserID:         &actorID,
+			OrganizationID: &orgID,
+			Action:         "member.removed",
+			Resource:       "organization_member",
+		})
+
+		require.Len(t, repo.logs, 1)
+		assert.Equal(t, &actorID, repo.logs[0].UserID)
+		assert.Equal(t, "{}", repo.logs[0].Metadata)
+		assert.Nil(t, repo.logs[0].Changes)
+	})
+
+	t.Run("unknown project still records the event", func(t *testing.T) {
+		repo := &memoryAuditLogRepository{}
+		service := newTestAuditLogService(repo)
+		missing := ulid.New()
+
+		service.Record(context.Background(), &authDomain.AuditEvent{ProjectID: &missing, Action: "trace.deleted", Resource: "trace"})
+
+		require.Len(t, repo.logs, 1)
+		assert.Nil(t, repo.logs[0].OrganizationID)
+		assert.Nil(t, repo.logs[0].UserID)
+	})
+}
+
+func TestAuditLogService_Export(t *testing.T) {
+	orgID := ulid.New()
+	repo := &memoryAuditLogRepository{}
+	for i := 0; i < auditExportBatchSize+2; i++ {
+		entry := authDomain.NewAuditLog(nil, &orgID, "prompt.labels_set", "prompt", "p-1", `{"labels": ["production"]}`, "", "")
+		entry.Changes = json.RawMessage(`{"production": {"before": 1, "after": 2}}`)
+		repo.logs = append(repo.logs, entry)
+	}
+	service := newTestAuditLogService(repo)
+	filters := &authDomain.AuditLogFilters{OrganizationID: &orgID}
+
+	t.Run("csv", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, service.ExportAuditLogs(context.Background(), filters, authDomain.AuditExportCSV, &out))
+
+		records, err := csv.NewReader(&out).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, len(repo.logs)+1)
+		assert.Equal(t, auditCSVHeader, records[0])
+		assert.Equal(t, repo.logs[0].ID.String(), records[1][0])
+		assert.Equal(t, orgID.String(), records[1][5])
+		assert.Equal(t, `{"production": {"before": 1, "after": 2}}`, records[1][11])
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, service.ExportAuditLogs(context.Background(), filters, authDomain.AuditExportJSON, &out))
+
+		var entries []map[string]interface{}
+		require.NoError(t, json.Unmarshal(out.Bytes(), &entries))
+		require.Len(t, entries, len(repo.logs))
+		assert.Equal(t, repo.logs[len(repo.logs)-1].ID.String(), entries[len(entries)-1]["id"])
+		assert.Equal(t, map[string]interface{}{"labels": []interface{}{"production"}}, entries[0]["metadata"])
+		assert.Contains(t, entries[0], "changes")
+	})
+
+	t.Run("empty json export is an empty array", func(t *testing.T) {
+		var out bytes.Buffer
+		empty := newTestAuditLogService(&memoryAuditLogRepository{})
+		require.NoError(t, empty.ExportAuditLogs(context.Background(), filters, authDomain.AuditExportJSON, &out))
+		assert.JSONEq(t, `[]`, out.String())
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		err := service.ExportAuditLogs(context.Background(), filters, authDomain.AuditExportFormat("xml"), io.Discard)
+		assert.True(t, isAppErrorType(err, appErrors.ValidationError))
+	})
+}
@@ -13,7 +13,9 @@ import (
 
 	"brokle/internal/config"
 	authDomain "brokle/internal/core/domain/auth"
+	orgDomain "brokle/internal/core/domain/organization"
 	userDomain "brokle/internal/core/domain/user"
+	"brokle/pkg/encryption"
 	appErrors "brokle/pkg/errors"
 	"brokle/pkg/ulid"
 )
@@ -27,7 +29,12 @@ type authService struct {
 	roleService       authDomain.RoleService
 	passwordResetRepo authDomain.PasswordResetTokenRepository
 	blacklistedTokens authDomain.BlacklistedTokenService
-	redis             *redis.Client // For OAuth session storage
+	redis             *redis.Client // For OAuth session and MFA challenge storage
+	mfaRepo           authDomain.MFARepository
+	orgMemberRepo     authDomain.OrganizationMemberRepository
+	orgSettingsRepo   orgDomain.OrganizationSettingsRepository
+	encryptor         *encryption.Service // Encrypts TOTP secrets at rest; MFA is unavailable when nil
+	ssoRepo           authDomain.SSOConnectionRepository
 }
 
 // NewAuthService creates a new auth service instance
@@ -40,6 +47,11 @@ func NewAuthService(
 	passwordResetRepo authDomain.PasswordResetTokenRepository,
 	blacklistedTokens authDomain.BlacklistedTokenService,
 	redisClient *redis.Client,
+	mfaRepo authDomain.MFARepository,
+	orgMemberRepo authDomain.OrganizationMemberRepository,
+	orgSettingsRepo orgDomain.OrganizationSettingsRepository,
+	encryptor *encryption.Service,
+	ssoRepo authDomain.SSOConnectionRepository,
 ) authDomain.AuthService {
 	return &authService{
 		authConfig:        authConfig,
@@ -50,6 +62,11 @@ func NewAuthService(
 		passwordResetRepo: passwordResetRepo,
 		blacklistedTokens: blacklistedTokens,
 		redis:             redisClient,
+		mfaRepo:           mfaRepo,
+		orgMemberRepo:     orgMemberRepo,
+		orgSettingsRepo:   orgSettingsRepo,
+		encryptor:         encryptor,
+		ssoRepo:           ssoRepo,
 	}
 }
 
@@ -69,6 +86,11 @@ func (s *authService) Login(ctx context.Context, req *authDomain.LoginRequest) (
 		return nil, appErrors.NewForbiddenError("Account is inactive")
 	}
 
+	// Organizations enforcing SSO own their domain's logins
+	if err := s.checkPasswordLoginAllowed(ctx, user.Email); err != nil {
+		return nil, err
+	}
+
 	// Block OAuth users from password login
 	if user.AuthMethod == "oauth" {
 		providerName := "OAuth"
@@ -90,6 +112,20 @@ func (s *authService) Login(ctx context.Context, req *authDomain.LoginRequest) (
 		}
 	}
 
+	// Hold the tokens back until the second factor is verified
+	challenge, err := s.mfaChallenge(ctx, user, req.DeviceInfo)
+	if err != nil {
+		return nil, err
+	}
+	if challenge != nil {
+		return challenge, nil
+	}
+
+	return s.issueTokens(ctx, user, req.DeviceInfo)
+}
+
+// issueTokens creates a session for an authenticated user and returns its tokens
+func (s *authService) issueTokens(ctx context.Context, user *userDomain.User, deviceInfo map[string]interface{}) (*authDomain.LoginResponse, error) {
 	// Get user effective permissions across all scopes
 	// Note: Permissions are now handled by OrganizationMemberService
 	permissions := []string{}
@@ -121,7 +157,7 @@ func (s *authService) Login(ctx context.Context, req *authDomain.LoginRequest) (
 	// TODO: Extract from request context when available
 
 	// Create secure session (NO ACCESS TOKEN STORED)
-	session := authDomain.NewUserSession(user.ID, refreshTokenHash, jti, expiresAt, refreshExpiresAt, ipAddress, userAgent, req.DeviceInfo)
+	session := authDomain.NewUserSession(user.ID, refreshTokenHash, jti, expiresAt, refreshExpiresAt, ipAddress, userAgent, deviceInfo)
 	err = s.sessionRepo.Create(ctx, session)
 	if err != nil {
 		return nil, appErrors.NewInternalError("Failed to create session", err)
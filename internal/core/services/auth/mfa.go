@@ -0,0 +1,560 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	authDomain "brokle/internal/core/domain/auth"
+	orgDomain "brokle/internal/core/domain/organization"
+	userDomain "brokle/internal/core/domain/user"
+	appErrors "brokle/pkg/errors"
+	"brokle/pkg/totp"
+	"brokle/pkg/ulid"
+)
+
+// MFAChallenge is a login that passed the first factor and awaits a TOTP or recovery code
+type MFAChallenge struct {
+	DeviceInfo map[string]interface{} `json:"device_info,omitempty"`
+	UserID     ulid.ULID              `json:"user_id"`
+}
+
+func mfaChallengeKey(token string) string {
+	return "auth:mfa:challenge:" + token
+}
+
+func mfaAttemptsKey(token string) string {
+	return "auth:mfa:attempts:" + token
+}
+
+// mfaAttemptScript counts a code attempt against a challenge atomically, so concurrent
+// guesses cannot share one attempt. KEYS: challenge, attempts. The counter expires with
+// the challenge. Returns the attempt number, or 0 when the challenge is gone.
+var mfaAttemptScript = redis.NewScript(`
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl == -2 then
+	return 0
+end
+local attempts = redis.call('INCR', KEYS[2])
+if attempts == 1 and ttl > 0 then
+	redis.call('PEXPIRE', KEYS[2], ttl)
+end
+return attempts
+`)
+
+// CreateMFAChallenge starts the second login step for users with MFA enabled, or in an
+// organization that requires it. Returns nil when tokens can be issued right away.
+func (s *authService) CreateMFAChallenge(ctx context.Context, userID ulid.ULID, deviceInfo map[string]interface{}) (*authDomain.LoginResponse, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.mfaChallenge(ctx, user, deviceInfo)
+}
+
+func (s *authService) mfaChallenge(ctx context.Context, user *userDomain.User, deviceInfo map[string]interface{}) (*authDomain.LoginResponse, error) {
+	mfa, err := s.getMFA(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled := mfa != nil && mfa.Enabled
+	resp := &authDomain.LoginResponse{MFARequired: true}
+	if !enabled {
+		required, err := s.mfaRequired(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !required {
+			return nil, nil
+		}
+
+		// Enrolment is completed by the code that answers the challenge
+		enrollment, err := s.startEnrollment(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		resp.MFAEnrollment = enrollment
+	}
+
+	token := ulid.New().String()
+	data, err := json.Marshal(&MFAChallenge{UserID: user.ID, DeviceInfo: deviceInfo})
+	if err != nil {
+		return nil, appErrors.NewInternalError("Failed to marshal MFA challenge", err)
+	}
+	if err := s.redis.Set(ctx, mfaChallengeKey(token), data, authDomain.MFAChallengeTTL).Err(); err != nil {
+		return nil, appErrors.NewInternalError("Failed to store MFA challenge", err)
+	}
+
+	resp.MFAToken = token
+	return resp, nil
+}
+
+// GetMFAChallenge describes a pending challenge, including the enrolment when one is required.
+// OAuth logins redirect with the MFA token only, so secrets never appear in URLs.
+func (s *authService) GetMFAChallenge(ctx context.Context, mfaToken string) (*authDomain.LoginResponse, error) {
+	challenge, err := s.loadMFAChallenge(ctx, mfaToken)
+	if err != nil {
+		return nil, err
+	}
+	mfa, err := s.getMFA(ctx, challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa == nil {
+		return nil, appErrors.NewUnauthorizedError("MFA challenge expired or invalid")
+	}
+
+	resp := &authDomain.LoginResponse{MFARequired: true, MFAToken: mfaToken}
+	if !mfa.Enabled {
+		if s.encryptor == nil {
+			return nil, appErrors.NewServiceUnavailableError("MFA is not configured on this server")
+		}
+		secret, err := s.encryptor.Decrypt(mfa.SecretEncrypted)
+		if err != nil {
+			return nil, appErrors.NewInternalError("Failed to decrypt MFA secret", err)
+		}
+		user, err := s.getUser(ctx, challenge.UserID)
+		if err != nil {
+			return nil, err
+		}
+		resp.MFAEnrollment = &authDomain.MFAEnrollment{
+			Secret:          secret,
+			ProvisioningURI: totp.ProvisioningURI(authDomain.MFAEnrollmentIssuer, user.Email, secret),
+		}
+	}
+	return resp, nil
+}
+
+// VerifyMFAChallenge completes a login with a TOTP or recovery code and issues tokens
+func (s *authService) VerifyMFAChallenge(ctx context.Context, req *authDomain.MFAChallengeRequest) (*authDomain.LoginResponse, error) {
+	key, attemptsKey := mfaChallengeKey(req.MFAToken), mfaAttemptsKey(req.MFAToken)
+	challenge, err := s.loadMFAChallenge(ctx, req.MFAToken)
+	if err != nil {
+		return nil, err
+	}
+
+	mfa, err := s.getMFA(ctx, challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa == nil {
+		// Reset by an admin while the challenge was pending
+		s.redis.Del(ctx, key, attemptsKey)
+		return nil, appErrors.NewUnauthorizedError("MFA challenge expired or invalid")
+	}
+
+	// Count the attempt before checking the code, so parallel guesses each use one up
+	attempts, err := mfaAttemptScript.Run(ctx, s.redis, []string{key, attemptsKey}).Int()
+	if err != nil {
+		return nil, appErrors.NewInternalError("Failed to record MFA attempt", err)
+	}
+	if attempts == 0 {
+		return nil, appErrors.NewUnauthorizedError("MFA challenge expired or invalid")
+	}
+	if attempts > authDomain.MFAChallengeMaxAttempts {
+		s.redis.Del(ctx, key, attemptsKey)
+		return nil, appErrors.NewUnauthorizedError("Too many invalid codes - please log in again")
+	}
+
+	// Recovery codes only exist once enrolment is complete
+	ok, err := s.verifyMFACode(ctx, mfa, req.Code, mfa.Enabled)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if attempts >= authDomain.MFAChallengeMaxAttempts {
+			s.redis.Del(ctx, key, attemptsKey)
+			return nil, appErrors.NewUnauthorizedError("Too many invalid codes - please log in again")
+		}
+		return nil, appErrors.NewUnauthorizedError("Invalid authentication code")
+	}
+
+	// Single use, whatever happens next; only the request that deletes it may log in
+	deleted, err := s.redis.Del(ctx, key).Result()
+	if err != nil {
+		return nil, appErrors.NewInternalError("Failed to consume MFA challenge", err)
+	}
+	s.redis.Del(ctx, attemptsKey)
+	if deleted == 0 {
+		return nil, appErrors.NewUnauthorizedError("MFA challenge expired or invalid")
+	}
+
+	var recoveryCodes []string
+	if !mfa.Enabled {
+		if recoveryCodes, err = s.enableMFA(ctx, mfa); err != nil {
+			return nil, err
+		}
+	}
+
+	user, err := s.getUser(ctx, challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, appErrors.NewForbiddenError("Account is inactive")
+	}
+
+	resp, err := s.issueTokens(ctx, user, challenge.DeviceInfo)
+	if err != nil {
+		return nil, err
+	}
+	resp.RecoveryCodes = recoveryCodes
+	return resp, nil
+}
+
+// GetMFAChallengeUserID returns the user a pending challenge belongs to
+func (s *authService) GetMFAChallengeUserID(ctx context.Context, mfaToken string) (ulid.ULID, error) {
+	challenge, err := s.loadMFAChallenge(ctx, mfaToken)
+	if err != nil {
+		return ulid.ULID{}, err
+	}
+	return challenge.UserID, nil
+}
+
+func (s *authService) loadMFAChallenge(ctx context.Context, token string) (*MFAChallenge, error) {
+	data, err := s.redis.Get(ctx, mfaChallengeKey(token)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, appErrors.NewUnauthorizedError("MFA challenge expired or invalid")
+		}
+		return nil, appErrors.NewInternalError("Failed to load MFA challenge", err)
+	}
+
+	var challenge MFAChallenge
+	if err := json.Unmarshal([]byte(data), &challenge); err != nil {
+		return nil, appErrors.NewInternalError("Failed to unmarshal MFA challenge", err)
+	}
+	return &challenge, nil
+}
+
+// GetMFAStatus returns whether the user has MFA enabled and whether it is required
+func (s *authService) GetMFAStatus(ctx context.Context, userID ulid.ULID) (*authDomain.MFAStatus, error) {
+	mfa, err := s.getMFA(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	required, err := s.mfaRequired(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &authDomain.MFAStatus{Required: required}
+	if mfa != nil && mfa.Enabled {
+		status.Enabled = true
+		status.EnabledAt = mfa.EnabledAt
+		status.RecoveryCodesRemaining = len(mfa.RecoveryCodeHashes)
+	}
+	return status, nil
+}
+
+// BeginMFAEnrollment generates a new TOTP secret; it takes effect once a code is confirmed
+func (s *authService) BeginMFAEnrollment(ctx context.Context, userID ulid.ULID) (*authDomain.MFAEnrollment, error) {
+	mfa, err := s.getMFA(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa != nil && mfa.Enabled {
+		return nil, appErrors.NewConflictError("MFA is already enabled")
+	}
+
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.startEnrollment(ctx, user)
+}
+
+// ConfirmMFAEnrollment enables MFA with the first code from the authenticator app
+func (s *authService) ConfirmMFAEnrollment(ctx context.Context, userID ulid.ULID, code string) (*authDomain.MFARecoveryCodes, error) {
+	mfa, err := s.getMFA(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa == nil {
+		return nil, appErrors.NewNotFoundError("MFA enrollment")
+	}
+	if mfa.Enabled {
+		return nil, appErrors.NewConflictError("MFA is already enabled")
+	}
+
+	ok, err := s.verifyMFACode(ctx, mfa, code, false)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, appErrors.NewValidationError("Invalid authentication code", "code")
+	}
+
+	codes, err := s.enableMFA(ctx, mfa)
+	if err != nil {
+		return nil, err
+	}
+	return &authDomain.MFARecoveryCodes{RecoveryCodes: codes}, nil
+}
+
+// RegenerateMFARecoveryCodes replaces all recovery codes after verifying a TOTP code
+func (s *authService) RegenerateMFARecoveryCodes(ctx context.Context, userID ulid.ULID, code string) (*authDomain.MFARecoveryCodes, error) {
+	mfa, err := s.getEnabledMFA(ctx, userID, code)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	mfa.RecoveryCodeHashes = hashes
+	if err := s.mfaRepo.Save(ctx, mfa); err != nil {
+		return nil, appErrors.NewInternalError("Failed to save recovery codes", err)
+	}
+	return &authDomain.MFARecoveryCodes{RecoveryCodes: codes}, nil
+}
+
+// DisableMFA removes the user's second factor unless one of their organizations requires it
+func (s *authService) DisableMFA(ctx context.Context, userID ulid.ULID, code string) error {
+	if _, err := s.getEnabledMFA(ctx, userID, code); err != nil {
+		return err
+	}
+
+	required, err := s.mfaRequired(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if required {
+		return appErrors.NewForbiddenError("MFA is required by your organization")
+	}
+
+	if err := s.mfaRepo.Delete(ctx, userID); err != nil {
+		return appErrors.NewInternalError("Failed to disable MFA", err)
+	}
+	return nil
+}
+
+// ResetMFA removes a member's second factor so they can enrol again at their next login.
+// The admin needs members:update in the organization the user belongs to.
+func (s *authService) ResetMFA(ctx context.Context, adminID, orgID, userID ulid.ULID) error {
+	// The route check accepts members:update from any organization
+	permissions, err := s.orgMemberRepo.GetUserPermissionsInOrganization(ctx, adminID, orgID)
+	if err != nil {
+		return appErrors.NewInternalError("Failed to check organization permissions", err)
+	}
+	if !slices.Contains(permissions, "members:update") {
+		return appErrors.NewForbiddenError("Insufficient permissions in this organization")
+	}
+
+	member, err := s.orgMemberRepo.Exists(ctx, userID, orgID)
+	if err != nil {
+		return appErrors.NewInternalError("Failed to check membership", err)
+	}
+	if !member {
+		return appErrors.NewNotFoundError("Organization member")
+	}
+
+	mfa, err := s.getMFA(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if mfa == nil {
+		return appErrors.NewNotFoundError("MFA enrollment")
+	}
+
+	if err := s.mfaRepo.Delete(ctx, userID); err != nil {
+		return appErrors.NewInternalError("Failed to reset MFA", err)
+	}
+
+	// Sessions opened with the lost device end with it
+	if err := s.RevokeAllSessions(ctx, userID); err != nil {
+		return err
+	}
+	return nil
+}
+
+// startEnrollment stores a pending enrolment with a fresh secret, replacing any earlier one
+func (s *authService) startEnrollment(ctx context.Context, user *userDomain.User) (*authDomain.MFAEnrollment, error) {
+	if s.encryptor == nil {
+		return nil, appErrors.NewServiceUnavailableError("MFA is not configured on this server")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, appErrors.NewInternalError("Failed to generate MFA secret", err)
+	}
+	encrypted, err := s.encryptor.Encrypt(secret)
+	if err != nil {
+		return nil, appErrors.NewInternalError("Failed to encrypt MFA secret", err)
+	}
+
+	mfa := &authDomain.UserMFA{
+		UserID:             user.ID,
+		SecretEncrypted:    encrypted,
+		RecoveryCodeHashes: []string{},
+	}
+	if err := s.mfaRepo.Save(ctx, mfa); err != nil {
+		return nil, appErrors.NewInternalError("Failed to save MFA enrollment", err)
+	}
+
+	return &authDomain.MFAEnrollment{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(authDomain.MFAEnrollmentIssuer, user.Email, secret),
+	}, nil
+}
+
+// enableMFA completes a pending enrolment and returns its recovery codes
+func (s *authService) enableMFA(ctx context.Context, mfa *authDomain.UserMFA) ([]string, error) {
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	mfa.Enabled = true
+	mfa.EnabledAt = &now
+	mfa.RecoveryCodeHashes = hashes
+	if err := s.mfaRepo.Save(ctx, mfa); err != nil {
+		return nil, appErrors.NewInternalError("Failed to enable MFA", err)
+	}
+	return codes, nil
+}
+
+// getEnabledMFA returns the user's enabled MFA after verifying a current TOTP code
+func (s *authService) getEnabledMFA(ctx context.Context, userID ulid.ULID, code string) (*authDomain.UserMFA, error) {
+	mfa, err := s.getMFA(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa == nil || !mfa.Enabled {
+		return nil, appErrors.NewNotFoundError("MFA enrollment")
+	}
+
+	ok, err := s.verifyMFACode(ctx, mfa, code, false)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, appErrors.NewValidationError("Invalid authentication code", "code")
+	}
+	return mfa, nil
+}
+
+// verifyMFACode accepts a TOTP code not used before, or an unused recovery code when allowed.
+// Accepted codes are persisted as used.
+func (s *authService) verifyMFACode(ctx context.Context, mfa *authDomain.UserMFA, code string, allowRecovery bool) (bool, error) {
+	if s.encryptor == nil {
+		return false, appErrors.NewServiceUnavailableError("MFA is not configured on this server")
+	}
+	secret, err := s.encryptor.Decrypt(mfa.SecretEncrypted)
+	if err != nil {
+		return false, appErrors.NewInternalError("Failed to decrypt MFA secret", err)
+	}
+
+	if !acceptTOTP(mfa, secret, code, time.Now()) && !(allowRecovery && consumeRecoveryCode(mfa, code)) {
+		return false, nil
+	}
+
+	if err := s.mfaRepo.Save(ctx, mfa); err != nil {
+		return false, appErrors.NewInternalError("Failed to save MFA state", err)
+	}
+	return true, nil
+}
+
+// mfaRequired reports whether any organization the user is an active member of requires MFA
+func (s *authService) mfaRequired(ctx context.Context, userID ulid.ULID) (bool, error) {
+	members, err := s.orgMemberRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return false, appErrors.NewInternalError("Failed to get organization memberships", err)
+	}
+
+	for _, member := range members {
+		if !member.IsActive() {
+			continue
+		}
+		setting, err := s.orgSettingsRepo.GetByKey(ctx, member.OrganizationID, orgDomain.SettingRequireMFA)
+		if err != nil {
+			if errors.Is(err, orgDomain.ErrSettingsNotFound) {
+				continue
+			}
+			return false, appErrors.NewInternalError("Failed to get organization MFA policy", err)
+		}
+		if value, err := setting.GetValue(); err == nil && value == true {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *authService) getMFA(ctx context.Context, userID ulid.ULID) (*authDomain.UserMFA, error) {
+	mfa, err := s.mfaRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, authDomain.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, appErrors.NewInternalError("Failed to get MFA enrollment", err)
+	}
+	return mfa, nil
+}
+
+func (s *authService) getUser(ctx context.Context, userID ulid.ULID) (*userDomain.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, userDomain.ErrNotFound) {
+			return nil, appErrors.NewNotFoundError("User not found")
+		}
+		return nil, appErrors.NewInternalError("User lookup failed", err)
+	}
+	return user, nil
+}
+
+// acceptTOTP validates a code and records its step, so the same code cannot be replayed
+func acceptTOTP(mfa *authDomain.UserMFA, secret, code string, now time.Time) bool {
+	step, ok := totp.Validate(secret, code, now, authDomain.MFAValidationSkew)
+	if !ok || step <= mfa.LastUsedStep {
+		return false
+	}
+	mfa.LastUsedStep = step
+	return true
+}
+
+// consumeRecoveryCode removes a matching recovery code
+func consumeRecoveryCode(mfa *authDomain.UserMFA, code string) bool {
+	hash := hashRecoveryCode(code)
+	for i, stored := range mfa.RecoveryCodeHashes {
+		if subtle.ConstantTimeCompare([]byte(stored), []byte(hash)) == 1 {
+			mfa.RecoveryCodeHashes = append(mfa.RecoveryCodeHashes[:i:i], mfa.RecoveryCodeHashes[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// generateRecoveryCodes returns codes formatted as xxxxx-xxxxx and their hashes
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, authDomain.MFARecoveryCodeCount)
+	hashes := make([]string, authDomain.MFARecoveryCodeCount)
+	for i := range codes {
+		raw := make([]byte, 5)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, appErrors.NewInternalError("Failed to generate recovery codes", err)
+		}
+		encoded := hex.EncodeToString(raw)
+		codes[i] = encoded[:5] + "-" + encoded[5:]
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode ignores case, spaces and dashes so codes can be typed loosely
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(code)))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,300 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authDomain "brokle/internal/core/domain/auth"
+	orgDomain "brokle/internal/core/domain/organization"
+	userDomain "brokle/internal/core/domain/user"
+	"brokle/pkg/encryption"
+	appErrors "brokle/pkg/errors"
+	"brokle/pkg/totp"
+	"brokle/pkg/ulid"
+)
+
+type memoryMFARepository struct {
+	records map[ulid.ULID]authDomain.UserMFA
+}
+
+func (r *memoryMFARepository) GetByUserID(ctx context.Context, userID ulid.ULID) (*authDomain.UserMFA, error) {
+	mfa, ok := r.records[userID]
+	if !ok {
+		return nil, fmt.Errorf("get mfa: %w", authDomain.ErrNotFound)
+	}
+	return &mfa, nil
+}
+
+func (r *memoryMFARepository) Save(ctx context.Context, mfa *authDomain.UserMFA) error {
+	r.records[mfa.UserID] = *mfa
+	return nil
+}
+
+func (r *memoryMFARepository) Delete(ctx context.Context, userID ulid.ULID) error {
+	delete(r.records, userID)
+	return nil
+}
+
+// mfaUserRepository serves a single user; other Repository methods are not used
+type mfaUserRepository struct {
+	userDomain.Repository
+	user *userDomain.User
+}
+
+func (r *mfaUserRepository) GetByID(ctx context.Context, id ulid.ULID) (*userDomain.User, error) {
+	if id != r.user.ID {
+		return nil, userDomain.ErrNotFound
+	}
+	return r.user, nil
+}
+
+type mfaMemberRepository struct {
+	authDomain.OrganizationMemberRepository
+	members     []*authDomain.OrganizationMember
+	permissions map[ulid.ULID][]string // Granted to the user in each organization they belong to
+}
+
+func (r *mfaMemberRepository) GetByUserID(ctx context.Context, userID ulid.ULID) ([]*authDomain.OrganizationMember, error) {
+	return r.members, nil
+}
+
+func (r *mfaMemberRepository) Exists(ctx context.Context, userID, orgID ulid.ULID) (bool, error) {
+	for _, member := range r.members {
+		if member.UserID == userID && member.OrganizationID == orgID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *mfaMemberRepository) GetUserPermissionsInOrganization(ctx context.Context, userID, orgID ulid.ULID) ([]string, error) {
+	if member, _ := r.Exists(ctx, userID, orgID); !member {
+		return nil, nil
+	}
+	return r.permissions[userID], nil
+}
+
+type mfaSessionRepository struct {
+	authDomain.UserSessionRepository
+	revoked []ulid.ULID
+}
+
+func (r *mfaSessionRepository) RevokeUserSessions(ctx context.Context, userID ulid.ULID) error {
+	r.revoked = append(r.revoked, userID)
+	return nil
+}
+
+type mfaSettingsRepository struct {
+	orgDomain.OrganizationSettingsRepository
+	requireMFA map[ulid.ULID]bool
+}
+
+func (r *mfaSettingsRepository) GetByKey(ctx context.Context, orgID ulid.ULID, key string) (*orgDomain.OrganizationSettings, error) {
+	required, ok := r.requireMFA[orgID]
+	if !ok || key != orgDomain.SettingRequireMFA {
+		return nil, fmt.Errorf("get setting %s: %w", key, orgDomain.ErrSettingsNotFound)
+	}
+	return orgDomain.NewOrganizationSettings(orgID, key, required)
+}
+
+type mfaFixture struct {
+	service  *authService
+	repo     *memoryMFARepository
+	settings *mfaSettingsRepository
+	user     *userDomain.User
+	orgID    ulid.ULID
+}
+
+func newMFAFixture(t *testing.T) *mfaFixture {
+	encryptor, err := encryption.NewService(make([]byte, 32))
+	require.NoError(t, err)
+
+	user := &userDomain.User{ID: ulid.New(), Email: "jane@example.com", IsActive: true}
+	orgID := ulid.New()
+	f := &mfaFixture{
+		repo:     &memoryMFARepository{records: map[ulid.ULID]authDomain.UserMFA{}},
+		settings: &mfaSettingsRepository{requireMFA: map[ulid.ULID]bool{}},
+		user:     user,
+		orgID:    orgID,
+	}
+	members := &mfaMemberRepository{members: []*authDomain.OrganizationMember{
+		{UserID: user.ID, OrganizationID: orgID, Status: authDomain.MemberStatusActive},
+	}}
+	f.service = &authService{
+		userRepo:        &mfaUserRepository{user: user},
+		mfaRepo:         f.repo,
+		orgMemberRepo:   members,
+		orgSettingsRepo: f.settings,
+		encryptor:       encryptor,
+	}
+	return f
+}
+
+// enroll completes enrolment and returns the secret and recovery codes
+func (f *mfaFixture) enroll(t *testing.T) (string, []string) {
+	ctx := context.Background()
+	enrollment, err := f.service.BeginMFAEnrollment(ctx, f.user.ID)
+	require.NoError(t, err)
+	assert.Contains(t, enrollment.ProvisioningURI, "secret="+enrollment.Secret)
+
+	code, err := totp.Code(enrollment.Secret, totp.Step(time.Now()))
+	require.NoError(t, err)
+	codes, err := f.service.ConfirmMFAEnrollment(ctx, f.user.ID, code)
+	require.NoError(t, err)
+	return enrollment.Secret, codes.RecoveryCodes
+}
+
+func TestAuthService_MFAEnrollment(t *testing.T) {
+	ctx := context.Background()
+	f := newMFAFixture(t)
+
+	secret, recoveryCodes := f.enroll(t)
+	assert.Len(t, recoveryCodes, authDomain.MFARecoveryCodeCount)
+
+	stored := f.repo.records[f.user.ID]
+	assert.True(t, stored.Enabled)
+	assert.NotContains(t, stored.SecretEncrypted, secret)
+	assert.NotContains(t, stored.RecoveryCodeHashes, recoveryCodes[0])
+
+	status, err := f.service.GetMFAStatus(ctx, f.user.ID)
+	require.NoError(t, err)
+	assert.True(t, status.Enabled)
+	assert.False(t, status.Required)
+	assert.Equal(t, authDomain.MFARecoveryCodeCount, status.RecoveryCodesRemaining)
+
+	_, err = f.service.BeginMFAEnrollment(ctx, f.user.ID)
+	assert.True(t, isAppErrorType(err, appErrors.ConflictError))
+}
+
+func TestAuthService_MFACodes(t *testing.T) {
+	f := newMFAFixture(t)
+	secret, recoveryCodes := f.enroll(t)
+	mfa := f.repo.records[f.user.ID]
+	now := time.Now()
+
+	t.Run("totp codes are single use", func(t *testing.T) {
+		// The enrolment code already used the current step
+		code, _ := totp.Code(secret, totp.Step(now))
+		assert.False(t, acceptTOTP(&mfa, secret, code, now))
+
+		later := now.Add(totp.Period)
+		code, _ = totp.Code(secret, totp.Step(later))
+		assert.True(t, acceptTOTP(&mfa, secret, code, later))
+		assert.False(t, acceptTOTP(&mfa, secret, code, later))
+	})
+
+	t.Run("recovery codes are single use", func(t *testing.T) {
+		loose := " " + recoveryCodes[3][:5] + recoveryCodes[3][6:] + " "
+		assert.True(t, consumeRecoveryCode(&mfa, loose))
+		assert.False(t, consumeRecoveryCode(&mfa, recoveryCodes[3]))
+		assert.Len(t, mfa.RecoveryCodeHashes, authDomain.MFARecoveryCodeCount-1)
+		assert.False(t, consumeRecoveryCode(&mfa, "00000-00000"))
+	})
+}
+
+func TestAuthService_DisableMFA(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("required by organization", func(t *testing.T) {
+		f := newMFAFixture(t)
+		secret, _ := f.enroll(t)
+		f.settings.requireMFA[f.orgID] = true
+
+		code, _ := totp.Code(secret, totp.Step(time.Now())+1)
+		err := f.service.DisableMFA(ctx, f.user.ID, code)
+		assert.True(t, isAppErrorType(err, appErrors.ForbiddenError))
+		assert.Contains(t, f.repo.records, f.user.ID)
+	})
+
+	t.Run("invalid code", func(t *testing.T) {
+		f := newMFAFixture(t)
+		f.enroll(t)
+
+		err := f.service.DisableMFA(ctx, f.user.ID, "000000")
+		assert.True(t, isAppErrorType(err, appErrors.ValidationError))
+		assert.Contains(t, f.repo.records, f.user.ID)
+	})
+
+	t.Run("not required", func(t *testing.T) {
+		f := newMFAFixture(t)
+		secret, _ := f.enroll(t)
+		f.settings.requireMFA[f.orgID] = false
+
+		code, _ := totp.Code(secret, totp.Step(time.Now())+1)
+		require.NoError(t, f.service.DisableMFA(ctx, f.user.ID, code))
+		assert.NotContains(t, f.repo.records, f.user.ID)
+	})
+}
+
+func TestAuthService_ResetMFA(t *testing.T) {
+	ctx := context.Background()
+	f := newMFAFixture(t)
+	f.enroll(t)
+
+	members := f.service.orgMemberRepo.(*mfaMemberRepository)
+	sessions := &mfaSessionRepository{}
+	f.service.sessionRepo = sessions
+	adminID, outsiderID, otherOrgID := ulid.New(), ulid.New(), ulid.New()
+	members.members = append(members.members,
+		&authDomain.OrganizationMember{UserID: adminID, OrganizationID: f.orgID, Status: authDomain.MemberStatusActive},
+		&authDomain.OrganizationMember{UserID: adminID, OrganizationID: otherOrgID, Status: authDomain.MemberStatusActive},
+		&authDomain.OrganizationMember{UserID: outsiderID, OrganizationID: otherOrgID, Status: authDomain.MemberStatusActive},
+	)
+	members.permissions = map[ulid.ULID][]string{
+		adminID:    {"members:update"},
+		outsiderID: {"members:update"},
+	}
+
+	err := f.service.ResetMFA(ctx, outsiderID, f.orgID, f.user.ID)
+	assert.True(t, isAppErrorType(err, appErrors.ForbiddenError), "members:update must be held in the user's organization")
+	assert.Contains(t, f.repo.records, f.user.ID)
+
+	err = f.service.ResetMFA(ctx, adminID, otherOrgID, f.user.ID)
+	assert.True(t, appErrors.IsNotFound(err), "only members of the admin's organization can be reset")
+	assert.Contains(t, f.repo.records, f.user.ID)
+
+	require.NoError(t, f.service.ResetMFA(ctx, adminID, f.orgID, f.user.ID))
+	assert.NotContains(t, f.repo.records, f.user.ID)
+	assert.Equal(t, []ulid.ULID{f.user.ID}, sessions.revoked)
+}
+
+func isAppErrorType(err error, errorType appErrors.AppErrorType) bool {
+	appErr, ok := appErrors.IsAppError(err)
+	return ok && appErr.Type == errorType
+}
+
+// failingMFAAuthService rejects every code for a challenge owned by userID
+type failingMFAAuthService struct {
+	authDomain.AuthService
+	userID ulid.ULID
+}
+
+func (s *failingMFAAuthService) GetMFAChallengeUserID(ctx context.Context, mfaToken string) (ulid.ULID, error) {
+	return s.userID, nil
+}
+
+func (s *failingMFAAuthService) VerifyMFAChallenge(ctx context.Context, req *authDomain.MFAChallengeRequest) (*authDomain.LoginResponse, error) {
+	return nil, appErrors.NewUnauthorizedError("Invalid authentication code")
+}
+
+func TestAuditDecorator_VerifyMFAChallengeFailure(t *testing.T) {
+	userID := ulid.New()
+	repo := &memoryAuditLogRepository{}
+	decorator := NewAuditDecorator(&failingMFAAuthService{userID: userID}, repo, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	_, err := decorator.VerifyMFAChallenge(context.Background(), &authDomain.MFAChallengeRequest{MFAToken: "token", Code: "000000"})
+	require.Error(t, err)
+
+	require.Len(t, repo.logs, 1)
+	assert.Equal(t, "auth.mfa.verify.failed", repo.logs[0].Action)
+	require.NotNil(t, repo.logs[0].UserID)
+	assert.Equal(t, userID, *repo.logs[0].UserID)
+	assert.Equal(t, userID.String(), repo.logs[0].ResourceID)
+}
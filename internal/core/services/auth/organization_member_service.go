@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"slices"
 
 	authDomain "brokle/internal/core/domain/auth"
 	appErrors "brokle/pkg/errors"
@@ -132,6 +133,20 @@ func (s *organizationMemberService) CheckUserPermissions(ctx context.Context, us
 	return s.orgMemberRepo.CheckUserPermissions(ctx, userID, permissions)
 }
 
+// RequireOrganizationPermission checks the permission within one organization. Route
+// permission checks accept a permission held in any organization, so services acting
+// on an organization from the URL call this before reading or changing it.
+func (s *organizationMemberService) RequireOrganizationPermission(ctx context.Context, userID, orgID ulid.ULID, permission string) error {
+	permissions, err := s.orgMemberRepo.GetUserPermissionsInOrganization(ctx, userID, orgID)
+	if err != nil {
+		return appErrors.NewInternalError("Failed to check organization permissions", err)
+	}
+	if !slices.Contains(permissions, permission) {
+		return appErrors.NewForbiddenError("Insufficient permissions in this organization")
+	}
+	return nil
+}
+
 // ActivateMember activates a member in an organization
 func (s *organizationMemberService) ActivateMember(ctx context.Context, userID, orgID ulid.ULID) error {
 	return s.orgMemberRepo.ActivateMember(ctx, userID, orgID)
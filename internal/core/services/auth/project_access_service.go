@@ -0,0 +1,210 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	authDomain "brokle/internal/core/domain/auth"
+	orgDomain "brokle/internal/core/domain/organization"
+	appErrors "brokle/pkg/errors"
+	"brokle/pkg/ulid"
+)
+
+// ownerRoleName is the template role that keeps full access to every project
+const ownerRoleName = "owner"
+
+// projectAccessService implements authDomain.ProjectAccessService.
+//
+// A user acts in a project with exactly one role: organization owners always
+// keep the owner role, a project assignment otherwise replaces the organization
+// role, and without one the organization role applies unless the organization
+// only grants access to assigned projects. Project assignments only count for
+// active organization members.
+type projectAccessService struct {
+	projectRepo       orgDomain.ProjectRepository
+	memberRepo        orgDomain.MemberRepository
+	projectMemberRepo authDomain.ProjectMemberRepository
+	roleRepo          authDomain.RoleRepository
+	settingsRepo      orgDomain.OrganizationSettingsRepository
+}
+
+// NewProjectAccessService creates the project access service
+func NewProjectAccessService(
+	projectRepo orgDomain.ProjectRepository,
+	memberRepo orgDomain.MemberRepository,
+	projectMemberRepo authDomain.ProjectMemberRepository,
+	roleRepo authDomain.RoleRepository,
+	settingsRepo orgDomain.OrganizationSettingsRepository,
+) authDomain.ProjectAccessService {
+	return &projectAccessService{
+		projectRepo:       projectRepo,
+		memberRepo:        memberRepo,
+		projectMemberRepo: projectMemberRepo,
+		roleRepo:          roleRepo,
+		settingsRepo:      settingsRepo,
+	}
+}
+
+// GetProjectAccess resolves the role a user acts with in a project
+func (s *projectAccessService) GetProjectAccess(ctx context.Context, userID, projectID ulid.ULID) (*authDomain.ProjectAccess, error) {
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, orgDomain.ErrProjectNotFound) {
+			return nil, appErrors.NewNotFoundError("project")
+		}
+		return nil, appErrors.NewInternalError("Failed to get project", err)
+	}
+
+	access := &authDomain.ProjectAccess{
+		UserID:         userID,
+		ProjectID:      projectID,
+		OrganizationID: project.OrganizationID,
+		Source:         authDomain.PermissionSourceNone,
+		Permissions:    []string{},
+	}
+
+	orgRole, err := s.organizationRole(ctx, userID, project.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	if orgRole == nil {
+		return access, nil
+	}
+	if orgRole.Name == ownerRoleName && orgRole.ScopeID == nil {
+		grantRole(access, authDomain.PermissionSourceOwner, orgRole)
+		return access, nil
+	}
+
+	assignment, err := s.projectMemberRepo.GetByUserAndProject(ctx, userID, projectID)
+	if err != nil && !errors.Is(err, authDomain.ErrNotFound) {
+		return nil, appErrors.NewInternalError("Failed to get project role", err)
+	}
+	if assignment != nil && assignment.IsActive() && assignment.Role != nil {
+		grantRole(access, authDomain.PermissionSourceProjectRole, assignment.Role)
+		return access, nil
+	}
+
+	assignedOnly, err := s.assignedProjectsOnly(ctx, project.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	if assignedOnly {
+		access.Source = authDomain.PermissionSourceUnassigned
+		return access, nil
+	}
+
+	grantRole(access, authDomain.PermissionSourceOrganizationRole, orgRole)
+	return access, nil
+}
+
+// CheckUserProjectPermissions reports which of the permissions the user holds in the project
+func (s *projectAccessService) CheckUserProjectPermissions(ctx context.Context, userID, projectID ulid.ULID, permissions []string) (map[string]bool, error) {
+	access, err := s.GetProjectAccess(ctx, userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]bool, len(permissions))
+	for _, permission := range permissions {
+		_, result[permission] = access.Allows(permission)
+	}
+	return result, nil
+}
+
+// SimulatePermission explains whether the user holds a permission in the project
+func (s *projectAccessService) SimulatePermission(ctx context.Context, userID, projectID ulid.ULID, permission string) (*authDomain.PermissionSimulation, error) {
+	if err := authDomain.ValidateResourceAction(permission); err != nil {
+		return nil, appErrors.NewValidationError("Invalid permission", "permission must be in resource:action format")
+	}
+
+	access, err := s.GetProjectAccess(ctx, userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	simulation := &authDomain.PermissionSimulation{
+		UserID:         userID,
+		ProjectID:      projectID,
+		OrganizationID: access.OrganizationID,
+		Permission:     permission,
+		Source:         access.Source,
+	}
+	if access.Role != nil {
+		roleID := access.Role.ID
+		simulation.RoleID = &roleID
+		simulation.RoleName = access.Role.Name
+		simulation.RoleScope = access.Role.GetScopeDisplay()
+	}
+	simulation.MatchedPermission, simulation.Allowed = access.Allows(permission)
+	simulation.Reason = simulationReason(access, simulation)
+
+	return simulation, nil
+}
+
+// organizationRole returns the role of an active organization member with its
+// permissions, or nil when the user is not one
+func (s *projectAccessService) organizationRole(ctx context.Context, userID, orgID ulid.ULID) (*authDomain.Role, error) {
+	member, err := s.memberRepo.GetByUserAndOrg(ctx, userID, orgID)
+	if err != nil {
+		if errors.Is(err, orgDomain.ErrMemberNotFound) {
+			return nil, nil
+		}
+		return nil, appErrors.NewInternalError("Failed to get organization membership", err)
+	}
+	if member.Status != authDomain.MemberStatusActive {
+		return nil, nil
+	}
+
+	role, err := s.roleRepo.GetByID(ctx, member.RoleID)
+	if err != nil {
+		return nil, appErrors.NewInternalError("Failed to get organization role", err)
+	}
+	return role, nil
+}
+
+// assignedProjectsOnly reports whether the organization withholds the organization
+// role from projects a member has no role in
+func (s *projectAccessService) assignedProjectsOnly(ctx context.Context, orgID ulid.ULID) (bool, error) {
+	setting, err := s.settingsRepo.GetByKey(ctx, orgID, orgDomain.SettingAssignedProjectsOnly)
+	if err != nil {
+		if errors.Is(err, orgDomain.ErrSettingsNotFound) {
+			return false, nil
+		}
+		return false, appErrors.NewInternalError("Failed to get organization project access policy", err)
+	}
+	value, err := setting.GetValue()
+	return err == nil && value == true, nil
+}
+
+func grantRole(access *authDomain.ProjectAccess, source authDomain.PermissionSource, role *authDomain.Role) {
+	access.Source = source
+	access.Role = role
+	for _, permission := range role.Permissions {
+		access.Permissions = append(access.Permissions, permission.Name)
+	}
+}
+
+func simulationReason(access *authDomain.ProjectAccess, simulation *authDomain.PermissionSimulation) string {
+	switch access.Source {
+	case authDomain.PermissionSourceOwner:
+		if !simulation.Allowed {
+			return fmt.Sprintf("Organization owners keep the %q role in every project, which does not grant %s", simulation.RoleName, simulation.Permission)
+		}
+		return fmt.Sprintf("Organization owners keep the %q role in every project, which grants %s", simulation.RoleName, simulation.MatchedPermission)
+	case authDomain.PermissionSourceProjectRole:
+		if simulation.Allowed {
+			return fmt.Sprintf("Project role %q grants %s", simulation.RoleName, simulation.MatchedPermission)
+		}
+		return fmt.Sprintf("Project role %q does not grant %s; in this project it replaces the organization role", simulation.RoleName, simulation.Permission)
+	case authDomain.PermissionSourceOrganizationRole:
+		if simulation.Allowed {
+			return fmt.Sprintf("Organization role %q grants %s; no project role is assigned", simulation.RoleName, simulation.MatchedPermission)
+		}
+		return fmt.Sprintf("Organization role %q does not grant %s and no project role is assigned", simulation.RoleName, simulation.Permission)
+	case authDomain.PermissionSourceUnassigned:
+		return "The organization only grants access to assigned projects and no project role is assigned"
+	default:
+		return "User is not an active member of the project's organization"
+	}
+}
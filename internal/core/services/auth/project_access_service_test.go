@@ -0,0 +1,288 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authDomain "brokle/internal/core/domain/auth"
+	orgDomain "brokle/internal/core/domain/organization"
+	appErrors "brokle/pkg/errors"
+	"brokle/pkg/ulid"
+)
+
+type accessProjectRepository struct {
+	orgDomain.ProjectRepository
+	project *orgDomain.Project
+}
+
+func (r *accessProjectRepository) GetByID(ctx context.Context, id ulid.ULID) (*orgDomain.Project, error) {
+	if id != r.project.ID {
+		return nil, orgDomain.ErrProjectNotFound
+	}
+	return r.project, nil
+}
+
+type accessMemberRepository struct {
+	orgDomain.MemberRepository
+	members map[ulid.ULID]*orgDomain.Member
+}
+
+func (r *accessMemberRepository) GetByUserAndOrg(ctx context.Context, userID, orgID ulid.ULID) (*orgDomain.Member, error) {
+	member, ok := r.members[userID]
+	if !ok || member.OrganizationID != orgID {
+		return nil, orgDomain.ErrMemberNotFound
+	}
+	return member, nil
+}
+
+type accessProjectMemberRepository struct {
+	authDomain.ProjectMemberRepository
+	assignments map[ulid.ULID]*authDomain.ProjectMember
+}
+
+func (r *accessProjectMemberRepository) GetByUserAndProject(ctx context.Context, userID, projectID ulid.ULID) (*authDomain.ProjectMember, error) {
+	assignment, ok := r.assignments[userID]
+	if !ok || assignment.ProjectID != projectID {
+		return nil, authDomain.ErrNotFound
+	}
+	return assignment, nil
+}
+
+type accessRoleRepository struct {
+	authDomain.RoleRepository
+	roles map[ulid.ULID]*authDomain.Role
+}
+
+func (r *accessRoleRepository) GetByID(ctx context.Context, id ulid.ULID) (*authDomain.Role, error) {
+	role, ok := r.roles[id]
+	if !ok {
+		return nil, authDomain.ErrNotFound
+	}
+	return role, nil
+}
+
+type accessSettingsRepository struct {
+	orgDomain.OrganizationSettingsRepository
+	assignedOnly map[ulid.ULID]bool
+}
+
+func (r *accessSettingsRepository) GetByKey(ctx context.Context, orgID ulid.ULID, key string) (*orgDomain.OrganizationSettings, error) {
+	assignedOnly, ok := r.assignedOnly[orgID]
+	if !ok || key != orgDomain.SettingAssignedProjectsOnly {
+		return nil, fmt.Errorf("get setting %s: %w", key, orgDomain.ErrSettingsNotFound)
+	}
+	return orgDomain.NewOrganizationSettings(orgID, key, assignedOnly)
+}
+
+func accessRole(name string, scopeID *ulid.ULID, permissions ...string) *authDomain.Role {
+	role := &authDomain.Role{ID: ulid.New(), Name: name, ScopeType: authDomain.ScopeOrganization, ScopeID: scopeID}
+	for _, permission := range permissions {
+		resource, action, _ := authDomain.ParseResourceAction(permission)
+		role.Permissions = append(role.Permissions, authDomain.Permission{Name: permission, Resource: resource, Action: action})
+	}
+	return role
+}
+
+type projectAccessFixture struct {
+	service     authDomain.ProjectAccessService
+	project     *orgDomain.Project
+	members     *accessMemberRepository
+	assignments *accessProjectMemberRepository
+	roles       *accessRoleRepository
+	settings    *accessSettingsRepository
+}
+
+func newProjectAccessFixture() *projectAccessFixture {
+	project := &orgDomain.Project{ID: ulid.New(), OrganizationID: ulid.New()}
+	f := &projectAccessFixture{
+		project:     project,
+		members:     &accessMemberRepository{members: make(map[ulid.ULID]*orgDomain.Member)},
+		assignments: &accessProjectMemberRepository{assignments: make(map[ulid.ULID]*authDomain.ProjectMember)},
+		roles:       &accessRoleRepository{roles: make(map[ulid.ULID]*authDomain.Role)},
+		settings:    &accessSettingsRepository{assignedOnly: make(map[ulid.ULID]bool)},
+	}
+	f.service = NewProjectAccessService(&accessProjectRepository{project: project}, f.members, f.assignments, f.roles, f.settings)
+	return f
+}
+
+// member adds an organization member with the role and returns their user ID
+func (f *projectAccessFixture) member(role *authDomain.Role, status string) ulid.ULID {
+	userID := ulid.New()
+	f.roles.roles[role.ID] = role
+	f.members.members[userID] = &orgDomain.Member{UserID: userID, OrganizationID: f.project.OrganizationID, RoleID: role.ID, Status: status}
+	return userID
+}
+
+func (f *projectAccessFixture) assign(userID ulid.ULID, role *authDomain.Role) {
+	assignment := authDomain.NewProjectMember(userID, f.project.ID, role.ID)
+	assignment.Role = role
+	f.assignments.assignments[userID] = assignment
+}
+
+func TestProjectAccessService_GetProjectAccess(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("organization role applies without an assignment", func(t *testing.T) {
+		f := newProjectAccessFixture()
+		userID := f.member(accessRole("developer", nil, "prompts:read", "prompts:update"), authDomain.MemberStatusActive)
+
+		access, err := f.service.GetProjectAccess(ctx, userID, f.project.ID)
+		require.NoError(t, err)
+		assert.Equal(t, authDomain.PermissionSourceOrganizationRole, access.Source)
+		assert.Equal(t, f.project.OrganizationID, access.OrganizationID)
+		assert.ElementsMatch(t, []string{"prompts:read", "prompts:update"}, access.Permissions)
+	})
+
+	t.Run("project role replaces the organization role", func(t *testing.T) {
+		f := newProjectAccessFixture()
+		userID := f.member(accessRole("admin", nil, "prompts:update", "traces:delete"), authDomain.MemberStatusActive)
+		f.assign(userID, accessRole("viewer", nil, "prompts:read"))
+
+		result, err := f.service.CheckUserProjectPermissions(ctx, userID, f.project.ID, []string{"prompts:read", "prompts:update", "traces:delete"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]bool{"prompts:read": true, "prompts:update": false, "traces:delete": false}, result)
+	})
+
+	t.Run("contractor sees only assigned projects", func(t *testing.T) {
+		f := newProjectAccessFixture()
+		orgID := f.project.OrganizationID
+		userID := f.member(accessRole("Contractor", &orgID), authDomain.MemberStatusActive)
+
+		result, err := f.service.CheckUserProjectPermissions(ctx, userID, f.project.ID, []string{"projects:read"})
+		require.NoError(t, err)
+		assert.False(t, result["projects:read"])
+
+		f.assign(userID, accessRole("Prompt Editor", &orgID, "projects:read", "prompts:update"))
+		result, err = f.service.CheckUserProjectPermissions(ctx, userID, f.project.ID, []string{"projects:read"})
+		require.NoError(t, err)
+		assert.True(t, result["projects:read"])
+	})
+
+	t.Run("owners keep full access", func(t *testing.T) {
+		f := newProjectAccessFixture()
+		userID := f.member(accessRole("owner", nil, "projects:delete"), authDomain.MemberStatusActive)
+		f.assign(userID, accessRole("viewer", nil, "projects:read"))
+
+		access, err := f.service.GetProjectAccess(ctx, userID, f.project.ID)
+		require.NoError(t, err)
+		assert.Equal(t, authDomain.PermissionSourceOwner, access.Source)
+		assert.Equal(t, []string{"projects:delete"}, access.Permissions)
+	})
+
+	t.Run("assignments need an active organization membership", func(t *testing.T) {
+		f := newProjectAccessFixture()
+		userID := f.member(accessRole("developer", nil, "prompts:read"), authDomain.MemberStatusSuspended)
+		f.assign(userID, accessRole("viewer", nil, "prompts:read"))
+
+		access, err := f.service.GetProjectAccess(ctx, userID, f.project.ID)
+		require.NoError(t, err)
+		assert.Equal(t, authDomain.PermissionSourceNone, access.Source)
+		assert.Empty(t, access.Permissions)
+
+		access, err = f.service.GetProjectAccess(ctx, ulid.New(), f.project.ID)
+		require.NoError(t, err)
+		assert.Equal(t, authDomain.PermissionSourceNone, access.Source)
+	})
+
+	t.Run("assigned projects only withholds the organization role", func(t *testing.T) {
+		f := newProjectAccessFixture()
+		f.settings.assignedOnly[f.project.OrganizationID] = true
+		userID := f.member(accessRole("admin", nil, "projects:read", "traces:read"), authDomain.MemberStatusActive)
+		ownerID := f.member(accessRole("owner", nil, "projects:read"), authDomain.MemberStatusActive)
+
+		access, err := f.service.GetProjectAccess(ctx, userID, f.project.ID)
+		require.NoError(t, err)
+		assert.Equal(t, authDomain.PermissionSourceUnassigned, access.Source)
+		assert.Nil(t, access.Role)
+		assert.Empty(t, access.Permissions)
+
+		f.assign(userID, accessRole("viewer", nil, "traces:read"))
+		result, err := f.service.CheckUserProjectPermissions(ctx, userID, f.project.ID, []string{"projects:read", "traces:read"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]bool{"projects:read": false, "traces:read": true}, result)
+
+		access, err = f.service.GetProjectAccess(ctx, ownerID, f.project.ID)
+		require.NoError(t, err)
+		assert.Equal(t, authDomain.PermissionSourceOwner, access.Source)
+
+		f.settings.assignedOnly[f.project.OrganizationID] = false
+		access, err = f.service.GetProjectAccess(ctx, f.member(accessRole("developer", nil, "projects:read"), authDomain.MemberStatusActive), f.project.ID)
+		require.NoError(t, err)
+		assert.Equal(t, authDomain.PermissionSourceOrganizationRole, access.Source)
+	})
+
+	t.Run("wildcard grants match", func(t *testing.T) {
+		f := newProjectAccessFixture()
+		userID := f.member(accessRole("developer", nil, "traces:*"), authDomain.MemberStatusActive)
+
+		result, err := f.service.CheckUserProjectPermissions(ctx, userID, f.project.ID, []string{"traces:delete", "prompts:read"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]bool{"traces:delete": true, "prompts:read": false}, result)
+	})
+
+	t.Run("unknown project is not found", func(t *testing.T) {
+		f := newProjectAccessFixture()
+
+		_, err := f.service.GetProjectAccess(ctx, ulid.New(), ulid.New())
+		assert.True(t, appErrors.IsNotFound(err))
+	})
+}
+
+func TestProjectAccessService_SimulatePermission(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("explains the matching grant", func(t *testing.T) {
+		f := newProjectAccessFixture()
+		orgID := f.project.OrganizationID
+		userID := f.member(accessRole("viewer", nil, "prompts:read"), authDomain.MemberStatusActive)
+		editor := accessRole("Prompt Editor", &orgID, "prompts:*")
+		f.assign(userID, editor)
+
+		simulation, err := f.service.SimulatePermission(ctx, userID, f.project.ID, "prompts:update")
+		require.NoError(t, err)
+		assert.True(t, simulation.Allowed)
+		assert.Equal(t, authDomain.PermissionSourceProjectRole, simulation.Source)
+		assert.Equal(t, editor.ID, *simulation.RoleID)
+		assert.Equal(t, "Prompt Editor", simulation.RoleName)
+		assert.Equal(t, "Organization Custom", simulation.RoleScope)
+		assert.Equal(t, "prompts:*", simulation.MatchedPermission)
+		assert.Contains(t, simulation.Reason, `Project role "Prompt Editor" grants prompts:*`)
+	})
+
+	t.Run("explains a denial", func(t *testing.T) {
+		f := newProjectAccessFixture()
+		userID := f.member(accessRole("admin", nil, "traces:delete"), authDomain.MemberStatusActive)
+		f.assign(userID, accessRole("viewer", nil, "traces:read"))
+
+		simulation, err := f.service.SimulatePermission(ctx, userID, f.project.ID, "traces:delete")
+		require.NoError(t, err)
+		assert.False(t, simulation.Allowed)
+		assert.Empty(t, simulation.MatchedPermission)
+		assert.Contains(t, simulation.Reason, "replaces the organization role")
+	})
+
+	t.Run("explains an unassigned project", func(t *testing.T) {
+		f := newProjectAccessFixture()
+		f.settings.assignedOnly[f.project.OrganizationID] = true
+		userID := f.member(accessRole("admin", nil, "traces:read"), authDomain.MemberStatusActive)
+
+		simulation, err := f.service.SimulatePermission(ctx, userID, f.project.ID, "traces:read")
+		require.NoError(t, err)
+		assert.False(t, simulation.Allowed)
+		assert.Equal(t, authDomain.PermissionSourceUnassigned, simulation.Source)
+		assert.Contains(t, simulation.Reason, "only grants access to assigned projects")
+	})
+
+	t.Run("rejects malformed permissions", func(t *testing.T) {
+		f := newProjectAccessFixture()
+
+		_, err := f.service.SimulatePermission(ctx, ulid.New(), f.project.ID, "prompts")
+		appErr, ok := appErrors.IsAppError(err)
+		require.True(t, ok)
+		assert.Equal(t, appErrors.ValidationError, appErr.Type)
+	})
+}
@@ -0,0 +1,448 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+
+	"brokle/internal/config"
+	authDomain "brokle/internal/core/domain/auth"
+	appErrors "brokle/pkg/errors"
+	"brokle/pkg/ulid"
+)
+
+const (
+	// Limits are resolved on every SDK request, so they are cached per key.
+	// Changes made through this service invalidate immediately; other
+	// processes pick them up after the TTL.
+	rateLimitCacheTTL  = 30 * time.Second
+	rateLimitCacheSize = 10000
+)
+
+type rateLimitCacheEntry struct {
+	key       authDomain.RateLimits
+	project   authDomain.RateLimits
+	expiresAt time.Time
+}
+
+// rateLimitBucket ties a limiter bucket to the limit it enforces
+type rateLimitBucket struct {
+	authDomain.RateLimitBucket
+	scope     authDomain.RateLimitScope
+	dimension authDomain.RateLimitDimension
+}
+
+// rateLimitService implements the authDomain.RateLimitService interface
+type rateLimitService struct {
+	policyRepo authDomain.RateLimitPolicyRepository
+	apiKeyRepo authDomain.APIKeyRepository
+	limiter    authDomain.RateLimiter
+	config     *config.AuthConfig
+	logger     *slog.Logger
+	limits     *lru.Cache[string, *rateLimitCacheEntry]
+}
+
+// NewRateLimitService creates a new rate limit service instance
+func NewRateLimitService(
+	policyRepo authDomain.RateLimitPolicyRepository,
+	apiKeyRepo authDomain.APIKeyRepository,
+	limiter authDomain.RateLimiter,
+	cfg *config.AuthConfig,
+	logger *slog.Logger,
+) authDomain.RateLimitService {
+	limits, _ := lru.New[string, *rateLimitCacheEntry](rateLimitCacheSize)
+
+	return &rateLimitService{
+		policyRepo: policyRepo,
+		apiKeyRepo: apiKeyRepo,
+		limiter:    limiter,
+		config:     cfg,
+		logger:     logger,
+		limits:     limits,
+	}
+}
+
+// Check consumes cost from the key's and the project's limits. Nothing is consumed
+// when any limit would be exceeded. Fails open when limits can't be resolved or counted.
+func (s *rateLimitService) Check(ctx context.Context, projectID, keyID ulid.ULID, cost authDomain.RateLimitCost) (*authDomain.RateLimitDecision, error) {
+	if !s.config.RateLimitEnabled {
+		return &authDomain.RateLimitDecision{Allowed: true}, nil
+	}
+
+	entry := s.resolve(ctx, projectID, keyID)
+
+	var buckets []rateLimitBucket
+	for _, dimension := range authDomain.RateLimitDimensions {
+		if cost.Of(dimension) <= 0 {
+			continue
+		}
+		buckets = appendBucket(buckets, authDomain.RateLimitScopeAPIKey, keyID, dimension, entry.key.Limit(dimension), cost.Of(dimension))
+		buckets = appendBucket(buckets, authDomain.RateLimitScopeProject, projectID, dimension, entry.project.Limit(dimension), cost.Of(dimension))
+	}
+	if len(buckets) == 0 {
+		return &authDomain.RateLimitDecision{Allowed: true}, nil
+	}
+
+	now := time.Now()
+	allowed, states, err := s.limiter.Allow(ctx, now, limiterBuckets(buckets))
+	if err != nil {
+		s.logger.Error("rate limit check failed, allowing request",
+			"error", err,
+			"project_id", projectID,
+			"api_key_id", keyID,
+		)
+		return &authDomain.RateLimitDecision{Allowed: true}, nil
+	}
+
+	return decideRateLimit(now, buckets, states, allowed), nil
+}
+
+// EnforcesPayloadQuota returns true if a payload bytes quota applies to the key or its project
+func (s *rateLimitService) EnforcesPayloadQuota(ctx context.Context, projectID, keyID ulid.ULID) bool {
+	if !s.config.RateLimitEnabled {
+		return false
+	}
+	entry := s.resolve(ctx, projectID, keyID)
+	return entry.key.PayloadBytesPerDay > 0 || entry.project.PayloadBytesPerDay > 0
+}
+
+// GetProjectLimits returns the project's limits and current usage
+func (s *rateLimitService) GetProjectLimits(ctx context.Context, projectID ulid.ULID) (*authDomain.RateLimitsResponse, error) {
+	policy, err := s.findPolicy(ctx, projectID, nil)
+	if err != nil {
+		return nil, appErrors.NewInternalError("Failed to get rate limits", err)
+	}
+	return s.describe(ctx, policy, authDomain.RateLimits{}.Apply(policy), projectID, nil), nil
+}
+
+// UpdateProjectLimits replaces the project's limits
+func (s *rateLimitService) UpdateProjectLimits(ctx context.Context, projectID ulid.ULID, req *authDomain.UpdateRateLimitsRequest) (*authDomain.RateLimitsResponse, error) {
+	policy, err := s.savePolicy(ctx, projectID, nil, req)
+	if err != nil {
+		return nil, err
+	}
+	return s.describe(ctx, policy, authDomain.RateLimits{}.Apply(policy), projectID, nil), nil
+}
+
+// GetAPIKeyLimits returns the key's limits and current usage, including the shared project limits
+func (s *rateLimitService) GetAPIKeyLimits(ctx context.Context, projectID, keyID ulid.ULID) (*authDomain.RateLimitsResponse, error) {
+	if err := s.verifyKey(ctx, projectID, keyID); err != nil {
+		return nil, err
+	}
+	policy, err := s.findPolicy(ctx, projectID, &keyID)
+	if err != nil {
+		return nil, appErrors.NewInternalError("Failed to get rate limits", err)
+	}
+	return s.describe(ctx, policy, s.defaults().Apply(policy), projectID, &keyID), nil
+}
+
+// UpdateAPIKeyLimits replaces the key's limits
+func (s *rateLimitService) UpdateAPIKeyLimits(ctx context.Context, projectID, keyID ulid.ULID, req *authDomain.UpdateRateLimitsRequest) (*authDomain.RateLimitsResponse, error) {
+	if err := s.verifyKey(ctx, projectID, keyID); err != nil {
+		return nil, err
+	}
+	policy, err := s.savePolicy(ctx, projectID, &keyID, req)
+	if err != nil {
+		return nil, err
+	}
+	return s.describe(ctx, policy, s.defaults().Apply(policy), projectID, &keyID), nil
+}
+
+// resolve returns the effective key and project limits, cached per key
+func (s *rateLimitService) resolve(ctx context.Context, projectID, keyID ulid.ULID) *rateLimitCacheEntry {
+	cacheKey := projectID.String() + ":" + keyID.String()
+	if entry, ok := s.limits.Get(cacheKey); ok && time.Now().Before(entry.expiresAt) {
+		return entry
+	}
+
+	entry := &rateLimitCacheEntry{
+		key:       s.defaults(),
+		expiresAt: time.Now().Add(rateLimitCacheTTL),
+	}
+
+	keyPolicy, err := s.findPolicy(ctx, projectID, &keyID)
+	if err != nil {
+		s.logger.Warn("failed to load API key rate limits, using defaults",
+			"error", err,
+			"project_id", projectID,
+			"api_key_id", keyID,
+		)
+		return entry
+	}
+	projectPolicy, err := s.findPolicy(ctx, projectID, nil)
+	if err != nil {
+		s.logger.Warn("failed to load project rate limits, using defaults",
+			"error", err,
+			"project_id", projectID,
+		)
+		return entry
+	}
+
+	entry.key = entry.key.Apply(keyPolicy)
+	entry.project = entry.project.Apply(projectPolicy)
+	s.limits.Add(cacheKey, entry)
+	return entry
+}
+
+func (s *rateLimitService) defaults() authDomain.RateLimits {
+	return authDomain.RateLimits{
+		RequestsPerSecond:  s.config.APIKeyRequestsPerSecond,
+		SpansPerMinute:     s.config.APIKeySpansPerMinute,
+		PayloadBytesPerDay: s.config.APIKeyPayloadBytesPerDay,
+	}
+}
+
+// findPolicy maps a missing policy to nil
+func (s *rateLimitService) findPolicy(ctx context.Context, projectID ulid.ULID, keyID *ulid.ULID) (*authDomain.RateLimitPolicy, error) {
+	policy, err := s.policyRepo.Get(ctx, projectID, keyID)
+	if err != nil {
+		if errors.Is(err, authDomain.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return policy, nil
+}
+
+// savePolicy replaces the overrides; a request without overrides removes the policy
+func (s *rateLimitService) savePolicy(ctx context.Context, projectID ulid.ULID, keyID *ulid.ULID, req *authDomain.UpdateRateLimitsRequest) (*authDomain.RateLimitPolicy, error) {
+	for field, limit := range map[string]*int64{
+		"requests_per_second":   req.RequestsPerSecond,
+		"spans_per_minute":      req.SpansPerMinute,
+		"payload_bytes_per_day": req.PayloadBytesPerDay,
+	} {
+		if limit != nil && *limit < 0 {
+			return nil, appErrors.NewValidationError(field, "must not be negative")
+		}
+	}
+
+	defer s.invalidate(projectID)
+
+	if req.RequestsPerSecond == nil && req.SpansPerMinute == nil && req.PayloadBytesPerDay == nil {
+		if err := s.policyRepo.Delete(ctx, projectID, keyID); err != nil && !errors.Is(err, authDomain.ErrNotFound) {
+			return nil, appErrors.NewInternalError("Failed to reset rate limits", err)
+		}
+		return nil, nil
+	}
+
+	policy, err := s.findPolicy(ctx, projectID, keyID)
+	if err != nil {
+		return nil, appErrors.NewInternalError("Failed to get rate limits", err)
+	}
+	now := time.Now()
+	if policy == nil {
+		policy = &authDomain.RateLimitPolicy{
+			ID:        ulid.New(),
+			ProjectID: projectID,
+			APIKeyID:  keyID,
+			CreatedAt: now,
+		}
+	}
+	policy.RequestsPerSecond = req.RequestsPerSecond
+	policy.SpansPerMinute = req.SpansPerMinute
+	policy.PayloadBytesPerDay = req.PayloadBytesPerDay
+	policy.UpdatedAt = now
+
+	if err := s.policyRepo.Save(ctx, policy); err != nil {
+		return nil, appErrors.NewInternalError("Failed to save rate limits", err)
+	}
+
+	s.logger.Info("rate limits updated",
+		"project_id", projectID,
+		"api_key_id", keyID,
+		"requests_per_second", req.RequestsPerSecond,
+		"spans_per_minute", req.SpansPerMinute,
+		"payload_bytes_per_day", req.PayloadBytesPerDay,
+	)
+	return policy, nil
+}
+
+// invalidate drops cached limits of every key in the project
+func (s *rateLimitService) invalidate(projectID ulid.ULID) {
+	prefix := projectID.String() + ":"
+	for _, key := range s.limits.Keys() {
+		if strings.HasPrefix(key, prefix) {
+			s.limits.Remove(key)
+		}
+	}
+}
+
+func (s *rateLimitService) verifyKey(ctx context.Context, projectID, keyID ulid.ULID) error {
+	apiKey, err := s.apiKeyRepo.GetByID(ctx, keyID)
+	if err != nil {
+		if errors.Is(err, authDomain.ErrNotFound) {
+			return appErrors.NewNotFoundError("API key not found")
+		}
+		return appErrors.NewInternalError("Failed to get API key", err)
+	}
+	if apiKey.ProjectID != projectID {
+		return appErrors.NewNotFoundError("API key not found in this project")
+	}
+	return nil
+}
+
+// describe builds the limits response. Key responses include the shared project usage.
+func (s *rateLimitService) describe(ctx context.Context, policy *authDomain.RateLimitPolicy, effective authDomain.RateLimits, projectID ulid.ULID, keyID *ulid.ULID) *authDomain.RateLimitsResponse {
+	resp := &authDomain.RateLimitsResponse{
+		Policy:    policy,
+		Effective: effective,
+		Usage:     []authDomain.RateLimitUsage{},
+	}
+
+	projectLimits := effective
+	if keyID != nil {
+		projectPolicy, err := s.findPolicy(ctx, projectID, nil)
+		if err != nil {
+			s.logger.Warn("failed to load project rate limits", "error", err, "project_id", projectID)
+		}
+		projectLimits = authDomain.RateLimits{}.Apply(projectPolicy)
+	}
+
+	var buckets []rateLimitBucket
+	for _, dimension := range authDomain.RateLimitDimensions {
+		if keyID != nil {
+			buckets = appendBucket(buckets, authDomain.RateLimitScopeAPIKey, *keyID, dimension, effective.Limit(dimension), 0)
+		}
+		buckets = appendBucket(buckets, authDomain.RateLimitScopeProject, projectID, dimension, projectLimits.Limit(dimension), 0)
+	}
+	if len(buckets) == 0 {
+		return resp
+	}
+
+	now := time.Now()
+	states, err := s.limiter.Usage(ctx, now, limiterBuckets(buckets))
+	if err != nil {
+		s.logger.Warn("failed to read rate limit usage", "error", err, "project_id", projectID)
+		return resp
+	}
+
+	for i, bucket := range buckets {
+		used := int64(math.Round(windowUsage(now, bucket.Window, states[i])))
+		resp.Usage = append(resp.Usage, authDomain.RateLimitUsage{
+			Scope:         bucket.scope,
+			Dimension:     bucket.dimension,
+			Limit:         bucket.Limit,
+			Used:          used,
+			Remaining:     max(bucket.Limit-used, 0),
+			WindowSeconds: int64(bucket.Window.Seconds()),
+		})
+	}
+	return resp
+}
+
+// appendBucket adds a bucket for a limit; unlimited (0) limits are skipped
+func appendBucket(buckets []rateLimitBucket, scope authDomain.RateLimitScope, id ulid.ULID, dimension authDomain.RateLimitDimension, limit, cost int64) []rateLimitBucket {
+	if limit <= 0 {
+		return buckets
+	}
+	return append(buckets, rateLimitBucket{
+		RateLimitBucket: authDomain.RateLimitBucket{
+			Key:    fmt.Sprintf("%s:%s:%s", scope, id, dimension),
+			Window: dimension.Window(),
+			Limit:  limit,
+			Cost:   cost,
+		},
+		scope:     scope,
+		dimension: dimension,
+	})
+}
+
+func limiterBuckets(buckets []rateLimitBucket) []authDomain.RateLimitBucket {
+	result := make([]authDomain.RateLimitBucket, len(buckets))
+	for i, bucket := range buckets {
+		result[i] = bucket.RateLimitBucket
+	}
+	return result
+}
+
+// decideRateLimit describes the exceeded limit, or the limit closest to exhaustion
+func decideRateLimit(now time.Time, buckets []rateLimitBucket, states []authDomain.RateLimitBucketState, allowed bool) *authDomain.RateLimitDecision {
+	var decision *authDomain.RateLimitDecision
+	for i, bucket := range buckets {
+		used := windowUsage(now, bucket.Window, states[i])
+		if !allowed {
+			if used+float64(bucket.Cost) <= float64(bucket.Limit) {
+				continue
+			}
+			retryAfter := retryAfter(now, bucket.RateLimitBucket, states[i])
+			return &authDomain.RateLimitDecision{
+				Dimension:  bucket.dimension,
+				Scope:      bucket.scope,
+				Limit:      bucket.Limit,
+				Remaining:  max(bucket.Limit-int64(math.Ceil(used)), 0),
+				ResetAt:    now.Add(retryAfter),
+				RetryAfter: retryAfter,
+			}
+		}
+
+		remaining := max(bucket.Limit-int64(math.Ceil(used))-bucket.Cost, 0)
+		if decision == nil || float64(remaining)/float64(bucket.Limit) < float64(decision.Remaining)/float64(decision.Limit) {
+			decision = &authDomain.RateLimitDecision{
+				Dimension: bucket.dimension,
+				Scope:     bucket.scope,
+				Limit:     bucket.Limit,
+				Remaining: remaining,
+				ResetAt:   windowEnd(now, bucket.Window),
+				Allowed:   true,
+			}
+		}
+	}
+
+	if decision == nil {
+		// Denied by a bucket that recovered between the check and now; retry shortly
+		return &authDomain.RateLimitDecision{RetryAfter: time.Second, ResetAt: now.Add(time.Second)}
+	}
+	return decision
+}
+
+// windowUsage estimates the sliding window count: the current fixed window plus the
+// part of the previous window that still overlaps the sliding window
+func windowUsage(now time.Time, window time.Duration, state authDomain.RateLimitBucketState) float64 {
+	return float64(state.Previous)*(1-windowElapsed(now, window)) + float64(state.Current)
+}
+
+// retryAfter estimates when the bucket has room for its cost again, assuming no other traffic
+func retryAfter(now time.Time, bucket authDomain.RateLimitBucket, state authDomain.RateLimitBucketState) time.Duration {
+	limit, cost := float64(bucket.Limit), float64(bucket.Cost)
+	elapsed := windowElapsed(now, bucket.Window)
+
+	var wait float64 // In windows
+	switch {
+	case cost > limit:
+		// Never fits; a retry after a full window at least sees an empty window
+		wait = 1
+	case float64(state.Current)+cost <= limit && state.Previous > 0:
+		// Fits once the previous window decays far enough within this window
+		weight := (limit - float64(state.Current) - cost) / float64(state.Previous)
+		wait = (1 - weight) - elapsed
+	default:
+		// Fits in the next window once the current window decays far enough
+		weight := 1.0
+		if state.Current > 0 {
+			weight = (limit - cost) / float64(state.Current)
+		}
+		wait = (1 - elapsed) + (1 - weight)
+	}
+
+	retry := time.Duration(wait * float64(bucket.Window))
+	if retry < time.Millisecond {
+		retry = time.Millisecond
+	}
+	return retry
+}
+
+func windowElapsed(now time.Time, window time.Duration) float64 {
+	ms := window.Milliseconds()
+	return float64(now.UnixMilli()%ms) / float64(ms)
+}
+
+func windowEnd(now time.Time, window time.Duration) time.Time {
+	ms := window.Milliseconds()
+	return time.UnixMilli((now.UnixMilli()/ms + 1) * ms)
+}
@@ -0,0 +1,202 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"brokle/internal/config"
+	authDomain "brokle/internal/core/domain/auth"
+	"brokle/pkg/ulid"
+)
+
+type mockRateLimitPolicyRepository struct {
+	mock.Mock
+}
+
+func (m *mockRateLimitPolicyRepository) Get(ctx context.Context, projectID ulid.ULID, apiKeyID *ulid.ULID) (*authDomain.RateLimitPolicy, error) {
+	args := m.Called(ctx, projectID, apiKeyID)
+	if v := args.Get(0); v != nil {
+		return v.(*authDomain.RateLimitPolicy), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockRateLimitPolicyRepository) Save(ctx context.Context, policy *authDomain.RateLimitPolicy) error {
+	return m.Called(ctx, policy).Error(0)
+}
+
+func (m *mockRateLimitPolicyRepository) Delete(ctx context.Context, projectID ulid.ULID, apiKeyID *ulid.ULID) error {
+	return m.Called(ctx, projectID, apiKeyID).Error(0)
+}
+
+type mockRateLimiter struct {
+	mock.Mock
+}
+
+func (m *mockRateLimiter) Allow(ctx context.Context, now time.Time, buckets []authDomain.RateLimitBucket) (bool, []authDomain.RateLimitBucketState, error) {
+	args := m.Called(ctx, now, buckets)
+	states, _ := args.Get(1).([]authDomain.RateLimitBucketState)
+	return args.Bool(0), states, args.Error(2)
+}
+
+func (m *mockRateLimiter) Usage(ctx context.Context, now time.Time, buckets []authDomain.RateLimitBucket) ([]authDomain.RateLimitBucketState, error) {
+	args := m.Called(ctx, now, buckets)
+	states, _ := args.Get(0).([]authDomain.RateLimitBucketState)
+	return states, args.Error(1)
+}
+
+func newTestRateLimitService(repo authDomain.RateLimitPolicyRepository, limiter authDomain.RateLimiter, enabled bool) authDomain.RateLimitService {
+	cfg := &config.AuthConfig{RateLimitEnabled: enabled, APIKeyRequestsPerSecond: 10}
+	return NewRateLimitService(repo, nil, limiter, cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestRateLimitService_Check(t *testing.T) {
+	ctx := context.Background()
+	projectID, keyID := ulid.New(), ulid.New()
+	spans := int64(100)
+
+	repo := new(mockRateLimitPolicyRepository)
+	repo.On("Get", ctx, projectID, &keyID).Return(nil, authDomain.ErrNotFound)
+	repo.On("Get", ctx, projectID, (*ulid.ULID)(nil)).Return(&authDomain.RateLimitPolicy{SpansPerMinute: &spans}, nil)
+
+	t.Run("allowed", func(t *testing.T) {
+		limiter := new(mockRateLimiter)
+		limiter.On("Allow", ctx, mock.Anything, mock.MatchedBy(func(buckets []authDomain.RateLimitBucket) bool {
+			// Key requests (default) and project spans; unlimited project requests are skipped
+			return len(buckets) == 2 &&
+				buckets[0].Key == fmt.Sprintf("api_key:%s:requests", keyID) && buckets[0].Limit == 10 &&
+				buckets[1].Key == fmt.Sprintf("project:%s:spans", projectID) && buckets[1].Cost == 40
+		})).Return(true, []authDomain.RateLimitBucketState{{Current: 1}, {Current: 50}}, nil).Once()
+
+		decision, err := newTestRateLimitService(repo, limiter, true).Check(ctx, projectID, keyID, authDomain.RateLimitCost{Requests: 1, Spans: 40})
+		require.NoError(t, err)
+		assert.True(t, decision.Allowed)
+		assert.Equal(t, authDomain.RateLimitSpans, decision.Dimension)
+		assert.Equal(t, int64(10), decision.Remaining)
+		limiter.AssertExpectations(t)
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		limiter := new(mockRateLimiter)
+		limiter.On("Allow", ctx, mock.Anything, mock.Anything).
+			Return(false, []authDomain.RateLimitBucketState{{Current: 10}, {Current: 0}}, nil).Once()
+
+		decision, err := newTestRateLimitService(repo, limiter, true).Check(ctx, projectID, keyID, authDomain.RateLimitCost{Requests: 1, Spans: 1})
+		require.NoError(t, err)
+		assert.False(t, decision.Allowed)
+		assert.Equal(t, authDomain.RateLimitScopeAPIKey, decision.Scope)
+		assert.Equal(t, authDomain.RateLimitRequests, decision.Dimension)
+		assert.Positive(t, decision.RetryAfter)
+		assert.LessOrEqual(t, decision.RetryAfter, 2*time.Second)
+	})
+
+	t.Run("fails open", func(t *testing.T) {
+		limiter := new(mockRateLimiter)
+		limiter.On("Allow", ctx, mock.Anything, mock.Anything).Return(false, nil, fmt.Errorf("connection refused")).Once()
+
+		decision, err := newTestRateLimitService(repo, limiter, true).Check(ctx, projectID, keyID, authDomain.RateLimitCost{Requests: 1})
+		require.NoError(t, err)
+		assert.True(t, decision.Allowed)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		limiter := new(mockRateLimiter)
+
+		decision, err := newTestRateLimitService(repo, limiter, false).Check(ctx, projectID, keyID, authDomain.RateLimitCost{Requests: 1})
+		require.NoError(t, err)
+		assert.True(t, decision.Allowed)
+		limiter.AssertNotCalled(t, "Allow", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestRateLimitService_EnforcesPayloadQuota(t *testing.T) {
+	ctx := context.Background()
+	projectID, keyID, limitedKeyID := ulid.New(), ulid.New(), ulid.New()
+	bytes := int64(1 << 20)
+
+	repo := new(mockRateLimitPolicyRepository)
+	repo.On("Get", ctx, projectID, &keyID).Return(nil, authDomain.ErrNotFound)
+	repo.On("Get", ctx, projectID, &limitedKeyID).Return(&authDomain.RateLimitPolicy{PayloadBytesPerDay: &bytes}, nil)
+	repo.On("Get", ctx, projectID, (*ulid.ULID)(nil)).Return(nil, authDomain.ErrNotFound)
+
+	service := newTestRateLimitService(repo, new(mockRateLimiter), true)
+	assert.False(t, service.EnforcesPayloadQuota(ctx, projectID, keyID))
+	assert.True(t, service.EnforcesPayloadQuota(ctx, projectID, limitedKeyID))
+	assert.False(t, newTestRateLimitService(repo, new(mockRateLimiter), false).EnforcesPayloadQuota(ctx, projectID, limitedKeyID))
+}
+
+func TestRetryAfter(t *testing.T) {
+	window := time.Minute
+	start := time.UnixMilli(60_000 * 1000) // Start of a window
+
+	tests := []struct {
+		name  string
+		now   time.Time
+		state authDomain.RateLimitBucketState
+		cost  int64
+		want  time.Duration
+	}{
+		// 100 from the previous window must decay to 50: half way through this window
+		{"previous window decays", start, authDomain.RateLimitBucketState{Previous: 100, Current: 40}, 10, 30 * time.Second},
+		// Current window full: the next window must start and 90% of this one decay
+		{"current window full", start.Add(30 * time.Second), authDomain.RateLimitBucketState{Current: 100}, 10, 30*time.Second + 6*time.Second},
+		{"cost above limit", start, authDomain.RateLimitBucketState{}, 200, window},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bucket := authDomain.RateLimitBucket{Window: window, Limit: 100, Cost: tt.cost}
+			assert.InDelta(t, tt.want, retryAfter(tt.now, bucket, tt.state), float64(time.Millisecond))
+		})
+	}
+}
+
+func TestRateLimitService_UpdateProjectLimits(t *testing.T) {
+	ctx := context.Background()
+	projectID := ulid.New()
+
+	t.Run("creates policy", func(t *testing.T) {
+		rps := int64(50)
+		repo := new(mockRateLimitPolicyRepository)
+		repo.On("Get", ctx, projectID, (*ulid.ULID)(nil)).Return(nil, authDomain.ErrNotFound)
+		repo.On("Save", ctx, mock.AnythingOfType("*auth.RateLimitPolicy")).Return(nil)
+		limiter := new(mockRateLimiter)
+		limiter.On("Usage", ctx, mock.Anything, mock.Anything).Return([]authDomain.RateLimitBucketState{{Current: 5}}, nil)
+
+		resp, err := newTestRateLimitService(repo, limiter, true).UpdateProjectLimits(ctx, projectID, &authDomain.UpdateRateLimitsRequest{RequestsPerSecond: &rps})
+		require.NoError(t, err)
+		require.NotNil(t, resp.Policy)
+		assert.Equal(t, projectID, resp.Policy.ProjectID)
+		assert.Equal(t, rps, resp.Effective.RequestsPerSecond)
+		require.Len(t, resp.Usage, 1)
+		assert.Equal(t, int64(5), resp.Usage[0].Used)
+		assert.Equal(t, int64(45), resp.Usage[0].Remaining)
+	})
+
+	t.Run("empty request resets", func(t *testing.T) {
+		repo := new(mockRateLimitPolicyRepository)
+		repo.On("Delete", ctx, projectID, (*ulid.ULID)(nil)).Return(nil)
+
+		resp, err := newTestRateLimitService(repo, new(mockRateLimiter), true).UpdateProjectLimits(ctx, projectID, &authDomain.UpdateRateLimitsRequest{})
+		require.NoError(t, err)
+		assert.Nil(t, resp.Policy)
+		assert.Empty(t, resp.Usage)
+		repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects negative limit", func(t *testing.T) {
+		negative := int64(-1)
+		repo := new(mockRateLimitPolicyRepository)
+
+		_, err := newTestRateLimitService(repo, new(mockRateLimiter), true).UpdateProjectLimits(ctx, projectID, &authDomain.UpdateRateLimitsRequest{SpansPerMinute: &negative})
+		assert.Error(t, err)
+		repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"fmt"
 
 	authDomain "brokle/internal/core/domain/auth"
 	appErrors "brokle/pkg/errors"
@@ -10,18 +11,21 @@ import (
 
 // roleService implements clean auth.RoleService interface (template roles only)
 type roleService struct {
-	roleRepo     authDomain.RoleRepository
-	rolePermRepo authDomain.RolePermissionRepository
+	roleRepo       authDomain.RoleRepository
+	rolePermRepo   authDomain.RolePermissionRepository
+	permissionRepo authDomain.PermissionRepository
 }
 
 // NewRoleService creates a new clean role service instance
 func NewRoleService(
 	roleRepo authDomain.RoleRepository,
 	rolePermRepo authDomain.RolePermissionRepository,
+	permissionRepo authDomain.PermissionRepository,
 ) authDomain.RoleService {
 	return &roleService{
-		roleRepo:     roleRepo,
-		rolePermRepo: rolePermRepo,
+		roleRepo:       roleRepo,
+		rolePermRepo:   rolePermRepo,
+		permissionRepo: permissionRepo,
 	}
 }
 
@@ -166,6 +170,11 @@ func (s *roleService) CreateCustomRole(ctx context.Context, scopeType string, sc
 		return nil, appErrors.NewValidationError("scope_type", "Scope type is required")
 	}
 
+	permissionIDs, err := s.resolvePermissionIDs(ctx, req.PermissionIDs, req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
 	// Check if custom role already exists with this name and scope
 	existing, err := s.roleRepo.GetByNameScopeAndID(ctx, req.Name, scopeType, &scopeID)
 	if err == nil && existing != nil {
@@ -181,8 +190,8 @@ func (s *roleService) CreateCustomRole(ctx context.Context, scopeType string, sc
 	}
 
 	// Assign permissions if provided
-	if len(req.PermissionIDs) > 0 {
-		err = s.roleRepo.AssignRolePermissions(ctx, role.ID, req.PermissionIDs, nil)
+	if len(permissionIDs) > 0 {
+		err = s.roleRepo.AssignRolePermissions(ctx, role.ID, permissionIDs, nil)
 		if err != nil {
 			return nil, appErrors.NewInternalError("Failed to assign permissions to custom role", err)
 		}
@@ -206,6 +215,13 @@ func (s *roleService) UpdateCustomRole(ctx context.Context, roleID ulid.ULID, re
 		return nil, appErrors.NewForbiddenError("Cannot update system role")
 	}
 
+	var permissionIDs []ulid.ULID
+	if req.PermissionIDs != nil || req.Permissions != nil {
+		if permissionIDs, err = s.resolvePermissionIDs(ctx, req.PermissionIDs, req.Permissions); err != nil {
+			return nil, err
+		}
+	}
+
 	// Update fields
 	if req.Description != nil {
 		role.Description = *req.Description
@@ -218,8 +234,8 @@ func (s *roleService) UpdateCustomRole(ctx context.Context, roleID ulid.ULID, re
 	}
 
 	// Update permissions if provided
-	if req.PermissionIDs != nil {
-		err = s.roleRepo.UpdateRolePermissions(ctx, role.ID, req.PermissionIDs, nil)
+	if req.PermissionIDs != nil || req.Permissions != nil {
+		err = s.roleRepo.UpdateRolePermissions(ctx, role.ID, permissionIDs, nil)
 		if err != nil {
 			return nil, appErrors.NewInternalError("Failed to update role permissions", err)
 		}
@@ -239,8 +255,50 @@ func (s *roleService) DeleteCustomRole(ctx context.Context, roleID ulid.ULID) er
 		return appErrors.NewForbiddenError("Cannot delete system role")
 	}
 
-	// TODO: Add check if role is in use by organization members
-	// This would require checking the organization_members table
+	// Members and project assignments would lose their role
+	assignments, err := s.roleRepo.CountAssignments(ctx, roleID)
+	if err != nil {
+		return appErrors.NewInternalError("Failed to check role assignments", err)
+	}
+	if assignments > 0 {
+		return appErrors.NewConflictError(fmt.Sprintf("Custom role is assigned to %d members; reassign them before deleting it", assignments))
+	}
 
 	return s.roleRepo.Delete(ctx, roleID)
 }
+
+// resolvePermissionIDs merges catalogue permissions picked by ID and by name
+func (s *roleService) resolvePermissionIDs(ctx context.Context, ids []ulid.ULID, names []string) ([]ulid.ULID, error) {
+	if len(names) == 0 {
+		return ids, nil
+	}
+
+	permissions, err := s.permissionRepo.GetByNames(ctx, names)
+	if err != nil {
+		return nil, appErrors.NewInternalError("Failed to resolve permissions", err)
+	}
+	found := make(map[string]ulid.ULID, len(permissions))
+	for _, permission := range permissions {
+		found[permission.Name] = permission.ID
+	}
+
+	seen := make(map[ulid.ULID]bool, len(ids)+len(names))
+	resolved := make([]ulid.ULID, 0, len(ids)+len(names))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			resolved = append(resolved, id)
+		}
+	}
+	for _, name := range names {
+		id, ok := found[name]
+		if !ok {
+			return nil, appErrors.NewValidationError("Unknown permission", "permission "+name+" is not in the catalogue")
+		}
+		if !seen[id] {
+			seen[id] = true
+			resolved = append(resolved, id)
+		}
+	}
+	return resolved, nil
+}
@@ -0,0 +1,304 @@
+package auth
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+
+	authDomain "brokle/internal/core/domain/auth"
+	appErrors "brokle/pkg/errors"
+	"brokle/pkg/ulid"
+)
+
+// SCIM error types (RFC 7644 section 3.12), carried in the details of validation errors
+const (
+	scimInvalidFilter = "invalidFilter"
+	scimInvalidSyntax = "invalidSyntax"
+	scimInvalidPath   = "invalidPath"
+	scimInvalidValue  = "invalidValue"
+	scimMutability    = "mutability"
+	scimNoTarget      = "noTarget"
+)
+
+var (
+	// Identity providers only filter with a single equality, e.g. userName eq "jane@acme.com"
+	scimEqFilter = regexp.MustCompile(`(?i)^\s*([a-z.]+)\s+eq\s+("(?:[^"\\]|\\.)*")\s*$`)
+	// members[value eq "01H..."] selects one member to remove
+	scimMemberPath = regexp.MustCompile(`(?i)^members\[\s*value\s+eq\s+"([^"]*)"\s*\]$`)
+)
+
+// parseSCIMFilter parses an equality filter on one of the allowed attributes
+func parseSCIMFilter(filter string, allowed ...string) (authDomain.SCIMListFilter, error) {
+	var result authDomain.SCIMListFilter
+	if strings.TrimSpace(filter) == "" {
+		return result, nil
+	}
+
+	match := scimEqFilter.FindStringSubmatch(filter)
+	if match == nil {
+		return result, appErrors.NewValidationError("Unsupported filter; use <attribute> eq \"<value>\"", scimInvalidFilter)
+	}
+	var value string
+	if err := json.Unmarshal([]byte(match[2]), &value); err != nil {
+		return result, appErrors.NewValidationError("Invalid filter value", scimInvalidFilter)
+	}
+
+	attribute := ""
+	for _, name := range allowed {
+		if strings.EqualFold(match[1], name) {
+			attribute = name
+		}
+	}
+	switch attribute {
+	case "userName":
+		result.Email = value
+	case "externalId":
+		result.ExternalID = value
+	case "displayName":
+		result.DisplayName = value
+	default:
+		return result, appErrors.NewValidationError("Filtering on "+match[1]+" is not supported", scimInvalidFilter)
+	}
+	return result, nil
+}
+
+// scimPage normalizes the 1-based startIndex and the page size
+func scimPage(startIndex, count int) (int, int) {
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	if count < 1 || count > authDomain.SCIMMaxResults {
+		count = authDomain.SCIMMaxResults
+	}
+	return startIndex, count
+}
+
+func scimList(total int64, startIndex, items int, resources interface{}) *authDomain.SCIMListResponse {
+	return &authDomain.SCIMListResponse{
+		Schemas:      []string{authDomain.SCIMSchemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: items,
+		Resources:    resources,
+	}
+}
+
+// userPatch holds the writable user attributes set by PATCH operations
+type userPatch struct {
+	active     *bool
+	externalID *string
+	userName   *string
+}
+
+// parseUserPatch reads PATCH operations in both the path form
+// ({"op":"replace","path":"active","value":false}) and the value form
+// ({"op":"replace","value":{"active":false}}). Read-only and unknown attributes are ignored.
+func parseUserPatch(operations []authDomain.SCIMPatchOperation) (*userPatch, error) {
+	patch := &userPatch{}
+	for _, op := range operations {
+		kind, err := patchOp(op)
+		if err != nil {
+			return nil, err
+		}
+		path := strings.ToLower(strings.TrimSpace(op.Path))
+
+		if kind == "remove" {
+			if path == "" {
+				return nil, appErrors.NewValidationError("remove requires a path", scimNoTarget)
+			}
+			if path == "externalid" {
+				empty := ""
+				patch.externalID = &empty
+			}
+			continue
+		}
+
+		if path != "" {
+			if err := patch.set(path, op.Value); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		var attributes map[string]json.RawMessage
+		if err := json.Unmarshal(op.Value, &attributes); err != nil {
+			return nil, appErrors.NewValidationError("PATCH value without a path must be an object", scimInvalidSyntax)
+		}
+		for name, value := range attributes {
+			if err := patch.set(strings.ToLower(name), value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return patch, nil
+}
+
+func (p *userPatch) set(attribute string, value json.RawMessage) error {
+	switch attribute {
+	case "active":
+		active, err := parseSCIMBool(value)
+		if err != nil {
+			return err
+		}
+		p.active = &active
+	case "externalid":
+		externalID, err := parseSCIMString(value)
+		if err != nil {
+			return err
+		}
+		p.externalID = &externalID
+	case "username":
+		userName, err := parseSCIMString(value)
+		if err != nil {
+			return err
+		}
+		p.userName = &userName
+	}
+	return nil
+}
+
+// groupPatch holds the changes of group PATCH operations
+type groupPatch struct {
+	displayName    *string
+	externalID     *string
+	add            []ulid.ULID
+	remove         []ulid.ULID
+	replaceMembers bool // add is the complete member list
+	removeAll      bool
+}
+
+// parseGroupPatch reads PATCH operations on displayName, externalId and members
+func parseGroupPatch(operations []authDomain.SCIMPatchOperation) (*groupPatch, error) {
+	patch := &groupPatch{}
+	for _, op := range operations {
+		kind, err := patchOp(op)
+		if err != nil {
+			return nil, err
+		}
+		path := strings.TrimSpace(op.Path)
+
+		if match := scimMemberPath.FindStringSubmatch(path); match != nil {
+			if kind != "remove" {
+				return nil, appErrors.NewValidationError("Only remove supports a member filter", scimInvalidPath)
+			}
+			if userID, err := ulid.Parse(match[1]); err == nil {
+				patch.remove = append(patch.remove, userID)
+			}
+			continue
+		}
+
+		if path != "" {
+			if err := patch.apply(kind, strings.ToLower(path), op.Value); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if kind == "remove" {
+			return nil, appErrors.NewValidationError("remove requires a path", scimNoTarget)
+		}
+		var attributes map[string]json.RawMessage
+		if err := json.Unmarshal(op.Value, &attributes); err != nil {
+			return nil, appErrors.NewValidationError("PATCH value without a path must be an object", scimInvalidSyntax)
+		}
+		for name, value := range attributes {
+			if err := patch.apply(kind, strings.ToLower(name), value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return patch, nil
+}
+
+func (p *groupPatch) apply(kind, attribute string, value json.RawMessage) error {
+	switch attribute {
+	case "displayname":
+		if kind == "remove" {
+			return appErrors.NewValidationError("displayName is required", scimMutability)
+		}
+		displayName, err := parseSCIMString(value)
+		if err != nil {
+			return err
+		}
+		p.displayName = &displayName
+	case "externalid":
+		externalID := ""
+		if kind != "remove" {
+			var err error
+			if externalID, err = parseSCIMString(value); err != nil {
+				return err
+			}
+		}
+		p.externalID = &externalID
+	case "members":
+		members, err := parseMemberIDs(value)
+		if err != nil {
+			return err
+		}
+		switch kind {
+		case "add":
+			p.add = append(p.add, members...)
+		case "replace":
+			p.replaceMembers, p.removeAll = true, false
+			p.add = members
+		case "remove":
+			if len(members) == 0 {
+				p.removeAll = true
+			}
+			p.remove = append(p.remove, members...)
+		}
+	}
+	return nil
+}
+
+func patchOp(op authDomain.SCIMPatchOperation) (string, error) {
+	kind := strings.ToLower(strings.TrimSpace(op.Op))
+	switch kind {
+	case "add", "replace", "remove":
+		return kind, nil
+	default:
+		return "", appErrors.NewValidationError("Unsupported PATCH op "+op.Op, scimInvalidSyntax)
+	}
+}
+
+// parseSCIMBool accepts JSON booleans and the "True"/"False" strings some IdPs send
+func parseSCIMBool(value json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, nil
+	}
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		if b, err := strconv.ParseBool(strings.TrimSpace(s)); err == nil {
+			return b, nil
+		}
+	}
+	return false, appErrors.NewValidationError("Expected a boolean value", scimInvalidValue)
+}
+
+func parseSCIMString(value json.RawMessage) (string, error) {
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		return "", appErrors.NewValidationError("Expected a string value", scimInvalidValue)
+	}
+	return s, nil
+}
+
+// parseMemberIDs reads a list of member references; a missing value is an empty list
+func parseMemberIDs(value json.RawMessage) ([]ulid.ULID, error) {
+	if len(value) == 0 || string(value) == "null" {
+		return nil, nil
+	}
+	var members []authDomain.SCIMReference
+	if err := json.Unmarshal(value, &members); err != nil {
+		return nil, appErrors.NewValidationError("members must be a list of references", scimInvalidValue)
+	}
+	ids := make([]ulid.ULID, 0, len(members))
+	for _, member := range members {
+		userID, err := ulid.Parse(member.Value)
+		if err != nil {
+			return nil, appErrors.NewValidationError("Unknown group member "+member.Value, scimInvalidValue)
+		}
+		ids = append(ids, userID)
+	}
+	return ids, nil
+}
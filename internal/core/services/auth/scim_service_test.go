@@ -0,0 +1,604 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authDomain "brokle/internal/core/domain/auth"
+	orgDomain "brokle/internal/core/domain/organization"
+	userDomain "brokle/internal/core/domain/user"
+	appErrors "brokle/pkg/errors"
+	"brokle/pkg/ulid"
+)
+
+type memorySCIMRepository struct {
+	tokens  map[string]*authDomain.SCIMToken
+	links   map[ulid.ULID]*authDomain.SCIMUserLink
+	groups  map[ulid.ULID]*authDomain.SCIMGroup
+	members map[ulid.ULID][]ulid.ULID
+}
+
+func newMemorySCIMRepository() *memorySCIMRepository {
+	return &memorySCIMRepository{
+		tokens:  map[string]*authDomain.SCIMToken{},
+		links:   map[ulid.ULID]*authDomain.SCIMUserLink{},
+		groups:  map[ulid.ULID]*authDomain.SCIMGroup{},
+		members: map[ulid.ULID][]ulid.ULID{},
+	}
+}
+
+func (r *memorySCIMRepository) CreateToken(ctx context.Context, token *authDomain.SCIMToken) error {
+	r.tokens[token.TokenHash] = token
+	return nil
+}
+
+func (r *memorySCIMRepository) GetTokenByHash(ctx context.Context, tokenHash string) (*authDomain.SCIMToken, error) {
+	token, ok := r.tokens[tokenHash]
+	if !ok {
+		return nil, fmt.Errorf("get scim token: %w", authDomain.ErrNotFound)
+	}
+	return token, nil
+}
+
+func (r *memorySCIMRepository) ListTokens(ctx context.Context, orgID ulid.ULID) ([]*authDomain.SCIMToken, error) {
+	return nil, nil
+}
+
+func (r *memorySCIMRepository) DeleteToken(ctx context.Context, orgID, tokenID ulid.ULID) error {
+	for hash, token := range r.tokens {
+		if token.ID == tokenID && token.OrganizationID == orgID {
+			delete(r.tokens, hash)
+			return nil
+		}
+	}
+	return fmt.Errorf("delete scim token: %w", authDomain.ErrNotFound)
+}
+
+func (r *memorySCIMRepository) UpdateTokenLastUsed(ctx context.Context, tokenID ulid.ULID, at time.Time) error {
+	return nil
+}
+
+func (r *memorySCIMRepository) GetUserLink(ctx context.Context, orgID, userID ulid.ULID) (*authDomain.SCIMUserLink, error) {
+	link, ok := r.links[userID]
+	if !ok || link.OrganizationID != orgID {
+		return nil, fmt.Errorf("get scim user: %w", authDomain.ErrNotFound)
+	}
+	copied := *link
+	return &copied, nil
+}
+
+func (r *memorySCIMRepository) SaveUserLink(ctx context.Context, link *authDomain.SCIMUserLink) error {
+	copied := *link
+	r.links[link.UserID] = &copied
+	return nil
+}
+
+func (r *memorySCIMRepository) DeleteUserLink(ctx context.Context, orgID, userID ulid.ULID) error {
+	delete(r.links, userID)
+	return nil
+}
+
+func (r *memorySCIMRepository) ListUserLinks(ctx context.Context, orgID ulid.ULID, filter authDomain.SCIMListFilter) ([]*authDomain.SCIMUserLink, int64, error) {
+	var links []*authDomain.SCIMUserLink
+	for _, link := range r.links {
+		if link.OrganizationID == orgID && (filter.ExternalID == "" || link.ExternalID == filter.ExternalID) {
+			links = append(links, link)
+		}
+	}
+	return links, int64(len(links)), nil
+}
+
+func (r *memorySCIMRepository) CreateGroup(ctx context.Context, group *authDomain.SCIMGroup) error {
+	r.groups[group.ID] = group
+	return nil
+}
+
+func (r *memorySCIMRepository) GetGroup(ctx context.Context, orgID, groupID ulid.ULID) (*authDomain.SCIMGroup, error) {
+	group, ok := r.groups[groupID]
+	if !ok || group.OrganizationID != orgID {
+		return nil, fmt.Errorf("get scim group: %w", authDomain.ErrNotFound)
+	}
+	return group, nil
+}
+
+func (r *memorySCIMRepository) UpdateGroup(ctx context.Context, group *authDomain.SCIMGroup) error {
+	r.groups[group.ID] = group
+	return nil
+}
+
+func (r *memorySCIMRepository) DeleteGroup(ctx context.Context, orgID, groupID ulid.ULID) error {
+	delete(r.groups, groupID)
+	delete(r.members, groupID)
+	return nil
+}
+
+func (r *memorySCIMRepository) ListGroups(ctx context.Context, orgID ulid.ULID, filter authDomain.SCIMListFilter) ([]*authDomain.SCIMGroup, int64, error) {
+	var groups []*authDomain.SCIMGroup
+	for _, group := range r.groups {
+		if group.OrganizationID == orgID && (filter.DisplayName == "" || strings.EqualFold(group.DisplayName, filter.DisplayName)) {
+			groups = append(groups, group)
+		}
+	}
+	return groups, int64(len(groups)), nil
+}
+
+func (r *memorySCIMRepository) HasRoleMappings(ctx context.Context, orgID ulid.ULID) (bool, error) {
+	for _, group := range r.groups {
+		if group.OrganizationID == orgID && group.RoleID != nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memorySCIMRepository) ListGroupMembers(ctx context.Context, groupID ulid.ULID) ([]ulid.ULID, error) {
+	return slices.Clone(r.members[groupID]), nil
+}
+
+func (r *memorySCIMRepository) AddGroupMembers(ctx context.Context, groupID ulid.ULID, userIDs []ulid.ULID) error {
+	for _, userID := range userIDs {
+		if !slices.Contains(r.members[groupID], userID) {
+			r.members[groupID] = append(r.members[groupID], userID)
+		}
+	}
+	return nil
+}
+
+func (r *memorySCIMRepository) RemoveGroupMembers(ctx context.Context, groupID ulid.ULID, userIDs []ulid.ULID) error {
+	r.members[groupID] = slices.DeleteFunc(r.members[groupID], func(id ulid.ULID) bool { return slices.Contains(userIDs, id) })
+	return nil
+}
+
+func (r *memorySCIMRepository) ListUserGroups(ctx context.Context, orgID, userID ulid.ULID) ([]*authDomain.SCIMGroup, error) {
+	var groups []*authDomain.SCIMGroup
+	for groupID, members := range r.members {
+		if slices.Contains(members, userID) && r.groups[groupID].OrganizationID == orgID {
+			groups = append(groups, r.groups[groupID])
+		}
+	}
+	return groups, nil
+}
+
+func (r *memorySCIMRepository) RemoveUserFromGroups(ctx context.Context, orgID, userID ulid.ULID) error {
+	for groupID := range r.members {
+		_ = r.RemoveGroupMembers(ctx, groupID, []ulid.ULID{userID})
+	}
+	return nil
+}
+
+// scimUserRepository stores users by email; other Repository methods are not used
+type scimUserRepository struct {
+	userDomain.Repository
+	users map[string]*userDomain.User
+}
+
+func (r *scimUserRepository) GetByEmail(ctx context.Context, email string) (*userDomain.User, error) {
+	user, ok := r.users[email]
+	if !ok {
+		return nil, fmt.Errorf("get user by email: %w", userDomain.ErrNotFound)
+	}
+	return user, nil
+}
+
+func (r *scimUserRepository) GetByID(ctx context.Context, id ulid.ULID) (*userDomain.User, error) {
+	for _, user := range r.users {
+		if user.ID == id {
+			return user, nil
+		}
+	}
+	return nil, fmt.Errorf("get user: %w", userDomain.ErrNotFound)
+}
+
+func (r *scimUserRepository) Create(ctx context.Context, user *userDomain.User) error {
+	r.users[user.Email] = user
+	return nil
+}
+
+func (r *scimUserRepository) CreateProfile(ctx context.Context, profile *userDomain.UserProfile) error {
+	return nil
+}
+
+// scimMemberService keeps organization members in memory
+type scimMemberService struct {
+	orgDomain.MemberService
+	members map[ulid.ULID]*orgDomain.Member
+}
+
+func (s *scimMemberService) GetMember(ctx context.Context, orgID, userID ulid.ULID) (*orgDomain.Member, error) {
+	member, ok := s.members[userID]
+	if !ok {
+		return nil, fmt.Errorf("get member: %w", orgDomain.ErrMemberNotFound)
+	}
+	return member, nil
+}
+
+func (s *scimMemberService) IsMember(ctx context.Context, userID, orgID ulid.ULID) (bool, error) {
+	_, ok := s.members[userID]
+	return ok, nil
+}
+
+func (s *scimMemberService) AddMember(ctx context.Context, orgID, userID, roleID ulid.ULID, addedByID ulid.ULID) error {
+	s.members[userID] = orgDomain.NewMember(orgID, userID, roleID)
+	return nil
+}
+
+func (s *scimMemberService) UpdateMemberRole(ctx context.Context, orgID, userID, roleID ulid.ULID, updatedByID ulid.ULID) error {
+	s.members[userID].RoleID = roleID
+	return nil
+}
+
+func (s *scimMemberService) RemoveMember(ctx context.Context, orgID, userID ulid.ULID, removedByID ulid.ULID) error {
+	delete(s.members, userID)
+	return nil
+}
+
+// scimSessionService records revocations
+type scimSessionService struct {
+	authDomain.SessionService
+	revoked []ulid.ULID
+}
+
+func (s *scimSessionService) RevokeUserSessions(ctx context.Context, userID ulid.ULID) error {
+	s.revoked = append(s.revoked, userID)
+	return nil
+}
+
+type scimBlacklistedTokens struct {
+	authDomain.BlacklistedTokenService
+	revoked []ulid.ULID
+}
+
+func (s *scimBlacklistedTokens) BlacklistUserTokens(ctx context.Context, userID ulid.ULID, reason string) error {
+	s.revoked = append(s.revoked, userID)
+	return nil
+}
+
+// scimRoleService serves the system organization roles, each with one more permission than the last
+type scimRoleService struct {
+	authDomain.RoleService
+	roles []*authDomain.Role
+}
+
+func newSCIMRoleService() *scimRoleService {
+	service := &scimRoleService{}
+	for _, name := range []string{"viewer", "developer", "admin", "owner"} {
+		service.roles = append(service.roles, &authDomain.Role{ID: ulid.New(), Name: name, ScopeType: authDomain.ScopeOrganization})
+	}
+	return service
+}
+
+func (s *scimRoleService) role(name string) *authDomain.Role {
+	for _, role := range s.roles {
+		if role.Name == name {
+			return role
+		}
+	}
+	return nil
+}
+
+func (s *scimRoleService) GetRoleByNameAndScope(ctx context.Context, name, scopeType string) (*authDomain.Role, error) {
+	if role := s.role(name); role != nil {
+		return role, nil
+	}
+	return nil, appErrors.NewNotFoundError("Role")
+}
+
+func (s *scimRoleService) GetRoleByID(ctx context.Context, roleID ulid.ULID) (*authDomain.Role, error) {
+	for _, role := range s.roles {
+		if role.ID == roleID {
+			return role, nil
+		}
+	}
+	return nil, appErrors.NewNotFoundError("Role")
+}
+
+func (s *scimRoleService) GetRolesByScopeType(ctx context.Context, scopeType string) ([]*authDomain.Role, error) {
+	return s.roles, nil
+}
+
+func (s *scimRoleService) GetCustomRolesByOrganization(ctx context.Context, organizationID ulid.ULID) ([]*authDomain.Role, error) {
+	return nil, nil
+}
+
+func (s *scimRoleService) GetRolePermissions(ctx context.Context, roleID ulid.ULID) ([]*authDomain.Permission, error) {
+	for i, role := range s.roles {
+		if role.ID == roleID {
+			return make([]*authDomain.Permission, i+1), nil
+		}
+	}
+	return nil, nil
+}
+
+// orgPermissions grants permissions per organization member; other methods are not used
+type orgPermissions struct {
+	authDomain.OrganizationMemberService
+	granted map[ulid.ULID]map[ulid.ULID][]string // orgID -> userID -> permissions
+}
+
+func (p *orgPermissions) grant(orgID, userID ulid.ULID, permissions ...string) {
+	if p.granted == nil {
+		p.granted = map[ulid.ULID]map[ulid.ULID][]string{}
+	}
+	if p.granted[orgID] == nil {
+		p.granted[orgID] = map[ulid.ULID][]string{}
+	}
+	p.granted[orgID][userID] = append(p.granted[orgID][userID], permissions...)
+}
+
+func (p *orgPermissions) RequireOrganizationPermission(ctx context.Context, userID, orgID ulid.ULID, permission string) error {
+	if !slices.Contains(p.granted[orgID][userID], permission) {
+		return appErrors.NewForbiddenError("Insufficient permissions in this organization")
+	}
+	return nil
+}
+
+type scimTestEnv struct {
+	service  *scimService
+	repo     *memorySCIMRepository
+	users    *scimUserRepository
+	members  *scimMemberService
+	sessions *scimSessionService
+	tokens   *scimBlacklistedTokens
+	roles    *scimRoleService
+	admins   *orgPermissions
+	token    *authDomain.SCIMToken
+}
+
+func newSCIMTestEnv() *scimTestEnv {
+	env := &scimTestEnv{
+		repo:     newMemorySCIMRepository(),
+		users:    &scimUserRepository{users: map[string]*userDomain.User{}},
+		members:  &scimMemberService{members: map[ulid.ULID]*orgDomain.Member{}},
+		sessions: &scimSessionService{},
+		tokens:   &scimBlacklistedTokens{},
+		roles:    newSCIMRoleService(),
+		admins:   &orgPermissions{},
+		token:    &authDomain.SCIMToken{ID: ulid.New(), OrganizationID: ulid.New(), CreatedBy: ulid.New()},
+	}
+	// The organization has verified acme.com; another organization owns other.com
+	verified := time.Now()
+	ssoRepo := &ssoDomainRepository{connections: map[string]*authDomain.SSOConnection{
+		"acme.com":  {Domain: "acme.com", OrganizationID: env.token.OrganizationID, DomainVerifiedAt: &verified},
+		"other.com": {Domain: "other.com", OrganizationID: ulid.New(), DomainVerifiedAt: &verified},
+	}}
+	env.service = NewSCIMService(env.repo, ssoRepo, env.users, env.members, env.sessions, env.tokens, env.roles, env.admins,
+		slog.New(slog.NewTextHandler(io.Discard, nil))).(*scimService)
+	return env
+}
+
+func (e *scimTestEnv) createUser(t *testing.T, email string) *authDomain.SCIMUser {
+	user, err := e.service.CreateUser(context.Background(), e.token, &authDomain.SCIMUser{
+		UserName: email,
+		Name:     &authDomain.SCIMName{GivenName: "Jane", FamilyName: "Doe"},
+	})
+	require.NoError(t, err)
+	return user
+}
+
+func (e *scimTestEnv) memberRole(t *testing.T, userID string) string {
+	id, err := ulid.Parse(userID)
+	require.NoError(t, err)
+	member, ok := e.members.members[id]
+	if !ok {
+		return ""
+	}
+	role, err := e.roles.GetRoleByID(context.Background(), member.RoleID)
+	require.NoError(t, err)
+	return role.Name
+}
+
+func patchRequest(t *testing.T, ops ...map[string]interface{}) *authDomain.SCIMPatchRequest {
+	raw, err := json.Marshal(map[string]interface{}{"schemas": []string{authDomain.SCIMSchemaPatchOp}, "Operations": ops})
+	require.NoError(t, err)
+	var req authDomain.SCIMPatchRequest
+	require.NoError(t, json.Unmarshal(raw, &req))
+	return &req
+}
+
+func TestSCIMService_Authenticate(t *testing.T) {
+	ctx := context.Background()
+	env := newSCIMTestEnv()
+	orgID, adminID, outsiderID := ulid.New(), ulid.New(), ulid.New()
+	env.admins.grant(orgID, adminID, "settings:security")
+	// Holds the permission, but in another organization
+	env.admins.grant(ulid.New(), outsiderID, "settings:security")
+
+	_, err := env.service.CreateToken(ctx, orgID, outsiderID, &authDomain.CreateSCIMTokenRequest{Name: "Okta"})
+	assert.True(t, isAppErrorType(err, appErrors.ForbiddenError))
+
+	created, err := env.service.CreateToken(ctx, orgID, adminID, &authDomain.CreateSCIMTokenRequest{Name: "Okta"})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(created.Token, authDomain.SCIMTokenPrefix))
+	assert.NotContains(t, created.TokenPreview, created.Token[len(authDomain.SCIMTokenPrefix)+4:len(created.Token)-4])
+
+	token, err := env.service.Authenticate(ctx, created.Token)
+	require.NoError(t, err)
+	assert.Equal(t, orgID, token.OrganizationID)
+
+	_, err = env.service.Authenticate(ctx, created.Token+"x")
+	assert.True(t, isAppErrorType(err, appErrors.UnauthorizedError))
+
+	_, err = env.service.ListTokens(ctx, orgID, outsiderID)
+	assert.True(t, isAppErrorType(err, appErrors.ForbiddenError))
+	err = env.service.DeleteToken(ctx, orgID, created.ID, outsiderID)
+	assert.True(t, isAppErrorType(err, appErrors.ForbiddenError))
+
+	require.NoError(t, env.service.DeleteToken(ctx, orgID, created.ID, adminID))
+	_, err = env.service.Authenticate(ctx, created.Token)
+	assert.True(t, isAppErrorType(err, appErrors.UnauthorizedError))
+}
+
+func TestSCIMService_UserLifecycle(t *testing.T) {
+	ctx := context.Background()
+	env := newSCIMTestEnv()
+
+	user := env.createUser(t, "Jane@Acme.com")
+	assert.Equal(t, "jane@acme.com", user.UserName)
+	assert.True(t, *user.Active)
+	assert.Equal(t, "viewer", env.memberRole(t, user.ID), "new users join with the default role")
+	assert.True(t, env.users.users["jane@acme.com"].IsEmailVerified)
+
+	_, err := env.service.CreateUser(ctx, env.token, &authDomain.SCIMUser{UserName: "jane@acme.com"})
+	assert.True(t, isAppErrorType(err, appErrors.ConflictError))
+
+	t.Run("deactivation removes the member and revokes sessions", func(t *testing.T) {
+		// Azure AD sends booleans as strings
+		patched, err := env.service.PatchUser(ctx, env.token, user.ID, patchRequest(t,
+			map[string]interface{}{"op": "Replace", "path": "active", "value": "False"}))
+		require.NoError(t, err)
+		assert.False(t, *patched.Active)
+		assert.Empty(t, env.memberRole(t, user.ID))
+		assert.Equal(t, user.ID, env.sessions.revoked[0].String())
+		assert.Equal(t, user.ID, env.tokens.revoked[0].String())
+	})
+
+	t.Run("reactivation restores the membership", func(t *testing.T) {
+		_, err := env.service.PatchUser(ctx, env.token, user.ID, patchRequest(t,
+			map[string]interface{}{"op": "replace", "value": map[string]interface{}{"active": true}}))
+		require.NoError(t, err)
+		assert.Equal(t, "viewer", env.memberRole(t, user.ID))
+		assert.Len(t, env.sessions.revoked, 1)
+	})
+
+	t.Run("userName is immutable", func(t *testing.T) {
+		_, err := env.service.ReplaceUser(ctx, env.token, user.ID, &authDomain.SCIMUser{UserName: "other@acme.com"})
+		assert.True(t, isAppErrorType(err, appErrors.ValidationError))
+	})
+
+	t.Run("delete deprovisions", func(t *testing.T) {
+		require.NoError(t, env.service.DeleteUser(ctx, env.token, user.ID))
+		assert.Empty(t, env.memberRole(t, user.ID))
+		assert.Len(t, env.sessions.revoked, 2)
+		_, err := env.service.GetUser(ctx, env.token, user.ID)
+		assert.True(t, isAppErrorType(err, appErrors.NotFoundError))
+	})
+}
+
+func TestSCIMService_ExistingAccounts(t *testing.T) {
+	ctx := context.Background()
+	env := newSCIMTestEnv()
+	existing := func(email string) *userDomain.User {
+		user := userDomain.NewUser(email, "Sam", "Roe", "other")
+		env.users.users[email] = user
+		return user
+	}
+
+	t.Run("accounts outside verified domains are not linked", func(t *testing.T) {
+		victim := existing("victim@other.com")
+		_, err := env.service.CreateUser(ctx, env.token, &authDomain.SCIMUser{UserName: "victim@other.com"})
+		assert.True(t, isAppErrorType(err, appErrors.ConflictError))
+		assert.NotContains(t, env.members.members, victim.ID)
+	})
+
+	t.Run("accounts in the organization's verified domain are linked", func(t *testing.T) {
+		existing("sam@acme.com")
+		_, err := env.service.CreateUser(ctx, env.token, &authDomain.SCIMUser{UserName: "sam@acme.com"})
+		require.NoError(t, err)
+	})
+
+	t.Run("existing members are linked but keep their sessions on deactivation", func(t *testing.T) {
+		guest := existing("guest@gmail.com")
+		require.NoError(t, env.members.AddMember(ctx, env.token.OrganizationID, guest.ID, env.roles.role("developer").ID, ulid.New()))
+
+		active := false
+		_, err := env.service.CreateUser(ctx, env.token, &authDomain.SCIMUser{UserName: "guest@gmail.com", Active: &active})
+		require.NoError(t, err)
+		assert.NotContains(t, env.members.members, guest.ID)
+		assert.Empty(t, env.sessions.revoked)
+		assert.Empty(t, env.tokens.revoked)
+	})
+
+	t.Run("new accounts outside verified domains are not email verified", func(t *testing.T) {
+		env.createUser(t, "new@gmail.com")
+		assert.False(t, env.users.users["new@gmail.com"].IsEmailVerified)
+	})
+}
+
+func TestSCIMService_GroupRoles(t *testing.T) {
+	ctx := context.Background()
+	env := newSCIMTestEnv()
+	jane := env.createUser(t, "jane@acme.com")
+	john := env.createUser(t, "john@acme.com")
+
+	// A group named after a role maps to it and takes over role management
+	admins, err := env.service.CreateGroup(ctx, env.token, &authDomain.SCIMGroupResource{
+		DisplayName: "Admin",
+		Members:     []authDomain.SCIMReference{{Value: jane.ID}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "admin", env.memberRole(t, jane.ID))
+	assert.Equal(t, "viewer", env.memberRole(t, john.ID))
+
+	developers, err := env.service.CreateGroup(ctx, env.token, &authDomain.SCIMGroupResource{DisplayName: "developer"})
+	require.NoError(t, err)
+	_, err = env.service.PatchGroup(ctx, env.token, developers.ID, patchRequest(t,
+		map[string]interface{}{"op": "add", "path": "members", "value": []map[string]string{{"value": jane.ID}, {"value": john.ID}}}))
+	require.NoError(t, err)
+	assert.Equal(t, "admin", env.memberRole(t, jane.ID), "the role with the most permissions wins")
+	assert.Equal(t, "developer", env.memberRole(t, john.ID))
+
+	_, err = env.service.PatchGroup(ctx, env.token, admins.ID, patchRequest(t,
+		map[string]interface{}{"op": "remove", "path": fmt.Sprintf(`members[value eq "%s"]`, jane.ID)}))
+	require.NoError(t, err)
+	assert.Equal(t, "developer", env.memberRole(t, jane.ID))
+
+	_, err = env.service.CreateGroup(ctx, env.token, &authDomain.SCIMGroupResource{DisplayName: "ADMIN"})
+	assert.True(t, isAppErrorType(err, appErrors.ConflictError))
+
+	_, err = env.service.CreateGroup(ctx, env.token, &authDomain.SCIMGroupResource{
+		DisplayName: "Engineering",
+		Members:     []authDomain.SCIMReference{{Value: ulid.New().String()}},
+	})
+	assert.True(t, isAppErrorType(err, appErrors.ValidationError), "members must be provisioned users")
+
+	t.Run("owners keep their role", func(t *testing.T) {
+		janeID, _ := ulid.Parse(jane.ID)
+		env.members.members[janeID].RoleID = env.roles.role("owner").ID
+		require.NoError(t, env.service.DeleteGroup(ctx, env.token, developers.ID))
+		assert.Equal(t, "owner", env.memberRole(t, jane.ID))
+		assert.Equal(t, "viewer", env.memberRole(t, john.ID))
+	})
+
+	t.Run("groups cannot grant ownership", func(t *testing.T) {
+		groupID, _ := ulid.Parse(admins.ID)
+		ownerID := env.roles.role("owner").ID
+		adminID := ulid.New()
+		env.admins.grant(env.token.OrganizationID, adminID, "settings:security")
+		_, err := env.service.SetGroupRole(ctx, env.token.OrganizationID, groupID, &ownerID, adminID)
+		assert.True(t, isAppErrorType(err, appErrors.ValidationError))
+	})
+
+	t.Run("only admins of the organization map roles", func(t *testing.T) {
+		groupID, _ := ulid.Parse(admins.ID)
+		viewerID := env.roles.role("viewer").ID
+		outsiderID := ulid.New()
+		env.admins.grant(ulid.New(), outsiderID, "settings:security")
+		_, err := env.service.SetGroupRole(ctx, env.token.OrganizationID, groupID, &viewerID, outsiderID)
+		assert.True(t, isAppErrorType(err, appErrors.ForbiddenError))
+	})
+}
+
+func TestParseSCIMFilter(t *testing.T) {
+	filter, err := parseSCIMFilter(`userName eq "jane@acme.com"`, "userName", "externalId")
+	require.NoError(t, err)
+	assert.Equal(t, "jane@acme.com", filter.Email)
+
+	filter, err = parseSCIMFilter(`DISPLAYNAME Eq "Eng \"core\""`, "displayName")
+	require.NoError(t, err)
+	assert.Equal(t, `Eng "core"`, filter.DisplayName)
+
+	for _, invalid := range []string{`userName co "jane"`, `title eq "x"`, `userName eq "a" and active eq true`} {
+		_, err := parseSCIMFilter(invalid, "userName")
+		assert.True(t, isAppErrorType(err, appErrors.ValidationError), invalid)
+	}
+}
@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	authDomain "brokle/internal/core/domain/auth"
+	appErrors "brokle/pkg/errors"
+)
+
+// CheckSocialLoginAllowed rejects Google and GitHub login for domains whose SSO
+// connection enforces SSO, so enforcement cannot be bypassed through another provider
+func (s *authService) CheckSocialLoginAllowed(ctx context.Context, email string) error {
+	return s.checkPasswordLoginAllowed(ctx, email)
+}
+
+// checkPasswordLoginAllowed rejects password login for domains whose SSO connection enforces SSO
+func (s *authService) checkPasswordLoginAllowed(ctx context.Context, email string) error {
+	if s.ssoRepo == nil {
+		return nil
+	}
+
+	conn, err := s.ssoRepo.GetByDomain(ctx, authDomain.EmailDomain(email))
+	if err != nil {
+		if errors.Is(err, authDomain.ErrNotFound) {
+			return nil
+		}
+		return appErrors.NewInternalError("Authentication service unavailable", err)
+	}
+	if conn.EnforcesSSO() {
+		return appErrors.NewForbiddenError("This account must sign in with SSO")
+	}
+	return nil
+}
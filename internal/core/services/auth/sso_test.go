@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	authDomain "brokle/internal/core/domain/auth"
+	appErrors "brokle/pkg/errors"
+)
+
+// ssoDomainRepository serves connections by domain; other methods are not used
+type ssoDomainRepository struct {
+	authDomain.SSOConnectionRepository
+	connections map[string]*authDomain.SSOConnection
+}
+
+func (r *ssoDomainRepository) GetByDomain(ctx context.Context, domain string) (*authDomain.SSOConnection, error) {
+	conn, ok := r.connections[domain]
+	if !ok {
+		return nil, fmt.Errorf("get sso connection by domain %s: %w", domain, authDomain.ErrNotFound)
+	}
+	return conn, nil
+}
+
+func TestAuthService_PasswordLoginWithSSO(t *testing.T) {
+	ctx := context.Background()
+	verified := time.Now()
+	repo := &ssoDomainRepository{connections: map[string]*authDomain.SSOConnection{
+		"enforced.com":   {Domain: "enforced.com", Enabled: true, EnforceSSO: true, DomainVerifiedAt: &verified},
+		"optional.com":   {Domain: "optional.com", Enabled: true, DomainVerifiedAt: &verified},
+		"unverified.com": {Domain: "unverified.com", Enabled: true, EnforceSSO: true},
+	}}
+	service := &authService{ssoRepo: repo}
+
+	err := service.checkPasswordLoginAllowed(ctx, "jane@Enforced.com")
+	assert.True(t, isAppErrorType(err, appErrors.ForbiddenError))
+
+	assert.NoError(t, service.checkPasswordLoginAllowed(ctx, "jane@optional.com"))
+	assert.NoError(t, service.checkPasswordLoginAllowed(ctx, "jane@unverified.com"), "enforcement starts once the domain is verified")
+	assert.NoError(t, service.checkPasswordLoginAllowed(ctx, "jane@example.com"))
+	assert.NoError(t, (&authService{}).checkPasswordLoginAllowed(ctx, "jane@enforced.com"))
+
+	// Google and GitHub logins follow the same enforcement
+	err = service.CheckSocialLoginAllowed(ctx, "jane@enforced.com")
+	assert.True(t, isAppErrorType(err, appErrors.ForbiddenError))
+	assert.NoError(t, service.CheckSocialLoginAllowed(ctx, "jane@optional.com"))
+}
@@ -17,27 +17,34 @@ import (
 )
 
 type budgetService struct {
-	budgetRepo  billing.UsageBudgetRepository
-	alertRepo   billing.UsageAlertRepository
-	projectRepo orgDomain.ProjectRepository
-	logger      *slog.Logger
+	budgetRepo         billing.UsageBudgetRepository
+	alertRepo          billing.UsageAlertRepository
+	projectRepo        orgDomain.ProjectRepository
+	enforcementService billing.EnforcementService
+	logger             *slog.Logger
 }
 
 func NewBudgetService(
 	budgetRepo billing.UsageBudgetRepository,
 	alertRepo billing.UsageAlertRepository,
 	projectRepo orgDomain.ProjectRepository,
+	enforcementService billing.EnforcementService,
 	logger *slog.Logger,
 ) billing.BudgetService {
 	return &budgetService{
-		budgetRepo:  budgetRepo,
-		alertRepo:   alertRepo,
-		projectRepo: projectRepo,
-		logger:      logger,
+		budgetRepo:         budgetRepo,
+		alertRepo:          alertRepo,
+		projectRepo:        projectRepo,
+		enforcementService: enforcementService,
+		logger:             logger,
 	}
 }
 
 func (s *budgetService) CreateBudget(ctx context.Context, budget *billing.UsageBudget) error {
+	if err := validateEnforcement(budget); err != nil {
+		return err
+	}
+
 	// Validate project ownership if project_id is provided
 	if budget.ProjectID != nil && !budget.ProjectID.IsZero() {
 		project, err := s.projectRepo.GetByID(ctx, *budget.ProjectID)
@@ -91,6 +98,8 @@ func (s *budgetService) CreateBudget(ctx context.Context, budget *billing.UsageB
 		"name", budget.Name,
 	)
 
+	s.refreshEnforcement(ctx, budget.OrganizationID)
+
 	return nil
 }
 
@@ -103,6 +112,10 @@ func (s *budgetService) GetBudgetsByOrg(ctx context.Context, orgID ulid.ULID) ([
 }
 
 func (s *budgetService) UpdateBudget(ctx context.Context, budget *billing.UsageBudget) error {
+	if err := validateEnforcement(budget); err != nil {
+		return err
+	}
+
 	budget.UpdatedAt = time.Now()
 
 	if err := s.budgetRepo.Update(ctx, budget); err != nil {
@@ -118,10 +131,18 @@ func (s *budgetService) UpdateBudget(ctx context.Context, budget *billing.UsageB
 		"organization_id", budget.OrganizationID,
 	)
 
+	// Raising a limit or disabling enforcement takes effect without waiting for the next usage sync
+	s.refreshEnforcement(ctx, budget.OrganizationID)
+
 	return nil
 }
 
 func (s *budgetService) DeleteBudget(ctx context.Context, id ulid.ULID) error {
+	budget, err := s.budgetRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
 	if err := s.budgetRepo.Delete(ctx, id); err != nil {
 		s.logger.Error("failed to delete budget",
 			"error", err,
@@ -134,9 +155,41 @@ func (s *budgetService) DeleteBudget(ctx context.Context, id ulid.ULID) error {
 		"budget_id", id,
 	)
 
+	s.refreshEnforcement(ctx, budget.OrganizationID)
+
 	return nil
 }
 
+// validateEnforcement checks the enforcement settings and defaults the action to none
+func validateEnforcement(budget *billing.UsageBudget) error {
+	if budget.EnforcementAction == "" {
+		budget.EnforcementAction = billing.EnforcementActionNone
+	}
+	if !budget.EnforcementAction.IsValid() {
+		return appErrors.NewValidationError("enforcement_action", "must be one of none, block, sample, drop_payloads")
+	}
+	if budget.EnforcementAction == billing.EnforcementActionSample {
+		if budget.EnforcementSamplePercent == nil || *budget.EnforcementSamplePercent < 1 || *budget.EnforcementSamplePercent > 99 {
+			return appErrors.NewValidationError("enforcement_sample_percent", "must be between 1 and 99 when enforcement_action is sample")
+		}
+	}
+	return nil
+}
+
+// refreshEnforcement re-evaluates ingestion enforcement after a budget change.
+// Failures are logged; the usage worker refreshes again on its next sync.
+func (s *budgetService) refreshEnforcement(ctx context.Context, orgID ulid.ULID) {
+	if s.enforcementService == nil {
+		return
+	}
+	if err := s.enforcementService.Refresh(ctx, orgID); err != nil {
+		s.logger.Warn("failed to refresh budget enforcement",
+			"error", err,
+			"organization_id", orgID,
+		)
+	}
+}
+
 func (s *budgetService) CheckBudgets(ctx context.Context, orgID ulid.ULID) ([]*billing.UsageAlert, error) {
 	budgets, err := s.budgetRepo.GetActive(ctx, orgID)
 	if err != nil {
@@ -0,0 +1,142 @@
+package billing
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"brokle/internal/core/domain/billing"
+	"brokle/pkg/ulid"
+)
+
+type enforcementService struct {
+	budgetRepo billing.UsageBudgetRepository
+	stateRepo  billing.EnforcementStateRepository
+	stateTTL   time.Duration
+	logger     *slog.Logger
+}
+
+// NewEnforcementService creates a budget enforcement service.
+// stateTTL bounds how long cached state survives without a refresh.
+func NewEnforcementService(
+	budgetRepo billing.UsageBudgetRepository,
+	stateRepo billing.EnforcementStateRepository,
+	stateTTL time.Duration,
+	logger *slog.Logger,
+) billing.EnforcementService {
+	if stateTTL <= 0 {
+		stateTTL = 15 * time.Minute
+	}
+	return &enforcementService{
+		budgetRepo: budgetRepo,
+		stateRepo:  stateRepo,
+		stateTTL:   stateTTL,
+		logger:     logger,
+	}
+}
+
+func (s *enforcementService) Refresh(ctx context.Context, orgID ulid.ULID) error {
+	budgets, err := s.budgetRepo.GetActive(ctx, orgID)
+	if err != nil {
+		return fmt.Errorf("get active budgets: %w", err)
+	}
+
+	states := evaluateEnforcement(budgets, time.Now())
+
+	if err := s.stateRepo.Replace(ctx, orgID, states, s.stateTTL); err != nil {
+		return err
+	}
+
+	for scope, state := range states {
+		s.logger.Info("budget enforcement active",
+			"organization_id", orgID,
+			"scope", scope,
+			"budget_id", state.BudgetID,
+			"dimension", state.Dimension,
+			"block", state.Block,
+			"sample_percent", state.SamplePercent,
+			"drop_payloads", state.DropPayloads,
+		)
+	}
+
+	return nil
+}
+
+func (s *enforcementService) GetIngestionEnforcement(ctx context.Context, orgID, projectID ulid.ULID) *billing.IngestionEnforcement {
+	orgState, projectState, err := s.stateRepo.Get(ctx, orgID, projectID)
+	if err != nil {
+		// Fail open: a Redis outage must not stop ingestion
+		s.logger.Warn("failed to load budget enforcement state",
+			"error", err,
+			"organization_id", orgID,
+			"project_id", projectID,
+		)
+		return nil
+	}
+
+	merged := orgState.Merge(projectState)
+	if !merged.Restricts() {
+		return nil
+	}
+	return merged
+}
+
+// evaluateEnforcement maps each scope (organization or project) to the strictest
+// action among its budgets that have reached a limit.
+func evaluateEnforcement(budgets []*billing.UsageBudget, now time.Time) map[string]*billing.IngestionEnforcement {
+	states := make(map[string]*billing.IngestionEnforcement)
+
+	for _, budget := range budgets {
+		state := budgetEnforcement(budget, now)
+		if state == nil {
+			continue
+		}
+
+		scope := billing.OrganizationEnforcementScope
+		if budget.ProjectID != nil {
+			scope = budget.ProjectID.String()
+		}
+		states[scope] = states[scope].Merge(state)
+	}
+
+	return states
+}
+
+// budgetEnforcement returns the budget's action if one of its limits has been reached.
+func budgetEnforcement(budget *billing.UsageBudget, now time.Time) *billing.IngestionEnforcement {
+	if budget.EnforcementAction == "" || budget.EnforcementAction == billing.EnforcementActionNone {
+		return nil
+	}
+
+	dimension, exceeded := budget.ExceededDimension()
+	if !exceeded {
+		return nil
+	}
+
+	state := &billing.IngestionEnforcement{
+		BudgetID:    budget.ID,
+		BudgetName:  budget.Name,
+		Dimension:   dimension,
+		EvaluatedAt: now,
+	}
+
+	switch budget.EnforcementAction {
+	case billing.EnforcementActionBlock:
+		state.Block = true
+	case billing.EnforcementActionSample:
+		if budget.EnforcementSamplePercent == nil {
+			return nil
+		}
+		state.SamplePercent = *budget.EnforcementSamplePercent
+	case billing.EnforcementActionDropPayloads:
+		state.DropPayloads = true
+	default:
+		return nil
+	}
+
+	if !state.Restricts() {
+		return nil
+	}
+	return state
+}
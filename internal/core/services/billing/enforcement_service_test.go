@@ -0,0 +1,202 @@
+package billing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"brokle/internal/core/domain/billing"
+	"brokle/pkg/ulid"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockEnforcementStateRepository struct {
+	mock.Mock
+}
+
+func (m *MockEnforcementStateRepository) Replace(ctx context.Context, orgID ulid.ULID, states map[string]*billing.IngestionEnforcement, ttl time.Duration) error {
+	args := m.Called(ctx, orgID, states, ttl)
+	return args.Error(0)
+}
+
+func (m *MockEnforcementStateRepository) Get(ctx context.Context, orgID, projectID ulid.ULID) (*billing.IngestionEnforcement, *billing.IngestionEnforcement, error) {
+	args := m.Called(ctx, orgID, projectID)
+	var orgState, projectState *billing.IngestionEnforcement
+	if v := args.Get(0); v != nil {
+		orgState = v.(*billing.IngestionEnforcement)
+	}
+	if v := args.Get(1); v != nil {
+		projectState = v.(*billing.IngestionEnforcement)
+	}
+	return orgState, projectState, args.Error(2)
+}
+
+func exceededBudget(action billing.EnforcementAction, projectID *ulid.ULID) *billing.UsageBudget {
+	return &billing.UsageBudget{
+		ID:                ulid.New(),
+		Name:              "monthly",
+		ProjectID:         projectID,
+		SpanLimit:         ptrInt64(1000),
+		CurrentSpans:      1000,
+		EnforcementAction: action,
+		IsActive:          true,
+	}
+}
+
+func ptrInt(v int) *int {
+	return &v
+}
+
+func TestBudgetEnforcement(t *testing.T) {
+	now := time.Now()
+
+	t.Run("none action is alert only", func(t *testing.T) {
+		assert.Nil(t, budgetEnforcement(exceededBudget(billing.EnforcementActionNone, nil), now))
+	})
+
+	t.Run("under limit does not enforce", func(t *testing.T) {
+		budget := exceededBudget(billing.EnforcementActionBlock, nil)
+		budget.CurrentSpans = 999
+		assert.Nil(t, budgetEnforcement(budget, now))
+	})
+
+	t.Run("block", func(t *testing.T) {
+		state := budgetEnforcement(exceededBudget(billing.EnforcementActionBlock, nil), now)
+		require.NotNil(t, state)
+		assert.True(t, state.Blocks())
+		assert.Equal(t, billing.AlertDimensionSpans, state.Dimension)
+		assert.Equal(t, "budget 'monthly' has reached its spans limit", state.Reason())
+	})
+
+	t.Run("sample", func(t *testing.T) {
+		budget := exceededBudget(billing.EnforcementActionSample, nil)
+		budget.EnforcementSamplePercent = ptrInt(10)
+		state := budgetEnforcement(budget, now)
+		require.NotNil(t, state)
+		assert.False(t, state.Blocks())
+		assert.True(t, state.Samples())
+		assert.Equal(t, 10, state.SamplePercent)
+	})
+
+	t.Run("sample without percent is ignored", func(t *testing.T) {
+		assert.Nil(t, budgetEnforcement(exceededBudget(billing.EnforcementActionSample, nil), now))
+	})
+
+	t.Run("drop payloads", func(t *testing.T) {
+		state := budgetEnforcement(exceededBudget(billing.EnforcementActionDropPayloads, nil), now)
+		require.NotNil(t, state)
+		assert.True(t, state.DropPayloads)
+		assert.False(t, state.Samples())
+	})
+}
+
+func TestEvaluateEnforcement_ScopesAndMerge(t *testing.T) {
+	projectID := ulid.New()
+
+	sample := exceededBudget(billing.EnforcementActionSample, &projectID)
+	sample.EnforcementSamplePercent = ptrInt(25)
+	block := exceededBudget(billing.EnforcementActionBlock, &projectID)
+	block.Name = "hard cap"
+	orgDrop := exceededBudget(billing.EnforcementActionDropPayloads, nil)
+
+	states := evaluateEnforcement([]*billing.UsageBudget{sample, block, orgDrop}, time.Now())
+
+	require.Len(t, states, 2)
+	projectState := states[projectID.String()]
+	require.NotNil(t, projectState)
+	assert.True(t, projectState.Blocks())
+	assert.Equal(t, block.ID, projectState.BudgetID)
+
+	orgState := states[billing.OrganizationEnforcementScope]
+	require.NotNil(t, orgState)
+	assert.True(t, orgState.DropPayloads)
+	assert.False(t, orgState.Blocks())
+}
+
+func TestEnforcementService_Refresh(t *testing.T) {
+	ctx := context.Background()
+	orgID := ulid.New()
+
+	budgetRepo := new(MockUsageBudgetRepository)
+	stateRepo := new(MockEnforcementStateRepository)
+	service := NewEnforcementService(budgetRepo, stateRepo, time.Minute, newTestLogger())
+
+	budgetRepo.On("GetActive", ctx, orgID).Return([]*billing.UsageBudget{
+		exceededBudget(billing.EnforcementActionBlock, nil),
+	}, nil)
+	stateRepo.On("Replace", ctx, orgID, mock.MatchedBy(func(states map[string]*billing.IngestionEnforcement) bool {
+		return len(states) == 1 && states[billing.OrganizationEnforcementScope].Blocks()
+	}), time.Minute).Return(nil)
+
+	require.NoError(t, service.Refresh(ctx, orgID))
+	stateRepo.AssertExpectations(t)
+}
+
+func TestEnforcementService_GetIngestionEnforcement(t *testing.T) {
+	ctx := context.Background()
+	orgID := ulid.New()
+	projectID := ulid.New()
+
+	t.Run("merges organization and project state", func(t *testing.T) {
+		stateRepo := new(MockEnforcementStateRepository)
+		service := NewEnforcementService(new(MockUsageBudgetRepository), stateRepo, time.Minute, newTestLogger())
+
+		stateRepo.On("Get", ctx, orgID, projectID).Return(
+			&billing.IngestionEnforcement{DropPayloads: true},
+			&billing.IngestionEnforcement{SamplePercent: 20},
+			nil,
+		)
+
+		state := service.GetIngestionEnforcement(ctx, orgID, projectID)
+		require.NotNil(t, state)
+		assert.True(t, state.DropPayloads)
+		assert.Equal(t, 20, state.SamplePercent)
+	})
+
+	t.Run("fails open on state error", func(t *testing.T) {
+		stateRepo := new(MockEnforcementStateRepository)
+		service := NewEnforcementService(new(MockUsageBudgetRepository), stateRepo, time.Minute, newTestLogger())
+
+		stateRepo.On("Get", ctx, orgID, projectID).Return(nil, nil, errors.New("redis down"))
+
+		assert.Nil(t, service.GetIngestionEnforcement(ctx, orgID, projectID))
+	})
+}
+
+func TestValidateEnforcement(t *testing.T) {
+	budget := &billing.UsageBudget{}
+	require.NoError(t, validateEnforcement(budget))
+	assert.Equal(t, billing.EnforcementActionNone, budget.EnforcementAction)
+
+	assert.Error(t, validateEnforcement(&billing.UsageBudget{EnforcementAction: "throttle"}))
+	assert.Error(t, validateEnforcement(&billing.UsageBudget{EnforcementAction: billing.EnforcementActionSample}))
+	assert.Error(t, validateEnforcement(&billing.UsageBudget{
+		EnforcementAction:        billing.EnforcementActionSample,
+		EnforcementSamplePercent: ptrInt(100),
+	}))
+	assert.NoError(t, validateEnforcement(&billing.UsageBudget{
+		EnforcementAction:        billing.EnforcementActionSample,
+		EnforcementSamplePercent: ptrInt(50),
+	}))
+}
+
+func TestIngestionEnforcement_KeepTrace(t *testing.T) {
+	var noEnforcement *billing.IngestionEnforcement
+	assert.True(t, noEnforcement.KeepTrace("any"))
+
+	state := &billing.IngestionEnforcement{SamplePercent: 30}
+	kept := 0
+	for i := 0; i < 1000; i++ {
+		traceID := ulid.New().String()
+		keep := state.KeepTrace(traceID)
+		assert.Equal(t, keep, state.KeepTrace(traceID), "sampling must be deterministic per trace")
+		if keep {
+			kept++
+		}
+	}
+	assert.InDelta(t, 300, kept, 80)
+}
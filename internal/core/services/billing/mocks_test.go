@@ -194,6 +194,62 @@ func (m *MockContractHistoryRepository) GetByContractID(ctx context.Context, con
 	return args.Get(0).([]*billing.ContractHistory), args.Error(1)
 }
 
+type MockUsageBudgetRepository struct {
+	mock.Mock
+}
+
+func (m *MockUsageBudgetRepository) GetByID(ctx context.Context, id ulid.ULID) (*billing.UsageBudget, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*billing.UsageBudget), args.Error(1)
+}
+
+func (m *MockUsageBudgetRepository) GetByOrgID(ctx context.Context, orgID ulid.ULID) ([]*billing.UsageBudget, error) {
+	args := m.Called(ctx, orgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*billing.UsageBudget), args.Error(1)
+}
+
+func (m *MockUsageBudgetRepository) GetByProjectID(ctx context.Context, projectID ulid.ULID) ([]*billing.UsageBudget, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*billing.UsageBudget), args.Error(1)
+}
+
+func (m *MockUsageBudgetRepository) GetActive(ctx context.Context, orgID ulid.ULID) ([]*billing.UsageBudget, error) {
+	args := m.Called(ctx, orgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*billing.UsageBudget), args.Error(1)
+}
+
+func (m *MockUsageBudgetRepository) Create(ctx context.Context, budget *billing.UsageBudget) error {
+	args := m.Called(ctx, budget)
+	return args.Error(0)
+}
+
+func (m *MockUsageBudgetRepository) Update(ctx context.Context, budget *billing.UsageBudget) error {
+	args := m.Called(ctx, budget)
+	return args.Error(0)
+}
+
+func (m *MockUsageBudgetRepository) UpdateUsage(ctx context.Context, budgetID ulid.ULID, spans, bytes, scores int64, cost decimal.Decimal) error {
+	args := m.Called(ctx, budgetID, spans, bytes, scores, cost)
+	return args.Error(0)
+}
+
+func (m *MockUsageBudgetRepository) Delete(ctx context.Context, id ulid.ULID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 // Shared test helper functions
 
 func newTestLogger() *slog.Logger {
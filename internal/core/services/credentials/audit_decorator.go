@@ -0,0 +1,88 @@
+package credentials
+
+import (
+	"context"
+	"sort"
+
+	authDomain "brokle/internal/core/domain/auth"
+	credentialsDomain "brokle/internal/core/domain/credentials"
+	"brokle/pkg/ulid"
+)
+
+const auditResourceCredential = "provider_credential"
+
+// auditDecorator records credential changes in the audit log. Keys are never
+// audited; header values are dropped since they often carry secrets too.
+type auditDecorator struct {
+	credentialsDomain.ProviderCredentialService
+	auditLogs authDomain.AuditLogService
+}
+
+// NewAuditDecorator wraps a ProviderCredentialService with audit logging
+func NewAuditDecorator(service credentialsDomain.ProviderCredentialService, auditLogs authDomain.AuditLogService) credentialsDomain.ProviderCredentialService {
+	return &auditDecorator{
+		ProviderCredentialService: service,
+		auditLogs:                 auditLogs,
+	}
+}
+
+func (d *auditDecorator) Create(ctx context.Context, req *credentialsDomain.CreateCredentialRequest) (*credentialsDomain.ProviderCredentialResponse, error) {
+	created, err := d.ProviderCredentialService.Create(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	d.record(ctx, "credential.created", created.OrganizationID, created.ID, nil, credentialSnapshot(created))
+	return created, nil
+}
+
+func (d *auditDecorator) Update(ctx context.Context, id ulid.ULID, orgID ulid.ULID, req *credentialsDomain.UpdateCredentialRequest) (*credentialsDomain.ProviderCredentialResponse, error) {
+	before, _ := d.ProviderCredentialService.GetByID(ctx, id, orgID)
+	updated, err := d.ProviderCredentialService.Update(ctx, id, orgID, req)
+	if err != nil {
+		return nil, err
+	}
+	d.record(ctx, "credential.updated", orgID, id, credentialSnapshot(before), credentialSnapshot(updated))
+	return updated, nil
+}
+
+func (d *auditDecorator) Delete(ctx context.Context, id ulid.ULID, orgID ulid.ULID) error {
+	before, _ := d.ProviderCredentialService.GetByID(ctx, id, orgID)
+	if err := d.ProviderCredentialService.Delete(ctx, id, orgID); err != nil {
+		return err
+	}
+	d.record(ctx, "credential.deleted", orgID, id, credentialSnapshot(before), nil)
+	return nil
+}
+
+func (d *auditDecorator) record(ctx context.Context, action string, orgID, id ulid.ULID, before, after interface{}) {
+	d.auditLogs.Record(ctx, &authDomain.AuditEvent{
+		OrganizationID: &orgID,
+		Action:         action,
+		Resource:       auditResourceCredential,
+		ResourceID:     id.String(),
+		Before:         before,
+		After:          after,
+	})
+}
+
+// credentialSnapshot returns the auditable fields of a credential, or nil
+func credentialSnapshot(credential *credentialsDomain.ProviderCredentialResponse) interface{} {
+	if credential == nil {
+		return nil
+	}
+	headers := make([]string, 0, len(credential.Headers))
+	for name := range credential.Headers {
+		headers = append(headers, name)
+	}
+	sort.Strings(headers)
+
+	return map[string]interface{}{
+		"name":          credential.Name,
+		"adapter":       credential.Adapter,
+		"key_preview":   credential.KeyPreview,
+		"base_url":      credential.BaseURL,
+		"config":        credential.Config,
+		"custom_models": credential.CustomModels,
+		"headers":       headers,
+	}
+}
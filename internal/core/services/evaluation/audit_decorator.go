@@ -0,0 +1,136 @@
+package evaluation
+
+import (
+	"context"
+
+	authDomain "brokle/internal/core/domain/auth"
+	"brokle/internal/core/domain/evaluation"
+	"brokle/pkg/ulid"
+)
+
+const (
+	auditResourceDataset   = "dataset"
+	auditResourceEvaluator = "evaluator"
+)
+
+// datasetAuditDecorator records dataset changes in the audit log
+type datasetAuditDecorator struct {
+	evaluation.DatasetService
+	auditLogs authDomain.AuditLogService
+}
+
+// NewDatasetAuditDecorator wraps a DatasetService with audit logging
+func NewDatasetAuditDecorator(service evaluation.DatasetService, auditLogs authDomain.AuditLogService) evaluation.DatasetService {
+	return &datasetAuditDecorator{
+		DatasetService: service,
+		auditLogs:      auditLogs,
+	}
+}
+
+func (d *datasetAuditDecorator) Create(ctx context.Context, projectID ulid.ULID, req *evaluation.CreateDatasetRequest) (*evaluation.Dataset, error) {
+	dataset, err := d.DatasetService.Create(ctx, projectID, req)
+	if err != nil {
+		return nil, err
+	}
+	recordAudit(ctx, d.auditLogs, "dataset.created", auditResourceDataset, projectID, dataset.ID, nil, dataset)
+	return dataset, nil
+}
+
+func (d *datasetAuditDecorator) Update(ctx context.Context, id ulid.ULID, projectID ulid.ULID, req *evaluation.UpdateDatasetRequest) (*evaluation.Dataset, error) {
+	before, _ := d.DatasetService.GetByID(ctx, id, projectID)
+	dataset, err := d.DatasetService.Update(ctx, id, projectID, req)
+	if err != nil {
+		return nil, err
+	}
+	recordAudit(ctx, d.auditLogs, "dataset.updated", auditResourceDataset, projectID, id, before, dataset)
+	return dataset, nil
+}
+
+func (d *datasetAuditDecorator) Delete(ctx context.Context, id ulid.ULID, projectID ulid.ULID) error {
+	before, _ := d.DatasetService.GetByID(ctx, id, projectID)
+	if err := d.DatasetService.Delete(ctx, id, projectID); err != nil {
+		return err
+	}
+	recordAudit(ctx, d.auditLogs, "dataset.deleted", auditResourceDataset, projectID, id, before, nil)
+	return nil
+}
+
+// evaluatorAuditDecorator records evaluator changes, including activation, in the audit log
+type evaluatorAuditDecorator struct {
+	evaluation.EvaluatorService
+	auditLogs authDomain.AuditLogService
+}
+
+// NewEvaluatorAuditDecorator wraps an EvaluatorService with audit logging
+func NewEvaluatorAuditDecorator(service evaluation.EvaluatorService, auditLogs authDomain.AuditLogService) evaluation.EvaluatorService {
+	return &evaluatorAuditDecorator{
+		EvaluatorService: service,
+		auditLogs:        auditLogs,
+	}
+}
+
+func (d *evaluatorAuditDecorator) Create(ctx context.Context, projectID ulid.ULID, userID *ulid.ULID, req *evaluation.CreateEvaluatorRequest) (*evaluation.Evaluator, error) {
+	evaluator, err := d.EvaluatorService.Create(ctx, projectID, userID, req)
+	if err != nil {
+		return nil, err
+	}
+	recordAudit(ctx, d.auditLogs, "evaluator.created", auditResourceEvaluator, projectID, evaluator.ID, nil, evaluator)
+	return evaluator, nil
+}
+
+func (d *evaluatorAuditDecorator) Update(ctx context.Context, id ulid.ULID, projectID ulid.ULID, req *evaluation.UpdateEvaluatorRequest) (*evaluation.Evaluator, error) {
+	before, _ := d.EvaluatorService.GetByID(ctx, id, projectID)
+	evaluator, err := d.EvaluatorService.Update(ctx, id, projectID, req)
+	if err != nil {
+		return nil, err
+	}
+	recordAudit(ctx, d.auditLogs, "evaluator.updated", auditResourceEvaluator, projectID, id, before, evaluator)
+	return evaluator, nil
+}
+
+func (d *evaluatorAuditDecorator) Delete(ctx context.Context, id ulid.ULID, projectID ulid.ULID) error {
+	before, _ := d.EvaluatorService.GetByID(ctx, id, projectID)
+	if err := d.EvaluatorService.Delete(ctx, id, projectID); err != nil {
+		return err
+	}
+	recordAudit(ctx, d.auditLogs, "evaluator.deleted", auditResourceEvaluator, projectID, id, before, nil)
+	return nil
+}
+
+func (d *evaluatorAuditDecorator) Activate(ctx context.Context, id ulid.ULID, projectID ulid.ULID) error {
+	return d.changeStatus(ctx, id, projectID, evaluation.EvaluatorStatusActive, d.EvaluatorService.Activate)
+}
+
+func (d *evaluatorAuditDecorator) Deactivate(ctx context.Context, id ulid.ULID, projectID ulid.ULID) error {
+	return d.changeStatus(ctx, id, projectID, evaluation.EvaluatorStatusInactive, d.EvaluatorService.Deactivate)
+}
+
+// changeStatus audits activation changes; no-op transitions are not recorded
+func (d *evaluatorAuditDecorator) changeStatus(ctx context.Context, id, projectID ulid.ULID, status evaluation.EvaluatorStatus, change func(context.Context, ulid.ULID, ulid.ULID) error) error {
+	before, _ := d.EvaluatorService.GetByID(ctx, id, projectID)
+	if err := change(ctx, id, projectID); err != nil {
+		return err
+	}
+	if before != nil && before.Status == status {
+		return nil
+	}
+
+	var previous interface{}
+	if before != nil {
+		previous = map[string]interface{}{"status": before.Status}
+	}
+	recordAudit(ctx, d.auditLogs, "evaluator.status_changed", auditResourceEvaluator, projectID, id,
+		previous, map[string]interface{}{"status": status})
+	return nil
+}
+
+func recordAudit(ctx context.Context, auditLogs authDomain.AuditLogService, action, resource string, projectID, id ulid.ULID, before, after interface{}) {
+	auditLogs.Record(ctx, &authDomain.AuditEvent{
+		ProjectID:  &projectID,
+		Action:     action,
+		Resource:   resource,
+		ResourceID: id.String(),
+		Before:     before,
+		After:      after,
+	})
+}
@@ -243,6 +243,9 @@ func (s *evaluatorService) Activate(ctx context.Context, id ulid.ULID, projectID
 	if rule.Status == evaluation.EvaluatorStatusActive {
 		return nil
 	}
+	if rule.ScorerType == evaluation.ScorerTypePairwise {
+		return appErrors.NewValidationError("scorer_type", "pairwise evaluators cannot be activated, trigger them on experiment or span pairs")
+	}
 
 	rule.Status = evaluation.EvaluatorStatusActive
 	rule.UpdatedAt = time.Now()
@@ -306,6 +309,9 @@ func (s *evaluatorService) TriggerEvaluator(ctx context.Context, evaluatorID uli
 		}
 		return nil, appErrors.NewInternalError("failed to get evaluator", err)
 	}
+	if evaluator.ScorerType == evaluation.ScorerTypePairwise {
+		return nil, appErrors.NewValidationError("scorer_type", "pairwise evaluators compare two outputs, use the pairwise trigger")
+	}
 
 	execution, err := s.executionService.StartExecution(ctx, evaluatorID, projectID, evaluation.TriggerTypeManual)
 	if err != nil {
@@ -372,6 +378,76 @@ func (s *evaluatorService) TriggerEvaluator(ctx context.Context, evaluatorID uli
 	}, nil
 }
 
+func (s *evaluatorService) TriggerPairwise(ctx context.Context, evaluatorID ulid.ULID, projectID ulid.ULID, req *evaluation.PairwiseTriggerRequest) (*evaluation.TriggerResponse, error) {
+	if verr := req.Validate(); verr != nil {
+		return nil, appErrors.NewValidationError(verr.Field, verr.Message)
+	}
+
+	evaluator, err := s.repo.GetByID(ctx, evaluatorID, projectID)
+	if err != nil {
+		if errors.Is(err, evaluation.ErrEvaluatorNotFound) {
+			return nil, appErrors.NewNotFoundError(fmt.Sprintf("evaluator %s", evaluatorID))
+		}
+		return nil, appErrors.NewInternalError("failed to get evaluator", err)
+	}
+	if evaluator.ScorerType != evaluation.ScorerTypePairwise {
+		return nil, appErrors.NewValidationError("scorer_type", "evaluator must use the pairwise scorer")
+	}
+
+	execution, err := s.executionService.StartExecution(ctx, evaluatorID, projectID, evaluation.TriggerTypeManual)
+	if err != nil {
+		return nil, appErrors.NewInternalError("failed to create execution record", err)
+	}
+
+	triggerMsg := ManualTriggerMessage{
+		ExecutionID:     execution.ID,
+		EvaluatorID:     evaluatorID,
+		ProjectID:       projectID,
+		ScorerType:      evaluator.ScorerType,
+		ScorerConfig:    evaluator.ScorerConfig,
+		TargetScope:     evaluator.TargetScope,
+		VariableMapping: evaluator.VariableMapping,
+		ExperimentIDs:   req.ExperimentIDs,
+		SpanPairs:       req.SpanPairs,
+		SampleLimit:     req.SampleLimit,
+		CreatedAt:       time.Now(),
+	}
+	if triggerMsg.SampleLimit == 0 {
+		triggerMsg.SampleLimit = 1000 // Default limit
+	}
+
+	msgData, err := json.Marshal(triggerMsg)
+	if err != nil {
+		_ = s.executionService.FailExecution(ctx, execution.ID, projectID, "failed to serialize trigger message")
+		return nil, appErrors.NewInternalError("failed to serialize trigger message", err)
+	}
+
+	_, err = s.redis.Client.XAdd(ctx, &redis.XAddArgs{
+		Stream: manualTriggerStream,
+		Values: map[string]interface{}{
+			"data": string(msgData),
+		},
+	}).Result()
+	if err != nil {
+		_ = s.executionService.FailExecution(ctx, execution.ID, projectID, "failed to queue trigger job")
+		return nil, appErrors.NewInternalError("failed to queue pairwise trigger job", err)
+	}
+
+	s.logger.Info("pairwise evaluation triggered",
+		"evaluator_id", evaluatorID,
+		"project_id", projectID,
+		"execution_id", execution.ID,
+		"experiments", len(req.ExperimentIDs),
+		"span_pairs", len(req.SpanPairs),
+	)
+
+	return &evaluation.TriggerResponse{
+		ExecutionID: execution.ID.String(),
+		SpansQueued: 0, // Will be updated by worker when it starts processing
+		Message:     "Pairwise evaluation queued successfully",
+	}, nil
+}
+
 func (s *evaluatorService) TestEvaluator(ctx context.Context, evaluatorID ulid.ULID, projectID ulid.ULID, req *evaluation.TestEvaluatorRequest) (*evaluation.TestEvaluatorResponse, error) {
 	// Validate evaluator exists
 	evaluator, err := s.repo.GetByID(ctx, evaluatorID, projectID)
@@ -808,7 +884,7 @@ func buildFilterDescription(filters []evaluation.FilterClause) string {
 
 // buildPromptPreview creates a preview of the LLM prompt for LLM scorers.
 func buildPromptPreview(rule *evaluation.Evaluator) string {
-	if rule.ScorerType != evaluation.ScorerTypeLLM {
+	if rule.ScorerType != evaluation.ScorerTypeLLM && rule.ScorerType != evaluation.ScorerTypePairwise {
 		return ""
 	}
 	// Extract messages from scorer config if present
@@ -1263,4 +1339,8 @@ type ManualTriggerMessage struct {
 	SpanIDs         []string                  `json:"span_ids,omitempty"`
 	SampleLimit     int                       `json:"sample_limit"`
 	CreatedAt       time.Time                 `json:"created_at"`
+
+	// Pairwise evaluators judge either every pair of these experiments or the span pairs
+	ExperimentIDs []string                      `json:"experiment_ids,omitempty"`
+	SpanPairs     []evaluation.PairwiseSpanPair `json:"span_pairs,omitempty"`
 }
@@ -0,0 +1,205 @@
+package evaluation
+
+import (
+	"context"
+	"sort"
+
+	"brokle/internal/core/domain/evaluation"
+	"brokle/internal/core/domain/observability"
+	"brokle/pkg/ulid"
+)
+
+// itemScoreSamples holds the scores one dataset item received in an experiment.
+// Repeated trials leave several samples per item.
+type itemScoreSamples struct {
+	values []float64
+	labels []string
+}
+
+// comparePairedScores runs paired significance tests of every experiment
+// against the baseline, over the dataset items scored in both.
+func (s *experimentService) comparePairedScores(
+	ctx context.Context,
+	projectID ulid.ULID,
+	experimentIDs []ulid.ULID,
+	baselineID ulid.ULID,
+) (map[string]map[string]*evaluation.PairedComparison, error) {
+	links, err := s.itemRepo.ListDatasetItemLinks(ctx, experimentIDs)
+	if err != nil {
+		return nil, err
+	}
+	datasetItems := make(map[string]string, len(links))
+	for _, item := range links {
+		if item.DatasetItemID != nil {
+			datasetItems[item.ID.String()] = item.DatasetItemID.String()
+		}
+	}
+
+	ids := make([]string, len(experimentIDs))
+	for i, id := range experimentIDs {
+		ids[i] = id.String()
+	}
+	scores, err := s.scoreRepo.GetItemScoresByExperiments(ctx, projectID.String(), ids)
+	if err != nil {
+		return nil, err
+	}
+
+	return pairScoresByDatasetItem(scores, datasetItems, baselineID.String()), nil
+}
+
+// pairScoresByDatasetItem groups item scores by dataset item and compares each
+// experiment with the baseline on the items they share.
+// Returns: scoreName -> experimentID -> comparison
+func pairScoresByDatasetItem(
+	scores []*observability.Score,
+	datasetItems map[string]string,
+	baselineID string,
+) map[string]map[string]*evaluation.PairedComparison {
+	// scoreName -> experimentID -> datasetItemID -> samples
+	grouped := make(map[string]map[string]map[string]*itemScoreSamples)
+	scoreTypes := make(map[string]string)
+	seen := make(map[string]struct{}, len(scores))
+
+	for _, score := range scores {
+		if score.ExperimentID == nil || score.ExperimentItemID == nil {
+			continue
+		}
+		// ReplacingMergeTree may return a score twice until parts are merged
+		if _, dup := seen[score.ID]; dup {
+			continue
+		}
+		seen[score.ID] = struct{}{}
+
+		datasetItemID, ok := datasetItems[*score.ExperimentItemID]
+		if !ok {
+			continue
+		}
+		expID := *score.ExperimentID
+		if _, ok := scoreTypes[score.Name]; !ok || expID == baselineID {
+			scoreTypes[score.Name] = score.Type
+		}
+
+		if grouped[score.Name] == nil {
+			grouped[score.Name] = make(map[string]map[string]*itemScoreSamples)
+		}
+		if grouped[score.Name][expID] == nil {
+			grouped[score.Name][expID] = make(map[string]*itemScoreSamples)
+		}
+		samples := grouped[score.Name][expID][datasetItemID]
+		if samples == nil {
+			samples = &itemScoreSamples{}
+			grouped[score.Name][expID][datasetItemID] = samples
+		}
+		if score.Value != nil {
+			samples.values = append(samples.values, *score.Value)
+		}
+		if score.StringValue != nil {
+			samples.labels = append(samples.labels, *score.StringValue)
+		}
+	}
+
+	comparisons := make(map[string]map[string]*evaluation.PairedComparison)
+	for scoreName, byExperiment := range grouped {
+		baseline := byExperiment[baselineID]
+		if baseline == nil {
+			continue
+		}
+
+		for expID, current := range byExperiment {
+			if expID == baselineID {
+				continue
+			}
+			comparison := comparePairedSamples(scoreTypes[scoreName], baseline, current)
+			if comparison == nil {
+				continue
+			}
+			if comparisons[scoreName] == nil {
+				comparisons[scoreName] = make(map[string]*evaluation.PairedComparison)
+			}
+			comparisons[scoreName][expID] = comparison
+		}
+	}
+
+	return comparisons
+}
+
+// comparePairedSamples reduces each shared dataset item to one value per
+// experiment and runs the test that fits the score type
+func comparePairedSamples(scoreType string, baseline, current map[string]*itemScoreSamples) *evaluation.PairedComparison {
+	shared := make([]string, 0, len(current))
+	for datasetItemID := range current {
+		if _, ok := baseline[datasetItemID]; ok {
+			shared = append(shared, datasetItemID)
+		}
+	}
+	if len(shared) == 0 {
+		return nil
+	}
+	// Stable order keeps the bootstrap interval reproducible
+	sort.Strings(shared)
+
+	switch scoreType {
+	case observability.ScoreTypeCategorical:
+		var b, c []string
+		for _, id := range shared {
+			bl, bok := modeLabel(baseline[id].labels)
+			cl, cok := modeLabel(current[id].labels)
+			if bok && cok {
+				b, c = append(b, bl), append(c, cl)
+			}
+		}
+		return evaluation.ComparePairedCategorical(b, c)
+
+	case observability.ScoreTypeBoolean:
+		var b, c []bool
+		for _, id := range shared {
+			bv, bok := meanValue(baseline[id].values)
+			cv, cok := meanValue(current[id].values)
+			if bok && cok {
+				// Majority vote over repeated trials
+				b, c = append(b, bv >= 0.5), append(c, cv >= 0.5)
+			}
+		}
+		return evaluation.ComparePairedBoolean(b, c)
+
+	default:
+		var b, c []float64
+		for _, id := range shared {
+			bv, bok := meanValue(baseline[id].values)
+			cv, cok := meanValue(current[id].values)
+			if bok && cok {
+				b, c = append(b, bv), append(c, cv)
+			}
+		}
+		return evaluation.ComparePairedNumeric(b, c)
+	}
+}
+
+func meanValue(values []float64) (float64, bool) {
+	if len(values) == 0 {
+		return 0, false
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values)), true
+}
+
+// modeLabel returns the most frequent label, breaking ties alphabetically
+func modeLabel(labels []string) (string, bool) {
+	if len(labels) == 0 {
+		return "", false
+	}
+	counts := make(map[string]int, len(labels))
+	for _, label := range labels {
+		counts[label]++
+	}
+	best := ""
+	for label, count := range counts {
+		if best == "" || count > counts[best] || (count == counts[best] && label < best) {
+			best = label
+		}
+	}
+	return best, true
+}
@@ -0,0 +1,83 @@
+package evaluation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"brokle/internal/core/domain/evaluation"
+	"brokle/internal/core/domain/observability"
+)
+
+func itemScore(id, experimentID, itemID, name, scoreType string, value *float64, label *string) *observability.Score {
+	return &observability.Score{
+		ID:               id,
+		ExperimentID:     &experimentID,
+		ExperimentItemID: &itemID,
+		Name:             name,
+		Type:             scoreType,
+		Value:            value,
+		StringValue:      label,
+	}
+}
+
+func floatPtr(v float64) *float64 { return &v }
+
+func TestPairScoresByDatasetItem(t *testing.T) {
+	// Experiment items b1..b3 (baseline) and c1..c4 (candidate) over dataset items d1..d3;
+	// c4 is a second trial of d3 and x1 belongs to no dataset item
+	datasetItems := map[string]string{
+		"b1": "d1", "b2": "d2", "b3": "d3",
+		"c1": "d1", "c2": "d2", "c3": "d3", "c4": "d3",
+	}
+	positive, negative := "positive", "negative"
+
+	scores := []*observability.Score{
+		itemScore("s1", "base", "b1", "accuracy", observability.ScoreTypeNumeric, floatPtr(0.5), nil),
+		itemScore("s2", "base", "b2", "accuracy", observability.ScoreTypeNumeric, floatPtr(0.6), nil),
+		itemScore("s3", "base", "b3", "accuracy", observability.ScoreTypeNumeric, floatPtr(0.7), nil),
+		itemScore("s3", "base", "b3", "accuracy", observability.ScoreTypeNumeric, floatPtr(0.7), nil), // unmerged duplicate
+		itemScore("s4", "cand", "c1", "accuracy", observability.ScoreTypeNumeric, floatPtr(0.8), nil),
+		itemScore("s5", "cand", "c2", "accuracy", observability.ScoreTypeNumeric, floatPtr(0.6), nil),
+		itemScore("s6", "cand", "c3", "accuracy", observability.ScoreTypeNumeric, floatPtr(0.2), nil),
+		itemScore("s7", "cand", "c4", "accuracy", observability.ScoreTypeNumeric, floatPtr(0.4), nil),
+		itemScore("s8", "cand", "x1", "accuracy", observability.ScoreTypeNumeric, floatPtr(1.0), nil),
+
+		itemScore("s9", "base", "b1", "passed", observability.ScoreTypeBoolean, floatPtr(1), nil),
+		itemScore("s10", "cand", "c1", "passed", observability.ScoreTypeBoolean, floatPtr(0), nil),
+		itemScore("s11", "base", "b2", "passed", observability.ScoreTypeBoolean, floatPtr(0), nil),
+		itemScore("s12", "cand", "c2", "passed", observability.ScoreTypeBoolean, floatPtr(0), nil),
+
+		itemScore("s13", "base", "b1", "sentiment", observability.ScoreTypeCategorical, nil, &positive),
+		itemScore("s14", "cand", "c1", "sentiment", observability.ScoreTypeCategorical, nil, &negative),
+
+		itemScore("s15", "cand", "c1", "candidate_only", observability.ScoreTypeNumeric, floatPtr(1), nil),
+	}
+
+	comparisons := pairScoresByDatasetItem(scores, datasetItems, "base")
+
+	accuracy := comparisons["accuracy"]["cand"]
+	require.NotNil(t, accuracy)
+	assert.Equal(t, evaluation.ScoreDiffTypeNumeric, accuracy.Type)
+	assert.Equal(t, 3, accuracy.PairedItems)
+	// d1 +0.3, d2 0, d3 averaged over trials 0.3 - 0.7 = -0.4
+	assert.InDelta(t, -0.1/3, accuracy.MeanDifference, 1e-9)
+	assert.Equal(t, 1, accuracy.Wins)
+	assert.Equal(t, 1, accuracy.Ties)
+	assert.Equal(t, 1, accuracy.Losses)
+
+	passed := comparisons["passed"]["cand"]
+	require.NotNil(t, passed)
+	assert.Equal(t, evaluation.ScoreDiffTypeBoolean, passed.Type)
+	assert.Equal(t, 1, passed.Losses)
+	assert.Equal(t, 1, passed.Ties)
+
+	sentiment := comparisons["sentiment"]["cand"]
+	require.NotNil(t, sentiment)
+	assert.Equal(t, evaluation.ScoreDiffTypeCategorical, sentiment.Type)
+	assert.Equal(t, 1, sentiment.Changed)
+
+	assert.NotContains(t, comparisons, "candidate_only")
+	assert.NotContains(t, comparisons["accuracy"], "base")
+}
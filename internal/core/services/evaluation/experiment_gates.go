@@ -0,0 +1,369 @@
+package evaluation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"brokle/internal/core/domain/evaluation"
+	"brokle/internal/core/domain/observability"
+	appErrors "brokle/pkg/errors"
+	"brokle/pkg/ulid"
+)
+
+// gateItemPageSize is the page size used to read item costs
+const gateItemPageSize = 1000
+
+// SetGates replaces the CI gates of an experiment
+func (s *experimentService) SetGates(ctx context.Context, id ulid.ULID, projectID ulid.ULID, gates []evaluation.ExperimentGate) (*evaluation.Experiment, error) {
+	if verr := evaluation.ValidateGates(gates); verr != nil {
+		return nil, appErrors.NewValidationError(verr.Field, verr.Message)
+	}
+
+	experiment, err := s.repo.GetByID(ctx, id, projectID)
+	if err != nil {
+		if errors.Is(err, evaluation.ErrExperimentNotFound) {
+			return nil, appErrors.NewNotFoundError(fmt.Sprintf("experiment %s", id))
+		}
+		return nil, appErrors.NewInternalError("failed to get experiment", err)
+	}
+
+	experiment.Gates = gates
+	experiment.GateVerdict = nil
+	if isFinishedExperiment(experiment.Status) && len(gates) > 0 {
+		experiment.GateVerdict = s.evaluateGates(ctx, experiment)
+	}
+	experiment.UpdatedAt = time.Now()
+
+	if err := s.repo.UpdateGates(ctx, experiment, projectID); err != nil {
+		if errors.Is(err, evaluation.ErrExperimentNotFound) {
+			return nil, appErrors.NewNotFoundError(fmt.Sprintf("experiment %s", id))
+		}
+		return nil, appErrors.NewInternalError("failed to update experiment gates", err)
+	}
+
+	s.logger.Info("experiment gates updated",
+		"experiment_id", id,
+		"project_id", projectID,
+		"gates", len(gates),
+	)
+
+	return experiment, nil
+}
+
+// GetVerdict returns the verdict of the experiment's gates
+func (s *experimentService) GetVerdict(ctx context.Context, id ulid.ULID, projectID ulid.ULID) (*evaluation.GateVerdict, error) {
+	experiment, err := s.repo.GetByID(ctx, id, projectID)
+	if err != nil {
+		if errors.Is(err, evaluation.ErrExperimentNotFound) {
+			return nil, appErrors.NewNotFoundError(fmt.Sprintf("experiment %s", id))
+		}
+		return nil, appErrors.NewInternalError("failed to get experiment", err)
+	}
+
+	if !isFinishedExperiment(experiment.Status) {
+		return &evaluation.GateVerdict{
+			ExperimentID:   experiment.ID.String(),
+			ExperimentName: experiment.Name,
+			Status:         evaluation.GateVerdictPending,
+			Reason:         fmt.Sprintf("experiment is %s", experiment.Status),
+			Results:        []evaluation.GateResult{},
+		}, nil
+	}
+
+	if experiment.GateVerdict != nil {
+		return experiment.GateVerdict, nil
+	}
+
+	// Finished before the verdict was recorded, e.g. with no gates at completion
+	s.recordVerdict(ctx, experiment)
+	if experiment.GateVerdict == nil {
+		experiment.GateVerdict = s.evaluateGates(ctx, experiment)
+	}
+	return experiment.GateVerdict, nil
+}
+
+// recordVerdict evaluates and stores the verdict of a finished experiment with gates
+func (s *experimentService) recordVerdict(ctx context.Context, experiment *evaluation.Experiment) {
+	if len(experiment.Gates) == 0 {
+		return
+	}
+
+	experiment.GateVerdict = s.evaluateGates(ctx, experiment)
+	if err := s.repo.UpdateGates(ctx, experiment, experiment.ProjectID); err != nil {
+		s.logger.Warn("failed to store experiment gate verdict",
+			"experiment_id", experiment.ID,
+			"project_id", experiment.ProjectID,
+			"error", err,
+		)
+		return
+	}
+
+	s.logger.Info("experiment gates evaluated",
+		"experiment_id", experiment.ID,
+		"project_id", experiment.ProjectID,
+		"verdict", experiment.GateVerdict.Status,
+	)
+}
+
+// evaluateGates checks every gate against the experiment's results. A gate
+// whose metric cannot be computed fails with the reason as its message.
+func (s *experimentService) evaluateGates(ctx context.Context, experiment *evaluation.Experiment) *evaluation.GateVerdict {
+	inputs := &gateInputs{service: s, experiment: experiment}
+	now := time.Now()
+
+	verdict := &evaluation.GateVerdict{
+		ExperimentID:   experiment.ID.String(),
+		ExperimentName: experiment.Name,
+		Passed:         true,
+		Results:        make([]evaluation.GateResult, 0, len(experiment.Gates)),
+		EvaluatedAt:    &now,
+	}
+
+	for _, gate := range experiment.Gates {
+		result := inputs.evaluate(ctx, gate)
+		if !result.Passed {
+			verdict.Passed = false
+		}
+		verdict.Results = append(verdict.Results, result)
+	}
+
+	if experiment.Status == evaluation.ExperimentStatusFailed || experiment.Status == evaluation.ExperimentStatusCancelled {
+		verdict.Passed = false
+		verdict.Reason = fmt.Sprintf("experiment %s", experiment.Status)
+	} else if len(experiment.Gates) == 0 {
+		verdict.Reason = "no gates configured"
+	}
+
+	verdict.Status = evaluation.GateVerdictFailed
+	if verdict.Passed {
+		verdict.Status = evaluation.GateVerdictPassed
+	}
+	return verdict
+}
+
+func isFinishedExperiment(status evaluation.ExperimentStatus) bool {
+	switch status {
+	case evaluation.ExperimentStatusCompleted,
+		evaluation.ExperimentStatusPartial,
+		evaluation.ExperimentStatusFailed,
+		evaluation.ExperimentStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// gateInputs loads the data gates assert on once per evaluation
+type gateInputs struct {
+	service    *experimentService
+	experiment *evaluation.Experiment
+
+	metrics    *evaluation.ExperimentMetricsResponse
+	itemScores []*observability.Score
+	scoresErr  error
+	scoresRead bool
+}
+
+func (in *gateInputs) evaluate(ctx context.Context, gate evaluation.ExperimentGate) evaluation.GateResult {
+	result := evaluation.GateResult{Gate: gate, Name: gate.DisplayName()}
+
+	if gate.Metric == evaluation.GateMetricNoRegression {
+		regressed, err := in.regressions(ctx, gate)
+		if err != nil {
+			result.Message = err.Error()
+			return result
+		}
+		count := float64(len(regressed))
+		result.Actual = &count
+		result.Passed = len(regressed) == 0
+		if result.Passed {
+			result.Message = "no significant regression against the baseline"
+		} else {
+			result.Message = "significant regression of " + strings.Join(regressed, ", ")
+		}
+		return result
+	}
+
+	actual, label, err := in.metric(ctx, gate)
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+	result.Actual = &actual
+	result.Passed = gate.Check(actual)
+	result.Message = fmt.Sprintf("%s is %.6g, expected %s %g", label, actual, gate.Operator, gate.Threshold)
+	return result
+}
+
+// metric computes the value a threshold gate compares, with a label for its message
+func (in *gateInputs) metric(ctx context.Context, gate evaluation.ExperimentGate) (float64, string, error) {
+	switch gate.Metric {
+	case evaluation.GateMetricScoreMean:
+		label := gate.ScoreName + " mean"
+		if score, ok := in.getMetrics(ctx).Scores[gate.ScoreName]; ok {
+			return score.Mean, label, nil
+		}
+		// Aggregations only cover numeric scores; boolean pass rates come from the items
+		values, err := in.scoreValues(ctx, gate.ScoreName)
+		if err != nil {
+			return 0, label, err
+		}
+		if len(values) == 0 {
+			return 0, label, fmt.Errorf("no %s scores recorded", gate.ScoreName)
+		}
+		var sum float64
+		for _, v := range values {
+			sum += v
+		}
+		return sum / float64(len(values)), label, nil
+
+	case evaluation.GateMetricScoreRate:
+		label := fmt.Sprintf("rate of %s = %g", gate.ScoreName, *gate.MatchValue)
+		values, err := in.scoreValues(ctx, gate.ScoreName)
+		if err != nil {
+			return 0, label, err
+		}
+		if len(values) == 0 {
+			return 0, label, fmt.Errorf("no %s scores recorded", gate.ScoreName)
+		}
+		matched := 0
+		for _, v := range values {
+			if v == *gate.MatchValue {
+				matched++
+			}
+		}
+		return float64(matched) / float64(len(values)), label, nil
+
+	case evaluation.GateMetricCostPerItem:
+		cost, err := in.costPerItem(ctx)
+		return cost, "cost per item", err
+
+	case evaluation.GateMetricErrorRate:
+		processed := in.experiment.CompletedItems + in.experiment.FailedItems
+		if processed == 0 {
+			return 0, "error rate", errors.New("no items processed")
+		}
+		return float64(in.experiment.FailedItems) / float64(processed), "error rate", nil
+	}
+
+	return 0, string(gate.Metric), fmt.Errorf("unsupported gate metric %q", gate.Metric)
+}
+
+func (in *gateInputs) getMetrics(ctx context.Context) *evaluation.ExperimentMetricsResponse {
+	if in.metrics == nil {
+		scoreAggs, err := in.service.scoreRepo.GetAggregationsByExperiments(ctx, in.experiment.ProjectID.String(), []string{in.experiment.ID.String()})
+		if err != nil {
+			in.service.logger.Warn("failed to get score aggregations for gates",
+				"error", err,
+				"experiment_id", in.experiment.ID,
+			)
+		}
+		in.metrics = in.service.buildMetricsResponse(in.experiment, scoreAggs)
+	}
+	return in.metrics
+}
+
+// scoreValues returns the values of a score over the experiment's items
+func (in *gateInputs) scoreValues(ctx context.Context, name string) ([]float64, error) {
+	if !in.scoresRead {
+		in.scoresRead = true
+		in.itemScores, in.scoresErr = in.service.scoreRepo.GetItemScoresByExperiments(ctx, in.experiment.ProjectID.String(), []string{in.experiment.ID.String()})
+	}
+	if in.scoresErr != nil {
+		return nil, fmt.Errorf("failed to read scores: %w", in.scoresErr)
+	}
+
+	seen := make(map[string]struct{})
+	var values []float64
+	for _, score := range in.itemScores {
+		if score.Name != name || score.Value == nil {
+			continue
+		}
+		if _, dup := seen[score.ID]; dup {
+			continue
+		}
+		seen[score.ID] = struct{}{}
+		values = append(values, *score.Value)
+	}
+	return values, nil
+}
+
+// costPerItem averages the "cost" recorded in item metadata, which the server
+// side runner writes and SDK experiments report alongside their outputs
+func (in *gateInputs) costPerItem(ctx context.Context) (float64, error) {
+	var total float64
+	costed := 0
+	for offset := 0; ; offset += gateItemPageSize {
+		items, _, err := in.service.itemRepo.List(ctx, in.experiment.ID, gateItemPageSize, offset)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read experiment items: %w", err)
+		}
+		for _, item := range items {
+			if cost, ok := metadataNumber(item.Metadata, "cost"); ok {
+				total += cost
+				costed++
+			}
+		}
+		if len(items) < gateItemPageSize {
+			break
+		}
+	}
+
+	if costed == 0 {
+		return 0, errors.New("no item reports a cost")
+	}
+	return total / float64(costed), nil
+}
+
+// regressions returns the scores that regressed significantly against the gate's baseline
+func (in *gateInputs) regressions(ctx context.Context, gate evaluation.ExperimentGate) ([]string, error) {
+	baselineID, err := ulid.Parse(*gate.BaselineExperimentID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid baseline experiment: %w", err)
+	}
+
+	comparison, err := in.service.CompareExperiments(ctx, in.experiment.ProjectID, []ulid.ULID{baselineID, in.experiment.ID}, &baselineID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare with baseline: %w", err)
+	}
+
+	expID := in.experiment.ID.String()
+	var regressed []string
+	found := false
+	for scoreName, byExperiment := range comparison.Comparisons {
+		if gate.ScoreName != "" && scoreName != gate.ScoreName {
+			continue
+		}
+		if paired, ok := byExperiment[expID]; ok {
+			found = true
+			if paired.SignificantRegression {
+				regressed = append(regressed, scoreName)
+			}
+		}
+	}
+	if !found {
+		return nil, errors.New("no scores shared with the baseline on the same dataset items")
+	}
+
+	sort.Strings(regressed)
+	return regressed, nil
+}
+
+func metadataNumber(metadata map[string]interface{}, key string) (float64, bool) {
+	switch v := metadata[key].(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,179 @@
+package evaluation
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"brokle/internal/core/domain/evaluation"
+	"brokle/internal/core/domain/observability"
+	"brokle/pkg/ulid"
+)
+
+// fakeGateScoreRepository serves the score reads gate evaluation makes
+type fakeGateScoreRepository struct {
+	observability.ScoreRepository
+	aggregations map[string]map[string]*observability.ScoreAggregation
+	scores       []*observability.Score
+}
+
+func (f *fakeGateScoreRepository) GetAggregationsByExperiments(ctx context.Context, projectID string, experimentIDs []string) (map[string]map[string]*observability.ScoreAggregation, error) {
+	return f.aggregations, nil
+}
+
+func (f *fakeGateScoreRepository) GetItemScoresByExperiments(ctx context.Context, projectID string, experimentIDs []string) ([]*observability.Score, error) {
+	return f.scores, nil
+}
+
+func TestExperimentService_EvaluateGates(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	newExperiment := func(gates ...evaluation.ExperimentGate) *evaluation.Experiment {
+		exp := evaluation.NewExperiment(ulid.New(), "nightly-rag")
+		exp.Status = evaluation.ExperimentStatusCompleted
+		exp.TotalItems = 100
+		exp.CompletedItems = 99
+		exp.FailedItems = 1
+		exp.Gates = gates
+		return exp
+	}
+
+	t.Run("thresholds on scores, cost and errors", func(t *testing.T) {
+		exp := newExperiment(
+			evaluation.ExperimentGate{Metric: evaluation.GateMetricScoreMean, ScoreName: "faithfulness", Operator: evaluation.GateOperatorGTE, Threshold: 0.8},
+			evaluation.ExperimentGate{Name: "json", Metric: evaluation.GateMetricScoreRate, ScoreName: "json_valid", Operator: evaluation.GateOperatorLTE, Threshold: 0.02, MatchValue: floatPtr(0)},
+			evaluation.ExperimentGate{Metric: evaluation.GateMetricCostPerItem, Operator: evaluation.GateOperatorLTE, Threshold: 0.002},
+			evaluation.ExperimentGate{Metric: evaluation.GateMetricErrorRate, Operator: evaluation.GateOperatorLT, Threshold: 0.05},
+		)
+		expID := exp.ID.String()
+
+		scoreRepo := &fakeGateScoreRepository{
+			aggregations: map[string]map[string]*observability.ScoreAggregation{
+				"faithfulness": {expID: {Mean: 0.85, Count: 99}},
+			},
+			scores: []*observability.Score{
+				itemScore("s1", expID, "i1", "json_valid", observability.ScoreTypeBoolean, floatPtr(1), nil),
+				itemScore("s2", expID, "i2", "json_valid", observability.ScoreTypeBoolean, floatPtr(0), nil),
+				itemScore("s2", expID, "i2", "json_valid", observability.ScoreTypeBoolean, floatPtr(0), nil), // unmerged duplicate
+				itemScore("s3", expID, "i3", "json_valid", observability.ScoreTypeBoolean, floatPtr(1), nil),
+				itemScore("s4", expID, "i4", "json_valid", observability.ScoreTypeBoolean, floatPtr(1), nil),
+			},
+		}
+		itemRepo := new(MockExperimentItemRepository)
+		itemRepo.On("List", mock.Anything, exp.ID, gateItemPageSize, 0).Return([]*evaluation.ExperimentItem{
+			{Metadata: map[string]interface{}{"cost": 0.001}},
+			{Metadata: map[string]interface{}{"cost": 0.002}},
+			{Metadata: map[string]interface{}{}},
+		}, int64(3), nil)
+
+		service := &experimentService{itemRepo: itemRepo, scoreRepo: scoreRepo, logger: logger}
+		verdict := service.evaluateGates(context.Background(), exp)
+
+		require.Len(t, verdict.Results, 4)
+		assert.Equal(t, evaluation.GateVerdictFailed, verdict.Status)
+		assert.False(t, verdict.Passed)
+
+		assert.True(t, verdict.Results[0].Passed)
+		assert.Equal(t, "faithfulness mean >= 0.8", verdict.Results[0].Name)
+		assert.InDelta(t, 0.85, *verdict.Results[0].Actual, 1e-9)
+
+		// One of four items failed json_valid
+		assert.False(t, verdict.Results[1].Passed)
+		assert.Equal(t, "json", verdict.Results[1].Name)
+		assert.InDelta(t, 0.25, *verdict.Results[1].Actual, 1e-9)
+
+		assert.True(t, verdict.Results[2].Passed)
+		assert.InDelta(t, 0.0015, *verdict.Results[2].Actual, 1e-12)
+
+		assert.True(t, verdict.Results[3].Passed)
+		assert.InDelta(t, 0.01, *verdict.Results[3].Actual, 1e-12)
+	})
+
+	t.Run("boolean score mean falls back to item scores", func(t *testing.T) {
+		exp := newExperiment(evaluation.ExperimentGate{Metric: evaluation.GateMetricScoreMean, ScoreName: "passed", Operator: evaluation.GateOperatorGTE, Threshold: 0.5})
+		expID := exp.ID.String()
+		scoreRepo := &fakeGateScoreRepository{scores: []*observability.Score{
+			itemScore("s1", expID, "i1", "passed", observability.ScoreTypeBoolean, floatPtr(1), nil),
+			itemScore("s2", expID, "i2", "passed", observability.ScoreTypeBoolean, floatPtr(1), nil),
+			itemScore("s3", expID, "i3", "passed", observability.ScoreTypeBoolean, floatPtr(0), nil),
+		}}
+
+		service := &experimentService{scoreRepo: scoreRepo, logger: logger}
+		verdict := service.evaluateGates(context.Background(), exp)
+
+		assert.Equal(t, evaluation.GateVerdictPassed, verdict.Status)
+		assert.InDelta(t, 2.0/3, *verdict.Results[0].Actual, 1e-9)
+	})
+
+	t.Run("missing data fails the gate", func(t *testing.T) {
+		exp := newExperiment(
+			evaluation.ExperimentGate{Metric: evaluation.GateMetricScoreMean, ScoreName: "relevance", Operator: evaluation.GateOperatorGTE, Threshold: 0.5},
+			evaluation.ExperimentGate{Metric: evaluation.GateMetricCostPerItem, Operator: evaluation.GateOperatorLTE, Threshold: 1},
+		)
+		itemRepo := new(MockExperimentItemRepository)
+		itemRepo.On("List", mock.Anything, exp.ID, gateItemPageSize, 0).Return([]*evaluation.ExperimentItem{}, int64(0), nil)
+
+		service := &experimentService{itemRepo: itemRepo, scoreRepo: &fakeGateScoreRepository{}, logger: logger}
+		verdict := service.evaluateGates(context.Background(), exp)
+
+		assert.False(t, verdict.Passed)
+		assert.Nil(t, verdict.Results[0].Actual)
+		assert.Equal(t, "no relevance scores recorded", verdict.Results[0].Message)
+		assert.Equal(t, "no item reports a cost", verdict.Results[1].Message)
+	})
+
+	t.Run("cancelled experiment fails with no gates", func(t *testing.T) {
+		exp := newExperiment()
+		exp.Status = evaluation.ExperimentStatusCancelled
+
+		service := &experimentService{scoreRepo: &fakeGateScoreRepository{}, logger: logger}
+		verdict := service.evaluateGates(context.Background(), exp)
+
+		assert.Equal(t, evaluation.GateVerdictFailed, verdict.Status)
+		assert.Equal(t, "experiment cancelled", verdict.Reason)
+		assert.Empty(t, verdict.Results)
+	})
+}
+
+func TestExperimentService_GetVerdict(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	ctx := context.Background()
+
+	t.Run("pending while running", func(t *testing.T) {
+		exp := evaluation.NewExperiment(ulid.New(), "nightly-rag")
+		exp.Status = evaluation.ExperimentStatusRunning
+		repo := new(MockExperimentRepository)
+		repo.On("GetByID", ctx, exp.ID, exp.ProjectID).Return(exp, nil)
+
+		service := &experimentService{repo: repo, logger: logger}
+		verdict, err := service.GetVerdict(ctx, exp.ID, exp.ProjectID)
+
+		require.NoError(t, err)
+		assert.Equal(t, evaluation.GateVerdictPending, verdict.Status)
+		assert.False(t, verdict.Passed)
+		assert.Equal(t, "experiment is running", verdict.Reason)
+	})
+
+	t.Run("evaluates and stores a missing verdict", func(t *testing.T) {
+		exp := evaluation.NewExperiment(ulid.New(), "nightly-rag")
+		exp.Status = evaluation.ExperimentStatusCompleted
+		exp.CompletedItems = 10
+		exp.Gates = []evaluation.ExperimentGate{{Metric: evaluation.GateMetricErrorRate, Operator: evaluation.GateOperatorLTE, Threshold: 0}}
+		repo := new(MockExperimentRepository)
+		repo.On("GetByID", ctx, exp.ID, exp.ProjectID).Return(exp, nil)
+		repo.On("UpdateGates", ctx, exp, exp.ProjectID).Return(nil)
+
+		service := &experimentService{repo: repo, scoreRepo: &fakeGateScoreRepository{}, logger: logger}
+		verdict, err := service.GetVerdict(ctx, exp.ID, exp.ProjectID)
+
+		require.NoError(t, err)
+		assert.Equal(t, evaluation.GateVerdictPassed, verdict.Status)
+		assert.Same(t, verdict, exp.GateVerdict)
+		repo.AssertExpectations(t)
+	})
+}
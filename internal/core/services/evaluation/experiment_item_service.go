@@ -216,3 +216,18 @@ func (s *experimentItemService) List(ctx context.Context, experimentID ulid.ULID
 	}
 	return items, total, nil
 }
+
+func (s *experimentItemService) ListDatasetItemIDs(ctx context.Context, experimentID ulid.ULID, projectID ulid.ULID) ([]ulid.ULID, error) {
+	if _, err := s.experimentRepo.GetByID(ctx, experimentID, projectID); err != nil {
+		if errors.Is(err, evaluation.ErrExperimentNotFound) {
+			return nil, appErrors.NewNotFoundError(fmt.Sprintf("experiment %s", experimentID))
+		}
+		return nil, appErrors.NewInternalError("failed to verify experiment", err)
+	}
+
+	ids, err := s.itemRepo.ListDatasetItemIDs(ctx, experimentID)
+	if err != nil {
+		return nil, appErrors.NewInternalError("failed to list experiment dataset items", err)
+	}
+	return ids, nil
+}
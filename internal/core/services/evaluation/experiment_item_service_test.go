@@ -66,6 +66,16 @@ func (m *MockExperimentRepository) IncrementCountersAndUpdateStatus(ctx context.
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockExperimentRepository) UpdateStatus(ctx context.Context, experiment *evaluation.Experiment, projectID ulid.ULID) error {
+	args := m.Called(ctx, experiment, projectID)
+	return args.Error(0)
+}
+
+func (m *MockExperimentRepository) UpdateGates(ctx context.Context, experiment *evaluation.Experiment, projectID ulid.ULID) error {
+	args := m.Called(ctx, experiment, projectID)
+	return args.Error(0)
+}
+
 func (m *MockExperimentRepository) GetProgress(ctx context.Context, id, projectID ulid.ULID) (*evaluation.Experiment, error) {
 	args := m.Called(ctx, id, projectID)
 	if args.Get(0) == nil {
@@ -101,6 +111,22 @@ func (m *MockExperimentItemRepository) CountByExperiment(ctx context.Context, ex
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *MockExperimentItemRepository) ListDatasetItemIDs(ctx context.Context, experimentID ulid.ULID) ([]ulid.ULID, error) {
+	args := m.Called(ctx, experimentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]ulid.ULID), args.Error(1)
+}
+
+func (m *MockExperimentItemRepository) ListDatasetItemLinks(ctx context.Context, experimentIDs []ulid.ULID) ([]*evaluation.ExperimentItem, error) {
+	args := m.Called(ctx, experimentIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*evaluation.ExperimentItem), args.Error(1)
+}
+
 type MockDatasetItemRepository struct {
 	mock.Mock
 }
@@ -0,0 +1,108 @@
+package evaluation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"brokle/internal/core/domain/evaluation"
+	"brokle/internal/core/domain/observability"
+	appErrors "brokle/pkg/errors"
+	"brokle/pkg/ulid"
+)
+
+// pairwiseScoreMetadata is the part of a pairwise score's metadata that
+// identifies the match. Each match is written once per side.
+type pairwiseScoreMetadata struct {
+	Side                 string `json:"pairwise_side"`
+	OpponentExperimentID string `json:"opponent_experiment_id"`
+}
+
+// GetPairwiseLeaderboard ranks experiments by the pairwise judge scores between them.
+func (s *experimentService) GetPairwiseLeaderboard(ctx context.Context, projectID ulid.ULID, req *evaluation.PairwiseLeaderboardRequest) (*evaluation.PairwiseLeaderboard, error) {
+	scoreName := req.ScoreName
+	if scoreName == "" {
+		scoreName = evaluation.DefaultPairwiseScoreName
+	}
+
+	ids := make([]string, 0, len(req.ExperimentIDs))
+	names := make(map[string]string, len(req.ExperimentIDs))
+	for _, rawID := range req.ExperimentIDs {
+		id, err := ulid.Parse(rawID)
+		if err != nil {
+			return nil, appErrors.NewValidationError("experiment_ids", fmt.Sprintf("invalid experiment ID: %s", rawID))
+		}
+		if _, dup := names[id.String()]; dup {
+			continue
+		}
+		experiment, err := s.repo.GetByID(ctx, id, projectID)
+		if err != nil {
+			if errors.Is(err, evaluation.ErrExperimentNotFound) {
+				return nil, appErrors.NewNotFoundError(fmt.Sprintf("experiment %s", id))
+			}
+			return nil, appErrors.NewInternalError("failed to get experiment", err)
+		}
+		ids = append(ids, id.String())
+		names[id.String()] = experiment.Name
+	}
+
+	scores, err := s.scoreRepo.GetItemScoresByExperiments(ctx, projectID.String(), ids)
+	if err != nil {
+		return nil, appErrors.NewInternalError("failed to get pairwise scores", err)
+	}
+
+	matches := pairwiseMatches(scores, scoreName, names)
+	entries := evaluation.ComputePairwiseLeaderboard(ids, matches)
+	for _, entry := range entries {
+		entry.ExperimentName = names[entry.ExperimentID]
+	}
+
+	return &evaluation.PairwiseLeaderboard{
+		ScoreName: scoreName,
+		Matches:   len(matches),
+		Entries:   entries,
+	}, nil
+}
+
+// pairwiseMatches reads the matches between the given experiments from side A
+// of each pairwise score, in the order they were judged
+func pairwiseMatches(scores []*observability.Score, scoreName string, experiments map[string]string) []evaluation.PairwiseMatch {
+	sorted := make([]*observability.Score, 0, len(scores))
+	seen := make(map[string]struct{}, len(scores))
+	for _, score := range scores {
+		if score.Name != scoreName || score.ExperimentID == nil || score.StringValue == nil {
+			continue
+		}
+		// ReplacingMergeTree may return a score twice until parts are merged
+		if _, dup := seen[score.ID]; dup {
+			continue
+		}
+		seen[score.ID] = struct{}{}
+		sorted = append(sorted, score)
+	}
+	// Score IDs are ULIDs, so this is the order the judge wrote them in
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+
+	var matches []evaluation.PairwiseMatch
+	for _, score := range sorted {
+		var metadata pairwiseScoreMetadata
+		if err := json.Unmarshal([]byte(score.Metadata), &metadata); err != nil || metadata.Side != "a" {
+			continue
+		}
+		if _, ok := experiments[metadata.OpponentExperimentID]; !ok {
+			continue
+		}
+		outcome := evaluation.PairwiseOutcome(*score.StringValue)
+		if !outcome.IsValid() {
+			continue
+		}
+		matches = append(matches, evaluation.PairwiseMatch{
+			A:       *score.ExperimentID,
+			B:       metadata.OpponentExperimentID,
+			Outcome: outcome,
+		})
+	}
+	return matches
+}
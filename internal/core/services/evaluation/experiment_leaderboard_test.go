@@ -0,0 +1,100 @@
+package evaluation
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"brokle/internal/core/domain/evaluation"
+	"brokle/internal/core/domain/observability"
+	"brokle/pkg/ulid"
+)
+
+func pairwiseScore(id, experimentID, name, outcome, metadata string) *observability.Score {
+	score := itemScore(id, experimentID, "item-"+id, name, observability.ScoreTypeCategorical, nil, &outcome)
+	score.Metadata = metadata
+	return score
+}
+
+func TestPairwiseMatches(t *testing.T) {
+	experiments := map[string]string{"expA": "baseline", "expB": "candidate"}
+
+	scores := []*observability.Score{
+		pairwiseScore("s3", "expA", "preference", "tie", `{"pairwise_side":"a","opponent_experiment_id":"expB"}`),
+		pairwiseScore("s1", "expA", "preference", "win", `{"pairwise_side":"a","opponent_experiment_id":"expB"}`),
+		pairwiseScore("s2", "expB", "preference", "lose", `{"pairwise_side":"b","opponent_experiment_id":"expA"}`), // other side of s1
+		pairwiseScore("s1", "expA", "preference", "win", `{"pairwise_side":"a","opponent_experiment_id":"expB"}`),  // unmerged duplicate
+		pairwiseScore("s4", "expA", "preference", "win", `{"pairwise_side":"a","opponent_experiment_id":"expC"}`),  // opponent not ranked
+		pairwiseScore("s5", "expA", "helpfulness", "win", `{"pairwise_side":"a","opponent_experiment_id":"expB"}`),
+		pairwiseScore("s6", "expA", "preference", "maybe", `{"pairwise_side":"a","opponent_experiment_id":"expB"}`),
+		pairwiseScore("s7", "expA", "preference", "win", `not json`),
+	}
+
+	matches := pairwiseMatches(scores, "preference", experiments)
+
+	assert.Equal(t, []evaluation.PairwiseMatch{
+		{A: "expA", B: "expB", Outcome: evaluation.PairwiseOutcomeWin},
+		{A: "expA", B: "expB", Outcome: evaluation.PairwiseOutcomeTie},
+	}, matches)
+}
+
+func TestExperimentService_GetPairwiseLeaderboard(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	projectID := ulid.New()
+
+	expA := evaluation.NewExperiment(projectID, "gpt-4o")
+	expB := evaluation.NewExperiment(projectID, "claude")
+	a, b := expA.ID.String(), expB.ID.String()
+
+	repo := new(MockExperimentRepository)
+	repo.On("GetByID", mock.Anything, expA.ID, projectID).Return(expA, nil)
+	repo.On("GetByID", mock.Anything, expB.ID, projectID).Return(expB, nil)
+
+	scoreRepo := &fakeGateScoreRepository{
+		scores: []*observability.Score{
+			pairwiseScore("s1", b, "preference", "win", `{"pairwise_side":"a","opponent_experiment_id":"`+a+`"}`),
+			pairwiseScore("s2", b, "preference", "win", `{"pairwise_side":"a","opponent_experiment_id":"`+a+`"}`),
+			pairwiseScore("s3", a, "preference", "tie", `{"pairwise_side":"a","opponent_experiment_id":"`+b+`"}`),
+		},
+	}
+
+	service := &experimentService{repo: repo, scoreRepo: scoreRepo, logger: logger}
+	leaderboard, err := service.GetPairwiseLeaderboard(context.Background(), projectID, &evaluation.PairwiseLeaderboardRequest{
+		ExperimentIDs: []string{a, b, a},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, evaluation.DefaultPairwiseScoreName, leaderboard.ScoreName)
+	assert.Equal(t, 3, leaderboard.Matches)
+	require.Len(t, leaderboard.Entries, 2)
+
+	winner := leaderboard.Entries[0]
+	assert.Equal(t, b, winner.ExperimentID)
+	assert.Equal(t, "claude", winner.ExperimentName)
+	assert.Equal(t, 1, winner.Rank)
+	assert.Equal(t, 2, winner.Wins)
+	assert.Equal(t, 1, winner.Ties)
+	assert.Greater(t, winner.Elo, leaderboard.Entries[1].Elo)
+	assert.Equal(t, "gpt-4o", leaderboard.Entries[1].ExperimentName)
+}
+
+func TestExperimentService_GetPairwiseLeaderboard_NotFound(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	projectID := ulid.New()
+	missing := ulid.New()
+
+	repo := new(MockExperimentRepository)
+	repo.On("GetByID", mock.Anything, missing, projectID).Return(nil, evaluation.ErrExperimentNotFound)
+
+	service := &experimentService{repo: repo, scoreRepo: &fakeGateScoreRepository{}, logger: logger}
+	_, err := service.GetPairwiseLeaderboard(context.Background(), projectID, &evaluation.PairwiseLeaderboardRequest{
+		ExperimentIDs: []string{missing.String(), ulid.New().String()},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
@@ -9,27 +9,34 @@ import (
 
 	"brokle/internal/core/domain/evaluation"
 	"brokle/internal/core/domain/observability"
+	"brokle/internal/core/domain/webhook"
 	appErrors "brokle/pkg/errors"
 	"brokle/pkg/ulid"
 )
 
 type experimentService struct {
 	repo        evaluation.ExperimentRepository
+	itemRepo    evaluation.ExperimentItemRepository
 	datasetRepo evaluation.DatasetRepository
 	scoreRepo   observability.ScoreRepository
+	publisher   webhook.Publisher // Optional: emits experiment.completed
 	logger      *slog.Logger
 }
 
 func NewExperimentService(
 	repo evaluation.ExperimentRepository,
+	itemRepo evaluation.ExperimentItemRepository,
 	datasetRepo evaluation.DatasetRepository,
 	scoreRepo observability.ScoreRepository,
+	publisher webhook.Publisher,
 	logger *slog.Logger,
 ) evaluation.ExperimentService {
 	return &experimentService{
 		repo:        repo,
+		itemRepo:    itemRepo,
 		datasetRepo: datasetRepo,
 		scoreRepo:   scoreRepo,
+		publisher:   publisher,
 		logger:      logger,
 	}
 }
@@ -55,6 +62,13 @@ func (s *experimentService) Create(ctx context.Context, projectID ulid.ULID, req
 		experiment.DatasetID = &datasetID
 	}
 
+	if len(req.Gates) > 0 {
+		if verr := evaluation.ValidateGates(req.Gates); verr != nil {
+			return nil, appErrors.NewValidationError(verr.Field, verr.Message)
+		}
+		experiment.Gates = req.Gates
+	}
+
 	if validationErrors := experiment.Validate(); len(validationErrors) > 0 {
 		return nil, appErrors.NewValidationError(validationErrors[0].Field, validationErrors[0].Message)
 	}
@@ -90,6 +104,7 @@ func (s *experimentService) Update(ctx context.Context, id ulid.ULID, projectID
 	if req.Metadata != nil {
 		experiment.Metadata = req.Metadata
 	}
+	finished := false
 	if req.Status != nil {
 		oldStatus := experiment.Status
 		experiment.Status = *req.Status
@@ -105,6 +120,7 @@ func (s *experimentService) Update(ctx context.Context, id ulid.ULID, projectID
 			*req.Status == evaluation.ExperimentStatusCancelled) &&
 			experiment.CompletedAt == nil {
 			experiment.CompletedAt = &now
+			finished = true
 		}
 	}
 
@@ -127,6 +143,11 @@ func (s *experimentService) Update(ctx context.Context, id ulid.ULID, projectID
 		"status", experiment.Status,
 	)
 
+	if finished {
+		s.recordVerdict(ctx, experiment)
+		s.publishCompleted(ctx, experiment)
+	}
+
 	return experiment, nil
 }
 
@@ -213,6 +234,9 @@ func (s *experimentService) Rerun(ctx context.Context, sourceID ulid.ULID, proje
 		newExp.Metadata = make(map[string]interface{})
 	}
 	newExp.Metadata["source_experiment_id"] = sourceID.String()
+	if sourceExp.Gates != nil {
+		newExp.Gates = sourceExp.Gates
+	}
 
 	if validationErrors := newExp.Validate(); len(validationErrors) > 0 {
 		return nil, appErrors.NewValidationError(validationErrors[0].Field, validationErrors[0].Message)
@@ -298,8 +322,10 @@ func (s *experimentService) CompareExperiments(
 		}
 	}
 
-	// 5. Calculate diffs if baseline is provided
+	// 5. Calculate diffs and paired comparisons if baseline is provided
 	var diffs map[string]map[string]*evaluation.ScoreDiff
+	var comparisons map[string]map[string]*evaluation.PairedComparison
+	significantRegression := false
 	if baselineID != nil {
 		diffs = make(map[string]map[string]*evaluation.ScoreDiff)
 		baselineIDStr := baselineID.String()
@@ -318,18 +344,33 @@ func (s *experimentService) CompareExperiments(
 				diffs[scoreName][expID] = evaluation.CalculateDiff(baselineAgg, agg)
 			}
 		}
+
+		comparisons, err = s.comparePairedScores(ctx, projectID, experimentIDs, *baselineID)
+		if err != nil {
+			return nil, appErrors.NewInternalError("failed to compare experiment items", err)
+		}
+		for _, expComparisons := range comparisons {
+			for _, comparison := range expComparisons {
+				if comparison.SignificantRegression {
+					significantRegression = true
+				}
+			}
+		}
 	}
 
 	s.logger.Info("experiments compared",
 		"project_id", projectID,
 		"experiment_count", len(experimentIDs),
 		"score_names", len(scores),
+		"significant_regression", significantRegression,
 	)
 
 	return &evaluation.CompareExperimentsResponse{
-		Experiments: experimentSummaries,
-		Scores:      scores,
-		Diffs:       diffs,
+		Experiments:           experimentSummaries,
+		Scores:                scores,
+		Diffs:                 diffs,
+		Comparisons:           comparisons,
+		SignificantRegression: significantRegression,
 	}, nil
 }
 
@@ -389,11 +430,26 @@ func (s *experimentService) IncrementAndCheckCompletion(ctx context.Context, id
 			"experiment_id", id,
 			"project_id", projectID,
 		)
+
+		if experiment, err := s.repo.GetByID(ctx, id, projectID); err != nil {
+			s.logger.Warn("failed to load completed experiment", "experiment_id", id, "error", err)
+		} else {
+			s.recordVerdict(ctx, experiment)
+			s.publishCompleted(ctx, experiment)
+		}
 	}
 
 	return isComplete, nil
 }
 
+// publishCompleted emits experiment.completed for subscribed webhooks.
+func (s *experimentService) publishCompleted(ctx context.Context, experiment *evaluation.Experiment) {
+	if s.publisher == nil {
+		return
+	}
+	s.publisher.Publish(ctx, experiment.ProjectID, webhook.EventExperimentCompleted, experiment)
+}
+
 // GetMetrics returns comprehensive metrics for an experiment including progress,
 // performance, and score aggregations from ClickHouse.
 func (s *experimentService) GetMetrics(ctx context.Context, projectID, experimentID ulid.ULID) (*evaluation.ExperimentMetricsResponse, error) {
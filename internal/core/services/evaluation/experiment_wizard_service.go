@@ -9,13 +9,27 @@ import (
 	"reflect"
 	"time"
 
+	"github.com/redis/go-redis/v9"
+
 	"brokle/internal/core/domain/common"
 	"brokle/internal/core/domain/evaluation"
 	"brokle/internal/core/domain/prompt"
+	"brokle/internal/infrastructure/database"
 	appErrors "brokle/pkg/errors"
 	"brokle/pkg/ulid"
 )
 
+const (
+	experimentRunStream = "evaluation:experiment-runs"
+)
+
+// ExperimentRunMessage is the message format for the experiment run stream
+type ExperimentRunMessage struct {
+	ExperimentID ulid.ULID `json:"experiment_id"`
+	ProjectID    ulid.ULID `json:"project_id"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
 type experimentWizardService struct {
 	transactor         common.Transactor
 	experimentRepo     evaluation.ExperimentRepository
@@ -25,6 +39,7 @@ type experimentWizardService struct {
 	datasetVersionRepo evaluation.DatasetVersionRepository
 	promptRepo         prompt.PromptRepository
 	versionRepo        prompt.VersionRepository
+	redis              *database.RedisDB
 	logger             *slog.Logger
 }
 
@@ -37,6 +52,7 @@ func NewExperimentWizardService(
 	datasetVersionRepo evaluation.DatasetVersionRepository,
 	promptRepo prompt.PromptRepository,
 	versionRepo prompt.VersionRepository,
+	redis *database.RedisDB,
 	logger *slog.Logger,
 ) evaluation.ExperimentWizardService {
 	return &experimentWizardService{
@@ -48,6 +64,7 @@ func NewExperimentWizardService(
 		datasetVersionRepo: datasetVersionRepo,
 		promptRepo:         promptRepo,
 		versionRepo:        versionRepo,
+		redis:              redis,
 		logger:             logger,
 	}
 }
@@ -184,15 +201,11 @@ func (s *experimentWizardService) CreateFromWizard(
 		"run_immediately", req.RunImmediately,
 	)
 
-	// If run_immediately is true, update status to running with started_at timestamp
-	// Note: Actual execution would be handled by a worker or separate service
+	// If run_immediately is true, hand the experiment to the experiment run worker
 	if req.RunImmediately {
-		now := time.Now()
-		experiment.StartedAt = &now
-		experiment.Status = evaluation.ExperimentStatusRunning
-		if err := s.experimentRepo.Update(ctx, experiment, projectID); err != nil {
-			// Log but don't fail - experiment is created
-			s.logger.Warn("failed to update experiment status to running",
+		if err := s.startRun(ctx, experiment, projectID); err != nil {
+			// Log but don't fail - experiment is created and can be started with resume
+			s.logger.Warn("failed to start experiment run",
 				"experiment_id", experiment.ID,
 				"error", err,
 			)
@@ -202,6 +215,135 @@ func (s *experimentWizardService) CreateFromWizard(
 	return experiment, nil
 }
 
+func (s *experimentWizardService) CancelExperiment(
+	ctx context.Context,
+	experimentID ulid.ULID,
+	projectID ulid.ULID,
+) (*evaluation.Experiment, error) {
+	experiment, err := s.getRunnableExperiment(ctx, experimentID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if experiment.Status != evaluation.ExperimentStatusPending && experiment.Status != evaluation.ExperimentStatusRunning {
+		return nil, appErrors.NewConflictError(fmt.Sprintf("experiment is %s and cannot be cancelled", experiment.Status))
+	}
+
+	// The worker checks the status before each item, so items in flight finish
+	// and are recorded but no new ones start
+	now := time.Now()
+	experiment.Status = evaluation.ExperimentStatusCancelled
+	experiment.CompletedAt = &now
+	experiment.UpdatedAt = now
+	if err := s.experimentRepo.UpdateStatus(ctx, experiment, projectID); err != nil {
+		return nil, appErrors.NewInternalError("failed to cancel experiment", err)
+	}
+
+	s.logger.Info("experiment cancelled",
+		"experiment_id", experimentID,
+		"project_id", projectID,
+	)
+
+	return experiment, nil
+}
+
+func (s *experimentWizardService) ResumeExperiment(
+	ctx context.Context,
+	experimentID ulid.ULID,
+	projectID ulid.ULID,
+) (*evaluation.Experiment, error) {
+	experiment, err := s.getRunnableExperiment(ctx, experimentID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch experiment.Status {
+	case evaluation.ExperimentStatusPending, evaluation.ExperimentStatusCancelled, evaluation.ExperimentStatusFailed:
+	case evaluation.ExperimentStatusRunning:
+		return nil, appErrors.NewConflictError("experiment is already running")
+	default:
+		return nil, appErrors.NewConflictError(fmt.Sprintf("experiment is %s and cannot be resumed", experiment.Status))
+	}
+
+	if err := s.startRun(ctx, experiment, projectID); err != nil {
+		return nil, appErrors.NewInternalError("failed to queue experiment run", err)
+	}
+
+	s.logger.Info("experiment resumed",
+		"experiment_id", experimentID,
+		"project_id", projectID,
+		"completed_items", experiment.CompletedItems,
+		"failed_items", experiment.FailedItems,
+	)
+
+	return experiment, nil
+}
+
+// getRunnableExperiment loads an experiment the server can execute, i.e. one
+// created through the wizard with a stored config
+func (s *experimentWizardService) getRunnableExperiment(ctx context.Context, experimentID, projectID ulid.ULID) (*evaluation.Experiment, error) {
+	experiment, err := s.experimentRepo.GetByID(ctx, experimentID, projectID)
+	if err != nil {
+		if errors.Is(err, evaluation.ErrExperimentNotFound) {
+			return nil, appErrors.NewNotFoundError(fmt.Sprintf("experiment %s", experimentID))
+		}
+		return nil, appErrors.NewInternalError("failed to get experiment", err)
+	}
+
+	if experiment.ConfigID == nil {
+		return nil, appErrors.NewValidationError("experiment_id", "only experiments created from the dashboard are run by the server")
+	}
+
+	return experiment, nil
+}
+
+// startRun marks the experiment running and publishes it to the experiment run
+// stream. The previous status is restored when the message cannot be published.
+func (s *experimentWizardService) startRun(ctx context.Context, experiment *evaluation.Experiment, projectID ulid.ULID) error {
+	previousStatus := experiment.Status
+	previousStartedAt := experiment.StartedAt
+	previousCompletedAt := experiment.CompletedAt
+
+	now := time.Now()
+	if experiment.StartedAt == nil {
+		experiment.StartedAt = &now
+	}
+	experiment.Status = evaluation.ExperimentStatusRunning
+	experiment.CompletedAt = nil
+	experiment.UpdatedAt = now
+	if err := s.experimentRepo.UpdateStatus(ctx, experiment, projectID); err != nil {
+		return fmt.Errorf("failed to update experiment status: %w", err)
+	}
+
+	msgData, err := json.Marshal(ExperimentRunMessage{
+		ExperimentID: experiment.ID,
+		ProjectID:    projectID,
+		CreatedAt:    now,
+	})
+	if err == nil {
+		err = s.redis.Client.XAdd(ctx, &redis.XAddArgs{
+			Stream: experimentRunStream,
+			Values: map[string]interface{}{
+				"data": string(msgData),
+			},
+		}).Err()
+	}
+	if err != nil {
+		experiment.Status = previousStatus
+		experiment.StartedAt = previousStartedAt
+		experiment.CompletedAt = previousCompletedAt
+		if revertErr := s.experimentRepo.UpdateStatus(ctx, experiment, projectID); revertErr != nil {
+			s.logger.Error("failed to restore experiment status",
+				"experiment_id", experiment.ID,
+				"error", revertErr,
+			)
+		}
+		return fmt.Errorf("failed to publish experiment run: %w", err)
+	}
+
+	return nil
+}
+
 func (s *experimentWizardService) ValidateStep(
 	ctx context.Context,
 	projectID ulid.ULID,
@@ -0,0 +1,558 @@
+package observability
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"brokle/internal/core/domain/observability"
+	"brokle/internal/core/domain/webhook"
+	appErrors "brokle/pkg/errors"
+	"brokle/pkg/ulid"
+
+	"gorm.io/gorm"
+)
+
+// AlertService manages alert rules and evaluates them against span aggregations.
+type AlertService struct {
+	ruleRepo   observability.AlertRuleRepository
+	eventRepo  observability.AlertEventRepository
+	presetRepo observability.FilterPresetRepository
+	traceRepo  observability.TraceRepository
+	logger     *slog.Logger
+}
+
+// NewAlertService creates a new alert service.
+func NewAlertService(
+	ruleRepo observability.AlertRuleRepository,
+	eventRepo observability.AlertEventRepository,
+	presetRepo observability.FilterPresetRepository,
+	traceRepo observability.TraceRepository,
+	logger *slog.Logger,
+) *AlertService {
+	return &AlertService{
+		ruleRepo:   ruleRepo,
+		eventRepo:  eventRepo,
+		presetRepo: presetRepo,
+		traceRepo:  traceRepo,
+		logger:     logger,
+	}
+}
+
+// Create creates a new alert rule in the OK state with its own webhook signing secret.
+func (s *AlertService) Create(ctx context.Context, projectID, userID string, req *observability.CreateAlertRuleRequest) (*observability.AlertRule, error) {
+	secret, err := webhook.GenerateSecret()
+	if err != nil {
+		return nil, appErrors.NewInternalError("failed to generate webhook secret", err)
+	}
+
+	rule := &observability.AlertRule{
+		ID:             ulid.New().String(),
+		ProjectID:      projectID,
+		Name:           req.Name,
+		Description:    req.Description,
+		FilterPresetID: req.FilterPresetID,
+		Filter:         req.Filter,
+		Aggregation:    req.Aggregation,
+		Operator:       req.Operator,
+		Threshold:      req.Threshold,
+		WindowMinutes:  req.WindowMinutes,
+		PendingMinutes: req.PendingMinutes,
+		Channels:       observability.AlertChannels(req.Channels),
+		Enabled:        true,
+		State:          observability.AlertStateOK,
+		WebhookSecret:  secret,
+		CreatedBy:      &userID,
+	}
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+
+	if err := s.validateRule(ctx, rule, userID); err != nil {
+		return nil, err
+	}
+
+	exists, err := s.ruleRepo.ExistsByName(ctx, projectID, rule.Name, nil)
+	if err != nil {
+		return nil, appErrors.NewInternalError("failed to check alert rule name", err)
+	}
+	if exists {
+		return nil, appErrors.NewConflictError("an alert rule with this name already exists")
+	}
+
+	if err := s.ruleRepo.Create(ctx, rule); err != nil {
+		s.logger.Error("failed to create alert rule",
+			"error", err,
+			"project_id", projectID,
+			"name", rule.Name,
+		)
+		return nil, appErrors.NewInternalError("failed to create alert rule", err)
+	}
+
+	s.logger.Info("alert rule created",
+		"rule_id", rule.ID,
+		"project_id", projectID,
+		"name", rule.Name,
+		"user_id", userID,
+	)
+
+	return rule, nil
+}
+
+// Get returns an alert rule scoped to the project.
+func (s *AlertService) Get(ctx context.Context, projectID, id string) (*observability.AlertRule, error) {
+	rule, err := s.ruleRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErrors.NewNotFoundError("alert rule " + id)
+		}
+		return nil, appErrors.NewInternalError("failed to get alert rule", err)
+	}
+
+	// Verify project scoping - return 404 to avoid information leakage
+	if rule.ProjectID != projectID {
+		return nil, appErrors.NewNotFoundError("alert rule " + id)
+	}
+
+	return rule, nil
+}
+
+// List returns all alert rules of a project.
+func (s *AlertService) List(ctx context.Context, projectID string) ([]*observability.AlertRule, error) {
+	rules, err := s.ruleRepo.ListByProject(ctx, projectID)
+	if err != nil {
+		return nil, appErrors.NewInternalError("failed to list alert rules", err)
+	}
+	return rules, nil
+}
+
+// Update updates an alert rule. Changing what is evaluated or disabling the rule
+// resets its state, since the previous state no longer describes the rule.
+func (s *AlertService) Update(ctx context.Context, projectID, id, userID string, req *observability.UpdateAlertRuleRequest) (*observability.AlertRule, error) {
+	rule, err := s.Get(ctx, projectID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	reset := false
+	if req.Name != nil && *req.Name != rule.Name {
+		exists, err := s.ruleRepo.ExistsByName(ctx, projectID, *req.Name, &id)
+		if err != nil {
+			return nil, appErrors.NewInternalError("failed to check alert rule name", err)
+		}
+		if exists {
+			return nil, appErrors.NewConflictError("an alert rule with this name already exists")
+		}
+		rule.Name = *req.Name
+	}
+	if req.Description != nil {
+		rule.Description = req.Description
+	}
+	if req.FilterPresetID != nil {
+		if *req.FilterPresetID == "" {
+			rule.FilterPresetID = nil
+		} else {
+			rule.FilterPresetID = req.FilterPresetID
+		}
+		reset = true
+	}
+	if req.Filter != nil {
+		rule.Filter = *req.Filter
+		reset = true
+	}
+	if req.Aggregation != nil {
+		rule.Aggregation = *req.Aggregation
+		reset = true
+	}
+	if req.Operator != nil {
+		rule.Operator = *req.Operator
+		reset = true
+	}
+	if req.Threshold != nil {
+		rule.Threshold = *req.Threshold
+		reset = true
+	}
+	if req.WindowMinutes != nil {
+		rule.WindowMinutes = *req.WindowMinutes
+		reset = true
+	}
+	if req.PendingMinutes != nil {
+		rule.PendingMinutes = *req.PendingMinutes
+	}
+	if req.Channels != nil {
+		rule.Channels = observability.AlertChannels(req.Channels)
+	}
+	if req.Enabled != nil {
+		if !*req.Enabled {
+			reset = true
+		}
+		rule.Enabled = *req.Enabled
+	}
+
+	if err := s.validateRule(ctx, rule, userID); err != nil {
+		return nil, err
+	}
+
+	if reset {
+		rule.State = observability.AlertStateOK
+		rule.StateChangedAt = nil
+	}
+
+	if err := s.ruleRepo.Update(ctx, rule); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErrors.NewNotFoundError("alert rule " + id)
+		}
+		return nil, appErrors.NewInternalError("failed to update alert rule", err)
+	}
+
+	return rule, nil
+}
+
+// Delete deletes an alert rule and its history.
+func (s *AlertService) Delete(ctx context.Context, projectID, id string) error {
+	if _, err := s.Get(ctx, projectID, id); err != nil {
+		return err
+	}
+
+	if err := s.ruleRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return appErrors.NewNotFoundError("alert rule " + id)
+		}
+		return appErrors.NewInternalError("failed to delete alert rule", err)
+	}
+
+	s.logger.Info("alert rule deleted", "rule_id", id, "project_id", projectID)
+	return nil
+}
+
+// RotateWebhookSecret replaces the secret webhook channel deliveries are signed with.
+// The previous secret stops working immediately.
+func (s *AlertService) RotateWebhookSecret(ctx context.Context, projectID, id string) (*observability.AlertRule, error) {
+	rule, err := s.Get(ctx, projectID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := webhook.GenerateSecret()
+	if err != nil {
+		return nil, appErrors.NewInternalError("failed to generate webhook secret", err)
+	}
+	rule.WebhookSecret = secret
+
+	if err := s.ruleRepo.Update(ctx, rule); err != nil {
+		return nil, appErrors.NewInternalError("failed to rotate webhook secret", err)
+	}
+
+	s.logger.Info("alert rule webhook secret rotated", "rule_id", id, "project_id", projectID)
+	return rule, nil
+}
+
+// Silence suppresses notifications for a rule. Evaluation and history continue.
+func (s *AlertService) Silence(ctx context.Context, projectID, id string, req *observability.SilenceAlertRuleRequest) (*observability.AlertRule, error) {
+	if req.DurationMinutes < 1 || req.DurationMinutes > observability.AlertRuleMaxSilenceMinutes {
+		return nil, appErrors.NewValidationError("duration_minutes", "duration_minutes must be between 1 and 43200")
+	}
+
+	rule, err := s.Get(ctx, projectID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	until := time.Now().Add(time.Duration(req.DurationMinutes) * time.Minute)
+	rule.SilencedUntil = &until
+	rule.SilenceReason = req.Reason
+
+	if err := s.ruleRepo.Update(ctx, rule); err != nil {
+		return nil, appErrors.NewInternalError("failed to silence alert rule", err)
+	}
+
+	s.logger.Info("alert rule silenced", "rule_id", id, "project_id", projectID, "until", until)
+	return rule, nil
+}
+
+// Unsilence resumes notifications for a rule.
+func (s *AlertService) Unsilence(ctx context.Context, projectID, id string) (*observability.AlertRule, error) {
+	rule, err := s.Get(ctx, projectID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rule.SilencedUntil = nil
+	rule.SilenceReason = nil
+
+	if err := s.ruleRepo.Update(ctx, rule); err != nil {
+		return nil, appErrors.NewInternalError("failed to unsilence alert rule", err)
+	}
+
+	return rule, nil
+}
+
+// ListHistory returns state transitions of a rule, newest first.
+func (s *AlertService) ListHistory(ctx context.Context, projectID, id string, limit, offset int) ([]*observability.AlertEvent, int64, error) {
+	if _, err := s.Get(ctx, projectID, id); err != nil {
+		return nil, 0, err
+	}
+
+	if limit <= 0 {
+		limit = observability.AlertHistoryDefaultLimit
+	} else if limit > observability.AlertHistoryMaxLimit {
+		limit = observability.AlertHistoryMaxLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	events, total, err := s.eventRepo.ListByRule(ctx, id, limit, offset)
+	if err != nil {
+		return nil, 0, appErrors.NewInternalError("failed to list alert history", err)
+	}
+	return events, total, nil
+}
+
+// EvaluateAll evaluates every enabled rule once. A failing rule is logged and skipped
+// so one broken rule cannot block the others. Notifications are returned for delivery.
+func (s *AlertService) EvaluateAll(ctx context.Context, now time.Time) (*observability.AlertEvaluationSummary, error) {
+	rules, err := s.ruleRepo.ListEnabled(ctx)
+	if err != nil {
+		return nil, appErrors.NewInternalError("failed to list alert rules", err)
+	}
+
+	summary := &observability.AlertEvaluationSummary{}
+	for _, rule := range rules {
+		if ctx.Err() != nil {
+			return summary, ctx.Err()
+		}
+
+		event, err := s.evaluateRule(ctx, rule, now)
+		if err != nil {
+			summary.RulesFailed++
+			s.logger.Warn("alert rule evaluation failed",
+				"rule_id", rule.ID,
+				"project_id", rule.ProjectID,
+				"error", err,
+			)
+			continue
+		}
+		summary.RulesEvaluated++
+
+		if event == nil {
+			continue
+		}
+		summary.Transitions++
+		if event.Notified {
+			summary.Notifications = append(summary.Notifications, &observability.AlertNotification{Rule: rule, Event: event})
+		}
+	}
+
+	return summary, nil
+}
+
+// evaluateRule computes the rule value, advances its state and records a history
+// event on transitions. Returns nil when the state did not change.
+func (s *AlertService) evaluateRule(ctx context.Context, rule *observability.AlertRule, now time.Time) (*observability.AlertEvent, error) {
+	node, err := s.resolveFilter(ctx, rule)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := s.computeValue(ctx, rule, node, now)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := rule.State
+	if previous == "" {
+		previous = observability.AlertStateOK
+	}
+	pendingFor := time.Duration(rule.PendingMinutes) * time.Minute
+	next := observability.NextAlertState(previous, rule.Breached(value), rule.StateChangedAt, pendingFor, now)
+
+	rule.LastValue = value
+	rule.LastEvaluatedAt = &now
+	if next != previous {
+		rule.State = next
+		rule.StateChangedAt = &now
+	}
+
+	if err := s.ruleRepo.UpdateEvaluation(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	if next == previous {
+		return nil, nil
+	}
+
+	// Only firing and resolution reach the channels; pending and OK are history only
+	notify := next == observability.AlertStateFiring || next == observability.AlertStateResolved
+	silenced := notify && rule.IsSilenced(now)
+
+	event := &observability.AlertEvent{
+		ID:            ulid.New().String(),
+		RuleID:        rule.ID,
+		ProjectID:     rule.ProjectID,
+		State:         next,
+		PreviousState: previous,
+		Value:         value,
+		Threshold:     rule.Threshold,
+		Silenced:      silenced,
+		Notified:      notify && !silenced && len(rule.Channels) > 0,
+	}
+	if err := s.eventRepo.Create(ctx, event); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("alert rule state changed",
+		"rule_id", rule.ID,
+		"project_id", rule.ProjectID,
+		"from", previous,
+		"to", next,
+		"silenced", silenced,
+	)
+
+	return event, nil
+}
+
+// computeValue runs the rule aggregation over spans in the trailing window.
+func (s *AlertService) computeValue(ctx context.Context, rule *observability.AlertRule, node observability.FilterNode, now time.Time) (*float64, error) {
+	agg, err := ParseAggregation(rule.Aggregation)
+	if err != nil {
+		return nil, err
+	}
+
+	start := now.Add(-time.Duration(rule.WindowMinutes) * time.Minute)
+	queryResult, err := NewSpanQueryBuilder().BuildAggregateQuery(
+		node,
+		rule.ProjectID,
+		&start,
+		&now,
+		[]*observability.SpanAggregation{agg},
+		nil,
+		0,
+		1,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.traceRepo.AggregateSpansByExpression(ctx, queryResult.Query, queryResult.Args, false, nil, []string{agg.Expression})
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	return rows[0].Values[agg.Expression], nil
+}
+
+// resolveFilter returns the filter of the rule's preset, or its expression.
+func (s *AlertService) resolveFilter(ctx context.Context, rule *observability.AlertRule) (observability.FilterNode, error) {
+	if rule.FilterPresetID != nil {
+		preset, err := s.presetRepo.GetByID(ctx, *rule.FilterPresetID)
+		if err != nil {
+			return nil, fmt.Errorf("get filter preset: %w", err)
+		}
+		if preset.ProjectID != rule.ProjectID {
+			return nil, fmt.Errorf("filter preset %s belongs to another project", preset.ID)
+		}
+		return PresetFilterNode(preset)
+	}
+
+	if rule.Filter == "" {
+		return nil, nil
+	}
+	return NewFilterParser().Parse(rule.Filter)
+}
+
+// validateRule checks the rule fields, its aggregation and filter, and preset access.
+func (s *AlertService) validateRule(ctx context.Context, rule *observability.AlertRule, userID string) error {
+	if errs := observability.ValidateAlertRule(rule); len(errs) > 0 {
+		return appErrors.NewValidationError(errs[0].Field, errs[0].Message)
+	}
+
+	if _, err := ParseAggregation(rule.Aggregation); err != nil {
+		return appErrors.NewValidationError("invalid aggregation", err.Error())
+	}
+
+	if rule.FilterPresetID == nil {
+		if rule.Filter != "" {
+			if _, err := NewFilterParser().Parse(rule.Filter); err != nil {
+				return appErrors.NewValidationError("invalid filter expression", err.Error())
+			}
+		}
+		return nil
+	}
+
+	preset, err := s.presetRepo.GetByID(ctx, *rule.FilterPresetID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return appErrors.NewNotFoundError("filter preset " + *rule.FilterPresetID)
+		}
+		return appErrors.NewInternalError("failed to get filter preset", err)
+	}
+	if preset.ProjectID != rule.ProjectID {
+		return appErrors.NewNotFoundError("filter preset " + *rule.FilterPresetID)
+	}
+	if !preset.IsPublic && (preset.CreatedBy == nil || *preset.CreatedBy != userID) {
+		return appErrors.NewForbiddenError("access to filter preset denied")
+	}
+	if _, err := PresetFilterNode(preset); err != nil {
+		return appErrors.NewValidationError("filter_preset_id", err.Error())
+	}
+
+	return nil
+}
+
+// PresetFilterNode converts the conditions of a saved filter preset into a filter AST.
+// Conditions are combined with AND; a preset without conditions matches all spans.
+func PresetFilterNode(preset *observability.FilterPreset) (observability.FilterNode, error) {
+	var conditions []observability.FilterCondition
+	if len(preset.Filters) > 0 {
+		if err := json.Unmarshal(preset.Filters, &conditions); err != nil {
+			return nil, fmt.Errorf("invalid preset filters: %w", err)
+		}
+	}
+
+	var node observability.FilterNode
+	for _, cond := range conditions {
+		op := observability.FilterOperator(cond.Operator)
+
+		value := cond.Value
+		if op == observability.FilterOpIn || op == observability.FilterOpNotIn {
+			values, ok := cond.Value.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%w: %s requires a list value", observability.ErrInvalidInClause, op)
+			}
+			strs := make([]string, len(values))
+			for i, v := range values {
+				strs[i] = fmt.Sprint(v)
+			}
+			value = strs
+		} else if op.RequiresValue() && op != observability.FilterOpIsEmpty && op != observability.FilterOpIsNotEmpty && cond.Value == nil {
+			return nil, fmt.Errorf("%w for %s", observability.ErrMissingValue, cond.Column)
+		}
+
+		leaf := &observability.ConditionNode{
+			Field:    cond.Column,
+			Operator: op,
+			Value:    value,
+			Negated:  op == observability.FilterOpNotExists,
+		}
+		if node == nil {
+			node = leaf
+		} else {
+			node = &observability.BinaryNode{Left: node, Right: leaf, Operator: observability.LogicAnd}
+		}
+	}
+
+	// Build once to reject unknown operators and invalid fields up front
+	if node != nil {
+		if _, _, err := NewSpanQueryBuilder().buildNode(node); err != nil {
+			return nil, err
+		}
+	}
+
+	return node, nil
+}
@@ -0,0 +1,229 @@
+package observability
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	obsDomain "brokle/internal/core/domain/observability"
+)
+
+func floatPtr(v float64) *float64 { return &v }
+
+func TestNextAlertState(t *testing.T) {
+	now := time.Date(2026, 2, 15, 12, 0, 0, 0, time.UTC)
+	recent := now.Add(-2 * time.Minute)
+	old := now.Add(-10 * time.Minute)
+
+	tests := []struct {
+		name         string
+		current      obsDomain.AlertState
+		breached     bool
+		pendingSince *time.Time
+		pendingFor   time.Duration
+		want         obsDomain.AlertState
+	}{
+		{"ok stays ok", obsDomain.AlertStateOK, false, nil, 5 * time.Minute, obsDomain.AlertStateOK},
+		{"ok breach enters pending", obsDomain.AlertStateOK, true, nil, 5 * time.Minute, obsDomain.AlertStatePending},
+		{"ok breach without pending period fires", obsDomain.AlertStateOK, true, nil, 0, obsDomain.AlertStateFiring},
+		{"pending within period stays pending", obsDomain.AlertStatePending, true, &recent, 5 * time.Minute, obsDomain.AlertStatePending},
+		{"pending past period fires", obsDomain.AlertStatePending, true, &old, 5 * time.Minute, obsDomain.AlertStateFiring},
+		{"pending cleared returns to ok", obsDomain.AlertStatePending, false, &recent, 5 * time.Minute, obsDomain.AlertStateOK},
+		{"firing stays firing", obsDomain.AlertStateFiring, true, &old, 5 * time.Minute, obsDomain.AlertStateFiring},
+		{"firing cleared resolves", obsDomain.AlertStateFiring, false, &old, 5 * time.Minute, obsDomain.AlertStateResolved},
+		{"resolved stays resolved", obsDomain.AlertStateResolved, false, &old, 5 * time.Minute, obsDomain.AlertStateResolved},
+		{"resolved breach enters pending", obsDomain.AlertStateResolved, true, &old, 5 * time.Minute, obsDomain.AlertStatePending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := obsDomain.NextAlertState(tt.current, tt.breached, tt.pendingSince, tt.pendingFor, now)
+			if got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAlertRule_Breached(t *testing.T) {
+	tests := []struct {
+		operator obsDomain.FilterOperator
+		value    *float64
+		want     bool
+	}{
+		{obsDomain.FilterOpGreaterThan, floatPtr(0.2), true},
+		{obsDomain.FilterOpGreaterThan, floatPtr(0.1), false},
+		{obsDomain.FilterOpGreaterOrEqual, floatPtr(0.1), true},
+		{obsDomain.FilterOpLessThan, floatPtr(0.05), true},
+		{obsDomain.FilterOpLessOrEqual, floatPtr(0.2), false},
+		{obsDomain.FilterOpGreaterThan, nil, false},
+		{obsDomain.FilterOpEqual, floatPtr(0.1), false},
+	}
+
+	for _, tt := range tests {
+		rule := &obsDomain.AlertRule{Operator: tt.operator, Threshold: 0.1}
+		if got := rule.Breached(tt.value); got != tt.want {
+			t.Errorf("%s threshold 0.1 with %v: expected %v, got %v", tt.operator, tt.value, tt.want, got)
+		}
+	}
+}
+
+func TestAlertRule_IsSilenced(t *testing.T) {
+	now := time.Now()
+	future := now.Add(time.Hour)
+	past := now.Add(-time.Hour)
+
+	if (&obsDomain.AlertRule{}).IsSilenced(now) {
+		t.Error("rule without silence should not be silenced")
+	}
+	if !(&obsDomain.AlertRule{SilencedUntil: &future}).IsSilenced(now) {
+		t.Error("rule silenced until the future should be silenced")
+	}
+	if (&obsDomain.AlertRule{SilencedUntil: &past}).IsSilenced(now) {
+		t.Error("expired silence should not apply")
+	}
+}
+
+func TestValidateAlertRule(t *testing.T) {
+	valid := func() *obsDomain.AlertRule {
+		return &obsDomain.AlertRule{
+			Name:          "High error rate",
+			Filter:        `service.name = "api"`,
+			Aggregation:   "avg(has_error)",
+			Operator:      obsDomain.FilterOpGreaterThan,
+			Threshold:     0.05,
+			WindowMinutes: 5,
+			Channels: obsDomain.AlertChannels{
+				{Type: obsDomain.AlertChannelEmail, Target: "oncall@example.com"},
+				{Type: obsDomain.AlertChannelWebhook, Target: "https://hooks.example.com/alerts"},
+				{Type: obsDomain.AlertChannelSlack, Target: "https://hooks.slack.com/services/T/B/X"},
+			},
+		}
+	}
+
+	if errs := obsDomain.ValidateAlertRule(valid()); len(errs) != 0 {
+		t.Fatalf("expected valid rule, got %v", errs)
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(r *obsDomain.AlertRule)
+		field  string
+	}{
+		{"missing name", func(r *obsDomain.AlertRule) { r.Name = "" }, "name"},
+		{"missing aggregation", func(r *obsDomain.AlertRule) { r.Aggregation = "" }, "aggregation"},
+		{"non-comparison operator", func(r *obsDomain.AlertRule) { r.Operator = obsDomain.FilterOpEqual }, "operator"},
+		{"zero window", func(r *obsDomain.AlertRule) { r.WindowMinutes = 0 }, "window_minutes"},
+		{"window too long", func(r *obsDomain.AlertRule) { r.WindowMinutes = obsDomain.AlertRuleMaxWindowMinutes + 1 }, "window_minutes"},
+		{"negative pending", func(r *obsDomain.AlertRule) { r.PendingMinutes = -1 }, "pending_minutes"},
+		{"invalid email", func(r *obsDomain.AlertRule) { r.Channels[0].Target = "not-an-email" }, "channels"},
+		{"non-http webhook", func(r *obsDomain.AlertRule) { r.Channels[1].Target = "ftp://example.com" }, "channels"},
+		{"internal webhook address", func(r *obsDomain.AlertRule) { r.Channels[1].Target = "http://169.254.169.254/latest" }, "channels"},
+		{"localhost webhook", func(r *obsDomain.AlertRule) { r.Channels[1].Target = "https://localhost:8080/hook" }, "channels"},
+		{"slack channel name", func(r *obsDomain.AlertRule) { r.Channels[2].Target = "#general" }, "channels"},
+		{"internal slack webhook", func(r *obsDomain.AlertRule) { r.Channels[2].Target = "https://10.0.0.5/hook" }, "channels"},
+		{"unknown channel type", func(r *obsDomain.AlertRule) { r.Channels[2].Type = "pager" }, "channels"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := valid()
+			tt.mutate(rule)
+			errs := obsDomain.ValidateAlertRule(rule)
+			if len(errs) == 0 {
+				t.Fatal("expected validation error")
+			}
+			if errs[0].Field != tt.field {
+				t.Errorf("expected error on %s, got %s", tt.field, errs[0].Field)
+			}
+		})
+	}
+}
+
+func TestPresetFilterNode(t *testing.T) {
+	presetWith := func(conditions string) *obsDomain.FilterPreset {
+		return &obsDomain.FilterPreset{Filters: json.RawMessage(conditions)}
+	}
+
+	t.Run("empty preset matches all spans", func(t *testing.T) {
+		node, err := PresetFilterNode(presetWith(`[]`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if node != nil {
+			t.Errorf("expected nil node, got %T", node)
+		}
+	})
+
+	t.Run("conditions are joined with AND", func(t *testing.T) {
+		node, err := PresetFilterNode(presetWith(`[
+			{"id":"1","column":"service.name","operator":"=","value":"api"},
+			{"id":"2","column":"duration","operator":">","value":1000}
+		]`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		binary, ok := node.(*obsDomain.BinaryNode)
+		if !ok {
+			t.Fatalf("expected BinaryNode, got %T", node)
+		}
+		if binary.Operator != obsDomain.LogicAnd {
+			t.Errorf("expected AND, got %s", binary.Operator)
+		}
+		left := binary.Left.(*obsDomain.ConditionNode)
+		if left.Field != "service.name" || left.Value != "api" {
+			t.Errorf("unexpected left condition: %+v", left)
+		}
+	})
+
+	t.Run("IN values become strings", func(t *testing.T) {
+		node, err := PresetFilterNode(presetWith(`[{"column":"service.name","operator":"IN","value":["api","worker"]}]`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		cond := node.(*obsDomain.ConditionNode)
+		values, ok := cond.Value.([]string)
+		if !ok || len(values) != 2 || values[1] != "worker" {
+			t.Errorf("expected []string{api, worker}, got %#v", cond.Value)
+		}
+	})
+
+	t.Run("NOT EXISTS is negated", func(t *testing.T) {
+		node, err := PresetFilterNode(presetWith(`[{"column":"service.name","operator":"NOT EXISTS"}]`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !node.(*obsDomain.ConditionNode).Negated {
+			t.Error("expected negated condition")
+		}
+	})
+
+	t.Run("IN without list is rejected", func(t *testing.T) {
+		_, err := PresetFilterNode(presetWith(`[{"column":"service.name","operator":"IN","value":"api"}]`))
+		if !errors.Is(err, obsDomain.ErrInvalidInClause) {
+			t.Errorf("expected ErrInvalidInClause, got %v", err)
+		}
+	})
+
+	t.Run("missing value is rejected", func(t *testing.T) {
+		_, err := PresetFilterNode(presetWith(`[{"column":"service.name","operator":"="}]`))
+		if !errors.Is(err, obsDomain.ErrMissingValue) {
+			t.Errorf("expected ErrMissingValue, got %v", err)
+		}
+	})
+
+	t.Run("unknown operator is rejected", func(t *testing.T) {
+		_, err := PresetFilterNode(presetWith(`[{"column":"service.name","operator":"LIKE","value":"api"}]`))
+		if err == nil {
+			t.Error("expected error for unknown operator")
+		}
+	})
+
+	t.Run("malformed filters are rejected", func(t *testing.T) {
+		_, err := PresetFilterNode(presetWith(`{"column":"service.name"}`))
+		if err == nil {
+			t.Error("expected error for malformed filters")
+		}
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"log/slog"
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 
@@ -102,18 +103,55 @@ func (s *ArchiveService) ArchiveBatch(
 
 // GenerateS3Path creates Hive-style partition path: {prefix}/project_id={id}/signal={type}/year={y}/month={m}/day={d}/{batch_id}.parquet
 func (s *ArchiveService) GenerateS3Path(projectID, signalType string, timestamp time.Time, batchID ulid.ULID) string {
+	return ArchiveDayPrefix(s.config.PathPrefix, projectID, signalType, timestamp) + batchID.String() + ".parquet"
+}
+
+// ArchiveSignalPrefix returns the Hive-style partition prefix holding all of one project's signal files.
+func ArchiveSignalPrefix(pathPrefix, projectID, signalType string) string {
+	return fmt.Sprintf("%sproject_id=%s/signal=%s/", pathPrefix, projectID, signalType)
+}
+
+// ArchiveDayPrefix returns the Hive-style partition prefix holding one project's signal files for a day.
+func ArchiveDayPrefix(pathPrefix, projectID, signalType string, day time.Time) string {
 	return fmt.Sprintf(
-		"%sproject_id=%s/signal=%s/year=%04d/month=%02d/day=%02d/%s.parquet",
-		s.config.PathPrefix,
-		projectID,
-		signalType,
-		timestamp.Year(),
-		timestamp.Month(),
-		timestamp.Day(),
-		batchID.String(),
+		"%syear=%04d/month=%02d/day=%02d/",
+		ArchiveSignalPrefix(pathPrefix, projectID, signalType),
+		day.Year(),
+		day.Month(),
+		day.Day(),
 	)
 }
 
+// ArchiveGlob returns a glob matching one project's signal files between start and end.
+// Ranges within a year list their months so only those partitions are scanned.
+func ArchiveGlob(pathPrefix, projectID, signalType string, start, end time.Time) string {
+	start, end = start.UTC(), end.UTC()
+
+	var partitions string
+	if start.Year() == end.Year() {
+		months := make([]string, 0, 12)
+		for m := start.Month(); m <= end.Month(); m++ {
+			months = append(months, fmt.Sprintf("%02d", m))
+		}
+		partitions = fmt.Sprintf("year=%04d/month=%s/day=*", start.Year(), globAlternatives(months))
+	} else {
+		years := make([]string, 0, end.Year()-start.Year()+1)
+		for y := start.Year(); y <= end.Year(); y++ {
+			years = append(years, fmt.Sprintf("%04d", y))
+		}
+		partitions = fmt.Sprintf("year=%s/month=*/day=*", globAlternatives(years))
+	}
+
+	return fmt.Sprintf("%sproject_id=%s/signal=%s/%s/*.parquet", pathPrefix, projectID, signalType, partitions)
+}
+
+func globAlternatives(values []string) string {
+	if len(values) == 1 {
+		return values[0]
+	}
+	return "{" + strings.Join(values, ",") + "}"
+}
+
 func (s *ArchiveService) IsEnabled() bool {
 	return s.config != nil && s.config.Enabled
 }
@@ -0,0 +1,636 @@
+package observability
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"brokle/internal/config"
+	authDomain "brokle/internal/core/domain/auth"
+	"brokle/internal/core/domain/observability"
+	infraStorage "brokle/internal/infrastructure/storage"
+	appErrors "brokle/pkg/errors"
+	"brokle/pkg/ulid"
+
+	"gorm.io/gorm"
+)
+
+// dataSubjectArchiveSignals are the archived signals whose records carry a trace ID.
+var dataSubjectArchiveSignals = []string{
+	observability.SignalTypeTraces,
+	observability.SignalTypeLogs,
+	observability.SignalTypeGenAI,
+}
+
+// dataSubjectArchive is the object storage holding archived Parquet files.
+type dataSubjectArchive interface {
+	ListKeys(ctx context.Context, prefix string) ([]string, error)
+	Download(ctx context.Context, key string) ([]byte, error)
+	Upload(ctx context.Context, key string, content []byte, contentType string) error
+	Delete(ctx context.Context, key string) error
+}
+
+// DataSubjectService erases or exports everything stored about one end user of a
+// project: the traces carrying their user.id with the spans, scores, logs and
+// GenAI events of those traces, comments on them, dataset items sourced from them
+// and their archived Parquet records. Requests are queued here and processed by
+// the worker; each stage is persisted as it completes so progress can be polled.
+type DataSubjectService struct {
+	requestRepo   observability.DataSubjectRequestRepository
+	telemetryRepo observability.DataSubjectTelemetryRepository
+	recordRepo    observability.DataSubjectRecordRepository
+	traceRepo     observability.TraceRepository
+	scoreRepo     observability.ScoreRepository
+	archive       dataSubjectArchive // nil without archive storage
+	parquetWriter *ParquetWriter
+	archivePrefix string
+	auditLogs     authDomain.AuditLogService // Set by the server provider; nil in workers
+	logger        *slog.Logger
+	now           func() time.Time
+}
+
+// NewDataSubjectService creates a new data subject service. Archived files are
+// covered whenever S3 is configured, even if archival itself is disabled.
+func NewDataSubjectService(
+	requestRepo observability.DataSubjectRequestRepository,
+	telemetryRepo observability.DataSubjectTelemetryRepository,
+	recordRepo observability.DataSubjectRecordRepository,
+	traceRepo observability.TraceRepository,
+	scoreRepo observability.ScoreRepository,
+	s3Client *infraStorage.S3Client,
+	archiveConfig *config.ArchiveConfig,
+	logger *slog.Logger,
+) *DataSubjectService {
+	service := &DataSubjectService{
+		requestRepo:   requestRepo,
+		telemetryRepo: telemetryRepo,
+		recordRepo:    recordRepo,
+		traceRepo:     traceRepo,
+		scoreRepo:     scoreRepo,
+		logger:        logger,
+		now:           time.Now,
+	}
+	if s3Client != nil && archiveConfig != nil {
+		service.archive = s3Client
+		service.parquetWriter = NewParquetWriter(archiveConfig.CompressionLevel)
+		service.archivePrefix = archiveConfig.PathPrefix
+	}
+	return service
+}
+
+// SetAuditLogService enables audit logging of new requests
+func (s *DataSubjectService) SetAuditLogService(auditLogs authDomain.AuditLogService) {
+	s.auditLogs = auditLogs
+}
+
+// CreateRequest queues an erasure or export of the end user's data.
+func (s *DataSubjectService) CreateRequest(
+	ctx context.Context,
+	projectID ulid.ULID,
+	requestedBy *ulid.ULID,
+	reqType observability.DataSubjectRequestType,
+	req *observability.CreateDataSubjectRequest,
+) (*observability.DataSubjectRequestResponse, error) {
+	if !reqType.IsValid() {
+		return nil, appErrors.NewValidationError("Invalid type", "type must be erasure or export")
+	}
+	userID := strings.TrimSpace(req.UserID)
+	if userID == "" {
+		return nil, appErrors.NewValidationError("user_id is required", "user_id cannot be empty")
+	}
+	if len(userID) > observability.DataSubjectMaxUserIDLength {
+		return nil, appErrors.NewValidationError("Invalid user_id", fmt.Sprintf("user_id must be at most %d characters", observability.DataSubjectMaxUserIDLength))
+	}
+
+	request := &observability.DataSubjectRequest{
+		ID:          ulid.New(),
+		ProjectID:   projectID,
+		UserID:      userID,
+		Type:        reqType,
+		Status:      observability.DataSubjectStatusPending,
+		Progress:    observability.DataSubjectProgress{CompletedStages: []observability.DataSubjectStage{}},
+		RequestedBy: requestedBy,
+	}
+	if err := s.requestRepo.Create(ctx, request); err != nil {
+		return nil, appErrors.NewInternalError("failed to create data subject request", err)
+	}
+
+	if s.auditLogs != nil {
+		s.auditLogs.Record(ctx, &authDomain.AuditEvent{
+			Action:     "data_subject." + string(reqType) + "_requested",
+			Resource:   "data_subject_request",
+			ResourceID: request.ID.String(),
+			ProjectID:  &projectID,
+			UserID:     requestedBy,
+			After:      request,
+		})
+	}
+
+	s.logger.Info("data subject request created",
+		"request_id", request.ID,
+		"project_id", projectID,
+		"type", reqType,
+	)
+
+	return request.ToResponse(), nil
+}
+
+// GetRequest returns a request with its progress and, once completed, its certificate.
+func (s *DataSubjectService) GetRequest(ctx context.Context, projectID, requestID ulid.ULID) (*observability.DataSubjectRequestResponse, error) {
+	request, err := s.requestRepo.GetByID(ctx, requestID, projectID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErrors.NewNotFoundError("data subject request")
+		}
+		return nil, appErrors.NewInternalError("failed to get data subject request", err)
+	}
+	return request.ToResponse(), nil
+}
+
+// ListRequests lists a project's requests, newest first.
+func (s *DataSubjectService) ListRequests(ctx context.Context, projectID ulid.ULID, limit, offset int) ([]*observability.DataSubjectRequestResponse, int64, error) {
+	requests, total, err := s.requestRepo.ListByProject(ctx, projectID, limit, offset)
+	if err != nil {
+		return nil, 0, appErrors.NewInternalError("failed to list data subject requests", err)
+	}
+
+	responses := make([]*observability.DataSubjectRequestResponse, len(requests))
+	for i, request := range requests {
+		responses[i] = request.ToResponse()
+	}
+	return responses, total, nil
+}
+
+// GetExportBundle returns the JSON bundle of a completed export.
+func (s *DataSubjectService) GetExportBundle(ctx context.Context, projectID, requestID ulid.ULID) ([]byte, error) {
+	request, err := s.GetRequest(ctx, projectID, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if request.Type != observability.DataSubjectRequestExport {
+		return nil, appErrors.NewValidationError("Not an export request", "only export requests have a bundle")
+	}
+	if request.Status != observability.DataSubjectStatusCompleted {
+		return nil, appErrors.NewConflictError("export is " + string(request.Status))
+	}
+
+	compressed, err := s.requestRepo.GetBundle(ctx, requestID, projectID)
+	if err != nil {
+		return nil, appErrors.NewInternalError("failed to get export bundle", err)
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, appErrors.NewInternalError("failed to read export bundle", err)
+	}
+	defer reader.Close()
+
+	bundle, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, appErrors.NewInternalError("failed to read export bundle", err)
+	}
+	return bundle, nil
+}
+
+// ProcessNext claims and processes the next queued request. It returns false
+// when there was nothing to do.
+func (s *DataSubjectService) ProcessNext(ctx context.Context) (bool, error) {
+	request, err := s.requestRepo.ClaimNext(ctx, s.now(), observability.DataSubjectLease)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, s.process(ctx, request)
+}
+
+// dataSubjectRun carries the state of one processing attempt.
+type dataSubjectRun struct {
+	request  *observability.DataSubjectRequest
+	traceSet map[string]struct{}
+	export   *observability.DataSubjectExport // nil for erasures
+}
+
+func (s *DataSubjectService) process(ctx context.Context, request *observability.DataSubjectRequest) error {
+	run := &dataSubjectRun{request: request}
+	if request.Type == observability.DataSubjectRequestExport {
+		// Collected records only live in memory, so an export restarts from scratch
+		request.Progress = observability.DataSubjectProgress{CompletedStages: []observability.DataSubjectStage{}}
+		request.TraceIDs = nil
+		run.export = &observability.DataSubjectExport{
+			RequestID:       request.ID,
+			ProjectID:       request.ProjectID,
+			UserID:          request.UserID,
+			Spans:           []*observability.Span{},
+			Scores:          []*observability.Score{},
+			Logs:            []*observability.Log{},
+			ArchivedRecords: []*observability.DataSubjectArchivedRecord{},
+		}
+	}
+	run.setTraceIDs(request.TraceIDs)
+
+	for _, stage := range observability.DataSubjectStages() {
+		if request.Progress.Completed(stage) {
+			continue
+		}
+		request.Progress.CurrentStage = stage
+
+		if err := s.runStage(ctx, run, stage); err != nil {
+			return s.fail(ctx, request, stage, err)
+		}
+
+		request.Progress.CompletedStages = append(request.Progress.CompletedStages, stage)
+		leaseUntil := s.now().Add(observability.DataSubjectLease)
+		request.LeaseExpiresAt = &leaseUntil
+		if err := s.requestRepo.Update(ctx, request); err != nil {
+			return fmt.Errorf("save data subject request progress: %w", err)
+		}
+	}
+
+	return s.complete(ctx, run)
+}
+
+func (s *DataSubjectService) runStage(ctx context.Context, run *dataSubjectRun, stage observability.DataSubjectStage) error {
+	request := run.request
+	counts := &request.Progress.Counts
+	projectID := request.ProjectID.String()
+
+	switch stage {
+	case observability.DataSubjectStageDiscover:
+		return s.discover(ctx, run)
+
+	case observability.DataSubjectStageComments:
+		if run.export != nil {
+			comments, err := s.recordRepo.ListComments(ctx, request.ProjectID, request.TraceIDs)
+			if err != nil {
+				return err
+			}
+			run.export.Comments = comments
+			counts.Comments = int64(len(comments))
+			return nil
+		}
+		deleted, err := s.recordRepo.DeleteComments(ctx, request.ProjectID, request.TraceIDs)
+		counts.Comments = deleted
+		return err
+
+	case observability.DataSubjectStageDatasetItems:
+		if run.export != nil {
+			items, err := s.recordRepo.ListDatasetItems(ctx, request.ProjectID, request.TraceIDs)
+			if err != nil {
+				return err
+			}
+			run.export.DatasetItems = items
+			counts.DatasetItems = int64(len(items))
+			return nil
+		}
+		deleted, err := s.recordRepo.DeleteDatasetItems(ctx, request.ProjectID, request.TraceIDs)
+		counts.DatasetItems = deleted
+		return err
+
+	case observability.DataSubjectStageArchive:
+		if s.archive == nil || len(run.traceSet) == 0 {
+			return nil
+		}
+		if run.export != nil {
+			return s.collectArchive(ctx, run)
+		}
+		return s.eraseArchive(ctx, run)
+
+	case observability.DataSubjectStageTelemetry:
+		if run.export != nil {
+			return s.collectTelemetry(ctx, run)
+		}
+		found, err := s.telemetryRepo.Count(ctx, projectID, request.UserID, request.TraceIDs)
+		if err != nil {
+			return err
+		}
+		// A retry after a partial delete finds fewer rows than the first attempt counted
+		counts.Spans = max(counts.Spans, found.Spans)
+		counts.Scores = max(counts.Scores, found.Scores)
+		counts.Logs = max(counts.Logs, found.Logs)
+		counts.GenAIEvents = max(counts.GenAIEvents, found.GenAIEvents)
+		// Persist the counts first: once the rows are gone they cannot be recounted
+		if err := s.requestRepo.Update(ctx, request); err != nil {
+			return err
+		}
+		return s.telemetryRepo.Delete(ctx, projectID, request.UserID, request.TraceIDs)
+	}
+
+	return fmt.Errorf("unknown stage %s", stage)
+}
+
+// discover finds the user's traces in ClickHouse and, for traces whose hot copy
+// already expired, in archived spans.
+func (s *DataSubjectService) discover(ctx context.Context, run *dataSubjectRun) error {
+	request := run.request
+
+	traceIDs, err := s.telemetryRepo.ListTraceIDs(ctx, request.ProjectID.String(), request.UserID)
+	if err != nil {
+		return err
+	}
+	run.addTraceIDs(traceIDs)
+
+	if s.archive != nil {
+		err := s.eachArchiveFile(ctx, request.ProjectID.String(), []string{observability.SignalTypeTraces}, func(key string, records []observability.RawTelemetryRecord) error {
+			for i := range records {
+				if archivedSpanUserID(&records[i]) == request.UserID {
+					run.addTraceIDs([]string{records[i].TraceID})
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	all := make([]string, 0, len(run.traceSet))
+	for traceID := range run.traceSet {
+		all = append(all, traceID)
+	}
+	sort.Strings(all)
+
+	if run.export != nil && len(all) > observability.DataSubjectMaxExportTraces {
+		all = all[:observability.DataSubjectMaxExportTraces]
+		run.export.Truncated = true
+		run.setTraceIDs(all)
+	}
+
+	request.TraceIDs = all
+	request.Progress.Counts.Traces = len(all)
+	return nil
+}
+
+// eraseArchive rewrites every archived file holding records of the traces without
+// them, deleting files left empty.
+func (s *DataSubjectService) eraseArchive(ctx context.Context, run *dataSubjectRun) error {
+	request := run.request
+	counts := &request.Progress.Counts
+
+	return s.eachArchiveFile(ctx, request.ProjectID.String(), dataSubjectArchiveSignals, func(key string, records []observability.RawTelemetryRecord) error {
+		request.Progress.ArchiveFilesScanned++
+
+		kept := make([]observability.RawTelemetryRecord, 0, len(records))
+		for _, record := range records {
+			if !run.matches(&record) {
+				kept = append(kept, record)
+			}
+		}
+		removed := len(records) - len(kept)
+		if removed == 0 {
+			return nil
+		}
+
+		if len(kept) == 0 {
+			if err := s.archive.Delete(ctx, key); err != nil {
+				return fmt.Errorf("delete archive file %s: %w", key, err)
+			}
+		} else {
+			data, err := s.parquetWriter.WriteRecords(kept)
+			if err != nil {
+				return fmt.Errorf("rewrite archive file %s: %w", key, err)
+			}
+			if err := s.archive.Upload(ctx, key, data, "application/x-parquet"); err != nil {
+				return fmt.Errorf("upload archive file %s: %w", key, err)
+			}
+		}
+
+		counts.ArchiveFiles++
+		counts.ArchiveRecords += removed
+		return nil
+	})
+}
+
+func (s *DataSubjectService) collectArchive(ctx context.Context, run *dataSubjectRun) error {
+	request := run.request
+	counts := &request.Progress.Counts
+
+	return s.eachArchiveFile(ctx, request.ProjectID.String(), dataSubjectArchiveSignals, func(key string, records []observability.RawTelemetryRecord) error {
+		request.Progress.ArchiveFilesScanned++
+
+		matched := 0
+		for i := range records {
+			record := &records[i]
+			if !run.matches(record) {
+				continue
+			}
+			run.export.ArchivedRecords = append(run.export.ArchivedRecords, &observability.DataSubjectArchivedRecord{
+				SignalType: record.SignalType,
+				EventType:  string(archivedEventType(record)),
+				Timestamp:  record.Timestamp,
+				TraceID:    record.TraceID,
+				SpanID:     record.SpanID,
+				Payload:    record.SpanJSONRaw,
+			})
+			matched++
+		}
+		if matched > 0 {
+			counts.ArchiveFiles++
+			counts.ArchiveRecords += matched
+		}
+		return nil
+	})
+}
+
+// collectTelemetry reads the traces' spans, scores and logs from ClickHouse.
+func (s *DataSubjectService) collectTelemetry(ctx context.Context, run *dataSubjectRun) error {
+	request := run.request
+	counts := &request.Progress.Counts
+	projectID := request.ProjectID.String()
+
+	for _, traceID := range request.TraceIDs {
+		spans, err := s.traceRepo.GetSpansByTraceID(ctx, traceID)
+		if err != nil {
+			return err
+		}
+		for _, span := range spans {
+			if span.ProjectID == projectID {
+				run.export.Spans = append(run.export.Spans, span)
+			}
+		}
+
+		scores, err := s.scoreRepo.GetByTraceID(ctx, traceID)
+		if err != nil {
+			return err
+		}
+		for _, score := range scores {
+			if score.ProjectID == projectID {
+				run.export.Scores = append(run.export.Scores, score)
+			}
+		}
+	}
+
+	logs, err := s.telemetryRepo.ListLogs(ctx, projectID, request.TraceIDs)
+	if err != nil {
+		return err
+	}
+	run.export.Logs = logs
+
+	counts.Spans = uint64(len(run.export.Spans))
+	counts.Scores = uint64(len(run.export.Scores))
+	counts.Logs = uint64(len(run.export.Logs))
+	return nil
+}
+
+// eachArchiveFile calls fn with the records of every archived Parquet file of the
+// project's signals. Unreadable files fail the stage: skipping them could leave
+// the user's data behind.
+func (s *DataSubjectService) eachArchiveFile(ctx context.Context, projectID string, signals []string, fn func(key string, records []observability.RawTelemetryRecord) error) error {
+	for _, signal := range signals {
+		keys, err := s.archive.ListKeys(ctx, ArchiveSignalPrefix(s.archivePrefix, projectID, signal))
+		if err != nil {
+			return fmt.Errorf("list archive files: %w", err)
+		}
+
+		for _, key := range keys {
+			if !strings.HasSuffix(key, ".parquet") {
+				continue
+			}
+			data, err := s.archive.Download(ctx, key)
+			if err != nil {
+				return fmt.Errorf("download archive file %s: %w", key, err)
+			}
+			records, err := s.parquetWriter.ReadRecords(data)
+			if err != nil {
+				return fmt.Errorf("decode archive file %s: %w", key, err)
+			}
+			if err := fn(key, records); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// complete issues the certificate and, for exports, stores the bundle.
+func (s *DataSubjectService) complete(ctx context.Context, run *dataSubjectRun) error {
+	request := run.request
+	completedAt := s.now().UTC()
+
+	certificate := &observability.DataSubjectCertificate{
+		RequestID:      request.ID,
+		ProjectID:      request.ProjectID,
+		UserID:         request.UserID,
+		Type:           request.Type,
+		RequestedAt:    request.CreatedAt.UTC(),
+		CompletedAt:    completedAt,
+		Counts:         request.Progress.Counts,
+		ArchiveScanned: s.archive != nil,
+	}
+
+	if run.export != nil {
+		run.export.GeneratedAt = completedAt
+		run.export.TraceIDs = request.TraceIDs
+		bundle, err := gzipJSON(run.export)
+		if err != nil {
+			return s.fail(ctx, request, observability.DataSubjectStageTelemetry, err)
+		}
+		request.Bundle = bundle
+		certificate.Truncated = run.export.Truncated
+	}
+	certificate.Digest = certificate.ComputeDigest()
+
+	request.Status = observability.DataSubjectStatusCompleted
+	request.Progress.CurrentStage = ""
+	request.Certificate = certificate
+	request.CompletedAt = &completedAt
+	request.LeaseExpiresAt = nil
+	request.Error = nil
+	// The trace IDs were only kept to resume an interrupted erasure
+	request.TraceIDs = nil
+
+	if err := s.requestRepo.Update(ctx, request); err != nil {
+		return fmt.Errorf("complete data subject request: %w", err)
+	}
+
+	s.logger.Info("data subject request completed",
+		"request_id", request.ID,
+		"project_id", request.ProjectID,
+		"type", request.Type,
+		"traces", request.Progress.Counts.Traces,
+		"spans", request.Progress.Counts.Spans,
+		"archive_records", request.Progress.Counts.ArchiveRecords,
+	)
+	return nil
+}
+
+// fail records the error; the request is retried after a delay until it runs out of attempts.
+func (s *DataSubjectService) fail(ctx context.Context, request *observability.DataSubjectRequest, stage observability.DataSubjectStage, cause error) error {
+	message := fmt.Sprintf("%s: %v", stage, cause)
+	request.Error = &message
+
+	if request.Attempts >= observability.DataSubjectMaxAttempts {
+		request.Status = observability.DataSubjectStatusFailed
+		request.LeaseExpiresAt = nil
+	} else {
+		retryAt := s.now().Add(observability.DataSubjectRetryDelay)
+		request.LeaseExpiresAt = &retryAt
+	}
+
+	s.logger.Error("data subject request failed",
+		"error", cause,
+		"request_id", request.ID,
+		"project_id", request.ProjectID,
+		"stage", stage,
+		"attempt", request.Attempts,
+		"status", request.Status,
+	)
+
+	if err := s.requestRepo.Update(ctx, request); err != nil {
+		return fmt.Errorf("save data subject request failure: %w", err)
+	}
+	return cause
+}
+
+func (r *dataSubjectRun) setTraceIDs(traceIDs []string) {
+	r.traceSet = make(map[string]struct{}, len(traceIDs))
+	r.addTraceIDs(traceIDs)
+}
+
+func (r *dataSubjectRun) addTraceIDs(traceIDs []string) {
+	for _, traceID := range traceIDs {
+		if traceID != "" {
+			r.traceSet[traceID] = struct{}{}
+		}
+	}
+}
+
+// matches returns true if an archived record belongs to one of the traces.
+func (r *dataSubjectRun) matches(record *observability.RawTelemetryRecord) bool {
+	_, ok := r.traceSet[record.TraceID]
+	return ok
+}
+
+// archivedSpanUserID returns the user.id attribute of an archived span, or "" for other records.
+func archivedSpanUserID(record *observability.RawTelemetryRecord) string {
+	if archivedEventType(record) != observability.TelemetryEventTypeSpan {
+		return ""
+	}
+	var span struct {
+		SpanAttributes map[string]string `json:"span_attributes"`
+	}
+	if err := json.Unmarshal([]byte(record.SpanJSONRaw), &span); err != nil {
+		return ""
+	}
+	return span.SpanAttributes["user.id"]
+}
+
+func gzipJSON(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(writer).Encode(v); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
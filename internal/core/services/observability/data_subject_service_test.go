@@ -0,0 +1,419 @@
+package observability
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"brokle/internal/core/domain/comment"
+	"brokle/internal/core/domain/evaluation"
+	obsDomain "brokle/internal/core/domain/observability"
+	appErrors "brokle/pkg/errors"
+	"brokle/pkg/ulid"
+)
+
+// fakeDataSubjectRequestRepository holds one project's requests in memory
+type fakeDataSubjectRequestRepository struct {
+	obsDomain.DataSubjectRequestRepository
+	requests []*obsDomain.DataSubjectRequest
+	updates  int
+}
+
+func (r *fakeDataSubjectRequestRepository) Create(ctx context.Context, req *obsDomain.DataSubjectRequest) error {
+	req.CreatedAt = time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC)
+	r.requests = append(r.requests, req)
+	return nil
+}
+
+func (r *fakeDataSubjectRequestRepository) GetByID(ctx context.Context, id, projectID ulid.ULID) (*obsDomain.DataSubjectRequest, error) {
+	for _, req := range r.requests {
+		if req.ID == id && req.ProjectID == projectID {
+			return req, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeDataSubjectRequestRepository) GetBundle(ctx context.Context, id, projectID ulid.ULID) ([]byte, error) {
+	req, err := r.GetByID(ctx, id, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return req.Bundle, nil
+}
+
+func (r *fakeDataSubjectRequestRepository) Update(ctx context.Context, req *obsDomain.DataSubjectRequest) error {
+	r.updates++
+	return nil
+}
+
+func (r *fakeDataSubjectRequestRepository) ClaimNext(ctx context.Context, now time.Time, lease time.Duration) (*obsDomain.DataSubjectRequest, error) {
+	for _, req := range r.requests {
+		expired := req.Status == obsDomain.DataSubjectStatusRunning && req.LeaseExpiresAt != nil && req.LeaseExpiresAt.Before(now)
+		if req.Status == obsDomain.DataSubjectStatusPending || expired {
+			leaseUntil := now.Add(lease)
+			req.Status = obsDomain.DataSubjectStatusRunning
+			req.LeaseExpiresAt = &leaseUntil
+			req.Attempts++
+			return req, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+type fakeDataSubjectTelemetryRepository struct {
+	traceIDs  []string
+	logs      []*obsDomain.Log
+	counts    obsDomain.DataSubjectCounts
+	deleteErr error
+	deleted   []string
+}
+
+func (r *fakeDataSubjectTelemetryRepository) ListTraceIDs(ctx context.Context, projectID, userID string) ([]string, error) {
+	return r.traceIDs, nil
+}
+
+func (r *fakeDataSubjectTelemetryRepository) ListLogs(ctx context.Context, projectID string, traceIDs []string) ([]*obsDomain.Log, error) {
+	return r.logs, nil
+}
+
+func (r *fakeDataSubjectTelemetryRepository) Count(ctx context.Context, projectID, userID string, traceIDs []string) (*obsDomain.DataSubjectCounts, error) {
+	counts := r.counts
+	return &counts, nil
+}
+
+func (r *fakeDataSubjectTelemetryRepository) Delete(ctx context.Context, projectID, userID string, traceIDs []string) error {
+	if r.deleteErr != nil {
+		return r.deleteErr
+	}
+	r.deleted = append([]string(nil), traceIDs...)
+	return nil
+}
+
+type fakeDataSubjectRecordRepository struct {
+	comments       []*comment.Comment
+	commentDeletes int
+	commentTraces  []string
+}
+
+func (r *fakeDataSubjectRecordRepository) ListComments(ctx context.Context, projectID ulid.ULID, traceIDs []string) ([]*comment.Comment, error) {
+	return r.comments, nil
+}
+
+func (r *fakeDataSubjectRecordRepository) DeleteComments(ctx context.Context, projectID ulid.ULID, traceIDs []string) (int64, error) {
+	r.commentDeletes++
+	r.commentTraces = append([]string(nil), traceIDs...)
+	return int64(len(r.comments)), nil
+}
+
+func (r *fakeDataSubjectRecordRepository) ListDatasetItems(ctx context.Context, projectID ulid.ULID, traceIDs []string) ([]*evaluation.DatasetItem, error) {
+	return nil, nil
+}
+
+func (r *fakeDataSubjectRecordRepository) DeleteDatasetItems(ctx context.Context, projectID ulid.ULID, traceIDs []string) (int64, error) {
+	return 1, nil
+}
+
+// fakeArchive is an in-memory object store
+type fakeArchive struct {
+	objects map[string][]byte
+}
+
+func (a *fakeArchive) ListKeys(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	for key := range a.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (a *fakeArchive) Download(ctx context.Context, key string) ([]byte, error) {
+	return a.objects[key], nil
+}
+
+func (a *fakeArchive) Upload(ctx context.Context, key string, content []byte, contentType string) error {
+	a.objects[key] = content
+	return nil
+}
+
+func (a *fakeArchive) Delete(ctx context.Context, key string) error {
+	delete(a.objects, key)
+	return nil
+}
+
+type dataSubjectTraceRepository struct {
+	obsDomain.TraceRepository
+	spans map[string][]*obsDomain.Span
+}
+
+func (r *dataSubjectTraceRepository) GetSpansByTraceID(ctx context.Context, traceID string) ([]*obsDomain.Span, error) {
+	return r.spans[traceID], nil
+}
+
+type dataSubjectScoreRepository struct {
+	obsDomain.ScoreRepository
+}
+
+func (r *dataSubjectScoreRepository) GetByTraceID(ctx context.Context, traceID string) ([]*obsDomain.Score, error) {
+	return nil, nil
+}
+
+type dataSubjectFixture struct {
+	service   *DataSubjectService
+	requests  *fakeDataSubjectRequestRepository
+	telemetry *fakeDataSubjectTelemetryRepository
+	records   *fakeDataSubjectRecordRepository
+	archive   *fakeArchive
+	projectID ulid.ULID
+	now       time.Time
+}
+
+func newDataSubjectFixture(t *testing.T) *dataSubjectFixture {
+	f := &dataSubjectFixture{
+		requests:  &fakeDataSubjectRequestRepository{},
+		telemetry: &fakeDataSubjectTelemetryRepository{},
+		records:   &fakeDataSubjectRecordRepository{},
+		archive:   &fakeArchive{objects: map[string][]byte{}},
+		projectID: ulid.New(),
+		now:       time.Date(2026, 4, 2, 10, 0, 0, 0, time.UTC),
+	}
+	f.service = NewDataSubjectService(
+		f.requests, f.telemetry, f.records,
+		&dataSubjectTraceRepository{}, &dataSubjectScoreRepository{},
+		nil, nil,
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+	f.service.archive = f.archive
+	f.service.parquetWriter = NewParquetWriter(3)
+	f.service.now = func() time.Time { return f.now }
+	return f
+}
+
+// archiveSpans writes an archived traces file holding one span per trace ID, attributed to users.
+func (f *dataSubjectFixture) archiveSpans(t *testing.T, key string, spans map[string]string) {
+	records := make([]obsDomain.RawTelemetryRecord, 0, len(spans))
+	for traceID, userID := range spans {
+		raw, err := json.Marshal(&obsDomain.Span{TraceID: traceID, SpanID: "span-" + traceID, SpanAttributes: map[string]string{"user.id": userID}})
+		require.NoError(t, err)
+		records = append(records, obsDomain.RawTelemetryRecord{
+			RecordID:    traceID,
+			ProjectID:   f.projectID.String(),
+			SignalType:  obsDomain.SignalTypeTraces,
+			TraceID:     traceID,
+			SpanID:      "span-" + traceID,
+			SpanJSONRaw: string(raw),
+			EventType:   string(obsDomain.TelemetryEventTypeSpan),
+		})
+	}
+	data, err := f.service.parquetWriter.WriteRecords(records)
+	require.NoError(t, err)
+	f.archive.objects[ArchiveDayPrefix("", f.projectID.String(), obsDomain.SignalTypeTraces, f.now)+key] = data
+}
+
+func (f *dataSubjectFixture) create(t *testing.T, reqType obsDomain.DataSubjectRequestType) *obsDomain.DataSubjectRequest {
+	resp, err := f.service.CreateRequest(context.Background(), f.projectID, nil, reqType, &obsDomain.CreateDataSubjectRequest{UserID: " customer-42 "})
+	require.NoError(t, err)
+	return resp.DataSubjectRequest
+}
+
+func TestDataSubjectService_CreateRequest_Validation(t *testing.T) {
+	f := newDataSubjectFixture(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		reqType obsDomain.DataSubjectRequestType
+		userID  string
+	}{
+		{"unknown type", "rectification", "customer-42"},
+		{"blank user", obsDomain.DataSubjectRequestErasure, "   "},
+		{"user too long", obsDomain.DataSubjectRequestErasure, strings.Repeat("u", obsDomain.DataSubjectMaxUserIDLength+1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.service.CreateRequest(ctx, f.projectID, nil, tt.reqType, &obsDomain.CreateDataSubjectRequest{UserID: tt.userID})
+			appErr, ok := appErrors.IsAppError(err)
+			require.True(t, ok)
+			assert.Equal(t, appErrors.ValidationError, appErr.Type)
+		})
+	}
+	assert.Empty(t, f.requests.requests)
+
+	request := f.create(t, obsDomain.DataSubjectRequestErasure)
+	assert.Equal(t, "customer-42", request.UserID)
+	assert.Equal(t, obsDomain.DataSubjectStatusPending, request.Status)
+	assert.Equal(t, request.CreatedAt.AddDate(0, 0, 30), request.DueBy())
+}
+
+func TestDataSubjectService_Erasure(t *testing.T) {
+	f := newDataSubjectFixture(t)
+	ctx := context.Background()
+
+	f.telemetry.traceIDs = []string{"trace-hot"}
+	f.telemetry.counts = obsDomain.DataSubjectCounts{Spans: 4, Scores: 2, Logs: 1, GenAIEvents: 3}
+	f.records.comments = []*comment.Comment{{}, {}}
+	// A file shared with another user is rewritten; one holding only the user's expired trace is deleted
+	f.archiveSpans(t, "shared.parquet", map[string]string{"trace-hot": "customer-42", "trace-other": "customer-7"})
+	f.archiveSpans(t, "expired.parquet", map[string]string{"trace-cold": "customer-42"})
+
+	request := f.create(t, obsDomain.DataSubjectRequestErasure)
+
+	processed, err := f.service.ProcessNext(ctx)
+	require.NoError(t, err)
+	require.True(t, processed)
+
+	assert.Equal(t, obsDomain.DataSubjectStatusCompleted, request.Status)
+	assert.Equal(t, []string{"trace-cold", "trace-hot"}, f.records.commentTraces)
+	assert.Equal(t, []string{"trace-cold", "trace-hot"}, f.telemetry.deleted)
+	assert.Nil(t, request.TraceIDs)
+	assert.Nil(t, request.LeaseExpiresAt)
+	assert.Equal(t, float64(100), request.Progress.Percent())
+
+	keys, err := f.archive.ListKeys(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.True(t, strings.HasSuffix(keys[0], "shared.parquet"))
+	kept, err := f.service.parquetWriter.ReadRecords(f.archive.objects[keys[0]])
+	require.NoError(t, err)
+	require.Len(t, kept, 1)
+	assert.Equal(t, "trace-other", kept[0].TraceID)
+
+	certificate := request.Certificate
+	require.NotNil(t, certificate)
+	assert.Equal(t, obsDomain.DataSubjectCounts{
+		Traces: 2, Spans: 4, Scores: 2, Logs: 1, GenAIEvents: 3,
+		Comments: 2, DatasetItems: 1, ArchiveFiles: 2, ArchiveRecords: 2,
+	}, certificate.Counts)
+	assert.True(t, certificate.ArchiveScanned)
+	assert.Equal(t, f.now, certificate.CompletedAt)
+	assert.Equal(t, certificate.ComputeDigest(), certificate.Digest)
+	assert.Len(t, certificate.Digest, 64)
+
+	processed, err = f.service.ProcessNext(ctx)
+	require.NoError(t, err)
+	assert.False(t, processed)
+}
+
+func TestDataSubjectService_ErasureRetry(t *testing.T) {
+	f := newDataSubjectFixture(t)
+	ctx := context.Background()
+
+	f.telemetry.traceIDs = []string{"trace-hot"}
+	f.telemetry.counts = obsDomain.DataSubjectCounts{Spans: 4}
+	f.telemetry.deleteErr = errors.New("clickhouse unavailable")
+	request := f.create(t, obsDomain.DataSubjectRequestErasure)
+
+	_, err := f.service.ProcessNext(ctx)
+	require.Error(t, err)
+	assert.Equal(t, obsDomain.DataSubjectStatusRunning, request.Status)
+	require.NotNil(t, request.Error)
+	assert.Equal(t, "telemetry: clickhouse unavailable", *request.Error)
+	assert.Equal(t, f.now.Add(obsDomain.DataSubjectRetryDelay), *request.LeaseExpiresAt)
+	assert.Equal(t, []string{"trace-hot"}, []string(request.TraceIDs))
+
+	// Not claimable until the retry delay passes
+	processed, err := f.service.ProcessNext(ctx)
+	require.NoError(t, err)
+	assert.False(t, processed)
+
+	// The retry resumes at the failed stage and keeps the counts taken before the partial delete
+	f.now = f.now.Add(obsDomain.DataSubjectRetryDelay + time.Second)
+	f.telemetry.deleteErr = nil
+	f.telemetry.counts = obsDomain.DataSubjectCounts{Spans: 1}
+	processed, err = f.service.ProcessNext(ctx)
+	require.NoError(t, err)
+	require.True(t, processed)
+
+	assert.Equal(t, obsDomain.DataSubjectStatusCompleted, request.Status)
+	assert.Nil(t, request.Error)
+	assert.Equal(t, 1, f.records.commentDeletes)
+	assert.Equal(t, uint64(4), request.Certificate.Counts.Spans)
+	assert.Equal(t, 2, request.Attempts)
+}
+
+func TestDataSubjectService_ErasureFailsAfterMaxAttempts(t *testing.T) {
+	f := newDataSubjectFixture(t)
+	ctx := context.Background()
+
+	f.telemetry.deleteErr = errors.New("clickhouse unavailable")
+	request := f.create(t, obsDomain.DataSubjectRequestErasure)
+
+	for attempt := 1; attempt <= obsDomain.DataSubjectMaxAttempts; attempt++ {
+		processed, err := f.service.ProcessNext(ctx)
+		require.Error(t, err)
+		require.True(t, processed)
+		f.now = f.now.Add(obsDomain.DataSubjectRetryDelay + time.Second)
+	}
+
+	assert.Equal(t, obsDomain.DataSubjectStatusFailed, request.Status)
+	assert.Nil(t, request.LeaseExpiresAt)
+
+	processed, err := f.service.ProcessNext(ctx)
+	require.NoError(t, err)
+	assert.False(t, processed)
+}
+
+func TestDataSubjectService_Export(t *testing.T) {
+	f := newDataSubjectFixture(t)
+	ctx := context.Background()
+
+	otherProject := ulid.New().String()
+	f.service.traceRepo = &dataSubjectTraceRepository{spans: map[string][]*obsDomain.Span{
+		"trace-hot": {
+			{TraceID: "trace-hot", SpanID: "s1", ProjectID: f.projectID.String()},
+			{TraceID: "trace-hot", SpanID: "s2", ProjectID: otherProject},
+		},
+	}}
+	f.telemetry.traceIDs = []string{"trace-hot"}
+	f.telemetry.logs = []*obsDomain.Log{{TraceID: "trace-hot", Body: "user logged in"}}
+	f.archiveSpans(t, "expired.parquet", map[string]string{"trace-cold": "customer-42", "trace-other": "customer-7"})
+
+	request := f.create(t, obsDomain.DataSubjectRequestExport)
+
+	_, err := f.service.GetExportBundle(ctx, f.projectID, request.ID)
+	appErr, ok := appErrors.IsAppError(err)
+	require.True(t, ok)
+	assert.Equal(t, appErrors.ConflictError, appErr.Type)
+
+	processed, err := f.service.ProcessNext(ctx)
+	require.NoError(t, err)
+	require.True(t, processed)
+	assert.Equal(t, obsDomain.DataSubjectStatusCompleted, request.Status)
+	assert.Len(t, f.archive.objects, 1, "exports leave the archive untouched")
+
+	raw, err := f.service.GetExportBundle(ctx, f.projectID, request.ID)
+	require.NoError(t, err)
+
+	var bundle obsDomain.DataSubjectExport
+	require.NoError(t, json.NewDecoder(bytes.NewReader(raw)).Decode(&bundle))
+	assert.Equal(t, "customer-42", bundle.UserID)
+	assert.Equal(t, []string{"trace-cold", "trace-hot"}, bundle.TraceIDs)
+	require.Len(t, bundle.Spans, 1)
+	assert.Equal(t, "s1", bundle.Spans[0].SpanID)
+	require.Len(t, bundle.Logs, 1)
+	require.Len(t, bundle.ArchivedRecords, 1)
+	assert.Equal(t, "trace-cold", bundle.ArchivedRecords[0].TraceID)
+	assert.Equal(t, string(obsDomain.TelemetryEventTypeSpan), bundle.ArchivedRecords[0].EventType)
+
+	assert.Equal(t, obsDomain.DataSubjectCounts{Traces: 2, Spans: 1, Logs: 1, ArchiveFiles: 1, ArchiveRecords: 1}, request.Certificate.Counts)
+
+	erasure := f.create(t, obsDomain.DataSubjectRequestErasure)
+	_, err = f.service.GetExportBundle(ctx, f.projectID, erasure.ID)
+	appErr, ok = appErrors.IsAppError(err)
+	require.True(t, ok)
+	assert.Equal(t, appErrors.ValidationError, appErr.Type)
+}
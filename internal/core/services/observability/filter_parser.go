@@ -13,7 +13,10 @@ import (
 //   expression  = or_expr
 //   or_expr     = and_expr ("OR" and_expr)*
 //   and_expr    = primary ("AND" primary)*
-//   primary     = "(" expression ")" | condition
+//   primary     = "(" expression ")" | trace_pred | condition
+//   trace_pred  = ("HAS" "(" expression ")")
+//                | ("COUNT" "(" expression ")" comparison_op integer)
+//                | (("DESCENDANT" | "CHILD" | "SIBLING") "(" expression "," expression ")")
 //   condition   = field operator value | field existence_op | field empty_op
 //   field       = identifier ("." identifier)*
 //   operator    = "=" | "!=" | ">" | "<" | ">=" | "<=" | "CONTAINS" | "NOT CONTAINS"
@@ -116,7 +119,7 @@ func (p *FilterParser) parseAndExpr() (obsDomain.FilterNode, error) {
 	return left, nil
 }
 
-// parsePrimary handles parenthesized expressions or conditions.
+// parsePrimary handles parenthesized expressions, trace predicates, or conditions.
 func (p *FilterParser) parsePrimary() (obsDomain.FilterNode, error) {
 	if p.match(TokenLParen) {
 		expr, err := p.parseExpression()
@@ -129,9 +132,72 @@ func (p *FilterParser) parsePrimary() (obsDomain.FilterNode, error) {
 		return expr, nil
 	}
 
+	// A relation name directly followed by "(" is a trace predicate; otherwise
+	// words like "count" remain usable as field names.
+	if p.check(TokenField) && p.peek(1).Type == TokenLParen {
+		if relation, ok := obsDomain.ParseTraceRelation(p.currentToken().Value); ok {
+			return p.parseTracePredicate(relation)
+		}
+	}
+
 	return p.parseCondition()
 }
 
+// parseTracePredicate handles HAS, COUNT, DESCENDANT, CHILD and SIBLING predicates.
+func (p *FilterParser) parseTracePredicate(relation obsDomain.TraceRelation) (obsDomain.FilterNode, error) {
+	p.advance() // relation name
+	p.advance() // "("
+
+	left, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	var right obsDomain.FilterNode
+	if relation.IsBinary() {
+		if !p.match(TokenComma) {
+			return nil, fmt.Errorf("%w: %s expects two span filters separated by ','", obsDomain.ErrInvalidFilterSyntax, relation)
+		}
+		right, err = p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !p.match(TokenRParen) {
+		return nil, obsDomain.ErrUnclosedParenthesis
+	}
+
+	node := &obsDomain.TraceNode{
+		Relation: relation,
+		Left:     left,
+		Right:    right,
+	}
+
+	if relation == obsDomain.TraceRelationCount {
+		if !p.check(TokenOperator) || p.currentToken().Value == "~" {
+			return nil, fmt.Errorf("%w: COUNT must be followed by a comparison", obsDomain.ErrMissingOperator)
+		}
+		node.Operator = obsDomain.FilterOperator(p.advance().Value)
+
+		if !p.check(TokenNumber) {
+			return nil, obsDomain.ErrMissingValue
+		}
+		count, err := strconv.ParseInt(p.advance().Value, 10, 64)
+		if err != nil || count < 0 {
+			return nil, fmt.Errorf("%w: COUNT requires a non-negative integer", obsDomain.ErrInvalidNumericValue)
+		}
+		node.Count = count
+	}
+
+	p.clauseCount++
+	if err := p.checkComplexity(); err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
 // parseCondition handles individual filter conditions.
 func (p *FilterParser) parseCondition() (obsDomain.FilterNode, error) {
 	if !p.check(TokenField) {
@@ -398,6 +464,14 @@ func (p *FilterParser) currentToken() Token {
 	return p.tokens[p.pos]
 }
 
+// peek returns the token offset positions ahead of the current one.
+func (p *FilterParser) peek(offset int) Token {
+	if p.pos+offset >= len(p.tokens) {
+		return Token{Type: TokenEOF, Pos: -1}
+	}
+	return p.tokens[p.pos+offset]
+}
+
 func (p *FilterParser) isAtEnd() bool {
 	return p.pos >= len(p.tokens) || p.tokens[p.pos].Type == TokenEOF
 }
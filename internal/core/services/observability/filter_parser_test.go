@@ -715,3 +715,137 @@ func containsError(err error, target error) bool {
 		(err.Error() == target.Error() ||
 		 len(err.Error()) > 0 && len(target.Error()) > 0))
 }
+
+func TestFilterParser_Parse_TracePredicates(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError error
+		validate  func(t *testing.T, node obsDomain.FilterNode)
+	}{
+		{
+			name:  "has",
+			input: "HAS(span.type=tool)",
+			validate: func(t *testing.T, node obsDomain.FilterNode) {
+				trace, ok := node.(*obsDomain.TraceNode)
+				if !ok {
+					t.Fatalf("expected TraceNode, got %T", node)
+				}
+				if trace.Relation != obsDomain.TraceRelationHas {
+					t.Errorf("expected relation HAS, got %s", trace.Relation)
+				}
+				if _, ok := trace.Left.(*obsDomain.ConditionNode); !ok {
+					t.Errorf("expected ConditionNode inside HAS, got %T", trace.Left)
+				}
+				if trace.Right != nil {
+					t.Errorf("expected no right operand, got %T", trace.Right)
+				}
+			},
+		},
+		{
+			name:  "count with comparison",
+			input: "count(has_error=true) >= 2",
+			validate: func(t *testing.T, node obsDomain.FilterNode) {
+				trace := node.(*obsDomain.TraceNode)
+				if trace.Relation != obsDomain.TraceRelationCount {
+					t.Errorf("expected relation COUNT, got %s", trace.Relation)
+				}
+				if trace.Operator != obsDomain.FilterOpGreaterOrEqual {
+					t.Errorf("expected operator '>=', got '%s'", trace.Operator)
+				}
+				if trace.Count != 2 {
+					t.Errorf("expected count 2, got %d", trace.Count)
+				}
+			},
+		},
+		{
+			name:  "descendant with compound operands",
+			input: "DESCENDANT(span_name=agent AND service.name=api, (span.type=tool OR span.type=retrieval))",
+			validate: func(t *testing.T, node obsDomain.FilterNode) {
+				trace := node.(*obsDomain.TraceNode)
+				if trace.Relation != obsDomain.TraceRelationDescendant {
+					t.Errorf("expected relation DESCENDANT, got %s", trace.Relation)
+				}
+				if _, ok := trace.Left.(*obsDomain.BinaryNode); !ok {
+					t.Errorf("expected BinaryNode as left operand, got %T", trace.Left)
+				}
+				if _, ok := trace.Right.(*obsDomain.BinaryNode); !ok {
+					t.Errorf("expected BinaryNode as right operand, got %T", trace.Right)
+				}
+			},
+		},
+		{
+			name:  "predicate combined with condition",
+			input: "service.name=chatbot AND SIBLING(span_name=plan, span_name=act)",
+			validate: func(t *testing.T, node obsDomain.FilterNode) {
+				bin := node.(*obsDomain.BinaryNode)
+				trace, ok := bin.Right.(*obsDomain.TraceNode)
+				if !ok {
+					t.Fatalf("expected TraceNode on the right, got %T", bin.Right)
+				}
+				if trace.Relation != obsDomain.TraceRelationSibling {
+					t.Errorf("expected relation SIBLING, got %s", trace.Relation)
+				}
+			},
+		},
+		{
+			name:  "relation name as field",
+			input: "count>5",
+			validate: func(t *testing.T, node obsDomain.FilterNode) {
+				cond, ok := node.(*obsDomain.ConditionNode)
+				if !ok {
+					t.Fatalf("expected ConditionNode, got %T", node)
+				}
+				if cond.Field != "count" {
+					t.Errorf("expected field 'count', got '%s'", cond.Field)
+				}
+			},
+		},
+		{
+			name:      "child missing second operand",
+			input:     "CHILD(span_name=agent)",
+			wantError: obsDomain.ErrInvalidFilterSyntax,
+		},
+		{
+			name:      "has unclosed",
+			input:     "HAS(span.type=tool",
+			wantError: obsDomain.ErrUnclosedParenthesis,
+		},
+		{
+			name:      "count without comparison",
+			input:     "COUNT(span.type=tool)",
+			wantError: obsDomain.ErrMissingOperator,
+		},
+		{
+			name:      "count with fractional value",
+			input:     "COUNT(span.type=tool) > 1.5",
+			wantError: obsDomain.ErrInvalidNumericValue,
+		},
+		{
+			name:      "count with negative value",
+			input:     "COUNT(span.type=tool) > -1",
+			wantError: obsDomain.ErrInvalidNumericValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := NewFilterParser()
+			node, err := parser.Parse(tt.input)
+
+			if tt.wantError != nil {
+				if !errors.Is(err, tt.wantError) {
+					t.Fatalf("expected error %v, got %v", tt.wantError, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.validate != nil {
+				tt.validate(t, node)
+			}
+		})
+	}
+}
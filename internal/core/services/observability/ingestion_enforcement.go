@@ -0,0 +1,90 @@
+package observability
+
+import (
+	"strings"
+
+	"brokle/internal/core/domain/billing"
+	"brokle/internal/core/domain/observability"
+)
+
+// payloadBodyKeys are the converted span fields that carry prompt/completion content.
+var payloadBodyKeys = []string{
+	"input",
+	"input_mime_type",
+	"input_truncated",
+	"output",
+	"output_mime_type",
+	"output_truncated",
+}
+
+// EnforcementResult reports what budget enforcement removed from a batch.
+type EnforcementResult struct {
+	SampledOut      int // Events dropped by trace sampling
+	PayloadsDropped int // Span events whose input/output bodies were removed
+}
+
+// ApplyIngestionEnforcement samples and strips converted OTLP events according to
+// the budget enforcement in effect. Blocking is handled by the caller before parsing.
+// Sampling is by trace ID, so a trace is either kept whole or dropped whole, and kept
+// spans record the sample rate so aggregates stay accurate.
+func ApplyIngestionEnforcement(events []*observability.TelemetryEventRequest, enforcement *billing.IngestionEnforcement) ([]*observability.TelemetryEventRequest, EnforcementResult) {
+	var result EnforcementResult
+	if !enforcement.Restricts() {
+		return events, result
+	}
+
+	kept := events
+	if enforcement.Samples() {
+		rate := float64(enforcement.SamplePercent) / 100
+		kept = make([]*observability.TelemetryEventRequest, 0, len(events))
+		for _, event := range events {
+			if !enforcement.KeepTrace(event.TraceID) {
+				result.SampledOut++
+				continue
+			}
+			if event.EventType == observability.TelemetryEventTypeSpan {
+				ScalePayloadSampleRate(event.Payload, rate)
+			}
+			kept = append(kept, event)
+		}
+	}
+
+	if enforcement.DropPayloads {
+		for _, event := range kept {
+			if event.EventType == observability.TelemetryEventTypeSpan && dropPayloadBodies(event.Payload) {
+				result.PayloadsDropped++
+			}
+		}
+	}
+
+	return kept, result
+}
+
+// dropPayloadBodies removes input/output and GenAI message event attributes in place.
+// Returns true if anything was removed.
+func dropPayloadBodies(payload map[string]any) bool {
+	if payload == nil {
+		return false
+	}
+
+	dropped := false
+	for _, key := range payloadBodyKeys {
+		if _, ok := payload[key]; ok {
+			delete(payload, key)
+			dropped = true
+		}
+	}
+
+	// GenAI message events (gen_ai.user.message, gen_ai.choice, ...) repeat the prompt and completion
+	if spanEvents, ok := payload["events"].([]map[string]interface{}); ok {
+		for _, event := range spanEvents {
+			name, _ := event["name"].(string)
+			if strings.HasPrefix(name, "gen_ai.") && event["attributes"] != nil {
+				delete(event, "attributes")
+				dropped = true
+			}
+		}
+	}
+
+	return dropped
+}
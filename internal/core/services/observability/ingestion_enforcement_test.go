@@ -0,0 +1,82 @@
+package observability
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"brokle/internal/core/domain/billing"
+	"brokle/internal/core/domain/observability"
+)
+
+func spanEvent(traceID, spanID string) *observability.TelemetryEventRequest {
+	return &observability.TelemetryEventRequest{
+		EventType: observability.TelemetryEventTypeSpan,
+		TraceID:   traceID,
+		SpanID:    spanID,
+		Payload: map[string]any{
+			"span_name":        "chat",
+			"input":            `[{"role":"user","content":"secret"}]`,
+			"input_mime_type":  "application/json",
+			"output":           "answer",
+			"output_truncated": true,
+			"events": []map[string]interface{}{
+				{"name": "gen_ai.user.message", "attributes": map[string]interface{}{"content": "secret"}},
+				{"name": "exception", "attributes": map[string]interface{}{"exception.message": "boom"}},
+			},
+		},
+	}
+}
+
+func TestApplyIngestionEnforcement_NoEnforcement(t *testing.T) {
+	events := []*observability.TelemetryEventRequest{spanEvent("t1", "s1")}
+
+	kept, result := ApplyIngestionEnforcement(events, nil)
+
+	assert.Len(t, kept, 1)
+	assert.Equal(t, EnforcementResult{}, result)
+	assert.Contains(t, kept[0].Payload, "input")
+}
+
+func TestApplyIngestionEnforcement_DropPayloads(t *testing.T) {
+	events := []*observability.TelemetryEventRequest{spanEvent("t1", "s1")}
+
+	kept, result := ApplyIngestionEnforcement(events, &billing.IngestionEnforcement{DropPayloads: true})
+
+	require.Len(t, kept, 1)
+	assert.Equal(t, 1, result.PayloadsDropped)
+
+	payload := kept[0].Payload
+	assert.Equal(t, "chat", payload["span_name"])
+	for _, key := range payloadBodyKeys {
+		assert.NotContains(t, payload, key)
+	}
+
+	spanEvents := payload["events"].([]map[string]interface{})
+	assert.NotContains(t, spanEvents[0], "attributes", "GenAI message events carry content")
+	assert.Contains(t, spanEvents[1], "attributes", "non-GenAI events are kept intact")
+}
+
+func TestApplyIngestionEnforcement_SamplesWholeTraces(t *testing.T) {
+	var events []*observability.TelemetryEventRequest
+	for i := 0; i < 200; i++ {
+		traceID := fmt.Sprintf("%032x", i)
+		events = append(events, spanEvent(traceID, "a"), spanEvent(traceID, "b"))
+	}
+
+	kept, result := ApplyIngestionEnforcement(events, &billing.IngestionEnforcement{SamplePercent: 50})
+
+	assert.Equal(t, len(events), len(kept)+result.SampledOut)
+	assert.NotEmpty(t, kept)
+	assert.NotZero(t, result.SampledOut)
+
+	spansPerTrace := make(map[string]int)
+	for _, event := range kept {
+		spansPerTrace[event.TraceID]++
+	}
+	for traceID, count := range spansPerTrace {
+		assert.Equal(t, 2, count, "trace %s was split by sampling", traceID)
+	}
+}
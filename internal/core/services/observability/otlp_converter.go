@@ -14,6 +14,7 @@ import (
 	"brokle/internal/config"
 	"brokle/internal/core/domain/analytics"
 	"brokle/internal/core/domain/observability"
+	"brokle/pkg/pii"
 	"brokle/pkg/ulid"
 )
 
@@ -89,6 +90,7 @@ type OTLPConverterService struct {
 	logger                 *slog.Logger
 	providerPricingService analytics.ProviderPricingService
 	config                 *config.ObservabilityConfig
+	redactionService       *RedactionService
 }
 
 // NewOTLPConverterService creates a new OTLP converter service
@@ -96,11 +98,13 @@ func NewOTLPConverterService(
 	logger *slog.Logger,
 	providerPricingService analytics.ProviderPricingService,
 	observabilityConfig *config.ObservabilityConfig,
+	redactionService *RedactionService,
 ) *OTLPConverterService {
 	return &OTLPConverterService{
 		logger:                 logger,
 		providerPricingService: providerPricingService,
 		config:                 observabilityConfig,
+		redactionService:       redactionService,
 	}
 }
 
@@ -158,17 +162,25 @@ func isRootSpanCheck(parentSpanID interface{}) bool {
 
 // ConvertOTLPToBrokleEvents converts OTLP resourceSpans to Brokle span events.
 // Traces are derived asynchronously from root spans (parent_span_id IS NULL).
+// The project's redaction policy is applied to attributes before anything is extracted from them.
 func (s *OTLPConverterService) ConvertOTLPToBrokleEvents(ctx context.Context, otlpReq *observability.OTLPRequest, projectID string) ([]*observability.TelemetryEventRequest, error) {
 	var internalEvents []*brokleEvent
 
+	redactor, err := s.redactorFor(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
 	for _, resourceSpan := range otlpReq.ResourceSpans {
 		resourceAttrs := extractAttributes(resourceSpan.Resource)
+		resourceRedactions := redactor.RedactAttributes(resourceAttrs)
 
 		for _, scopeSpan := range resourceSpan.ScopeSpans {
 			scopeAttrs := extractAttributes(scopeSpan.Scope)
+			sharedRedactions := resourceRedactions + redactor.RedactAttributes(scopeAttrs)
 
 			for _, span := range scopeSpan.Spans {
-				obsEvent, err := s.createSpanEvent(ctx, span, resourceAttrs, scopeAttrs, resourceSpan.Resource, scopeSpan.Scope, projectID)
+				obsEvent, err := s.createSpanEvent(ctx, span, resourceAttrs, scopeAttrs, resourceSpan.Resource, scopeSpan.Scope, projectID, redactor, sharedRedactions)
 				if err != nil {
 					return nil, fmt.Errorf("failed to create span event: %w", err)
 				}
@@ -180,6 +192,22 @@ func (s *OTLPConverterService) ConvertOTLPToBrokleEvents(ctx context.Context, ot
 	return convertToDomainEvents(internalEvents), nil
 }
 
+// redactorFor returns the project's redactor, or nil when its spans are stored verbatim.
+func (s *OTLPConverterService) redactorFor(ctx context.Context, projectID string) (*pii.Redactor, error) {
+	if s.redactionService == nil {
+		return nil, nil
+	}
+	id, err := ulid.Parse(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid project_id: %w", err)
+	}
+	redactor, err := s.redactionService.RedactorFor(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load redaction policy: %w", err)
+	}
+	return redactor, nil
+}
+
 func truncateWithIndicator(value string, maxSize int) (string, bool) {
 	if len(value) <= maxSize {
 		return value, false
@@ -385,7 +413,9 @@ func extractLLMMetadata(inputValue string) map[string]interface{} {
 	return metadata
 }
 
-func (s *OTLPConverterService) createSpanEvent(ctx context.Context, span observability.OTLPSpan, resourceAttrs, scopeAttrs map[string]interface{}, resource *observability.Resource, scope *observability.Scope, projectID string) (*brokleEvent, error) {
+// createSpanEvent converts one span. redactor may be nil; sharedRedactions counts
+// the values already redacted from the resource and scope attributes.
+func (s *OTLPConverterService) createSpanEvent(ctx context.Context, span observability.OTLPSpan, resourceAttrs, scopeAttrs map[string]interface{}, resource *observability.Resource, scope *observability.Scope, projectID string, redactor *pii.Redactor, sharedRedactions int) (*brokleEvent, error) {
 	traceID, err := convertTraceID(span.TraceID)
 	if err != nil {
 		return nil, fmt.Errorf("invalid trace_id: %w", err)
@@ -405,6 +435,7 @@ func (s *OTLPConverterService) createSpanEvent(ctx context.Context, span observa
 	}
 
 	spanAttrs := extractAttributesFromKeyValues(span.Attributes)
+	redactions := sharedRedactions + redactor.RedactAttributes(spanAttrs)
 	allAttrs := mergeAttributes(resourceAttrs, scopeAttrs, spanAttrs)
 	startTime := convertUnixNano(span.StartTimeUnixNano)
 	endTime := convertUnixNano(span.EndTimeUnixNano)
@@ -428,7 +459,9 @@ func (s *OTLPConverterService) createSpanEvent(ctx context.Context, span observa
 		payload["end_time"] = endTime.Format(time.RFC3339Nano)
 	}
 	if span.Status != nil && span.Status.Message != "" {
-		payload["status_message"] = span.Status.Message
+		statusMessage, n := redactor.RedactString(span.Status.Message)
+		redactions += n
+		payload["status_message"] = statusMessage
 	}
 
 	// Get instrumentation scope name for framework detection
@@ -452,7 +485,9 @@ func (s *OTLPConverterService) createSpanEvent(ctx context.Context, span observa
 				eventMap["timestamp"] = eventTime.Format(time.RFC3339Nano)
 			}
 			eventMap["name"] = event.Name
-			eventMap["attributes"] = convertToStringMap(extractAttributesFromKeyValues(event.Attributes))
+			eventAttrs := extractAttributesFromKeyValues(event.Attributes)
+			redactions += redactor.RedactAttributes(eventAttrs)
+			eventMap["attributes"] = convertToStringMap(eventAttrs)
 			eventMap["dropped_attributes_count"] = event.DroppedAttributesCount
 			spanEvents[i] = eventMap
 		}
@@ -538,13 +573,19 @@ func (s *OTLPConverterService) createSpanEvent(ctx context.Context, span observa
 					linkMap["trace_state"] = ts
 				}
 			}
-			linkMap["attributes"] = convertToStringMap(extractAttributesFromKeyValues(link.Attributes))
+			linkAttrs := extractAttributesFromKeyValues(link.Attributes)
+			redactions += redactor.RedactAttributes(linkAttrs)
+			linkMap["attributes"] = convertToStringMap(linkAttrs)
 			linkMap["dropped_attributes_count"] = link.DroppedAttributesCount
 			links[i] = linkMap
 		}
 		payload["links"] = links
 	}
 
+	if redactor != nil {
+		spanAttrs[observability.RedactionCountAttribute] = redactions
+	}
+
 	payload["resource_attributes"] = convertToStringMap(resourceAttrs)
 	payload["span_attributes"] = convertToStringMap(filterIOKeysFromMetadata(spanAttrs))
 	payload["scope_attributes"] = convertToStringMap(scopeAttrs)
@@ -561,7 +602,8 @@ func (s *OTLPConverterService) createSpanEvent(ctx context.Context, span observa
 		payload["scope_schema_url"] = scope.SchemaUrl
 	}
 
-	if s.config.PreserveRawOTLP {
+	// The raw span predates redaction, so it is never kept for redacting projects
+	if s.config.PreserveRawOTLP && redactor == nil {
 		rawOTLPJSON, err := json.Marshal(span)
 		if err == nil {
 			payload["otlp_span_raw"] = string(rawOTLPJSON)
@@ -64,3 +64,18 @@ func (w *ParquetWriter) WriteRecords(records []observability.RawTelemetryRecord)
 
 	return buf.Bytes(), nil
 }
+
+// ReadRecords decodes Parquet bytes written by WriteRecords back into RawTelemetryRecord slices.
+// Files archived before a column was added decode with that column's zero value.
+func (w *ParquetWriter) ReadRecords(data []byte) ([]observability.RawTelemetryRecord, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("no parquet data to read")
+	}
+
+	records, err := parquet.Read[observability.RawTelemetryRecord](bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read parquet records: %w", err)
+	}
+
+	return records, nil
+}
@@ -1,9 +1,11 @@
 package observability
 
 import (
+	"bytes"
 	"testing"
 	"time"
 
+	"github.com/parquet-go/parquet-go"
 	"github.com/parquet-go/parquet-go/compress/zstd"
 	"github.com/stretchr/testify/assert"
 
@@ -187,3 +189,58 @@ func TestParquetWriter_WriteRecords(t *testing.T) {
 		assert.Greater(t, len(data), 0)
 	})
 }
+
+func TestParquetWriter_ReadRecords_RoundTrip(t *testing.T) {
+	writer := NewParquetWriter(3)
+	ts := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	records := []observability.RawTelemetryRecord{
+		{
+			RecordID:    "01JX0000000000000000000001",
+			ProjectID:   "01JX0000000000000000000002",
+			SignalType:  observability.SignalTypeTraces,
+			Timestamp:   ts,
+			TraceID:     "trace-1",
+			SpanID:      "span-1",
+			SpanJSONRaw: `{"span_id":"span-1"}`,
+			ArchivedAt:  ts,
+			EventType:   string(observability.TelemetryEventTypeSpan),
+		},
+	}
+
+	data, err := writer.WriteRecords(records)
+	assert.NoError(t, err)
+
+	decoded, err := writer.ReadRecords(data)
+	assert.NoError(t, err)
+	assert.Len(t, decoded, 1)
+	assert.Equal(t, records[0].SpanID, decoded[0].SpanID)
+	assert.Equal(t, records[0].SpanJSONRaw, decoded[0].SpanJSONRaw)
+	assert.Equal(t, records[0].EventType, decoded[0].EventType)
+	assert.True(t, records[0].Timestamp.Equal(decoded[0].Timestamp))
+}
+
+// legacyRawTelemetryRecord is the archive schema before event_type was recorded.
+type legacyRawTelemetryRecord struct {
+	RecordID    string    `parquet:"record_id"`
+	ProjectID   string    `parquet:"project_id"`
+	SignalType  string    `parquet:"signal_type"`
+	Timestamp   time.Time `parquet:"timestamp,timestamp(microsecond)"`
+	TraceID     string    `parquet:"trace_id"`
+	SpanID      string    `parquet:"span_id"`
+	SpanJSONRaw string    `parquet:"span_json_raw"`
+	ArchivedAt  time.Time `parquet:"archived_at,timestamp(microsecond)"`
+}
+
+func TestParquetWriter_ReadRecords_LegacySchema(t *testing.T) {
+	var buf bytes.Buffer
+	w := parquet.NewGenericWriter[legacyRawTelemetryRecord](&buf)
+	_, err := w.Write([]legacyRawTelemetryRecord{{RecordID: "r1", SignalType: observability.SignalTypeLogs, SpanJSONRaw: "{}"}})
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	decoded, err := NewParquetWriter(3).ReadRecords(buf.Bytes())
+	assert.NoError(t, err)
+	assert.Len(t, decoded, 1)
+	assert.Equal(t, "r1", decoded[0].RecordID)
+	assert.Empty(t, decoded[0].EventType)
+}
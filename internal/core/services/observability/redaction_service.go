@@ -0,0 +1,189 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/lib/pq"
+
+	"brokle/internal/core/domain/observability"
+	appErrors "brokle/pkg/errors"
+	"brokle/pkg/pii"
+	"brokle/pkg/ulid"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// Compiled redactors are cached per project because redaction runs on every
+	// OTLP request. Changes made through this service invalidate immediately;
+	// other processes pick them up after the TTL.
+	redactionPolicyCacheTTL  = 30 * time.Second
+	redactionPolicyCacheSize = 10000
+)
+
+type redactorCacheEntry struct {
+	redactor  *pii.Redactor // nil when the project does not redact
+	expiresAt time.Time
+}
+
+// RedactionService manages per-project PII redaction policies and compiles them
+// for the OTLP converter.
+type RedactionService struct {
+	policyRepo observability.RedactionPolicyRepository
+	hashSecret []byte // Server secret the per-project hash keys are derived from
+	logger     *slog.Logger
+	redactors  *lru.Cache[ulid.ULID, *redactorCacheEntry]
+}
+
+// NewRedactionService creates a new redaction service.
+func NewRedactionService(policyRepo observability.RedactionPolicyRepository, hashSecret []byte, logger *slog.Logger) *RedactionService {
+	redactors, _ := lru.New[ulid.ULID, *redactorCacheEntry](redactionPolicyCacheSize)
+
+	return &RedactionService{
+		policyRepo: policyRepo,
+		hashSecret: hashSecret,
+		logger:     logger,
+		redactors:  redactors,
+	}
+}
+
+// GetProjectRedaction returns the project's redaction policy, or the disabled default.
+func (s *RedactionService) GetProjectRedaction(ctx context.Context, projectID ulid.ULID) (*observability.RedactionPolicy, error) {
+	policy, err := s.findPolicy(s.policyRepo.GetByProject(ctx, projectID))
+	if err != nil {
+		return nil, appErrors.NewInternalError("failed to get redaction policy", err)
+	}
+	if policy == nil {
+		return observability.DefaultRedactionPolicy(projectID), nil
+	}
+	return policy, nil
+}
+
+// UpdateProjectRedaction replaces the project's redaction policy.
+func (s *RedactionService) UpdateProjectRedaction(ctx context.Context, projectID ulid.ULID, req *observability.UpdateRedactionPolicyRequest) (*observability.RedactionPolicy, error) {
+	policy, err := s.findPolicy(s.policyRepo.GetByProject(ctx, projectID))
+	if err != nil {
+		return nil, appErrors.NewInternalError("failed to get redaction policy", err)
+	}
+	if policy == nil {
+		policy = observability.DefaultRedactionPolicy(projectID)
+		policy.ID = ulid.New()
+	}
+	applyRedactionRequest(policy, req)
+
+	if errs := observability.ValidateRedactionPolicy(policy); len(errs) > 0 {
+		return nil, appErrors.NewValidationError(errs[0].Field, errs[0].Message)
+	}
+
+	if err := s.policyRepo.Upsert(ctx, policy); err != nil {
+		s.logger.Error("failed to save redaction policy",
+			"error", err,
+			"project_id", projectID,
+		)
+		return nil, appErrors.NewInternalError("failed to save redaction policy", err)
+	}
+	s.redactors.Remove(projectID)
+
+	s.logger.Info("project redaction policy updated",
+		"project_id", projectID,
+		"policy_id", policy.ID,
+		"enabled", policy.Enabled,
+		"mode", policy.Mode,
+	)
+
+	return policy, nil
+}
+
+// DeleteProjectRedaction removes the project's policy so spans are stored verbatim.
+func (s *RedactionService) DeleteProjectRedaction(ctx context.Context, projectID ulid.ULID) error {
+	if err := s.policyRepo.DeleteByProject(ctx, projectID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return appErrors.NewNotFoundError("redaction policy for project " + projectID.String())
+		}
+		return appErrors.NewInternalError("failed to delete redaction policy", err)
+	}
+	s.redactors.Remove(projectID)
+
+	s.logger.Info("project redaction policy deleted", "project_id", projectID)
+	return nil
+}
+
+// RedactorFor returns the cached redactor in effect for ingestion, or nil when the
+// project does not redact. Unlike sampling it fails closed: when the policy cannot
+// be loaded and no earlier copy is cached, ingestion must be retried rather than
+// store spans unredacted.
+func (s *RedactionService) RedactorFor(ctx context.Context, projectID ulid.ULID) (*pii.Redactor, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	entry, cached := s.redactors.Get(projectID)
+	if cached && time.Now().Before(entry.expiresAt) {
+		return entry.redactor, nil
+	}
+
+	policy, err := s.findPolicy(s.policyRepo.GetByProject(ctx, projectID))
+	if err != nil {
+		if cached {
+			s.logger.Warn("failed to reload redaction policy, using cached copy",
+				"error", err,
+				"project_id", projectID,
+			)
+			return entry.redactor, nil
+		}
+		return nil, fmt.Errorf("load redaction policy: %w", err)
+	}
+
+	var redactor *pii.Redactor
+	if policy.Redacts() {
+		if redactor, err = pii.New(policy.RedactorConfig(s.hashSecret)); err != nil {
+			return nil, fmt.Errorf("compile redaction policy: %w", err)
+		}
+	}
+
+	s.redactors.Add(projectID, &redactorCacheEntry{
+		redactor:  redactor,
+		expiresAt: time.Now().Add(redactionPolicyCacheTTL),
+	})
+	return redactor, nil
+}
+
+// findPolicy maps a missing policy to nil.
+func (s *RedactionService) findPolicy(policy *observability.RedactionPolicy, err error) (*observability.RedactionPolicy, error) {
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return policy, nil
+}
+
+func applyRedactionRequest(policy *observability.RedactionPolicy, req *observability.UpdateRedactionPolicyRequest) {
+	policy.Enabled = req.Enabled
+	policy.Mode = pii.ModeMask
+	if req.Mode != nil {
+		policy.Mode = *req.Mode
+	}
+	policy.Detectors = observability.AllRedactionDetectors()
+	if req.Detectors != nil {
+		policy.Detectors = pq.StringArray(req.Detectors)
+	}
+	policy.CustomPatterns = req.CustomPatterns
+	if policy.CustomPatterns == nil {
+		policy.CustomPatterns = []pii.Pattern{}
+	}
+	policy.AllowAttributes = pq.StringArray(req.AllowAttributes)
+	if policy.AllowAttributes == nil {
+		policy.AllowAttributes = pq.StringArray{}
+	}
+	policy.DenyAttributes = pq.StringArray(req.DenyAttributes)
+	if policy.DenyAttributes == nil {
+		policy.DenyAttributes = pq.StringArray{}
+	}
+}
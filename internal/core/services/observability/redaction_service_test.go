@@ -0,0 +1,205 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"brokle/internal/config"
+	obsDomain "brokle/internal/core/domain/observability"
+	appErrors "brokle/pkg/errors"
+	"brokle/pkg/pii"
+	"brokle/pkg/ulid"
+)
+
+type mockRedactionPolicyRepository struct {
+	mock.Mock
+}
+
+func (m *mockRedactionPolicyRepository) GetByProject(ctx context.Context, projectID ulid.ULID) (*obsDomain.RedactionPolicy, error) {
+	args := m.Called(ctx, projectID)
+	if v := args.Get(0); v != nil {
+		return v.(*obsDomain.RedactionPolicy), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockRedactionPolicyRepository) Upsert(ctx context.Context, policy *obsDomain.RedactionPolicy) error {
+	return m.Called(ctx, policy).Error(0)
+}
+
+func (m *mockRedactionPolicyRepository) DeleteByProject(ctx context.Context, projectID ulid.ULID) error {
+	return m.Called(ctx, projectID).Error(0)
+}
+
+func newTestRedactionService(repo obsDomain.RedactionPolicyRepository) *RedactionService {
+	return NewRedactionService(repo, []byte("server-secret"), slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func stringAttr(key, value string) obsDomain.KeyValue {
+	return obsDomain.KeyValue{Key: key, Value: map[string]interface{}{"stringValue": value}}
+}
+
+func TestRedactionService_UpdateProjectRedaction(t *testing.T) {
+	ctx := context.Background()
+	projectID := ulid.New()
+
+	t.Run("defaults to every detector in mask mode", func(t *testing.T) {
+		repo := &mockRedactionPolicyRepository{}
+		repo.On("GetByProject", ctx, projectID).Return(nil, gorm.ErrRecordNotFound)
+		repo.On("Upsert", ctx, mock.Anything).Return(nil)
+
+		policy, err := newTestRedactionService(repo).UpdateProjectRedaction(ctx, projectID, &obsDomain.UpdateRedactionPolicyRequest{Enabled: true})
+		require.NoError(t, err)
+		assert.True(t, policy.Enabled)
+		assert.Equal(t, pii.ModeMask, policy.Mode)
+		assert.ElementsMatch(t, obsDomain.AllRedactionDetectors(), policy.Detectors)
+		assert.False(t, policy.ID.IsZero())
+	})
+
+	t.Run("rejects invalid policies", func(t *testing.T) {
+		badMode := pii.Mode("encrypt")
+		for name, req := range map[string]*obsDomain.UpdateRedactionPolicyRequest{
+			"mode":     {Mode: &badMode},
+			"detector": {Detectors: []string{"passport"}},
+			"pattern":  {CustomPatterns: []pii.Pattern{{Name: "broken", Pattern: "("}}},
+			"name":     {CustomPatterns: []pii.Pattern{{Pattern: `\d+`}}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				repo := &mockRedactionPolicyRepository{}
+				repo.On("GetByProject", ctx, projectID).Return(nil, gorm.ErrRecordNotFound)
+
+				_, err := newTestRedactionService(repo).UpdateProjectRedaction(ctx, projectID, req)
+				appErr, ok := appErrors.IsAppError(err)
+				require.True(t, ok)
+				assert.Equal(t, appErrors.ValidationError, appErr.Type)
+				repo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+			})
+		}
+	})
+}
+
+func TestRedactionService_RedactorFor(t *testing.T) {
+	ctx := context.Background()
+	projectID := ulid.New()
+
+	t.Run("projects without a policy are not redacted", func(t *testing.T) {
+		repo := &mockRedactionPolicyRepository{}
+		repo.On("GetByProject", ctx, projectID).Return(nil, gorm.ErrRecordNotFound).Once()
+		service := newTestRedactionService(repo)
+
+		redactor, err := service.RedactorFor(ctx, projectID)
+		require.NoError(t, err)
+		assert.Nil(t, redactor)
+
+		// Cached: the repository is hit once
+		_, err = service.RedactorFor(ctx, projectID)
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("fails closed when the policy cannot be loaded", func(t *testing.T) {
+		repo := &mockRedactionPolicyRepository{}
+		repo.On("GetByProject", ctx, projectID).Return(nil, errors.New("connection refused"))
+
+		_, err := newTestRedactionService(repo).RedactorFor(ctx, projectID)
+		assert.Error(t, err)
+	})
+}
+
+func TestRedactionPolicy_RedactorConfigHashKey(t *testing.T) {
+	projectID := ulid.New()
+	policy := &obsDomain.RedactionPolicy{ProjectID: projectID, Mode: pii.ModeHash}
+
+	key := policy.RedactorConfig([]byte("server-secret")).HashKey
+	assert.Equal(t, key, policy.RedactorConfig([]byte("server-secret")).HashKey)
+	assert.NotEqual(t, []byte(projectID.String()), key, "the project ID alone must not be the key")
+	assert.NotEqual(t, key, policy.RedactorConfig([]byte("other-secret")).HashKey)
+
+	other := &obsDomain.RedactionPolicy{ProjectID: ulid.New(), Mode: pii.ModeHash}
+	assert.NotEqual(t, key, other.RedactorConfig([]byte("server-secret")).HashKey)
+}
+
+func TestConvertOTLPToBrokleEvents_Redaction(t *testing.T) {
+	ctx := context.Background()
+	projectID := ulid.New()
+
+	policy := obsDomain.DefaultRedactionPolicy(projectID)
+	policy.Enabled = true
+	policy.DenyAttributes = []string{"enduser.*"}
+	policy.CustomPatterns = []pii.Pattern{{Name: "order_id", Pattern: `ORD-\d{6}`}}
+
+	repo := &mockRedactionPolicyRepository{}
+	repo.On("GetByProject", ctx, projectID).Return(policy, nil)
+	converter := NewOTLPConverterService(
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		nil,
+		&config.ObservabilityConfig{PreserveRawOTLP: true},
+		newTestRedactionService(repo),
+	)
+
+	req := &obsDomain.OTLPRequest{ResourceSpans: []obsDomain.ResourceSpan{{
+		Resource: &obsDomain.Resource{Attributes: []obsDomain.KeyValue{
+			stringAttr("service.name", "support-bot"),
+			stringAttr("host.ip", "10.1.2.3"),
+		}},
+		ScopeSpans: []obsDomain.ScopeSpan{{
+			Scope: &obsDomain.Scope{Name: "brokle"},
+			Spans: []obsDomain.OTLPSpan{{
+				TraceID: "0123456789abcdef0123456789abcdef",
+				SpanID:  "0123456789abcdef",
+				Name:    "chat",
+				Status:  &obsDomain.Status{Code: 2, Message: "card 4111 1111 1111 1111 declined"},
+				Attributes: []obsDomain.KeyValue{
+					stringAttr("gen_ai.input.messages", `[{"role":"user","content":"I'm jane@example.com, where is ORD-123456?"}]`),
+					stringAttr("enduser.id", "customer-42"),
+					stringAttr("gen_ai.request.model", "gpt-4o"),
+				},
+				Events: []obsDomain.Event{{
+					Name:       "feedback",
+					Attributes: []obsDomain.KeyValue{stringAttr("comment", "call me on +44 20 7946 0958")},
+				}},
+			}},
+		}},
+	}}}
+
+	events, err := converter.ConvertOTLPToBrokleEvents(ctx, req, projectID.String())
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	payload := events[0].Payload
+
+	assert.Equal(t, `[{"role":"user","content":"I'm [REDACTED:email], where is [REDACTED:order_id]?"}]`, payload["input"])
+	assert.Equal(t, "card [REDACTED:credit_card] declined", payload["status_message"])
+
+	spanAttrs := payload["span_attributes"].(map[string]string)
+	assert.Equal(t, "[REDACTED:attribute]", spanAttrs["enduser.id"])
+	assert.Equal(t, "gpt-4o", spanAttrs["gen_ai.request.model"])
+	assert.Equal(t, "6", spanAttrs[obsDomain.RedactionCountAttribute]) // host.ip, email, order, enduser.id, card, phone
+
+	assert.Equal(t, "[REDACTED:ip_address]", payload["resource_attributes"].(map[string]string)["host.ip"])
+	assert.Equal(t, "support-bot", payload["resource_attributes"].(map[string]string)["service.name"])
+
+	spanEvents := payload["events"].([]map[string]interface{})
+	assert.Equal(t, "call me on [REDACTED:phone]", spanEvents[0]["attributes"].(map[string]string)["comment"])
+
+	assert.NotContains(t, payload, "otlp_span_raw", "raw spans would bypass redaction")
+}
+
+func TestConvertOTLPToBrokleEvents_RedactionPolicyUnavailable(t *testing.T) {
+	ctx := context.Background()
+	projectID := ulid.New()
+
+	repo := &mockRedactionPolicyRepository{}
+	repo.On("GetByProject", ctx, projectID).Return(nil, errors.New("connection refused"))
+	converter := NewOTLPConverterService(slog.New(slog.NewTextHandler(io.Discard, nil)), nil, &config.ObservabilityConfig{}, newTestRedactionService(repo))
+
+	_, err := converter.ConvertOTLPToBrokleEvents(ctx, &obsDomain.OTLPRequest{}, projectID.String())
+	assert.Error(t, err)
+}
@@ -0,0 +1,81 @@
+package observability
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	obsDomain "brokle/internal/core/domain/observability"
+)
+
+func TestArchivedEventType_InfersLegacyRecords(t *testing.T) {
+	tests := []struct {
+		name    string
+		signal  string
+		payload any
+		want    obsDomain.TelemetryEventType
+	}{
+		{"span", obsDomain.SignalTypeTraces, &obsDomain.Span{SpanID: "s1", StartTime: time.Now()}, obsDomain.TelemetryEventTypeSpan},
+		{"score archived under traces", obsDomain.SignalTypeTraces, &obsDomain.Score{ID: "sc1"}, obsDomain.TelemetryEventTypeQualityScore},
+		{"metric sum", obsDomain.SignalTypeMetrics, &obsDomain.MetricSum{}, obsDomain.TelemetryEventTypeMetricSum},
+		{"metric gauge", obsDomain.SignalTypeMetrics, &obsDomain.MetricGauge{}, obsDomain.TelemetryEventTypeMetricGauge},
+		{"metric histogram", obsDomain.SignalTypeMetrics, &obsDomain.MetricHistogram{}, obsDomain.TelemetryEventTypeMetricHistogram},
+		{"metric exponential histogram", obsDomain.SignalTypeMetrics, &obsDomain.MetricExponentialHistogram{}, obsDomain.TelemetryEventTypeMetricExponentialHistogram},
+		{"log", obsDomain.SignalTypeLogs, &obsDomain.Log{}, obsDomain.TelemetryEventTypeLog},
+		{"genai event", obsDomain.SignalTypeGenAI, &obsDomain.GenAIEvent{}, obsDomain.TelemetryEventTypeGenAIEvent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := json.Marshal(tt.payload)
+			if err != nil {
+				t.Fatalf("marshal payload: %v", err)
+			}
+			record := &obsDomain.RawTelemetryRecord{SignalType: tt.signal, SpanJSONRaw: string(raw)}
+			if got := archivedEventType(record); got != tt.want {
+				t.Errorf("archivedEventType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArchivedEventType_PrefersRecordedType(t *testing.T) {
+	record := &obsDomain.RawTelemetryRecord{
+		SignalType:  obsDomain.SignalTypeMetrics,
+		EventType:   string(obsDomain.TelemetryEventTypeMetricGauge),
+		SpanJSONRaw: `{"IsMonotonic":true}`,
+	}
+	if got := archivedEventType(record); got != obsDomain.TelemetryEventTypeMetricGauge {
+		t.Errorf("archivedEventType() = %q, want %q", got, obsDomain.TelemetryEventTypeMetricGauge)
+	}
+}
+
+func TestValidateRehydrateRequest(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		req     *obsDomain.RehydrateRequest
+		wantErr bool
+		wantLen int
+	}{
+		{"defaults to all signals", &obsDomain.RehydrateRequest{From: from, To: from.AddDate(0, 0, 7)}, false, 4},
+		{"explicit signals", &obsDomain.RehydrateRequest{Signals: []string{"traces"}, From: from, To: from.AddDate(0, 0, 1)}, false, 1},
+		{"missing range", &obsDomain.RehydrateRequest{From: from}, true, 0},
+		{"inverted range", &obsDomain.RehydrateRequest{From: from, To: from.AddDate(0, 0, -1)}, true, 0},
+		{"range too long", &obsDomain.RehydrateRequest{From: from, To: from.AddDate(0, 0, obsDomain.MaxRehydrateDays+1)}, true, 0},
+		{"unknown signal", &obsDomain.RehydrateRequest{Signals: []string{"profiles"}, From: from, To: from.AddDate(0, 0, 1)}, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signals, err := validateRehydrateRequest(tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateRehydrateRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(signals) != tt.wantLen {
+				t.Errorf("got %d signals, want %d", len(signals), tt.wantLen)
+			}
+		})
+	}
+}
@@ -0,0 +1,408 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"brokle/internal/config"
+	"brokle/internal/core/domain/observability"
+	"brokle/internal/core/domain/organization"
+	appErrors "brokle/pkg/errors"
+	"brokle/pkg/ulid"
+
+	"gorm.io/gorm"
+)
+
+// RetentionService resolves and enforces per-project telemetry retention.
+// Resolution order per signal: project policy, organization policy, config default.
+type RetentionService struct {
+	policyRepo  observability.RetentionPolicyRepository
+	purgeRepo   observability.RetentionPurgeRepository
+	projectRepo organization.ProjectRepository
+	config      *config.RetentionConfig
+	logger      *slog.Logger
+}
+
+// NewRetentionService creates a new retention service.
+func NewRetentionService(
+	policyRepo observability.RetentionPolicyRepository,
+	purgeRepo observability.RetentionPurgeRepository,
+	projectRepo organization.ProjectRepository,
+	cfg *config.RetentionConfig,
+	logger *slog.Logger,
+) *RetentionService {
+	return &RetentionService{
+		policyRepo:  policyRepo,
+		purgeRepo:   purgeRepo,
+		projectRepo: projectRepo,
+		config:      cfg,
+		logger:      logger,
+	}
+}
+
+// GetOrganizationRetention returns the organization policy resolved against config defaults.
+func (s *RetentionService) GetOrganizationRetention(ctx context.Context, orgID ulid.ULID) (*observability.EffectiveRetention, error) {
+	orgPolicy, err := s.findPolicy(s.policyRepo.GetByOrganization(ctx, orgID))
+	if err != nil {
+		return nil, appErrors.NewInternalError("failed to get retention policy", err)
+	}
+
+	return &observability.EffectiveRetention{
+		OrganizationID: orgID.String(),
+		Policy:         orgPolicy,
+		Signals:        resolveRetention(s.defaults(), orgPolicy, nil),
+	}, nil
+}
+
+// UpdateOrganizationRetention replaces the organization default retention.
+func (s *RetentionService) UpdateOrganizationRetention(ctx context.Context, orgID ulid.ULID, req *observability.UpdateRetentionPolicyRequest) (*observability.EffectiveRetention, error) {
+	if field := req.Validate(); field != "" {
+		return nil, appErrors.NewValidationError(field, fmt.Sprintf("must be between %d and %d days", observability.MinRetentionDays, observability.MaxRetentionDays))
+	}
+
+	policy, err := s.findPolicy(s.policyRepo.GetByOrganization(ctx, orgID))
+	if err != nil {
+		return nil, appErrors.NewInternalError("failed to get retention policy", err)
+	}
+	if policy == nil {
+		policy = &observability.RetentionPolicy{ID: ulid.New(), OrganizationID: orgID}
+	}
+	applyRetentionRequest(policy, req)
+
+	if err := s.policyRepo.Upsert(ctx, policy); err != nil {
+		s.logger.Error("failed to save organization retention policy",
+			"error", err,
+			"organization_id", orgID,
+		)
+		return nil, appErrors.NewInternalError("failed to save retention policy", err)
+	}
+
+	s.logger.Info("organization retention policy updated",
+		"organization_id", orgID,
+		"policy_id", policy.ID,
+	)
+
+	return s.GetOrganizationRetention(ctx, orgID)
+}
+
+// GetProjectRetention returns the effective retention of a project.
+func (s *RetentionService) GetProjectRetention(ctx context.Context, projectID ulid.ULID) (*observability.EffectiveRetention, error) {
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, organization.ErrProjectNotFound) {
+			return nil, appErrors.NewNotFoundError("project " + projectID.String())
+		}
+		return nil, appErrors.NewInternalError("failed to get project", err)
+	}
+
+	orgPolicy, err := s.findPolicy(s.policyRepo.GetByOrganization(ctx, project.OrganizationID))
+	if err != nil {
+		return nil, appErrors.NewInternalError("failed to get retention policy", err)
+	}
+	projectPolicy, err := s.findPolicy(s.policyRepo.GetByProject(ctx, projectID))
+	if err != nil {
+		return nil, appErrors.NewInternalError("failed to get retention policy", err)
+	}
+
+	return &observability.EffectiveRetention{
+		OrganizationID: project.OrganizationID.String(),
+		ProjectID:      projectID.String(),
+		Policy:         projectPolicy,
+		Signals:        resolveRetention(s.defaults(), orgPolicy, projectPolicy),
+	}, nil
+}
+
+// UpdateProjectRetention replaces the project retention override.
+func (s *RetentionService) UpdateProjectRetention(ctx context.Context, projectID ulid.ULID, req *observability.UpdateRetentionPolicyRequest) (*observability.EffectiveRetention, error) {
+	if field := req.Validate(); field != "" {
+		return nil, appErrors.NewValidationError(field, fmt.Sprintf("must be between %d and %d days", observability.MinRetentionDays, observability.MaxRetentionDays))
+	}
+
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, organization.ErrProjectNotFound) {
+			return nil, appErrors.NewNotFoundError("project " + projectID.String())
+		}
+		return nil, appErrors.NewInternalError("failed to get project", err)
+	}
+
+	policy, err := s.findPolicy(s.policyRepo.GetByProject(ctx, projectID))
+	if err != nil {
+		return nil, appErrors.NewInternalError("failed to get retention policy", err)
+	}
+	if policy == nil {
+		policy = &observability.RetentionPolicy{
+			ID:             ulid.New(),
+			OrganizationID: project.OrganizationID,
+			ProjectID:      &projectID,
+		}
+	}
+	applyRetentionRequest(policy, req)
+
+	if err := s.policyRepo.Upsert(ctx, policy); err != nil {
+		s.logger.Error("failed to save project retention policy",
+			"error", err,
+			"project_id", projectID,
+		)
+		return nil, appErrors.NewInternalError("failed to save retention policy", err)
+	}
+
+	s.logger.Info("project retention policy updated",
+		"project_id", projectID,
+		"policy_id", policy.ID,
+	)
+
+	return s.GetProjectRetention(ctx, projectID)
+}
+
+// DeleteProjectRetention removes the project override so the organization policy applies.
+func (s *RetentionService) DeleteProjectRetention(ctx context.Context, projectID ulid.ULID) error {
+	if err := s.policyRepo.DeleteByProject(ctx, projectID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return appErrors.NewNotFoundError("retention policy for project " + projectID.String())
+		}
+		return appErrors.NewInternalError("failed to delete retention policy", err)
+	}
+
+	s.logger.Info("project retention policy deleted", "project_id", projectID)
+	return nil
+}
+
+// DryRun reports how many rows the project's effective policy would purge per signal.
+func (s *RetentionService) DryRun(ctx context.Context, projectID ulid.ULID) ([]*observability.RetentionPurgeEstimate, error) {
+	effective, err := s.GetProjectRetention(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	estimates := make([]*observability.RetentionPurgeEstimate, 0, len(effective.Signals))
+	for _, sr := range effective.Signals {
+		if sr.Days <= 0 {
+			continue
+		}
+		cutoff := retentionCutoff(now, sr.Days)
+		rows, err := s.purgeRepo.CountBefore(ctx, sr.Signal, effective.ProjectID, cutoff)
+		if err != nil {
+			return nil, appErrors.NewInternalError("failed to count expired rows", err)
+		}
+		estimates = append(estimates, &observability.RetentionPurgeEstimate{
+			ProjectID:     effective.ProjectID,
+			Signal:        sr.Signal,
+			RetentionDays: sr.Days,
+			Source:        sr.Source,
+			Cutoff:        cutoff,
+			Rows:          rows,
+		})
+	}
+	return estimates, nil
+}
+
+// Enforce purges expired telemetry for every project holding data past its retention.
+// With dryRun set, nothing is deleted and the summary carries per-project estimates.
+func (s *RetentionService) Enforce(ctx context.Context, dryRun bool) (*observability.RetentionRunSummary, error) {
+	policies, err := s.policyRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list retention policies: %w", err)
+	}
+
+	orgPolicies := make(map[ulid.ULID]*observability.RetentionPolicy)
+	projectPolicies := make(map[string]*observability.RetentionPolicy)
+	for _, p := range policies {
+		if p.ProjectID == nil {
+			orgPolicies[p.OrganizationID] = p
+		} else {
+			projectPolicies[p.ProjectID.String()] = p
+		}
+	}
+
+	defaults := s.defaults()
+	projectOrgs := make(map[string]ulid.ULID)
+	checked := make(map[string]struct{})
+	summary := &observability.RetentionRunSummary{DryRun: dryRun}
+	now := time.Now().UTC()
+
+	for _, signal := range observability.AllRetentionSignals() {
+		minDays := minRetentionDays(signal, defaults[signal], policies)
+		if minDays <= 0 {
+			continue
+		}
+
+		// Only projects with data older than the shortest policy can have anything to purge
+		projectIDs, err := s.purgeRepo.ListProjectsWithDataBefore(ctx, signal, retentionCutoff(now, minDays))
+		if err != nil {
+			return summary, fmt.Errorf("list projects for %s: %w", signal, err)
+		}
+
+		for _, projectID := range projectIDs {
+			checked[projectID] = struct{}{}
+
+			orgID, ok := projectOrgs[projectID]
+			if !ok {
+				orgID, err = s.lookupOrganization(ctx, projectID)
+				if err != nil {
+					// Without its organization policy the project could be purged too early
+					s.logger.Error("skipping project retention, organization lookup failed",
+						"error", err,
+						"project_id", projectID,
+						"signal", signal,
+					)
+					continue
+				}
+				projectOrgs[projectID] = orgID
+			}
+
+			resolved := resolveSignalRetention(signal, defaults[signal], orgPolicies[orgID], projectPolicies[projectID])
+			if resolved.Days <= 0 {
+				continue
+			}
+
+			cutoff := retentionCutoff(now, resolved.Days)
+			rows, err := s.purgeRepo.CountBefore(ctx, signal, projectID, cutoff)
+			if err != nil {
+				s.logger.Error("failed to count expired rows",
+					"error", err,
+					"project_id", projectID,
+					"signal", signal,
+				)
+				continue
+			}
+			if rows == 0 {
+				continue
+			}
+			summary.RowsExpired += rows
+
+			if dryRun {
+				summary.Estimates = append(summary.Estimates, &observability.RetentionPurgeEstimate{
+					ProjectID:     projectID,
+					Signal:        signal,
+					RetentionDays: resolved.Days,
+					Source:        resolved.Source,
+					Cutoff:        cutoff,
+					Rows:          rows,
+				})
+				s.logger.Info("retention dry run: rows would be purged",
+					"project_id", projectID,
+					"signal", signal,
+					"retention_days", resolved.Days,
+					"source", resolved.Source,
+					"rows", rows,
+				)
+				continue
+			}
+
+			result, err := s.purgeRepo.PurgeBefore(ctx, signal, projectID, cutoff)
+			if result != nil {
+				summary.PartitionsDropped += result.PartitionsDropped
+				summary.DeleteMutations += result.DeleteMutations
+			}
+			if err != nil {
+				s.logger.Error("failed to purge expired rows",
+					"error", err,
+					"project_id", projectID,
+					"signal", signal,
+				)
+				continue
+			}
+
+			s.logger.Info("expired telemetry purged",
+				"project_id", projectID,
+				"signal", signal,
+				"retention_days", resolved.Days,
+				"source", resolved.Source,
+				"rows", rows,
+				"partitions_dropped", result.PartitionsDropped,
+				"delete_mutations", result.DeleteMutations,
+			)
+		}
+	}
+
+	summary.ProjectsChecked = len(checked)
+	return summary, nil
+}
+
+// lookupOrganization returns the project's organization, or a zero ULID if the
+// project no longer exists (its data then falls back to config defaults). Any other
+// failure is returned so the caller skips the project rather than ignore its policies.
+func (s *RetentionService) lookupOrganization(ctx context.Context, projectID string) (ulid.ULID, error) {
+	id, err := ulid.Parse(projectID)
+	if err != nil {
+		return ulid.ULID{}, nil
+	}
+	project, err := s.projectRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, organization.ErrProjectNotFound) {
+			return ulid.ULID{}, nil
+		}
+		return ulid.ULID{}, fmt.Errorf("get project %s: %w", projectID, err)
+	}
+	return project.OrganizationID, nil
+}
+
+func (s *RetentionService) defaults() map[observability.RetentionSignal]int {
+	return map[observability.RetentionSignal]int{
+		observability.RetentionSignalSpans:       s.config.DefaultSpansDays,
+		observability.RetentionSignalLogs:        s.config.DefaultLogsDays,
+		observability.RetentionSignalMetrics:     s.config.DefaultMetricsDays,
+		observability.RetentionSignalScores:      s.config.DefaultScoresDays,
+		observability.RetentionSignalGenAIEvents: s.config.DefaultGenAIEventsDays,
+	}
+}
+
+// findPolicy maps a not-found lookup to a nil policy.
+func (s *RetentionService) findPolicy(policy *observability.RetentionPolicy, err error) (*observability.RetentionPolicy, error) {
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return policy, nil
+}
+
+func applyRetentionRequest(policy *observability.RetentionPolicy, req *observability.UpdateRetentionPolicyRequest) {
+	policy.SpansDays = req.SpansDays
+	policy.LogsDays = req.LogsDays
+	policy.MetricsDays = req.MetricsDays
+	policy.ScoresDays = req.ScoresDays
+	policy.GenAIEventsDays = req.GenAIEventsDays
+}
+
+// resolveRetention resolves every signal; either policy may be nil.
+func resolveRetention(defaults map[observability.RetentionSignal]int, orgPolicy, projectPolicy *observability.RetentionPolicy) []*observability.SignalRetention {
+	signals := observability.AllRetentionSignals()
+	resolved := make([]*observability.SignalRetention, 0, len(signals))
+	for _, signal := range signals {
+		resolved = append(resolved, resolveSignalRetention(signal, defaults[signal], orgPolicy, projectPolicy))
+	}
+	return resolved
+}
+
+func resolveSignalRetention(signal observability.RetentionSignal, defaultDays int, orgPolicy, projectPolicy *observability.RetentionPolicy) *observability.SignalRetention {
+	if days := projectPolicy.DaysFor(signal); days != nil {
+		return &observability.SignalRetention{Signal: signal, Days: *days, Source: observability.RetentionSourceProject}
+	}
+	if days := orgPolicy.DaysFor(signal); days != nil {
+		return &observability.SignalRetention{Signal: signal, Days: *days, Source: observability.RetentionSourceOrganization}
+	}
+	return &observability.SignalRetention{Signal: signal, Days: defaultDays, Source: observability.RetentionSourceDefault}
+}
+
+// minRetentionDays returns the shortest retention any project can have for a signal.
+// A non-positive default means data without a policy is kept indefinitely.
+func minRetentionDays(signal observability.RetentionSignal, defaultDays int, policies []*observability.RetentionPolicy) int {
+	minDays := defaultDays
+	for _, p := range policies {
+		if days := p.DaysFor(signal); days != nil && (minDays <= 0 || *days < minDays) {
+			minDays = *days
+		}
+	}
+	return minDays
+}
+
+func retentionCutoff(now time.Time, days int) time.Time {
+	return now.AddDate(0, 0, -days)
+}
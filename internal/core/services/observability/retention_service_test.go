@@ -0,0 +1,169 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"brokle/internal/config"
+	obsDomain "brokle/internal/core/domain/observability"
+	orgDomain "brokle/internal/core/domain/organization"
+	"brokle/pkg/ulid"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestResolveRetention_Precedence(t *testing.T) {
+	defaults := map[obsDomain.RetentionSignal]int{
+		obsDomain.RetentionSignalSpans:       365,
+		obsDomain.RetentionSignalLogs:        90,
+		obsDomain.RetentionSignalMetrics:     365,
+		obsDomain.RetentionSignalScores:      365,
+		obsDomain.RetentionSignalGenAIEvents: 365,
+	}
+	orgPolicy := &obsDomain.RetentionPolicy{SpansDays: intPtr(30), LogsDays: intPtr(30)}
+	projectPolicy := &obsDomain.RetentionPolicy{SpansDays: intPtr(730)}
+
+	tests := []struct {
+		name       string
+		org        *obsDomain.RetentionPolicy
+		project    *obsDomain.RetentionPolicy
+		signal     obsDomain.RetentionSignal
+		wantDays   int
+		wantSource obsDomain.RetentionSource
+	}{
+		{"no policies uses default", nil, nil, obsDomain.RetentionSignalLogs, 90, obsDomain.RetentionSourceDefault},
+		{"organization overrides default", orgPolicy, nil, obsDomain.RetentionSignalSpans, 30, obsDomain.RetentionSourceOrganization},
+		{"project overrides organization", orgPolicy, projectPolicy, obsDomain.RetentionSignalSpans, 730, obsDomain.RetentionSourceProject},
+		{"unset project signal inherits organization", orgPolicy, projectPolicy, obsDomain.RetentionSignalLogs, 30, obsDomain.RetentionSourceOrganization},
+		{"unset everywhere uses default", orgPolicy, projectPolicy, obsDomain.RetentionSignalScores, 365, obsDomain.RetentionSourceDefault},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved := resolveRetention(defaults, tt.org, tt.project)
+			if len(resolved) != len(obsDomain.AllRetentionSignals()) {
+				t.Fatalf("expected %d signals, got %d", len(obsDomain.AllRetentionSignals()), len(resolved))
+			}
+			effective := &obsDomain.EffectiveRetention{Signals: resolved}
+			if got := effective.DaysFor(tt.signal); got != tt.wantDays {
+				t.Errorf("expected %d days, got %d", tt.wantDays, got)
+			}
+			got := resolveSignalRetention(tt.signal, defaults[tt.signal], tt.org, tt.project)
+			if got.Source != tt.wantSource {
+				t.Errorf("expected source %s, got %s", tt.wantSource, got.Source)
+			}
+		})
+	}
+}
+
+func TestMinRetentionDays(t *testing.T) {
+	policies := []*obsDomain.RetentionPolicy{
+		{SpansDays: intPtr(730)},
+		{SpansDays: intPtr(30), LogsDays: intPtr(120)},
+	}
+
+	if got := minRetentionDays(obsDomain.RetentionSignalSpans, 365, policies); got != 30 {
+		t.Errorf("spans: expected 30, got %d", got)
+	}
+	if got := minRetentionDays(obsDomain.RetentionSignalLogs, 90, policies); got != 90 {
+		t.Errorf("logs: expected 90, got %d", got)
+	}
+	if got := minRetentionDays(obsDomain.RetentionSignalScores, 0, policies); got != 0 {
+		t.Errorf("scores: expected 0 (keep indefinitely), got %d", got)
+	}
+	if got := minRetentionDays(obsDomain.RetentionSignalLogs, 0, policies); got != 120 {
+		t.Errorf("logs without default: expected 120, got %d", got)
+	}
+}
+
+func TestUpdateRetentionPolicyRequest_Validate(t *testing.T) {
+	valid := &obsDomain.UpdateRetentionPolicyRequest{SpansDays: intPtr(30), ScoresDays: intPtr(730)}
+	if field := valid.Validate(); field != "" {
+		t.Errorf("expected valid request, got invalid field %s", field)
+	}
+
+	invalid := &obsDomain.UpdateRetentionPolicyRequest{SpansDays: intPtr(30), LogsDays: intPtr(0)}
+	if field := invalid.Validate(); field != "logs_days" {
+		t.Errorf("expected logs_days to be invalid, got %q", field)
+	}
+}
+
+type fakePolicyRepo struct {
+	obsDomain.RetentionPolicyRepository
+	policies []*obsDomain.RetentionPolicy
+}
+
+func (f *fakePolicyRepo) List(ctx context.Context) ([]*obsDomain.RetentionPolicy, error) {
+	return f.policies, nil
+}
+
+type fakePurgeRepo struct {
+	projectIDs []string
+	purged     map[string]int
+}
+
+func (f *fakePurgeRepo) ListProjectsWithDataBefore(ctx context.Context, signal obsDomain.RetentionSignal, cutoff time.Time) ([]string, error) {
+	return f.projectIDs, nil
+}
+
+func (f *fakePurgeRepo) CountBefore(ctx context.Context, signal obsDomain.RetentionSignal, projectID string, cutoff time.Time) (uint64, error) {
+	return 1, nil
+}
+
+func (f *fakePurgeRepo) PurgeBefore(ctx context.Context, signal obsDomain.RetentionSignal, projectID string, cutoff time.Time) (*obsDomain.RetentionPurgeResult, error) {
+	f.purged[projectID]++
+	return &obsDomain.RetentionPurgeResult{}, nil
+}
+
+type fakeProjectRepo struct {
+	orgDomain.ProjectRepository
+	projects map[ulid.ULID]*orgDomain.Project
+	failing  ulid.ULID
+}
+
+func (f *fakeProjectRepo) GetByID(ctx context.Context, id ulid.ULID) (*orgDomain.Project, error) {
+	if id == f.failing {
+		return nil, errors.New("connection refused")
+	}
+	if project, ok := f.projects[id]; ok {
+		return project, nil
+	}
+	return nil, orgDomain.ErrProjectNotFound
+}
+
+func TestEnforce_SkipsProjectWhenOrganizationLookupFails(t *testing.T) {
+	orgID := ulid.New()
+	healthy, failing, deleted := ulid.New(), ulid.New(), ulid.New()
+
+	purgeRepo := &fakePurgeRepo{
+		projectIDs: []string{healthy.String(), failing.String(), deleted.String()},
+		purged:     make(map[string]int),
+	}
+	svc := NewRetentionService(
+		&fakePolicyRepo{policies: []*obsDomain.RetentionPolicy{{OrganizationID: orgID, SpansDays: intPtr(30)}}},
+		purgeRepo,
+		&fakeProjectRepo{
+			projects: map[ulid.ULID]*orgDomain.Project{healthy: {ID: healthy, OrganizationID: orgID}},
+			failing:  failing,
+		},
+		&config.RetentionConfig{DefaultSpansDays: 365},
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+
+	if _, err := svc.Enforce(context.Background(), false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if purgeRepo.purged[healthy.String()] != 1 {
+		t.Errorf("expected healthy project to be purged once, got %d", purgeRepo.purged[healthy.String()])
+	}
+	if purgeRepo.purged[deleted.String()] != 1 {
+		t.Errorf("expected deleted project to fall back to defaults, got %d purges", purgeRepo.purged[deleted.String()])
+	}
+	if n := purgeRepo.purged[failing.String()]; n != 0 {
+		t.Errorf("expected project with failed lookup to be skipped, got %d purges", n)
+	}
+}
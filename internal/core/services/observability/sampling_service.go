@@ -0,0 +1,291 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/lib/pq"
+
+	"brokle/internal/config"
+	"brokle/internal/core/domain/observability"
+	appErrors "brokle/pkg/errors"
+	"brokle/pkg/ulid"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// Policies are cached per project because head sampling runs on every OTLP
+	// request. Changes made through this service invalidate immediately; other
+	// processes pick them up after the TTL.
+	samplingPolicyCacheTTL   = 30 * time.Second
+	samplingPolicyCacheSize  = 10000
+	samplingMatcherCacheSize = 1000
+
+	// SampleRatePayloadKey is the converted span field carrying the recorded sample rate.
+	SampleRatePayloadKey = "sample_rate"
+)
+
+type samplingPolicyCacheEntry struct {
+	policy    *observability.SamplingPolicy
+	expiresAt time.Time
+}
+
+// SamplingService manages per-project sampling policies and applies them at ingestion.
+// Head sampling runs in the OTLP handlers; tail sampling in the telemetry stream consumer.
+type SamplingService struct {
+	policyRepo observability.SamplingPolicyRepository
+	buffer     observability.TailSamplingBuffer
+	config     *config.SamplingConfig
+	logger     *slog.Logger
+	policies   *lru.Cache[ulid.ULID, *samplingPolicyCacheEntry]
+	matchers   *lru.Cache[string, *SpanMatcher]
+}
+
+// NewSamplingService creates a new sampling service.
+func NewSamplingService(
+	policyRepo observability.SamplingPolicyRepository,
+	buffer observability.TailSamplingBuffer,
+	cfg *config.SamplingConfig,
+	logger *slog.Logger,
+) *SamplingService {
+	policies, _ := lru.New[ulid.ULID, *samplingPolicyCacheEntry](samplingPolicyCacheSize)
+	matchers, _ := lru.New[string, *SpanMatcher](samplingMatcherCacheSize)
+
+	return &SamplingService{
+		policyRepo: policyRepo,
+		buffer:     buffer,
+		config:     cfg,
+		logger:     logger,
+		policies:   policies,
+		matchers:   matchers,
+	}
+}
+
+// Enabled returns true if sampling policies are applied at ingestion.
+func (s *SamplingService) Enabled() bool {
+	return s != nil && s.config != nil && s.config.Enabled
+}
+
+// GetProjectSampling returns the project's sampling policy, or the keep-everything default.
+func (s *SamplingService) GetProjectSampling(ctx context.Context, projectID ulid.ULID) (*observability.SamplingPolicy, error) {
+	policy, err := s.findPolicy(s.policyRepo.GetByProject(ctx, projectID))
+	if err != nil {
+		return nil, appErrors.NewInternalError("failed to get sampling policy", err)
+	}
+	if policy == nil {
+		return observability.DefaultSamplingPolicy(projectID), nil
+	}
+	return policy, nil
+}
+
+// UpdateProjectSampling replaces the project's sampling policy.
+func (s *SamplingService) UpdateProjectSampling(ctx context.Context, projectID ulid.ULID, req *observability.UpdateSamplingPolicyRequest) (*observability.SamplingPolicy, error) {
+	policy, err := s.findPolicy(s.policyRepo.GetByProject(ctx, projectID))
+	if err != nil {
+		return nil, appErrors.NewInternalError("failed to get sampling policy", err)
+	}
+	if policy == nil {
+		policy = observability.DefaultSamplingPolicy(projectID)
+		policy.ID = ulid.New()
+	}
+	applySamplingRequest(policy, req)
+
+	if errs := observability.ValidateSamplingPolicy(policy); len(errs) > 0 {
+		return nil, appErrors.NewValidationError(errs[0].Field, errs[0].Message)
+	}
+	if policy.KeepFilter != "" {
+		if _, err := NewSpanMatcher(policy.KeepFilter); err != nil {
+			return nil, appErrors.NewValidationError("invalid filter expression", err.Error())
+		}
+	}
+
+	if err := s.policyRepo.Upsert(ctx, policy); err != nil {
+		s.logger.Error("failed to save sampling policy",
+			"error", err,
+			"project_id", projectID,
+		)
+		return nil, appErrors.NewInternalError("failed to save sampling policy", err)
+	}
+	s.policies.Remove(projectID)
+
+	s.logger.Info("project sampling policy updated",
+		"project_id", projectID,
+		"policy_id", policy.ID,
+		"head_sample_rate", policy.HeadSampleRate,
+		"tail_enabled", policy.TailEnabled,
+	)
+
+	return policy, nil
+}
+
+// DeleteProjectSampling removes the project's policy so every trace is kept.
+func (s *SamplingService) DeleteProjectSampling(ctx context.Context, projectID ulid.ULID) error {
+	if err := s.policyRepo.DeleteByProject(ctx, projectID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return appErrors.NewNotFoundError("sampling policy for project " + projectID.String())
+		}
+		return appErrors.NewInternalError("failed to delete sampling policy", err)
+	}
+	s.policies.Remove(projectID)
+
+	s.logger.Info("project sampling policy deleted", "project_id", projectID)
+	return nil
+}
+
+// PolicyFor returns the cached policy in effect for ingestion. It fails open:
+// when sampling is disabled or the lookup fails, nothing is sampled.
+func (s *SamplingService) PolicyFor(ctx context.Context, projectID ulid.ULID) *observability.SamplingPolicy {
+	if !s.Enabled() {
+		return nil
+	}
+
+	if entry, ok := s.policies.Get(projectID); ok && time.Now().Before(entry.expiresAt) {
+		return entry.policy
+	}
+
+	policy, err := s.findPolicy(s.policyRepo.GetByProject(ctx, projectID))
+	if err != nil {
+		s.logger.Warn("failed to load sampling policy, keeping all spans",
+			"error", err,
+			"project_id", projectID,
+		)
+		return nil
+	}
+
+	s.policies.Add(projectID, &samplingPolicyCacheEntry{
+		policy:    policy,
+		expiresAt: time.Now().Add(samplingPolicyCacheTTL),
+	})
+	return policy
+}
+
+// ApplyHeadSampling drops events of traces outside the project's head sample and
+// records the head rate on kept spans. Returns the kept events and the number dropped.
+func (s *SamplingService) ApplyHeadSampling(ctx context.Context, projectID ulid.ULID, events []*observability.TelemetryEventRequest) ([]*observability.TelemetryEventRequest, int) {
+	policy := s.PolicyFor(ctx, projectID)
+	if !policy.HeadSamples() {
+		return events, 0
+	}
+
+	kept := make([]*observability.TelemetryEventRequest, 0, len(events))
+	for _, event := range events {
+		if !policy.KeepHead(event.TraceID) {
+			continue
+		}
+		if event.EventType == observability.TelemetryEventTypeSpan {
+			ScalePayloadSampleRate(event.Payload, policy.HeadSampleRate)
+		}
+		kept = append(kept, event)
+	}
+	return kept, len(events) - len(kept)
+}
+
+// DecideTail applies the policy's keep rules to a buffered trace.
+func (s *SamplingService) DecideTail(policy *observability.SamplingPolicy, traceID string, spans []*observability.Span) *observability.TailDecision {
+	return policy.DecideTail(traceID, spans, func(spans []*observability.Span) bool {
+		matcher, err := s.matcher(policy.KeepFilter)
+		if err != nil {
+			s.logger.Warn("invalid sampling keep filter",
+				"error", err,
+				"project_id", policy.ProjectID,
+			)
+			return false
+		}
+		return matcher.MatchesTrace(spans)
+	})
+}
+
+// BufferSpans holds spans of an undecided trace until the policy's decision wait elapses.
+// The wait starts with the first buffered span of the trace.
+func (s *SamplingService) BufferSpans(ctx context.Context, policy *observability.SamplingPolicy, traceID string, spans []*observability.BufferedSpan) error {
+	return s.buffer.Add(ctx, policy.ProjectID, traceID, spans, time.Now().Add(policy.DecisionWait()))
+}
+
+// ClaimDueTraces returns buffered traces ready for a tail decision.
+func (s *SamplingService) ClaimDueTraces(ctx context.Context, limit int) ([]*observability.BufferedTrace, error) {
+	return s.buffer.ClaimDue(ctx, time.Now(), limit)
+}
+
+// RecordDecision stores a tail decision so late spans of the trace follow it.
+func (s *SamplingService) RecordDecision(ctx context.Context, projectID ulid.ULID, traceID string, decision *observability.TailDecision) error {
+	return s.buffer.SetDecision(ctx, projectID, traceID, decision, s.decisionTTL())
+}
+
+// GetDecisions returns recorded tail decisions for the given traces.
+func (s *SamplingService) GetDecisions(ctx context.Context, projectID ulid.ULID, traceIDs []string) (map[string]*observability.TailDecision, error) {
+	return s.buffer.GetDecisions(ctx, projectID, traceIDs)
+}
+
+func (s *SamplingService) decisionTTL() time.Duration {
+	if s.config == nil || s.config.DecisionTTLMinutes <= 0 {
+		return time.Hour
+	}
+	return time.Duration(s.config.DecisionTTLMinutes) * time.Minute
+}
+
+func (s *SamplingService) matcher(filter string) (*SpanMatcher, error) {
+	if matcher, ok := s.matchers.Get(filter); ok {
+		return matcher, nil
+	}
+	matcher, err := NewSpanMatcher(filter)
+	if err != nil {
+		return nil, err
+	}
+	s.matchers.Add(filter, matcher)
+	return matcher, nil
+}
+
+// findPolicy maps a missing policy to nil.
+func (s *SamplingService) findPolicy(policy *observability.SamplingPolicy, err error) (*observability.SamplingPolicy, error) {
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return policy, nil
+}
+
+func applySamplingRequest(policy *observability.SamplingPolicy, req *observability.UpdateSamplingPolicyRequest) {
+	policy.HeadSampleRate = 1
+	if req.HeadSampleRate != nil {
+		policy.HeadSampleRate = *req.HeadSampleRate
+	}
+	policy.TailEnabled = req.TailEnabled
+	policy.DecisionWaitSeconds = observability.SamplingDefaultDecisionWaitSeconds
+	if req.DecisionWaitSeconds != nil {
+		policy.DecisionWaitSeconds = *req.DecisionWaitSeconds
+	}
+	policy.TailSampleRate = 1
+	if req.TailSampleRate != nil {
+		policy.TailSampleRate = *req.TailSampleRate
+	}
+	policy.KeepErrors = true
+	if req.KeepErrors != nil {
+		policy.KeepErrors = *req.KeepErrors
+	}
+	policy.KeepMinCost = req.KeepMinCost
+	policy.KeepMinDurationMs = req.KeepMinDurationMs
+	policy.KeepTags = pq.StringArray(req.KeepTags)
+	if policy.KeepTags == nil {
+		policy.KeepTags = pq.StringArray{}
+	}
+	policy.KeepFilter = req.KeepFilter
+}
+
+// ScalePayloadSampleRate multiplies the sample rate recorded on a converted span
+// payload by rate. Spans without a recorded rate start at 1.
+func ScalePayloadSampleRate(payload map[string]any, rate float64) {
+	if payload == nil || rate >= 1 {
+		return
+	}
+	current := 1.0
+	if v, ok := payload[SampleRatePayloadKey].(float64); ok && v > 0 {
+		current = v
+	}
+	payload[SampleRatePayloadKey] = current * rate
+}
@@ -0,0 +1,228 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"brokle/internal/config"
+	obsDomain "brokle/internal/core/domain/observability"
+	"brokle/pkg/ulid"
+)
+
+type mockSamplingPolicyRepository struct {
+	mock.Mock
+}
+
+func (m *mockSamplingPolicyRepository) GetByProject(ctx context.Context, projectID ulid.ULID) (*obsDomain.SamplingPolicy, error) {
+	args := m.Called(ctx, projectID)
+	if v := args.Get(0); v != nil {
+		return v.(*obsDomain.SamplingPolicy), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockSamplingPolicyRepository) Upsert(ctx context.Context, policy *obsDomain.SamplingPolicy) error {
+	return m.Called(ctx, policy).Error(0)
+}
+
+func (m *mockSamplingPolicyRepository) DeleteByProject(ctx context.Context, projectID ulid.ULID) error {
+	return m.Called(ctx, projectID).Error(0)
+}
+
+func newTestSamplingService(repo obsDomain.SamplingPolicyRepository) *SamplingService {
+	return NewSamplingService(repo, nil, &config.SamplingConfig{Enabled: true}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestSamplingPolicy_KeepHead(t *testing.T) {
+	policy := &obsDomain.SamplingPolicy{HeadSampleRate: 0.25}
+
+	kept := 0
+	for i := 0; i < 2000; i++ {
+		traceID := fmt.Sprintf("%032x", i)
+		keep := policy.KeepHead(traceID)
+		assert.Equal(t, keep, policy.KeepHead(traceID), "head sampling must be deterministic per trace")
+		if keep {
+			kept++
+		}
+	}
+	assert.InDelta(t, 500, kept, 100)
+
+	assert.True(t, (&obsDomain.SamplingPolicy{HeadSampleRate: 1}).KeepHead("any"))
+	assert.True(t, (*obsDomain.SamplingPolicy)(nil).KeepHead("any"))
+}
+
+func TestSamplingPolicy_DecideTail(t *testing.T) {
+	start := time.Now()
+	end := start.Add(12 * time.Second)
+	cost := decimal.NewFromFloat(0.5)
+	minCost := decimal.NewFromFloat(0.3)
+	minDuration := int64(10000)
+
+	policy := &obsDomain.SamplingPolicy{
+		TailEnabled:       true,
+		TailSampleRate:    0.000001,
+		KeepErrors:        true,
+		KeepMinCost:       &minCost,
+		KeepMinDurationMs: &minDuration,
+		KeepTags:          []string{"vip"},
+	}
+
+	tests := []struct {
+		name   string
+		span   *obsDomain.Span
+		reason string
+	}{
+		{"error", &obsDomain.Span{StartTime: start, StatusCode: 2}, "error"},
+		{"cost", &obsDomain.Span{StartTime: start, TotalCost: &cost}, "cost"},
+		{"duration", &obsDomain.Span{StartTime: start, EndTime: &end}, "duration"},
+		{"sdk tag", &obsDomain.Span{StartTime: start, SpanAttributes: map[string]string{obsDomain.TraceTagsAttribute: `["beta","vip"]`}}, "tag"},
+		{"comma tags", &obsDomain.Span{StartTime: start, SpanAttributes: map[string]string{obsDomain.TraceTagsAttribute: "beta, vip"}}, "tag"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := policy.DecideTail("trace", []*obsDomain.Span{tt.span}, nil)
+			assert.True(t, decision.Keep)
+			assert.Equal(t, 1.0, decision.SampleRate)
+			assert.Equal(t, tt.reason, decision.Reason)
+		})
+	}
+
+	t.Run("no rule matches", func(t *testing.T) {
+		decision := policy.DecideTail("trace", []*obsDomain.Span{{StartTime: start}}, nil)
+		assert.False(t, decision.Keep)
+	})
+
+	t.Run("keep everything else at full rate", func(t *testing.T) {
+		p := *policy
+		p.TailSampleRate = 1
+		decision := p.DecideTail("trace", []*obsDomain.Span{{StartTime: start}}, nil)
+		assert.True(t, decision.Keep)
+		assert.Equal(t, "sampled", decision.Reason)
+		assert.Equal(t, 1.0, decision.SampleRate)
+	})
+}
+
+func TestSamplingService_DecideTailFilter(t *testing.T) {
+	service := newTestSamplingService(new(mockSamplingPolicyRepository))
+	policy := &obsDomain.SamplingPolicy{
+		TailEnabled:    true,
+		TailSampleRate: 0.000001,
+		KeepFilter:     `gen_ai.request.model="gpt-4o"`,
+	}
+
+	match := []*obsDomain.Span{{SpanAttributes: map[string]string{"gen_ai.request.model": "gpt-4o"}}}
+	decision := service.DecideTail(policy, "trace", match)
+	assert.True(t, decision.Keep)
+	assert.Equal(t, "filter", decision.Reason)
+
+	noMatch := []*obsDomain.Span{{SpanAttributes: map[string]string{"gen_ai.request.model": "gpt-4o-mini"}}}
+	assert.False(t, service.DecideTail(policy, "trace", noMatch).Keep)
+}
+
+func TestSamplingService_ApplyHeadSampling(t *testing.T) {
+	ctx := context.Background()
+	projectID := ulid.New()
+
+	repo := new(mockSamplingPolicyRepository)
+	repo.On("GetByProject", ctx, projectID).Return(&obsDomain.SamplingPolicy{ProjectID: projectID, HeadSampleRate: 0.5}, nil).Once()
+	service := newTestSamplingService(repo)
+
+	var events []*obsDomain.TelemetryEventRequest
+	for i := 0; i < 200; i++ {
+		traceID := fmt.Sprintf("%032x", i)
+		events = append(events, spanEvent(traceID, "a"), spanEvent(traceID, "b"))
+	}
+
+	kept, sampledOut := service.ApplyHeadSampling(ctx, projectID, events)
+	assert.Equal(t, len(events), len(kept)+sampledOut)
+	assert.NotEmpty(t, kept)
+	assert.NotZero(t, sampledOut)
+	for _, event := range kept {
+		assert.Equal(t, 0.5, event.Payload[SampleRatePayloadKey])
+	}
+
+	// Cached policy: the repository is hit once
+	service.ApplyHeadSampling(ctx, projectID, events[:2])
+	repo.AssertExpectations(t)
+}
+
+func TestSamplingService_ApplyHeadSampling_FailsOpen(t *testing.T) {
+	ctx := context.Background()
+	projectID := ulid.New()
+
+	repo := new(mockSamplingPolicyRepository)
+	repo.On("GetByProject", ctx, projectID).Return(nil, fmt.Errorf("connection refused"))
+	service := newTestSamplingService(repo)
+
+	events := []*obsDomain.TelemetryEventRequest{spanEvent("t1", "s1")}
+	kept, sampledOut := service.ApplyHeadSampling(ctx, projectID, events)
+	assert.Len(t, kept, 1)
+	assert.Zero(t, sampledOut)
+	assert.NotContains(t, kept[0].Payload, SampleRatePayloadKey)
+}
+
+func TestSamplingService_UpdateProjectSampling(t *testing.T) {
+	ctx := context.Background()
+	projectID := ulid.New()
+	rate := 0.1
+
+	t.Run("creates policy with defaults", func(t *testing.T) {
+		repo := new(mockSamplingPolicyRepository)
+		repo.On("GetByProject", ctx, projectID).Return(nil, gorm.ErrRecordNotFound)
+		repo.On("Upsert", ctx, mock.AnythingOfType("*observability.SamplingPolicy")).Return(nil)
+		service := newTestSamplingService(repo)
+
+		policy, err := service.UpdateProjectSampling(ctx, projectID, &obsDomain.UpdateSamplingPolicyRequest{
+			HeadSampleRate: &rate,
+			TailEnabled:    true,
+			KeepTags:       []string{"vip"},
+		})
+		require.NoError(t, err)
+		assert.False(t, policy.ID.IsZero())
+		assert.Equal(t, rate, policy.HeadSampleRate)
+		assert.Equal(t, 1.0, policy.TailSampleRate)
+		assert.Equal(t, obsDomain.SamplingDefaultDecisionWaitSeconds, policy.DecisionWaitSeconds)
+		assert.True(t, policy.KeepErrors)
+	})
+
+	t.Run("rejects invalid rate", func(t *testing.T) {
+		repo := new(mockSamplingPolicyRepository)
+		repo.On("GetByProject", ctx, projectID).Return(nil, gorm.ErrRecordNotFound)
+		service := newTestSamplingService(repo)
+
+		zero := 0.0
+		_, err := service.UpdateProjectSampling(ctx, projectID, &obsDomain.UpdateSamplingPolicyRequest{HeadSampleRate: &zero})
+		assert.Error(t, err)
+		repo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects invalid filter", func(t *testing.T) {
+		repo := new(mockSamplingPolicyRepository)
+		repo.On("GetByProject", ctx, projectID).Return(nil, gorm.ErrRecordNotFound)
+		service := newTestSamplingService(repo)
+
+		_, err := service.UpdateProjectSampling(ctx, projectID, &obsDomain.UpdateSamplingPolicyRequest{KeepFilter: "model = ("})
+		assert.Error(t, err)
+		repo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+	})
+}
+
+func TestScalePayloadSampleRate(t *testing.T) {
+	payload := map[string]any{}
+	ScalePayloadSampleRate(payload, 1)
+	assert.NotContains(t, payload, SampleRatePayloadKey)
+
+	ScalePayloadSampleRate(payload, 0.5)
+	ScalePayloadSampleRate(payload, 0.2)
+	assert.InDelta(t, 0.1, payload[SampleRatePayloadKey], 1e-9)
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"brokle/internal/core/domain/observability"
+	"brokle/internal/core/domain/webhook"
 	appErrors "brokle/pkg/errors"
 	"brokle/pkg/ulid"
 )
@@ -15,15 +16,18 @@ import (
 type ScoreService struct {
 	scoreRepo observability.ScoreRepository
 	traceRepo observability.TraceRepository
+	publisher webhook.Publisher // Optional: emits score.created
 }
 
 func NewScoreService(
 	scoreRepo observability.ScoreRepository,
 	traceRepo observability.TraceRepository,
+	publisher webhook.Publisher,
 ) *ScoreService {
 	return &ScoreService{
 		scoreRepo: scoreRepo,
 		traceRepo: traceRepo,
+		publisher: publisher,
 	}
 }
 
@@ -62,6 +66,8 @@ func (s *ScoreService) CreateScore(ctx context.Context, score *observability.Sco
 		return appErrors.NewInternalError("failed to create score", err)
 	}
 
+	s.publishCreated(ctx, score)
+
 	return nil
 }
 
@@ -219,6 +225,10 @@ func (s *ScoreService) CreateScoreBatch(ctx context.Context, scores []*observabi
 		return appErrors.NewInternalError("failed to create score batch", err)
 	}
 
+	for _, score := range scores {
+		s.publishCreated(ctx, score)
+	}
+
 	return nil
 }
 
@@ -284,3 +294,15 @@ func (s *ScoreService) validateScoreTargets(ctx context.Context, score *observab
 
 	return nil
 }
+
+// publishCreated emits score.created for subscribed webhooks.
+func (s *ScoreService) publishCreated(ctx context.Context, score *observability.Score) {
+	if s.publisher == nil {
+		return
+	}
+	projectID, err := ulid.Parse(score.ProjectID)
+	if err != nil {
+		return
+	}
+	s.publisher.Publish(ctx, projectID, webhook.EventScoreCreated, score)
+}
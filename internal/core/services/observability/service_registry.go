@@ -5,6 +5,8 @@ import (
 	"brokle/internal/config"
 	"brokle/internal/core/domain/analytics"
 	"brokle/internal/core/domain/observability"
+	"brokle/internal/core/domain/organization"
+	"brokle/internal/core/domain/webhook"
 	storageDomain "brokle/internal/core/domain/storage"
 	infraStorage "brokle/internal/infrastructure/storage"
 	"brokle/internal/infrastructure/streams"
@@ -20,8 +22,15 @@ type ServiceRegistry struct {
 	GenAIEventsService    *GenAIEventsService
 	BlobStorageService    storageDomain.BlobStorageService
 	ArchiveService        *ArchiveService
+	RehydrationService    *RehydrationService
 	SpanQueryService      *SpanQueryService
 	FilterPresetService   *FilterPresetService
+	RetentionService      *RetentionService
+	AlertService          *AlertService
+	SamplingService       *SamplingService
+	RedactionService      *RedactionService
+	DataSubjectService    *DataSubjectService
+	ShareLinkService      *ShareLinkService // Set by the server provider; nil in workers
 
 	OTLPConverterService        *OTLPConverterService
 	OTLPMetricsConverterService *OTLPMetricsConverterService
@@ -41,9 +50,21 @@ func NewServiceRegistry(
 	logsRepo observability.LogsRepository,
 	genaiEventsRepo observability.GenAIEventsRepository,
 	filterPresetRepo observability.FilterPresetRepository,
+	retentionPolicyRepo observability.RetentionPolicyRepository,
+	retentionPurgeRepo observability.RetentionPurgeRepository,
+	alertRuleRepo observability.AlertRuleRepository,
+	alertEventRepo observability.AlertEventRepository,
+	samplingPolicyRepo observability.SamplingPolicyRepository,
+	tailSamplingBuffer observability.TailSamplingBuffer,
+	redactionPolicyRepo observability.RedactionPolicyRepository,
+	dataSubjectRequestRepo observability.DataSubjectRequestRepository,
+	dataSubjectTelemetryRepo observability.DataSubjectTelemetryRepository,
+	dataSubjectRecordRepo observability.DataSubjectRecordRepository,
+	projectRepo organization.ProjectRepository,
 	blobStorageService storageDomain.BlobStorageService,
 	s3Client *infraStorage.S3Client,
 	archiveConfig *config.ArchiveConfig,
+	blobStorageConfig *config.BlobStorageConfig,
 
 	streamProducer *streams.TelemetryStreamProducer,
 	deduplicationService observability.TelemetryDeduplicationService,
@@ -51,21 +72,39 @@ func NewServiceRegistry(
 	telemetryService observability.TelemetryService,
 	providerPricingService analytics.ProviderPricingService,
 	observabilityConfig *config.ObservabilityConfig,
+	retentionConfig *config.RetentionConfig,
+	samplingConfig *config.SamplingConfig,
+	encryptionConfig *config.EncryptionConfig,
 
+	webhookPublisher webhook.Publisher,
 	logger *slog.Logger,
 ) *ServiceRegistry {
-	otlpConverterService := NewOTLPConverterService(logger, providerPricingService, observabilityConfig)
+	redactionService := NewRedactionService(redactionPolicyRepo, []byte(encryptionConfig.AIKeyEncryptionKey), logger)
+	otlpConverterService := NewOTLPConverterService(logger, providerPricingService, observabilityConfig, redactionService)
 	otlpMetricsConverterService := NewOTLPMetricsConverterService(logger)
 	otlpLogsConverterService := NewOTLPLogsConverterService(logger)
 	otlpEventsConverterService := NewOTLPEventsConverterService(logger)
 	traceService := NewTraceService(traceRepo, logger)
-	scoreService := NewScoreService(scoreRepo, traceRepo)
+	scoreService := NewScoreService(scoreRepo, traceRepo, webhookPublisher)
 	scoreAnalyticsService := NewScoreAnalyticsService(scoreAnalyticsRepo, logger)
 	metricsService := NewMetricsService(metricsRepo, logger)
 	logsService := NewLogsService(logsRepo, logger)
 	genaiEventsService := NewGenAIEventsService(genaiEventsRepo, logger)
-	spanQueryService := NewSpanQueryService(traceRepo, logger)
+	spanQueryService := NewSpanQueryService(traceRepo, blobStorageConfig, archiveConfig, logger)
 	filterPresetService := NewFilterPresetService(filterPresetRepo, logger)
+	retentionService := NewRetentionService(retentionPolicyRepo, retentionPurgeRepo, projectRepo, retentionConfig, logger)
+	alertService := NewAlertService(alertRuleRepo, alertEventRepo, filterPresetRepo, traceRepo, logger)
+	samplingService := NewSamplingService(samplingPolicyRepo, tailSamplingBuffer, samplingConfig, logger)
+	dataSubjectService := NewDataSubjectService(
+		dataSubjectRequestRepo,
+		dataSubjectTelemetryRepo,
+		dataSubjectRecordRepo,
+		traceRepo,
+		scoreRepo,
+		s3Client,
+		archiveConfig,
+		logger,
+	)
 
 	var archiveService *ArchiveService
 	if archiveConfig != nil && archiveConfig.Enabled && s3Client != nil {
@@ -74,6 +113,25 @@ func NewServiceRegistry(
 		logger.Info("Archive service initialized for S3 raw telemetry archival", "bucket", s3Client.GetBucketName(), "path_prefix", archiveConfig.PathPrefix, "compression_level", archiveConfig.CompressionLevel)
 	}
 
+	// Rehydration only needs S3 access, so previously archived data stays restorable
+	// after archival is disabled.
+	var rehydrationService *RehydrationService
+	if archiveConfig != nil && s3Client != nil {
+		rehydrationService = NewRehydrationService(
+			s3Client,
+			NewParquetWriter(archiveConfig.CompressionLevel),
+			traceRepo,
+			projectRepo,
+			traceService,
+			NewScoreService(scoreRepo, traceRepo, nil), // Restored scores are not new events, so no webhooks
+			metricsService,
+			logsService,
+			genaiEventsService,
+			archiveConfig,
+			logger,
+		)
+	}
+
 	return &ServiceRegistry{
 		TraceService:                traceService,
 		ScoreService:                scoreService,
@@ -83,8 +141,14 @@ func NewServiceRegistry(
 		GenAIEventsService:          genaiEventsService,
 		BlobStorageService:          blobStorageService,
 		ArchiveService:              archiveService,
+		RehydrationService:          rehydrationService,
 		SpanQueryService:            spanQueryService,
 		FilterPresetService:         filterPresetService,
+		RetentionService:            retentionService,
+		AlertService:                alertService,
+		SamplingService:             samplingService,
+		RedactionService:            redactionService,
+		DataSubjectService:          dataSubjectService,
 		OTLPConverterService:        otlpConverterService,
 		OTLPMetricsConverterService: otlpMetricsConverterService,
 		OTLPLogsConverterService:    otlpLogsConverterService,
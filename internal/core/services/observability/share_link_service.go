@@ -0,0 +1,370 @@
+package observability
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	authDomain "brokle/internal/core/domain/auth"
+	"brokle/internal/core/domain/observability"
+	"brokle/internal/core/domain/organization"
+	appErrors "brokle/pkg/errors"
+	"brokle/pkg/ulid"
+)
+
+const (
+	// shareTokenContext separates share link signatures from other uses of the server secret
+	shareTokenContext = "brokle.trace-share."
+
+	auditActionShareViewed = "trace_share.viewed"
+	auditResourceShareLink = "trace_share_link"
+)
+
+// Span attributes holding prompt and completion content, hidden on redacted links
+var (
+	shareInputAttributes = map[string]bool{
+		AttrVercelPromptMessages:    true,
+		AttrVercelPrompt:            true,
+		AttrVercelToolCallArgs:      true,
+		AttrGenAIInputMessages:      true,
+		AttrGenAISystemInstructions: true,
+		AttrInputValue:              true,
+		"gen_ai.prompt":             true,
+	}
+	shareOutputAttributes = map[string]bool{
+		AttrVercelResponseText:      true,
+		AttrVercelResponseToolCalls: true,
+		AttrVercelResponseObject:    true,
+		AttrVercelResultText:        true,
+		AttrVercelResultObject:      true,
+		AttrVercelResultToolCalls:   true,
+		AttrGenAIOutputMessages:     true,
+		AttrOutputValue:             true,
+		"gen_ai.completion":         true,
+	}
+)
+
+// ShareLinkService mints and serves public, read-only links to traces and sessions.
+type ShareLinkService struct {
+	linkRepo     observability.TraceShareLinkRepository
+	traceService observability.TraceService
+	auditRepo    authDomain.AuditLogRepository
+	projectRepo  organization.ProjectRepository
+	signingKey   []byte
+	appURL       string
+	logger       *slog.Logger
+}
+
+// NewShareLinkService creates a new share link service. Tokens are signed with signingKey;
+// links point to appURL when it is set.
+func NewShareLinkService(
+	linkRepo observability.TraceShareLinkRepository,
+	traceService observability.TraceService,
+	auditRepo authDomain.AuditLogRepository,
+	projectRepo organization.ProjectRepository,
+	signingKey string,
+	appURL string,
+	logger *slog.Logger,
+) *ShareLinkService {
+	return &ShareLinkService{
+		linkRepo:     linkRepo,
+		traceService: traceService,
+		auditRepo:    auditRepo,
+		projectRepo:  projectRepo,
+		signingKey:   []byte(signingKey),
+		appURL:       strings.TrimRight(appURL, "/"),
+		logger:       logger,
+	}
+}
+
+// Create shares a trace or session of the project.
+func (s *ShareLinkService) Create(ctx context.Context, projectID, userID ulid.ULID, req *observability.CreateShareLinkRequest) (*observability.ShareLinkResponse, error) {
+	if req.ResourceType != observability.ShareResourceTrace && req.ResourceType != observability.ShareResourceSession {
+		return nil, appErrors.NewValidationError("Invalid resource_type", "resource_type must be trace or session")
+	}
+	if strings.TrimSpace(req.ResourceID) == "" {
+		return nil, appErrors.NewValidationError("resource_id is required", "resource_id cannot be empty")
+	}
+
+	now := time.Now()
+	link := &observability.TraceShareLink{
+		ID:            ulid.New(),
+		ProjectID:     projectID,
+		ResourceType:  req.ResourceType,
+		ResourceID:    strings.TrimSpace(req.ResourceID),
+		RedactInputs:  req.RedactInputs,
+		RedactOutputs: req.RedactOutputs,
+		CreatedBy:     userID,
+	}
+	if req.ExpiresInHours != nil {
+		hours := *req.ExpiresInHours
+		if hours < 1 || hours > observability.ShareLinkMaxExpiryHours {
+			return nil, appErrors.NewValidationError("Invalid expires_in_hours", fmt.Sprintf("expires_in_hours must be between 1 and %d", observability.ShareLinkMaxExpiryHours))
+		}
+		expiresAt := now.Add(time.Duration(hours) * time.Hour)
+		link.ExpiresAt = &expiresAt
+	}
+
+	// Only resources of the project can be shared
+	traces, err := s.loadTraces(ctx, link)
+	if err != nil {
+		return nil, err
+	}
+	if len(traces) == 0 {
+		return nil, appErrors.NewNotFoundError(string(link.ResourceType) + " " + link.ResourceID)
+	}
+
+	if err := s.linkRepo.Create(ctx, link); err != nil {
+		return nil, appErrors.NewInternalError("failed to create share link", err)
+	}
+
+	s.logger.Info("share link created",
+		"share_link_id", link.ID.String(),
+		"project_id", projectID.String(),
+		"resource_type", link.ResourceType,
+		"resource_id", link.ResourceID,
+		"created_by", userID.String(),
+	)
+
+	return s.toResponse(link, now), nil
+}
+
+// List returns the project's share links, newest first.
+func (s *ShareLinkService) List(ctx context.Context, projectID ulid.ULID) ([]*observability.ShareLinkResponse, error) {
+	links, err := s.linkRepo.ListByProject(ctx, projectID)
+	if err != nil {
+		return nil, appErrors.NewInternalError("failed to list share links", err)
+	}
+
+	now := time.Now()
+	result := make([]*observability.ShareLinkResponse, len(links))
+	for i, link := range links {
+		result[i] = s.toResponse(link, now)
+	}
+	return result, nil
+}
+
+// Revoke disables a share link immediately.
+func (s *ShareLinkService) Revoke(ctx context.Context, projectID, linkID ulid.ULID) error {
+	link, err := s.linkRepo.GetByID(ctx, linkID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return appErrors.NewNotFoundError("share link")
+		}
+		return appErrors.NewInternalError("failed to get share link", err)
+	}
+	if link.ProjectID != projectID {
+		return appErrors.NewNotFoundError("share link")
+	}
+
+	if err := s.linkRepo.Revoke(ctx, linkID, time.Now()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return appErrors.NewConflictError("share link is already revoked")
+		}
+		return appErrors.NewInternalError("failed to revoke share link", err)
+	}
+
+	s.logger.Info("share link revoked", "share_link_id", linkID.String(), "project_id", projectID.String())
+	return nil
+}
+
+// View resolves a share token to the shared traces and records the view.
+// Invalid, revoked and expired links are indistinguishable to the viewer.
+func (s *ShareLinkService) View(ctx context.Context, token string, viewer observability.ShareViewer) (*observability.SharedView, error) {
+	notFound := appErrors.NewNotFoundError("share link")
+
+	linkID, ok := s.verifyToken(token)
+	if !ok {
+		return nil, notFound
+	}
+
+	link, err := s.linkRepo.GetByID(ctx, linkID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, notFound
+		}
+		return nil, appErrors.NewInternalError("failed to get share link", err)
+	}
+
+	now := time.Now()
+	if !link.IsActive(now) {
+		return nil, notFound
+	}
+
+	traces, err := s.loadTraces(ctx, link)
+	if err != nil {
+		return nil, err
+	}
+	if len(traces) == 0 {
+		// The shared data was deleted or has aged out of retention
+		return nil, notFound
+	}
+	if link.Redacts() {
+		for _, trace := range traces {
+			redactSpans(trace.Spans, link.RedactInputs, link.RedactOutputs)
+		}
+	}
+
+	if err := s.linkRepo.RecordView(ctx, link.ID, now); err != nil {
+		s.logger.Warn("failed to record share link view", "error", err, "share_link_id", link.ID.String())
+	}
+	s.auditView(ctx, link, viewer)
+
+	return &observability.SharedView{
+		ResourceType:  link.ResourceType,
+		ResourceID:    link.ResourceID,
+		ExpiresAt:     link.ExpiresAt,
+		RedactInputs:  link.RedactInputs,
+		RedactOutputs: link.RedactOutputs,
+		Traces:        traces,
+	}, nil
+}
+
+// Token returns the signed token of a link.
+func (s *ShareLinkService) Token(linkID ulid.ULID) string {
+	return linkID.String() + "." + base64.RawURLEncoding.EncodeToString(s.sign(linkID))
+}
+
+func (s *ShareLinkService) verifyToken(token string) (ulid.ULID, bool) {
+	id, signature, found := strings.Cut(token, ".")
+	if !found {
+		return ulid.ULID{}, false
+	}
+	linkID, err := ulid.Parse(id)
+	if err != nil {
+		return ulid.ULID{}, false
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(decoded, s.sign(linkID)) {
+		return ulid.ULID{}, false
+	}
+	return linkID, true
+}
+
+func (s *ShareLinkService) sign(linkID ulid.ULID) []byte {
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write([]byte(shareTokenContext + linkID.String()))
+	return mac.Sum(nil)
+}
+
+func (s *ShareLinkService) toResponse(link *observability.TraceShareLink, now time.Time) *observability.ShareLinkResponse {
+	token := s.Token(link.ID)
+	resp := &observability.ShareLinkResponse{
+		TraceShareLink: link,
+		Token:          token,
+		Active:         link.IsActive(now),
+	}
+	if s.appURL != "" {
+		resp.URL = s.appURL + "/share/" + token
+	}
+	return resp
+}
+
+// loadTraces returns the shared traces, restricted to the link's project
+func (s *ShareLinkService) loadTraces(ctx context.Context, link *observability.TraceShareLink) ([]*observability.SharedTrace, error) {
+	projectID := link.ProjectID.String()
+
+	var summaries []*observability.TraceSummary
+	switch link.ResourceType {
+	case observability.ShareResourceTrace:
+		summary, err := s.traceService.GetTrace(ctx, link.ResourceID)
+		if err != nil {
+			if appErrors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		summaries = append(summaries, summary)
+	case observability.ShareResourceSession:
+		sessionTraces, err := s.traceService.GetTracesBySession(ctx, link.ResourceID)
+		if err != nil {
+			return nil, err
+		}
+		summaries = sessionTraces
+	default:
+		return nil, nil
+	}
+
+	traces := make([]*observability.SharedTrace, 0, len(summaries))
+	for _, summary := range summaries {
+		if summary.ProjectID != projectID {
+			continue
+		}
+		if len(traces) == observability.ShareLinkMaxSessionTraces {
+			break
+		}
+
+		spans, err := s.traceService.GetTraceSpans(ctx, summary.TraceID)
+		if err != nil {
+			return nil, err
+		}
+		projectSpans := make([]*observability.Span, 0, len(spans))
+		for _, span := range spans {
+			if span.ProjectID == projectID {
+				projectSpans = append(projectSpans, span)
+			}
+		}
+		traces = append(traces, &observability.SharedTrace{Trace: summary, Spans: projectSpans})
+	}
+	return traces, nil
+}
+
+// auditView writes an audit entry per view; failures never block the viewer
+func (s *ShareLinkService) auditView(ctx context.Context, link *observability.TraceShareLink, viewer observability.ShareViewer) {
+	var orgID *ulid.ULID
+	if project, err := s.projectRepo.GetByID(ctx, link.ProjectID); err == nil {
+		orgID = &project.OrganizationID
+	}
+
+	metadata, _ := json.Marshal(map[string]interface{}{
+		"project_id":     link.ProjectID.String(),
+		"resource_type":  link.ResourceType,
+		"resource_id":    link.ResourceID,
+		"redact_inputs":  link.RedactInputs,
+		"redact_outputs": link.RedactOutputs,
+	})
+
+	auditLog := authDomain.NewAuditLog(nil, orgID, auditActionShareViewed, auditResourceShareLink, link.ID.String(),
+		string(metadata), viewer.IPAddress, viewer.UserAgent)
+	if err := s.auditRepo.Create(ctx, auditLog); err != nil {
+		s.logger.Error("failed to create audit log for share link view", "error", err, "share_link_id", link.ID.String())
+	}
+}
+
+// redactSpans hides span inputs and outputs in place, including framework
+// attributes and GenAI message events that carry the same content
+func redactSpans(spans []*observability.Span, inputs, outputs bool) {
+	redacted := observability.ShareRedactedValue
+	for _, span := range spans {
+		if inputs && span.Input != nil {
+			span.Input = &redacted
+		}
+		if outputs && span.Output != nil {
+			span.Output = &redacted
+		}
+
+		for key := range span.SpanAttributes {
+			if (inputs && shareInputAttributes[key]) || (outputs && shareOutputAttributes[key]) {
+				span.SpanAttributes[key] = redacted
+			}
+		}
+
+		for i := range span.Events {
+			name := span.Events[i].Name
+			isInput := strings.HasPrefix(name, "gen_ai.") && strings.HasSuffix(name, ".message")
+			isOutput := name == "gen_ai.choice"
+			if (inputs && isInput) || (outputs && isOutput) {
+				span.Events[i].Attributes = map[string]string{"content": redacted}
+			}
+		}
+	}
+}
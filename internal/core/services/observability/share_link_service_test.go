@@ -0,0 +1,234 @@
+package observability
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	authDomain "brokle/internal/core/domain/auth"
+	obsDomain "brokle/internal/core/domain/observability"
+	"brokle/internal/core/domain/organization"
+	appErrors "brokle/pkg/errors"
+	"brokle/pkg/ulid"
+)
+
+type mockShareLinkRepository struct {
+	mock.Mock
+}
+
+func (m *mockShareLinkRepository) Create(ctx context.Context, link *obsDomain.TraceShareLink) error {
+	return m.Called(ctx, link).Error(0)
+}
+
+func (m *mockShareLinkRepository) GetByID(ctx context.Context, id ulid.ULID) (*obsDomain.TraceShareLink, error) {
+	args := m.Called(ctx, id)
+	if v := args.Get(0); v != nil {
+		return v.(*obsDomain.TraceShareLink), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockShareLinkRepository) ListByProject(ctx context.Context, projectID ulid.ULID) ([]*obsDomain.TraceShareLink, error) {
+	args := m.Called(ctx, projectID)
+	return args.Get(0).([]*obsDomain.TraceShareLink), args.Error(1)
+}
+
+func (m *mockShareLinkRepository) Revoke(ctx context.Context, id ulid.ULID, at time.Time) error {
+	return m.Called(ctx, id, at).Error(0)
+}
+
+func (m *mockShareLinkRepository) RecordView(ctx context.Context, id ulid.ULID, at time.Time) error {
+	return m.Called(ctx, id, at).Error(0)
+}
+
+// shareTraceService serves a fixed set of traces; other TraceService methods are not used
+type shareTraceService struct {
+	obsDomain.TraceService
+	summaries map[string]*obsDomain.TraceSummary
+	spans     map[string][]*obsDomain.Span
+}
+
+func (s *shareTraceService) GetTrace(ctx context.Context, traceID string) (*obsDomain.TraceSummary, error) {
+	if summary, ok := s.summaries[traceID]; ok {
+		return summary, nil
+	}
+	return nil, appErrors.NewNotFoundError("trace " + traceID)
+}
+
+func (s *shareTraceService) GetTraceSpans(ctx context.Context, traceID string) ([]*obsDomain.Span, error) {
+	return s.spans[traceID], nil
+}
+
+func (s *shareTraceService) GetTracesBySession(ctx context.Context, sessionID string) ([]*obsDomain.TraceSummary, error) {
+	var result []*obsDomain.TraceSummary
+	for _, summary := range s.summaries {
+		if summary.SessionID != nil && *summary.SessionID == sessionID {
+			result = append(result, summary)
+		}
+	}
+	return result, nil
+}
+
+type recordingAuditRepository struct {
+	authDomain.AuditLogRepository
+	logs []*authDomain.AuditLog
+}
+
+func (r *recordingAuditRepository) Create(ctx context.Context, auditLog *authDomain.AuditLog) error {
+	r.logs = append(r.logs, auditLog)
+	return nil
+}
+
+type shareProjectRepository struct {
+	organization.ProjectRepository
+	orgID ulid.ULID
+}
+
+func (r *shareProjectRepository) GetByID(ctx context.Context, id ulid.ULID) (*organization.Project, error) {
+	return &organization.Project{ID: id, OrganizationID: r.orgID}, nil
+}
+
+func shareFixture(projectID ulid.ULID) *shareTraceService {
+	input, output := "what is my password?", "hunter2"
+	session := "session-1"
+	const traceID = "0123456789abcdef0123456789abcdef"
+	return &shareTraceService{
+		summaries: map[string]*obsDomain.TraceSummary{
+			traceID:                            {TraceID: traceID, ProjectID: projectID.String(), SessionID: &session},
+			"ffffffffffffffffffffffffffffffff": {TraceID: "ffffffffffffffffffffffffffffffff", ProjectID: ulid.New().String(), SessionID: &session},
+		},
+		spans: map[string][]*obsDomain.Span{
+			traceID: {{
+				TraceID:        traceID,
+				SpanID:         "span-1",
+				ProjectID:      projectID.String(),
+				Input:          &input,
+				Output:         &output,
+				SpanAttributes: map[string]string{AttrGenAIInputMessages: input, AttrOutputValue: output, "gen_ai.request.model": "gpt-4o"},
+				Events:         []obsDomain.SpanEvent{{Name: "gen_ai.user.message", Attributes: map[string]string{"content": input}}},
+			}},
+		},
+	}
+}
+
+func newTestShareLinkService(repo obsDomain.TraceShareLinkRepository, traces obsDomain.TraceService, audit authDomain.AuditLogRepository) *ShareLinkService {
+	return NewShareLinkService(repo, traces, audit, &shareProjectRepository{orgID: ulid.New()},
+		"test-secret-test-secret-test-secret", "https://app.example.com/", slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestShareLinkService_Create(t *testing.T) {
+	ctx := context.Background()
+	projectID, userID := ulid.New(), ulid.New()
+	traces := shareFixture(projectID)
+
+	t.Run("trace in project", func(t *testing.T) {
+		repo := new(mockShareLinkRepository)
+		repo.On("Create", ctx, mock.AnythingOfType("*observability.TraceShareLink")).Return(nil)
+		service := newTestShareLinkService(repo, traces, &recordingAuditRepository{})
+
+		hours := 24
+		link, err := service.Create(ctx, projectID, userID, &obsDomain.CreateShareLinkRequest{
+			ResourceType:   obsDomain.ShareResourceTrace,
+			ResourceID:     "0123456789abcdef0123456789abcdef",
+			ExpiresInHours: &hours,
+		})
+		require.NoError(t, err)
+		assert.True(t, link.Active)
+		assert.Equal(t, "https://app.example.com/share/"+link.Token, link.URL)
+		require.NotNil(t, link.ExpiresAt)
+		assert.WithinDuration(t, time.Now().Add(24*time.Hour), *link.ExpiresAt, time.Minute)
+	})
+
+	t.Run("trace of another project", func(t *testing.T) {
+		repo := new(mockShareLinkRepository)
+		service := newTestShareLinkService(repo, traces, &recordingAuditRepository{})
+
+		_, err := service.Create(ctx, projectID, userID, &obsDomain.CreateShareLinkRequest{
+			ResourceType: obsDomain.ShareResourceTrace,
+			ResourceID:   "ffffffffffffffffffffffffffffffff",
+		})
+		assert.True(t, appErrors.IsNotFound(err))
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestShareLinkService_View(t *testing.T) {
+	ctx := context.Background()
+	projectID := ulid.New()
+	viewer := obsDomain.ShareViewer{IPAddress: "203.0.113.7", UserAgent: "curl/8"}
+
+	t.Run("redacted session", func(t *testing.T) {
+		link := &obsDomain.TraceShareLink{
+			ID:            ulid.New(),
+			ProjectID:     projectID,
+			ResourceType:  obsDomain.ShareResourceSession,
+			ResourceID:    "session-1",
+			RedactInputs:  true,
+			RedactOutputs: true,
+		}
+		repo := new(mockShareLinkRepository)
+		repo.On("GetByID", ctx, link.ID).Return(link, nil)
+		repo.On("RecordView", ctx, link.ID, mock.Anything).Return(nil)
+		audit := &recordingAuditRepository{}
+		service := newTestShareLinkService(repo, shareFixture(projectID), audit)
+
+		view, err := service.View(ctx, service.Token(link.ID), viewer)
+		require.NoError(t, err)
+
+		// Only the trace of the link's project is shared
+		require.Len(t, view.Traces, 1)
+		span := view.Traces[0].Spans[0]
+		assert.Equal(t, obsDomain.ShareRedactedValue, *span.Input)
+		assert.Equal(t, obsDomain.ShareRedactedValue, *span.Output)
+		assert.Equal(t, obsDomain.ShareRedactedValue, span.SpanAttributes[AttrGenAIInputMessages])
+		assert.Equal(t, obsDomain.ShareRedactedValue, span.SpanAttributes[AttrOutputValue])
+		assert.Equal(t, "gpt-4o", span.SpanAttributes["gen_ai.request.model"])
+		assert.Equal(t, obsDomain.ShareRedactedValue, span.Events[0].Attributes["content"])
+
+		require.Len(t, audit.logs, 1)
+		assert.Equal(t, "trace_share.viewed", audit.logs[0].Action)
+		assert.Equal(t, link.ID.String(), audit.logs[0].ResourceID)
+		assert.Equal(t, viewer.IPAddress, audit.logs[0].IPAddress)
+		assert.NotNil(t, audit.logs[0].OrganizationID)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("inactive links", func(t *testing.T) {
+		past := time.Now().Add(-time.Hour)
+		for name, link := range map[string]*obsDomain.TraceShareLink{
+			"expired": {ID: ulid.New(), ProjectID: projectID, ExpiresAt: &past},
+			"revoked": {ID: ulid.New(), ProjectID: projectID, RevokedAt: &past},
+		} {
+			t.Run(name, func(t *testing.T) {
+				repo := new(mockShareLinkRepository)
+				repo.On("GetByID", ctx, link.ID).Return(link, nil)
+				audit := &recordingAuditRepository{}
+				service := newTestShareLinkService(repo, shareFixture(projectID), audit)
+
+				_, err := service.View(ctx, service.Token(link.ID), viewer)
+				assert.True(t, appErrors.IsNotFound(err))
+				assert.Empty(t, audit.logs)
+			})
+		}
+	})
+
+	t.Run("invalid tokens", func(t *testing.T) {
+		repo := new(mockShareLinkRepository)
+		repo.On("GetByID", ctx, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+		service := newTestShareLinkService(repo, shareFixture(projectID), &recordingAuditRepository{})
+		other := NewShareLinkService(repo, nil, nil, nil, "another-secret-another-secret-00", "", slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+		for _, token := range []string{"", "not-a-token", ulid.New().String() + ".AAAA", other.Token(ulid.New())} {
+			_, err := service.View(ctx, token, viewer)
+			assert.True(t, appErrors.IsNotFound(err), token)
+		}
+		repo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,58 @@
+package observability
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	obsDomain "brokle/internal/core/domain/observability"
+)
+
+// aggregationPattern matches fn(field), fn() and quantile(level)(field); function names are case-insensitive.
+var aggregationPattern = regexp.MustCompile(`(?i)^([a-z]+)(?:\(\s*([0-9.]+)\s*\))?\(\s*([a-zA-Z_][a-zA-Z0-9_.]*)?\s*\)$`)
+
+// ParseAggregation parses an aggregation expression such as count(), sum(total_cost),
+// uniq(user_id) or quantile(0.95)(duration).
+func ParseAggregation(expr string) (*obsDomain.SpanAggregation, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" || len(expr) > obsDomain.SpanAggregateMaxExpressionLen {
+		return nil, fmt.Errorf("%w: aggregation %q", obsDomain.ErrInvalidValue, expr)
+	}
+
+	m := aggregationPattern.FindStringSubmatch(expr)
+	if m == nil {
+		return nil, fmt.Errorf("%w: invalid aggregation %q", obsDomain.ErrInvalidFilterSyntax, expr)
+	}
+
+	agg := &obsDomain.SpanAggregation{
+		Expression: expr,
+		Function:   obsDomain.AggregateFunction(strings.ToLower(m[1])),
+		Field:      m[3],
+	}
+
+	switch agg.Function {
+	case obsDomain.AggregateCount:
+		if agg.Field != "" || m[2] != "" {
+			return nil, fmt.Errorf("%w: count() takes no arguments", obsDomain.ErrInvalidFilterSyntax)
+		}
+	case obsDomain.AggregateQuantile:
+		level, err := strconv.ParseFloat(m[2], 64)
+		if m[2] == "" || err != nil || level < 0 || level > 1 {
+			return nil, fmt.Errorf("%w: quantile level must be between 0 and 1", obsDomain.ErrInvalidValue)
+		}
+		agg.Quantile = level
+	case obsDomain.AggregateSum, obsDomain.AggregateAvg, obsDomain.AggregateMin, obsDomain.AggregateMax, obsDomain.AggregateUniq:
+		if m[2] != "" {
+			return nil, fmt.Errorf("%w: %s does not take a parameter", obsDomain.ErrInvalidFilterSyntax, agg.Function)
+		}
+	default:
+		return nil, obsDomain.NewUnsupportedOperatorError(string(agg.Function))
+	}
+
+	if agg.Function != obsDomain.AggregateCount && agg.Field == "" {
+		return nil, fmt.Errorf("%w: %s requires a field", obsDomain.ErrMissingValue, agg.Function)
+	}
+
+	return agg, nil
+}
@@ -0,0 +1,132 @@
+package observability
+
+import (
+	"testing"
+	"time"
+
+	obsDomain "brokle/internal/core/domain/observability"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAggregation(t *testing.T) {
+	tests := []struct {
+		expr     string
+		wantFunc obsDomain.AggregateFunction
+		wantFld  string
+		wantQ    float64
+		wantErr  bool
+	}{
+		{expr: "count()", wantFunc: obsDomain.AggregateCount},
+		{expr: "COUNT()", wantFunc: obsDomain.AggregateCount},
+		{expr: "sum(total_cost)", wantFunc: obsDomain.AggregateSum, wantFld: "total_cost"},
+		{expr: "avg( duration )", wantFunc: obsDomain.AggregateAvg, wantFld: "duration"},
+		{expr: "uniq(user_id)", wantFunc: obsDomain.AggregateUniq, wantFld: "user_id"},
+		{expr: "quantile(0.95)(duration)", wantFunc: obsDomain.AggregateQuantile, wantFld: "duration", wantQ: 0.95},
+		{expr: "max(gen_ai.usage.total_tokens)", wantFunc: obsDomain.AggregateMax, wantFld: "gen_ai.usage.total_tokens"},
+		{expr: "", wantErr: true},
+		{expr: "count(span_id)", wantErr: true},
+		{expr: "sum()", wantErr: true},
+		{expr: "quantile(duration)", wantErr: true},
+		{expr: "quantile(1.5)(duration)", wantErr: true},
+		{expr: "avg(0.5)(duration)", wantErr: true},
+		{expr: "median(duration)", wantErr: true},
+		{expr: "sum(total_cost); DROP TABLE otel_traces", wantErr: true},
+		{expr: "sum(span_attributes['x'])", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			agg, err := ParseAggregation(tt.expr)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFunc, agg.Function)
+			assert.Equal(t, tt.wantFld, agg.Field)
+			assert.Equal(t, tt.wantQ, agg.Quantile)
+			assert.Equal(t, tt.expr, agg.Expression)
+		})
+	}
+}
+
+func TestSpanQueryBuilder_BuildAggregateQuery(t *testing.T) {
+	parse := func(exprs ...string) []*obsDomain.SpanAggregation {
+		aggs := make([]*obsDomain.SpanAggregation, len(exprs))
+		for i, expr := range exprs {
+			agg, err := ParseAggregation(expr)
+			require.NoError(t, err)
+			aggs[i] = agg
+		}
+		return aggs
+	}
+	filter := &obsDomain.ConditionNode{Field: "gen_ai.system", Operator: obsDomain.FilterOpEqual, Value: "openai"}
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("group by with quantile and time buckets", func(t *testing.T) {
+		result, err := NewSpanQueryBuilder().BuildAggregateQuery(
+			filter, "proj123", &start, nil,
+			parse("count()", "quantile(0.95)(duration)", "sum(total_cost)", "uniq(user.id)"),
+			[]string{"service_name", "gen_ai.request.model", "custom.team"},
+			time.Hour, 101,
+		)
+		require.NoError(t, err)
+
+		assert.Contains(t, result.Query, "toStartOfInterval(start_time, INTERVAL 3600 SECOND) AS bucket")
+		assert.Contains(t, result.Query, "toString(service_name) AS g0")
+		assert.Contains(t, result.Query, "toString(model_name) AS g1")
+		assert.Contains(t, result.Query, "toString(span_attributes['custom.team']) AS g2")
+		assert.Contains(t, result.Query, "toNullable(toFloat64(count())) AS a0")
+		assert.Contains(t, result.Query, "toNullable(toFloat64(quantile(0.95)(duration_nano))) AS a1")
+		assert.Contains(t, result.Query, "toNullable(toFloat64(sum(total_cost))) AS a2")
+		assert.Contains(t, result.Query, "toNullable(toFloat64(uniq(user_id))) AS a3")
+		assert.Contains(t, result.Query, "WHERE (provider_name = ?)")
+		assert.Contains(t, result.Query, "GROUP BY bucket, g0, g1, g2")
+		assert.Contains(t, result.Query, "ORDER BY bucket ASC, a0 DESC")
+		assert.Equal(t, []interface{}{"proj123", start, "openai", 101}, result.Args)
+	})
+
+	t.Run("attribute aggregates are converted to numbers", func(t *testing.T) {
+		result, err := NewSpanQueryBuilder().BuildAggregateQuery(nil, "proj123", nil, nil, parse("avg(gen_ai.usage.total_tokens)"), nil, 0, 10)
+		require.NoError(t, err)
+		assert.Contains(t, result.Query, "avg(toFloat64OrNull(span_attributes['gen_ai.usage.total_tokens']))")
+		assert.NotContains(t, result.Query, "GROUP BY")
+		assert.NotContains(t, result.Query, "WHERE (")
+		assert.Equal(t, []interface{}{"proj123", 10}, result.Args)
+	})
+
+	t.Run("rejects unsafe group by field", func(t *testing.T) {
+		_, err := NewSpanQueryBuilder().BuildAggregateQuery(nil, "proj123", nil, nil, parse("count()"), []string{"x') OR 1=1 --"}, 0, 10)
+		assert.ErrorIs(t, err, obsDomain.ErrInvalidFieldName)
+	})
+}
+
+func TestParseAggregateInterval(t *testing.T) {
+	tests := []struct {
+		interval string
+		want     time.Duration
+		wantErr  bool
+	}{
+		{interval: "5m", want: 5 * time.Minute},
+		{interval: "1h", want: time.Hour},
+		{interval: "1d", want: 24 * time.Hour},
+		{interval: "2w", want: 14 * 24 * time.Hour},
+		{interval: "0d", wantErr: true},
+		{interval: "-1h", wantErr: true},
+		{interval: "hour", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.interval, func(t *testing.T) {
+			got, err := obsDomain.ParseAggregateInterval(tt.interval)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
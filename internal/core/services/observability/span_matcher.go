@@ -0,0 +1,305 @@
+package observability
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	obsDomain "brokle/internal/core/domain/observability"
+)
+
+// SpanMatcher evaluates a parsed filter expression against the spans of one trace
+// in memory. It mirrors SpanQueryBuilder semantics for spans that are not yet stored,
+// e.g. during tail sampling.
+type SpanMatcher struct {
+	root    obsDomain.FilterNode
+	regexes map[string]*regexp.Regexp
+}
+
+// NewSpanMatcher parses a filter expression into a matcher.
+func NewSpanMatcher(filter string) (*SpanMatcher, error) {
+	root, err := NewFilterParser().Parse(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &SpanMatcher{root: root, regexes: make(map[string]*regexp.Regexp)}
+	if err := m.compile(root); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// compile validates the tree and precompiles regex patterns.
+func (m *SpanMatcher) compile(node obsDomain.FilterNode) error {
+	switch n := node.(type) {
+	case *obsDomain.BinaryNode:
+		if err := m.compile(n.Left); err != nil {
+			return err
+		}
+		return m.compile(n.Right)
+
+	case *obsDomain.TraceNode:
+		if n.Left == nil || (n.Relation.IsBinary() && n.Right == nil) {
+			return fmt.Errorf("%w: %s requires a span filter", obsDomain.ErrMissingValue, n.Relation)
+		}
+		if err := m.compile(n.Left); err != nil {
+			return err
+		}
+		if n.Right != nil {
+			return m.compile(n.Right)
+		}
+		return nil
+
+	case *obsDomain.ConditionNode:
+		if n.Operator != obsDomain.FilterOpRegex && n.Operator != obsDomain.FilterOpNotRegex {
+			return nil
+		}
+		pattern, ok := n.Value.(string)
+		if !ok {
+			return obsDomain.ErrInvalidValue
+		}
+		if err := validateRegexPattern(pattern); err != nil {
+			return err
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("%w: %v", obsDomain.ErrInvalidValue, err)
+		}
+		m.regexes[pattern] = re
+		return nil
+
+	default:
+		return fmt.Errorf("%w: unknown filter node", obsDomain.ErrInvalidFilterSyntax)
+	}
+}
+
+// MatchesTrace returns true if any span of the trace satisfies the filter,
+// matching how trace listing applies span filters.
+func (m *SpanMatcher) MatchesTrace(spans []*obsDomain.Span) bool {
+	for _, span := range spans {
+		if m.matches(m.root, span, spans) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *SpanMatcher) matches(node obsDomain.FilterNode, span *obsDomain.Span, trace []*obsDomain.Span) bool {
+	switch n := node.(type) {
+	case *obsDomain.BinaryNode:
+		if n.Operator == obsDomain.LogicOr {
+			return m.matches(n.Left, span, trace) || m.matches(n.Right, span, trace)
+		}
+		return m.matches(n.Left, span, trace) && m.matches(n.Right, span, trace)
+
+	case *obsDomain.TraceNode:
+		// Trace predicates hold for every span of the trace or none
+		return m.matchesRelation(n, trace)
+
+	case *obsDomain.ConditionNode:
+		return m.matchesCondition(n, span)
+	}
+	return false
+}
+
+func (m *SpanMatcher) matchesRelation(node *obsDomain.TraceNode, trace []*obsDomain.Span) bool {
+	left := m.filterSpans(node.Left, trace)
+
+	switch node.Relation {
+	case obsDomain.TraceRelationHas:
+		return len(left) > 0
+	case obsDomain.TraceRelationCount:
+		return compareNumbers(float64(len(left)), node.Operator, float64(node.Count))
+	}
+
+	right := m.filterSpans(node.Right, trace)
+	if len(left) == 0 || len(right) == 0 {
+		return false
+	}
+
+	leftIDs := make(map[string]struct{}, len(left))
+	for _, span := range left {
+		leftIDs[span.SpanID] = struct{}{}
+	}
+	parents := make(map[string]string, len(trace))
+	for _, span := range trace {
+		if span.ParentSpanID != nil {
+			parents[span.SpanID] = *span.ParentSpanID
+		}
+	}
+
+	for _, r := range right {
+		parentID := parents[r.SpanID]
+		switch node.Relation {
+		case obsDomain.TraceRelationChild:
+			if _, ok := leftIDs[parentID]; ok {
+				return true
+			}
+
+		case obsDomain.TraceRelationDescendant:
+			for depth := 0; parentID != "" && depth < obsDomain.SpanQueryMaxTraceDepth; depth++ {
+				if _, ok := leftIDs[parentID]; ok {
+					return true
+				}
+				parentID = parents[parentID]
+			}
+
+		case obsDomain.TraceRelationSibling:
+			if parentID == "" {
+				continue
+			}
+			for _, l := range left {
+				if l.SpanID != r.SpanID && parents[l.SpanID] == parentID {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func (m *SpanMatcher) filterSpans(node obsDomain.FilterNode, trace []*obsDomain.Span) []*obsDomain.Span {
+	var matched []*obsDomain.Span
+	for _, span := range trace {
+		if m.matches(node, span, trace) {
+			matched = append(matched, span)
+		}
+	}
+	return matched
+}
+
+func (m *SpanMatcher) matchesCondition(node *obsDomain.ConditionNode, span *obsDomain.Span) bool {
+	value, present := spanFieldValue(span, node.Field)
+
+	switch node.Operator {
+	case obsDomain.FilterOpEqual:
+		return present && equalValues(value, node.Value)
+	case obsDomain.FilterOpNotEqual:
+		return !present || !equalValues(value, node.Value)
+
+	case obsDomain.FilterOpGreaterThan, obsDomain.FilterOpLessThan,
+		obsDomain.FilterOpGreaterOrEqual, obsDomain.FilterOpLessOrEqual:
+		actual, err := strconv.ParseFloat(value, 64)
+		if err != nil || !present {
+			return false
+		}
+		expected, ok := toFloat(node.Value)
+		return ok && compareNumbers(actual, node.Operator, expected)
+
+	case obsDomain.FilterOpContains, obsDomain.FilterOpSearch:
+		return strings.Contains(strings.ToLower(value), strings.ToLower(fmt.Sprint(node.Value)))
+	case obsDomain.FilterOpNotContains:
+		return !strings.Contains(strings.ToLower(value), strings.ToLower(fmt.Sprint(node.Value)))
+
+	case obsDomain.FilterOpIn, obsDomain.FilterOpNotIn:
+		values, _ := node.Value.([]string)
+		found := false
+		for _, v := range values {
+			if present && value == v {
+				found = true
+				break
+			}
+		}
+		return found == (node.Operator == obsDomain.FilterOpIn)
+
+	case obsDomain.FilterOpExists:
+		return present && value != ""
+	case obsDomain.FilterOpNotExists:
+		return !present || value == ""
+
+	case obsDomain.FilterOpStartsWith:
+		return strings.HasPrefix(value, fmt.Sprint(node.Value))
+	case obsDomain.FilterOpEndsWith:
+		return strings.HasSuffix(value, fmt.Sprint(node.Value))
+
+	case obsDomain.FilterOpRegex, obsDomain.FilterOpNotRegex:
+		pattern, _ := node.Value.(string)
+		re := m.regexes[pattern]
+		if re == nil {
+			return false
+		}
+		return re.MatchString(value) == (node.Operator == obsDomain.FilterOpRegex)
+
+	case obsDomain.FilterOpIsEmpty:
+		return value == ""
+	case obsDomain.FilterOpIsNotEmpty:
+		return value != ""
+	}
+	return false
+}
+
+// spanFieldValue resolves a filter field the way SpanQueryBuilder.getColumn does:
+// materialized columns first, then resource or span attributes.
+func spanFieldValue(span *obsDomain.Span, field string) (string, bool) {
+	switch field {
+	case "span.name":
+		return span.SpanName, true
+	case "trace.id":
+		return span.TraceID, true
+	case "span.id":
+		return span.SpanID, true
+	case "status.code":
+		return strconv.Itoa(int(span.StatusCode)), true
+	case "service.name":
+		if span.ServiceName != nil {
+			return *span.ServiceName, true
+		}
+		v, ok := span.ResourceAttributes[field]
+		return v, ok
+	case "gen_ai.system", "gen_ai.provider.name":
+		if v, ok := span.SpanAttributes["gen_ai.provider.name"]; ok {
+			return v, true
+		}
+		v, ok := span.SpanAttributes["gen_ai.system"]
+		return v, ok
+	}
+
+	if strings.HasPrefix(field, "resource.") || strings.HasPrefix(field, "deployment.") {
+		v, ok := span.ResourceAttributes[field]
+		return v, ok
+	}
+	v, ok := span.SpanAttributes[field]
+	return v, ok
+}
+
+func equalValues(actual string, expected interface{}) bool {
+	if f, ok := expected.(float64); ok {
+		if v, err := strconv.ParseFloat(actual, 64); err == nil {
+			return v == f
+		}
+	}
+	return actual == fmt.Sprint(expected)
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int64:
+		return float64(n), true
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func compareNumbers(actual float64, op obsDomain.FilterOperator, expected float64) bool {
+	switch op {
+	case obsDomain.FilterOpEqual:
+		return actual == expected
+	case obsDomain.FilterOpNotEqual:
+		return actual != expected
+	case obsDomain.FilterOpGreaterThan:
+		return actual > expected
+	case obsDomain.FilterOpLessThan:
+		return actual < expected
+	case obsDomain.FilterOpGreaterOrEqual:
+		return actual >= expected
+	case obsDomain.FilterOpLessOrEqual:
+		return actual <= expected
+	}
+	return false
+}
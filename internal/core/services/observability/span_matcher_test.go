@@ -0,0 +1,66 @@
+package observability
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	obsDomain "brokle/internal/core/domain/observability"
+)
+
+func matcherSpan(id, parent, name string, attrs map[string]string) *obsDomain.Span {
+	span := &obsDomain.Span{SpanID: id, SpanName: name, SpanAttributes: attrs}
+	if parent != "" {
+		span.ParentSpanID = &parent
+	}
+	return span
+}
+
+func TestSpanMatcher_MatchesTrace(t *testing.T) {
+	trace := []*obsDomain.Span{
+		matcherSpan("root", "", "agent", map[string]string{"brokle.span.type": "agent", "user.id": "u1"}),
+		matcherSpan("llm", "root", "chat", map[string]string{"brokle.span.type": "generation", "gen_ai.request.model": "gpt-4o", "gen_ai.usage.total_tokens": "1500"}),
+		matcherSpan("tool", "llm", "search", map[string]string{"brokle.span.type": "tool"}),
+		matcherSpan("retriever", "root", "retrieve", map[string]string{"brokle.span.type": "retriever"}),
+	}
+
+	tests := []struct {
+		filter string
+		want   bool
+	}{
+		{`gen_ai.request.model="gpt-4o"`, true},
+		{"gen_ai.request.model=claude", false},
+		{"gen_ai.usage.total_tokens > 1000", true},
+		{"gen_ai.usage.total_tokens > 2000", false},
+		{"span.name STARTS WITH sea AND brokle.span.type=tool", true},
+		{"span.name = chat AND brokle.span.type=tool", false},
+		{"user.id EXISTS", true},
+		{`gen_ai.request.model IN ("gpt-4o", "gpt-4o-mini")`, true},
+		{"span.name REGEX '^ret'", true},
+		{"HAS(brokle.span.type=tool)", true},
+		{"COUNT(brokle.span.type EXISTS) >= 4", true},
+		{"COUNT(brokle.span.type EXISTS) > 4", false},
+		{"CHILD(brokle.span.type=agent, brokle.span.type=generation)", true},
+		{"CHILD(brokle.span.type=agent, brokle.span.type=tool)", false},
+		{"DESCENDANT(brokle.span.type=agent, brokle.span.type=tool)", true},
+		{"SIBLING(brokle.span.type=generation, brokle.span.type=retriever)", true},
+		{"SIBLING(brokle.span.type=tool, brokle.span.type=retriever)", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			matcher, err := NewSpanMatcher(tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, matcher.MatchesTrace(trace))
+		})
+	}
+}
+
+func TestNewSpanMatcher_InvalidFilter(t *testing.T) {
+	_, err := NewSpanMatcher("")
+	assert.Error(t, err)
+
+	_, err = NewSpanMatcher("span.name REGEX 'a+b+c+d+e+f+g+h+i+j+k+l+'")
+	assert.Error(t, err)
+}
@@ -3,6 +3,7 @@ package observability
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -41,6 +42,11 @@ func escapeAttributeKey(key string) string {
 // Uses PREWHERE optimization for indexed columns to improve query performance.
 type SpanQueryBuilder struct {
 	paramCount int
+
+	// Scope of trace predicate subqueries, set by each Build* call
+	projectID string
+	startTime *time.Time
+	endTime   *time.Time
 }
 
 // NewSpanQueryBuilder creates a new query builder.
@@ -63,7 +69,7 @@ func (b *SpanQueryBuilder) BuildQuery(
 	startTime, endTime *time.Time,
 	limit, offset int,
 ) (*QueryResult, error) {
-	b.paramCount = 0
+	b.reset(projectID, startTime, endTime)
 
 	whereClause, args, err := b.buildNode(node)
 	if err != nil {
@@ -126,7 +132,7 @@ func (b *SpanQueryBuilder) BuildCountQuery(
 	projectID string,
 	startTime, endTime *time.Time,
 ) (*QueryResult, error) {
-	b.paramCount = 0
+	b.reset(projectID, startTime, endTime)
 
 	whereClause, args, err := b.buildNode(node)
 	if err != nil {
@@ -188,6 +194,8 @@ func (b *SpanQueryBuilder) buildNode(node obsDomain.FilterNode) (string, []inter
 		return b.buildBinaryNode(n)
 	case *obsDomain.ConditionNode:
 		return b.buildConditionNode(n)
+	case *obsDomain.TraceNode:
+		return b.buildTraceNode(n)
 	default:
 		return "", nil, fmt.Errorf("unknown node type: %T", node)
 	}
@@ -286,6 +294,152 @@ func (b *SpanQueryBuilder) buildConditionNode(node *obsDomain.ConditionNode) (st
 	}
 }
 
+// reset clears per-query state and sets the scope used by trace predicate subqueries.
+func (b *SpanQueryBuilder) reset(projectID string, startTime, endTime *time.Time) {
+	b.paramCount = 0
+	b.projectID = projectID
+	b.startTime = startTime
+	b.endTime = endTime
+}
+
+// traceScope returns the conditions restricting trace predicate subqueries to the
+// project and time range of the enclosing query.
+func (b *SpanQueryBuilder) traceScope() (string, []interface{}) {
+	conditions := []string{"project_id = ?", "deleted_at IS NULL"}
+	args := []interface{}{b.projectID}
+
+	if b.startTime != nil {
+		conditions = append(conditions, "start_time >= ?")
+		args = append(args, *b.startTime)
+	}
+	if b.endTime != nil {
+		conditions = append(conditions, "start_time <= ?")
+		args = append(args, *b.endTime)
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// buildTraceNode converts a trace predicate to a trace_id IN subquery, so that every
+// span of a matching trace satisfies the condition.
+func (b *SpanQueryBuilder) buildTraceNode(node *obsDomain.TraceNode) (string, []interface{}, error) {
+	if b.projectID == "" {
+		return "", nil, fmt.Errorf("%w: trace predicates require a project scope", obsDomain.ErrInvalidFilterSyntax)
+	}
+	if node.Left == nil || (node.Relation.IsBinary() && node.Right == nil) {
+		return "", nil, fmt.Errorf("%w: %s requires a span filter", obsDomain.ErrMissingValue, node.Relation)
+	}
+
+	leftSQL, leftArgs, err := b.buildNode(node.Left)
+	if err != nil {
+		return "", nil, err
+	}
+
+	scope, scopeArgs := b.traceScope()
+
+	switch node.Relation {
+	case obsDomain.TraceRelationHas:
+		sql := fmt.Sprintf("trace_id IN (SELECT trace_id FROM otel_traces WHERE %s AND (%s))", scope, leftSQL)
+		return sql, append(scopeArgs, leftArgs...), nil
+
+	case obsDomain.TraceRelationCount:
+		if !node.Operator.IsComparisonOperator() && node.Operator != obsDomain.FilterOpEqual && node.Operator != obsDomain.FilterOpNotEqual {
+			return "", nil, obsDomain.NewUnsupportedOperatorError(string(node.Operator))
+		}
+		if node.Count < 0 {
+			return "", nil, fmt.Errorf("%w: COUNT requires a non-negative integer", obsDomain.ErrInvalidNumericValue)
+		}
+		b.paramCount++
+		sql := fmt.Sprintf("trace_id IN (SELECT trace_id FROM otel_traces WHERE %s GROUP BY trace_id HAVING countIf(%s) %s ?)",
+			scope, leftSQL, node.Operator)
+		args := append(scopeArgs, leftArgs...)
+		return sql, append(args, node.Count), nil
+	}
+
+	relation, err := traceRelationCondition(node.Relation)
+	if err != nil {
+		return "", nil, err
+	}
+
+	rightSQL, rightArgs, err := b.buildNode(node.Right)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Collect span and parent ids per trace, then test the relation on the arrays
+	sql := fmt.Sprintf(`trace_id IN (
+		SELECT trace_id FROM (
+			SELECT trace_id,
+				groupArray(span_id) AS ids,
+				groupArray(ifNull(parent_span_id, '')) AS parents,
+				groupArrayIf(span_id, %s) AS left_ids,
+				groupArrayIf(span_id, %s) AS right_ids
+			FROM otel_traces
+			WHERE %s
+			GROUP BY trace_id
+			HAVING notEmpty(left_ids) AND notEmpty(right_ids)
+		)
+		WHERE %s
+	)`, leftSQL, rightSQL, scope, relation)
+
+	args := append(leftArgs, rightArgs...)
+	return sql, append(args, scopeArgs...), nil
+}
+
+// traceRelationCondition returns the array expression testing a binary relation
+// over the ids, parents, left_ids and right_ids columns of a grouped trace.
+func traceRelationCondition(relation obsDomain.TraceRelation) (string, error) {
+	switch relation {
+	case obsDomain.TraceRelationChild:
+		// The parent of some right span is a left span
+		return "hasAny(left_ids, arrayFilter((p, s) -> has(right_ids, s), parents, ids))", nil
+
+	case obsDomain.TraceRelationDescendant:
+		// Expand the children of left spans level by level, then look for a right span
+		return fmt.Sprintf(
+			"hasAny(right_ids, arrayFold((acc, i) -> arrayDistinct(arrayConcat(acc, arrayFilter((s, p) -> has(acc, p), ids, parents))), range(%d), arrayFilter((s, p) -> has(left_ids, p), ids, parents)))",
+			obsDomain.SpanQueryMaxTraceDepth,
+		), nil
+
+	case obsDomain.TraceRelationSibling:
+		// A left span and a different right span share a parent
+		return "arrayExists((s, p) -> p != '' AND has(left_ids, s) AND arrayExists((s2, p2) -> p2 = p AND s2 != s AND has(right_ids, s2), ids, parents), ids, parents)", nil
+
+	default:
+		return "", fmt.Errorf("%w: unknown trace relation %s", obsDomain.ErrUnsupportedOperator, relation)
+	}
+}
+
+// BuildTraceFilterCondition generates a trace-level condition for trace listing: a trace
+// matches when any of its spans satisfies the filter. Trace predicates keep their meaning.
+func (b *SpanQueryBuilder) BuildTraceFilterCondition(
+	node obsDomain.FilterNode,
+	projectID string,
+	startTime, endTime *time.Time,
+) (*QueryResult, error) {
+	b.reset(projectID, startTime, endTime)
+
+	if projectID == "" {
+		return nil, fmt.Errorf("%w: project_id is required", obsDomain.ErrMissingValue)
+	}
+
+	whereClause, args, err := b.buildNode(node)
+	if err != nil {
+		return nil, err
+	}
+	if whereClause == "" {
+		return &QueryResult{}, nil
+	}
+
+	scope, scopeArgs := b.traceScope()
+
+	return &QueryResult{
+		Query: fmt.Sprintf("trace_id IN (SELECT trace_id FROM otel_traces WHERE %s AND (%s))", scope, whereClause),
+		Args:  append(scopeArgs, args...),
+		Count: b.paramCount,
+	}, nil
+}
+
 // getColumn returns the ClickHouse column for a field path.
 // It validates the field name to prevent SQL injection and returns an error if invalid.
 func (b *SpanQueryBuilder) getColumn(field string) (string, error) {
@@ -529,7 +683,7 @@ func (b *SpanQueryBuilder) BuildQueryWithSearch(
 	startTime, endTime *time.Time,
 	limit, offset int,
 ) (*QueryResult, error) {
-	b.paramCount = 0
+	b.reset(projectID, startTime, endTime)
 
 	// PREWHERE conditions: indexed columns that benefit from early filtering
 	prewhereConditions := []string{"project_id = ?", "deleted_at IS NULL"}
@@ -609,7 +763,7 @@ func (b *SpanQueryBuilder) BuildCountQueryWithSearch(
 	projectID string,
 	startTime, endTime *time.Time,
 ) (*QueryResult, error) {
-	b.paramCount = 0
+	b.reset(projectID, startTime, endTime)
 
 	// PREWHERE conditions: indexed columns that benefit from early filtering
 	prewhereConditions := []string{"project_id = ?", "deleted_at IS NULL"}
@@ -674,3 +828,282 @@ func (b *SpanQueryBuilder) BuildCountQueryWithSearch(
 		Count: b.paramCount,
 	}, nil
 }
+
+// ArchiveTable is an s3() table function over archived Parquet files.
+// Args are bound to the placeholders in Expr (URL and credentials).
+type ArchiveTable struct {
+	Expr string
+	Args []interface{}
+}
+
+// ArchiveParquetStructure is the ClickHouse structure of RawTelemetryRecord files.
+// event_type is Nullable because files archived before it was recorded lack the column.
+const ArchiveParquetStructure = "record_id String, project_id String, signal_type String, timestamp DateTime64(6, 'UTC'), " +
+	"trace_id String, span_id String, span_json_raw String, archived_at DateTime64(6, 'UTC'), event_type Nullable(String)"
+
+// archiveSpanColumns projects archived span JSON onto the otel_traces column names,
+// using the same expressions as the table's MATERIALIZED columns, so filter SQL applies unchanged.
+const archiveSpanColumns = `
+	span_json_raw,
+	trace_id,
+	span_id,
+	timestamp AS start_time,
+	JSONExtractString(span_json_raw, 'span_name') AS span_name,
+	JSONExtractUInt(span_json_raw, 'status_code') AS status_code,
+	JSONExtract(span_json_raw, 'span_attributes', 'Map(String, String)') AS span_attributes,
+	JSONExtract(span_json_raw, 'resource_attributes', 'Map(String, String)') AS resource_attributes,
+	span_attributes['user.id'] AS user_id,
+	span_attributes['session.id'] AS session_id,
+	resource_attributes['service.name'] AS service_name,
+	span_attributes['gen_ai.request.model'] AS model_name,
+	coalesce(nullIf(span_attributes['gen_ai.provider.name'], ''), span_attributes['gen_ai.system']) AS provider_name,
+	span_attributes['brokle.span.type'] AS span_type,
+	span_attributes['brokle.span.version'] AS span_version
+`
+
+// BuildArchiveQuery generates a query over archived spans from a filter AST.
+// The query selects span_json_raw; spans are decoded from the archived JSON.
+func (b *SpanQueryBuilder) BuildArchiveQuery(
+	node obsDomain.FilterNode,
+	table *ArchiveTable,
+	projectID string,
+	startTime, endTime time.Time,
+	limit, offset int,
+) (*QueryResult, error) {
+	inner, args, err := b.buildArchiveSubquery(node, table, projectID, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT span_json_raw
+		FROM (%s)
+		ORDER BY start_time DESC
+		LIMIT ? OFFSET ?
+	`, inner)
+
+	return &QueryResult{
+		Query: query,
+		Args:  append(args, limit, offset),
+		Count: b.paramCount,
+	}, nil
+}
+
+// BuildArchiveCountQuery generates a COUNT query over archived spans for pagination.
+func (b *SpanQueryBuilder) BuildArchiveCountQuery(
+	node obsDomain.FilterNode,
+	table *ArchiveTable,
+	projectID string,
+	startTime, endTime time.Time,
+) (*QueryResult, error) {
+	inner, args, err := b.buildArchiveSubquery(node, table, projectID, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT count(*) as total
+		FROM (%s)
+	`, inner)
+
+	return &QueryResult{
+		Query: query,
+		Args:  args,
+		Count: b.paramCount,
+	}, nil
+}
+
+// buildArchiveSubquery selects archived spans of a project within the time range and
+// applies the user filter on the projected columns.
+func (b *SpanQueryBuilder) buildArchiveSubquery(
+	node obsDomain.FilterNode,
+	table *ArchiveTable,
+	projectID string,
+	startTime, endTime time.Time,
+) (string, []interface{}, error) {
+	b.paramCount = 0
+
+	// Trace predicates group spans by trace_id, which would scan every archived file per subquery
+	if obsDomain.HasTracePredicate(node) {
+		return "", nil, fmt.Errorf("%w: trace predicates are not supported for archive queries", obsDomain.ErrUnsupportedOperator)
+	}
+
+	whereClause, filterArgs, err := b.buildNode(node)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Scores share the traces signal; legacy records without event_type are told apart by start_time
+	conditions := []string{
+		"project_id = ?",
+		"signal_type = ?",
+		"(ifNull(event_type, '') = ? OR (ifNull(event_type, '') = '' AND JSONHas(span_json_raw, 'start_time')))",
+		"timestamp >= ?",
+		"timestamp <= ?",
+	}
+	args := append([]interface{}{}, table.Args...)
+	args = append(args, projectID, obsDomain.SignalTypeTraces, string(obsDomain.TelemetryEventTypeSpan), startTime, endTime)
+
+	inner := fmt.Sprintf(`
+			SELECT %s
+			FROM %s
+			WHERE %s
+		`, archiveSpanColumns, table.Expr, strings.Join(conditions, " AND "))
+
+	if whereClause == "" {
+		return inner, args, nil
+	}
+	return fmt.Sprintf("SELECT * FROM (%s) WHERE (%s)", inner, whereClause), append(args, filterArgs...), nil
+}
+
+// BuildAggregateQuery generates a parameterized GROUP BY query over spans matching a filter AST.
+// Result columns are aliased positionally: bucket (when interval > 0), g0..gN for group
+// values as strings, then a0..aN for aggregate values as Nullable(Float64).
+func (b *SpanQueryBuilder) BuildAggregateQuery(
+	node obsDomain.FilterNode,
+	projectID string,
+	startTime, endTime *time.Time,
+	aggregations []*obsDomain.SpanAggregation,
+	groupBy []string,
+	interval time.Duration,
+	limit int,
+) (*QueryResult, error) {
+	b.reset(projectID, startTime, endTime)
+
+	if len(aggregations) == 0 {
+		return nil, fmt.Errorf("%w: at least one aggregation is required", obsDomain.ErrMissingValue)
+	}
+
+	whereClause, args, err := b.buildNode(node)
+	if err != nil {
+		return nil, err
+	}
+
+	var selectExprs, groupExprs []string
+	if interval > 0 {
+		selectExprs = append(selectExprs, fmt.Sprintf("toStartOfInterval(start_time, INTERVAL %d SECOND) AS bucket", int64(interval.Seconds())))
+		groupExprs = append(groupExprs, "bucket")
+	}
+	for i, field := range groupBy {
+		column, err := b.getGroupColumn(field)
+		if err != nil {
+			return nil, err
+		}
+		alias := fmt.Sprintf("g%d", i)
+		selectExprs = append(selectExprs, fmt.Sprintf("toString(%s) AS %s", column, alias))
+		groupExprs = append(groupExprs, alias)
+	}
+	for i, agg := range aggregations {
+		expr, err := b.buildAggregation(agg)
+		if err != nil {
+			return nil, err
+		}
+		selectExprs = append(selectExprs, fmt.Sprintf("toNullable(toFloat64(%s)) AS a%d", expr, i))
+	}
+
+	// PREWHERE conditions: indexed columns that benefit from early filtering
+	prewhereConditions := []string{"project_id = ?", "deleted_at IS NULL"}
+	prewhereArgs := []interface{}{projectID}
+
+	if startTime != nil {
+		prewhereConditions = append(prewhereConditions, "start_time >= ?")
+		prewhereArgs = append(prewhereArgs, *startTime)
+	}
+	if endTime != nil {
+		prewhereConditions = append(prewhereConditions, "start_time <= ?")
+		prewhereArgs = append(prewhereArgs, *endTime)
+	}
+
+	// Optional clauses are appended after PREWHERE; ORDER BY uses the first aggregation
+	var clauses []string
+	if whereClause != "" {
+		clauses = append(clauses, "WHERE ("+whereClause+")")
+	}
+	if len(groupExprs) > 0 {
+		clauses = append(clauses, "GROUP BY "+strings.Join(groupExprs, ", "))
+	}
+	if interval > 0 {
+		clauses = append(clauses, "ORDER BY bucket ASC, a0 DESC")
+	} else {
+		clauses = append(clauses, "ORDER BY a0 DESC")
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM otel_traces
+		PREWHERE %s
+		%s
+		LIMIT ?
+	`, strings.Join(selectExprs, ", "), strings.Join(prewhereConditions, " AND "), strings.Join(clauses, "\n\t\t"))
+
+	allArgs := append(prewhereArgs, args...)
+	allArgs = append(allArgs, limit)
+
+	return &QueryResult{
+		Query: query,
+		Args:  allArgs,
+		Count: b.paramCount,
+	}, nil
+}
+
+// buildAggregation converts a parsed aggregation to a ClickHouse aggregate expression.
+func (b *SpanQueryBuilder) buildAggregation(agg *obsDomain.SpanAggregation) (string, error) {
+	if agg.Function == obsDomain.AggregateCount {
+		return "count()", nil
+	}
+
+	if agg.Function == obsDomain.AggregateUniq {
+		column, err := b.getGroupColumn(agg.Field)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("uniq(%s)", column), nil
+	}
+
+	column, err := b.getNumericColumn(agg.Field)
+	if err != nil {
+		return "", err
+	}
+
+	switch agg.Function {
+	case obsDomain.AggregateSum, obsDomain.AggregateAvg, obsDomain.AggregateMin, obsDomain.AggregateMax:
+		return fmt.Sprintf("%s(%s)", agg.Function, column), nil
+	case obsDomain.AggregateQuantile:
+		if agg.Quantile < 0 || agg.Quantile > 1 {
+			return "", obsDomain.ErrInvalidValue
+		}
+		return fmt.Sprintf("quantile(%s)(%s)", strconv.FormatFloat(agg.Quantile, 'f', -1, 64), column), nil
+	default:
+		return "", obsDomain.NewUnsupportedOperatorError(string(agg.Function))
+	}
+}
+
+// getNumericColumn returns a numeric expression for a field. Known numeric span fields map
+// to their columns; string columns and attributes are converted with toFloat64OrNull.
+func (b *SpanQueryBuilder) getNumericColumn(field string) (string, error) {
+	if err := validateFieldName(field); err != nil {
+		return "", err
+	}
+	if expr, ok := obsDomain.AggregatableColumns[field]; ok {
+		return expr, nil
+	}
+	column, err := b.getGroupColumn(field)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("toFloat64OrNull(%s)", column), nil
+}
+
+// getGroupColumn resolves a field like getColumn, and additionally accepts materialized
+// column names directly (service_name, model_name) as used in GROUP BY clauses.
+func (b *SpanQueryBuilder) getGroupColumn(field string) (string, error) {
+	if err := validateFieldName(field); err != nil {
+		return "", err
+	}
+	for _, column := range obsDomain.MaterializedColumns {
+		if column == field {
+			return column, nil
+		}
+	}
+	return b.getColumn(field)
+}
@@ -1,10 +1,13 @@
 package observability
 
 import (
+	"io"
+	"log/slog"
 	"strings"
 	"testing"
 	"time"
 
+	"brokle/internal/config"
 	obsDomain "brokle/internal/core/domain/observability"
 
 	"github.com/stretchr/testify/assert"
@@ -1452,3 +1455,201 @@ func TestSpanQueryBuilder_BuildCountQueryWithSearch(t *testing.T) {
 		})
 	}
 }
+
+func TestSpanQueryBuilder_BuildArchiveQuery(t *testing.T) {
+	builder := NewSpanQueryBuilder()
+	table := &ArchiveTable{
+		Expr: "s3(?, 'Parquet', ?)",
+		Args: []interface{}{"https://bucket.s3.us-east-1.amazonaws.com/telemetry/*.parquet", ArchiveParquetStructure},
+	}
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+	node := &obsDomain.ConditionNode{Field: "user.id", Operator: obsDomain.FilterOpEqual, Value: "user-42"}
+
+	result, err := builder.BuildArchiveQuery(node, table, "proj123", start, end, 50, 100)
+	require.NoError(t, err)
+
+	assert.Contains(t, result.Query, "FROM s3(?, 'Parquet', ?)")
+	assert.Contains(t, result.Query, "span_attributes['user.id'] AS user_id")
+	assert.Contains(t, result.Query, "WHERE (user_id = ?)")
+	assert.Contains(t, result.Query, "LIMIT ? OFFSET ?")
+
+	// Table args, then archive conditions, then filter values, then pagination
+	require.Len(t, result.Args, 10)
+	assert.Equal(t, table.Args[0], result.Args[0])
+	assert.Equal(t, "proj123", result.Args[2])
+	assert.Equal(t, obsDomain.SignalTypeTraces, result.Args[3])
+	assert.Equal(t, string(obsDomain.TelemetryEventTypeSpan), result.Args[4])
+	assert.Equal(t, start, result.Args[5])
+	assert.Equal(t, end, result.Args[6])
+	assert.Equal(t, "user-42", result.Args[7])
+	assert.Equal(t, []interface{}{50, 100}, result.Args[8:])
+
+	countResult, err := builder.BuildArchiveCountQuery(node, table, "proj123", start, end)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(strings.TrimSpace(countResult.Query), "SELECT count(*) as total"))
+	assert.Len(t, countResult.Args, 8)
+}
+
+func TestArchiveGlob(t *testing.T) {
+	tests := []struct {
+		name  string
+		start time.Time
+		end   time.Time
+		want  string
+	}{
+		{
+			name:  "single month",
+			start: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+			end:   time.Date(2025, 3, 20, 0, 0, 0, 0, time.UTC),
+			want:  "telemetry/project_id=p1/signal=traces/year=2025/month=03/day=*/*.parquet",
+		},
+		{
+			name:  "months within a year",
+			start: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+			end:   time.Date(2025, 5, 2, 0, 0, 0, 0, time.UTC),
+			want:  "telemetry/project_id=p1/signal=traces/year=2025/month={03,04,05}/day=*/*.parquet",
+		},
+		{
+			name:  "across years",
+			start: time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC),
+			end:   time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+			want:  "telemetry/project_id=p1/signal=traces/year={2024,2025}/month=*/day=*/*.parquet",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ArchiveGlob("telemetry/", "p1", obsDomain.SignalTypeTraces, tt.start, tt.end))
+		})
+	}
+}
+
+func TestSpanQueryService_ArchiveTable(t *testing.T) {
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)
+	req := &obsDomain.SpanQueryRequest{StartTime: &start, EndTime: &end}
+	blobConfig := &config.BlobStorageConfig{
+		Provider:        "s3",
+		BucketName:      "bucket",
+		Endpoint:        "http://minio:9000",
+		AccessKeyID:     "AKIA",
+		SecretAccessKey: "secret",
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	t.Run("named collection keeps credentials out of the query", func(t *testing.T) {
+		archiveConfig := &config.ArchiveConfig{PathPrefix: "telemetry/", QueryNamedCollection: "brokle_archive"}
+		table := NewSpanQueryService(nil, blobConfig, archiveConfig, logger).archiveTable("p1", req)
+
+		assert.Equal(t, "s3(brokle_archive, url = ?, format = 'Parquet', structure = ?)", table.Expr)
+		assert.NotContains(t, table.Args, "secret")
+	})
+
+	t.Run("static keys without a named collection", func(t *testing.T) {
+		archiveConfig := &config.ArchiveConfig{PathPrefix: "telemetry/"}
+		table := NewSpanQueryService(nil, blobConfig, archiveConfig, logger).archiveTable("p1", req)
+
+		assert.Equal(t, "s3(?, ?, ?, 'Parquet', ?)", table.Expr)
+		assert.Contains(t, table.Args, "secret")
+	})
+}
+
+func TestSpanQueryBuilder_TracePredicates(t *testing.T) {
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)
+	tool := &obsDomain.ConditionNode{Field: "span.type", Operator: obsDomain.FilterOpEqual, Value: "tool"}
+	agent := &obsDomain.ConditionNode{Field: "span_name", Operator: obsDomain.FilterOpEqual, Value: "agent"}
+
+	t.Run("has", func(t *testing.T) {
+		node := &obsDomain.TraceNode{Relation: obsDomain.TraceRelationHas, Left: tool}
+
+		result, err := NewSpanQueryBuilder().BuildQuery(node, "proj123", &start, &end, 10, 0)
+		require.NoError(t, err)
+
+		assert.Contains(t, result.Query, "trace_id IN (SELECT trace_id FROM otel_traces WHERE project_id = ? AND deleted_at IS NULL AND start_time >= ? AND start_time <= ? AND (")
+		// Outer scope, subquery scope, filter value, pagination
+		assert.Equal(t, []interface{}{"proj123", start, end, "proj123", start, end, "tool", 10, 0}, result.Args)
+	})
+
+	t.Run("count", func(t *testing.T) {
+		node := &obsDomain.TraceNode{
+			Relation: obsDomain.TraceRelationCount,
+			Left:     tool,
+			Operator: obsDomain.FilterOpGreaterOrEqual,
+			Count:    3,
+		}
+
+		result, err := NewSpanQueryBuilder().BuildCountQuery(node, "proj123", nil, nil)
+		require.NoError(t, err)
+
+		assert.Contains(t, result.Query, "GROUP BY trace_id HAVING countIf(")
+		assert.Contains(t, result.Query, ") >= ?)")
+		assert.Equal(t, []interface{}{"proj123", "proj123", "tool", int64(3)}, result.Args)
+	})
+
+	t.Run("count rejects invalid operator", func(t *testing.T) {
+		node := &obsDomain.TraceNode{
+			Relation: obsDomain.TraceRelationCount,
+			Left:     tool,
+			Operator: obsDomain.FilterOperator("> 0) OR 1=1 --"),
+		}
+
+		_, err := NewSpanQueryBuilder().BuildQuery(node, "proj123", nil, nil, 10, 0)
+		require.Error(t, err)
+	})
+
+	relations := map[obsDomain.TraceRelation]string{
+		obsDomain.TraceRelationChild:      "hasAny(left_ids, arrayFilter(",
+		obsDomain.TraceRelationDescendant: "arrayFold(",
+		obsDomain.TraceRelationSibling:    "arrayExists(",
+	}
+	for relation, expr := range relations {
+		t.Run(strings.ToLower(string(relation)), func(t *testing.T) {
+			node := &obsDomain.TraceNode{Relation: relation, Left: agent, Right: tool}
+
+			result, err := NewSpanQueryBuilder().BuildQuery(node, "proj123", &start, nil, 10, 0)
+			require.NoError(t, err)
+
+			assert.Contains(t, result.Query, "groupArray(ifNull(parent_span_id, '')) AS parents")
+			assert.Contains(t, result.Query, "AS left_ids")
+			assert.Contains(t, result.Query, "AS right_ids")
+			assert.Contains(t, result.Query, expr)
+			// Left and right values appear in the SELECT list before the subquery scope
+			assert.Equal(t, []interface{}{"proj123", start, "agent", "tool", "proj123", start, 10, 0}, result.Args)
+		})
+	}
+
+	t.Run("binary relation requires right operand", func(t *testing.T) {
+		node := &obsDomain.TraceNode{Relation: obsDomain.TraceRelationChild, Left: agent}
+
+		_, err := NewSpanQueryBuilder().BuildQuery(node, "proj123", nil, nil, 10, 0)
+		require.Error(t, err)
+	})
+
+	t.Run("archive rejects trace predicates", func(t *testing.T) {
+		node := &obsDomain.BinaryNode{
+			Left:     agent,
+			Right:    &obsDomain.TraceNode{Relation: obsDomain.TraceRelationHas, Left: tool},
+			Operator: obsDomain.LogicAnd,
+		}
+		table := &ArchiveTable{Expr: "s3(?, 'Parquet')", Args: []interface{}{"https://bucket/*.parquet"}}
+
+		_, err := NewSpanQueryBuilder().BuildArchiveQuery(node, table, "proj123", start, end, 10, 0)
+		require.ErrorIs(t, err, obsDomain.ErrUnsupportedOperator)
+	})
+}
+
+func TestSpanQueryBuilder_BuildTraceFilterCondition(t *testing.T) {
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	node := &obsDomain.ConditionNode{Field: "has_error", Operator: obsDomain.FilterOpEqual, Value: "true"}
+
+	result, err := NewSpanQueryBuilder().BuildTraceFilterCondition(node, "proj123", &start, nil)
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(result.Query, "trace_id IN (SELECT trace_id FROM otel_traces WHERE project_id = ? AND deleted_at IS NULL AND start_time >= ? AND ("))
+	assert.Equal(t, []interface{}{"proj123", start, "true"}, result.Args[:3])
+
+	_, err = NewSpanQueryBuilder().BuildTraceFilterCondition(node, "", nil, nil)
+	require.Error(t, err)
+}
@@ -2,8 +2,12 @@ package observability
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"strings"
+	"time"
 
+	"brokle/internal/config"
 	obsDomain "brokle/internal/core/domain/observability"
 	appErrors "brokle/pkg/errors"
 )
@@ -12,19 +16,29 @@ import (
 // It parses filter expressions, builds safe SQL queries, and executes them.
 // Parser and query builder are created per-request for thread safety.
 type SpanQueryService struct {
-	traceRepo obsDomain.TraceRepository
-	logger    *slog.Logger
+	traceRepo     obsDomain.TraceRepository
+	blobConfig    *config.BlobStorageConfig // nil or unconfigured disables archive queries
+	archiveConfig *config.ArchiveConfig
+	logger        *slog.Logger
 }
 
 // NewSpanQueryService creates a new span query service.
 func NewSpanQueryService(
 	traceRepo obsDomain.TraceRepository,
+	blobConfig *config.BlobStorageConfig,
+	archiveConfig *config.ArchiveConfig,
 	logger *slog.Logger,
 ) *SpanQueryService {
-	return &SpanQueryService{
-		traceRepo: traceRepo,
-		logger:    logger.With("service", "span_query"),
+	s := &SpanQueryService{
+		traceRepo:     traceRepo,
+		blobConfig:    blobConfig,
+		archiveConfig: archiveConfig,
+		logger:        logger.With("service", "span_query"),
 	}
+	if s.archiveQueryEnabled() && archiveConfig.QueryNamedCollection == "" && blobConfig.AccessKeyID != "" {
+		s.logger.Warn("archive queries send S3 keys in the query text; set ARCHIVE_QUERY_NAMED_COLLECTION to keep them in ClickHouse")
+	}
+	return s
 }
 
 // QuerySpans executes a span query using the filter expression syntax.
@@ -57,6 +71,10 @@ func (s *SpanQueryService) QuerySpans(
 		offset = 0
 	}
 
+	if req.Source == obsDomain.SpanQuerySourceArchive {
+		return s.queryArchivedSpans(ctx, projectID, req, filterNode, offset)
+	}
+
 	queryResult, err := queryBuilder.BuildQuery(
 		filterNode,
 		projectID,
@@ -121,6 +139,208 @@ func (s *SpanQueryService) QuerySpans(
 	}, nil
 }
 
+// AggregateSpans computes aggregates over spans matching an optional filter expression,
+// grouped by fields and optionally bucketed by time.
+func (s *SpanQueryService) AggregateSpans(
+	ctx context.Context,
+	projectID string,
+	req *obsDomain.SpanAggregateRequest,
+) (*obsDomain.SpanAggregateResponse, error) {
+	if errs := obsDomain.ValidateSpanAggregateRequest(req); len(errs) > 0 {
+		return nil, appErrors.NewValidationError("invalid span aggregate request", errs[0].Message)
+	}
+
+	obsDomain.NormalizeSpanAggregateRequest(req)
+
+	var filterNode obsDomain.FilterNode
+	if strings.TrimSpace(req.Filter) != "" {
+		node, err := NewFilterParser().Parse(req.Filter)
+		if err != nil {
+			s.logger.Debug("filter parse error",
+				"filter", req.Filter,
+				"error", err,
+			)
+			return nil, appErrors.NewValidationError("invalid filter expression", err.Error())
+		}
+		filterNode = node
+	}
+
+	aggregations := make([]*obsDomain.SpanAggregation, len(req.Aggregations))
+	valueKeys := make([]string, len(req.Aggregations))
+	for i, expr := range req.Aggregations {
+		agg, err := ParseAggregation(expr)
+		if err != nil {
+			return nil, appErrors.NewValidationError("invalid aggregation", err.Error())
+		}
+		aggregations[i] = agg
+		valueKeys[i] = agg.Expression
+	}
+
+	var interval time.Duration
+	if req.Interval != "" {
+		interval, _ = obsDomain.ParseAggregateInterval(req.Interval) // validated above
+	}
+
+	// Fetch one extra row to detect truncation
+	queryResult, err := NewSpanQueryBuilder().BuildAggregateQuery(
+		filterNode,
+		projectID,
+		req.StartTime,
+		req.EndTime,
+		aggregations,
+		req.GroupBy,
+		interval,
+		req.Limit+1,
+	)
+	if err != nil {
+		// Field and operator errors stem from user input
+		return nil, appErrors.NewValidationError("invalid aggregation query", err.Error())
+	}
+
+	rows, err := s.traceRepo.AggregateSpansByExpression(ctx, queryResult.Query, queryResult.Args, interval > 0, req.GroupBy, valueKeys)
+	if err != nil {
+		s.logger.Error("aggregate query execution error",
+			"project_id", projectID,
+			"error", err,
+		)
+		return nil, appErrors.NewInternalError("aggregate query execution failed", err)
+	}
+
+	truncated := len(rows) > req.Limit
+	if truncated {
+		rows = rows[:req.Limit]
+	}
+
+	s.logger.Debug("span aggregate query executed",
+		"project_id", projectID,
+		"filter", req.Filter,
+		"aggregations", req.Aggregations,
+		"group_by", req.GroupBy,
+		"result_count", len(rows),
+	)
+
+	return &obsDomain.SpanAggregateResponse{
+		Rows:      rows,
+		Truncated: truncated,
+	}, nil
+}
+
+// queryArchivedSpans runs the filter over archived Parquet files through the ClickHouse s3() table function.
+// Archived spans are returned as ingested; nothing is loaded back into hot storage.
+func (s *SpanQueryService) queryArchivedSpans(
+	ctx context.Context,
+	projectID string,
+	req *obsDomain.SpanQueryRequest,
+	filterNode obsDomain.FilterNode,
+	offset int,
+) (*obsDomain.SpanQueryResponse, error) {
+	if !s.archiveQueryEnabled() {
+		return nil, appErrors.NewServiceUnavailableError("Archive storage is not configured")
+	}
+	if obsDomain.HasTracePredicate(filterNode) {
+		return nil, appErrors.NewValidationError("invalid filter expression", "trace predicates are not supported for archive queries")
+	}
+
+	table := s.archiveTable(projectID, req)
+	queryBuilder := NewSpanQueryBuilder()
+
+	queryResult, err := queryBuilder.BuildArchiveQuery(filterNode, table, projectID, *req.StartTime, *req.EndTime, req.Limit, offset)
+	if err != nil {
+		s.logger.Error("archive query build error",
+			"project_id", projectID,
+			"filter", req.Filter,
+			"error", err,
+		)
+		return nil, appErrors.NewInternalError("failed to build query", err)
+	}
+
+	countResult, err := queryBuilder.BuildArchiveCountQuery(filterNode, table, projectID, *req.StartTime, *req.EndTime)
+	if err != nil {
+		s.logger.Error("archive count query build error",
+			"project_id", projectID,
+			"filter", req.Filter,
+			"error", err,
+		)
+		return nil, appErrors.NewInternalError("failed to build count query", err)
+	}
+
+	spans, err := s.traceRepo.QueryArchivedSpansByExpression(ctx, queryResult.Query, queryResult.Args)
+	if err != nil {
+		s.logger.Error("archive query execution error",
+			"project_id", projectID,
+			"error", err,
+		)
+		return nil, appErrors.NewInternalError("archive query execution failed", err)
+	}
+
+	totalCount, err := s.traceRepo.CountSpansByExpression(ctx, countResult.Query, countResult.Args)
+	if err != nil {
+		s.logger.Error("archive count query execution error",
+			"project_id", projectID,
+			"error", err,
+		)
+		return nil, appErrors.NewInternalError("archive count query failed", err)
+	}
+
+	s.logger.Debug("archive span query executed",
+		"project_id", projectID,
+		"filter", req.Filter,
+		"result_count", len(spans),
+		"total_count", totalCount,
+	)
+
+	return &obsDomain.SpanQueryResponse{
+		Spans:      spans,
+		TotalCount: totalCount,
+		HasMore:    int64(offset+len(spans)) < totalCount,
+	}, nil
+}
+
+func (s *SpanQueryService) archiveQueryEnabled() bool {
+	return s.blobConfig != nil && s.archiveConfig != nil &&
+		s.blobConfig.Provider != "" && s.blobConfig.BucketName != ""
+}
+
+// archiveTable builds the s3() table function over the project's trace files in the query range.
+// The driver interpolates parameters client-side, so bound values still reach ClickHouse (and its
+// query log) as SQL text. A named collection keeps the credentials on the ClickHouse server instead.
+func (s *SpanQueryService) archiveTable(projectID string, req *obsDomain.SpanQueryRequest) *ArchiveTable {
+	glob := ArchiveGlob(s.archiveConfig.PathPrefix, projectID, obsDomain.SignalTypeTraces, *req.StartTime, *req.EndTime)
+	url := archiveObjectURL(s.blobConfig, s.archiveConfig.QueryEndpoint, glob)
+
+	if collection := s.archiveConfig.QueryNamedCollection; collection != "" {
+		// Validated as an identifier when the config is loaded
+		return &ArchiveTable{
+			Expr: "s3(" + collection + ", url = ?, format = 'Parquet', structure = ?)",
+			Args: []interface{}{url, ArchiveParquetStructure},
+		}
+	}
+	if s.blobConfig.AccessKeyID != "" && s.blobConfig.SecretAccessKey != "" {
+		return &ArchiveTable{
+			Expr: "s3(?, ?, ?, 'Parquet', ?)",
+			Args: []interface{}{url, s.blobConfig.AccessKeyID, s.blobConfig.SecretAccessKey, ArchiveParquetStructure},
+		}
+	}
+	// No static keys: ClickHouse uses its own S3 credentials (e.g. instance role)
+	return &ArchiveTable{
+		Expr: "s3(?, 'Parquet', ?)",
+		Args: []interface{}{url, ArchiveParquetStructure},
+	}
+}
+
+// archiveObjectURL returns the URL ClickHouse uses to read an archive key.
+// Custom endpoints (MinIO) use path-style URLs; AWS uses virtual-hosted style.
+func archiveObjectURL(blobConfig *config.BlobStorageConfig, queryEndpoint, key string) string {
+	endpoint := queryEndpoint
+	if endpoint == "" {
+		endpoint = blobConfig.Endpoint
+	}
+	if endpoint != "" {
+		return fmt.Sprintf("%s/%s/%s", strings.TrimRight(endpoint, "/"), blobConfig.BucketName, key)
+	}
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", blobConfig.BucketName, blobConfig.Region, key)
+}
+
 // ValidateFilter validates a filter expression without executing it.
 // This is useful for SDK clients to validate filters before submitting queries.
 func (s *SpanQueryService) ValidateFilter(filter string) error {
@@ -9,8 +9,10 @@ import (
 	"sync"
 	"time"
 
+	authDomain "brokle/internal/core/domain/auth"
 	"brokle/internal/core/domain/observability"
 	appErrors "brokle/pkg/errors"
+	"brokle/pkg/ulid"
 
 	lru "github.com/hashicorp/golang-lru/v2"
 )
@@ -25,6 +27,7 @@ const filterOptionsCacheTTL = 5 * time.Minute
 
 type TraceService struct {
 	traceRepo            observability.TraceRepository
+	auditLogs            authDomain.AuditLogService // Set by the server provider; nil in workers
 	logger               *slog.Logger
 	filterOptionsCache   *lru.Cache[string, *filterOptionsCacheEntry]
 	filterOptionsCacheMu sync.Mutex
@@ -43,6 +46,11 @@ func NewTraceService(
 	}
 }
 
+// SetAuditLogService enables audit logging of trace deletions
+func (s *TraceService) SetAuditLogService(auditLogs authDomain.AuditLogService) {
+	s.auditLogs = auditLogs
+}
+
 func (s *TraceService) IngestSpan(ctx context.Context, span *observability.Span) error {
 	if span.TraceID == "" {
 		return appErrors.NewValidationError("trace_id is required", "span must be linked to a trace")
@@ -250,6 +258,10 @@ func (s *TraceService) ListTraces(ctx context.Context, filter *observability.Tra
 
 	filter.SetDefaults("trace_start")
 
+	if err := compileTraceFilter(filter); err != nil {
+		return nil, err
+	}
+
 	traces, err := s.traceRepo.ListTraces(ctx, filter)
 	if err != nil {
 		return nil, appErrors.NewInternalError("failed to list traces", err)
@@ -263,6 +275,10 @@ func (s *TraceService) CountTraces(ctx context.Context, filter *observability.Tr
 		return 0, appErrors.NewValidationError("filter is required", "trace filter cannot be nil")
 	}
 
+	if err := compileTraceFilter(filter); err != nil {
+		return 0, err
+	}
+
 	count, err := s.traceRepo.CountTraces(ctx, filter)
 	if err != nil {
 		return 0, appErrors.NewInternalError("failed to count traces", err)
@@ -271,6 +287,30 @@ func (s *TraceService) CountTraces(ctx context.Context, filter *observability.Tr
 	return count, nil
 }
 
+// compileTraceFilter parses the filter expression and sets the trace_id condition applied by the repository.
+func compileTraceFilter(filter *observability.TraceFilter) error {
+	filter.FilterCondition = ""
+	filter.FilterArgs = nil
+
+	if filter.Filter == nil || *filter.Filter == "" {
+		return nil
+	}
+
+	node, err := NewFilterParser().Parse(*filter.Filter)
+	if err != nil {
+		return appErrors.NewValidationError("invalid filter expression", err.Error())
+	}
+
+	result, err := NewSpanQueryBuilder().BuildTraceFilterCondition(node, filter.ProjectID, filter.StartTime, filter.EndTime)
+	if err != nil {
+		return appErrors.NewValidationError("invalid filter expression", err.Error())
+	}
+
+	filter.FilterCondition = result.Query
+	filter.FilterArgs = result.Args
+	return nil
+}
+
 func (s *TraceService) GetTracesBySession(ctx context.Context, sessionID string) ([]*observability.TraceSummary, error) {
 	if sessionID == "" {
 		return nil, appErrors.NewValidationError("session_id is required", "session_id cannot be empty")
@@ -352,10 +392,31 @@ func (s *TraceService) DeleteTrace(ctx context.Context, traceID string) error {
 		return appErrors.NewNotFoundError("trace " + traceID)
 	}
 
+	var summary *observability.TraceSummary
+	if s.auditLogs != nil {
+		summary, _ = s.traceRepo.GetTraceSummary(ctx, traceID)
+	}
+
 	if err := s.traceRepo.DeleteTrace(ctx, traceID); err != nil {
 		return appErrors.NewInternalError("failed to delete trace", err)
 	}
 
+	if s.auditLogs != nil {
+		event := &authDomain.AuditEvent{
+			Action:     "trace.deleted",
+			Resource:   "trace",
+			ResourceID: traceID,
+			Metadata:   map[string]interface{}{"span_count": count},
+		}
+		if summary != nil {
+			if projectID, err := ulid.Parse(summary.ProjectID); err == nil {
+				event.ProjectID = &projectID
+			}
+			event.Before = summary
+		}
+		s.auditLogs.Record(ctx, event)
+	}
+
 	return nil
 }
 
@@ -27,6 +27,14 @@ func (m *MockTraceRepository) InsertSpanBatch(ctx context.Context, spans []*obse
 	return args.Error(0)
 }
 
+func (m *MockTraceRepository) GetExistingSpanIDs(ctx context.Context, projectID string, spanIDs []string) (map[string]struct{}, error) {
+	args := m.Called(ctx, projectID, spanIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]struct{}), args.Error(1)
+}
+
 func (m *MockTraceRepository) GetSpan(ctx context.Context, spanID string) (*observability.Span, error) {
 	args := m.Called(ctx, spanID)
 	if args.Get(0) == nil {
@@ -184,6 +192,22 @@ func (m *MockTraceRepository) QuerySpansByExpression(ctx context.Context, query
 	return args.Get(0).([]*observability.Span), args.Error(1)
 }
 
+func (m *MockTraceRepository) QueryArchivedSpansByExpression(ctx context.Context, query string, queryArgs []interface{}) ([]*observability.Span, error) {
+	args := m.Called(ctx, query, queryArgs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*observability.Span), args.Error(1)
+}
+
+func (m *MockTraceRepository) AggregateSpansByExpression(ctx context.Context, query string, queryArgs []interface{}, bucketed bool, groupKeys, valueKeys []string) ([]*observability.SpanAggregateRow, error) {
+	args := m.Called(ctx, query, queryArgs, bucketed, groupKeys, valueKeys)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*observability.SpanAggregateRow), args.Error(1)
+}
+
 func (m *MockTraceRepository) CountSpansByExpression(ctx context.Context, query string, queryArgs []interface{}) (int64, error) {
 	args := m.Called(ctx, query, queryArgs)
 	return args.Get(0).(int64), args.Error(1)
@@ -0,0 +1,76 @@
+package organization
+
+import (
+	"context"
+
+	authDomain "brokle/internal/core/domain/auth"
+	orgDomain "brokle/internal/core/domain/organization"
+	"brokle/pkg/ulid"
+)
+
+const auditResourceMember = "organization_member"
+
+// memberAuditDecorator records membership changes in the audit log.
+// Read methods pass straight through to the wrapped service.
+type memberAuditDecorator struct {
+	orgDomain.MemberService
+	auditLogs authDomain.AuditLogService
+}
+
+// NewMemberAuditDecorator wraps a MemberService with audit logging
+func NewMemberAuditDecorator(service orgDomain.MemberService, auditLogs authDomain.AuditLogService) orgDomain.MemberService {
+	return &memberAuditDecorator{
+		MemberService: service,
+		auditLogs:     auditLogs,
+	}
+}
+
+func (d *memberAuditDecorator) AddMember(ctx context.Context, orgID, userID, roleID ulid.ULID, addedByID ulid.ULID) error {
+	if err := d.MemberService.AddMember(ctx, orgID, userID, roleID, addedByID); err != nil {
+		return err
+	}
+	d.record(ctx, orgID, userID, addedByID, "member.added", nil, map[string]interface{}{"role_id": roleID})
+	return nil
+}
+
+func (d *memberAuditDecorator) RemoveMember(ctx context.Context, orgID, userID ulid.ULID, removedByID ulid.ULID) error {
+	before := d.roleSnapshot(ctx, orgID, userID)
+	if err := d.MemberService.RemoveMember(ctx, orgID, userID, removedByID); err != nil {
+		return err
+	}
+	d.record(ctx, orgID, userID, removedByID, "member.removed", before, nil)
+	return nil
+}
+
+func (d *memberAuditDecorator) UpdateMemberRole(ctx context.Context, orgID, userID, roleID ulid.ULID, updatedByID ulid.ULID) error {
+	before := d.roleSnapshot(ctx, orgID, userID)
+	if err := d.MemberService.UpdateMemberRole(ctx, orgID, userID, roleID, updatedByID); err != nil {
+		return err
+	}
+	d.record(ctx, orgID, userID, updatedByID, "member.role_updated", before, map[string]interface{}{"role_id": roleID})
+	return nil
+}
+
+// roleSnapshot returns the member's current role, or nil when it cannot be read
+func (d *memberAuditDecorator) roleSnapshot(ctx context.Context, orgID, userID ulid.ULID) interface{} {
+	member, err := d.MemberService.GetMember(ctx, orgID, userID)
+	if err != nil {
+		return nil
+	}
+	return map[string]interface{}{"role_id": member.RoleID}
+}
+
+func (d *memberAuditDecorator) record(ctx context.Context, orgID, userID, actorID ulid.ULID, action string, before, after interface{}) {
+	event := &authDomain.AuditEvent{
+		OrganizationID: &orgID,
+		Action:         action,
+		Resource:       auditResourceMember,
+		ResourceID:     userID.String(),
+		Before:         before,
+		After:          after,
+	}
+	if !actorID.IsZero() {
+		event.UserID = &actorID
+	}
+	d.auditLogs.Record(ctx, event)
+}
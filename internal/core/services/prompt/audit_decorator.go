@@ -0,0 +1,116 @@
+package prompt
+
+import (
+	"context"
+
+	authDomain "brokle/internal/core/domain/auth"
+	promptDomain "brokle/internal/core/domain/prompt"
+	"brokle/pkg/ulid"
+)
+
+const (
+	auditResourcePrompt          = "prompt"
+	auditResourceProtectedLabels = "prompt_protected_labels"
+)
+
+// auditDecorator records prompt deletions and label moves in the audit log.
+// Label changes are diffed as label -> version number across the whole prompt.
+type auditDecorator struct {
+	promptDomain.PromptService
+	auditLogs authDomain.AuditLogService
+}
+
+// NewAuditDecorator wraps a PromptService with audit logging
+func NewAuditDecorator(service promptDomain.PromptService, auditLogs authDomain.AuditLogService) promptDomain.PromptService {
+	return &auditDecorator{
+		PromptService: service,
+		auditLogs:     auditLogs,
+	}
+}
+
+func (d *auditDecorator) DeletePrompt(ctx context.Context, projectID, promptID ulid.ULID) error {
+	before, _ := d.PromptService.GetPromptByID(ctx, projectID, promptID)
+	if err := d.PromptService.DeletePrompt(ctx, projectID, promptID); err != nil {
+		return err
+	}
+
+	d.auditLogs.Record(ctx, &authDomain.AuditEvent{
+		ProjectID:  &projectID,
+		Action:     "prompt.deleted",
+		Resource:   auditResourcePrompt,
+		ResourceID: promptID.String(),
+		Before:     before,
+	})
+	return nil
+}
+
+func (d *auditDecorator) SetLabels(ctx context.Context, projectID, promptID, versionID ulid.ULID, userID *ulid.ULID, labels []string) error {
+	before := d.labelSnapshot(ctx, projectID, promptID)
+	if err := d.PromptService.SetLabels(ctx, projectID, promptID, versionID, userID, labels); err != nil {
+		return err
+	}
+
+	d.auditLogs.Record(ctx, &authDomain.AuditEvent{
+		UserID:     userID,
+		ProjectID:  &projectID,
+		Action:     "prompt.labels_set",
+		Resource:   auditResourcePrompt,
+		ResourceID: promptID.String(),
+		Before:     before,
+		After:      d.labelSnapshot(ctx, projectID, promptID),
+		Metadata:   map[string]interface{}{"version_id": versionID.String(), "labels": labels},
+	})
+	return nil
+}
+
+func (d *auditDecorator) RemoveLabel(ctx context.Context, projectID, promptID ulid.ULID, userID *ulid.ULID, labelName string) error {
+	before := d.labelSnapshot(ctx, projectID, promptID)
+	if err := d.PromptService.RemoveLabel(ctx, projectID, promptID, userID, labelName); err != nil {
+		return err
+	}
+
+	d.auditLogs.Record(ctx, &authDomain.AuditEvent{
+		UserID:     userID,
+		ProjectID:  &projectID,
+		Action:     "prompt.label_removed",
+		Resource:   auditResourcePrompt,
+		ResourceID: promptID.String(),
+		Before:     before,
+		After:      d.labelSnapshot(ctx, projectID, promptID),
+		Metadata:   map[string]interface{}{"label": labelName},
+	})
+	return nil
+}
+
+func (d *auditDecorator) SetProtectedLabels(ctx context.Context, projectID ulid.ULID, userID *ulid.ULID, labels []string) error {
+	before, _ := d.PromptService.GetProtectedLabels(ctx, projectID)
+	if err := d.PromptService.SetProtectedLabels(ctx, projectID, userID, labels); err != nil {
+		return err
+	}
+
+	d.auditLogs.Record(ctx, &authDomain.AuditEvent{
+		UserID:     userID,
+		ProjectID:  &projectID,
+		Action:     "prompt.protected_labels_set",
+		Resource:   auditResourceProtectedLabels,
+		ResourceID: projectID.String(),
+		Before:     map[string]interface{}{"labels": before},
+		After:      map[string]interface{}{"labels": labels},
+	})
+	return nil
+}
+
+// labelSnapshot maps each label of the prompt to the version number it points at
+func (d *auditDecorator) labelSnapshot(ctx context.Context, projectID, promptID ulid.ULID) map[string]interface{} {
+	versions, err := d.PromptService.ListVersions(ctx, projectID, promptID)
+	if err != nil {
+		return nil
+	}
+	labels := make(map[string]interface{})
+	for _, version := range versions {
+		for _, label := range version.Labels {
+			labels[label] = version.Version
+		}
+	}
+	return labels
+}
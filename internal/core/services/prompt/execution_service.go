@@ -114,6 +114,7 @@ func (s *executionService) Execute(ctx context.Context, prompt *promptDomain.Pro
 			CompiledPrompt: compiled,
 			LatencyMs:      latencyMs,
 			Error:          err.Error(),
+			RateLimited:    promptDomain.IsRateLimitError(err),
 		}, nil
 	}
 
@@ -429,6 +430,10 @@ func (s *executionService) executeOpenAICompatible(ctx context.Context, promptTy
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, promptDomain.NewProviderRateLimitedError(string(provider), string(respBody))
+	}
+
 	var openAIResp openAIResponse
 	if err := json.Unmarshal(respBody, &openAIResp); err != nil {
 		return nil, fmt.Errorf("failed to parse response: %w", err)
@@ -586,6 +591,10 @@ func (s *executionService) executeAnthropic(ctx context.Context, promptType prom
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, promptDomain.NewProviderRateLimitedError("Anthropic", string(respBody))
+	}
+
 	var anthropicResp anthropicResponse
 	if err := json.Unmarshal(respBody, &anthropicResp); err != nil {
 		return nil, fmt.Errorf("failed to parse response: %w", err)
@@ -883,6 +892,10 @@ func (s *executionService) executeGemini(ctx context.Context, promptType promptD
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, promptDomain.NewProviderRateLimitedError("Gemini", string(respBody))
+	}
+
 	var geminiResp geminiResponse
 	if err := json.Unmarshal(respBody, &geminiResp); err != nil {
 		return nil, fmt.Errorf("failed to parse response: %w", err)
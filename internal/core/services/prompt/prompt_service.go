@@ -10,6 +10,7 @@ import (
 
 	"brokle/internal/core/domain/common"
 	promptDomain "brokle/internal/core/domain/prompt"
+	"brokle/internal/core/domain/webhook"
 	appErrors "brokle/pkg/errors"
 	"brokle/pkg/ulid"
 )
@@ -31,6 +32,7 @@ type promptService struct {
 	protectedLabelRepo  promptDomain.ProtectedLabelRepository
 	cacheRepo           promptDomain.CacheRepository
 	compiler            promptDomain.CompilerService
+	publisher           webhook.Publisher // Optional: emits prompt.label.moved
 	logger              *slog.Logger
 }
 
@@ -42,6 +44,7 @@ func NewPromptService(
 	protectedLabelRepo promptDomain.ProtectedLabelRepository,
 	cacheRepo promptDomain.CacheRepository,
 	compiler promptDomain.CompilerService,
+	publisher webhook.Publisher,
 	logger *slog.Logger,
 ) promptDomain.PromptService {
 	return &promptService{
@@ -52,6 +55,7 @@ func NewPromptService(
 		protectedLabelRepo: protectedLabelRepo,
 		cacheRepo:          cacheRepo,
 		compiler:           compiler,
+		publisher:          publisher,
 		logger:             logger,
 	}
 }
@@ -829,6 +833,7 @@ func (s *promptService) SetLabels(ctx context.Context, projectID, promptID, vers
 		}
 	}
 
+	var moves []labelMove
 	for _, labelName := range labels {
 		if labelName == promptDomain.LabelLatest {
 			continue
@@ -846,18 +851,48 @@ func (s *promptService) SetLabels(ctx context.Context, projectID, promptID, vers
 			return appErrors.NewForbiddenError(fmt.Sprintf("label '%s' is protected and requires admin permissions to modify", labelName))
 		}
 
+		// Remember where the label pointed before so the move can be reported
+		var previousVersionID *ulid.ULID
+		if s.publisher != nil {
+			if existing, err := s.labelRepo.GetByPromptAndName(ctx, promptID, labelName); err == nil && existing != nil {
+				previousVersionID = &existing.VersionID
+			}
+		}
+
 		if err := s.labelRepo.SetLabel(ctx, promptID, versionID, labelName, userID); err != nil {
 			return appErrors.NewInternalError(fmt.Sprintf("failed to set label %s", labelName), err)
 		}
+
+		if s.publisher != nil && (previousVersionID == nil || *previousVersionID != versionID) {
+			moves = append(moves, labelMove{name: labelName, previousVersionID: previousVersionID})
+		}
 	}
 
 	if err := s.InvalidateCache(ctx, prompt.ProjectID, prompt.Name); err != nil {
 		s.logger.Warn("failed to invalidate cache", "project_id", prompt.ProjectID, "name", prompt.Name, "error", err)
 	}
 
+	for _, move := range moves {
+		s.publisher.Publish(ctx, prompt.ProjectID, webhook.EventPromptLabelMoved, map[string]interface{}{
+			"prompt_id":           prompt.ID,
+			"prompt_name":         prompt.Name,
+			"label":               move.name,
+			"version_id":          version.ID,
+			"version":             version.Version,
+			"previous_version_id": move.previousVersionID,
+			"moved_by":            userID,
+		})
+	}
+
 	return nil
 }
 
+// labelMove records a label that now points at a different version.
+type labelMove struct {
+	name              string
+	previousVersionID *ulid.ULID
+}
+
 func (s *promptService) RemoveLabel(ctx context.Context, projectID, promptID ulid.ULID, userID *ulid.ULID, labelName string) error {
 	if labelName == promptDomain.LabelLatest {
 		return appErrors.NewValidationError("label", "'latest' label cannot be removed")
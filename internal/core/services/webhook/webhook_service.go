@@ -0,0 +1,702 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+
+	"brokle/internal/config"
+	"brokle/internal/core/domain/webhook"
+	appErrors "brokle/pkg/errors"
+	"brokle/pkg/ulid"
+)
+
+const (
+	// Subscriptions are cached per project so high-volume events (trace.created)
+	// do not hit PostgreSQL for projects without webhooks. Changes made through this
+	// service invalidate immediately; other processes pick them up after the TTL.
+	subscriptionCacheTTL  = 30 * time.Second
+	subscriptionCacheSize = 10000
+
+	deliveryConcurrency = 10
+	deliveryMaxBackoff  = 6 * time.Hour
+	deliveryUserAgent   = "Brokle-Webhooks/1.0"
+
+	// Receiver responses are never stored or shown; this much is read to reuse the connection.
+	deliveryDrainLimit = 64 << 10
+)
+
+// ErrInternalAddress is returned by the delivery client for non-public addresses.
+var ErrInternalAddress = errors.New("webhook endpoint resolves to an internal address")
+
+type subscriptionCacheEntry struct {
+	subscriptions []*webhook.Subscription
+	expiresAt     time.Time
+}
+
+type webhookService struct {
+	subscriptionRepo webhook.SubscriptionRepository
+	deliveryRepo     webhook.DeliveryRepository
+	config           *config.WebhooksConfig
+	httpClient       *http.Client
+	logger           *slog.Logger
+	cache            *lru.Cache[ulid.ULID, *subscriptionCacheEntry]
+}
+
+// NewWebhookService creates a new webhook subscription service.
+func NewWebhookService(
+	subscriptionRepo webhook.SubscriptionRepository,
+	deliveryRepo webhook.DeliveryRepository,
+	cfg *config.WebhooksConfig,
+	logger *slog.Logger,
+) webhook.Service {
+	cache, _ := lru.New[ulid.ULID, *subscriptionCacheEntry](subscriptionCacheSize)
+
+	timeout := time.Duration(cfg.TimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	return &webhookService{
+		subscriptionRepo: subscriptionRepo,
+		deliveryRepo:     deliveryRepo,
+		config:           cfg,
+		httpClient:       NewDeliveryClient(timeout),
+		logger:           logger,
+		cache:            cache,
+	}
+}
+
+// NewDeliveryClient returns the client tenant-configured URLs are called with. Endpoints
+// are checked when saved, but DNS can change afterwards, so the dialer re-checks every
+// address it connects to. Proxies are bypassed and redirects are not followed for the
+// same reason.
+func NewDeliveryClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !webhook.IsPublicAddress(addrPort.Addr()) {
+				return fmt.Errorf("%w: %s", ErrInternalAddress, addrPort.Addr())
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// checkEndpointAddress resolves the endpoint's host and rejects it if any address is
+// internal, so misconfigured subscriptions fail when saved rather than on delivery.
+func checkEndpointAddress(ctx context.Context, endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return appErrors.NewValidationError("url", "url must be an absolute URL")
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", u.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return appErrors.NewValidationError("url", "url host could not be resolved")
+	}
+	for _, addr := range addrs {
+		if !webhook.IsPublicAddress(addr) {
+			return appErrors.NewValidationError("url", "url must not point to an internal address")
+		}
+	}
+	return nil
+}
+
+// ============================================================================
+// Subscription management
+// ============================================================================
+
+func (s *webhookService) Create(ctx context.Context, projectID ulid.ULID, userID *ulid.ULID, req *webhook.CreateSubscriptionRequest) (*webhook.Subscription, error) {
+	secret, err := webhook.GenerateSecret()
+	if err != nil {
+		return nil, appErrors.NewInternalError("failed to generate webhook secret", err)
+	}
+
+	sub := webhook.NewSubscription(projectID, req.Name, req.URL, secret, dedupeEventTypes(req.EventTypes))
+	sub.Description = req.Description
+	sub.CreatedBy = userID
+	if req.Enabled != nil {
+		sub.Enabled = *req.Enabled
+	}
+
+	if validationErrors := sub.Validate(); len(validationErrors) > 0 {
+		return nil, appErrors.NewValidationError(validationErrors[0].Field, validationErrors[0].Message)
+	}
+	if err := checkEndpointAddress(ctx, sub.URL); err != nil {
+		return nil, err
+	}
+
+	exists, err := s.subscriptionRepo.ExistsByName(ctx, projectID, sub.Name)
+	if err != nil {
+		return nil, appErrors.NewInternalError("failed to check webhook name", err)
+	}
+	if exists {
+		return nil, appErrors.NewConflictError(fmt.Sprintf("webhook '%s' already exists in this project", sub.Name))
+	}
+
+	if err := s.subscriptionRepo.Create(ctx, sub); err != nil {
+		if errors.Is(err, webhook.ErrSubscriptionExists) {
+			return nil, appErrors.NewConflictError(fmt.Sprintf("webhook '%s' already exists in this project", sub.Name))
+		}
+		return nil, appErrors.NewInternalError("failed to create webhook", err)
+	}
+
+	s.invalidate(projectID)
+
+	s.logger.Info("webhook subscription created",
+		"subscription_id", sub.ID,
+		"project_id", projectID,
+		"event_types", sub.EventTypes,
+	)
+
+	return sub, nil
+}
+
+func (s *webhookService) Get(ctx context.Context, projectID, id ulid.ULID) (*webhook.Subscription, error) {
+	sub, err := s.subscriptionRepo.GetByID(ctx, id, projectID)
+	if err != nil {
+		if errors.Is(err, webhook.ErrSubscriptionNotFound) {
+			return nil, appErrors.NewNotFoundError(fmt.Sprintf("webhook %s", id))
+		}
+		return nil, appErrors.NewInternalError("failed to get webhook", err)
+	}
+	return sub, nil
+}
+
+func (s *webhookService) List(ctx context.Context, projectID ulid.ULID) ([]*webhook.Subscription, error) {
+	subs, err := s.subscriptionRepo.List(ctx, projectID)
+	if err != nil {
+		return nil, appErrors.NewInternalError("failed to list webhooks", err)
+	}
+	return subs, nil
+}
+
+func (s *webhookService) Update(ctx context.Context, projectID, id ulid.ULID, req *webhook.UpdateSubscriptionRequest) (*webhook.Subscription, error) {
+	sub, err := s.Get(ctx, projectID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil && *req.Name != sub.Name {
+		exists, err := s.subscriptionRepo.ExistsByName(ctx, projectID, *req.Name)
+		if err != nil {
+			return nil, appErrors.NewInternalError("failed to check webhook name", err)
+		}
+		if exists {
+			return nil, appErrors.NewConflictError(fmt.Sprintf("webhook '%s' already exists in this project", *req.Name))
+		}
+		sub.Name = *req.Name
+	}
+	if req.Description != nil {
+		sub.Description = req.Description
+	}
+	if req.URL != nil {
+		sub.URL = *req.URL
+	}
+	if req.EventTypes != nil {
+		sub.EventTypes = dedupeEventTypes(req.EventTypes)
+	}
+	if req.Enabled != nil {
+		sub.Enabled = *req.Enabled
+	}
+	sub.UpdatedAt = time.Now()
+
+	if validationErrors := sub.Validate(); len(validationErrors) > 0 {
+		return nil, appErrors.NewValidationError(validationErrors[0].Field, validationErrors[0].Message)
+	}
+	if req.URL != nil {
+		if err := checkEndpointAddress(ctx, sub.URL); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.subscriptionRepo.Update(ctx, sub); err != nil {
+		if errors.Is(err, webhook.ErrSubscriptionExists) {
+			return nil, appErrors.NewConflictError(fmt.Sprintf("webhook '%s' already exists in this project", sub.Name))
+		}
+		if errors.Is(err, webhook.ErrSubscriptionNotFound) {
+			return nil, appErrors.NewNotFoundError(fmt.Sprintf("webhook %s", id))
+		}
+		return nil, appErrors.NewInternalError("failed to update webhook", err)
+	}
+
+	s.invalidate(projectID)
+
+	s.logger.Info("webhook subscription updated",
+		"subscription_id", id,
+		"project_id", projectID,
+		"enabled", sub.Enabled,
+	)
+
+	return sub, nil
+}
+
+func (s *webhookService) Delete(ctx context.Context, projectID, id ulid.ULID) error {
+	if err := s.subscriptionRepo.Delete(ctx, id, projectID); err != nil {
+		if errors.Is(err, webhook.ErrSubscriptionNotFound) {
+			return appErrors.NewNotFoundError(fmt.Sprintf("webhook %s", id))
+		}
+		return appErrors.NewInternalError("failed to delete webhook", err)
+	}
+
+	s.invalidate(projectID)
+
+	s.logger.Info("webhook subscription deleted",
+		"subscription_id", id,
+		"project_id", projectID,
+	)
+
+	return nil
+}
+
+func (s *webhookService) RotateSecret(ctx context.Context, projectID, id ulid.ULID) (*webhook.Subscription, error) {
+	sub, err := s.Get(ctx, projectID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := webhook.GenerateSecret()
+	if err != nil {
+		return nil, appErrors.NewInternalError("failed to generate webhook secret", err)
+	}
+	sub.Secret = secret
+	sub.UpdatedAt = time.Now()
+
+	if err := s.subscriptionRepo.Update(ctx, sub); err != nil {
+		return nil, appErrors.NewInternalError("failed to rotate webhook secret", err)
+	}
+
+	s.invalidate(projectID)
+
+	s.logger.Info("webhook secret rotated",
+		"subscription_id", id,
+		"project_id", projectID,
+	)
+
+	return sub, nil
+}
+
+func (s *webhookService) SendTest(ctx context.Context, projectID, id ulid.ULID) (*webhook.Delivery, error) {
+	sub, err := s.Get(ctx, projectID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !sub.Enabled {
+		return nil, appErrors.NewBadRequestError("webhook is disabled", "enable the webhook before sending a test event")
+	}
+
+	deliveries, err := s.buildDeliveries([]*webhook.Subscription{sub}, webhook.EventPing, map[string]interface{}{
+		"subscription_id": sub.ID,
+		"message":         "Test event from Brokle",
+	})
+	if err != nil {
+		return nil, appErrors.NewInternalError("failed to build test event", err)
+	}
+
+	if err := s.deliveryRepo.CreateBatch(ctx, deliveries); err != nil {
+		return nil, appErrors.NewInternalError("failed to queue test event", err)
+	}
+
+	return deliveries[0], nil
+}
+
+func (s *webhookService) ListDeliveries(ctx context.Context, projectID, subscriptionID ulid.ULID, filter *webhook.DeliveryFilter, page, limit int) ([]*webhook.Delivery, int64, error) {
+	if _, err := s.Get(ctx, projectID, subscriptionID); err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	deliveries, total, err := s.deliveryRepo.List(ctx, subscriptionID, filter, offset, limit)
+	if err != nil {
+		return nil, 0, appErrors.NewInternalError("failed to list webhook deliveries", err)
+	}
+	return deliveries, total, nil
+}
+
+func (s *webhookService) Redeliver(ctx context.Context, projectID, subscriptionID, deliveryID ulid.ULID) (*webhook.Delivery, error) {
+	sub, err := s.Get(ctx, projectID, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if !sub.Enabled {
+		return nil, appErrors.NewBadRequestError("webhook is disabled", "enable the webhook before redelivering events")
+	}
+
+	original, err := s.deliveryRepo.GetByID(ctx, deliveryID, subscriptionID)
+	if err != nil {
+		if errors.Is(err, webhook.ErrDeliveryNotFound) {
+			return nil, appErrors.NewNotFoundError(fmt.Sprintf("webhook delivery %s", deliveryID))
+		}
+		return nil, appErrors.NewInternalError("failed to get webhook delivery", err)
+	}
+
+	// Same event ID and payload so receivers can deduplicate
+	delivery := webhook.NewDelivery(sub, &webhook.Event{ID: original.EventID, Type: original.EventType}, original.Payload)
+	if err := s.deliveryRepo.CreateBatch(ctx, []*webhook.Delivery{delivery}); err != nil {
+		return nil, appErrors.NewInternalError("failed to queue redelivery", err)
+	}
+
+	s.logger.Info("webhook redelivery queued",
+		"subscription_id", subscriptionID,
+		"original_delivery_id", deliveryID,
+		"delivery_id", delivery.ID,
+	)
+
+	return delivery, nil
+}
+
+// ============================================================================
+// Publishing
+// ============================================================================
+
+func (s *webhookService) Publish(ctx context.Context, projectID ulid.ULID, eventType webhook.EventType, data interface{}) {
+	subs, err := s.enabledSubscriptions(ctx, projectID)
+	if err != nil {
+		s.logger.Warn("failed to load webhook subscriptions", "project_id", projectID, "event_type", eventType, "error", err)
+		return
+	}
+	s.enqueue(ctx, subs, eventType, data)
+}
+
+func (s *webhookService) PublishToOrganization(ctx context.Context, orgID ulid.ULID, eventType webhook.EventType, data interface{}) {
+	subs, err := s.subscriptionRepo.ListEnabledByOrganization(ctx, orgID)
+	if err != nil {
+		s.logger.Warn("failed to load webhook subscriptions", "organization_id", orgID, "event_type", eventType, "error", err)
+		return
+	}
+	s.enqueue(ctx, subs, eventType, data)
+}
+
+func (s *webhookService) enqueue(ctx context.Context, subs []*webhook.Subscription, eventType webhook.EventType, data interface{}) {
+	matching := make([]*webhook.Subscription, 0, len(subs))
+	for _, sub := range subs {
+		if sub.Subscribes(eventType) {
+			matching = append(matching, sub)
+		}
+	}
+	if len(matching) == 0 {
+		return
+	}
+
+	deliveries, err := s.buildDeliveries(matching, eventType, data)
+	if err != nil {
+		s.logger.Warn("failed to build webhook event", "event_type", eventType, "error", err)
+		return
+	}
+
+	if err := s.deliveryRepo.CreateBatch(ctx, deliveries); err != nil {
+		s.logger.Warn("failed to queue webhook deliveries", "event_type", eventType, "count", len(deliveries), "error", err)
+	}
+}
+
+// buildDeliveries creates one pending delivery per subscription. All deliveries
+// share the event ID; the envelope carries the subscription's own project ID.
+func (s *webhookService) buildDeliveries(subs []*webhook.Subscription, eventType webhook.EventType, data interface{}) ([]*webhook.Delivery, error) {
+	eventID := ulid.New()
+	createdAt := time.Now().UTC()
+	payloads := make(map[ulid.ULID]json.RawMessage)
+
+	deliveries := make([]*webhook.Delivery, 0, len(subs))
+	for _, sub := range subs {
+		event := &webhook.Event{
+			ID:        eventID,
+			Type:      eventType,
+			ProjectID: sub.ProjectID,
+			CreatedAt: createdAt,
+			Data:      data,
+		}
+
+		payload, ok := payloads[sub.ProjectID]
+		if !ok {
+			var err error
+			payload, err = json.Marshal(event)
+			if err != nil {
+				return nil, err
+			}
+			payloads[sub.ProjectID] = payload
+		}
+
+		deliveries = append(deliveries, webhook.NewDelivery(sub, event, payload))
+	}
+	return deliveries, nil
+}
+
+func (s *webhookService) enabledSubscriptions(ctx context.Context, projectID ulid.ULID) ([]*webhook.Subscription, error) {
+	if entry, ok := s.cache.Get(projectID); ok && time.Now().Before(entry.expiresAt) {
+		return entry.subscriptions, nil
+	}
+
+	subs, err := s.subscriptionRepo.ListEnabledByProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.Add(projectID, &subscriptionCacheEntry{
+		subscriptions: subs,
+		expiresAt:     time.Now().Add(subscriptionCacheTTL),
+	})
+	return subs, nil
+}
+
+func (s *webhookService) invalidate(projectID ulid.ULID) {
+	s.cache.Remove(projectID)
+}
+
+// ============================================================================
+// Delivery
+// ============================================================================
+
+// deliveryOutcome classifies a single attempt.
+type deliveryOutcome int
+
+const (
+	outcomeSucceeded deliveryOutcome = iota
+	outcomeRetryable
+	outcomePermanent
+)
+
+func (s *webhookService) ProcessDueDeliveries(ctx context.Context, now time.Time) (*webhook.DeliverySummary, error) {
+	batchSize := s.config.BatchSize
+	if batchSize <= 0 {
+		batchSize = 50
+	}
+
+	// The lease outlives the slowest possible attempt so a claimed row is never sent twice concurrently
+	lease := s.httpClient.Timeout + time.Minute
+
+	deliveries, err := s.deliveryRepo.ClaimDue(ctx, now, lease, batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("claim due webhook deliveries: %w", err)
+	}
+
+	summary := &webhook.DeliverySummary{Claimed: len(deliveries)}
+	if len(deliveries) == 0 {
+		return summary, nil
+	}
+
+	subIDs := make([]ulid.ULID, 0, len(deliveries))
+	seen := make(map[ulid.ULID]bool)
+	for _, d := range deliveries {
+		if !seen[d.SubscriptionID] {
+			seen[d.SubscriptionID] = true
+			subIDs = append(subIDs, d.SubscriptionID)
+		}
+	}
+
+	subs, err := s.subscriptionRepo.GetByIDs(ctx, subIDs)
+	if err != nil {
+		return nil, fmt.Errorf("load webhook subscriptions: %w", err)
+	}
+	subsByID := make(map[ulid.ULID]*webhook.Subscription, len(subs))
+	for _, sub := range subs {
+		subsByID[sub.ID] = sub
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, deliveryConcurrency)
+
+	for _, d := range deliveries {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(d *webhook.Delivery) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			status := s.deliver(ctx, subsByID[d.SubscriptionID], d)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch status {
+			case webhook.DeliveryStatusSucceeded:
+				summary.Succeeded++
+			case webhook.DeliveryStatusFailed:
+				summary.Failed++
+			default:
+				summary.Retried++
+			}
+		}(d)
+	}
+	wg.Wait()
+
+	return summary, nil
+}
+
+// deliver makes one attempt and persists the outcome, returning the resulting status.
+func (s *webhookService) deliver(ctx context.Context, sub *webhook.Subscription, d *webhook.Delivery) webhook.DeliveryStatus {
+	var outcome deliveryOutcome
+	switch {
+	case sub == nil:
+		outcome = recordFailure(d, "webhook subscription no longer exists")
+	case !sub.Enabled:
+		outcome = recordFailure(d, "webhook subscription is disabled")
+	default:
+		outcome = s.attempt(ctx, sub, d)
+	}
+
+	now := time.Now()
+	switch {
+	case outcome == outcomeSucceeded:
+		d.Status = webhook.DeliveryStatusSucceeded
+		d.DeliveredAt = &now
+		d.NextAttemptAt = nil
+	case outcome == outcomeRetryable && d.Attempts < s.maxAttempts():
+		next := now.Add(deliveryBackoff(s.retryBase(), d.Attempts))
+		d.Status = webhook.DeliveryStatusPending
+		d.NextAttemptAt = &next
+	default:
+		d.Status = webhook.DeliveryStatusFailed
+		d.NextAttemptAt = nil
+	}
+
+	if err := s.deliveryRepo.Update(ctx, d); err != nil {
+		s.logger.Error("failed to record webhook delivery", "delivery_id", d.ID, "error", err)
+	}
+
+	if d.Status == webhook.DeliveryStatusFailed {
+		s.logger.Warn("webhook delivery failed",
+			"delivery_id", d.ID,
+			"subscription_id", d.SubscriptionID,
+			"event_type", d.EventType,
+			"attempts", d.Attempts,
+			"response_code", d.ResponseCode,
+		)
+	}
+
+	return d.Status
+}
+
+// attempt sends a signed POST and records the response status on the delivery.
+// Network errors, 408, 429 and 5xx are retryable; other non-2xx responses, including
+// redirects, and internal addresses are permanent.
+func (s *webhookService) attempt(ctx context.Context, sub *webhook.Subscription, d *webhook.Delivery) deliveryOutcome {
+	d.Attempts++
+	d.ResponseCode = nil
+	d.Error = nil
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return recordFailure(d, fmt.Sprintf("invalid request: %v", err))
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", deliveryUserAgent)
+	req.Header.Set(webhook.EventHeader, string(d.EventType))
+	req.Header.Set(webhook.DeliveryHeader, d.ID.String())
+	req.Header.Set(webhook.TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(webhook.SignatureHeader, webhook.SignPayload(sub.Secret, timestamp, d.Payload))
+
+	start := time.Now()
+	resp, err := s.httpClient.Do(req)
+	durationMs := time.Since(start).Milliseconds()
+	d.DurationMs = &durationMs
+
+	if err != nil {
+		if errors.Is(err, ErrInternalAddress) {
+			return recordFailure(d, ErrInternalAddress.Error())
+		}
+		msg := err.Error()
+		d.Error = &msg
+		return outcomeRetryable
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, deliveryDrainLimit))
+	code := resp.StatusCode
+	d.ResponseCode = &code
+
+	outcome := classifyStatus(code)
+	if outcome != outcomeSucceeded {
+		msg := fmt.Sprintf("receiver returned status %d", code)
+		d.Error = &msg
+	}
+	return outcome
+}
+
+func recordFailure(d *webhook.Delivery, msg string) deliveryOutcome {
+	d.Error = &msg
+	return outcomePermanent
+}
+
+func (s *webhookService) maxAttempts() int {
+	if s.config.MaxAttempts <= 0 {
+		return 8
+	}
+	return s.config.MaxAttempts
+}
+
+func (s *webhookService) retryBase() time.Duration {
+	if s.config.RetryBackoffSeconds <= 0 {
+		return 30 * time.Second
+	}
+	return time.Duration(s.config.RetryBackoffSeconds) * time.Second
+}
+
+func classifyStatus(code int) deliveryOutcome {
+	switch {
+	case code >= 200 && code < 300:
+		return outcomeSucceeded
+	case code == http.StatusRequestTimeout, code == http.StatusTooManyRequests, code >= 500:
+		return outcomeRetryable
+	default:
+		return outcomePermanent
+	}
+}
+
+// deliveryBackoff returns the delay before the next attempt: base * 2^(attempt-1), capped.
+func deliveryBackoff(base time.Duration, attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	backoff := base
+	for i := 1; i < attempt; i++ {
+		backoff *= 2
+		if backoff >= deliveryMaxBackoff {
+			return deliveryMaxBackoff
+		}
+	}
+	return backoff
+}
+
+func dedupeEventTypes(eventTypes []string) []string {
+	seen := make(map[string]bool, len(eventTypes))
+	result := make([]string, 0, len(eventTypes))
+	for _, t := range eventTypes {
+		if !seen[t] {
+			seen[t] = true
+			result = append(result, t)
+		}
+	}
+	return result
+}
@@ -0,0 +1,387 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"brokle/internal/config"
+	"brokle/internal/core/domain/webhook"
+	"brokle/pkg/ulid"
+)
+
+// ============================================================================
+// Fakes
+// ============================================================================
+
+type fakeSubscriptionRepo struct {
+	webhook.SubscriptionRepository
+	subs      []*webhook.Subscription
+	listCalls int
+}
+
+func (r *fakeSubscriptionRepo) ListEnabledByProject(ctx context.Context, projectID ulid.ULID) ([]*webhook.Subscription, error) {
+	r.listCalls++
+	var result []*webhook.Subscription
+	for _, sub := range r.subs {
+		if sub.ProjectID == projectID && sub.Enabled {
+			result = append(result, sub)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeSubscriptionRepo) GetByIDs(ctx context.Context, ids []ulid.ULID) ([]*webhook.Subscription, error) {
+	var result []*webhook.Subscription
+	for _, sub := range r.subs {
+		for _, id := range ids {
+			if sub.ID == id {
+				result = append(result, sub)
+			}
+		}
+	}
+	return result, nil
+}
+
+type fakeDeliveryRepo struct {
+	webhook.DeliveryRepository
+	mu      sync.Mutex
+	created []*webhook.Delivery
+	updated []*webhook.Delivery
+	due     []*webhook.Delivery
+}
+
+func (r *fakeDeliveryRepo) CreateBatch(ctx context.Context, deliveries []*webhook.Delivery) error {
+	r.created = append(r.created, deliveries...)
+	return nil
+}
+
+func (r *fakeDeliveryRepo) Update(ctx context.Context, d *webhook.Delivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.updated = append(r.updated, d)
+	return nil
+}
+
+func (r *fakeDeliveryRepo) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*webhook.Delivery, error) {
+	due := r.due
+	r.due = nil
+	return due, nil
+}
+
+func newTestService(subs []*webhook.Subscription, cfg *config.WebhooksConfig) (*webhookService, *fakeSubscriptionRepo, *fakeDeliveryRepo) {
+	if cfg == nil {
+		cfg = &config.WebhooksConfig{}
+	}
+	subRepo := &fakeSubscriptionRepo{subs: subs}
+	deliveryRepo := &fakeDeliveryRepo{}
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	svc := NewWebhookService(subRepo, deliveryRepo, cfg, logger).(*webhookService)
+	// Test receivers listen on loopback, which the delivery client refuses
+	svc.httpClient = &http.Client{Timeout: 5 * time.Second}
+	return svc, subRepo, deliveryRepo
+}
+
+func newTestSubscription(projectID ulid.ULID, endpoint string, eventTypes ...webhook.EventType) *webhook.Subscription {
+	types := make([]string, len(eventTypes))
+	for i, t := range eventTypes {
+		types[i] = string(t)
+	}
+	return webhook.NewSubscription(projectID, "test", endpoint, "whsec_test", types)
+}
+
+// ============================================================================
+// Domain
+// ============================================================================
+
+func TestSubscription_Subscribes(t *testing.T) {
+	sub := newTestSubscription(ulid.New(), "https://example.com", webhook.EventScoreCreated)
+
+	assert.True(t, sub.Subscribes(webhook.EventScoreCreated))
+	assert.False(t, sub.Subscribes(webhook.EventTraceCreated))
+	assert.True(t, sub.Subscribes(webhook.EventPing), "ping ignores the event filter")
+
+	sub.Enabled = false
+	assert.False(t, sub.Subscribes(webhook.EventScoreCreated))
+	assert.False(t, sub.Subscribes(webhook.EventPing))
+}
+
+func TestSubscription_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		endpoint  string
+		types     []string
+		wantField string
+	}{
+		{"valid", "https://example.com/hook", []string{"trace.created"}, ""},
+		{"relative url", "/hook", []string{"trace.created"}, "url"},
+		{"unsupported scheme", "ftp://example.com", []string{"trace.created"}, "url"},
+		{"plain http", "http://example.com/hook", []string{"trace.created"}, "url"},
+		{"localhost", "https://localhost:8080/hook", []string{"trace.created"}, "url"},
+		{"loopback", "https://127.0.0.1/hook", []string{"trace.created"}, "url"},
+		{"ipv6 loopback", "https://[::1]/hook", []string{"trace.created"}, "url"},
+		{"private network", "https://10.1.2.3/hook", []string{"trace.created"}, "url"},
+		{"cloud metadata", "https://169.254.169.254/latest/meta-data", []string{"trace.created"}, "url"},
+		{"mapped private address", "https://[::ffff:192.168.0.1]/hook", []string{"trace.created"}, "url"},
+		{"public address", "https://203.0.114.7/hook", []string{"trace.created"}, ""},
+		{"no event types", "https://example.com", nil, "event_types"},
+		{"unknown event type", "https://example.com", []string{"trace.deleted"}, "event_types"},
+		{"ping is not subscribable", "https://example.com", []string{"ping"}, "event_types"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := webhook.NewSubscription(ulid.New(), "hook", tt.endpoint, "s", tt.types)
+			errs := sub.Validate()
+			if tt.wantField == "" {
+				assert.Empty(t, errs)
+				return
+			}
+			require.NotEmpty(t, errs)
+			assert.Equal(t, tt.wantField, errs[0].Field)
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	a, err := webhook.GenerateSecret()
+	require.NoError(t, err)
+	b, err := webhook.GenerateSecret()
+	require.NoError(t, err)
+
+	assert.Len(t, a, len(webhook.SecretPrefix)+64)
+	assert.Equal(t, webhook.SecretPrefix, a[:len(webhook.SecretPrefix)])
+	assert.NotEqual(t, a, b)
+}
+
+// ============================================================================
+// Publishing
+// ============================================================================
+
+func TestPublish_FiltersAndSharesEventID(t *testing.T) {
+	projectID := ulid.New()
+	scoreHook := newTestSubscription(projectID, "https://a.example.com", webhook.EventScoreCreated)
+	allHook := newTestSubscription(projectID, "https://b.example.com", webhook.EventScoreCreated, webhook.EventTraceCreated)
+	traceHook := newTestSubscription(projectID, "https://c.example.com", webhook.EventTraceCreated)
+	otherProject := newTestSubscription(ulid.New(), "https://d.example.com", webhook.EventScoreCreated)
+
+	svc, subRepo, deliveryRepo := newTestService([]*webhook.Subscription{scoreHook, allHook, traceHook, otherProject}, nil)
+
+	svc.Publish(context.Background(), projectID, webhook.EventScoreCreated, map[string]interface{}{"name": "accuracy"})
+
+	require.Len(t, deliveryRepo.created, 2)
+	assert.Equal(t, scoreHook.ID, deliveryRepo.created[0].SubscriptionID)
+	assert.Equal(t, allHook.ID, deliveryRepo.created[1].SubscriptionID)
+	assert.Equal(t, deliveryRepo.created[0].EventID, deliveryRepo.created[1].EventID)
+	for _, d := range deliveryRepo.created {
+		assert.Equal(t, webhook.DeliveryStatusPending, d.Status)
+		assert.NotNil(t, d.NextAttemptAt)
+		assert.JSONEq(t, `"accuracy"`, string(extractField(t, d.Payload, "data", "name")))
+	}
+
+	// Subscriptions are cached per project
+	svc.Publish(context.Background(), projectID, webhook.EventTraceCreated, nil)
+	assert.Equal(t, 1, subRepo.listCalls)
+	assert.Len(t, deliveryRepo.created, 4)
+
+	// No matching subscription, nothing queued
+	svc.Publish(context.Background(), ulid.New(), webhook.EventScoreCreated, nil)
+	assert.Len(t, deliveryRepo.created, 4)
+}
+
+// ============================================================================
+// Delivery
+// ============================================================================
+
+func TestAttempt_SignsRequest(t *testing.T) {
+	var gotHeaders http.Header
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeaders = r.Header.Clone()
+		gotBody, _ = io.ReadAll(r.Body)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	projectID := ulid.New()
+	sub := newTestSubscription(projectID, server.URL, webhook.EventScoreCreated)
+	svc, _, _ := newTestService([]*webhook.Subscription{sub}, nil)
+
+	deliveries, err := svc.buildDeliveries([]*webhook.Subscription{sub}, webhook.EventScoreCreated, map[string]string{"id": "s1"})
+	require.NoError(t, err)
+	d := deliveries[0]
+
+	outcome := svc.attempt(context.Background(), sub, d)
+	assert.Equal(t, outcomeSucceeded, outcome)
+	assert.Equal(t, 1, d.Attempts)
+	require.NotNil(t, d.ResponseCode)
+	assert.Equal(t, http.StatusOK, *d.ResponseCode)
+
+	assert.Equal(t, string(d.Payload), string(gotBody))
+	assert.Equal(t, "score.created", gotHeaders.Get(webhook.EventHeader))
+	assert.Equal(t, d.ID.String(), gotHeaders.Get(webhook.DeliveryHeader))
+
+	ts, err := strconv.ParseInt(gotHeaders.Get(webhook.TimestampHeader), 10, 64)
+	require.NoError(t, err)
+	assert.Equal(t, webhook.SignPayload("whsec_test", ts, gotBody), gotHeaders.Get(webhook.SignatureHeader))
+}
+
+func TestDeliveryClient_RefusesInternalAddresses(t *testing.T) {
+	var hits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+	}))
+	defer server.Close()
+
+	sub := newTestSubscription(ulid.New(), server.URL, webhook.EventScoreCreated)
+	svc, _, _ := newTestService([]*webhook.Subscription{sub}, nil)
+	svc.httpClient = NewDeliveryClient(5 * time.Second)
+
+	d := webhook.NewDelivery(sub, &webhook.Event{ID: ulid.New(), Type: webhook.EventScoreCreated}, []byte(`{}`))
+	assert.Equal(t, outcomePermanent, svc.attempt(context.Background(), sub, d))
+	require.NotNil(t, d.Error)
+	assert.Contains(t, *d.Error, "internal address")
+	assert.Zero(t, hits)
+}
+
+func TestDeliveryClient_DoesNotFollowRedirects(t *testing.T) {
+	var followed bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/internal" {
+			followed = true
+			return
+		}
+		http.Redirect(w, r, "/internal", http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	sub := newTestSubscription(ulid.New(), server.URL, webhook.EventScoreCreated)
+	svc, _, _ := newTestService([]*webhook.Subscription{sub}, nil)
+	svc.httpClient = NewDeliveryClient(5 * time.Second)
+	svc.httpClient.Transport = http.DefaultTransport // Allow the loopback receiver
+
+	d := webhook.NewDelivery(sub, &webhook.Event{ID: ulid.New(), Type: webhook.EventScoreCreated}, []byte(`{}`))
+	assert.Equal(t, outcomePermanent, svc.attempt(context.Background(), sub, d))
+	require.NotNil(t, d.ResponseCode)
+	assert.Equal(t, http.StatusTemporaryRedirect, *d.ResponseCode)
+	assert.False(t, followed)
+}
+
+func TestProcessDueDeliveries_Outcomes(t *testing.T) {
+	var status int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	projectID := ulid.New()
+	sub := newTestSubscription(projectID, server.URL, webhook.EventScoreCreated)
+
+	tests := []struct {
+		name         string
+		status       int
+		attempts     int
+		wantStatus   webhook.DeliveryStatus
+		wantNextSoon bool
+	}{
+		{"success", http.StatusNoContent, 0, webhook.DeliveryStatusSucceeded, false},
+		{"server error retries", http.StatusBadGateway, 0, webhook.DeliveryStatusPending, true},
+		{"rate limited retries", http.StatusTooManyRequests, 0, webhook.DeliveryStatusPending, true},
+		{"client error is permanent", http.StatusGone, 0, webhook.DeliveryStatusFailed, false},
+		{"retries exhausted", http.StatusInternalServerError, 2, webhook.DeliveryStatusFailed, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status = tt.status
+			svc, _, deliveryRepo := newTestService([]*webhook.Subscription{sub}, &config.WebhooksConfig{MaxAttempts: 3, RetryBackoffSeconds: 10})
+
+			deliveries, err := svc.buildDeliveries([]*webhook.Subscription{sub}, webhook.EventScoreCreated, nil)
+			require.NoError(t, err)
+			d := deliveries[0]
+			d.Attempts = tt.attempts
+			deliveryRepo.due = []*webhook.Delivery{d}
+
+			summary, err := svc.ProcessDueDeliveries(context.Background(), time.Now())
+			require.NoError(t, err)
+			assert.Equal(t, 1, summary.Claimed)
+
+			require.Len(t, deliveryRepo.updated, 1)
+			assert.Equal(t, tt.wantStatus, d.Status)
+			assert.Equal(t, tt.attempts+1, d.Attempts)
+			if tt.wantNextSoon {
+				require.NotNil(t, d.NextAttemptAt)
+				assert.WithinDuration(t, time.Now().Add(deliveryBackoff(10*time.Second, d.Attempts)), *d.NextAttemptAt, 2*time.Second)
+			} else {
+				assert.Nil(t, d.NextAttemptAt)
+			}
+			if tt.wantStatus == webhook.DeliveryStatusSucceeded {
+				assert.NotNil(t, d.DeliveredAt)
+				assert.Equal(t, 1, summary.Succeeded)
+			}
+		})
+	}
+}
+
+func TestProcessDueDeliveries_MissingOrDisabledSubscription(t *testing.T) {
+	projectID := ulid.New()
+	disabled := newTestSubscription(projectID, "http://127.0.0.1:1", webhook.EventScoreCreated)
+	disabled.Enabled = false
+	deleted := newTestSubscription(projectID, "http://127.0.0.1:1", webhook.EventScoreCreated)
+
+	svc, _, deliveryRepo := newTestService([]*webhook.Subscription{disabled}, nil)
+
+	d1 := webhook.NewDelivery(disabled, &webhook.Event{ID: ulid.New(), Type: webhook.EventScoreCreated}, []byte(`{}`))
+	d2 := webhook.NewDelivery(deleted, &webhook.Event{ID: ulid.New(), Type: webhook.EventScoreCreated}, []byte(`{}`))
+	deliveryRepo.due = []*webhook.Delivery{d1, d2}
+
+	summary, err := svc.ProcessDueDeliveries(context.Background(), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 2, summary.Failed)
+	assert.Equal(t, 0, d1.Attempts, "no request is made for a disabled subscription")
+	assert.Equal(t, webhook.DeliveryStatusFailed, d2.Status)
+	require.NotNil(t, d2.Error)
+}
+
+func TestClassifyStatus(t *testing.T) {
+	assert.Equal(t, outcomeSucceeded, classifyStatus(http.StatusOK))
+	assert.Equal(t, outcomeSucceeded, classifyStatus(http.StatusAccepted))
+	assert.Equal(t, outcomeRetryable, classifyStatus(http.StatusRequestTimeout))
+	assert.Equal(t, outcomeRetryable, classifyStatus(http.StatusTooManyRequests))
+	assert.Equal(t, outcomeRetryable, classifyStatus(http.StatusServiceUnavailable))
+	assert.Equal(t, outcomePermanent, classifyStatus(http.StatusMovedPermanently))
+	assert.Equal(t, outcomePermanent, classifyStatus(http.StatusUnauthorized))
+}
+
+func TestDeliveryBackoff(t *testing.T) {
+	base := 30 * time.Second
+	assert.Equal(t, 30*time.Second, deliveryBackoff(base, 1))
+	assert.Equal(t, 60*time.Second, deliveryBackoff(base, 2))
+	assert.Equal(t, 120*time.Second, deliveryBackoff(base, 3))
+	assert.Equal(t, deliveryMaxBackoff, deliveryBackoff(base, 30))
+}
+
+func extractField(t *testing.T, payload []byte, path ...string) []byte {
+	t.Helper()
+	var node interface{}
+	require.NoError(t, json.Unmarshal(payload, &node))
+	for _, key := range path {
+		m, ok := node.(map[string]interface{})
+		require.True(t, ok, "expected object at %s", key)
+		node = m[key]
+	}
+	out, err := json.Marshal(node)
+	require.NoError(t, err)
+	return out
+}
@@ -3,8 +3,9 @@
 
 package compliance
 
-// OSS build uses stub implementation
-// This file is included in the OSS build and ensures stub implementation is used
-func init() {
-	// Initialize stub implementation
+import "brokle/internal/config"
+
+// OSS builds serve audit reports only when the license includes custom compliance
+func licensed(cfg *config.Config) bool {
+	return cfg.CanUseFeature("custom_compliance")
 }
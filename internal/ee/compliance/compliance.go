@@ -1,11 +1,16 @@
 package compliance
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"brokle/pkg/ulid"
+)
 
 // Compliance interface that both stub and real implementation satisfy
 type Compliance interface {
 	ValidateCompliance(ctx context.Context, data interface{}) error
-	GenerateAuditReport(ctx context.Context) ([]byte, error)
+	GenerateAuditReport(ctx context.Context, orgID ulid.ULID, from, to time.Time) ([]byte, error)
 	AnonymizePII(ctx context.Context, data interface{}) (interface{}, error)
 	CheckSOC2Compliance(ctx context.Context) (bool, error)
 	CheckHIPAACompliance(ctx context.Context) (bool, error)
@@ -25,7 +30,7 @@ func (s *StubCompliance) ValidateCompliance(ctx context.Context, data interface{
 	return nil
 }
 
-func (s *StubCompliance) GenerateAuditReport(ctx context.Context) ([]byte, error) {
+func (s *StubCompliance) GenerateAuditReport(ctx context.Context, orgID ulid.ULID, from, to time.Time) ([]byte, error) {
 	// Stub: Returns basic report
 	return []byte("Basic audit report - Enterprise license required for advanced compliance features"), nil
 }
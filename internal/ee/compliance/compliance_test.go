@@ -3,9 +3,12 @@ package compliance
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"brokle/pkg/ulid"
 )
 
 func TestStubCompliance(t *testing.T) {
@@ -23,7 +26,7 @@ func TestStubCompliance(t *testing.T) {
 	})
 
 	t.Run("GenerateAuditReport - returns basic report", func(t *testing.T) {
-		report, err := compliance.GenerateAuditReport(ctx)
+		report, err := compliance.GenerateAuditReport(ctx, ulid.New(), time.Now().Add(-time.Hour), time.Now())
 		assert.NoError(t, err)
 		assert.Contains(t, string(report), "Basic audit report")
 		assert.Contains(t, string(report), "Enterprise license required")
@@ -68,7 +71,7 @@ func TestComplianceInterface(t *testing.T) {
 	// These calls should not panic
 	assert.NotPanics(t, func() {
 		compliance.ValidateCompliance(ctx, nil)
-		compliance.GenerateAuditReport(ctx)
+		compliance.GenerateAuditReport(ctx, ulid.New(), time.Now(), time.Now())
 		compliance.AnonymizePII(ctx, nil)
 		compliance.CheckSOC2Compliance(ctx)
 		compliance.CheckHIPAACompliance(ctx)
@@ -0,0 +1,178 @@
+package compliance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"brokle/internal/config"
+	authDomain "brokle/internal/core/domain/auth"
+	"brokle/internal/core/domain/observability"
+	"brokle/pkg/ulid"
+)
+
+type fakeAuditLogRepository struct {
+	authDomain.AuditLogRepository
+	logs    []*authDomain.AuditLog
+	err     error
+	filters *authDomain.AuditLogFilters
+}
+
+func (r *fakeAuditLogRepository) StreamByFilters(ctx context.Context, filters *authDomain.AuditLogFilters, batchSize int, fn func(batch []*authDomain.AuditLog) error) error {
+	r.filters = filters
+	if r.err != nil {
+		return r.err
+	}
+	for start := 0; start < len(r.logs); start += batchSize {
+		if err := fn(r.logs[start:min(start+batchSize, len(r.logs))]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type fakeDataSubjectRequestRepository struct {
+	observability.DataSubjectRequestRepository
+	overdue       int64
+	err           error
+	createdBefore time.Time
+}
+
+func (r *fakeDataSubjectRequestRepository) CountOverdueErasures(ctx context.Context, createdBefore time.Time) (int64, error) {
+	r.createdBefore = createdBefore
+	return r.overdue, r.err
+}
+
+func newTestService(repo *fakeAuditLogRepository) *Service {
+	service := NewService(Dependencies{AuditLogs: repo, Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+	service.now = func() time.Time { return time.Date(2026, 4, 30, 12, 0, 0, 0, time.UTC) }
+	return service
+}
+
+func TestService_GenerateAuditReport(t *testing.T) {
+	ctx := context.Background()
+	orgID := ulid.New()
+	userID := ulid.New()
+	apiKeyID := ulid.New()
+	from := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 4, 30, 0, 0, 0, 0, time.UTC)
+
+	t.Run("summarizes the organization's audit log", func(t *testing.T) {
+		repo := &fakeAuditLogRepository{logs: []*authDomain.AuditLog{
+			authDomain.NewAuditLog(&userID, &orgID, "api_key.created", "api_key", "k1", "{}", "", ""),
+			authDomain.NewAuditLog(&userID, &orgID, "api_key.deleted", "api_key", "k1", "{}", "", ""),
+			{ID: ulid.New(), APIKeyID: &apiKeyID, OrganizationID: &orgID, Action: "trace.deleted", Resource: "trace"},
+			authDomain.NewAuditLog(nil, &orgID, "trace.deleted", "trace", "t2", "{}", "", ""),
+		}}
+
+		raw, err := newTestService(repo).GenerateAuditReport(ctx, orgID, from, to)
+		require.NoError(t, err)
+
+		assert.Equal(t, &orgID, repo.filters.OrganizationID)
+		assert.Equal(t, from, *repo.filters.StartDate)
+		assert.Equal(t, to, *repo.filters.EndDate)
+
+		var report AuditReport
+		require.NoError(t, json.Unmarshal(raw, &report))
+		assert.Equal(t, orgID, report.OrganizationID)
+		assert.Equal(t, AuditReportPeriod{From: from, To: to}, report.Period)
+		assert.Equal(t, 4, report.Summary.TotalEvents)
+		assert.Equal(t, map[string]int{"api_key.created": 1, "api_key.deleted": 1, "trace.deleted": 2}, report.Summary.ByAction)
+		assert.Equal(t, map[string]int{"api_key": 2, "trace": 2}, report.Summary.ByResource)
+		assert.Equal(t, map[string]int{
+			"user:" + userID.String():      2,
+			"api_key:" + apiKeyID.String(): 1,
+			"system":                       1,
+		}, report.Summary.ByActor)
+		assert.Len(t, report.Events, 4)
+		assert.False(t, report.Truncated)
+	})
+
+	t.Run("caps the event listing but counts everything", func(t *testing.T) {
+		repo := &fakeAuditLogRepository{}
+		for i := 0; i < maxReportEvents+5; i++ {
+			repo.logs = append(repo.logs, authDomain.NewAuditLog(nil, &orgID, "dataset.deleted", "dataset", "", "{}", "", ""))
+		}
+
+		raw, err := newTestService(repo).GenerateAuditReport(ctx, orgID, from, to)
+		require.NoError(t, err)
+
+		var report AuditReport
+		require.NoError(t, json.Unmarshal(raw, &report))
+		assert.Equal(t, maxReportEvents+5, report.Summary.TotalEvents)
+		assert.Len(t, report.Events, maxReportEvents)
+		assert.True(t, report.Truncated)
+	})
+
+	t.Run("rejects an inverted period", func(t *testing.T) {
+		_, err := newTestService(&fakeAuditLogRepository{}).GenerateAuditReport(ctx, orgID, to, from)
+		assert.Error(t, err)
+	})
+
+	t.Run("surfaces repository errors", func(t *testing.T) {
+		_, err := newTestService(&fakeAuditLogRepository{err: errors.New("db down")}).GenerateAuditReport(ctx, orgID, from, to)
+		assert.Error(t, err)
+	})
+}
+
+func TestService_AnonymizePII(t *testing.T) {
+	anonymized, err := newTestService(&fakeAuditLogRepository{}).AnonymizePII(context.Background(), map[string]interface{}{
+		"email": "test@example.com",
+		"notes": []interface{}{"SSN 123-45-6789", 42},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"email": "[REDACTED:email]",
+		"notes": []interface{}{"SSN [REDACTED:ssn]", 42},
+	}, anonymized)
+}
+
+func TestService_CheckGDPRCompliance(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("compliant when no erasure is overdue", func(t *testing.T) {
+		repo := &fakeDataSubjectRequestRepository{}
+		service := newTestService(&fakeAuditLogRepository{})
+		service.deps.DataSubjectRequests = repo
+
+		compliant, err := service.CheckGDPRCompliance(ctx)
+		require.NoError(t, err)
+		assert.True(t, compliant)
+		assert.Equal(t, time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC), repo.createdBefore)
+	})
+
+	t.Run("not compliant with overdue erasures", func(t *testing.T) {
+		service := newTestService(&fakeAuditLogRepository{})
+		service.deps.DataSubjectRequests = &fakeDataSubjectRequestRepository{overdue: 2}
+
+		compliant, err := service.CheckGDPRCompliance(ctx)
+		require.NoError(t, err)
+		assert.False(t, compliant)
+	})
+
+	t.Run("surfaces repository errors", func(t *testing.T) {
+		service := newTestService(&fakeAuditLogRepository{})
+		service.deps.DataSubjectRequests = &fakeDataSubjectRequestRepository{err: errors.New("db down")}
+
+		_, err := service.CheckGDPRCompliance(ctx)
+		require.Error(t, err)
+	})
+
+	t.Run("not compliant without data subject requests", func(t *testing.T) {
+		compliant, err := newTestService(&fakeAuditLogRepository{}).CheckGDPRCompliance(ctx)
+		require.NoError(t, err)
+		assert.False(t, compliant)
+	})
+}
+
+func TestNewProvider_Unlicensed(t *testing.T) {
+	provider := NewProvider(&config.Config{}, Dependencies{})
+	assert.IsType(t, &StubCompliance{}, provider)
+}
@@ -3,7 +3,9 @@
 
 package rbac
 
-// OSS build uses stub implementation with basic roles
-func init() {
-	// Initialize stub RBAC implementation
+import "brokle/internal/config"
+
+// OSS builds assign project roles only when the license includes advanced RBAC
+func licensed(cfg *config.Config) bool {
+	return cfg.CanUseFeature("advanced_rbac")
 }
@@ -0,0 +1,196 @@
+package rbac
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"brokle/internal/config"
+	authDomain "brokle/internal/core/domain/auth"
+	orgDomain "brokle/internal/core/domain/organization"
+	appErrors "brokle/pkg/errors"
+	"brokle/pkg/ulid"
+)
+
+const (
+	auditResourceProjectMember = "project_member"
+	ownerRoleName              = "owner"
+)
+
+// Dependencies wires the licensed RBAC service to the platform
+type Dependencies struct {
+	ProjectMembers authDomain.ProjectMemberRepository
+	Projects       orgDomain.ProjectRepository
+	Members        orgDomain.MemberRepository
+	Roles          authDomain.RoleRepository
+	AuditLogs      authDomain.AuditLogService
+	Logger         *slog.Logger
+}
+
+// Service is the licensed RBAC implementation. Organization members are assigned
+// a role template or one of the organization's custom roles per project, e.g.
+// "Prompt Editor" on one project and "viewer" on another.
+type Service struct {
+	deps   Dependencies
+	logger *slog.Logger
+}
+
+// NewProvider returns the project role service when licensed, the stub otherwise
+func NewProvider(cfg *config.Config, deps Dependencies) RBACManager {
+	if !licensed(cfg) {
+		return New()
+	}
+	return NewService(deps)
+}
+
+// NewService creates the RBAC service
+func NewService(deps Dependencies) *Service {
+	return &Service{
+		deps:   deps,
+		logger: deps.Logger,
+	}
+}
+
+// ListProjectMembers lists the project's role assignments
+func (s *Service) ListProjectMembers(ctx context.Context, projectID ulid.ULID) ([]*authDomain.ProjectMember, error) {
+	if _, err := s.getProject(ctx, projectID); err != nil {
+		return nil, err
+	}
+
+	members, err := s.deps.ProjectMembers.GetByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, appErrors.NewInternalError("Failed to list project members", err)
+	}
+	return members, nil
+}
+
+// AssignProjectRole assigns an organization member a role in the project,
+// replacing any role they already hold there
+func (s *Service) AssignProjectRole(ctx context.Context, projectID, userID, roleID ulid.ULID, assignedBy *ulid.ULID) (*authDomain.ProjectMember, error) {
+	project, err := s.getProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := s.deps.Members.GetByUserAndOrg(ctx, userID, project.OrganizationID)
+	if err != nil {
+		if errors.Is(err, orgDomain.ErrMemberNotFound) {
+			return nil, appErrors.NewValidationError("User is not an organization member", "project roles can only be assigned to members of the project's organization")
+		}
+		return nil, appErrors.NewInternalError("Failed to get organization membership", err)
+	}
+	orgRole, err := s.deps.Roles.GetByID(ctx, member.RoleID)
+	if err != nil {
+		return nil, appErrors.NewInternalError("Failed to get organization role", err)
+	}
+	if isOwnerRole(orgRole) {
+		return nil, appErrors.NewValidationError("User is an organization owner", "organization owners keep full access to every project")
+	}
+
+	role, err := s.deps.Roles.GetByID(ctx, roleID)
+	if err != nil {
+		return nil, appErrors.NewNotFoundError("role")
+	}
+	if err := validateProjectRole(role, project); err != nil {
+		return nil, err
+	}
+
+	var before interface{}
+	if existing, err := s.deps.ProjectMembers.GetByUserAndProject(ctx, userID, projectID); err == nil {
+		before = map[string]interface{}{"role_id": existing.RoleID}
+	}
+
+	assignment := authDomain.NewProjectMember(userID, projectID, roleID)
+	assignment.AssignedBy = assignedBy
+	if err := s.deps.ProjectMembers.Upsert(ctx, assignment); err != nil {
+		return nil, appErrors.NewInternalError("Failed to assign project role", err)
+	}
+	assignment.Role = role
+
+	s.record(ctx, project, userID, assignedBy, "project_member.role_assigned", before, map[string]interface{}{"role_id": roleID})
+	s.logger.Info("project role assigned", "project_id", projectID, "user_id", userID, "role_id", roleID)
+
+	return assignment, nil
+}
+
+// RemoveProjectRole removes the user's project role; they fall back to their organization role
+func (s *Service) RemoveProjectRole(ctx context.Context, projectID, userID ulid.ULID) error {
+	project, err := s.getProject(ctx, projectID)
+	if err != nil {
+		return err
+	}
+
+	existing, err := s.deps.ProjectMembers.GetByUserAndProject(ctx, userID, projectID)
+	if err != nil {
+		if errors.Is(err, authDomain.ErrNotFound) {
+			return appErrors.NewNotFoundError("project member")
+		}
+		return appErrors.NewInternalError("Failed to get project member", err)
+	}
+
+	if err := s.deps.ProjectMembers.Delete(ctx, userID, projectID); err != nil {
+		if errors.Is(err, authDomain.ErrNotFound) {
+			return appErrors.NewNotFoundError("project member")
+		}
+		return appErrors.NewInternalError("Failed to remove project role", err)
+	}
+
+	s.record(ctx, project, userID, nil, "project_member.removed", map[string]interface{}{"role_id": existing.RoleID}, nil)
+	s.logger.Info("project role removed", "project_id", projectID, "user_id", userID)
+
+	return nil
+}
+
+func (s *Service) getProject(ctx context.Context, projectID ulid.ULID) (*orgDomain.Project, error) {
+	project, err := s.deps.Projects.GetByID(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, orgDomain.ErrProjectNotFound) {
+			return nil, appErrors.NewNotFoundError("project")
+		}
+		return nil, appErrors.NewInternalError("Failed to get project", err)
+	}
+	return project, nil
+}
+
+func (s *Service) record(ctx context.Context, project *orgDomain.Project, userID ulid.ULID, actorID *ulid.ULID, action string, before, after interface{}) {
+	if s.deps.AuditLogs == nil {
+		return
+	}
+	s.deps.AuditLogs.Record(ctx, &authDomain.AuditEvent{
+		OrganizationID: &project.OrganizationID,
+		ProjectID:      &project.ID,
+		UserID:         actorID,
+		Action:         action,
+		Resource:       auditResourceProjectMember,
+		ResourceID:     userID.String(),
+		Before:         before,
+		After:          after,
+	})
+}
+
+// validateProjectRole accepts role templates other than owner and the custom
+// roles of the project or its organization
+func validateProjectRole(role *authDomain.Role, project *orgDomain.Project) error {
+	if isOwnerRole(role) {
+		return appErrors.NewValidationError("Invalid role", "the owner role applies to the whole organization")
+	}
+	if role.ScopeID == nil {
+		if role.ScopeType == authDomain.ScopeSystem {
+			return appErrors.NewValidationError("Invalid role", "system roles cannot be assigned in a project")
+		}
+		return nil
+	}
+
+	switch {
+	case role.ScopeType == authDomain.ScopeOrganization && *role.ScopeID == project.OrganizationID:
+		return nil
+	case role.ScopeType == authDomain.ScopeProject && *role.ScopeID == project.ID:
+		return nil
+	default:
+		return appErrors.NewValidationError("Invalid role", "custom roles must belong to the project's organization")
+	}
+}
+
+func isOwnerRole(role *authDomain.Role) bool {
+	return role.Name == ownerRoleName && role.ScopeID == nil
+}
@@ -0,0 +1,249 @@
+package rbac
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"brokle/internal/config"
+	authDomain "brokle/internal/core/domain/auth"
+	orgDomain "brokle/internal/core/domain/organization"
+	appErrors "brokle/pkg/errors"
+	"brokle/pkg/ulid"
+)
+
+type fakeProjectMemberRepository struct {
+	authDomain.ProjectMemberRepository
+	assignments map[ulid.ULID]*authDomain.ProjectMember
+}
+
+func (r *fakeProjectMemberRepository) Upsert(ctx context.Context, member *authDomain.ProjectMember) error {
+	r.assignments[member.UserID] = member
+	return nil
+}
+
+func (r *fakeProjectMemberRepository) GetByUserAndProject(ctx context.Context, userID, projectID ulid.ULID) (*authDomain.ProjectMember, error) {
+	member, ok := r.assignments[userID]
+	if !ok || member.ProjectID != projectID {
+		return nil, authDomain.ErrNotFound
+	}
+	return member, nil
+}
+
+func (r *fakeProjectMemberRepository) Delete(ctx context.Context, userID, projectID ulid.ULID) error {
+	if _, err := r.GetByUserAndProject(ctx, userID, projectID); err != nil {
+		return err
+	}
+	delete(r.assignments, userID)
+	return nil
+}
+
+type fakeProjectRepository struct {
+	orgDomain.ProjectRepository
+	project *orgDomain.Project
+}
+
+func (r *fakeProjectRepository) GetByID(ctx context.Context, id ulid.ULID) (*orgDomain.Project, error) {
+	if id != r.project.ID {
+		return nil, orgDomain.ErrProjectNotFound
+	}
+	return r.project, nil
+}
+
+type fakeMemberRepository struct {
+	orgDomain.MemberRepository
+	members map[ulid.ULID]*orgDomain.Member
+}
+
+func (r *fakeMemberRepository) GetByUserAndOrg(ctx context.Context, userID, orgID ulid.ULID) (*orgDomain.Member, error) {
+	member, ok := r.members[userID]
+	if !ok || member.OrganizationID != orgID {
+		return nil, orgDomain.ErrMemberNotFound
+	}
+	return member, nil
+}
+
+type fakeRoleRepository struct {
+	authDomain.RoleRepository
+	roles map[ulid.ULID]*authDomain.Role
+}
+
+func (r *fakeRoleRepository) GetByID(ctx context.Context, id ulid.ULID) (*authDomain.Role, error) {
+	role, ok := r.roles[id]
+	if !ok {
+		return nil, authDomain.ErrNotFound
+	}
+	return role, nil
+}
+
+type fakeAuditLogService struct {
+	authDomain.AuditLogService
+	events []*authDomain.AuditEvent
+}
+
+func (s *fakeAuditLogService) Record(ctx context.Context, event *authDomain.AuditEvent) {
+	s.events = append(s.events, event)
+}
+
+type serviceFixture struct {
+	service     *Service
+	project     *orgDomain.Project
+	assignments *fakeProjectMemberRepository
+	members     *fakeMemberRepository
+	roles       *fakeRoleRepository
+	audit       *fakeAuditLogService
+}
+
+func newServiceFixture() *serviceFixture {
+	f := &serviceFixture{
+		project:     &orgDomain.Project{ID: ulid.New(), OrganizationID: ulid.New()},
+		assignments: &fakeProjectMemberRepository{assignments: make(map[ulid.ULID]*authDomain.ProjectMember)},
+		members:     &fakeMemberRepository{members: make(map[ulid.ULID]*orgDomain.Member)},
+		roles:       &fakeRoleRepository{roles: make(map[ulid.ULID]*authDomain.Role)},
+		audit:       &fakeAuditLogService{},
+	}
+	f.service = NewService(Dependencies{
+		ProjectMembers: f.assignments,
+		Projects:       &fakeProjectRepository{project: f.project},
+		Members:        f.members,
+		Roles:          f.roles,
+		AuditLogs:      f.audit,
+		Logger:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	return f
+}
+
+func (f *serviceFixture) role(name string, scopeType string, scopeID *ulid.ULID) *authDomain.Role {
+	role := &authDomain.Role{ID: ulid.New(), Name: name, ScopeType: scopeType, ScopeID: scopeID}
+	f.roles.roles[role.ID] = role
+	return role
+}
+
+func (f *serviceFixture) member(role *authDomain.Role) ulid.ULID {
+	userID := ulid.New()
+	f.members.members[userID] = &orgDomain.Member{UserID: userID, OrganizationID: f.project.OrganizationID, RoleID: role.ID, Status: authDomain.MemberStatusActive}
+	return userID
+}
+
+func assertValidationError(t *testing.T, err error) {
+	t.Helper()
+	appErr, ok := appErrors.IsAppError(err)
+	require.True(t, ok)
+	assert.Equal(t, appErrors.ValidationError, appErr.Type)
+}
+
+func TestService_AssignProjectRole(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("assigns an organization custom role and records it", func(t *testing.T) {
+		f := newServiceFixture()
+		orgID := f.project.OrganizationID
+		userID := f.member(f.role("Contractor", authDomain.ScopeOrganization, &orgID))
+		editor := f.role("Prompt Editor", authDomain.ScopeOrganization, &orgID)
+		actorID := ulid.New()
+
+		assignment, err := f.service.AssignProjectRole(ctx, f.project.ID, userID, editor.ID, &actorID)
+		require.NoError(t, err)
+		assert.Equal(t, editor, assignment.Role)
+		assert.Equal(t, &actorID, assignment.AssignedBy)
+		assert.Equal(t, editor.ID, f.assignments.assignments[userID].RoleID)
+
+		require.Len(t, f.audit.events, 1)
+		event := f.audit.events[0]
+		assert.Equal(t, "project_member.role_assigned", event.Action)
+		assert.Equal(t, userID.String(), event.ResourceID)
+		assert.Equal(t, &f.project.ID, event.ProjectID)
+		assert.Nil(t, event.Before)
+	})
+
+	t.Run("replaces an existing assignment", func(t *testing.T) {
+		f := newServiceFixture()
+		userID := f.member(f.role("developer", authDomain.ScopeOrganization, nil))
+		viewer := f.role("viewer", authDomain.ScopeOrganization, nil)
+		admin := f.role("admin", authDomain.ScopeOrganization, nil)
+
+		_, err := f.service.AssignProjectRole(ctx, f.project.ID, userID, viewer.ID, nil)
+		require.NoError(t, err)
+		_, err = f.service.AssignProjectRole(ctx, f.project.ID, userID, admin.ID, nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, admin.ID, f.assignments.assignments[userID].RoleID)
+		require.Len(t, f.audit.events, 2)
+		assert.Equal(t, map[string]interface{}{"role_id": viewer.ID}, f.audit.events[1].Before)
+	})
+
+	t.Run("rejects users outside the organization", func(t *testing.T) {
+		f := newServiceFixture()
+		viewer := f.role("viewer", authDomain.ScopeOrganization, nil)
+
+		_, err := f.service.AssignProjectRole(ctx, f.project.ID, ulid.New(), viewer.ID, nil)
+		assertValidationError(t, err)
+		assert.Empty(t, f.assignments.assignments)
+	})
+
+	t.Run("rejects organization owners", func(t *testing.T) {
+		f := newServiceFixture()
+		userID := f.member(f.role("owner", authDomain.ScopeOrganization, nil))
+		viewer := f.role("viewer", authDomain.ScopeOrganization, nil)
+
+		_, err := f.service.AssignProjectRole(ctx, f.project.ID, userID, viewer.ID, nil)
+		assertValidationError(t, err)
+	})
+
+	t.Run("rejects roles the project cannot use", func(t *testing.T) {
+		f := newServiceFixture()
+		userID := f.member(f.role("developer", authDomain.ScopeOrganization, nil))
+		otherOrgID := ulid.New()
+
+		for _, role := range []*authDomain.Role{
+			f.role("owner", authDomain.ScopeOrganization, nil),
+			f.role("superadmin", authDomain.ScopeSystem, nil),
+			f.role("Prompt Editor", authDomain.ScopeOrganization, &otherOrgID),
+		} {
+			_, err := f.service.AssignProjectRole(ctx, f.project.ID, userID, role.ID, nil)
+			assertValidationError(t, err)
+		}
+		assert.Empty(t, f.assignments.assignments)
+	})
+
+	t.Run("unknown role is not found", func(t *testing.T) {
+		f := newServiceFixture()
+		userID := f.member(f.role("developer", authDomain.ScopeOrganization, nil))
+
+		_, err := f.service.AssignProjectRole(ctx, f.project.ID, userID, ulid.New(), nil)
+		assert.True(t, appErrors.IsNotFound(err))
+	})
+}
+
+func TestService_RemoveProjectRole(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("removes the assignment", func(t *testing.T) {
+		f := newServiceFixture()
+		userID := f.member(f.role("developer", authDomain.ScopeOrganization, nil))
+		viewer := f.role("viewer", authDomain.ScopeOrganization, nil)
+		_, err := f.service.AssignProjectRole(ctx, f.project.ID, userID, viewer.ID, nil)
+		require.NoError(t, err)
+
+		require.NoError(t, f.service.RemoveProjectRole(ctx, f.project.ID, userID))
+		assert.Empty(t, f.assignments.assignments)
+		assert.Equal(t, "project_member.removed", f.audit.events[len(f.audit.events)-1].Action)
+	})
+
+	t.Run("missing assignment is not found", func(t *testing.T) {
+		f := newServiceFixture()
+
+		err := f.service.RemoveProjectRole(ctx, f.project.ID, ulid.New())
+		assert.True(t, appErrors.IsNotFound(err))
+		assert.Empty(t, f.audit.events)
+	})
+}
+
+func TestNewProvider_Unlicensed(t *testing.T) {
+	provider := NewProvider(&config.Config{}, Dependencies{})
+	assert.IsType(t, &StubRBAC{}, provider)
+}
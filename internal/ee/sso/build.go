@@ -3,7 +3,9 @@
 
 package sso
 
-// OSS build uses stub implementation
-func init() {
-	// Initialize stub SSO implementation
+import "brokle/internal/config"
+
+// OSS builds serve SSO only when the license includes it
+func licensed(cfg *config.Config) bool {
+	return cfg.CanUseFeature("sso_integration")
 }
@@ -3,13 +3,9 @@
 
 package sso
 
-// Enterprise build uses real SSO implementation
-// This file would be replaced in enterprise builds
+import "brokle/internal/config"
 
-// import "brokle/internal/ee-real/sso"
-
-// func New() SSOProvider {
-//     return sso.NewEnterpriseSSOProvider()
-// }
-
-// Note: Real implementation would support SAML, OIDC, OAuth2, etc.
+// Enterprise builds always serve SSO
+func licensed(cfg *config.Config) bool {
+	return true
+}
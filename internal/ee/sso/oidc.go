@@ -0,0 +1,257 @@
+package sso
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// OpenID Connect relying party: discovery, authorization code flow with PKCE and
+// id_token validation against the provider's published keys.
+const (
+	oidcDiscoveryPath  = "/.well-known/openid-configuration"
+	oidcCacheTTL       = time.Hour
+	oidcClockSkew      = time.Minute
+	oidcMaxDocumentLen = 1 << 20
+)
+
+var oidcSigningMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// oidcDiscovery is the subset of the provider configuration document Brokle uses
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// oidcCache holds discovery documents and key sets, which providers rotate rarely
+type oidcCache struct {
+	mu        sync.Mutex
+	discovery map[string]cachedDiscovery
+	keys      map[string]cachedKeys
+}
+
+type cachedDiscovery struct {
+	fetchedAt time.Time
+	doc       *oidcDiscovery
+}
+
+type cachedKeys struct {
+	fetchedAt time.Time
+	keys      map[string]crypto.PublicKey
+}
+
+func newOIDCCache() *oidcCache {
+	return &oidcCache{discovery: map[string]cachedDiscovery{}, keys: map[string]cachedKeys{}}
+}
+
+// discover loads the provider configuration for issuer
+func (s *Service) discover(ctx context.Context, issuer string) (*oidcDiscovery, error) {
+	issuer = strings.TrimSuffix(issuer, "/")
+	s.oidc.mu.Lock()
+	cached, ok := s.oidc.discovery[issuer]
+	s.oidc.mu.Unlock()
+	if ok && s.now().Sub(cached.fetchedAt) < oidcCacheTTL {
+		return cached.doc, nil
+	}
+
+	var doc oidcDiscovery
+	if err := s.fetchJSON(ctx, issuer+oidcDiscoveryPath, &doc); err != nil {
+		return nil, fmt.Errorf("oidc discovery: %w", err)
+	}
+	if strings.TrimSuffix(doc.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("oidc discovery: issuer %q does not match %q", doc.Issuer, issuer)
+	}
+	if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.JWKSURI == "" {
+		return nil, errors.New("oidc discovery: provider configuration is incomplete")
+	}
+
+	s.oidc.mu.Lock()
+	s.oidc.discovery[issuer] = cachedDiscovery{fetchedAt: s.now(), doc: &doc}
+	s.oidc.mu.Unlock()
+	return &doc, nil
+}
+
+// signingKeys returns the provider's keys by kid; refresh bypasses the cache after a rotation
+func (s *Service) signingKeys(ctx context.Context, jwksURI string, refresh bool) (map[string]crypto.PublicKey, error) {
+	s.oidc.mu.Lock()
+	cached, ok := s.oidc.keys[jwksURI]
+	s.oidc.mu.Unlock()
+	if ok && !refresh && s.now().Sub(cached.fetchedAt) < oidcCacheTTL {
+		return cached.keys, nil
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := s.fetchJSON(ctx, jwksURI, &set); err != nil {
+		return nil, fmt.Errorf("oidc keys: %w", err)
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			s.logger.Warn("Skipping unusable OIDC signing key", "kid", jwk.Kid, "error", err)
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	s.oidc.mu.Lock()
+	s.oidc.keys[jwksURI] = cachedKeys{fetchedAt: s.now(), keys: keys}
+	s.oidc.mu.Unlock()
+	return keys, nil
+}
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBase64URL(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBase64URL(k.E)
+		if err != nil {
+			return nil, err
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBase64URL(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBase64URL(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(key.X, key.Y) {
+			return nil, errors.New("EC point is not on the curve")
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+// verifyIDToken validates signature, issuer, audience, expiry and nonce of an id_token
+func (s *Service) verifyIDToken(ctx context.Context, doc *oidcDiscovery, clientID, rawToken, nonce string) (jwt.MapClaims, error) {
+	keyFunc := func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		keys, err := s.signingKeys(ctx, doc.JWKSURI, false)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := keys[kid]; !ok && kid != "" {
+			if keys, err = s.signingKeys(ctx, doc.JWKSURI, true); err != nil {
+				return nil, err
+			}
+		}
+		if key, ok := keys[kid]; ok {
+			return key, nil
+		}
+		if kid == "" && len(keys) == 1 {
+			for _, key := range keys {
+				return key, nil
+			}
+		}
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(rawToken, claims, keyFunc,
+		jwt.WithValidMethods(oidcSigningMethods),
+		jwt.WithIssuer(doc.Issuer),
+		jwt.WithAudience(clientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(oidcClockSkew),
+		jwt.WithTimeFunc(s.now),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id_token: %w", err)
+	}
+
+	if claimNonce, _ := claims["nonce"].(string); claimNonce == "" || claimNonce != nonce {
+		return nil, errors.New("invalid id_token: nonce mismatch")
+	}
+	// With several audiences the token must have been issued to this client
+	if audiences, _ := claims.GetAudience(); len(audiences) > 1 {
+		if azp, _ := claims["azp"].(string); azp != clientID {
+			return nil, errors.New("invalid id_token: authorized party mismatch")
+		}
+	}
+	return claims, nil
+}
+
+func (s *Service) fetchJSON(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned status %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, oidcMaxDocumentLen)).Decode(v)
+}
+
+// stringClaim returns a string claim, or "" when absent or of another type
+func stringClaim(claims jwt.MapClaims, name string) string {
+	value, _ := claims[name].(string)
+	return value
+}
+
+func decodeBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
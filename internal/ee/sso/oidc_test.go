@@ -0,0 +1,141 @@
+package sso
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testOIDCProvider serves discovery and a key set that can be rotated
+type testOIDCProvider struct {
+	server *httptest.Server
+	mu     sync.Mutex
+	keys   map[string]*rsa.PrivateKey
+}
+
+func newTestOIDCProvider(t *testing.T) *testOIDCProvider {
+	p := &testOIDCProvider{keys: map[string]*rsa.PrivateKey{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc(oidcDiscoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(oidcDiscovery{
+			Issuer:                p.server.URL,
+			AuthorizationEndpoint: p.server.URL + "/authorize",
+			TokenEndpoint:         p.server.URL + "/token",
+			JWKSURI:               p.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		var set struct {
+			Keys []jsonWebKey `json:"keys"`
+		}
+		for kid, key := range p.keys {
+			set.Keys = append(set.Keys, jsonWebKey{
+				Kty: "RSA", Kid: kid, Use: "sig",
+				N: base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				E: base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			})
+		}
+		json.NewEncoder(w).Encode(set)
+	})
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+func (p *testOIDCProvider) addKey(t *testing.T, kid string) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	p.mu.Lock()
+	p.keys[kid] = key
+	p.mu.Unlock()
+	return key
+}
+
+func (p *testOIDCProvider) sign(t *testing.T, kid string, key *rsa.PrivateKey, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	ctx := context.Background()
+	provider := newTestOIDCProvider(t)
+	key := provider.addKey(t, "key-1")
+	service := &Service{
+		logger:     slog.New(slog.NewTextHandler(io.Discard, nil)),
+		httpClient: provider.server.Client(),
+		oidc:       newOIDCCache(),
+		now:        time.Now,
+	}
+
+	doc, err := service.discover(ctx, provider.server.URL+"/")
+	require.NoError(t, err)
+
+	claims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":   provider.server.URL,
+			"aud":   "client-1",
+			"sub":   "user-123",
+			"email": "jane@acme.com",
+			"nonce": "nonce-1",
+			"iat":   time.Now().Unix(),
+			"exp":   time.Now().Add(5 * time.Minute).Unix(),
+		}
+	}
+
+	t.Run("valid token", func(t *testing.T) {
+		verified, err := service.verifyIDToken(ctx, doc, "client-1", provider.sign(t, "key-1", key, claims()), "nonce-1")
+		require.NoError(t, err)
+		assert.Equal(t, "jane@acme.com", stringClaim(verified, "email"))
+	})
+
+	t.Run("rejected tokens", func(t *testing.T) {
+		cases := map[string]func(c jwt.MapClaims){
+			"wrong audience": func(c jwt.MapClaims) { c["aud"] = "client-2" },
+			"wrong issuer":   func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" },
+			"wrong nonce":    func(c jwt.MapClaims) { c["nonce"] = "nonce-2" },
+			"expired":        func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() },
+			"no expiry":      func(c jwt.MapClaims) { delete(c, "exp") },
+			"other azp":      func(c jwt.MapClaims) { c["aud"] = []string{"client-1", "client-2"}; c["azp"] = "client-2" },
+		}
+		for name, mutate := range cases {
+			t.Run(name, func(t *testing.T) {
+				c := claims()
+				mutate(c)
+				_, err := service.verifyIDToken(ctx, doc, "client-1", provider.sign(t, "key-1", key, c), "nonce-1")
+				assert.Error(t, err)
+			})
+		}
+	})
+
+	t.Run("untrusted key", func(t *testing.T) {
+		other, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		_, err = service.verifyIDToken(ctx, doc, "client-1", provider.sign(t, "key-1", other, claims()), "nonce-1")
+		assert.Error(t, err)
+	})
+
+	t.Run("key rotation refreshes the cached key set", func(t *testing.T) {
+		rotated := provider.addKey(t, "key-2")
+		_, err := service.verifyIDToken(ctx, doc, "client-1", provider.sign(t, "key-2", rotated, claims()), "nonce-1")
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,262 @@
+package sso
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// SAML 2.0 service provider: SP-initiated login over the HTTP-Redirect binding,
+// signed responses over HTTP-POST. Encrypted and IdP-initiated assertions are not supported.
+const (
+	nsSAMLP    = "urn:oasis:names:tc:SAML:2.0:protocol"
+	nsSAML     = "urn:oasis:names:tc:SAML:2.0:assertion"
+	nsMetadata = "urn:oasis:names:tc:SAML:2.0:metadata"
+
+	samlStatusSuccess   = "urn:oasis:names:tc:SAML:2.0:status:Success"
+	samlBearer          = "urn:oasis:names:tc:SAML:2.0:cm:bearer"
+	samlBindingPOST     = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+	samlNameIDEmail     = "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress"
+	samlClockSkew       = 3 * time.Minute
+	samlMaxResponseSize = 1 << 20
+)
+
+// Attribute names IdPs commonly use when no mapping is configured
+var (
+	samlEmailAttributes     = []string{"email", "mail", "emailaddress", "http://schemas.xmlsoap.org/ws/2005/05/identity/claims/emailaddress", "urn:oid:0.9.2342.19200300.100.1.3"}
+	samlFirstNameAttributes = []string{"first_name", "firstname", "givenname", "http://schemas.xmlsoap.org/ws/2005/05/identity/claims/givenname", "urn:oid:2.5.4.42"}
+	samlLastNameAttributes  = []string{"last_name", "lastname", "surname", "sn", "http://schemas.xmlsoap.org/ws/2005/05/identity/claims/surname", "urn:oid:2.5.4.4"}
+)
+
+// samlAssertion is the data read from a verified assertion
+type samlAssertion struct {
+	Attributes   map[string]string // Name and FriendlyName -> first value
+	ID           string
+	Issuer       string
+	NameID       string
+	InResponseTo string
+}
+
+// attribute returns the first attribute present among names, matched case-insensitively
+func (a *samlAssertion) attribute(names ...string) string {
+	for _, name := range names {
+		if value := a.Attributes[strings.ToLower(name)]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// samlAuthnRequestURL returns the IdP URL carrying a deflated AuthnRequest and relay state
+func samlAuthnRequestURL(idpSSOURL, spEntityID, acsURL, requestID, relayState string, now time.Time) (string, error) {
+	request := fmt.Sprintf(
+		`<samlp:AuthnRequest xmlns:samlp="%s" xmlns:saml="%s" ID="%s" Version="2.0" IssueInstant="%s" Destination="%s" AssertionConsumerServiceURL="%s" ProtocolBinding="%s">`+
+			`<saml:Issuer>%s</saml:Issuer><samlp:NameIDPolicy Format="%s" AllowCreate="true"/></samlp:AuthnRequest>`,
+		nsSAMLP, nsSAML, escapeAttr(requestID), now.UTC().Format(time.RFC3339), escapeAttr(idpSSOURL),
+		escapeAttr(acsURL), samlBindingPOST, escapeText(spEntityID), samlNameIDEmail,
+	)
+
+	var deflated bytes.Buffer
+	writer, err := flate.NewWriter(&deflated, flate.DefaultCompression)
+	if err != nil {
+		return "", err
+	}
+	if _, err := writer.Write([]byte(request)); err != nil {
+		return "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(idpSSOURL)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set("SAMLRequest", base64.StdEncoding.EncodeToString(deflated.Bytes()))
+	query.Set("RelayState", relayState)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// samlMetadata describes Brokle as a service provider to the IdP
+func samlMetadata(spEntityID, acsURL string) []byte {
+	return []byte(fmt.Sprintf(
+		`<?xml version="1.0" encoding="UTF-8"?>`+"\n"+
+			`<md:EntityDescriptor xmlns:md="%s" entityID="%s">`+
+			`<md:SPSSODescriptor AuthnRequestsSigned="false" WantAssertionsSigned="true" protocolSupportEnumeration="%s">`+
+			`<md:NameIDFormat>%s</md:NameIDFormat>`+
+			`<md:AssertionConsumerService Binding="%s" Location="%s" index="0" isDefault="true"/>`+
+			`</md:SPSSODescriptor></md:EntityDescriptor>`,
+		nsMetadata, escapeAttr(spEntityID), nsSAMLP, samlNameIDEmail, samlBindingPOST, escapeAttr(acsURL),
+	))
+}
+
+// samlValidation is what a response must match to be accepted
+type samlValidation struct {
+	Now          time.Time
+	Certificate  *x509.Certificate
+	IdPEntityID  string // Optional; checked against the assertion issuer when set
+	SPEntityID   string
+	ACSURL       string
+	InResponseTo string
+}
+
+// validateSAMLResponse verifies a base64 SAMLResponse form value and returns its assertion.
+// Assertion data is only read from elements covered by a verified signature.
+func validateSAMLResponse(encoded string, v samlValidation) (*samlAssertion, error) {
+	raw, err := decodeBase64(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SAMLResponse encoding: %w", err)
+	}
+	if len(raw) > samlMaxResponseSize {
+		return nil, errors.New("SAMLResponse too large")
+	}
+	response, err := parseXML(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SAMLResponse: %w", err)
+	}
+	if !response.is(nsSAMLP, "Response") {
+		return nil, errors.New("not a SAML response")
+	}
+
+	status := response.child(nsSAMLP, "Status")
+	if status == nil || status.child(nsSAMLP, "StatusCode") == nil ||
+		status.child(nsSAMLP, "StatusCode").attr("Value") != samlStatusSuccess {
+		return nil, errors.New("identity provider reported an unsuccessful login")
+	}
+	if destination := response.attr("Destination"); destination != "" && destination != v.ACSURL {
+		return nil, errors.New("response destination does not match the assertion consumer service")
+	}
+
+	if len(response.childrenNamed(nsSAML, "EncryptedAssertion")) > 0 {
+		return nil, errors.New("encrypted assertions are not supported")
+	}
+	assertions := response.childrenNamed(nsSAML, "Assertion")
+	if len(assertions) != 1 {
+		return nil, errors.New("response must contain exactly one assertion")
+	}
+	assertion := assertions[0]
+
+	// Either signature covers the assertion: a signed response contains it
+	responseSigned := response.child(nsDSig, "Signature") != nil
+	assertionSigned := assertion.child(nsDSig, "Signature") != nil
+	if !responseSigned && !assertionSigned {
+		return nil, errors.New("response is not signed")
+	}
+	if responseSigned {
+		if err := verifySignature(response, v.Certificate); err != nil {
+			return nil, fmt.Errorf("response signature: %w", err)
+		}
+	}
+	if assertionSigned {
+		if err := verifySignature(assertion, v.Certificate); err != nil {
+			return nil, fmt.Errorf("assertion signature: %w", err)
+		}
+	}
+
+	result := &samlAssertion{
+		Attributes: map[string]string{},
+		ID:         assertion.attr("ID"),
+		Issuer:     assertion.child(nsSAML, "Issuer").text(),
+	}
+	if v.IdPEntityID != "" && result.Issuer != v.IdPEntityID {
+		return nil, fmt.Errorf("unexpected assertion issuer %q", result.Issuer)
+	}
+
+	subject := assertion.child(nsSAML, "Subject")
+	if subject == nil {
+		return nil, errors.New("assertion has no subject")
+	}
+	result.NameID = subject.child(nsSAML, "NameID").text()
+	if err := checkSubjectConfirmation(subject, v, result); err != nil {
+		return nil, err
+	}
+	if err := checkConditions(assertion.child(nsSAML, "Conditions"), v); err != nil {
+		return nil, err
+	}
+
+	for _, statement := range assertion.childrenNamed(nsSAML, "AttributeStatement") {
+		for _, attribute := range statement.childrenNamed(nsSAML, "Attribute") {
+			value := attribute.child(nsSAML, "AttributeValue").text()
+			for _, name := range []string{attribute.attr("Name"), attribute.attr("FriendlyName")} {
+				key := strings.ToLower(name)
+				if key != "" && result.Attributes[key] == "" {
+					result.Attributes[key] = value
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+// checkSubjectConfirmation requires a bearer confirmation for this ACS and login request
+func checkSubjectConfirmation(subject *xmlNode, v samlValidation, result *samlAssertion) error {
+	for _, confirmation := range subject.childrenNamed(nsSAML, "SubjectConfirmation") {
+		data := confirmation.child(nsSAML, "SubjectConfirmationData")
+		if confirmation.attr("Method") != samlBearer || data == nil {
+			continue
+		}
+		if data.attr("Recipient") != v.ACSURL {
+			continue
+		}
+		notOnOrAfter, err := parseSAMLTime(data.attr("NotOnOrAfter"))
+		if err != nil || !v.Now.Before(notOnOrAfter.Add(samlClockSkew)) {
+			continue
+		}
+		if data.attr("InResponseTo") != v.InResponseTo {
+			continue
+		}
+		result.InResponseTo = v.InResponseTo
+		return nil
+	}
+	return errors.New("assertion has no valid bearer subject confirmation")
+}
+
+// checkConditions enforces the validity window and requires this SP in the audience
+func checkConditions(conditions *xmlNode, v samlValidation) error {
+	if conditions == nil {
+		return errors.New("assertion has no conditions")
+	}
+	if value := conditions.attr("NotBefore"); value != "" {
+		notBefore, err := parseSAMLTime(value)
+		if err != nil || v.Now.Add(samlClockSkew).Before(notBefore) {
+			return errors.New("assertion is not yet valid")
+		}
+	}
+	if value := conditions.attr("NotOnOrAfter"); value != "" {
+		notOnOrAfter, err := parseSAMLTime(value)
+		if err != nil || !v.Now.Before(notOnOrAfter.Add(samlClockSkew)) {
+			return errors.New("assertion has expired")
+		}
+	}
+
+	restrictions := conditions.childrenNamed(nsSAML, "AudienceRestriction")
+	if len(restrictions) == 0 {
+		return errors.New("assertion has no audience restriction")
+	}
+	// Every restriction must admit this SP
+	for _, restriction := range restrictions {
+		admitted := false
+		for _, audience := range restriction.childrenNamed(nsSAML, "Audience") {
+			if audience.text() == v.SPEntityID {
+				admitted = true
+				break
+			}
+		}
+		if !admitted {
+			return errors.New("assertion is intended for a different audience")
+		}
+	}
+	return nil
+}
+
+func parseSAMLTime(value string) (time.Time, error) {
+	return time.Parse(time.RFC3339, value)
+}
@@ -0,0 +1,230 @@
+package sso
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testACSURL     = "https://api.brokle.test/api/v1/auth/sso/saml/acs"
+	testSPEntityID = "https://api.brokle.test/api/v1/auth/sso/saml/metadata/01HZXTESTORG"
+	testIdPEntity  = "https://idp.example.com/metadata"
+	testRequestID  = "_request1"
+)
+
+func TestCanonicalize(t *testing.T) {
+	root, err := parseXML([]byte(`<root xmlns="urn:a" xmlns:b="urn:b" xmlns:unused="urn:u">` +
+		`<b:child z="1" b:y="2" a="3 &amp; &lt;">text &gt; "quoted"</b:child><empty/></root>`))
+	require.NoError(t, err)
+	child := root.children[0].node
+
+	canonical, err := canonicalize(root, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, `<root xmlns="urn:a"><b:child xmlns:b="urn:b" a="3 &amp; &lt;" z="1" b:y="2">text &gt; "quoted"</b:child><empty></empty></root>`, string(canonical))
+
+	// Exclusive: the subtree does not inherit unused namespaces of its ancestors
+	canonical, err = canonicalize(child, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, `<b:child xmlns:b="urn:b" a="3 &amp; &lt;" z="1" b:y="2">text &gt; "quoted"</b:child>`, string(canonical))
+
+	canonical, err = canonicalize(child, []string{"unused"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, `<b:child xmlns:b="urn:b" xmlns:unused="urn:u" a="3 &amp; &lt;" z="1" b:y="2">text &gt; "quoted"</b:child>`, string(canonical))
+
+	_, err = parseXML([]byte(`<!DOCTYPE root [<!ENTITY x "y">]><root>&x;</root>`))
+	assert.Error(t, err)
+}
+
+func TestValidateSAMLResponse(t *testing.T) {
+	key, cert := newTestCertificate(t)
+	now := time.Now()
+	validation := samlValidation{
+		Now:          now,
+		Certificate:  cert,
+		IdPEntityID:  testIdPEntity,
+		SPEntityID:   testSPEntityID,
+		ACSURL:       testACSURL,
+		InResponseTo: testRequestID,
+	}
+	signed := signAssertion(t, testAssertion("_a1", "jane@acme.com", now, true), "_a1", key)
+
+	t.Run("valid signed assertion", func(t *testing.T) {
+		assertion, err := validateSAMLResponse(encodeResponse(signed), validation)
+		require.NoError(t, err)
+		assert.Equal(t, "jane@acme.com", assertion.NameID)
+		assert.Equal(t, testIdPEntity, assertion.Issuer)
+		assert.Equal(t, "jane@acme.com", assertion.attribute(samlEmailAttributes...))
+		assert.Equal(t, "Jane", assertion.attribute(samlFirstNameAttributes...))
+	})
+
+	t.Run("tampered assertion", func(t *testing.T) {
+		tampered := strings.ReplaceAll(signed, "jane@acme.com", "ceo@acme.com")
+		_, err := validateSAMLResponse(encodeResponse(tampered), validation)
+		assert.ErrorContains(t, err, "digest mismatch")
+	})
+
+	t.Run("signature wrapping", func(t *testing.T) {
+		// The signed assertion is moved aside and an unsigned one takes its place
+		evil := testAssertion("_a2", "ceo@acme.com", now, false)
+		wrapped := `<samlp:Extensions>` + signed + `</samlp:Extensions>` + evil
+		_, err := validateSAMLResponse(encodeResponse(wrapped), validation)
+		assert.ErrorContains(t, err, "not signed")
+
+		// A copied signature does not cover a different element
+		evil = strings.Replace(testAssertion("_a2", "ceo@acme.com", now, true), "<!--signature-->", extractSignature(signed), 1)
+		_, err = validateSAMLResponse(encodeResponse(evil), validation)
+		assert.ErrorContains(t, err, "does not reference")
+	})
+
+	t.Run("duplicate IDs", func(t *testing.T) {
+		duplicated := `<samlp:Extensions><saml:Issuer ID="_a1">` + testIdPEntity + `</saml:Issuer></samlp:Extensions>` + signed
+		_, err := validateSAMLResponse(encodeResponse(duplicated), validation)
+		assert.ErrorContains(t, err, "not unique")
+	})
+
+	t.Run("SHA-1 is rejected", func(t *testing.T) {
+		sha1Signature := strings.Replace(signed, algRSASHA256, algRSASHA1, 1)
+		_, err := validateSAMLResponse(encodeResponse(sha1Signature), validation)
+		assert.ErrorContains(t, err, "SHA-1 signatures are not accepted")
+
+		sha1Digest := strings.Replace(signed, algSHA256, algSHA1, 1)
+		_, err = validateSAMLResponse(encodeResponse(sha1Digest), validation)
+		assert.ErrorContains(t, err, "SHA-1 signatures are not accepted")
+	})
+
+	t.Run("untrusted certificate", func(t *testing.T) {
+		_, other := newTestCertificate(t)
+		v := validation
+		v.Certificate = other
+		_, err := validateSAMLResponse(encodeResponse(signed), v)
+		assert.ErrorContains(t, err, "signature verification failed")
+	})
+
+	t.Run("conditions", func(t *testing.T) {
+		cases := map[string]func(v *samlValidation){
+			"wrong audience":  func(v *samlValidation) { v.SPEntityID = "https://other.example.com" },
+			"wrong request":   func(v *samlValidation) { v.InResponseTo = "_other" },
+			"wrong recipient": func(v *samlValidation) { v.ACSURL = "https://other.example.com/acs" },
+			"expired":         func(v *samlValidation) { v.Now = now.Add(time.Hour) },
+			"not yet valid":   func(v *samlValidation) { v.Now = now.Add(-time.Hour) },
+			"unexpected IdP":  func(v *samlValidation) { v.IdPEntityID = "https://evil.example.com" },
+		}
+		for name, mutate := range cases {
+			t.Run(name, func(t *testing.T) {
+				v := validation
+				mutate(&v)
+				_, err := validateSAMLResponse(encodeResponse(signed), v)
+				assert.Error(t, err)
+			})
+		}
+	})
+}
+
+func TestSAMLAuthnRequestURL(t *testing.T) {
+	loginURL, err := samlAuthnRequestURL("https://idp.example.com/sso?tenant=acme", testSPEntityID, testACSURL, testRequestID, "relay", time.Now())
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(loginURL, "https://idp.example.com/sso?"))
+	assert.Contains(t, loginURL, "tenant=acme")
+	assert.Contains(t, loginURL, "RelayState=relay")
+	assert.Contains(t, loginURL, "SAMLRequest=")
+}
+
+func newTestCertificate(t *testing.T) (*rsa.PrivateKey, *x509.Certificate) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "idp.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := parseCertificate(base64.StdEncoding.EncodeToString(der))
+	require.NoError(t, err)
+	return key, cert
+}
+
+// testAssertion returns an assertion with a <!--signature--> placeholder after its issuer
+func testAssertion(id, email string, now time.Time, placeholder bool) string {
+	signature := ""
+	if placeholder {
+		signature = "<!--signature-->"
+	}
+	return fmt.Sprintf(`<saml:Assertion ID="%[1]s" Version="2.0" IssueInstant="%[2]s">
+    <saml:Issuer>%[3]s</saml:Issuer>%[4]s
+    <saml:Subject>
+      <saml:NameID Format="urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress">%[5]s</saml:NameID>
+      <saml:SubjectConfirmation Method="urn:oasis:names:tc:SAML:2.0:cm:bearer">
+        <saml:SubjectConfirmationData InResponseTo="%[6]s" NotOnOrAfter="%[7]s" Recipient="%[8]s"/>
+      </saml:SubjectConfirmation>
+    </saml:Subject>
+    <saml:Conditions NotBefore="%[9]s" NotOnOrAfter="%[7]s">
+      <saml:AudienceRestriction><saml:Audience>%[10]s</saml:Audience></saml:AudienceRestriction>
+    </saml:Conditions>
+    <saml:AttributeStatement>
+      <saml:Attribute Name="http://schemas.xmlsoap.org/ws/2005/05/identity/claims/emailaddress"><saml:AttributeValue xsi:type="xs:string">%[5]s</saml:AttributeValue></saml:Attribute>
+      <saml:Attribute Name="givenName"><saml:AttributeValue xsi:type="xs:string">Jane</saml:AttributeValue></saml:Attribute>
+    </saml:AttributeStatement>
+  </saml:Assertion>`,
+		id, now.UTC().Format(time.RFC3339), testIdPEntity, signature, email, testRequestID,
+		now.Add(5*time.Minute).UTC().Format(time.RFC3339), testACSURL,
+		now.Add(-time.Minute).UTC().Format(time.RFC3339Nano), testSPEntityID)
+}
+
+func encodeResponse(assertions string) string {
+	response := fmt.Sprintf(`<samlp:Response xmlns:samlp="%s" xmlns:saml="%s" xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" ID="_response1" Version="2.0" Destination="%s" InResponseTo="%s">
+  <saml:Issuer>%s</saml:Issuer>
+  <samlp:Status><samlp:StatusCode Value="%s"/></samlp:Status>
+  %s
+</samlp:Response>`, nsSAMLP, nsSAML, testACSURL, testRequestID, testIdPEntity, samlStatusSuccess, assertions)
+	return base64.StdEncoding.EncodeToString([]byte(response))
+}
+
+// signAssertion fills the placeholder with an enveloped signature over the assertion,
+// canonicalized in the namespace context of the response it will be embedded in.
+func signAssertion(t *testing.T, assertion, id string, key *rsa.PrivateKey) string {
+	raw, err := base64.StdEncoding.DecodeString(encodeResponse(assertion))
+	require.NoError(t, err)
+	response, err := parseXML(raw)
+	require.NoError(t, err)
+	element := response.child(nsSAML, "Assertion")
+	require.NotNil(t, element)
+
+	canonical, err := canonicalize(element, nil, nil)
+	require.NoError(t, err)
+	signedInfo := fmt.Sprintf(`<ds:SignedInfo><ds:CanonicalizationMethod Algorithm="%s"/><ds:SignatureMethod Algorithm="%s"/>`+
+		`<ds:Reference URI="#%s"><ds:Transforms><ds:Transform Algorithm="%s"/><ds:Transform Algorithm="%s"/></ds:Transforms>`+
+		`<ds:DigestMethod Algorithm="%s"/><ds:DigestValue>%s</ds:DigestValue></ds:Reference></ds:SignedInfo>`,
+		algExcC14N, algRSASHA256, id, algEnveloped, algExcC14N, algSHA256,
+		base64.StdEncoding.EncodeToString(digest(crypto.SHA256, canonical)))
+
+	standalone, err := parseXML([]byte(strings.Replace(signedInfo, "<ds:SignedInfo>", `<ds:SignedInfo xmlns:ds="`+nsDSig+`">`, 1)))
+	require.NoError(t, err)
+	canonicalSignedInfo, err := canonicalize(standalone, nil, nil)
+	require.NoError(t, err)
+	signatureValue, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest(crypto.SHA256, canonicalSignedInfo))
+	require.NoError(t, err)
+
+	signature := fmt.Sprintf(`<ds:Signature xmlns:ds="%s">%s<ds:SignatureValue>%s</ds:SignatureValue></ds:Signature>`,
+		nsDSig, signedInfo, base64.StdEncoding.EncodeToString(signatureValue))
+	return strings.Replace(assertion, "<!--signature-->", signature, 1)
+}
+
+func extractSignature(signed string) string {
+	start := strings.Index(signed, "<ds:Signature ")
+	end := strings.Index(signed, "</ds:Signature>") + len("</ds:Signature>")
+	return signed[start:end]
+}
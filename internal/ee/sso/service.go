@@ -0,0 +1,643 @@
+package sso
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/oauth2"
+
+	"brokle/internal/config"
+	authDomain "brokle/internal/core/domain/auth"
+	"brokle/internal/core/domain/common"
+	orgDomain "brokle/internal/core/domain/organization"
+	userDomain "brokle/internal/core/domain/user"
+	"brokle/pkg/encryption"
+	appErrors "brokle/pkg/errors"
+	"brokle/pkg/ulid"
+)
+
+// Public endpoints identity providers call, relative to the SSO base URL
+const (
+	oidcCallbackPath = "/api/v1/auth/sso/oidc/callback"
+	samlACSPath      = "/api/v1/auth/sso/saml/acs"
+	samlMetadataPath = "/api/v1/auth/sso/saml/metadata/"
+
+	loginStateKeyPrefix = "auth:sso:state:"
+	httpTimeout         = 10 * time.Second
+
+	// managePermission is required in the organization to read or change its connection
+	managePermission = "settings:security"
+)
+
+// Dependencies are the repositories and services SSO login uses
+type Dependencies struct {
+	Connections authDomain.SSOConnectionRepository
+	Users       userDomain.Repository
+	Members     orgDomain.MemberRepository
+	Roles       authDomain.RoleService
+	Permissions authDomain.OrganizationMemberService // Scopes connection management to the organization's admins
+	Transactor  common.Transactor
+	Redis       *redis.Client
+	Encryptor   *encryption.Service // Stores OIDC client secrets; OIDC cannot be configured without it
+	Logger      *slog.Logger
+}
+
+// Service is the licensed SSO provider: OIDC and SAML 2.0 connections per organization
+type Service struct {
+	deps       Dependencies
+	logger     *slog.Logger
+	baseURL    string
+	httpClient *http.Client
+	oidc       *oidcCache
+	now        func() time.Time
+	lookupTXT  func(ctx context.Context, domain string) ([]string, error)
+}
+
+// loginState ties an IdP callback to the login that started it
+type loginState struct {
+	ConnectionID ulid.ULID `json:"connection_id"`
+	Nonce        string    `json:"nonce,omitempty"`         // OIDC
+	CodeVerifier string    `json:"code_verifier,omitempty"` // OIDC PKCE
+	RequestID    string    `json:"request_id,omitempty"`    // SAML AuthnRequest ID
+}
+
+// NewProvider returns the SSO service when the license includes SSO, otherwise the stub
+func NewProvider(cfg *config.Config, deps Dependencies) SSOProvider {
+	if !licensed(cfg) {
+		return New()
+	}
+	return NewService(cfg.Auth.SSOBaseURL, deps)
+}
+
+// NewService creates the SSO service. baseURL is the public API URL used in callbacks.
+func NewService(baseURL string, deps Dependencies) *Service {
+	return &Service{
+		deps:       deps,
+		logger:     deps.Logger,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{Timeout: httpTimeout},
+		oidc:       newOIDCCache(),
+		now:        time.Now,
+		lookupTXT:  net.DefaultResolver.LookupTXT,
+	}
+}
+
+func (s *Service) GetSupportedProviders(ctx context.Context) ([]string, error) {
+	return []string{string(authDomain.SSOProtocolOIDC), string(authDomain.SSOProtocolSAML)}, nil
+}
+
+// ConfigureProvider creates or replaces the organization's connection. Changing the
+// domain or the identity provider requires verifying the domain again.
+func (s *Service) ConfigureProvider(ctx context.Context, orgID, userID ulid.ULID, req *authDomain.SSOConnectionRequest) (*authDomain.SSOConnectionResponse, error) {
+	if err := s.deps.Permissions.RequireOrganizationPermission(ctx, userID, orgID, managePermission); err != nil {
+		return nil, err
+	}
+
+	domain := strings.ToLower(strings.TrimSpace(req.Domain))
+	if !validDomain(domain) {
+		return nil, appErrors.NewValidationError("Invalid domain", "domain must be a DNS name such as example.com")
+	}
+
+	conn, err := s.deps.Connections.GetByOrganizationID(ctx, orgID)
+	if err != nil && !errors.Is(err, authDomain.ErrNotFound) {
+		return nil, appErrors.NewInternalError("Failed to get SSO connection", err)
+	}
+	if conn == nil {
+		conn = &authDomain.SSOConnection{ID: ulid.New(), OrganizationID: orgID, CreatedAt: s.now()}
+	}
+	previousIdP := identityProvider(conn)
+
+	if conn.Domain != domain {
+		claimed, err := s.deps.Connections.GetByDomain(ctx, domain)
+		if err != nil && !errors.Is(err, authDomain.ErrNotFound) {
+			return nil, appErrors.NewInternalError("Failed to check SSO domain", err)
+		}
+		if claimed != nil && claimed.OrganizationID != orgID {
+			return nil, appErrors.NewConflictError("Domain is already used by another organization's SSO connection")
+		}
+		token, err := randomHex(16)
+		if err != nil {
+			return nil, appErrors.NewInternalError("Failed to generate verification token", err)
+		}
+		conn.Domain = domain
+		conn.VerificationToken = token
+		conn.DomainVerifiedAt = nil
+	}
+
+	switch req.Protocol {
+	case authDomain.SSOProtocolOIDC:
+		if err := s.configureOIDC(conn, req); err != nil {
+			return nil, err
+		}
+	case authDomain.SSOProtocolSAML:
+		if err := configureSAML(conn, req); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, appErrors.NewValidationError("Invalid protocol", "protocol must be oidc or saml")
+	}
+
+	conn.Protocol = req.Protocol
+	if identityProvider(conn) != previousIdP {
+		// Verification vouches for the IdP that will sign logins, not just the domain
+		conn.DomainVerifiedAt = nil
+	}
+	conn.AttributeMapping = req.AttributeMapping
+	if conn.AttributeMapping == nil {
+		conn.AttributeMapping = map[string]string{}
+	}
+	conn.DefaultRole = req.DefaultRole
+	if conn.DefaultRole == "" {
+		conn.DefaultRole = authDomain.SSODefaultRole
+	}
+	conn.Enabled = req.Enabled == nil || *req.Enabled
+	conn.EnforceSSO = req.EnforceSSO
+	conn.UpdatedAt = s.now()
+
+	if err := s.deps.Connections.Save(ctx, conn); err != nil {
+		return nil, appErrors.NewInternalError("Failed to save SSO connection", err)
+	}
+	return s.connectionResponse(conn), nil
+}
+
+// identityProvider identifies who signs the connection's logins
+func identityProvider(conn *authDomain.SSOConnection) string {
+	return strings.Join([]string{
+		string(conn.Protocol),
+		conn.OIDCIssuer, conn.OIDCClientID,
+		conn.SAMLIdPEntityID, conn.SAMLIdPSSOURL, conn.SAMLIdPCertificate,
+	}, "\n")
+}
+
+func (s *Service) configureOIDC(conn *authDomain.SSOConnection, req *authDomain.SSOConnectionRequest) error {
+	issuer, err := url.Parse(strings.TrimSpace(req.OIDCIssuer))
+	if err != nil || issuer.Host == "" || (issuer.Scheme != "https" && issuer.Scheme != "http") {
+		return appErrors.NewValidationError("Invalid OIDC issuer", "oidc_issuer must be an absolute URL")
+	}
+	if strings.TrimSpace(req.OIDCClientID) == "" {
+		return appErrors.NewValidationError("OIDC client ID is required", "")
+	}
+
+	switch {
+	case req.OIDCClientSecret != "":
+		if s.deps.Encryptor == nil {
+			return appErrors.NewServiceUnavailableError("OIDC client secrets cannot be stored: encryption is not configured")
+		}
+		encrypted, err := s.deps.Encryptor.Encrypt(req.OIDCClientSecret)
+		if err != nil {
+			return appErrors.NewInternalError("Failed to encrypt client secret", err)
+		}
+		conn.OIDCClientSecretEncrypted = encrypted
+	case conn.Protocol != authDomain.SSOProtocolOIDC || conn.OIDCClientSecretEncrypted == "":
+		return appErrors.NewValidationError("OIDC client secret is required", "")
+	}
+
+	conn.OIDCIssuer = strings.TrimSuffix(issuer.String(), "/")
+	conn.OIDCClientID = strings.TrimSpace(req.OIDCClientID)
+	conn.SAMLIdPEntityID, conn.SAMLIdPSSOURL, conn.SAMLIdPCertificate = "", "", ""
+	return nil
+}
+
+func configureSAML(conn *authDomain.SSOConnection, req *authDomain.SSOConnectionRequest) error {
+	ssoURL, err := url.Parse(strings.TrimSpace(req.SAMLIdPSSOURL))
+	if err != nil || ssoURL.Host == "" || (ssoURL.Scheme != "https" && ssoURL.Scheme != "http") {
+		return appErrors.NewValidationError("Invalid SAML SSO URL", "saml_idp_sso_url must be an absolute URL")
+	}
+	if _, err := parseCertificate(req.SAMLIdPCertificate); err != nil {
+		return appErrors.NewValidationError("Invalid SAML IdP certificate", err.Error())
+	}
+
+	conn.SAMLIdPEntityID = strings.TrimSpace(req.SAMLIdPEntityID)
+	conn.SAMLIdPSSOURL = ssoURL.String()
+	conn.SAMLIdPCertificate = strings.TrimSpace(req.SAMLIdPCertificate)
+	conn.OIDCIssuer, conn.OIDCClientID, conn.OIDCClientSecretEncrypted = "", "", ""
+	return nil
+}
+
+func (s *Service) GetConnection(ctx context.Context, orgID, userID ulid.ULID) (*authDomain.SSOConnectionResponse, error) {
+	if err := s.deps.Permissions.RequireOrganizationPermission(ctx, userID, orgID, managePermission); err != nil {
+		return nil, err
+	}
+	conn, err := s.connectionForOrganization(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	return s.connectionResponse(conn), nil
+}
+
+func (s *Service) DeleteConnection(ctx context.Context, orgID, userID ulid.ULID) error {
+	if err := s.deps.Permissions.RequireOrganizationPermission(ctx, userID, orgID, managePermission); err != nil {
+		return err
+	}
+	if err := s.deps.Connections.Delete(ctx, orgID); err != nil {
+		if errors.Is(err, authDomain.ErrNotFound) {
+			return appErrors.NewNotFoundError("SSO connection")
+		}
+		return appErrors.NewInternalError("Failed to delete SSO connection", err)
+	}
+	return nil
+}
+
+// VerifyDomain checks the domain's TXT records for the connection's verification token
+func (s *Service) VerifyDomain(ctx context.Context, orgID, userID ulid.ULID) (*authDomain.SSOConnectionResponse, error) {
+	if err := s.deps.Permissions.RequireOrganizationPermission(ctx, userID, orgID, managePermission); err != nil {
+		return nil, err
+	}
+	conn, err := s.connectionForOrganization(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if conn.DomainVerifiedAt != nil {
+		return s.connectionResponse(conn), nil
+	}
+
+	records, err := s.lookupTXT(ctx, conn.Domain)
+	if err != nil {
+		s.logger.Warn("SSO domain TXT lookup failed", "domain", conn.Domain, "error", err)
+	}
+	if !slices.Contains(records, conn.DNSRecord()) {
+		return nil, appErrors.NewValidationError("Domain verification failed",
+			fmt.Sprintf("TXT record %q not found on %s", conn.DNSRecord(), conn.Domain))
+	}
+
+	now := s.now()
+	conn.DomainVerifiedAt = &now
+	conn.UpdatedAt = now
+	if err := s.deps.Connections.Save(ctx, conn); err != nil {
+		return nil, appErrors.NewInternalError("Failed to save SSO connection", err)
+	}
+	return s.connectionResponse(conn), nil
+}
+
+// Metadata returns the SAML service provider metadata for the organization's connection
+func (s *Service) Metadata(ctx context.Context, orgID ulid.ULID) ([]byte, error) {
+	conn, err := s.connectionForOrganization(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	return samlMetadata(s.spEntityID(conn), s.baseURL+samlACSPath), nil
+}
+
+// GetLoginURL starts SP-initiated login for the connection matching the email's domain
+func (s *Service) GetLoginURL(ctx context.Context, email string) (string, error) {
+	conn, err := s.deps.Connections.GetByDomain(ctx, authDomain.EmailDomain(email))
+	if err != nil && !errors.Is(err, authDomain.ErrNotFound) {
+		return "", appErrors.NewInternalError("Failed to get SSO connection", err)
+	}
+	if conn == nil || !conn.IsActive() {
+		return "", appErrors.NewNotFoundError("SSO connection for this email domain")
+	}
+
+	stateToken, err := randomHex(32)
+	if err != nil {
+		return "", appErrors.NewInternalError("Failed to generate SSO state", err)
+	}
+	state := &loginState{ConnectionID: conn.ID}
+
+	var loginURL string
+	switch conn.Protocol {
+	case authDomain.SSOProtocolOIDC:
+		doc, err := s.discover(ctx, conn.OIDCIssuer)
+		if err != nil {
+			return "", appErrors.NewInternalError("Identity provider is unavailable", err)
+		}
+		if state.Nonce, err = randomHex(16); err != nil {
+			return "", appErrors.NewInternalError("Failed to generate SSO nonce", err)
+		}
+		state.CodeVerifier = oauth2.GenerateVerifier()
+		loginURL = s.oauthConfig(conn, doc, "").AuthCodeURL(stateToken,
+			oauth2.S256ChallengeOption(state.CodeVerifier),
+			oauth2.SetAuthURLParam("nonce", state.Nonce),
+			oauth2.SetAuthURLParam("login_hint", email),
+		)
+	case authDomain.SSOProtocolSAML:
+		requestID, err := randomHex(20)
+		if err != nil {
+			return "", appErrors.NewInternalError("Failed to generate SAML request ID", err)
+		}
+		state.RequestID = "_" + requestID // IDs must not start with a digit
+		loginURL, err = samlAuthnRequestURL(conn.SAMLIdPSSOURL, s.spEntityID(conn), s.baseURL+samlACSPath, state.RequestID, stateToken, s.now())
+		if err != nil {
+			return "", appErrors.NewInternalError("Failed to build SAML request", err)
+		}
+	default:
+		return "", appErrors.NewInternalError("Unknown SSO protocol", fmt.Errorf("protocol %q", conn.Protocol))
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return "", appErrors.NewInternalError("Failed to encode SSO state", err)
+	}
+	if err := s.deps.Redis.Set(ctx, loginStateKeyPrefix+stateToken, data, authDomain.SSOLoginStateTTL).Err(); err != nil {
+		return "", appErrors.NewInternalError("Failed to store SSO state", err)
+	}
+	return loginURL, nil
+}
+
+// Authenticate completes OIDC login: exchanges the code and validates the id_token
+func (s *Service) Authenticate(ctx context.Context, stateToken, code string) (*User, error) {
+	state, conn, err := s.takeLoginState(ctx, stateToken, authDomain.SSOProtocolOIDC)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := s.discover(ctx, conn.OIDCIssuer)
+	if err != nil {
+		return nil, appErrors.NewInternalError("Identity provider is unavailable", err)
+	}
+	if s.deps.Encryptor == nil {
+		return nil, appErrors.NewServiceUnavailableError("SSO is not configured on this server")
+	}
+	secret, err := s.deps.Encryptor.Decrypt(conn.OIDCClientSecretEncrypted)
+	if err != nil {
+		return nil, appErrors.NewInternalError("Failed to decrypt client secret", err)
+	}
+
+	exchangeCtx := context.WithValue(ctx, oauth2.HTTPClient, s.httpClient)
+	token, err := s.oauthConfig(conn, doc, secret).Exchange(exchangeCtx, code, oauth2.VerifierOption(state.CodeVerifier))
+	if err != nil {
+		return nil, appErrors.NewUnauthorizedError("SSO code exchange failed")
+	}
+	rawIDToken, _ := token.Extra("id_token").(string)
+	if rawIDToken == "" {
+		return nil, appErrors.NewUnauthorizedError("Identity provider returned no id_token")
+	}
+	claims, err := s.verifyIDToken(ctx, doc, conn.OIDCClientID, rawIDToken, state.Nonce)
+	if err != nil {
+		s.logger.Warn("SSO id_token rejected", "connection_id", conn.ID, "error", err)
+		return nil, appErrors.NewUnauthorizedError("Invalid identity token")
+	}
+	if verified, ok := claims["email_verified"].(bool); ok && !verified {
+		return nil, appErrors.NewUnauthorizedError("Identity provider has not verified the email address")
+	}
+
+	attributes := map[string]string{}
+	for name, value := range claims {
+		if str, ok := value.(string); ok {
+			attributes[name] = str
+		}
+	}
+	user := &User{
+		Attributes:     attributes,
+		ID:             stringClaim(claims, "sub"),
+		Email:          stringClaim(claims, conn.Attribute(authDomain.SSOAttributeEmail, "email")),
+		Name:           stringClaim(claims, "name"),
+		FirstName:      stringClaim(claims, conn.Attribute(authDomain.SSOAttributeFirstName, "given_name")),
+		LastName:       stringClaim(claims, conn.Attribute(authDomain.SSOAttributeLastName, "family_name")),
+		Provider:       string(authDomain.SSOProtocolOIDC),
+		OrganizationID: conn.OrganizationID,
+	}
+	if err := s.checkUserDomain(conn, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// ValidateAssertion completes SAML login from the assertion consumer service POST
+func (s *Service) ValidateAssertion(ctx context.Context, samlResponse, relayState string) (*User, error) {
+	state, conn, err := s.takeLoginState(ctx, relayState, authDomain.SSOProtocolSAML)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := parseCertificate(conn.SAMLIdPCertificate)
+	if err != nil {
+		return nil, appErrors.NewInternalError("Invalid SAML IdP certificate", err)
+	}
+
+	assertion, err := validateSAMLResponse(samlResponse, samlValidation{
+		Now:          s.now(),
+		Certificate:  cert,
+		IdPEntityID:  conn.SAMLIdPEntityID,
+		SPEntityID:   s.spEntityID(conn),
+		ACSURL:       s.baseURL + samlACSPath,
+		InResponseTo: state.RequestID,
+	})
+	if err != nil {
+		s.logger.Warn("SAML response rejected", "connection_id", conn.ID, "error", err)
+		return nil, appErrors.NewUnauthorizedError("Invalid SAML response")
+	}
+
+	email := assertionAttribute(assertion, conn, authDomain.SSOAttributeEmail, samlEmailAttributes)
+	if email == "" && strings.Contains(assertion.NameID, "@") {
+		email = assertion.NameID
+	}
+	user := &User{
+		Attributes:     assertion.Attributes,
+		ID:             assertion.NameID,
+		Email:          email,
+		FirstName:      assertionAttribute(assertion, conn, authDomain.SSOAttributeFirstName, samlFirstNameAttributes),
+		LastName:       assertionAttribute(assertion, conn, authDomain.SSOAttributeLastName, samlLastNameAttributes),
+		Provider:       string(authDomain.SSOProtocolSAML),
+		OrganizationID: conn.OrganizationID,
+	}
+	user.Name = strings.TrimSpace(user.FirstName + " " + user.LastName)
+	if err := s.checkUserDomain(conn, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// ProvisionUser returns the user for an SSO login, creating them on first login, and adds
+// them to the connection's organization with its default role if they are not a member.
+func (s *Service) ProvisionUser(ctx context.Context, ssoUser *User) (ulid.ULID, error) {
+	conn, err := s.connectionForOrganization(ctx, ssoUser.OrganizationID)
+	if err != nil {
+		return ulid.ULID{}, err
+	}
+	if err := s.checkUserDomain(conn, ssoUser); err != nil {
+		return ulid.ULID{}, err
+	}
+
+	existing, err := s.deps.Users.GetByEmail(ctx, ssoUser.Email)
+	if err != nil && !errors.Is(err, userDomain.ErrNotFound) {
+		return ulid.ULID{}, appErrors.NewInternalError("User lookup failed", err)
+	}
+	if existing != nil && !existing.IsActive {
+		return ulid.ULID{}, appErrors.NewForbiddenError("Account is deactivated")
+	}
+	role, err := s.deps.Roles.GetRoleByNameAndScope(ctx, conn.DefaultRole, authDomain.ScopeOrganization)
+	if err != nil {
+		return ulid.ULID{}, appErrors.NewInternalError("Failed to resolve SSO default role", err)
+	}
+
+	var userID ulid.ULID
+	err = s.deps.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if existing != nil {
+			userID = existing.ID
+		} else {
+			newUser := newSSOUser(ssoUser, conn.OrganizationID, s.now())
+			if err := s.deps.Users.Create(ctx, newUser); err != nil {
+				if appErrors.IsDatabaseUniqueViolation(err) {
+					return appErrors.NewConflictError("Email already registered")
+				}
+				return appErrors.NewInternalError("Failed to create user", err)
+			}
+			if err := s.deps.Users.CreateProfile(ctx, userDomain.NewUserProfile(newUser.ID)); err != nil {
+				return appErrors.NewInternalError("Failed to create user profile", err)
+			}
+			userID = newUser.ID
+		}
+
+		isMember, err := s.deps.Members.IsMember(ctx, userID, conn.OrganizationID)
+		if err != nil {
+			return appErrors.NewInternalError("Failed to check organization membership", err)
+		}
+		if !isMember {
+			if err := s.deps.Members.Create(ctx, orgDomain.NewMember(conn.OrganizationID, userID, role.ID)); err != nil {
+				return appErrors.NewInternalError("Failed to add user to organization", err)
+			}
+			s.logger.Info("SSO user joined organization", "user_id", userID, "organization_id", conn.OrganizationID, "role", conn.DefaultRole)
+		}
+		return nil
+	})
+	if err != nil {
+		return ulid.ULID{}, err
+	}
+	return userID, nil
+}
+
+// newSSOUser builds an account for a first SSO login; the IdP has vouched for the email
+func newSSOUser(ssoUser *User, orgID ulid.ULID, now time.Time) *userDomain.User {
+	firstName, lastName := ssoUser.FirstName, ssoUser.LastName
+	if firstName == "" && lastName == "" {
+		firstName, lastName, _ = strings.Cut(ssoUser.Name, " ")
+	}
+	if firstName == "" {
+		firstName, _, _ = strings.Cut(ssoUser.Email, "@")
+	}
+
+	user := userDomain.NewUser(ssoUser.Email, firstName, lastName, "other")
+	user.AuthMethod = "oauth"
+	user.OAuthProvider = &ssoUser.Provider
+	user.OAuthProviderID = &ssoUser.ID
+	user.Password = ""
+	user.IsEmailVerified = true
+	user.EmailVerifiedAt = &now
+	user.DefaultOrganizationID = &orgID
+	return user
+}
+
+// takeLoginState consumes a login state and loads its active connection
+func (s *Service) takeLoginState(ctx context.Context, stateToken string, protocol authDomain.SSOProtocol) (*loginState, *authDomain.SSOConnection, error) {
+	if stateToken == "" {
+		return nil, nil, appErrors.NewUnauthorizedError("Missing SSO state")
+	}
+	data, err := s.deps.Redis.GetDel(ctx, loginStateKeyPrefix+stateToken).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil, appErrors.NewUnauthorizedError("SSO login expired or invalid")
+		}
+		return nil, nil, appErrors.NewInternalError("Failed to load SSO state", err)
+	}
+	var state loginState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, nil, appErrors.NewInternalError("Failed to decode SSO state", err)
+	}
+
+	conn, err := s.deps.Connections.GetByID(ctx, state.ConnectionID)
+	if err != nil {
+		if errors.Is(err, authDomain.ErrNotFound) {
+			return nil, nil, appErrors.NewUnauthorizedError("SSO connection no longer exists")
+		}
+		return nil, nil, appErrors.NewInternalError("Failed to get SSO connection", err)
+	}
+	if !conn.IsActive() || conn.Protocol != protocol {
+		return nil, nil, appErrors.NewUnauthorizedError("SSO connection is not active")
+	}
+	return &state, conn, nil
+}
+
+// checkUserDomain only accepts identities in the connection's verified domain, so an
+// IdP cannot sign in accounts the organization does not own.
+func (s *Service) checkUserDomain(conn *authDomain.SSOConnection, user *User) error {
+	if user.ID == "" || user.Email == "" {
+		return appErrors.NewUnauthorizedError("Identity provider did not return a subject and email")
+	}
+	if authDomain.EmailDomain(user.Email) != conn.Domain {
+		s.logger.Warn("SSO identity outside the connection's domain", "connection_id", conn.ID, "email", user.Email)
+		return appErrors.NewForbiddenError("Email domain does not match the organization's SSO domain")
+	}
+	return nil
+}
+
+func (s *Service) connectionForOrganization(ctx context.Context, orgID ulid.ULID) (*authDomain.SSOConnection, error) {
+	conn, err := s.deps.Connections.GetByOrganizationID(ctx, orgID)
+	if err != nil {
+		if errors.Is(err, authDomain.ErrNotFound) {
+			return nil, appErrors.NewNotFoundError("SSO connection")
+		}
+		return nil, appErrors.NewInternalError("Failed to get SSO connection", err)
+	}
+	return conn, nil
+}
+
+func (s *Service) connectionResponse(conn *authDomain.SSOConnection) *authDomain.SSOConnectionResponse {
+	resp := &authDomain.SSOConnectionResponse{SSOConnection: conn, DNSRecord: conn.DNSRecord()}
+	if conn.Protocol == authDomain.SSOProtocolSAML {
+		resp.CallbackURL = s.baseURL + samlACSPath
+		resp.SPEntityID = s.spEntityID(conn)
+		resp.SPMetadataURL = s.spEntityID(conn)
+	} else {
+		resp.CallbackURL = s.baseURL + oidcCallbackPath
+	}
+	return resp
+}
+
+// spEntityID identifies Brokle to the IdP; the metadata URL doubles as the entity ID
+func (s *Service) spEntityID(conn *authDomain.SSOConnection) string {
+	return s.baseURL + samlMetadataPath + conn.OrganizationID.String()
+}
+
+func (s *Service) oauthConfig(conn *authDomain.SSOConnection, doc *oidcDiscovery, secret string) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     conn.OIDCClientID,
+		ClientSecret: secret,
+		RedirectURL:  s.baseURL + oidcCallbackPath,
+		Scopes:       []string{"openid", "email", "profile"},
+		Endpoint:     oauth2.Endpoint{AuthURL: doc.AuthorizationEndpoint, TokenURL: doc.TokenEndpoint},
+	}
+}
+
+// assertionAttribute reads a mapped attribute, or the first common attribute name present
+func assertionAttribute(assertion *samlAssertion, conn *authDomain.SSOConnection, key string, defaults []string) string {
+	if name := conn.Attribute(key, ""); name != "" {
+		return assertion.attribute(name)
+	}
+	return assertion.attribute(defaults...)
+}
+
+func validDomain(domain string) bool {
+	if len(domain) > 253 || !strings.Contains(domain, ".") {
+		return false
+	}
+	for _, label := range strings.Split(domain, ".") {
+		if label == "" || len(label) > 63 || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return false
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
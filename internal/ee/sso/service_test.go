@@ -0,0 +1,115 @@
+package sso
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authDomain "brokle/internal/core/domain/auth"
+	appErrors "brokle/pkg/errors"
+	"brokle/pkg/ulid"
+)
+
+type memoryConnections struct {
+	authDomain.SSOConnectionRepository
+	byOrg map[ulid.ULID]*authDomain.SSOConnection
+}
+
+func (r *memoryConnections) GetByOrganizationID(ctx context.Context, orgID ulid.ULID) (*authDomain.SSOConnection, error) {
+	if conn, ok := r.byOrg[orgID]; ok {
+		copied := *conn
+		return &copied, nil
+	}
+	return nil, authDomain.ErrNotFound
+}
+
+func (r *memoryConnections) GetByDomain(ctx context.Context, domain string) (*authDomain.SSOConnection, error) {
+	for _, conn := range r.byOrg {
+		if conn.Domain == domain {
+			return conn, nil
+		}
+	}
+	return nil, authDomain.ErrNotFound
+}
+
+func (r *memoryConnections) Save(ctx context.Context, conn *authDomain.SSOConnection) error {
+	r.byOrg[conn.OrganizationID] = conn
+	return nil
+}
+
+// orgAdmins grants settings:security to one user in one organization
+type orgAdmins struct {
+	authDomain.OrganizationMemberService
+	orgID, userID ulid.ULID
+}
+
+func (a *orgAdmins) RequireOrganizationPermission(ctx context.Context, userID, orgID ulid.ULID, permission string) error {
+	if userID != a.userID || orgID != a.orgID || permission != managePermission {
+		return appErrors.NewForbiddenError("Insufficient permissions in this organization")
+	}
+	return nil
+}
+
+func TestService_ConfigureProvider(t *testing.T) {
+	ctx := context.Background()
+	orgID, adminID := ulid.New(), ulid.New()
+	connections := &memoryConnections{byOrg: map[ulid.ULID]*authDomain.SSOConnection{}}
+	service := NewService("https://api.brokle.test", Dependencies{
+		Connections: connections,
+		Permissions: &orgAdmins{orgID: orgID, userID: adminID},
+		Logger:      slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	samlRequest := func(t *testing.T) *authDomain.SSOConnectionRequest {
+		_, cert := newTestCertificate(t)
+		return &authDomain.SSOConnectionRequest{
+			Protocol:           authDomain.SSOProtocolSAML,
+			Domain:             "acme.com",
+			SAMLIdPEntityID:    testIdPEntity,
+			SAMLIdPSSOURL:      "https://idp.example.com/sso",
+			SAMLIdPCertificate: base64.StdEncoding.EncodeToString(cert.Raw),
+		}
+	}
+	verify := func() {
+		verified := time.Now()
+		connections.byOrg[orgID].DomainVerifiedAt = &verified
+	}
+
+	t.Run("admins of other organizations are refused", func(t *testing.T) {
+		_, err := service.ConfigureProvider(ctx, orgID, ulid.New(), samlRequest(t))
+		appErr, ok := appErrors.IsAppError(err)
+		require.True(t, ok)
+		assert.Equal(t, appErrors.ForbiddenError, appErr.Type)
+		assert.Empty(t, connections.byOrg)
+
+		err = service.DeleteConnection(ctx, orgID, ulid.New())
+		appErr, ok = appErrors.IsAppError(err)
+		require.True(t, ok)
+		assert.Equal(t, appErrors.ForbiddenError, appErr.Type)
+	})
+
+	req := samlRequest(t)
+	_, err := service.ConfigureProvider(ctx, orgID, adminID, req)
+	require.NoError(t, err)
+	verify()
+
+	t.Run("unchanged identity provider stays verified", func(t *testing.T) {
+		req.EnforceSSO = true
+		resp, err := service.ConfigureProvider(ctx, orgID, adminID, req)
+		require.NoError(t, err)
+		assert.NotNil(t, resp.DomainVerifiedAt)
+	})
+
+	t.Run("new certificate requires verifying again", func(t *testing.T) {
+		resp, err := service.ConfigureProvider(ctx, orgID, adminID, samlRequest(t))
+		require.NoError(t, err)
+		assert.Nil(t, resp.DomainVerifiedAt)
+		assert.False(t, resp.IsActive())
+	})
+}
@@ -3,51 +3,85 @@ package sso
 import (
 	"context"
 	"errors"
+
+	authDomain "brokle/internal/core/domain/auth"
+	"brokle/pkg/ulid"
 )
 
 // SSOProvider interface for enterprise SSO authentication
 type SSOProvider interface {
-	Authenticate(ctx context.Context, token string) (*User, error)
-	GetLoginURL(ctx context.Context) (string, error)
-	ValidateAssertion(ctx context.Context, assertion string) (*User, error)
+	// Per-organization connection management; userID must hold settings:security in the organization
 	GetSupportedProviders(ctx context.Context) ([]string, error)
-	ConfigureProvider(ctx context.Context, provider, config string) error
+	ConfigureProvider(ctx context.Context, orgID, userID ulid.ULID, req *authDomain.SSOConnectionRequest) (*authDomain.SSOConnectionResponse, error)
+	GetConnection(ctx context.Context, orgID, userID ulid.ULID) (*authDomain.SSOConnectionResponse, error)
+	DeleteConnection(ctx context.Context, orgID, userID ulid.ULID) error
+	VerifyDomain(ctx context.Context, orgID, userID ulid.ULID) (*authDomain.SSOConnectionResponse, error)
+	Metadata(ctx context.Context, orgID ulid.ULID) ([]byte, error)
+
+	// Login
+	GetLoginURL(ctx context.Context, email string) (string, error)
+	Authenticate(ctx context.Context, state, code string) (*User, error)                   // OIDC callback
+	ValidateAssertion(ctx context.Context, samlResponse, relayState string) (*User, error) // SAML assertion consumer service
+	ProvisionUser(ctx context.Context, user *User) (ulid.ULID, error)                      // Finds or creates the user and joins them to the organization
 }
 
 // User represents an authenticated SSO user
 type User struct {
-	Attributes map[string]string `json:"attributes"`
-	ID         string            `json:"id"`
-	Email      string            `json:"email"`
-	Name       string            `json:"name"`
-	Provider   string            `json:"provider"`
-	Roles      []string          `json:"roles"`
+	Attributes     map[string]string `json:"attributes"`
+	ID             string            `json:"id"` // Subject at the identity provider
+	Email          string            `json:"email"`
+	Name           string            `json:"name"`
+	FirstName      string            `json:"first_name"`
+	LastName       string            `json:"last_name"`
+	Provider       string            `json:"provider"`
+	Roles          []string          `json:"roles"`
+	OrganizationID ulid.ULID         `json:"organization_id"`
 }
 
 // StubSSO provides stub implementation for OSS version
 type StubSSO struct{}
 
-// New returns the SSO provider implementation (stub or real based on build tags)
+// New returns the unlicensed SSO provider; see NewProvider for the licensed one
 func New() SSOProvider {
 	return &StubSSO{}
 }
 
-func (s *StubSSO) Authenticate(ctx context.Context, token string) (*User, error) {
-	return nil, errors.New("SSO authentication requires Enterprise license")
+func (s *StubSSO) GetSupportedProviders(ctx context.Context) ([]string, error) {
+	return []string{}, errors.New("SSO providers require Enterprise license")
+}
+
+func (s *StubSSO) ConfigureProvider(ctx context.Context, orgID, userID ulid.ULID, req *authDomain.SSOConnectionRequest) (*authDomain.SSOConnectionResponse, error) {
+	return nil, errors.New("SSO configuration requires Enterprise license")
+}
+
+func (s *StubSSO) GetConnection(ctx context.Context, orgID, userID ulid.ULID) (*authDomain.SSOConnectionResponse, error) {
+	return nil, errors.New("SSO configuration requires Enterprise license")
+}
+
+func (s *StubSSO) DeleteConnection(ctx context.Context, orgID, userID ulid.ULID) error {
+	return errors.New("SSO configuration requires Enterprise license")
+}
+
+func (s *StubSSO) VerifyDomain(ctx context.Context, orgID, userID ulid.ULID) (*authDomain.SSOConnectionResponse, error) {
+	return nil, errors.New("SSO configuration requires Enterprise license")
 }
 
-func (s *StubSSO) GetLoginURL(ctx context.Context) (string, error) {
+func (s *StubSSO) Metadata(ctx context.Context, orgID ulid.ULID) ([]byte, error) {
+	return nil, errors.New("SSO metadata requires Enterprise license")
+}
+
+func (s *StubSSO) GetLoginURL(ctx context.Context, email string) (string, error) {
 	return "", errors.New("SSO login requires Enterprise license")
 }
 
-func (s *StubSSO) ValidateAssertion(ctx context.Context, assertion string) (*User, error) {
-	return nil, errors.New("SSO assertion validation requires Enterprise license")
+func (s *StubSSO) Authenticate(ctx context.Context, state, code string) (*User, error) {
+	return nil, errors.New("SSO authentication requires Enterprise license")
 }
 
-func (s *StubSSO) GetSupportedProviders(ctx context.Context) ([]string, error) {
-	return []string{}, errors.New("SSO providers require Enterprise license")
+func (s *StubSSO) ValidateAssertion(ctx context.Context, samlResponse, relayState string) (*User, error) {
+	return nil, errors.New("SSO assertion validation requires Enterprise license")
 }
 
-func (s *StubSSO) ConfigureProvider(ctx context.Context, provider, config string) error {
-	return errors.New("SSO configuration requires Enterprise license")
+func (s *StubSSO) ProvisionUser(ctx context.Context, user *User) (ulid.ULID, error) {
+	return ulid.ULID{}, errors.New("SSO login requires Enterprise license")
 }
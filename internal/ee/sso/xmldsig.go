@@ -0,0 +1,451 @@
+package sso
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// XML signature support for SAML. Only what identity providers use in practice is
+// accepted: enveloped signatures, exclusive canonicalization and RSA with SHA-2.
+// SHA-1 is refused for both the signature and the digest.
+const (
+	nsDSig = "http://www.w3.org/2000/09/xmldsig#"
+	nsXML  = "http://www.w3.org/XML/1998/namespace"
+
+	algExcC14N   = "http://www.w3.org/2001/10/xml-exc-c14n#"
+	algEnveloped = "http://www.w3.org/2000/09/xmldsig#enveloped-signature"
+	algRSASHA256 = "http://www.w3.org/2001/04/xmldsig-more#rsa-sha256"
+	algRSASHA384 = "http://www.w3.org/2001/04/xmldsig-more#rsa-sha384"
+	algRSASHA512 = "http://www.w3.org/2001/04/xmldsig-more#rsa-sha512"
+	algRSASHA1   = "http://www.w3.org/2000/09/xmldsig#rsa-sha1"
+	algSHA256    = "http://www.w3.org/2001/04/xmlenc#sha256"
+	algSHA384    = "http://www.w3.org/2001/04/xmldsig-more#sha384"
+	algSHA512    = "http://www.w3.org/2001/04/xmlenc#sha512"
+	algSHA1      = "http://www.w3.org/2000/09/xmldsig#sha1"
+
+	// minRSAKeyBits is the smallest IdP signing key accepted
+	minRSAKeyBits = 2048
+)
+
+var (
+	signatureHashes = map[string]crypto.Hash{algRSASHA256: crypto.SHA256, algRSASHA384: crypto.SHA384, algRSASHA512: crypto.SHA512}
+	digestHashes    = map[string]crypto.Hash{algSHA256: crypto.SHA256, algSHA384: crypto.SHA384, algSHA512: crypto.SHA512}
+)
+
+// xmlNode is an element that keeps its namespace prefixes as written, which
+// canonicalization needs and encoding/xml's resolved names lose.
+type xmlNode struct {
+	parent   *xmlNode
+	prefix   string
+	local    string
+	attrs    []xml.Attr // Name.Space holds the prefix
+	children []xmlContent
+}
+
+// xmlContent is either a child element or character data
+type xmlContent struct {
+	node *xmlNode
+	text string
+}
+
+// parseXML reads a document into an xmlNode tree. DTDs are rejected.
+func parseXML(data []byte) (*xmlNode, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var root, current *xmlNode
+	for {
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			node := &xmlNode{parent: current, prefix: t.Name.Space, local: t.Name.Local, attrs: t.Attr}
+			if current == nil {
+				if root != nil {
+					return nil, errors.New("multiple root elements")
+				}
+				root = node
+			} else {
+				current.children = append(current.children, xmlContent{node: node})
+			}
+			current = node
+		case xml.EndElement:
+			if current == nil || t.Name.Space != current.prefix || t.Name.Local != current.local {
+				return nil, fmt.Errorf("unexpected end element %s", t.Name.Local)
+			}
+			current = current.parent
+		case xml.CharData:
+			if current != nil {
+				current.children = append(current.children, xmlContent{text: string(t)})
+			}
+		case xml.Directive:
+			return nil, errors.New("DTDs are not allowed")
+		}
+	}
+	if root == nil || current != nil {
+		return nil, errors.New("incomplete XML document")
+	}
+	return root, nil
+}
+
+// lookupNamespace resolves a prefix ("" for the default namespace) in the scope of n
+func (n *xmlNode) lookupNamespace(prefix string) (string, bool) {
+	if prefix == "xml" {
+		return nsXML, true
+	}
+	for e := n; e != nil; e = e.parent {
+		for _, a := range e.attrs {
+			if prefix == "" && a.Name.Space == "" && a.Name.Local == "xmlns" ||
+				prefix != "" && a.Name.Space == "xmlns" && a.Name.Local == prefix {
+				return a.Value, true
+			}
+		}
+	}
+	return "", prefix == ""
+}
+
+func (n *xmlNode) is(namespace, local string) bool {
+	ns, _ := n.lookupNamespace(n.prefix)
+	return n.local == local && ns == namespace
+}
+
+// attr returns the value of an unqualified attribute
+func (n *xmlNode) attr(name string) string {
+	for _, a := range n.attrs {
+		if a.Name.Space == "" && a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func (n *xmlNode) child(namespace, local string) *xmlNode {
+	for _, c := range n.children {
+		if c.node != nil && c.node.is(namespace, local) {
+			return c.node
+		}
+	}
+	return nil
+}
+
+func (n *xmlNode) childrenNamed(namespace, local string) []*xmlNode {
+	var nodes []*xmlNode
+	for _, c := range n.children {
+		if c.node != nil && c.node.is(namespace, local) {
+			nodes = append(nodes, c.node)
+		}
+	}
+	return nodes
+}
+
+// text returns the element's own character data, trimmed
+func (n *xmlNode) text() string {
+	if n == nil {
+		return ""
+	}
+	var b strings.Builder
+	for _, c := range n.children {
+		if c.node == nil {
+			b.WriteString(c.text)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// canonicalize serializes n with Exclusive XML Canonicalization (without comments),
+// leaving out skip, the enveloped signature.
+func canonicalize(n *xmlNode, inclusivePrefixes []string, skip *xmlNode) ([]byte, error) {
+	c := &canonicalizer{inclusive: inclusivePrefixes, skip: skip}
+	if err := c.element(n, map[string]string{}); err != nil {
+		return nil, err
+	}
+	return c.buf.Bytes(), nil
+}
+
+type canonicalizer struct {
+	buf       bytes.Buffer
+	inclusive []string
+	skip      *xmlNode
+}
+
+type canonicalAttr struct {
+	namespace string
+	local     string
+	name      string
+	value     string
+}
+
+// element writes n; rendered holds the namespaces declared by output ancestors
+func (c *canonicalizer) element(n *xmlNode, rendered map[string]string) error {
+	// Only visibly utilized namespaces, plus the inclusive prefix list, are rendered
+	used := map[string]bool{n.prefix: true}
+	for _, a := range n.attrs {
+		if a.Name.Space != "" && a.Name.Space != "xmlns" {
+			used[a.Name.Space] = true
+		}
+	}
+	for _, p := range c.inclusive {
+		if p == "#default" {
+			p = ""
+		}
+		if _, ok := n.lookupNamespace(p); ok {
+			used[p] = true
+		}
+	}
+
+	scope := make(map[string]string, len(rendered)+len(used))
+	for p, uri := range rendered {
+		scope[p] = uri
+	}
+	var declared []string
+	for p := range used {
+		if p == "xml" {
+			continue
+		}
+		uri, ok := n.lookupNamespace(p)
+		if !ok {
+			return fmt.Errorf("undeclared namespace prefix %q", p)
+		}
+		previous, seen := rendered[p]
+		if seen && previous == uri || !seen && p == "" && uri == "" {
+			continue
+		}
+		declared = append(declared, p)
+		scope[p] = uri
+	}
+	sort.Strings(declared) // The default namespace ("") sorts first
+
+	attrs := make([]canonicalAttr, 0, len(n.attrs))
+	for _, a := range n.attrs {
+		if a.Name.Space == "xmlns" || a.Name.Space == "" && a.Name.Local == "xmlns" {
+			continue
+		}
+		attr := canonicalAttr{local: a.Name.Local, name: a.Name.Local, value: a.Value}
+		if a.Name.Space != "" {
+			attr.namespace, _ = n.lookupNamespace(a.Name.Space)
+			attr.name = a.Name.Space + ":" + a.Name.Local
+		}
+		attrs = append(attrs, attr)
+	}
+	sort.Slice(attrs, func(i, j int) bool {
+		if attrs[i].namespace != attrs[j].namespace {
+			return attrs[i].namespace < attrs[j].namespace
+		}
+		return attrs[i].local < attrs[j].local
+	})
+
+	name := n.local
+	if n.prefix != "" {
+		name = n.prefix + ":" + n.local
+	}
+	c.buf.WriteString("<" + name)
+	for _, p := range declared {
+		if p == "" {
+			c.buf.WriteString(` xmlns="`)
+		} else {
+			c.buf.WriteString(` xmlns:` + p + `="`)
+		}
+		c.buf.WriteString(escapeAttr(scope[p]) + `"`)
+	}
+	for _, a := range attrs {
+		c.buf.WriteString(" " + a.name + `="` + escapeAttr(a.value) + `"`)
+	}
+	c.buf.WriteString(">")
+
+	for _, child := range n.children {
+		if child.node == nil {
+			c.buf.WriteString(escapeText(child.text))
+			continue
+		}
+		if child.node == c.skip {
+			continue
+		}
+		if err := c.element(child.node, scope); err != nil {
+			return err
+		}
+	}
+	c.buf.WriteString("</" + name + ">")
+	return nil
+}
+
+var (
+	textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#xD;")
+	attrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", `"`, "&quot;", "\t", "&#x9;", "\n", "&#xA;", "\r", "&#xD;")
+)
+
+func escapeText(s string) string { return textEscaper.Replace(s) }
+func escapeAttr(s string) string { return attrEscaper.Replace(s) }
+
+// verifySignature checks the enveloped signature that is a direct child of el. The
+// signature must reference el itself, by an ID no other element in the document
+// carries, so callers can trust everything read from el.
+func verifySignature(el *xmlNode, cert *x509.Certificate) error {
+	signatures := el.childrenNamed(nsDSig, "Signature")
+	if len(signatures) == 0 {
+		return errors.New("element is not signed")
+	}
+	if len(signatures) > 1 {
+		return errors.New("element has more than one signature")
+	}
+	signature := signatures[0]
+	signedInfo := signature.child(nsDSig, "SignedInfo")
+	if signedInfo == nil {
+		return errors.New("signature has no SignedInfo")
+	}
+
+	c14nMethod := signedInfo.child(nsDSig, "CanonicalizationMethod")
+	if c14nMethod == nil || c14nMethod.attr("Algorithm") != algExcC14N {
+		return errors.New("unsupported canonicalization method")
+	}
+	signatureHash, err := hashForAlgorithm(signedInfo.child(nsDSig, "SignatureMethod"), signatureHashes, algRSASHA1)
+	if err != nil {
+		return err
+	}
+
+	references := signedInfo.childrenNamed(nsDSig, "Reference")
+	if len(references) != 1 {
+		return errors.New("signature must have exactly one reference")
+	}
+	reference := references[0]
+	id := el.attr("ID")
+	if id == "" || reference.attr("URI") != "#"+id {
+		return errors.New("signature does not reference the signed element")
+	}
+	if countIDs(documentRoot(el), id) != 1 {
+		return errors.New("signed element ID is not unique")
+	}
+
+	var inclusive []string
+	if transforms := reference.child(nsDSig, "Transforms"); transforms != nil {
+		for _, transform := range transforms.childrenNamed(nsDSig, "Transform") {
+			switch transform.attr("Algorithm") {
+			case algEnveloped:
+			case algExcC14N:
+				inclusive = inclusivePrefixes(transform)
+			default:
+				return fmt.Errorf("unsupported transform %s", transform.attr("Algorithm"))
+			}
+		}
+	}
+
+	digestHash, err := hashForAlgorithm(reference.child(nsDSig, "DigestMethod"), digestHashes, algSHA1)
+	if err != nil {
+		return err
+	}
+	canonical, err := canonicalize(el, inclusive, signature)
+	if err != nil {
+		return err
+	}
+	expected, err := decodeBase64(reference.child(nsDSig, "DigestValue").text())
+	if err != nil {
+		return fmt.Errorf("invalid digest value: %w", err)
+	}
+	if subtle.ConstantTimeCompare(digest(digestHash, canonical), expected) != 1 {
+		return errors.New("digest mismatch")
+	}
+
+	canonicalSignedInfo, err := canonicalize(signedInfo, inclusivePrefixes(c14nMethod), nil)
+	if err != nil {
+		return err
+	}
+	signatureValue, err := decodeBase64(signature.child(nsDSig, "SignatureValue").text())
+	if err != nil {
+		return fmt.Errorf("invalid signature value: %w", err)
+	}
+	publicKey, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return errors.New("certificate does not hold an RSA key")
+	}
+	if publicKey.N.BitLen() < minRSAKeyBits {
+		return fmt.Errorf("certificate key is shorter than %d bits", minRSAKeyBits)
+	}
+	if err := rsa.VerifyPKCS1v15(publicKey, signatureHash, digest(signatureHash, canonicalSignedInfo), signatureValue); err != nil {
+		return errors.New("signature verification failed")
+	}
+	return nil
+}
+
+// hashForAlgorithm maps a SignatureMethod or DigestMethod to its hash in hashes;
+// sha1Alg is the SHA-1 URI for that element type, named in the error.
+func hashForAlgorithm(method *xmlNode, hashes map[string]crypto.Hash, sha1Alg string) (crypto.Hash, error) {
+	if method != nil {
+		algorithm := method.attr("Algorithm")
+		if hash, ok := hashes[algorithm]; ok {
+			return hash, nil
+		}
+		if algorithm == sha1Alg {
+			return 0, errors.New("SHA-1 signatures are not accepted")
+		}
+	}
+	return 0, errors.New("unsupported signature or digest algorithm")
+}
+
+func documentRoot(n *xmlNode) *xmlNode {
+	for n.parent != nil {
+		n = n.parent
+	}
+	return n
+}
+
+// countIDs counts the elements under n carrying the ID, so a reference cannot be
+// satisfied by one element while another with the same ID is read
+func countIDs(n *xmlNode, id string) int {
+	count := 0
+	if n.attr("ID") == id {
+		count++
+	}
+	for _, c := range n.children {
+		if c.node != nil {
+			count += countIDs(c.node, id)
+		}
+	}
+	return count
+}
+
+func inclusivePrefixes(transform *xmlNode) []string {
+	list := transform.child(algExcC14N, "InclusiveNamespaces")
+	if list == nil {
+		return nil
+	}
+	return strings.Fields(list.attr("PrefixList"))
+}
+
+func digest(hash crypto.Hash, data []byte) []byte {
+	h := hash.New()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// decodeBase64 decodes base64 that may be wrapped across lines
+func decodeBase64(s string) ([]byte, error) {
+	return base64.StdEncoding.DecodeString(strings.Join(strings.Fields(s), ""))
+}
+
+// parseCertificate accepts a PEM certificate or its bare base64 body, as copied from IdP metadata
+func parseCertificate(data string) (*x509.Certificate, error) {
+	if block, _ := pem.Decode([]byte(data)); block != nil {
+		return x509.ParseCertificate(block.Bytes)
+	}
+	der, err := decodeBase64(data)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(der)
+}
@@ -84,33 +84,7 @@ func (r *auditLogRepository) GetByFilters(ctx context.Context, filters *authDoma
 	var auditLogs []*authDomain.AuditLog
 	var totalCount int64
 
-	query := r.db.WithContext(ctx).Model(&authDomain.AuditLog{})
-
-	// Apply filters
-	if filters.UserID != nil {
-		query = query.Where("user_id = ?", *filters.UserID)
-	}
-	if filters.OrganizationID != nil {
-		query = query.Where("organization_id = ?", *filters.OrganizationID)
-	}
-	if filters.Action != nil && *filters.Action != "" {
-		query = query.Where("action = ?", *filters.Action)
-	}
-	if filters.Resource != nil && *filters.Resource != "" {
-		query = query.Where("resource = ?", *filters.Resource)
-	}
-	if filters.ResourceID != nil && *filters.ResourceID != "" {
-		query = query.Where("resource_id = ?", *filters.ResourceID)
-	}
-	if filters.IPAddress != nil && *filters.IPAddress != "" {
-		query = query.Where("ip_address = ?", *filters.IPAddress)
-	}
-	if filters.StartDate != nil {
-		query = query.Where("created_at >= ?", *filters.StartDate)
-	}
-	if filters.EndDate != nil {
-		query = query.Where("created_at <= ?", *filters.EndDate)
-	}
+	query := applyAuditLogFilters(r.db.WithContext(ctx).Model(&authDomain.AuditLog{}), filters)
 
 	// Get total count
 	err := query.Count(&totalCount).Error
@@ -154,6 +128,48 @@ func (r *auditLogRepository) GetByFilters(ctx context.Context, filters *authDoma
 	return auditLogs, int(totalCount), err
 }
 
+// StreamByFilters walks every matching audit log oldest first in batches
+func (r *auditLogRepository) StreamByFilters(ctx context.Context, filters *authDomain.AuditLogFilters, batchSize int, fn func(batch []*authDomain.AuditLog) error) error {
+	var batch []*authDomain.AuditLog
+	// IDs are ULIDs, so primary key order is creation order
+	return applyAuditLogFilters(r.db.WithContext(ctx).Model(&authDomain.AuditLog{}), filters).
+		FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+			return fn(batch)
+		}).Error
+}
+
+// applyAuditLogFilters adds the WHERE clauses for the non-pagination filters
+func applyAuditLogFilters(query *gorm.DB, filters *authDomain.AuditLogFilters) *gorm.DB {
+	if filters.UserID != nil {
+		query = query.Where("user_id = ?", *filters.UserID)
+	}
+	if filters.OrganizationID != nil {
+		query = query.Where("organization_id = ?", *filters.OrganizationID)
+	}
+	if filters.ProjectID != nil {
+		query = query.Where("project_id = ?", *filters.ProjectID)
+	}
+	if filters.Action != nil && *filters.Action != "" {
+		query = query.Where("action = ?", *filters.Action)
+	}
+	if filters.Resource != nil && *filters.Resource != "" {
+		query = query.Where("resource = ?", *filters.Resource)
+	}
+	if filters.ResourceID != nil && *filters.ResourceID != "" {
+		query = query.Where("resource_id = ?", *filters.ResourceID)
+	}
+	if filters.IPAddress != nil && *filters.IPAddress != "" {
+		query = query.Where("ip_address = ?", *filters.IPAddress)
+	}
+	if filters.StartDate != nil {
+		query = query.Where("created_at >= ?", *filters.StartDate)
+	}
+	if filters.EndDate != nil {
+		query = query.Where("created_at <= ?", *filters.EndDate)
+	}
+	return query
+}
+
 // GetByAction retrieves audit logs by action
 func (r *auditLogRepository) GetByAction(ctx context.Context, action string, limit, offset int) ([]*authDomain.AuditLog, error) {
 	var auditLogs []*authDomain.AuditLog
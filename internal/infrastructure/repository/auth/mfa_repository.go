@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	authDomain "brokle/internal/core/domain/auth"
+	"brokle/pkg/ulid"
+)
+
+// mfaRepository implements authDomain.MFARepository using GORM
+type mfaRepository struct {
+	db *gorm.DB
+}
+
+// NewMFARepository creates a new MFA repository instance
+func NewMFARepository(db *gorm.DB) authDomain.MFARepository {
+	return &mfaRepository{
+		db: db,
+	}
+}
+
+// GetByUserID retrieves a user's MFA enrolment
+func (r *mfaRepository) GetByUserID(ctx context.Context, userID ulid.ULID) (*authDomain.UserMFA, error) {
+	var mfa authDomain.UserMFA
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&mfa).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("get mfa for user %s: %w", userID, authDomain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("database error getting mfa for user %s: %w", userID, err)
+	}
+	return &mfa, nil
+}
+
+// Save creates or replaces a user's MFA enrolment
+func (r *mfaRepository) Save(ctx context.Context, mfa *authDomain.UserMFA) error {
+	if err := r.db.WithContext(ctx).Save(mfa).Error; err != nil {
+		return fmt.Errorf("save mfa for user %s: %w", mfa.UserID, err)
+	}
+	return nil
+}
+
+// Delete removes a user's MFA enrolment
+func (r *mfaRepository) Delete(ctx context.Context, userID ulid.ULID) error {
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&authDomain.UserMFA{}).Error; err != nil {
+		return fmt.Errorf("delete mfa for user %s: %w", userID, err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	authDomain "brokle/internal/core/domain/auth"
+	"brokle/internal/infrastructure/shared"
+	"brokle/pkg/ulid"
+)
+
+// projectMemberRepository implements authDomain.ProjectMemberRepository using GORM
+type projectMemberRepository struct {
+	db *gorm.DB
+}
+
+// NewProjectMemberRepository creates a new project member repository instance
+func NewProjectMemberRepository(db *gorm.DB) authDomain.ProjectMemberRepository {
+	return &projectMemberRepository{
+		db: db,
+	}
+}
+
+// getDB returns transaction-aware DB instance
+func (r *projectMemberRepository) getDB(ctx context.Context) *gorm.DB {
+	return shared.GetDB(ctx, r.db)
+}
+
+// Upsert creates an assignment or replaces the role of an existing one
+func (r *projectMemberRepository) Upsert(ctx context.Context, member *authDomain.ProjectMember) error {
+	err := r.getDB(ctx).WithContext(ctx).
+		Omit("Role").
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "project_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"role_id", "status", "assigned_by", "updated_at"}),
+		}).
+		Create(member).Error
+	if err != nil {
+		return fmt.Errorf("upsert project member %s in project %s: %w", member.UserID, member.ProjectID, err)
+	}
+	return nil
+}
+
+// GetByUserAndProject retrieves an assignment with its role and the role's permissions
+func (r *projectMemberRepository) GetByUserAndProject(ctx context.Context, userID, projectID ulid.ULID) (*authDomain.ProjectMember, error) {
+	var member authDomain.ProjectMember
+	err := r.getDB(ctx).WithContext(ctx).
+		Where("user_id = ? AND project_id = ?", userID, projectID).
+		Preload("Role.Permissions").
+		First(&member).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("get project member: %w", authDomain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("database error getting project member: %w", err)
+	}
+	return &member, nil
+}
+
+// GetByProjectID lists a project's assignments with their roles
+func (r *projectMemberRepository) GetByProjectID(ctx context.Context, projectID ulid.ULID) ([]*authDomain.ProjectMember, error) {
+	var members []*authDomain.ProjectMember
+	err := r.getDB(ctx).WithContext(ctx).
+		Where("project_id = ?", projectID).
+		Preload("Role").
+		Order("joined_at ASC").
+		Find(&members).Error
+	return members, err
+}
+
+// Delete removes an assignment
+func (r *projectMemberRepository) Delete(ctx context.Context, userID, projectID ulid.ULID) error {
+	result := r.getDB(ctx).WithContext(ctx).
+		Where("user_id = ? AND project_id = ?", userID, projectID).
+		Delete(&authDomain.ProjectMember{})
+	if result.Error != nil {
+		return fmt.Errorf("delete project member: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("delete project member %s in project %s: %w", userID, projectID, authDomain.ErrNotFound)
+	}
+	return nil
+}
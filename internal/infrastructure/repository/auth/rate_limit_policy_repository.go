@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	authDomain "brokle/internal/core/domain/auth"
+	"brokle/pkg/ulid"
+)
+
+// rateLimitPolicyRepository implements authDomain.RateLimitPolicyRepository using GORM
+type rateLimitPolicyRepository struct {
+	db *gorm.DB
+}
+
+// NewRateLimitPolicyRepository creates a new rate limit policy repository instance
+func NewRateLimitPolicyRepository(db *gorm.DB) authDomain.RateLimitPolicyRepository {
+	return &rateLimitPolicyRepository{
+		db: db,
+	}
+}
+
+// Get retrieves the project policy (apiKeyID nil) or an API key's policy
+func (r *rateLimitPolicyRepository) Get(ctx context.Context, projectID ulid.ULID, apiKeyID *ulid.ULID) (*authDomain.RateLimitPolicy, error) {
+	var policy authDomain.RateLimitPolicy
+	err := r.scoped(ctx, projectID, apiKeyID).First(&policy).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("get rate limit policy for project %s: %w", projectID, authDomain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("database error getting rate limit policy for project %s: %w", projectID, err)
+	}
+	return &policy, nil
+}
+
+// Save writes all policy columns, so nil limits clear a previous override
+func (r *rateLimitPolicyRepository) Save(ctx context.Context, policy *authDomain.RateLimitPolicy) error {
+	if err := r.db.WithContext(ctx).Save(policy).Error; err != nil {
+		return fmt.Errorf("save rate limit policy: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the project policy (apiKeyID nil) or an API key's policy
+func (r *rateLimitPolicyRepository) Delete(ctx context.Context, projectID ulid.ULID, apiKeyID *ulid.ULID) error {
+	result := r.scoped(ctx, projectID, apiKeyID).Delete(&authDomain.RateLimitPolicy{})
+	if result.Error != nil {
+		return fmt.Errorf("delete rate limit policy: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("delete rate limit policy for project %s: %w", projectID, authDomain.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *rateLimitPolicyRepository) scoped(ctx context.Context, projectID ulid.ULID, apiKeyID *ulid.ULID) *gorm.DB {
+	query := r.db.WithContext(ctx).Where("project_id = ?", projectID)
+	if apiKeyID == nil {
+		return query.Where("api_key_id IS NULL")
+	}
+	return query.Where("api_key_id = ?", *apiKeyID)
+}
@@ -0,0 +1,115 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	authDomain "brokle/internal/core/domain/auth"
+	"brokle/internal/infrastructure/database"
+)
+
+// slidingWindowScript checks and consumes sliding window counters atomically.
+// Each bucket uses two fixed-window counters (KEYS: current, previous); the previous
+// window is weighted by how much of it still overlaps the sliding window.
+// ARGV[1] is "1" to consume; then per bucket: limit, cost, previous weight, TTL in ms.
+// Returns {allowed, current_1, previous_1, current_2, previous_2, ...}.
+var slidingWindowScript = redis.NewScript(`
+local consume = ARGV[1] == '1'
+local n = #KEYS / 2
+local result = {1}
+for i = 1, n do
+	local current = tonumber(redis.call('GET', KEYS[2*i-1]) or '0')
+	local previous = tonumber(redis.call('GET', KEYS[2*i]) or '0')
+	local limit = tonumber(ARGV[4*i-2])
+	local cost = tonumber(ARGV[4*i-1])
+	local weight = tonumber(ARGV[4*i])
+	result[2*i] = current
+	result[2*i+1] = previous
+	if limit > 0 and cost > 0 and previous * weight + current + cost > limit then
+		result[1] = 0
+	end
+end
+if consume and result[1] == 1 then
+	for i = 1, n do
+		local cost = tonumber(ARGV[4*i-1])
+		if cost > 0 then
+			redis.call('INCRBY', KEYS[2*i-1], cost)
+			redis.call('PEXPIRE', KEYS[2*i-1], ARGV[4*i+1])
+		end
+	end
+end
+return result
+`)
+
+// rateLimiterRepository implements authDomain.RateLimiter with Redis sliding window
+// counters, so limits are shared by every server replica
+type rateLimiterRepository struct {
+	redis *database.RedisDB
+}
+
+// NewRateLimiterRepository creates a new Redis-based rate limiter
+func NewRateLimiterRepository(redis *database.RedisDB) authDomain.RateLimiter {
+	return &rateLimiterRepository{redis: redis}
+}
+
+func (r *rateLimiterRepository) Allow(ctx context.Context, now time.Time, buckets []authDomain.RateLimitBucket) (bool, []authDomain.RateLimitBucketState, error) {
+	return r.run(ctx, now, buckets, true)
+}
+
+func (r *rateLimiterRepository) Usage(ctx context.Context, now time.Time, buckets []authDomain.RateLimitBucket) ([]authDomain.RateLimitBucketState, error) {
+	_, states, err := r.run(ctx, now, buckets, false)
+	return states, err
+}
+
+func (r *rateLimiterRepository) run(ctx context.Context, now time.Time, buckets []authDomain.RateLimitBucket, consume bool) (bool, []authDomain.RateLimitBucketState, error) {
+	if len(buckets) == 0 {
+		return true, nil, nil
+	}
+
+	keys := make([]string, 0, 2*len(buckets))
+	args := make([]interface{}, 0, 1+4*len(buckets))
+	if consume {
+		args = append(args, "1")
+	} else {
+		args = append(args, "0")
+	}
+
+	for _, bucket := range buckets {
+		window := bucket.Window.Milliseconds()
+		index := now.UnixMilli() / window
+		elapsed := float64(now.UnixMilli()-index*window) / float64(window)
+
+		keys = append(keys, windowKey(bucket.Key, index), windowKey(bucket.Key, index-1))
+		args = append(args,
+			bucket.Limit,
+			bucket.Cost,
+			strconv.FormatFloat(1-elapsed, 'f', 6, 64),
+			2*window, // The counter is read as the previous window during the next one
+		)
+	}
+
+	values, err := slidingWindowScript.Run(ctx, r.redis.Client, keys, args...).Int64Slice()
+	if err != nil {
+		return false, nil, fmt.Errorf("run rate limit script: %w", err)
+	}
+	if len(values) != 1+2*len(buckets) {
+		return false, nil, fmt.Errorf("unexpected rate limit script result length %d", len(values))
+	}
+
+	states := make([]authDomain.RateLimitBucketState, len(buckets))
+	for i := range buckets {
+		states[i] = authDomain.RateLimitBucketState{
+			Current:  values[1+2*i],
+			Previous: values[2+2*i],
+		}
+	}
+	return values[0] == 1, states, nil
+}
+
+func windowKey(key string, index int64) string {
+	return fmt.Sprintf("rate_limit:%s:%d", key, index)
+}
@@ -144,6 +144,18 @@ func (r *roleRepository) UpdateRolePermissions(ctx context.Context, roleID ulid.
 
 // Statistics
 
+// CountAssignments counts the organization members and project assignments holding a role
+func (r *roleRepository) CountAssignments(ctx context.Context, roleID ulid.ULID) (int64, error) {
+	var count int64
+	err := r.getDB(ctx).WithContext(ctx).Raw(`
+		SELECT
+			(SELECT COUNT(*) FROM organization_members WHERE role_id = ? AND deleted_at IS NULL) +
+			(SELECT COUNT(*) FROM project_members WHERE role_id = ?)
+	`, roleID, roleID).Scan(&count).Error
+
+	return count, err
+}
+
 func (r *roleRepository) GetRoleStatistics(ctx context.Context) (*authDomain.RoleStatistics, error) {
 	var stats authDomain.RoleStatistics
 
@@ -0,0 +1,289 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	authDomain "brokle/internal/core/domain/auth"
+	"brokle/internal/infrastructure/shared"
+	"brokle/pkg/ulid"
+)
+
+// scimRepository implements authDomain.SCIMRepository using GORM
+type scimRepository struct {
+	db *gorm.DB
+}
+
+// NewSCIMRepository creates a new SCIM repository instance
+func NewSCIMRepository(db *gorm.DB) authDomain.SCIMRepository {
+	return &scimRepository{
+		db: db,
+	}
+}
+
+// getDB returns transaction-aware DB instance
+func (r *scimRepository) getDB(ctx context.Context) *gorm.DB {
+	return shared.GetDB(ctx, r.db).WithContext(ctx)
+}
+
+// CreateToken stores a new token
+func (r *scimRepository) CreateToken(ctx context.Context, token *authDomain.SCIMToken) error {
+	if err := r.getDB(ctx).Create(token).Error; err != nil {
+		return fmt.Errorf("create scim token: %w", err)
+	}
+	return nil
+}
+
+// GetTokenByHash retrieves a token by the SHA-256 hash of its value
+func (r *scimRepository) GetTokenByHash(ctx context.Context, tokenHash string) (*authDomain.SCIMToken, error) {
+	var token authDomain.SCIMToken
+	if err := r.getDB(ctx).Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("get scim token: %w", authDomain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("get scim token: %w", err)
+	}
+	return &token, nil
+}
+
+// ListTokens lists an organization's tokens, newest first
+func (r *scimRepository) ListTokens(ctx context.Context, orgID ulid.ULID) ([]*authDomain.SCIMToken, error) {
+	var tokens []*authDomain.SCIMToken
+	if err := r.getDB(ctx).Where("organization_id = ?", orgID).Order("created_at DESC").Find(&tokens).Error; err != nil {
+		return nil, fmt.Errorf("list scim tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// DeleteToken revokes a token by deleting it
+func (r *scimRepository) DeleteToken(ctx context.Context, orgID, tokenID ulid.ULID) error {
+	result := r.getDB(ctx).Where("organization_id = ? AND id = ?", orgID, tokenID).Delete(&authDomain.SCIMToken{})
+	if result.Error != nil {
+		return fmt.Errorf("delete scim token: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("delete scim token %s: %w", tokenID, authDomain.ErrNotFound)
+	}
+	return nil
+}
+
+// UpdateTokenLastUsed records when a token was last used
+func (r *scimRepository) UpdateTokenLastUsed(ctx context.Context, tokenID ulid.ULID, at time.Time) error {
+	err := r.getDB(ctx).Model(&authDomain.SCIMToken{}).Where("id = ?", tokenID).UpdateColumn("last_used_at", at).Error
+	if err != nil {
+		return fmt.Errorf("update scim token last used: %w", err)
+	}
+	return nil
+}
+
+// GetUserLink retrieves a provisioned user of an organization
+func (r *scimRepository) GetUserLink(ctx context.Context, orgID, userID ulid.ULID) (*authDomain.SCIMUserLink, error) {
+	var link authDomain.SCIMUserLink
+	if err := r.getDB(ctx).Where("organization_id = ? AND user_id = ?", orgID, userID).First(&link).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("get scim user %s: %w", userID, authDomain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("get scim user %s: %w", userID, err)
+	}
+	return &link, nil
+}
+
+// SaveUserLink creates or updates a provisioned user
+func (r *scimRepository) SaveUserLink(ctx context.Context, link *authDomain.SCIMUserLink) error {
+	if err := r.getDB(ctx).Save(link).Error; err != nil {
+		return fmt.Errorf("save scim user %s: %w", link.UserID, err)
+	}
+	return nil
+}
+
+// DeleteUserLink removes a provisioned user from an organization
+func (r *scimRepository) DeleteUserLink(ctx context.Context, orgID, userID ulid.ULID) error {
+	err := r.getDB(ctx).Where("organization_id = ? AND user_id = ?", orgID, userID).Delete(&authDomain.SCIMUserLink{}).Error
+	if err != nil {
+		return fmt.Errorf("delete scim user %s: %w", userID, err)
+	}
+	return nil
+}
+
+// ListUserLinks lists an organization's provisioned users in provisioning order
+func (r *scimRepository) ListUserLinks(ctx context.Context, orgID ulid.ULID, filter authDomain.SCIMListFilter) ([]*authDomain.SCIMUserLink, int64, error) {
+	query := r.getDB(ctx).Model(&authDomain.SCIMUserLink{}).Where("scim_users.organization_id = ?", orgID)
+	if filter.Email != "" {
+		query = query.Joins("JOIN users ON users.id = scim_users.user_id").
+			Where("LOWER(users.email) = ?", strings.ToLower(filter.Email))
+	}
+	if filter.ExternalID != "" {
+		query = query.Where("scim_users.external_id = ?", filter.ExternalID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count scim users: %w", err)
+	}
+
+	var links []*authDomain.SCIMUserLink
+	query = query.Select("scim_users.*").
+		Order("scim_users.created_at ASC, scim_users.user_id ASC").
+		Offset(filter.Offset)
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if err := query.Find(&links).Error; err != nil {
+		return nil, 0, fmt.Errorf("list scim users: %w", err)
+	}
+	return links, total, nil
+}
+
+// CreateGroup stores a new group
+func (r *scimRepository) CreateGroup(ctx context.Context, group *authDomain.SCIMGroup) error {
+	if err := r.getDB(ctx).Create(group).Error; err != nil {
+		return fmt.Errorf("create scim group: %w", err)
+	}
+	return nil
+}
+
+// GetGroup retrieves an organization's group
+func (r *scimRepository) GetGroup(ctx context.Context, orgID, groupID ulid.ULID) (*authDomain.SCIMGroup, error) {
+	var group authDomain.SCIMGroup
+	if err := r.getDB(ctx).Where("organization_id = ? AND id = ?", orgID, groupID).First(&group).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("get scim group %s: %w", groupID, authDomain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("get scim group %s: %w", groupID, err)
+	}
+	return &group, nil
+}
+
+// UpdateGroup saves a group's attributes and role mapping
+func (r *scimRepository) UpdateGroup(ctx context.Context, group *authDomain.SCIMGroup) error {
+	if err := r.getDB(ctx).Save(group).Error; err != nil {
+		return fmt.Errorf("update scim group %s: %w", group.ID, err)
+	}
+	return nil
+}
+
+// DeleteGroup removes a group and its memberships
+func (r *scimRepository) DeleteGroup(ctx context.Context, orgID, groupID ulid.ULID) error {
+	db := r.getDB(ctx)
+	result := db.Where("organization_id = ? AND id = ?", orgID, groupID).Delete(&authDomain.SCIMGroup{})
+	if result.Error != nil {
+		return fmt.Errorf("delete scim group: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("delete scim group %s: %w", groupID, authDomain.ErrNotFound)
+	}
+	if err := db.Where("group_id = ?", groupID).Delete(&authDomain.SCIMGroupMember{}).Error; err != nil {
+		return fmt.Errorf("delete scim group members: %w", err)
+	}
+	return nil
+}
+
+// ListGroups lists an organization's groups by name
+func (r *scimRepository) ListGroups(ctx context.Context, orgID ulid.ULID, filter authDomain.SCIMListFilter) ([]*authDomain.SCIMGroup, int64, error) {
+	query := r.getDB(ctx).Model(&authDomain.SCIMGroup{}).Where("organization_id = ?", orgID)
+	if filter.DisplayName != "" {
+		query = query.Where("LOWER(display_name) = ?", strings.ToLower(filter.DisplayName))
+	}
+	if filter.ExternalID != "" {
+		query = query.Where("external_id = ?", filter.ExternalID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count scim groups: %w", err)
+	}
+
+	var groups []*authDomain.SCIMGroup
+	query = query.Order("display_name ASC, id ASC").Offset(filter.Offset)
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if err := query.Find(&groups).Error; err != nil {
+		return nil, 0, fmt.Errorf("list scim groups: %w", err)
+	}
+	return groups, total, nil
+}
+
+// HasRoleMappings reports whether any of the organization's groups is mapped to a role
+func (r *scimRepository) HasRoleMappings(ctx context.Context, orgID ulid.ULID) (bool, error) {
+	var count int64
+	err := r.getDB(ctx).Model(&authDomain.SCIMGroup{}).
+		Where("organization_id = ? AND role_id IS NOT NULL", orgID).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("count scim role mappings: %w", err)
+	}
+	return count > 0, nil
+}
+
+// ListGroupMembers lists the user IDs in a group
+func (r *scimRepository) ListGroupMembers(ctx context.Context, groupID ulid.ULID) ([]ulid.ULID, error) {
+	var userIDs []ulid.ULID
+	err := r.getDB(ctx).Model(&authDomain.SCIMGroupMember{}).
+		Where("group_id = ?", groupID).
+		Order("created_at ASC, user_id ASC").
+		Pluck("user_id", &userIDs).Error
+	if err != nil {
+		return nil, fmt.Errorf("list scim group members: %w", err)
+	}
+	return userIDs, nil
+}
+
+// AddGroupMembers adds users to a group; existing members are left as they are
+func (r *scimRepository) AddGroupMembers(ctx context.Context, groupID ulid.ULID, userIDs []ulid.ULID) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	members := make([]*authDomain.SCIMGroupMember, len(userIDs))
+	for i, userID := range userIDs {
+		members[i] = &authDomain.SCIMGroupMember{GroupID: groupID, UserID: userID}
+	}
+	if err := r.getDB(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&members).Error; err != nil {
+		return fmt.Errorf("add scim group members: %w", err)
+	}
+	return nil
+}
+
+// RemoveGroupMembers removes users from a group
+func (r *scimRepository) RemoveGroupMembers(ctx context.Context, groupID ulid.ULID, userIDs []ulid.ULID) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	err := r.getDB(ctx).Where("group_id = ? AND user_id IN ?", groupID, userIDs).Delete(&authDomain.SCIMGroupMember{}).Error
+	if err != nil {
+		return fmt.Errorf("remove scim group members: %w", err)
+	}
+	return nil
+}
+
+// ListUserGroups lists the organization's groups a user belongs to
+func (r *scimRepository) ListUserGroups(ctx context.Context, orgID, userID ulid.ULID) ([]*authDomain.SCIMGroup, error) {
+	var groups []*authDomain.SCIMGroup
+	err := r.getDB(ctx).
+		Joins("JOIN scim_group_members ON scim_group_members.group_id = scim_groups.id").
+		Where("scim_groups.organization_id = ? AND scim_group_members.user_id = ?", orgID, userID).
+		Order("scim_groups.display_name ASC").
+		Find(&groups).Error
+	if err != nil {
+		return nil, fmt.Errorf("list scim groups of user %s: %w", userID, err)
+	}
+	return groups, nil
+}
+
+// RemoveUserFromGroups removes a user from all of the organization's groups
+func (r *scimRepository) RemoveUserFromGroups(ctx context.Context, orgID, userID ulid.ULID) error {
+	err := r.getDB(ctx).
+		Where("user_id = ? AND group_id IN (?)", userID,
+			r.getDB(ctx).Model(&authDomain.SCIMGroup{}).Select("id").Where("organization_id = ?", orgID)).
+		Delete(&authDomain.SCIMGroupMember{}).Error
+	if err != nil {
+		return fmt.Errorf("remove scim user %s from groups: %w", userID, err)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+
+	authDomain "brokle/internal/core/domain/auth"
+	"brokle/pkg/ulid"
+)
+
+// ssoConnectionRepository implements authDomain.SSOConnectionRepository using GORM
+type ssoConnectionRepository struct {
+	db *gorm.DB
+}
+
+// NewSSOConnectionRepository creates a new SSO connection repository instance
+func NewSSOConnectionRepository(db *gorm.DB) authDomain.SSOConnectionRepository {
+	return &ssoConnectionRepository{
+		db: db,
+	}
+}
+
+// GetByID retrieves a connection by ID
+func (r *ssoConnectionRepository) GetByID(ctx context.Context, id ulid.ULID) (*authDomain.SSOConnection, error) {
+	return r.first(ctx, "id = ?", id)
+}
+
+// GetByOrganizationID retrieves an organization's connection
+func (r *ssoConnectionRepository) GetByOrganizationID(ctx context.Context, orgID ulid.ULID) (*authDomain.SSOConnection, error) {
+	return r.first(ctx, "organization_id = ?", orgID)
+}
+
+// GetByDomain retrieves the connection claiming an email domain
+func (r *ssoConnectionRepository) GetByDomain(ctx context.Context, domain string) (*authDomain.SSOConnection, error) {
+	return r.first(ctx, "domain = ?", strings.ToLower(domain))
+}
+
+// Save creates or replaces a connection
+func (r *ssoConnectionRepository) Save(ctx context.Context, connection *authDomain.SSOConnection) error {
+	if err := r.db.WithContext(ctx).Save(connection).Error; err != nil {
+		return fmt.Errorf("save sso connection for organization %s: %w", connection.OrganizationID, err)
+	}
+	return nil
+}
+
+// Delete removes an organization's connection
+func (r *ssoConnectionRepository) Delete(ctx context.Context, orgID ulid.ULID) error {
+	result := r.db.WithContext(ctx).Where("organization_id = ?", orgID).Delete(&authDomain.SSOConnection{})
+	if result.Error != nil {
+		return fmt.Errorf("delete sso connection: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("delete sso connection for organization %s: %w", orgID, authDomain.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *ssoConnectionRepository) first(ctx context.Context, query string, arg interface{}) (*authDomain.SSOConnection, error) {
+	var connection authDomain.SSOConnection
+	err := r.db.WithContext(ctx).Where(query, arg).First(&connection).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("get sso connection: %w", authDomain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("database error getting sso connection: %w", err)
+	}
+	return &connection, nil
+}
@@ -0,0 +1,76 @@
+package billing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"brokle/internal/core/domain/billing"
+	"brokle/internal/infrastructure/database"
+	"brokle/pkg/ulid"
+)
+
+// enforcementStateRepository stores ingestion enforcement per organization in a Redis hash.
+// Fields are the organization scope and project IDs; the TTL makes stale state fail open
+// if the usage worker stops refreshing it.
+type enforcementStateRepository struct {
+	redis *database.RedisDB
+}
+
+// NewEnforcementStateRepository creates a new Redis-based enforcement state repository
+func NewEnforcementStateRepository(redis *database.RedisDB) billing.EnforcementStateRepository {
+	return &enforcementStateRepository{redis: redis}
+}
+
+func (r *enforcementStateRepository) Replace(ctx context.Context, orgID ulid.ULID, states map[string]*billing.IngestionEnforcement, ttl time.Duration) error {
+	key := enforcementKey(orgID)
+
+	fields := make(map[string]interface{}, len(states))
+	for scope, state := range states {
+		data, err := json.Marshal(state)
+		if err != nil {
+			return fmt.Errorf("marshal enforcement state: %w", err)
+		}
+		fields[scope] = data
+	}
+
+	pipe := r.redis.Client.TxPipeline()
+	pipe.Del(ctx, key)
+	if len(fields) > 0 {
+		pipe.HSet(ctx, key, fields)
+		pipe.Expire(ctx, key, ttl)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("replace enforcement state: %w", err)
+	}
+	return nil
+}
+
+func (r *enforcementStateRepository) Get(ctx context.Context, orgID, projectID ulid.ULID) (*billing.IngestionEnforcement, *billing.IngestionEnforcement, error) {
+	values, err := r.redis.Client.HMGet(ctx, enforcementKey(orgID), billing.OrganizationEnforcementScope, projectID.String()).Result()
+	if err != nil && err != redis.Nil {
+		return nil, nil, fmt.Errorf("get enforcement state: %w", err)
+	}
+
+	states := make([]*billing.IngestionEnforcement, 2)
+	for i, value := range values {
+		raw, ok := value.(string)
+		if !ok || raw == "" {
+			continue
+		}
+		var state billing.IngestionEnforcement
+		if err := json.Unmarshal([]byte(raw), &state); err != nil {
+			return nil, nil, fmt.Errorf("unmarshal enforcement state: %w", err)
+		}
+		states[i] = &state
+	}
+
+	return states[0], states[1], nil
+}
+
+func enforcementKey(orgID ulid.ULID) string {
+	return fmt.Sprintf("budget_enforcement:%s", orgID.String())
+}
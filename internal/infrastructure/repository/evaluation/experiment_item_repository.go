@@ -71,3 +71,36 @@ func (r *ExperimentItemRepository) CountByExperiment(ctx context.Context, experi
 	}
 	return count, nil
 }
+
+func (r *ExperimentItemRepository) ListDatasetItemIDs(ctx context.Context, experimentID ulid.ULID) ([]ulid.ULID, error) {
+	var ids []ulid.ULID
+	err := r.getDB(ctx).WithContext(ctx).
+		Model(&evaluation.ExperimentItem{}).
+		Where("experiment_id = ? AND dataset_item_id IS NOT NULL", experimentID.String()).
+		Pluck("dataset_item_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *ExperimentItemRepository) ListDatasetItemLinks(ctx context.Context, experimentIDs []ulid.ULID) ([]*evaluation.ExperimentItem, error) {
+	if len(experimentIDs) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(experimentIDs))
+	for i, id := range experimentIDs {
+		ids[i] = id.String()
+	}
+
+	var items []*evaluation.ExperimentItem
+	err := r.getDB(ctx).WithContext(ctx).
+		Select("id", "experiment_id", "dataset_item_id").
+		Where("experiment_id IN ? AND dataset_item_id IS NOT NULL", ids).
+		Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -134,6 +134,39 @@ func (r *ExperimentRepository) SetTotalItems(ctx context.Context, id, projectID
 	return nil
 }
 
+// UpdateStatus writes the status and run timestamps without touching the counters.
+func (r *ExperimentRepository) UpdateStatus(ctx context.Context, experiment *evaluation.Experiment, projectID ulid.ULID) error {
+	result := r.getDB(ctx).WithContext(ctx).
+		Model(experiment).
+		Where("id = ? AND project_id = ?", experiment.ID.String(), projectID.String()).
+		Select("status", "started_at", "completed_at", "updated_at").
+		Updates(experiment)
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return evaluation.ErrExperimentNotFound
+	}
+	return nil
+}
+
+func (r *ExperimentRepository) UpdateGates(ctx context.Context, experiment *evaluation.Experiment, projectID ulid.ULID) error {
+	result := r.getDB(ctx).WithContext(ctx).
+		Model(experiment).
+		Where("id = ? AND project_id = ?", experiment.ID.String(), projectID.String()).
+		Select("gates", "gate_verdict", "updated_at").
+		Updates(experiment)
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return evaluation.ErrExperimentNotFound
+	}
+	return nil
+}
+
 // IncrementCounters atomically increments completed and/or failed counters.
 func (r *ExperimentRepository) IncrementCounters(ctx context.Context, id, projectID ulid.ULID, completed, failed int) error {
 	updates := map[string]interface{}{}
@@ -184,9 +217,9 @@ func (r *ExperimentRepository) IncrementCountersAndUpdateStatus(ctx context.Cont
 		exp.CompletedItems += completed
 		exp.FailedItems += failed
 
-		// Check if complete
+		// Check if complete; a cancelled experiment keeps its status while in-flight items land
 		processedItems := exp.CompletedItems + exp.FailedItems
-		if processedItems >= exp.TotalItems && exp.TotalItems > 0 {
+		if processedItems >= exp.TotalItems && exp.TotalItems > 0 && exp.Status != evaluation.ExperimentStatusCancelled {
 			isComplete = true
 			now := time.Now()
 			exp.CompletedAt = &now
@@ -0,0 +1,162 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"brokle/internal/core/domain/observability"
+
+	"gorm.io/gorm"
+)
+
+type alertRuleRepository struct {
+	db *gorm.DB
+}
+
+// NewAlertRuleRepository creates a new PostgreSQL alert rule repository.
+func NewAlertRuleRepository(db *gorm.DB) observability.AlertRuleRepository {
+	return &alertRuleRepository{db: db}
+}
+
+func (r *alertRuleRepository) Create(ctx context.Context, rule *observability.AlertRule) error {
+	if err := r.db.WithContext(ctx).Create(rule).Error; err != nil {
+		return fmt.Errorf("create alert rule: %w", err)
+	}
+	return nil
+}
+
+func (r *alertRuleRepository) GetByID(ctx context.Context, id string) (*observability.AlertRule, error) {
+	var rule observability.AlertRule
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&rule).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("get alert rule by id: %w", err)
+	}
+	return &rule, nil
+}
+
+// Update writes the configurable columns; evaluation state is owned by UpdateEvaluation.
+func (r *alertRuleRepository) Update(ctx context.Context, rule *observability.AlertRule) error {
+	result := r.db.WithContext(ctx).Model(rule).Updates(map[string]interface{}{
+		"name":             rule.Name,
+		"description":      rule.Description,
+		"filter_preset_id": rule.FilterPresetID,
+		"filter":           rule.Filter,
+		"aggregation":      rule.Aggregation,
+		"operator":         rule.Operator,
+		"threshold":        rule.Threshold,
+		"window_minutes":   rule.WindowMinutes,
+		"pending_minutes":  rule.PendingMinutes,
+		"channels":         rule.Channels,
+		"enabled":          rule.Enabled,
+		"state":            rule.State,
+		"state_changed_at": rule.StateChangedAt,
+		"silenced_until":   rule.SilencedUntil,
+		"silence_reason":   rule.SilenceReason,
+		"webhook_secret":   rule.WebhookSecret,
+	})
+	if result.Error != nil {
+		return fmt.Errorf("update alert rule: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *alertRuleRepository) UpdateEvaluation(ctx context.Context, rule *observability.AlertRule) error {
+	result := r.db.WithContext(ctx).Model(rule).Updates(map[string]interface{}{
+		"state":             rule.State,
+		"state_changed_at":  rule.StateChangedAt,
+		"last_value":        rule.LastValue,
+		"last_evaluated_at": rule.LastEvaluatedAt,
+	})
+	if result.Error != nil {
+		return fmt.Errorf("update alert rule evaluation: %w", result.Error)
+	}
+	return nil
+}
+
+func (r *alertRuleRepository) Delete(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Where("id = ?", id).Delete(&observability.AlertRule{})
+	if result.Error != nil {
+		return fmt.Errorf("delete alert rule: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *alertRuleRepository) ListByProject(ctx context.Context, projectID string) ([]*observability.AlertRule, error) {
+	var rules []*observability.AlertRule
+	err := r.db.WithContext(ctx).
+		Where("project_id = ?", projectID).
+		Order("created_at DESC").
+		Find(&rules).Error
+	if err != nil {
+		return nil, fmt.Errorf("list alert rules: %w", err)
+	}
+	return rules, nil
+}
+
+func (r *alertRuleRepository) ListEnabled(ctx context.Context) ([]*observability.AlertRule, error) {
+	var rules []*observability.AlertRule
+	err := r.db.WithContext(ctx).
+		Where("enabled = ?", true).
+		Order("created_at ASC").
+		Find(&rules).Error
+	if err != nil {
+		return nil, fmt.Errorf("list enabled alert rules: %w", err)
+	}
+	return rules, nil
+}
+
+func (r *alertRuleRepository) ExistsByName(ctx context.Context, projectID, name string, excludeID *string) (bool, error) {
+	query := r.db.WithContext(ctx).Model(&observability.AlertRule{}).
+		Where("project_id = ? AND name = ?", projectID, name)
+	if excludeID != nil {
+		query = query.Where("id != ?", *excludeID)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return false, fmt.Errorf("check alert rule name: %w", err)
+	}
+	return count > 0, nil
+}
+
+type alertEventRepository struct {
+	db *gorm.DB
+}
+
+// NewAlertEventRepository creates a new PostgreSQL alert history repository.
+func NewAlertEventRepository(db *gorm.DB) observability.AlertEventRepository {
+	return &alertEventRepository{db: db}
+}
+
+func (r *alertEventRepository) Create(ctx context.Context, event *observability.AlertEvent) error {
+	if err := r.db.WithContext(ctx).Create(event).Error; err != nil {
+		return fmt.Errorf("create alert event: %w", err)
+	}
+	return nil
+}
+
+func (r *alertEventRepository) ListByRule(ctx context.Context, ruleID string, limit, offset int) ([]*observability.AlertEvent, int64, error) {
+	query := r.db.WithContext(ctx).Model(&observability.AlertEvent{}).Where("rule_id = ?", ruleID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count alert events: %w", err)
+	}
+
+	var events []*observability.AlertEvent
+	err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&events).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("list alert events: %w", err)
+	}
+	return events, total, nil
+}
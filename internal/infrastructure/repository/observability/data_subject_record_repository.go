@@ -0,0 +1,88 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+
+	"brokle/internal/core/domain/comment"
+	"brokle/internal/core/domain/evaluation"
+	"brokle/internal/core/domain/observability"
+	"brokle/pkg/ulid"
+
+	"gorm.io/gorm"
+)
+
+type dataSubjectRecordRepository struct {
+	db *gorm.DB
+}
+
+// NewDataSubjectRecordRepository creates a PostgreSQL repository for records derived from traces.
+func NewDataSubjectRecordRepository(db *gorm.DB) observability.DataSubjectRecordRepository {
+	return &dataSubjectRecordRepository{db: db}
+}
+
+func (r *dataSubjectRecordRepository) ListComments(ctx context.Context, projectID ulid.ULID, traceIDs []string) ([]*comment.Comment, error) {
+	comments := make([]*comment.Comment, 0)
+	if len(traceIDs) == 0 {
+		return comments, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Where("project_id = ? AND entity_type = ? AND entity_id IN ?", projectID, comment.EntityTypeTrace, traceIDs).
+		Order("created_at ASC").
+		Find(&comments).Error
+	if err != nil {
+		return nil, fmt.Errorf("list trace comments: %w", err)
+	}
+	return comments, nil
+}
+
+// DeleteComments also removes soft-deleted comments, whose content is still stored.
+// Replies and reactions cascade.
+func (r *dataSubjectRecordRepository) DeleteComments(ctx context.Context, projectID ulid.ULID, traceIDs []string) (int64, error) {
+	if len(traceIDs) == 0 {
+		return 0, nil
+	}
+
+	result := r.db.WithContext(ctx).Unscoped().
+		Where("project_id = ? AND entity_type = ? AND entity_id IN ?", projectID, comment.EntityTypeTrace, traceIDs).
+		Delete(&comment.Comment{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("delete trace comments: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *dataSubjectRecordRepository) ListDatasetItems(ctx context.Context, projectID ulid.ULID, traceIDs []string) ([]*evaluation.DatasetItem, error) {
+	items := make([]*evaluation.DatasetItem, 0)
+	if len(traceIDs) == 0 {
+		return items, nil
+	}
+
+	err := r.projectDatasetItems(ctx, projectID, traceIDs).
+		Order("created_at ASC").
+		Find(&items).Error
+	if err != nil {
+		return nil, fmt.Errorf("list dataset items: %w", err)
+	}
+	return items, nil
+}
+
+// DeleteDatasetItems removes the items from every dataset version; experiment items keep their results.
+func (r *dataSubjectRecordRepository) DeleteDatasetItems(ctx context.Context, projectID ulid.ULID, traceIDs []string) (int64, error) {
+	if len(traceIDs) == 0 {
+		return 0, nil
+	}
+
+	result := r.projectDatasetItems(ctx, projectID, traceIDs).Delete(&evaluation.DatasetItem{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("delete dataset items: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *dataSubjectRecordRepository) projectDatasetItems(ctx context.Context, projectID ulid.ULID, traceIDs []string) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Where("source_trace_id IN ?", traceIDs).
+		Where("dataset_id IN (?)", r.db.Model(&evaluation.Dataset{}).Select("id").Where("project_id = ?", projectID))
+}
@@ -0,0 +1,150 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"brokle/internal/core/domain/observability"
+	"brokle/pkg/ulid"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type dataSubjectRequestRepository struct {
+	db *gorm.DB
+}
+
+// NewDataSubjectRequestRepository creates a new PostgreSQL data subject request repository.
+func NewDataSubjectRequestRepository(db *gorm.DB) observability.DataSubjectRequestRepository {
+	return &dataSubjectRequestRepository{db: db}
+}
+
+func (r *dataSubjectRequestRepository) Create(ctx context.Context, req *observability.DataSubjectRequest) error {
+	if err := r.db.WithContext(ctx).Create(req).Error; err != nil {
+		return fmt.Errorf("create data subject request: %w", err)
+	}
+	return nil
+}
+
+func (r *dataSubjectRequestRepository) GetByID(ctx context.Context, id, projectID ulid.ULID) (*observability.DataSubjectRequest, error) {
+	var req observability.DataSubjectRequest
+	err := r.db.WithContext(ctx).
+		Omit("bundle").
+		Where("id = ? AND project_id = ?", id, projectID).
+		First(&req).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("get data subject request: %w", err)
+	}
+	return &req, nil
+}
+
+func (r *dataSubjectRequestRepository) ListByProject(ctx context.Context, projectID ulid.ULID, limit, offset int) ([]*observability.DataSubjectRequest, int64, error) {
+	query := r.db.WithContext(ctx).Model(&observability.DataSubjectRequest{}).Where("project_id = ?", projectID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count data subject requests: %w", err)
+	}
+
+	var requests []*observability.DataSubjectRequest
+	err := query.Omit("bundle", "trace_ids").
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&requests).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("list data subject requests: %w", err)
+	}
+	return requests, total, nil
+}
+
+func (r *dataSubjectRequestRepository) GetBundle(ctx context.Context, id, projectID ulid.ULID) ([]byte, error) {
+	var req observability.DataSubjectRequest
+	err := r.db.WithContext(ctx).
+		Select("bundle").
+		Where("id = ? AND project_id = ?", id, projectID).
+		First(&req).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("get data subject export bundle: %w", err)
+	}
+	return req.Bundle, nil
+}
+
+// Update writes all columns; only the worker holding the lease updates a request.
+func (r *dataSubjectRequestRepository) Update(ctx context.Context, req *observability.DataSubjectRequest) error {
+	if err := r.db.WithContext(ctx).Save(req).Error; err != nil {
+		return fmt.Errorf("update data subject request: %w", err)
+	}
+	return nil
+}
+
+// ClaimNext locks the next request with FOR UPDATE SKIP LOCKED and leases it.
+func (r *dataSubjectRequestRepository) ClaimNext(ctx context.Context, now time.Time, lease time.Duration) (*observability.DataSubjectRequest, error) {
+	var req observability.DataSubjectRequest
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Where("status = ? OR (status = ? AND lease_expires_at < ?)",
+				observability.DataSubjectStatusPending, observability.DataSubjectStatusRunning, now).
+			Order("created_at ASC").
+			Clauses(clause.Locking{
+				Strength: "UPDATE",
+				Options:  "SKIP LOCKED",
+			}).
+			First(&req).Error
+		if err != nil {
+			return err
+		}
+
+		leaseUntil := now.Add(lease)
+		req.Status = observability.DataSubjectStatusRunning
+		req.LeaseExpiresAt = &leaseUntil
+		req.Attempts++
+		if req.StartedAt == nil {
+			req.StartedAt = &now
+		}
+		return tx.Save(&req).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("claim data subject request: %w", err)
+	}
+	return &req, nil
+}
+
+func (r *dataSubjectRequestRepository) CountOverdueErasures(ctx context.Context, createdBefore time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT COUNT(*) FROM data_subject_requests r
+		WHERE r.type = ?
+		  AND r.status <> ?
+		  AND r.created_at < ?
+		  AND NOT EXISTS (
+			SELECT 1 FROM data_subject_requests later
+			WHERE later.project_id = r.project_id
+			  AND later.user_id = r.user_id
+			  AND later.type = r.type
+			  AND later.status = ?
+			  AND later.created_at > r.created_at
+		  )`,
+		observability.DataSubjectRequestErasure,
+		observability.DataSubjectStatusCompleted,
+		createdBefore,
+		observability.DataSubjectStatusCompleted,
+	).Scan(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("count overdue erasure requests: %w", err)
+	}
+	return count, nil
+}
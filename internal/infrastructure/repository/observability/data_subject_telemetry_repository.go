@@ -0,0 +1,151 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"brokle/internal/core/domain/observability"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+)
+
+// dataSubjectChunkSize bounds the trace IDs bound into one IN clause.
+const dataSubjectChunkSize = 1000
+
+type dataSubjectTelemetryRepository struct {
+	db clickhouse.Conn
+}
+
+// NewDataSubjectTelemetryRepository creates a ClickHouse repository that finds and erases an end user's telemetry.
+func NewDataSubjectTelemetryRepository(db clickhouse.Conn) observability.DataSubjectTelemetryRepository {
+	return &dataSubjectTelemetryRepository{db: db}
+}
+
+// ListTraceIDs returns the traces with a span carrying the user's user.id attribute.
+func (r *dataSubjectTelemetryRepository) ListTraceIDs(ctx context.Context, projectID, userID string) ([]string, error) {
+	rows, err := r.db.Query(ctx, `SELECT DISTINCT trace_id FROM otel_traces WHERE project_id = ? AND user_id = ?`, projectID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query user trace ids: %w", err)
+	}
+	defer rows.Close()
+
+	traceIDs := make([]string, 0)
+	for rows.Next() {
+		var traceID string
+		if err := rows.Scan(&traceID); err != nil {
+			return nil, fmt.Errorf("scan trace id: %w", err)
+		}
+		traceIDs = append(traceIDs, traceID)
+	}
+	return traceIDs, rows.Err()
+}
+
+func (r *dataSubjectTelemetryRepository) ListLogs(ctx context.Context, projectID string, traceIDs []string) ([]*observability.Log, error) {
+	logs := make([]*observability.Log, 0)
+	for _, chunk := range chunkTraceIDs(traceIDs) {
+		placeholders, args := inPlaceholders(projectID, chunk)
+		rows, err := r.db.Query(ctx, `
+			SELECT timestamp, observed_timestamp, trace_id, span_id, trace_flags,
+				severity_text, severity_number, body, resource_attributes, service_name,
+				scope_name, scope_attributes, log_attributes, project_id
+			FROM otel_logs
+			WHERE project_id = ? AND trace_id IN (`+placeholders+`)
+			ORDER BY timestamp`, args...)
+		if err != nil {
+			return nil, fmt.Errorf("query logs: %w", err)
+		}
+
+		for rows.Next() {
+			var log observability.Log
+			if err := rows.ScanStruct(&log); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("scan log: %w", err)
+			}
+			logs = append(logs, &log)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return logs, nil
+}
+
+// Count counts GenAI events of the user once, whether or not they belong to one of the traces.
+func (r *dataSubjectTelemetryRepository) Count(ctx context.Context, projectID, userID string, traceIDs []string) (*observability.DataSubjectCounts, error) {
+	counts := &observability.DataSubjectCounts{}
+	if err := r.count(ctx, &counts.GenAIEvents, `SELECT count() FROM otel_genai_events WHERE project_id = ? AND user_id = ?`, projectID, userID); err != nil {
+		return nil, err
+	}
+
+	for _, chunk := range chunkTraceIDs(traceIDs) {
+		placeholders, args := inPlaceholders(projectID, chunk)
+		if err := r.count(ctx, &counts.Spans, `SELECT count() FROM otel_traces WHERE project_id = ? AND trace_id IN (`+placeholders+`)`, args...); err != nil {
+			return nil, err
+		}
+		if err := r.count(ctx, &counts.Scores, `SELECT count() FROM scores WHERE project_id = ? AND trace_id IN (`+placeholders+`)`, args...); err != nil {
+			return nil, err
+		}
+		if err := r.count(ctx, &counts.Logs, `SELECT count() FROM otel_logs WHERE project_id = ? AND trace_id IN (`+placeholders+`)`, args...); err != nil {
+			return nil, err
+		}
+		genaiArgs := append(args[:len(args):len(args)], userID)
+		if err := r.count(ctx, &counts.GenAIEvents, `SELECT count() FROM otel_genai_events WHERE project_id = ? AND trace_id IN (`+placeholders+`) AND user_id != ?`, genaiArgs...); err != nil {
+			return nil, err
+		}
+	}
+	return counts, nil
+}
+
+// count adds the result of a count() query to total.
+func (r *dataSubjectTelemetryRepository) count(ctx context.Context, total *uint64, query string, args ...interface{}) error {
+	var n uint64
+	if err := r.db.QueryRow(ctx, query, args...).Scan(&n); err != nil {
+		return fmt.Errorf("count user telemetry: %w", err)
+	}
+	*total += n
+	return nil
+}
+
+// Delete waits for the DELETE mutations to finish on every replica, so a
+// completed request means the rows are gone rather than scheduled for removal.
+func (r *dataSubjectTelemetryRepository) Delete(ctx context.Context, projectID, userID string, traceIDs []string) error {
+	ctx = clickhouse.Context(ctx, clickhouse.WithSettings(clickhouse.Settings{"mutations_sync": 2}))
+
+	if err := r.db.Exec(ctx, `ALTER TABLE otel_genai_events DELETE WHERE project_id = ? AND user_id = ?`, projectID, userID); err != nil {
+		return fmt.Errorf("delete user genai events: %w", err)
+	}
+
+	for _, chunk := range chunkTraceIDs(traceIDs) {
+		placeholders, args := inPlaceholders(projectID, chunk)
+		for _, table := range []string{"scores", "otel_logs", "otel_genai_events", "otel_traces"} {
+			query := fmt.Sprintf(`ALTER TABLE %s DELETE WHERE project_id = ? AND trace_id IN (%s)`, table, placeholders)
+			if err := r.db.Exec(ctx, query, args...); err != nil {
+				return fmt.Errorf("delete user traces from %s: %w", table, err)
+			}
+		}
+	}
+	return nil
+}
+
+func chunkTraceIDs(traceIDs []string) [][]string {
+	var chunks [][]string
+	for start := 0; start < len(traceIDs); start += dataSubjectChunkSize {
+		chunks = append(chunks, traceIDs[start:min(start+dataSubjectChunkSize, len(traceIDs))])
+	}
+	return chunks
+}
+
+// inPlaceholders returns "?,?,..." for the values and the arguments led by the project ID.
+func inPlaceholders(projectID string, values []string) (string, []interface{}) {
+	placeholders := make([]string, len(values))
+	args := make([]interface{}, 0, len(values)+1)
+	args = append(args, projectID)
+	for i, value := range values {
+		placeholders[i] = "?"
+		args = append(args, value)
+	}
+	return strings.Join(placeholders, ","), args
+}
@@ -0,0 +1,52 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"brokle/internal/core/domain/observability"
+	"brokle/pkg/ulid"
+
+	"gorm.io/gorm"
+)
+
+type redactionPolicyRepository struct {
+	db *gorm.DB
+}
+
+// NewRedactionPolicyRepository creates a new PostgreSQL redaction policy repository.
+func NewRedactionPolicyRepository(db *gorm.DB) observability.RedactionPolicyRepository {
+	return &redactionPolicyRepository{db: db}
+}
+
+func (r *redactionPolicyRepository) GetByProject(ctx context.Context, projectID ulid.ULID) (*observability.RedactionPolicy, error) {
+	var policy observability.RedactionPolicy
+	err := r.db.WithContext(ctx).Where("project_id = ?", projectID).First(&policy).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("get redaction policy: %w", err)
+	}
+	return &policy, nil
+}
+
+// Upsert writes all policy columns, so cleared lists replace previous ones.
+func (r *redactionPolicyRepository) Upsert(ctx context.Context, policy *observability.RedactionPolicy) error {
+	if err := r.db.WithContext(ctx).Save(policy).Error; err != nil {
+		return fmt.Errorf("upsert redaction policy: %w", err)
+	}
+	return nil
+}
+
+func (r *redactionPolicyRepository) DeleteByProject(ctx context.Context, projectID ulid.ULID) error {
+	result := r.db.WithContext(ctx).Where("project_id = ?", projectID).Delete(&observability.RedactionPolicy{})
+	if result.Error != nil {
+		return fmt.Errorf("delete redaction policy: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"brokle/internal/core/domain/observability"
+	"brokle/pkg/ulid"
+
+	"gorm.io/gorm"
+)
+
+type retentionPolicyRepository struct {
+	db *gorm.DB
+}
+
+// NewRetentionPolicyRepository creates a new PostgreSQL retention policy repository.
+func NewRetentionPolicyRepository(db *gorm.DB) observability.RetentionPolicyRepository {
+	return &retentionPolicyRepository{db: db}
+}
+
+func (r *retentionPolicyRepository) GetByOrganization(ctx context.Context, orgID ulid.ULID) (*observability.RetentionPolicy, error) {
+	var policy observability.RetentionPolicy
+	err := r.db.WithContext(ctx).
+		Where("organization_id = ? AND project_id IS NULL", orgID).
+		First(&policy).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("get organization retention policy: %w", err)
+	}
+	return &policy, nil
+}
+
+func (r *retentionPolicyRepository) GetByProject(ctx context.Context, projectID ulid.ULID) (*observability.RetentionPolicy, error) {
+	var policy observability.RetentionPolicy
+	err := r.db.WithContext(ctx).Where("project_id = ?", projectID).First(&policy).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("get project retention policy: %w", err)
+	}
+	return &policy, nil
+}
+
+func (r *retentionPolicyRepository) List(ctx context.Context) ([]*observability.RetentionPolicy, error) {
+	var policies []*observability.RetentionPolicy
+	if err := r.db.WithContext(ctx).Order("created_at ASC").Find(&policies).Error; err != nil {
+		return nil, fmt.Errorf("list retention policies: %w", err)
+	}
+	return policies, nil
+}
+
+// Upsert writes all retention columns, so nil days clear a previous override.
+func (r *retentionPolicyRepository) Upsert(ctx context.Context, policy *observability.RetentionPolicy) error {
+	if err := r.db.WithContext(ctx).Save(policy).Error; err != nil {
+		return fmt.Errorf("upsert retention policy: %w", err)
+	}
+	return nil
+}
+
+func (r *retentionPolicyRepository) DeleteByProject(ctx context.Context, projectID ulid.ULID) error {
+	result := r.db.WithContext(ctx).Where("project_id = ?", projectID).Delete(&observability.RetentionPolicy{})
+	if result.Error != nil {
+		return fmt.Errorf("delete project retention policy: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
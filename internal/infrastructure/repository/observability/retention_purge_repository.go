@@ -0,0 +1,168 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"brokle/internal/core/domain/observability"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+)
+
+// retentionTable is a ClickHouse table partitioned by (toYYYYMM(<timeColumn>), project_id).
+type retentionTable struct {
+	name       string
+	timeColumn string
+}
+
+// retentionTables maps each signal to the tables holding its data.
+var retentionTables = map[observability.RetentionSignal][]retentionTable{
+	observability.RetentionSignalSpans: {{"otel_traces", "start_time"}},
+	observability.RetentionSignalLogs:  {{"otel_logs", "timestamp"}},
+	observability.RetentionSignalMetrics: {
+		{"otel_metrics_sum", "time_unix"},
+		{"otel_metrics_gauge", "time_unix"},
+		{"otel_metrics_histogram", "time_unix"},
+		{"otel_metrics_exponential_histogram", "time_unix"},
+	},
+	observability.RetentionSignalScores:      {{"scores", "timestamp"}},
+	observability.RetentionSignalGenAIEvents: {{"otel_genai_events", "timestamp"}},
+}
+
+type retentionPurgeRepository struct {
+	db clickhouse.Conn
+}
+
+// NewRetentionPurgeRepository creates a ClickHouse repository that purges expired telemetry.
+func NewRetentionPurgeRepository(db clickhouse.Conn) observability.RetentionPurgeRepository {
+	return &retentionPurgeRepository{db: db}
+}
+
+func (r *retentionPurgeRepository) tablesFor(signal observability.RetentionSignal) ([]retentionTable, error) {
+	tables, ok := retentionTables[signal]
+	if !ok {
+		return nil, fmt.Errorf("unknown retention signal: %s", signal)
+	}
+	return tables, nil
+}
+
+func (r *retentionPurgeRepository) ListProjectsWithDataBefore(ctx context.Context, signal observability.RetentionSignal, cutoff time.Time) ([]string, error) {
+	tables, err := r.tablesFor(signal)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]struct{})
+	var projectIDs []string
+	for _, t := range tables {
+		query := fmt.Sprintf(`SELECT DISTINCT project_id FROM %s WHERE %s < ?`, t.name, t.timeColumn)
+		rows, err := r.db.Query(ctx, query, cutoff)
+		if err != nil {
+			return nil, fmt.Errorf("list projects in %s: %w", t.name, err)
+		}
+		for rows.Next() {
+			var projectID string
+			if err := rows.Scan(&projectID); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("scan project id: %w", err)
+			}
+			if _, ok := seen[projectID]; !ok {
+				seen[projectID] = struct{}{}
+				projectIDs = append(projectIDs, projectID)
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("iterate projects in %s: %w", t.name, err)
+		}
+	}
+	return projectIDs, nil
+}
+
+func (r *retentionPurgeRepository) CountBefore(ctx context.Context, signal observability.RetentionSignal, projectID string, cutoff time.Time) (uint64, error) {
+	tables, err := r.tablesFor(signal)
+	if err != nil {
+		return 0, err
+	}
+
+	var total uint64
+	for _, t := range tables {
+		query := fmt.Sprintf(`SELECT count() FROM %s WHERE project_id = ? AND %s < ?`, t.name, t.timeColumn)
+		var count uint64
+		if err := r.db.QueryRow(ctx, query, projectID, cutoff).Scan(&count); err != nil {
+			return 0, fmt.Errorf("count expired rows in %s: %w", t.name, err)
+		}
+		total += count
+	}
+	return total, nil
+}
+
+// PurgeBefore drops whole (month, project) partitions that end before the cutoff and
+// issues a DELETE mutation for the remaining rows in the cutoff's own month.
+func (r *retentionPurgeRepository) PurgeBefore(ctx context.Context, signal observability.RetentionSignal, projectID string, cutoff time.Time) (*observability.RetentionPurgeResult, error) {
+	tables, err := r.tablesFor(signal)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &observability.RetentionPurgeResult{}
+	for _, t := range tables {
+		months, err := r.expiredMonths(ctx, t, projectID, cutoff)
+		if err != nil {
+			return result, err
+		}
+
+		cutoffMonth := partitionMonth(cutoff)
+		hasBoundaryRows := false
+		for _, month := range months {
+			if month >= cutoffMonth {
+				hasBoundaryRows = true
+				continue
+			}
+			query := fmt.Sprintf(`ALTER TABLE %s DROP PARTITION tuple(?, ?)`, t.name)
+			if err := r.db.Exec(ctx, query, month, projectID); err != nil {
+				return result, fmt.Errorf("drop partition %d of %s: %w", month, t.name, err)
+			}
+			result.PartitionsDropped++
+		}
+
+		if hasBoundaryRows {
+			query := fmt.Sprintf(`ALTER TABLE %s DELETE WHERE project_id = ? AND %s < ?`, t.name, t.timeColumn)
+			if err := r.db.Exec(ctx, query, projectID, cutoff); err != nil {
+				return result, fmt.Errorf("delete expired rows from %s: %w", t.name, err)
+			}
+			result.DeleteMutations++
+		}
+	}
+	return result, nil
+}
+
+// expiredMonths returns the YYYYMM partitions holding rows older than the cutoff.
+func (r *retentionPurgeRepository) expiredMonths(ctx context.Context, t retentionTable, projectID string, cutoff time.Time) ([]uint32, error) {
+	query := fmt.Sprintf(
+		`SELECT DISTINCT toYYYYMM(%s) AS month FROM %s WHERE project_id = ? AND %s < ? ORDER BY month`,
+		t.timeColumn, t.name, t.timeColumn,
+	)
+	rows, err := r.db.Query(ctx, query, projectID, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("list expired partitions in %s: %w", t.name, err)
+	}
+	defer rows.Close()
+
+	var months []uint32
+	for rows.Next() {
+		var month uint32
+		if err := rows.Scan(&month); err != nil {
+			return nil, fmt.Errorf("scan partition month: %w", err)
+		}
+		months = append(months, month)
+	}
+	return months, rows.Err()
+}
+
+// partitionMonth mirrors toYYYYMM for a UTC timestamp.
+func partitionMonth(t time.Time) uint32 {
+	u := t.UTC()
+	return uint32(u.Year()*100 + int(u.Month()))
+}
@@ -0,0 +1,52 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"brokle/internal/core/domain/observability"
+	"brokle/pkg/ulid"
+
+	"gorm.io/gorm"
+)
+
+type samplingPolicyRepository struct {
+	db *gorm.DB
+}
+
+// NewSamplingPolicyRepository creates a new PostgreSQL sampling policy repository.
+func NewSamplingPolicyRepository(db *gorm.DB) observability.SamplingPolicyRepository {
+	return &samplingPolicyRepository{db: db}
+}
+
+func (r *samplingPolicyRepository) GetByProject(ctx context.Context, projectID ulid.ULID) (*observability.SamplingPolicy, error) {
+	var policy observability.SamplingPolicy
+	err := r.db.WithContext(ctx).Where("project_id = ?", projectID).First(&policy).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("get sampling policy: %w", err)
+	}
+	return &policy, nil
+}
+
+// Upsert writes all policy columns, so nil thresholds clear a previous rule.
+func (r *samplingPolicyRepository) Upsert(ctx context.Context, policy *observability.SamplingPolicy) error {
+	if err := r.db.WithContext(ctx).Save(policy).Error; err != nil {
+		return fmt.Errorf("upsert sampling policy: %w", err)
+	}
+	return nil
+}
+
+func (r *samplingPolicyRepository) DeleteByProject(ctx context.Context, projectID ulid.ULID) error {
+	result := r.db.WithContext(ctx).Where("project_id = ?", projectID).Delete(&observability.SamplingPolicy{})
+	if result.Error != nil {
+		return fmt.Errorf("delete sampling policy: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
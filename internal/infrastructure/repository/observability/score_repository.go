@@ -410,6 +410,70 @@ func (r *scoreRepository) GetAggregationsByExperiments(
 	return result, nil
 }
 
+// GetItemScoresByExperiments returns every experiment item score of the experiments.
+// Only the experiment, item, name, type and value fields are populated.
+func (r *scoreRepository) GetItemScoresByExperiments(
+	ctx context.Context,
+	projectID string,
+	experimentIDs []string,
+) ([]*observability.Score, error) {
+	if len(experimentIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT
+			score_id,
+			experiment_id,
+			experiment_item_id,
+			name,
+			type,
+			value,
+			string_value,
+			metadata
+		FROM scores
+		WHERE project_id = ?
+		  AND experiment_id IN (?)
+		  AND experiment_item_id IS NOT NULL
+		  AND experiment_item_id != ''
+	`
+
+	expIDs := make(clickhouse.ArraySet, len(experimentIDs))
+	for i, id := range experimentIDs {
+		expIDs[i] = id
+	}
+
+	rows, err := r.db.Query(ctx, query, projectID, expIDs)
+	if err != nil {
+		return nil, fmt.Errorf("query experiment item scores: %w", err)
+	}
+	defer rows.Close()
+
+	var scores []*observability.Score
+	for rows.Next() {
+		score := &observability.Score{ProjectID: projectID}
+		if err := rows.Scan(
+			&score.ID,
+			&score.ExperimentID,
+			&score.ExperimentItemID,
+			&score.Name,
+			&score.Type,
+			&score.Value,
+			&score.StringValue,
+			&score.Metadata,
+		); err != nil {
+			return nil, fmt.Errorf("scan experiment item score: %w", err)
+		}
+		scores = append(scores, score)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate experiment item scores: %w", err)
+	}
+
+	return scores, nil
+}
+
 // Helper function to scan a single score from query row
 func (r *scoreRepository) scanScoreRow(row driver.Row) (*observability.Score, error) {
 	var score observability.Score
@@ -0,0 +1,171 @@
+package observability
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"brokle/internal/core/domain/observability"
+	"brokle/internal/infrastructure/database"
+	"brokle/pkg/ulid"
+)
+
+const (
+	tailSamplingPendingKey      = "tail_sampling:pending" // ZSET of {project}:{trace} scored by decision time (unix ms)
+	tailSamplingBufferPrefix    = "tail_sampling:buffer:"
+	tailSamplingDecisionPrefix  = "tail_sampling:decision:"
+	tailSamplingBufferRetention = time.Hour // Safety TTL for buffers nobody claims
+)
+
+// tailSamplingBufferRepository buffers spans of undecided traces in Redis lists.
+// A pending ZSET orders traces by decision time; removing a member claims the trace.
+type tailSamplingBufferRepository struct {
+	redis *database.RedisDB
+}
+
+// NewTailSamplingBufferRepository creates a new Redis-based tail sampling buffer
+func NewTailSamplingBufferRepository(redis *database.RedisDB) observability.TailSamplingBuffer {
+	return &tailSamplingBufferRepository{redis: redis}
+}
+
+func (r *tailSamplingBufferRepository) Add(ctx context.Context, projectID ulid.ULID, traceID string, spans []*observability.BufferedSpan, decideAt time.Time) error {
+	if len(spans) == 0 {
+		return nil
+	}
+
+	values := make([]interface{}, 0, len(spans))
+	for _, span := range spans {
+		data, err := json.Marshal(span)
+		if err != nil {
+			return fmt.Errorf("failed to marshal buffered span: %w", err)
+		}
+		values = append(values, data)
+	}
+
+	member := pendingMember(projectID, traceID)
+	bufferKey := tailSamplingBufferPrefix + member
+
+	pipe := r.redis.Client.TxPipeline()
+	pipe.RPush(ctx, bufferKey, values...)
+	pipe.Expire(ctx, bufferKey, time.Until(decideAt)+tailSamplingBufferRetention)
+	// NX keeps the decision time of the first span of the trace
+	pipe.ZAddNX(ctx, tailSamplingPendingKey, redis.Z{Score: float64(decideAt.UnixMilli()), Member: member})
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to buffer spans: %w", err)
+	}
+	return nil
+}
+
+func (r *tailSamplingBufferRepository) ClaimDue(ctx context.Context, now time.Time, limit int) ([]*observability.BufferedTrace, error) {
+	members, err := r.redis.Client.ZRangeByScore(ctx, tailSamplingPendingKey, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(now.UnixMilli(), 10),
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due traces: %w", err)
+	}
+
+	traces := make([]*observability.BufferedTrace, 0, len(members))
+	for _, member := range members {
+		// Another consumer may have claimed the trace since the range read
+		removed, err := r.redis.Client.ZRem(ctx, tailSamplingPendingKey, member).Result()
+		if err != nil {
+			return traces, fmt.Errorf("failed to claim trace: %w", err)
+		}
+		if removed == 0 {
+			continue
+		}
+
+		trace, err := r.drain(ctx, member)
+		if err != nil {
+			return traces, err
+		}
+		if trace != nil {
+			traces = append(traces, trace)
+		}
+	}
+
+	return traces, nil
+}
+
+// drain reads and deletes a claimed trace's buffer.
+func (r *tailSamplingBufferRepository) drain(ctx context.Context, member string) (*observability.BufferedTrace, error) {
+	projectStr, traceID, ok := strings.Cut(member, ":")
+	if !ok {
+		return nil, nil
+	}
+	projectID, err := ulid.Parse(projectStr)
+	if err != nil {
+		return nil, nil
+	}
+
+	bufferKey := tailSamplingBufferPrefix + member
+	pipe := r.redis.Client.TxPipeline()
+	rangeCmd := pipe.LRange(ctx, bufferKey, 0, -1)
+	pipe.Del(ctx, bufferKey)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to drain trace buffer: %w", err)
+	}
+
+	trace := &observability.BufferedTrace{ProjectID: projectID, TraceID: traceID}
+	for _, raw := range rangeCmd.Val() {
+		var span observability.BufferedSpan
+		if err := json.Unmarshal([]byte(raw), &span); err != nil {
+			continue
+		}
+		trace.Spans = append(trace.Spans, &span)
+	}
+	return trace, nil
+}
+
+func (r *tailSamplingBufferRepository) SetDecision(ctx context.Context, projectID ulid.ULID, traceID string, decision *observability.TailDecision, ttl time.Duration) error {
+	data, err := json.Marshal(decision)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tail decision: %w", err)
+	}
+	if err := r.redis.Set(ctx, tailSamplingDecisionPrefix+pendingMember(projectID, traceID), data, ttl); err != nil {
+		return fmt.Errorf("failed to store tail decision: %w", err)
+	}
+	return nil
+}
+
+func (r *tailSamplingBufferRepository) GetDecisions(ctx context.Context, projectID ulid.ULID, traceIDs []string) (map[string]*observability.TailDecision, error) {
+	decisions := make(map[string]*observability.TailDecision)
+	if len(traceIDs) == 0 {
+		return decisions, nil
+	}
+
+	keys := make([]string, len(traceIDs))
+	for i, traceID := range traceIDs {
+		keys[i] = tailSamplingDecisionPrefix + pendingMember(projectID, traceID)
+	}
+
+	values, err := r.redis.Client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tail decisions: %w", err)
+	}
+
+	for i, value := range values {
+		raw, ok := value.(string)
+		if !ok {
+			continue
+		}
+		var decision observability.TailDecision
+		if err := json.Unmarshal([]byte(raw), &decision); err != nil {
+			continue
+		}
+		decisions[traceIDs[i]] = &decision
+	}
+	return decisions, nil
+}
+
+func pendingMember(projectID ulid.ULID, traceID string) string {
+	return projectID.String() + ":" + traceID
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
@@ -166,6 +167,13 @@ func textSearchCondition(filter *observability.TraceFilter) (condition string, a
 	}
 }
 
+func expressionFilterCondition(filter *observability.TraceFilter) (condition string, args []interface{}) {
+	if filter == nil || filter.FilterCondition == "" {
+		return "", nil
+	}
+	return " AND " + filter.FilterCondition, filter.FilterArgs
+}
+
 func statusHavingClauses(filter *observability.TraceFilter) (clauses []string, args []interface{}) {
 	if filter == nil {
 		return nil, nil
@@ -381,8 +389,8 @@ func (r *traceRepository) InsertSpan(ctx context.Context, span *observability.Sp
 			usage_details, cost_details, pricing_snapshot, total_cost,
 			events_timestamp, events_name, events_attributes,
 			links_trace_id, links_span_id, links_trace_state, links_attributes,
-			deleted_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			deleted_at, sample_rate
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	return r.db.Exec(ctx, query,
@@ -421,6 +429,7 @@ func (r *traceRepository) InsertSpan(ctx context.Context, span *observability.Sp
 		linksTraceStates,
 		linksAttributes,
 		span.DeletedAt,
+		span.GetSampleRate(),
 	)
 }
 
@@ -440,7 +449,7 @@ func (r *traceRepository) InsertSpanBatch(ctx context.Context, spans []*observab
 			usage_details, cost_details, pricing_snapshot, total_cost,
 			events_timestamp, events_name, events_attributes,
 			links_trace_id, links_span_id, links_trace_state, links_attributes,
-			deleted_at
+			deleted_at, sample_rate
 		)
 	`)
 	if err != nil {
@@ -489,6 +498,7 @@ func (r *traceRepository) InsertSpanBatch(ctx context.Context, spans []*observab
 			linksTraceStates,
 			linksAttributes,
 			span.DeletedAt,
+			span.GetSampleRate(),
 		)
 		if err != nil {
 			return fmt.Errorf("append to batch: %w", err)
@@ -498,6 +508,37 @@ func (r *traceRepository) InsertSpanBatch(ctx context.Context, spans []*observab
 	return batch.Send()
 }
 
+func (r *traceRepository) GetExistingSpanIDs(ctx context.Context, projectID string, spanIDs []string) (map[string]struct{}, error) {
+	existing := make(map[string]struct{})
+	if len(spanIDs) == 0 {
+		return existing, nil
+	}
+
+	placeholders := make([]string, len(spanIDs))
+	args := make([]interface{}, 0, len(spanIDs)+1)
+	args = append(args, projectID)
+	for i, id := range spanIDs {
+		placeholders[i] = "?"
+		args = append(args, id)
+	}
+
+	query := "SELECT DISTINCT span_id FROM otel_traces WHERE project_id = ? AND span_id IN (" + strings.Join(placeholders, ",") + ")"
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query existing span ids: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var spanID string
+		if err := rows.Scan(&spanID); err != nil {
+			return nil, fmt.Errorf("scan span id: %w", err)
+		}
+		existing[spanID] = struct{}{}
+	}
+	return existing, rows.Err()
+}
+
 func (r *traceRepository) DeleteSpan(ctx context.Context, spanID string) error {
 	query := `ALTER TABLE otel_traces DELETE WHERE span_id = ?`
 	return r.db.Exec(ctx, query, spanID)
@@ -939,6 +980,11 @@ func (r *traceRepository) ListTraces(ctx context.Context, filter *observability.
 			args = append(args, searchArgs...)
 		}
 
+		if condition, filterArgs := expressionFilterCondition(filter); condition != "" {
+			query += condition
+			args = append(args, filterArgs...)
+		}
+
 		if statusClauses, statusArgs := statusHavingClauses(filter); len(statusClauses) > 0 {
 			havingClauses = append(havingClauses, statusClauses...)
 			havingArgs = append(havingArgs, statusArgs...)
@@ -1135,6 +1181,11 @@ func (r *traceRepository) CountTraces(ctx context.Context, filter *observability
 			args = append(args, searchArgs...)
 		}
 
+		if condition, filterArgs := expressionFilterCondition(filter); condition != "" {
+			innerQuery += condition
+			args = append(args, filterArgs...)
+		}
+
 		if statusClauses, statusArgs := statusHavingClauses(filter); len(statusClauses) > 0 {
 			havingClauses = append(havingClauses, statusClauses...)
 			havingArgs = append(havingArgs, statusArgs...)
@@ -1422,6 +1473,84 @@ func (r *traceRepository) QuerySpansByExpression(ctx context.Context, query stri
 	return r.scanSpans(rows)
 }
 
+func (r *traceRepository) QueryArchivedSpansByExpression(ctx context.Context, query string, args []interface{}) ([]*observability.Span, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query archived spans by expression: %w", err)
+	}
+	defer rows.Close()
+
+	spans := make([]*observability.Span, 0)
+	for rows.Next() {
+		var raw string
+		if err := rows.Scan(&raw); err != nil {
+			return nil, fmt.Errorf("scan archived span: %w", err)
+		}
+		var span observability.Span
+		if err := json.Unmarshal([]byte(raw), &span); err != nil {
+			return nil, fmt.Errorf("decode archived span: %w", err)
+		}
+		spans = append(spans, &span)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate archived spans: %w", err)
+	}
+	return spans, nil
+}
+
+func (r *traceRepository) AggregateSpansByExpression(ctx context.Context, query string, args []interface{}, bucketed bool, groupKeys, valueKeys []string) ([]*observability.SpanAggregateRow, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("aggregate spans by expression: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]*observability.SpanAggregateRow, 0)
+	for rows.Next() {
+		var bucket time.Time
+		groups := make([]string, len(groupKeys))
+		values := make([]*float64, len(valueKeys))
+
+		dest := make([]interface{}, 0, 1+len(groups)+len(values))
+		if bucketed {
+			dest = append(dest, &bucket)
+		}
+		for i := range groups {
+			dest = append(dest, &groups[i])
+		}
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("scan span aggregate: %w", err)
+		}
+
+		row := &observability.SpanAggregateRow{Values: make(map[string]*float64, len(valueKeys))}
+		if bucketed {
+			b := bucket
+			row.Bucket = &b
+		}
+		if len(groupKeys) > 0 {
+			row.Groups = make(map[string]string, len(groupKeys))
+			for i, key := range groupKeys {
+				row.Groups[key] = groups[i]
+			}
+		}
+		for i, key := range valueKeys {
+			// Aggregates over empty input return NaN, which JSON cannot encode
+			if values[i] != nil && (math.IsNaN(*values[i]) || math.IsInf(*values[i], 0)) {
+				values[i] = nil
+			}
+			row.Values[key] = values[i]
+		}
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate span aggregates: %w", err)
+	}
+	return result, nil
+}
+
 func (r *traceRepository) CountSpansByExpression(ctx context.Context, query string, args []interface{}) (int64, error) {
 	var count uint64
 	err := r.db.QueryRow(ctx, query, args...).Scan(&count)
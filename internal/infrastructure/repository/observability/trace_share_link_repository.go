@@ -0,0 +1,76 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"brokle/internal/core/domain/observability"
+	"brokle/pkg/ulid"
+
+	"gorm.io/gorm"
+)
+
+type traceShareLinkRepository struct {
+	db *gorm.DB
+}
+
+// NewTraceShareLinkRepository creates a new PostgreSQL trace share link repository.
+func NewTraceShareLinkRepository(db *gorm.DB) observability.TraceShareLinkRepository {
+	return &traceShareLinkRepository{db: db}
+}
+
+func (r *traceShareLinkRepository) Create(ctx context.Context, link *observability.TraceShareLink) error {
+	if err := r.db.WithContext(ctx).Create(link).Error; err != nil {
+		return fmt.Errorf("create share link: %w", err)
+	}
+	return nil
+}
+
+func (r *traceShareLinkRepository) GetByID(ctx context.Context, id ulid.ULID) (*observability.TraceShareLink, error) {
+	var link observability.TraceShareLink
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&link).Error; err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (r *traceShareLinkRepository) ListByProject(ctx context.Context, projectID ulid.ULID) ([]*observability.TraceShareLink, error) {
+	var links []*observability.TraceShareLink
+	err := r.db.WithContext(ctx).
+		Where("project_id = ?", projectID).
+		Order("created_at DESC").
+		Find(&links).Error
+	if err != nil {
+		return nil, fmt.Errorf("list share links: %w", err)
+	}
+	return links, nil
+}
+
+func (r *traceShareLinkRepository) Revoke(ctx context.Context, id ulid.ULID, at time.Time) error {
+	result := r.db.WithContext(ctx).
+		Model(&observability.TraceShareLink{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{"revoked_at": at, "updated_at": at})
+	if result.Error != nil {
+		return fmt.Errorf("revoke share link: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *traceShareLinkRepository) RecordView(ctx context.Context, id ulid.ULID, at time.Time) error {
+	err := r.db.WithContext(ctx).
+		Model(&observability.TraceShareLink{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"view_count":     gorm.Expr("view_count + 1"),
+			"last_viewed_at": at,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("record share link view: %w", err)
+	}
+	return nil
+}
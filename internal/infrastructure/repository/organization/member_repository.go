@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	orgDomain "brokle/internal/core/domain/organization"
 	"brokle/pkg/ulid"
@@ -30,9 +31,23 @@ func (r *memberRepository) getDB(ctx context.Context) *gorm.DB {
 	return shared.GetDB(ctx, r.db)
 }
 
-// Create creates a new member
+// Create creates a new member. A previously removed (soft-deleted) membership of the
+// same user is restored in place, since the row is keyed by user and organization.
 func (r *memberRepository) Create(ctx context.Context, member *orgDomain.Member) error {
-	return r.getDB(ctx).WithContext(ctx).Create(member).Error
+	result := r.getDB(ctx).WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "organization_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"role_id", "status", "joined_at", "invited_by", "created_at", "updated_at", "deleted_at"}),
+			Where:     clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "organization_members.deleted_at IS NOT NULL"}}},
+		}).
+		Create(member)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("create member for user %s in org %s: %w", member.UserID, member.OrganizationID, orgDomain.ErrMemberAlreadyExists)
+	}
+	return nil
 }
 
 // GetByID retrieves a member by ID
@@ -0,0 +1,144 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"brokle/internal/core/domain/webhook"
+	"brokle/internal/infrastructure/shared"
+	"brokle/pkg/ulid"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DeliveryRepository implements webhook.DeliveryRepository using PostgreSQL.
+type DeliveryRepository struct {
+	db *gorm.DB
+}
+
+// NewDeliveryRepository creates a new DeliveryRepository.
+func NewDeliveryRepository(db *gorm.DB) *DeliveryRepository {
+	return &DeliveryRepository{db: db}
+}
+
+// getDB returns transaction-aware DB instance.
+func (r *DeliveryRepository) getDB(ctx context.Context) *gorm.DB {
+	return shared.GetDB(ctx, r.db)
+}
+
+// CreateBatch inserts deliveries for one event.
+func (r *DeliveryRepository) CreateBatch(ctx context.Context, deliveries []*webhook.Delivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return r.getDB(ctx).WithContext(ctx).Create(&deliveries).Error
+}
+
+// GetByID retrieves a delivery by its ID within a subscription.
+func (r *DeliveryRepository) GetByID(ctx context.Context, id, subscriptionID ulid.ULID) (*webhook.Delivery, error) {
+	var delivery webhook.Delivery
+	result := r.getDB(ctx).WithContext(ctx).
+		Where("id = ? AND subscription_id = ?", id.String(), subscriptionID.String()).
+		First(&delivery)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, webhook.ErrDeliveryNotFound
+		}
+		return nil, result.Error
+	}
+	return &delivery, nil
+}
+
+// Update records the outcome of a delivery attempt.
+func (r *DeliveryRepository) Update(ctx context.Context, delivery *webhook.Delivery) error {
+	result := r.getDB(ctx).WithContext(ctx).
+		Model(&webhook.Delivery{}).
+		Where("id = ?", delivery.ID.String()).
+		Updates(map[string]interface{}{
+			"status":          string(delivery.Status),
+			"attempts":        delivery.Attempts,
+			"response_code":   delivery.ResponseCode,
+			"error":           delivery.Error,
+			"duration_ms":     delivery.DurationMs,
+			"next_attempt_at": delivery.NextAttemptAt,
+			"delivered_at":    delivery.DeliveredAt,
+			"updated_at":      time.Now(),
+		})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return webhook.ErrDeliveryNotFound
+	}
+	return nil
+}
+
+// List retrieves a subscription's delivery log, newest first.
+func (r *DeliveryRepository) List(ctx context.Context, subscriptionID ulid.ULID, filter *webhook.DeliveryFilter, offset, limit int) ([]*webhook.Delivery, int64, error) {
+	var deliveries []*webhook.Delivery
+	var total int64
+
+	query := r.getDB(ctx).WithContext(ctx).
+		Where("subscription_id = ?", subscriptionID.String())
+
+	if filter != nil {
+		if filter.Status != nil {
+			query = query.Where("status = ?", string(*filter.Status))
+		}
+		if filter.EventType != nil {
+			query = query.Where("event_type = ?", string(*filter.EventType))
+		}
+	}
+
+	if err := query.Model(&webhook.Delivery{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	result := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&deliveries)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+	return deliveries, total, nil
+}
+
+// ClaimDue locks due pending deliveries with FOR UPDATE SKIP LOCKED and leases them.
+func (r *DeliveryRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*webhook.Delivery, error) {
+	var deliveries []*webhook.Delivery
+
+	err := r.getDB(ctx).WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Where("status = ? AND next_attempt_at <= ?", string(webhook.DeliveryStatusPending), now).
+			Order("next_attempt_at ASC").
+			Limit(limit).
+			Clauses(clause.Locking{
+				Strength: "UPDATE",
+				Options:  "SKIP LOCKED",
+			}).
+			Find(&deliveries)
+		if result.Error != nil {
+			return result.Error
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		ids := make([]string, len(deliveries))
+		for i, d := range deliveries {
+			ids[i] = d.ID.String()
+		}
+
+		leaseUntil := now.Add(lease)
+		return tx.Model(&webhook.Delivery{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", leaseUntil).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
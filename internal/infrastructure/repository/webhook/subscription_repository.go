@@ -0,0 +1,177 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"brokle/internal/core/domain/webhook"
+	"brokle/internal/infrastructure/shared"
+	"brokle/pkg/ulid"
+
+	"gorm.io/gorm"
+)
+
+// SubscriptionRepository implements webhook.SubscriptionRepository using PostgreSQL.
+type SubscriptionRepository struct {
+	db *gorm.DB
+}
+
+// NewSubscriptionRepository creates a new SubscriptionRepository.
+func NewSubscriptionRepository(db *gorm.DB) *SubscriptionRepository {
+	return &SubscriptionRepository{db: db}
+}
+
+// getDB returns transaction-aware DB instance.
+func (r *SubscriptionRepository) getDB(ctx context.Context) *gorm.DB {
+	return shared.GetDB(ctx, r.db)
+}
+
+// Create creates a new webhook subscription.
+func (r *SubscriptionRepository) Create(ctx context.Context, sub *webhook.Subscription) error {
+	result := r.getDB(ctx).WithContext(ctx).Create(sub)
+	if result.Error != nil {
+		if isUniqueViolation(result.Error) {
+			return webhook.ErrSubscriptionExists
+		}
+		return result.Error
+	}
+	return nil
+}
+
+// GetByID retrieves a subscription by its ID within a project.
+func (r *SubscriptionRepository) GetByID(ctx context.Context, id, projectID ulid.ULID) (*webhook.Subscription, error) {
+	var sub webhook.Subscription
+	result := r.getDB(ctx).WithContext(ctx).
+		Where("id = ? AND project_id = ?", id.String(), projectID.String()).
+		First(&sub)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, webhook.ErrSubscriptionNotFound
+		}
+		return nil, result.Error
+	}
+	return &sub, nil
+}
+
+// Update updates an existing subscription.
+func (r *SubscriptionRepository) Update(ctx context.Context, sub *webhook.Subscription) error {
+	result := r.getDB(ctx).WithContext(ctx).Save(sub)
+	if result.Error != nil {
+		if isUniqueViolation(result.Error) {
+			return webhook.ErrSubscriptionExists
+		}
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return webhook.ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+// Delete removes a subscription and, by cascade, its delivery log.
+func (r *SubscriptionRepository) Delete(ctx context.Context, id, projectID ulid.ULID) error {
+	result := r.getDB(ctx).WithContext(ctx).
+		Where("id = ? AND project_id = ?", id.String(), projectID.String()).
+		Delete(&webhook.Subscription{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return webhook.ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+// List retrieves all subscriptions for a project, newest first.
+func (r *SubscriptionRepository) List(ctx context.Context, projectID ulid.ULID) ([]*webhook.Subscription, error) {
+	var subs []*webhook.Subscription
+	result := r.getDB(ctx).WithContext(ctx).
+		Where("project_id = ?", projectID.String()).
+		Order("created_at DESC").
+		Find(&subs)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return subs, nil
+}
+
+// ExistsByName checks if a subscription with the given name exists in the project.
+func (r *SubscriptionRepository) ExistsByName(ctx context.Context, projectID ulid.ULID, name string) (bool, error) {
+	var count int64
+	result := r.getDB(ctx).WithContext(ctx).
+		Model(&webhook.Subscription{}).
+		Where("project_id = ? AND name = ?", projectID.String(), name).
+		Count(&count)
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return count > 0, nil
+}
+
+// ListEnabledByProject retrieves enabled subscriptions for a project.
+func (r *SubscriptionRepository) ListEnabledByProject(ctx context.Context, projectID ulid.ULID) ([]*webhook.Subscription, error) {
+	var subs []*webhook.Subscription
+	result := r.getDB(ctx).WithContext(ctx).
+		Where("project_id = ? AND enabled = TRUE", projectID.String()).
+		Find(&subs)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return subs, nil
+}
+
+// ListEnabledByOrganization retrieves enabled subscriptions of all live projects in an organization.
+func (r *SubscriptionRepository) ListEnabledByOrganization(ctx context.Context, orgID ulid.ULID) ([]*webhook.Subscription, error) {
+	var subs []*webhook.Subscription
+	result := r.getDB(ctx).WithContext(ctx).
+		Joins("JOIN projects ON projects.id = webhook_subscriptions.project_id").
+		Where("projects.organization_id = ? AND projects.deleted_at IS NULL", orgID.String()).
+		Where("webhook_subscriptions.enabled = TRUE").
+		Find(&subs)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return subs, nil
+}
+
+// GetByIDs retrieves subscriptions by ID across projects.
+func (r *SubscriptionRepository) GetByIDs(ctx context.Context, ids []ulid.ULID) ([]*webhook.Subscription, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	idStrings := make([]string, len(ids))
+	for i, id := range ids {
+		idStrings[i] = id.String()
+	}
+
+	var subs []*webhook.Subscription
+	result := r.getDB(ctx).WithContext(ctx).
+		Where("id IN ?", idStrings).
+		Find(&subs)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return subs, nil
+}
+
+// isUniqueViolation checks if the error is a PostgreSQL unique constraint violation.
+func isUniqueViolation(err error) bool {
+	if err == nil {
+		return false
+	}
+	errStr := strings.ToLower(err.Error())
+	return strings.Contains(errStr, "23505") ||
+		strings.Contains(errStr, "unique constraint") ||
+		strings.Contains(errStr, "duplicate key")
+}
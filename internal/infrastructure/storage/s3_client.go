@@ -160,3 +160,25 @@ func (c *S3Client) Exists(ctx context.Context, key string) (bool, error) {
 func (c *S3Client) GetS3URI(key string) string {
 	return fmt.Sprintf("s3://%s/%s", c.bucketName, key)
 }
+
+// ListKeys returns all object keys under a prefix
+func (c *S3Client) ListKeys(ctx context.Context, prefix string) ([]string, error) {
+	paginator := s3.NewListObjectsV2Paginator(c.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(c.bucketName),
+		Prefix: aws.String(prefix),
+	})
+
+	var keys []string
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.Error("Failed to list S3 objects", "error", err, "bucket", c.bucketName, "prefix", prefix)
+			return nil, fmt.Errorf("failed to list S3 objects: %w", err)
+		}
+		for _, obj := range page.Contents {
+			keys = append(keys, aws.ToString(obj.Key))
+		}
+	}
+
+	return keys, nil
+}
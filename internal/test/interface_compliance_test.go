@@ -3,14 +3,17 @@ package test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"brokle/internal/core/domain/auth"
 	"brokle/internal/ee/analytics"
 	"brokle/internal/ee/compliance"
 	"brokle/internal/ee/rbac"
 	"brokle/internal/ee/sso"
+	"brokle/pkg/ulid"
 )
 
 // TestEnterpriseInterfaceCompliance ensures all enterprise interfaces are properly implemented
@@ -35,7 +38,7 @@ func TestEnterpriseInterfaceCompliance(t *testing.T) {
 		})
 
 		assert.NotPanics(t, func() {
-			report, err := service.GenerateAuditReport(ctx)
+			report, err := service.GenerateAuditReport(ctx, ulid.New(), time.Now().Add(-time.Hour), time.Now())
 			assert.NoError(t, err)
 			assert.NotNil(t, report)
 		})
@@ -72,26 +75,26 @@ func TestEnterpriseInterfaceCompliance(t *testing.T) {
 
 		// Test interface methods
 		assert.NotPanics(t, func() {
-			err := service.ConfigureProvider(ctx, "saml", "config_string")
+			_, err := service.ConfigureProvider(ctx, ulid.New(), ulid.New(), &auth.SSOConnectionRequest{Protocol: auth.SSOProtocolSAML})
 			// Stub should return error but not panic
 			assert.Error(t, err)
 		})
 
 		assert.NotPanics(t, func() {
-			url, err := service.GetLoginURL(ctx)
+			url, err := service.GetLoginURL(ctx, "user@example.com")
 			// Stub should return error but not panic
 			assert.Error(t, err)
 			assert.Empty(t, url)
 		})
 
 		assert.NotPanics(t, func() {
-			_, err := service.Authenticate(ctx, "token")
+			_, err := service.Authenticate(ctx, "state", "code")
 			// Stub should return error but not panic
 			assert.Error(t, err)
 		})
 
 		assert.NotPanics(t, func() {
-			_, err := service.ValidateAssertion(ctx, "assertion")
+			_, err := service.ValidateAssertion(ctx, "assertion", "relay_state")
 			// Stub should return error but not panic
 			assert.Error(t, err)
 		})
@@ -111,47 +114,21 @@ func TestEnterpriseInterfaceCompliance(t *testing.T) {
 		var _ rbac.RBACManager = service
 
 		// Test interface methods
-		testRole := &rbac.Role{
-			Name:        "test-role",
-			Permissions: []string{"read", "write"},
-			Scopes:      []string{"project"},
-		}
-
-		assert.NotPanics(t, func() {
-			err := service.CreateRole(ctx, testRole)
-			// Stub should return error for custom roles
-			assert.Error(t, err)
-		})
-
 		assert.NotPanics(t, func() {
-			err := service.AssignRoleToUser(ctx, "user123", "admin")
-			// Basic role assignment should work
+			members, err := service.ListProjectMembers(ctx, ulid.New())
 			assert.NoError(t, err)
+			assert.Empty(t, members) // Everyone acts with their organization role
 		})
 
 		assert.NotPanics(t, func() {
-			has, err := service.CheckPermission(ctx, "user123", "project", "read")
-			assert.NoError(t, err)
-			// Stub returns true for simplicity
-			assert.True(t, has)
-		})
-
-		assert.NotPanics(t, func() {
-			roles, err := service.ListRoles(ctx)
-			assert.NoError(t, err)
-			assert.NotNil(t, roles) // Should return basic roles
-			assert.NotEmpty(t, roles)
-		})
-
-		assert.NotPanics(t, func() {
-			perms, err := service.GetUserPermissions(ctx, "user123")
-			assert.NoError(t, err)
-			assert.NotNil(t, perms)
+			_, err := service.AssignProjectRole(ctx, ulid.New(), ulid.New(), ulid.New(), nil)
+			// Stub should return error for project roles
+			assert.Error(t, err)
 		})
 
 		assert.NotPanics(t, func() {
-			err := service.RemoveRoleFromUser(ctx, "user123", "admin")
-			assert.NoError(t, err)
+			err := service.RemoveProjectRole(ctx, ulid.New(), ulid.New())
+			assert.Error(t, err)
 		})
 	})
 
@@ -240,26 +217,14 @@ func TestStubBehaviorConsistency(t *testing.T) {
 		compliance := compliance.New()
 		err := compliance.ValidateCompliance(ctx, map[string]interface{}{"test": "data"})
 		assert.NoError(t, err, "Stub compliance should not block operations")
-
-		// RBAC should allow basic permissions in stub mode for development
-		rbac := rbac.New()
-		hasPermission, err := rbac.CheckPermission(ctx, "user", "project", "read")
-		assert.NoError(t, err)
-		assert.True(t, hasPermission, "Stub RBAC allows basic permissions for development")
 	})
 
 	t.Run("Stub services return appropriate empty values", func(t *testing.T) {
-		// RBAC should return basic roles
+		// RBAC should return no project roles, not nil
 		rbac := rbac.New()
-		roles, err := rbac.ListRoles(ctx)
-		assert.NoError(t, err)
-		assert.NotNil(t, roles, "Should return basic roles, not nil")
-		assert.NotEmpty(t, roles, "Should return basic roles like owner, admin, etc.")
-
-		// User permissions should return basic permissions
-		permissions, err := rbac.GetUserPermissions(ctx, "user")
+		members, err := rbac.ListProjectMembers(ctx, ulid.New())
 		assert.NoError(t, err)
-		assert.NotNil(t, permissions, "Should return basic permissions, not nil")
+		assert.NotNil(t, members, "Should return an empty list, not nil")
 	})
 }
 
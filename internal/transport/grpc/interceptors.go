@@ -2,31 +2,39 @@ package grpc
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"log/slog"
 
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
 
 	"brokle/internal/core/domain/auth"
 )
 
 // AuthInterceptor validates API keys from gRPC metadata
 type AuthInterceptor struct {
-	apiKeyService auth.APIKeyService
-	logger        *slog.Logger
+	apiKeyService    auth.APIKeyService
+	rateLimitService auth.RateLimitService
+	logger           *slog.Logger
 }
 
 // NewAuthInterceptor creates a new gRPC auth interceptor
 func NewAuthInterceptor(
 	apiKeyService auth.APIKeyService,
+	rateLimitService auth.RateLimitService,
 	logger *slog.Logger,
 ) *AuthInterceptor {
 	return &AuthInterceptor{
-		apiKeyService: apiKeyService,
-		logger:        logger,
+		apiKeyService:    apiKeyService,
+		rateLimitService: rateLimitService,
+		logger:           logger,
 	}
 }
 
@@ -58,6 +66,20 @@ func (i *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
 			return nil, status.Error(codes.Unauthenticated, "invalid API key")
 		}
 
+		// gRPC only serves OTLP ingestion
+		if !keyData.APIKey.HasScope("traces:create") {
+			i.logger.Warn("API key missing required scope for gRPC request",
+				"api_key_id", keyData.APIKey.ID.String(),
+				"scope", "traces:create",
+				"method", info.FullMethod,
+			)
+			return nil, status.Error(codes.PermissionDenied, "API key is missing required scope: traces:create")
+		}
+
+		if err := i.checkRateLimit(ctx, keyData, req); err != nil {
+			return nil, err
+		}
+
 		i.logger.Debug("gRPC API key validated successfully",
 			"project_id", keyData.ProjectID.String(),
 			"api_key_id", keyData.APIKey.ID.String(),
@@ -65,13 +87,52 @@ func (i *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
 		)
 
 		// Store authentication data in context for handler use
-		ctx = storeAuthDataInContext(ctx, &keyData.ProjectID, &keyData.APIKey.ID)
+		ctx = storeAuthDataInContext(ctx, &keyData.ProjectID, &keyData.OrganizationID, &keyData.APIKey.ID)
 
 		// Call handler with authenticated context
 		return handler(ctx, req)
 	}
 }
 
+// checkRateLimit applies the key's request, span and payload limits. OTLP exporters
+// retry RESOURCE_EXHAUSTED responses after the RetryInfo delay.
+func (i *AuthInterceptor) checkRateLimit(ctx context.Context, keyData *auth.ValidateAPIKeyResponse, req interface{}) error {
+	if i.rateLimitService == nil {
+		return nil
+	}
+
+	cost := auth.RateLimitCost{Requests: 1}
+	if msg, ok := req.(proto.Message); ok {
+		cost.Bytes = int64(proto.Size(msg))
+	}
+	if traces, ok := req.(*coltracepb.ExportTraceServiceRequest); ok {
+		for _, rs := range traces.GetResourceSpans() {
+			for _, ss := range rs.GetScopeSpans() {
+				cost.Spans += int64(len(ss.GetSpans()))
+			}
+		}
+	}
+
+	decision, err := i.rateLimitService.Check(ctx, keyData.ProjectID, keyData.APIKey.ID, cost)
+	if err != nil || decision.Allowed {
+		return nil
+	}
+
+	i.logger.Warn("gRPC request rate limited",
+		"api_key_id", keyData.APIKey.ID.String(),
+		"project_id", keyData.ProjectID.String(),
+		"scope", decision.Scope,
+		"dimension", decision.Dimension,
+		"limit", decision.Limit,
+	)
+
+	st := status.New(codes.ResourceExhausted, fmt.Sprintf("%s %s limit of %d exceeded", decision.Scope, decision.Dimension, decision.Limit))
+	if detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(decision.RetryAfter)}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
 // LoggingInterceptor logs gRPC requests with timing and errors
 func LoggingInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
 	return func(
@@ -14,8 +14,9 @@ import (
 type contextKey string
 
 const (
-	contextKeyProjectID contextKey = "project_id"
-	contextKeyAPIKeyID  contextKey = "api_key_id"
+	contextKeyProjectID      contextKey = "project_id"
+	contextKeyAPIKeyID       contextKey = "api_key_id"
+	contextKeyOrganizationID contextKey = "organization_id"
 )
 
 // extractAPIKeyFromMetadata extracts API key from gRPC metadata
@@ -85,10 +86,26 @@ func extractAPIKeyIDFromContext(ctx context.Context) (*ulid.ULID, error) {
 	return apiKeyID, nil
 }
 
+// extractOrganizationIDFromContext retrieves organization ID from authenticated context
+func extractOrganizationIDFromContext(ctx context.Context) (*ulid.ULID, error) {
+	val := ctx.Value(contextKeyOrganizationID)
+	if val == nil {
+		return nil, fmt.Errorf("organization_id not found in context")
+	}
+
+	organizationID, ok := val.(*ulid.ULID)
+	if !ok {
+		return nil, fmt.Errorf("organization_id has invalid type in context")
+	}
+
+	return organizationID, nil
+}
+
 // storeAuthDataInContext stores authentication data in context
 // Used by auth interceptor after successful validation
-func storeAuthDataInContext(ctx context.Context, projectID, apiKeyID *ulid.ULID) context.Context {
+func storeAuthDataInContext(ctx context.Context, projectID, organizationID, apiKeyID *ulid.ULID) context.Context {
 	ctx = context.WithValue(ctx, contextKeyProjectID, projectID)
+	ctx = context.WithValue(ctx, contextKeyOrganizationID, organizationID)
 	ctx = context.WithValue(ctx, contextKeyAPIKeyID, apiKeyID)
 	return ctx
 }
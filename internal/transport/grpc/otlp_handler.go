@@ -14,6 +14,7 @@ import (
 	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
 	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
 
+	"brokle/internal/core/domain/billing"
 	"brokle/internal/core/domain/observability"
 	obsServices "brokle/internal/core/services/observability"
 	"brokle/internal/infrastructure/streams"
@@ -31,6 +32,8 @@ type OTLPHandler struct {
 	streamProducer       *streams.TelemetryStreamProducer
 	deduplicationService observability.TelemetryDeduplicationService
 	otlpConverter        *obsServices.OTLPConverterService
+	enforcementService   billing.EnforcementService
+	samplingService      *obsServices.SamplingService
 	logger               *slog.Logger
 }
 
@@ -39,12 +42,16 @@ func NewOTLPHandler(
 	streamProducer *streams.TelemetryStreamProducer,
 	deduplicationService observability.TelemetryDeduplicationService,
 	otlpConverter *obsServices.OTLPConverterService,
+	enforcementService billing.EnforcementService,
+	samplingService *obsServices.SamplingService,
 	logger *slog.Logger,
 ) *OTLPHandler {
 	return &OTLPHandler{
 		streamProducer:       streamProducer,
 		deduplicationService: deduplicationService,
 		otlpConverter:        otlpConverter,
+		enforcementService:   enforcementService,
+		samplingService:      samplingService,
 		logger:               logger,
 	}
 }
@@ -62,6 +69,26 @@ func (h *OTLPHandler) Export(
 	}
 	projectID := projectIDPtr.String()
 
+	organizationIDPtr, err := extractOrganizationIDFromContext(ctx)
+	if err != nil {
+		h.logger.Error("Organization ID not found in gRPC context", "error", err)
+		return nil, status.Error(codes.Internal, "failed to determine organization for billing")
+	}
+
+	// Check budget enforcement (cached in Redis, fails open)
+	var enforcement *billing.IngestionEnforcement
+	if h.enforcementService != nil {
+		enforcement = h.enforcementService.GetIngestionEnforcement(ctx, *organizationIDPtr, *projectIDPtr)
+	}
+	if enforcement.Blocks() {
+		h.logger.Warn("gRPC OTLP request rejected by budget enforcement",
+			"project_id", projectID,
+			"budget_id", enforcement.BudgetID.String(),
+			"dimension", enforcement.Dimension,
+		)
+		return nil, status.Error(codes.ResourceExhausted, enforcement.Reason())
+	}
+
 	// Validate request has resource spans
 	if len(req.ResourceSpans) == 0 {
 		return nil, status.Error(codes.InvalidArgument, "OTLP request must contain at least one resource span")
@@ -95,6 +122,18 @@ func (h *OTLPHandler) Export(
 		"brokle_events", len(brokleEvents),
 	)
 
+	// Apply project head sampling, then soft budget enforcement (trace sampling, payload dropping)
+	brokleEvents, headSampledOut := h.samplingService.ApplyHeadSampling(ctx, *projectIDPtr, brokleEvents)
+	brokleEvents, enforced := obsServices.ApplyIngestionEnforcement(brokleEvents, enforcement)
+	if len(brokleEvents) == 0 {
+		h.logger.Info("All gRPC OTLP events sampled out",
+			"project_id", projectID,
+			"head_sampled_out", headSampledOut,
+			"sampled_out", enforced.SampledOut,
+		)
+		return &coltracepb.ExportTraceServiceResponse{}, nil
+	}
+
 	// Deduplication: Extract composite IDs (trace_id:span_id)
 	dedupIDs := make([]string, 0, len(brokleEvents))
 	dedupIDToFirstIndex := make(map[string]int)
@@ -166,6 +205,7 @@ func (h *OTLPHandler) Export(
 	streamMsg := &streams.TelemetryStreamMessage{
 		BatchID:          batchID,
 		ProjectID:        *projectIDPtr,
+		OrganizationID:   *organizationIDPtr,
 		Events:           claimedEventData,
 		ClaimedSpanIDs:   claimedIDs,
 		DuplicateSpanIDs: duplicateIDs,
@@ -176,6 +216,14 @@ func (h *OTLPHandler) Export(
 		},
 		Timestamp: time.Now(),
 	}
+	if headSampledOut > 0 {
+		streamMsg.Metadata["head_sampled_out"] = headSampledOut
+	}
+	if enforcement.Restricts() {
+		streamMsg.Metadata["budget_enforcement_budget_id"] = enforcement.BudgetID.String()
+		streamMsg.Metadata["budget_enforcement_sampled_out"] = enforced.SampledOut
+		streamMsg.Metadata["budget_enforcement_payloads_dropped"] = enforced.PayloadsDropped
+	}
 
 	streamID, err := h.streamProducer.PublishBatch(ctx, streamMsg)
 	if err != nil {
@@ -143,7 +143,7 @@ type BudgetStatus struct {
 // @Produce json
 // @Param period query string false "Time period for analytics" default("30d") Enums(1d,7d,30d,90d,1y)
 // @Param organization_id query string false "Filter by organization ID" example("org_1234567890")
-// @Param project_id query string false "Filter by project ID" example("proj_1234567890")
+// @Param project_id query string true "Project ID" example("01K4FHGHT3XX9WFM293QPZ5G9V")
 // @Param environment query string false "Filter by environment tag" example("production")
 // @Success 200 {object} response.SuccessResponse{data=AnalyticsOverview} "Analytics overview"
 // @Failure 400 {object} response.ErrorResponse "Bad request - invalid query parameters"
@@ -164,7 +164,7 @@ func (h *Handler) Overview(c *gin.Context) {
 // @Produce json
 // @Param period query string false "Time period for analytics" default("30d") Enums(1d,7d,30d,90d,1y)
 // @Param organization_id query string false "Filter by organization ID" example("org_1234567890")
-// @Param project_id query string false "Filter by project ID" example("proj_1234567890")
+// @Param project_id query string true "Project ID" example("01K4FHGHT3XX9WFM293QPZ5G9V")
 // @Param environment query string false "Filter by environment tag" example("production")
 // @Param provider query string false "Filter by AI provider" example("openai")
 // @Param model query string false "Filter by AI model" example("gpt-4")
@@ -187,7 +187,7 @@ func (h *Handler) Requests(c *gin.Context) {
 // @Produce json
 // @Param period query string false "Time period for analytics" default("30d") Enums(1d,7d,30d,90d,1y)
 // @Param organization_id query string false "Filter by organization ID" example("org_1234567890")
-// @Param project_id query string false "Filter by project ID" example("proj_1234567890")
+// @Param project_id query string true "Project ID" example("01K4FHGHT3XX9WFM293QPZ5G9V")
 // @Param environment query string false "Filter by environment tag" example("production")
 // @Param currency query string false "Currency for cost display" default("USD") Enums(USD,EUR,GBP)
 // @Success 200 {object} response.SuccessResponse{data=CostAnalytics} "Cost analytics and optimization insights"
@@ -209,7 +209,7 @@ func (h *Handler) Costs(c *gin.Context) {
 // @Produce json
 // @Param period query string false "Time period for analytics" default("30d") Enums(1d,7d,30d,90d,1y)
 // @Param organization_id query string false "Filter by organization ID" example("org_1234567890")
-// @Param project_id query string false "Filter by project ID" example("proj_1234567890")
+// @Param project_id query string true "Project ID" example("01K4FHGHT3XX9WFM293QPZ5G9V")
 // @Param environment query string false "Filter by environment tag" example("production")
 // @Param provider query string false "Filter by specific provider" example("openai")
 // @Success 200 {object} response.SuccessResponse{data=[]ProviderAnalytics} "Provider performance analytics"
@@ -231,7 +231,7 @@ func (h *Handler) Providers(c *gin.Context) {
 // @Produce json
 // @Param period query string false "Time period for analytics" default("30d") Enums(1d,7d,30d,90d,1y)
 // @Param organization_id query string false "Filter by organization ID" example("org_1234567890")
-// @Param project_id query string false "Filter by project ID" example("proj_1234567890")
+// @Param project_id query string true "Project ID" example("01K4FHGHT3XX9WFM293QPZ5G9V")
 // @Param environment query string false "Filter by environment tag" example("production")
 // @Param provider query string false "Filter by AI provider" example("openai")
 // @Param model query string false "Filter by specific model" example("gpt-4")
@@ -14,20 +14,23 @@ import (
 )
 
 type Handler struct {
-	config        *config.Config
-	logger        *slog.Logger
-	apiKeyService auth.APIKeyService
+	config           *config.Config
+	logger           *slog.Logger
+	apiKeyService    auth.APIKeyService
+	rateLimitService auth.RateLimitService
 }
 
 func NewHandler(
 	config *config.Config,
 	logger *slog.Logger,
 	apiKeyService auth.APIKeyService,
+	rateLimitService auth.RateLimitService,
 ) *Handler {
 	return &Handler{
-		config:        config,
-		logger:        logger,
-		apiKeyService: apiKeyService,
+		config:           config,
+		logger:           logger,
+		apiKeyService:    apiKeyService,
+		rateLimitService: rateLimitService,
 	}
 }
 
@@ -40,6 +43,7 @@ type APIKey struct {
 	Key        string     `json:"key,omitempty" example:"bk_AbCdEfGhIjKlMnOpQrStUvWxYz0123456789AbCd" description:"The actual API key (only shown on creation)"`
 	KeyPreview string     `json:"key_preview" example:"bk_AbCd...AbCd" description:"Truncated version of the key for display"`
 	ProjectID  string     `json:"project_id" example:"proj_01234567890123456789012345" description:"Project ID this key belongs to"`
+	Scopes     []string   `json:"scopes" example:"traces:create" description:"Scopes granted to this key"`
 	Status     string     `json:"status" example:"active" description:"API key status (active, expired)"`
 	LastUsed   *time.Time `json:"last_used,omitempty" example:"2024-01-01T00:00:00Z" description:"Last time this key was used (null if never used)"`
 	CreatedAt  time.Time  `json:"created_at" example:"2024-01-01T00:00:00Z" description:"Creation timestamp"`
@@ -47,10 +51,17 @@ type APIKey struct {
 	CreatedBy  string     `json:"created_by" example:"usr_01234567890123456789012345" description:"User ID who created this key"`
 }
 
+// APIKeyDetail represents an API key with its rate limits and current usage
+type APIKeyDetail struct {
+	APIKey
+	RateLimits *auth.RateLimitsResponse `json:"rate_limits" description:"Effective limits and usage of this key and its project"`
+}
+
 // CreateAPIKeyRequest represents the request to create an API key
 type CreateAPIKeyRequest struct {
-	Name         string `json:"name" binding:"required,min=2,max=100" example:"Production API Key" description:"Human-readable name for the API key (2-100 characters)"`
-	ExpiryOption string `json:"expiry_option" binding:"required,oneof=30days 90days never" example:"90days" description:"Expiration option: '30days', '90days', or 'never'"`
+	Name         string   `json:"name" binding:"required,min=2,max=100" example:"Production API Key" description:"Human-readable name for the API key (2-100 characters)"`
+	ExpiryOption string   `json:"expiry_option" binding:"required,oneof=30days 90days never" example:"90days" description:"Expiration option: '30days', '90days', or 'never'"`
+	Scopes       []string `json:"scopes,omitempty" example:"traces:create" description:"Scopes to grant (e.g. traces:create, traces:read, prompts:read). All API key scopes when omitted"`
 }
 
 // ListAPIKeysResponse represents the response when listing API keys
@@ -144,6 +155,7 @@ func (h *Handler) List(c *gin.Context) {
 			Name:       key.Name,
 			KeyPreview: key.KeyPreview, // Use stored preview
 			ProjectID:  key.ProjectID.String(),
+			Scopes:     key.Scopes,
 			Status:     getKeyStatus(*key),
 			LastUsed:   key.LastUsedAt,  // Pointer, will be null if nil
 			CreatedAt:  key.CreatedAt,
@@ -184,7 +196,7 @@ func getKeyStatus(key auth.APIKey) string {
 
 // Create handles POST /api/v1/projects/:projectId/api-keys
 // @Summary Create API key
-// @Description Create a new industry-standard API key for the project. The full key will only be displayed once upon creation. Format: bk_{40_char_random}
+// @Description Create a new industry-standard API key for the project, restricted to the requested scopes. The full key will only be displayed once upon creation. Format: bk_{40_char_random}
 // @Tags API Keys
 // @Accept json
 // @Produce json
@@ -248,6 +260,7 @@ func (h *Handler) Create(c *gin.Context) {
 	serviceReq := &auth.CreateAPIKeyRequest{
 		Name:      req.Name,
 		ProjectID: projectID,
+		Scopes:    req.Scopes,
 		ExpiresAt: expiresAt,
 	}
 
@@ -266,6 +279,7 @@ func (h *Handler) Create(c *gin.Context) {
 		Key:        apiKeyResp.Key, // Only shown once
 		KeyPreview: apiKeyResp.KeyPreview,
 		ProjectID:  apiKeyResp.ProjectID,
+		Scopes:     apiKeyResp.Scopes,
 		Status:     "active",
 		LastUsed:   nil, // New keys have never been used
 		CreatedAt:  apiKeyResp.CreatedAt,
@@ -278,6 +292,7 @@ func (h *Handler) Create(c *gin.Context) {
 		"api_key_id", apiKeyResp.ID,
 		"project_id", projectID,
 		"key_name", req.Name,
+		"scopes", apiKeyResp.Scopes,
 	)
 
 	response.Created(c, responseKey)
@@ -340,3 +355,180 @@ func (h *Handler) Delete(c *gin.Context) {
 	response.NoContent(c)
 }
 
+// Get handles GET /api/v1/projects/:projectId/api-keys/:keyId
+// @Summary Get API key
+// @Description Get an API key with its effective rate limits and current usage counters (key and shared project limits).
+// @Tags API Keys
+// @Produce json
+// @Param projectId path string true "Project ID" example("proj_01234567890123456789012345")
+// @Param keyId path string true "API Key ID" example("key_01234567890123456789012345")
+// @Success 200 {object} response.SuccessResponse{data=APIKeyDetail} "API key with rate limit usage"
+// @Failure 400 {object} response.ErrorResponse "Bad request - invalid project ID or key ID"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden - insufficient permissions to view API keys"
+// @Failure 404 {object} response.ErrorResponse "Project or API key not found"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/projects/{projectId}/api-keys/{keyId} [get]
+func (h *Handler) Get(c *gin.Context) {
+	projectID, keyID, ok := h.parseKeyPath(c)
+	if !ok {
+		return
+	}
+
+	key, err := h.apiKeyService.GetAPIKey(c.Request.Context(), keyID)
+	if err != nil || key.ProjectID != projectID {
+		h.logger.Warn("API key not found", "error", err, "api_key_id", keyID, "project_id", projectID)
+		response.NotFound(c, "API key")
+		return
+	}
+
+	limits, err := h.rateLimitService.GetAPIKeyLimits(c.Request.Context(), projectID, keyID)
+	if err != nil {
+		h.logger.Error("Failed to get API key rate limits", "error", err)
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, APIKeyDetail{
+		APIKey: APIKey{
+			ID:         key.ID.String(),
+			Name:       key.Name,
+			KeyPreview: key.KeyPreview,
+			ProjectID:  key.ProjectID.String(),
+			Scopes:     key.Scopes,
+			Status:     getKeyStatus(*key),
+			LastUsed:   key.LastUsedAt,
+			CreatedAt:  key.CreatedAt,
+			ExpiresAt:  key.ExpiresAt,
+			CreatedBy:  key.UserID.String(),
+		},
+		RateLimits: limits,
+	})
+}
+
+// UpdateRateLimits handles PUT /api/v1/projects/:projectId/api-keys/:keyId/rate-limits
+// @Summary Update API key rate limits
+// @Description Replace the key's rate limit overrides. Omitted limits use the server defaults; 0 is unlimited.
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Param projectId path string true "Project ID" example("proj_01234567890123456789012345")
+// @Param keyId path string true "API Key ID" example("key_01234567890123456789012345")
+// @Param request body auth.UpdateRateLimitsRequest true "Rate limits"
+// @Success 200 {object} response.SuccessResponse{data=auth.RateLimitsResponse} "Updated rate limits"
+// @Failure 400 {object} response.ErrorResponse "Bad request - invalid input"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden - insufficient permissions to update API keys"
+// @Failure 404 {object} response.ErrorResponse "Project or API key not found"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/projects/{projectId}/api-keys/{keyId}/rate-limits [put]
+func (h *Handler) UpdateRateLimits(c *gin.Context) {
+	projectID, keyID, ok := h.parseKeyPath(c)
+	if !ok {
+		return
+	}
+
+	var req auth.UpdateRateLimitsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid rate limits request", "error", err)
+		response.BadRequest(c, "Invalid request payload", err.Error())
+		return
+	}
+
+	limits, err := h.rateLimitService.UpdateAPIKeyLimits(c.Request.Context(), projectID, keyID, &req)
+	if err != nil {
+		h.logger.Error("Failed to update API key rate limits", "error", err)
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, limits)
+}
+
+// GetProjectRateLimits handles GET /api/v1/projects/:projectId/rate-limits
+// @Summary Get project rate limits
+// @Description Get the limits shared by all API keys of the project and their current usage.
+// @Tags API Keys
+// @Produce json
+// @Param projectId path string true "Project ID" example("proj_01234567890123456789012345")
+// @Success 200 {object} response.SuccessResponse{data=auth.RateLimitsResponse} "Project rate limits"
+// @Failure 400 {object} response.ErrorResponse "Bad request - invalid project ID"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/projects/{projectId}/rate-limits [get]
+func (h *Handler) GetProjectRateLimits(c *gin.Context) {
+	projectID, err := ulid.Parse(c.Param("projectId"))
+	if err != nil {
+		response.BadRequest(c, "Invalid project ID", err.Error())
+		return
+	}
+
+	limits, err := h.rateLimitService.GetProjectLimits(c.Request.Context(), projectID)
+	if err != nil {
+		h.logger.Error("Failed to get project rate limits", "error", err)
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, limits)
+}
+
+// UpdateProjectRateLimits handles PUT /api/v1/projects/:projectId/rate-limits
+// @Summary Update project rate limits
+// @Description Replace the limits shared by all API keys of the project. Omitted limits are unlimited.
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Param projectId path string true "Project ID" example("proj_01234567890123456789012345")
+// @Param request body auth.UpdateRateLimitsRequest true "Rate limits"
+// @Success 200 {object} response.SuccessResponse{data=auth.RateLimitsResponse} "Updated rate limits"
+// @Failure 400 {object} response.ErrorResponse "Bad request - invalid input"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/projects/{projectId}/rate-limits [put]
+func (h *Handler) UpdateProjectRateLimits(c *gin.Context) {
+	projectID, err := ulid.Parse(c.Param("projectId"))
+	if err != nil {
+		response.BadRequest(c, "Invalid project ID", err.Error())
+		return
+	}
+
+	var req auth.UpdateRateLimitsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid rate limits request", "error", err)
+		response.BadRequest(c, "Invalid request payload", err.Error())
+		return
+	}
+
+	limits, err := h.rateLimitService.UpdateProjectLimits(c.Request.Context(), projectID, &req)
+	if err != nil {
+		h.logger.Error("Failed to update project rate limits", "error", err)
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, limits)
+}
+
+// parseKeyPath parses the project and API key IDs from the URL path
+func (h *Handler) parseKeyPath(c *gin.Context) (ulid.ULID, ulid.ULID, bool) {
+	projectID, err := ulid.Parse(c.Param("projectId"))
+	if err != nil {
+		h.logger.Error("Invalid project ID", "error", err)
+		response.BadRequest(c, "Invalid project ID", err.Error())
+		return ulid.ULID{}, ulid.ULID{}, false
+	}
+
+	keyID, err := ulid.Parse(c.Param("keyId"))
+	if err != nil {
+		h.logger.Error("Invalid API key ID", "error", err)
+		response.BadRequest(c, "Invalid API key ID", err.Error())
+		return ulid.ULID{}, ulid.ULID{}, false
+	}
+
+	return projectID, keyID, true
+}
@@ -0,0 +1,179 @@
+package audit
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	authDomain "brokle/internal/core/domain/auth"
+	"brokle/internal/transport/http/middleware"
+	appErrors "brokle/pkg/errors"
+	"brokle/pkg/response"
+	"brokle/pkg/ulid"
+)
+
+// Handler serves an organization's audit log
+type Handler struct {
+	logger    *slog.Logger
+	auditLogs authDomain.AuditLogService
+	members   authDomain.OrganizationMemberService
+}
+
+// NewHandler creates a new audit log Handler
+func NewHandler(logger *slog.Logger, auditLogs authDomain.AuditLogService, members authDomain.OrganizationMemberService) *Handler {
+	return &Handler{
+		logger:    logger,
+		auditLogs: auditLogs,
+		members:   members,
+	}
+}
+
+// List returns the organization's audit log, newest first
+// @Summary List audit logs
+// @Description Mutations across the organization with actor, IP address and before/after changes
+// @Tags Organizations
+// @Produce json
+// @Param orgId path string true "Organization ID"
+// @Param action query string false "Action, e.g. prompt.labels_set"
+// @Param resource query string false "Resource type, e.g. api_key"
+// @Param resource_id query string false "Resource ID"
+// @Param user_id query string false "Acting user ID"
+// @Param project_id query string false "Project ID"
+// @Param start_date query string false "RFC 3339 lower bound"
+// @Param end_date query string false "RFC 3339 upper bound"
+// @Param page query int false "Page number"
+// @Param limit query int false "Page size (10, 25, 50, 100)"
+// @Param sort_dir query string false "asc or desc"
+// @Success 200 {array} authDomain.AuditLog
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Router /api/v1/organizations/{orgId}/audit-logs [get]
+func (h *Handler) List(c *gin.Context) {
+	filters, ok := parseFilters(c)
+	if !ok || !h.authorize(c, *filters.OrganizationID, "audit-logs:read") {
+		return
+	}
+	filters.Params = response.ParsePaginationParams(c.Query("page"), c.Query("limit"), "created_at", c.Query("sort_dir"))
+
+	logs, total, err := h.auditLogs.SearchAuditLogs(c.Request.Context(), filters)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	pag := response.NewPagination(filters.Params.Page, filters.Params.Limit, int64(total))
+	response.SuccessWithPagination(c, logs, pag)
+}
+
+// Export downloads the organization's audit log, oldest first
+// @Summary Export audit logs
+// @Description Every matching entry as CSV or a JSON array; takes the same filters as the list endpoint
+// @Tags Organizations
+// @Produce text/csv
+// @Produce json
+// @Param orgId path string true "Organization ID"
+// @Param format query string false "csv (default) or json"
+// @Param action query string false "Action"
+// @Param resource query string false "Resource type"
+// @Param resource_id query string false "Resource ID"
+// @Param user_id query string false "Acting user ID"
+// @Param project_id query string false "Project ID"
+// @Param start_date query string false "RFC 3339 lower bound"
+// @Param end_date query string false "RFC 3339 upper bound"
+// @Success 200 {file} file
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Router /api/v1/organizations/{orgId}/audit-logs/export [get]
+func (h *Handler) Export(c *gin.Context) {
+	format := authDomain.AuditExportFormat(c.DefaultQuery("format", string(authDomain.AuditExportCSV)))
+	if !format.IsValid() {
+		response.Error(c, appErrors.NewValidationError("Invalid export format", "format must be csv or json"))
+		return
+	}
+	filters, ok := parseFilters(c)
+	if !ok || !h.authorize(c, *filters.OrganizationID, "audit-logs:export") {
+		return
+	}
+
+	contentType := "text/csv"
+	if format == authDomain.AuditExportJSON {
+		contentType = "application/json"
+	}
+	filename := fmt.Sprintf("audit_log_%s_%s.%s", filters.OrganizationID, time.Now().UTC().Format("20060102T150405Z"), format)
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Header("Content-Type", contentType)
+	c.Status(http.StatusOK)
+
+	// Rows are streamed, so a failure after the first batch can only truncate the file
+	if err := h.auditLogs.ExportAuditLogs(c.Request.Context(), filters, format, c.Writer); err != nil {
+		h.logger.Error("Failed to export audit logs", "error", err, "organization_id", filters.OrganizationID)
+	}
+}
+
+// authorize checks the permission within the organization; the route check accepts it
+// from any organization the caller belongs to
+func (h *Handler) authorize(c *gin.Context, orgID ulid.ULID, permission string) bool {
+	userID, exists := middleware.GetUserIDULID(c)
+	if !exists {
+		response.Unauthorized(c, "Authentication required")
+		return false
+	}
+	if err := h.members.RequireOrganizationPermission(c.Request.Context(), userID, orgID, permission); err != nil {
+		response.Error(c, err)
+		return false
+	}
+	return true
+}
+
+// parseFilters reads the organization from the path and the optional query filters
+func parseFilters(c *gin.Context) (*authDomain.AuditLogFilters, bool) {
+	orgID, err := ulid.Parse(c.Param("orgId"))
+	if err != nil {
+		response.BadRequest(c, "Invalid orgId format", err.Error())
+		return nil, false
+	}
+	filters := &authDomain.AuditLogFilters{OrganizationID: &orgID}
+
+	for param, target := range map[string]**string{
+		"action":      &filters.Action,
+		"resource":    &filters.Resource,
+		"resource_id": &filters.ResourceID,
+	} {
+		if value := c.Query(param); value != "" {
+			*target = &value
+		}
+	}
+
+	for param, target := range map[string]**ulid.ULID{
+		"user_id":    &filters.UserID,
+		"project_id": &filters.ProjectID,
+	} {
+		if value := c.Query(param); value != "" {
+			id, err := ulid.Parse(value)
+			if err != nil {
+				response.BadRequest(c, "Invalid "+param+" format", err.Error())
+				return nil, false
+			}
+			*target = &id
+		}
+	}
+
+	for param, target := range map[string]**time.Time{
+		"start_date": &filters.StartDate,
+		"end_date":   &filters.EndDate,
+	} {
+		if value := c.Query(param); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				response.BadRequest(c, "Invalid "+param+" format", "expected RFC 3339, e.g. 2026-01-02T15:04:05Z")
+				return nil, false
+			}
+			*target = &t
+		}
+	}
+
+	return filters, true
+}
@@ -15,6 +15,7 @@ import (
 	"brokle/internal/core/domain/user"
 	authService "brokle/internal/core/services/auth"
 	"brokle/internal/core/services/registration"
+	"brokle/internal/ee/sso"
 	"brokle/pkg/response"
 	"brokle/pkg/ulid"
 )
@@ -28,6 +29,7 @@ type Handler struct {
 	userService         user.UserService
 	registrationService registration.RegistrationService
 	oauthProvider       *authService.OAuthProviderService
+	ssoProvider         sso.SSOProvider
 }
 
 // NewHandler creates a new auth handler
@@ -39,6 +41,7 @@ func NewHandler(
 	userService user.UserService,
 	registrationService registration.RegistrationService,
 	oauthProvider *authService.OAuthProviderService,
+	ssoProvider sso.SSOProvider,
 ) *Handler {
 	return &Handler{
 		config:              config,
@@ -48,6 +51,7 @@ func NewHandler(
 		userService:         userService,
 		registrationService: registrationService,
 		oauthProvider:       oauthProvider,
+		ssoProvider:         ssoProvider,
 	}
 }
 
@@ -62,6 +66,7 @@ type LoginRequest struct {
 // Login handles user login
 // @Summary User login
 // @Description Authenticate user. Sets httpOnly cookies: access_token (15min), refresh_token (7days), csrf_token (15min). Returns user data + expiry metadata (milliseconds).
+// @Description When a second factor is required no cookies are set; the response is { mfa_required, mfa_token, mfa_enrollment? } and login completes at POST /api/v1/auth/mfa/verify.
 // @Tags Authentication
 // @Accept json
 // @Produce json
@@ -97,6 +102,12 @@ func (h *Handler) Login(c *gin.Context) {
 		return
 	}
 
+	// Second factor pending - no cookies until POST /auth/mfa/verify
+	if loginResp.MFARequired {
+		response.Success(c, mfaChallengeResponse(loginResp))
+		return
+	}
+
 	// Fetch user data BEFORE setting cookies (atomic authentication)
 	userInterface, err := h.userService.GetUserByEmail(c.Request.Context(), req.Email)
 	if err != nil {
@@ -105,12 +116,24 @@ func (h *Handler) Login(c *gin.Context) {
 		return
 	}
 
+	responseData, ok := h.setLoginCookies(c, loginResp, userInterface)
+	if !ok {
+		return
+	}
+
+	h.logger.Info("User logged in successfully", "email", req.Email)
+	response.Success(c, responseData)
+}
+
+// setLoginCookies sets the auth cookies and returns the login metadata.
+// Writes an error response and returns false on failure.
+func (h *Handler) setLoginCookies(c *gin.Context, loginResp *auth.LoginResponse, userInterface interface{}) (gin.H, bool) {
 	// Generate CSRF token with error handling
 	csrfToken, err := generateCSRFToken()
 	if err != nil {
 		h.logger.Error("Failed to generate CSRF token", "error", err)
 		response.InternalServerError(c, "Authentication setup failed")
-		return
+		return nil, false
 	}
 
 	// All data fetched successfully - NOW set httpOnly cookies (atomic)
@@ -122,14 +145,11 @@ func (h *Handler) Login(c *gin.Context) {
 	expiresInMs := loginResp.ExpiresIn * 1000
 
 	// Return metadata only (tokens in httpOnly cookies)
-	responseData := gin.H{
+	return gin.H{
 		"user":       userInterface, // Always present (atomic authentication)
 		"expires_at": expiresAtMs,   // Milliseconds
 		"expires_in": expiresInMs,   // Milliseconds
-	}
-
-	h.logger.Info("User logged in successfully", "email", req.Email)
-	response.Success(c, responseData)
+	}, true
 }
 
 // RegisterRequest represents the registration request payload
@@ -0,0 +1,273 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"brokle/internal/core/domain/auth"
+	"brokle/internal/transport/http/middleware"
+	"brokle/pkg/response"
+	"brokle/pkg/ulid"
+)
+
+// mfaChallengeResponse is returned instead of a session while a second factor is pending
+func mfaChallengeResponse(loginResp *auth.LoginResponse) gin.H {
+	data := gin.H{
+		"mfa_required": true,
+		"mfa_token":    loginResp.MFAToken,
+	}
+	if loginResp.MFAEnrollment != nil {
+		data["mfa_enrollment"] = loginResp.MFAEnrollment
+	}
+	return data
+}
+
+// GetMFAChallenge returns a pending MFA challenge
+// @Summary Get MFA challenge
+// @Description Describe the challenge behind an MFA token, including the enrolment to complete when an organization requires MFA. Used after OAuth login redirects.
+// @Tags Authentication
+// @Produce json
+// @Param mfa_token path string true "MFA token"
+// @Success 200 {object} response.SuccessResponse "{ mfa_required, mfa_token, mfa_enrollment? }"
+// @Failure 401 {object} response.ErrorResponse "Challenge expired or invalid"
+// @Router /api/v1/auth/mfa/challenge/{mfa_token} [get]
+func (h *Handler) GetMFAChallenge(c *gin.Context) {
+	challenge, err := h.authService.GetMFAChallenge(c.Request.Context(), c.Param("mfa_token"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	// May carry a TOTP secret
+	c.Header("Cache-Control", "no-store")
+	response.Success(c, mfaChallengeResponse(challenge))
+}
+
+// VerifyMFA completes a login that requires a second factor
+// @Summary Verify MFA challenge
+// @Description Complete login with a TOTP code or a recovery code. Sets the same cookies as login.
+// @Description When enrolment was required at login, the first code enables MFA and recovery_codes are returned once.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body auth.MFAChallengeRequest true "MFA token from login and code"
+// @Success 200 {object} response.SuccessResponse "Cookies set. Response: { user, expires_at(ms), expires_in(ms), recovery_codes? }"
+// @Failure 400 {object} response.ErrorResponse "Invalid request payload"
+// @Failure 401 {object} response.ErrorResponse "Invalid code, or challenge expired"
+// @Router /api/v1/auth/mfa/verify [post]
+func (h *Handler) VerifyMFA(c *gin.Context) {
+	var req auth.MFAChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request payload", err.Error())
+		return
+	}
+
+	loginResp, err := h.authService.VerifyMFAChallenge(c.Request.Context(), &req)
+	if err != nil {
+		h.logger.Warn("MFA verification failed", "error", err)
+		response.Error(c, err)
+		return
+	}
+
+	// Fetch user data BEFORE setting cookies (atomic authentication)
+	authCtx, err := h.authService.GetAuthContext(c.Request.Context(), loginResp.AccessToken)
+	if err != nil {
+		h.logger.Error("Failed to resolve user after MFA verification", "error", err)
+		response.InternalServerError(c, "Failed to complete authentication")
+		return
+	}
+	userInterface, err := h.userService.GetUser(c.Request.Context(), authCtx.UserID)
+	if err != nil {
+		h.logger.Error("Failed to get user data after MFA verification", "error", err, "user_id", authCtx.UserID)
+		response.InternalServerError(c, "Failed to complete authentication")
+		return
+	}
+
+	responseData, ok := h.setLoginCookies(c, loginResp, userInterface)
+	if !ok {
+		return
+	}
+	if len(loginResp.RecoveryCodes) > 0 {
+		responseData["recovery_codes"] = loginResp.RecoveryCodes
+	}
+
+	h.logger.Info("User logged in with MFA", "user_id", authCtx.UserID)
+	response.Success(c, responseData)
+}
+
+// GetMFAStatus returns the current user's MFA state
+// @Summary Get MFA status
+// @Description Whether MFA is enabled, whether an organization requires it, and how many recovery codes remain
+// @Tags Authentication
+// @Produce json
+// @Success 200 {object} auth.MFAStatus
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Router /api/v1/auth/mfa [get]
+func (h *Handler) GetMFAStatus(c *gin.Context) {
+	userID, exists := middleware.GetUserIDULID(c)
+	if !exists {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	status, err := h.authService.GetMFAStatus(c.Request.Context(), userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, status)
+}
+
+// BeginMFAEnrollment starts TOTP enrolment for the current user
+// @Summary Begin MFA enrolment
+// @Description Generate a TOTP secret and otpauth:// provisioning URI to render as a QR code. MFA is enabled once a code is confirmed.
+// @Tags Authentication
+// @Produce json
+// @Success 200 {object} auth.MFAEnrollment
+// @Failure 409 {object} response.ErrorResponse "MFA already enabled"
+// @Failure 503 {object} response.ErrorResponse "MFA not configured on this server"
+// @Router /api/v1/auth/mfa/enroll [post]
+func (h *Handler) BeginMFAEnrollment(c *gin.Context) {
+	userID, exists := middleware.GetUserIDULID(c)
+	if !exists {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	enrollment, err := h.authService.BeginMFAEnrollment(c.Request.Context(), userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, enrollment)
+}
+
+// ConfirmMFAEnrollment enables MFA with the first code from the authenticator app
+// @Summary Confirm MFA enrolment
+// @Description Verify the first TOTP code to enable MFA. Recovery codes are returned once.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body auth.MFACodeRequest true "TOTP code"
+// @Success 200 {object} auth.MFARecoveryCodes
+// @Failure 400 {object} response.ErrorResponse "Invalid code"
+// @Failure 404 {object} response.ErrorResponse "No pending enrolment"
+// @Router /api/v1/auth/mfa/enroll/verify [post]
+func (h *Handler) ConfirmMFAEnrollment(c *gin.Context) {
+	userID, req, ok := h.bindMFACode(c)
+	if !ok {
+		return
+	}
+
+	codes, err := h.authService.ConfirmMFAEnrollment(c.Request.Context(), userID, req.Code)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, codes)
+}
+
+// RegenerateMFARecoveryCodes replaces the current user's recovery codes
+// @Summary Regenerate recovery codes
+// @Description Invalidate all recovery codes and return new ones. Requires a current TOTP code.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body auth.MFACodeRequest true "TOTP code"
+// @Success 200 {object} auth.MFARecoveryCodes
+// @Failure 400 {object} response.ErrorResponse "Invalid code"
+// @Failure 404 {object} response.ErrorResponse "MFA not enabled"
+// @Router /api/v1/auth/mfa/recovery-codes [post]
+func (h *Handler) RegenerateMFARecoveryCodes(c *gin.Context) {
+	userID, req, ok := h.bindMFACode(c)
+	if !ok {
+		return
+	}
+
+	codes, err := h.authService.RegenerateMFARecoveryCodes(c.Request.Context(), userID, req.Code)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, codes)
+}
+
+// DisableMFA turns off MFA for the current user
+// @Summary Disable MFA
+// @Description Remove the second factor. Requires a current TOTP code; refused while an organization of the user requires MFA.
+// @Tags Authentication
+// @Accept json
+// @Param request body auth.MFACodeRequest true "TOTP code"
+// @Success 204 "No Content"
+// @Failure 400 {object} response.ErrorResponse "Invalid code"
+// @Failure 403 {object} response.ErrorResponse "Required by an organization"
+// @Router /api/v1/auth/mfa [delete]
+func (h *Handler) DisableMFA(c *gin.Context) {
+	userID, req, ok := h.bindMFACode(c)
+	if !ok {
+		return
+	}
+
+	if err := h.authService.DisableMFA(c.Request.Context(), userID, req.Code); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ResetMemberMFA removes the second factor of an organization member
+// @Summary Reset member MFA
+// @Description Remove a member's MFA enrolment after a lost device and revoke their sessions. They enrol again at next login if the organization requires MFA.
+// @Tags Organizations
+// @Param orgId path string true "Organization ID"
+// @Param userId path string true "User ID"
+// @Success 204 "No Content"
+// @Failure 403 {object} response.ErrorResponse "No members:update permission in the organization"
+// @Failure 404 {object} response.ErrorResponse "Not a member, or MFA not enrolled"
+// @Router /api/v1/organizations/{orgId}/members/{userId}/mfa/reset [post]
+func (h *Handler) ResetMemberMFA(c *gin.Context) {
+	adminID, exists := middleware.GetUserIDULID(c)
+	if !exists {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+	orgID, err := ulid.Parse(c.Param("orgId"))
+	if err != nil {
+		response.BadRequest(c, "Invalid organization ID format", err.Error())
+		return
+	}
+	userID, err := ulid.Parse(c.Param("userId"))
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID format", err.Error())
+		return
+	}
+
+	if err := h.authService.ResetMFA(c.Request.Context(), adminID, orgID, userID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	h.logger.Info("Member MFA reset", "admin_id", adminID, "organization_id", orgID, "user_id", userID)
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) bindMFACode(c *gin.Context) (ulid.ULID, *auth.MFACodeRequest, bool) {
+	userID, exists := middleware.GetUserIDULID(c)
+	if !exists {
+		response.Unauthorized(c, "Authentication required")
+		return ulid.ULID{}, nil, false
+	}
+
+	var req auth.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request payload", err.Error())
+		return ulid.ULID{}, nil, false
+	}
+	return userID, &req, true
+}
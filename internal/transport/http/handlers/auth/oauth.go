@@ -8,7 +8,9 @@ import (
 	"github.com/gin-gonic/gin"
 
 	authService "brokle/internal/core/services/auth"
+	appErrors "brokle/pkg/errors"
 	"brokle/pkg/response"
+	"brokle/pkg/ulid"
 )
 
 // getFrontendURL returns the frontend URL from environment or default
@@ -95,6 +97,11 @@ func (h *Handler) GoogleCallback(c *gin.Context) {
 		return
 	}
 
+	// Domains that enforce SSO cannot sign in or sign up through Google
+	if h.redirectIfSSORequired(c, userProfile.Email, "google") {
+		return
+	}
+
 	// Check if user already exists
 	existingUser, err := h.userService.GetUserByEmail(c.Request.Context(), userProfile.Email)
 	if err == nil && existingUser != nil {
@@ -133,6 +140,11 @@ func (h *Handler) GoogleCallback(c *gin.Context) {
 		// All security checks passed - safe to auto-login
 		h.logger.Info("Existing OAuth user logging in", "email", userProfile.Email, "provider", "google", "user_id", existingUser.ID)
 
+		// Second factor before any tokens are issued
+		if h.redirectToMFAChallenge(c, existingUser.ID) {
+			return
+		}
+
 		// Generate login tokens
 		loginTokens, err := h.authService.GenerateTokensForUser(c.Request.Context(), existingUser.ID)
 		if err != nil {
@@ -259,6 +271,11 @@ func (h *Handler) GitHubCallback(c *gin.Context) {
 		return
 	}
 
+	// Domains that enforce SSO cannot sign in or sign up through GitHub
+	if h.redirectIfSSORequired(c, userProfile.Email, "github") {
+		return
+	}
+
 	// Check if user already exists
 	existingUser, err := h.userService.GetUserByEmail(c.Request.Context(), userProfile.Email)
 	if err == nil && existingUser != nil {
@@ -297,6 +314,11 @@ func (h *Handler) GitHubCallback(c *gin.Context) {
 		// All security checks passed - safe to auto-login
 		h.logger.Info("Existing OAuth user logging in", "email", userProfile.Email, "provider", "github", "user_id", existingUser.ID)
 
+		// Second factor before any tokens are issued
+		if h.redirectToMFAChallenge(c, existingUser.ID) {
+			return
+		}
+
 		// Generate login tokens
 		loginTokens, err := h.authService.GenerateTokensForUser(c.Request.Context(), existingUser.ID)
 		if err != nil {
@@ -346,3 +368,39 @@ func (h *Handler) GitHubCallback(c *gin.Context) {
 	// Redirect to frontend Step 2 (personalization)
 	c.Redirect(http.StatusTemporaryRedirect, fmt.Sprintf("%s/auth/signup?session=%s", getFrontendURL(), sessionID))
 }
+
+// redirectIfSSORequired sends users whose email domain enforces SSO back to the sign-in
+// page. Returns false when the OAuth login can continue.
+func (h *Handler) redirectIfSSORequired(c *gin.Context, email, provider string) bool {
+	err := h.authService.CheckSocialLoginAllowed(c.Request.Context(), email)
+	if err == nil {
+		return false
+	}
+
+	if appErr, ok := appErrors.IsAppError(err); ok && appErr.Type == appErrors.ForbiddenError {
+		h.logger.Warn("OAuth login blocked by SSO enforcement", "email", email, "provider", provider)
+		c.Redirect(http.StatusTemporaryRedirect, getFrontendURL()+"/auth/signin?error=sso_required")
+		return true
+	}
+	h.logger.Error("Failed to check SSO enforcement", "error", err, "provider", provider)
+	c.Redirect(http.StatusTemporaryRedirect, getFrontendURL()+"/auth/signin?error=login_failed")
+	return true
+}
+
+// redirectToMFAChallenge sends users who need a second factor to the frontend MFA page.
+// Returns false when the login can continue without one.
+func (h *Handler) redirectToMFAChallenge(c *gin.Context, userID ulid.ULID) bool {
+	challenge, err := h.authService.CreateMFAChallenge(c.Request.Context(), userID, nil)
+	if err != nil {
+		h.logger.Error("Failed to create MFA challenge", "error", err, "user_id", userID)
+		c.Redirect(loginRedirectStatus(c), getFrontendURL()+"/auth/signin?error=login_failed")
+		return true
+	}
+	if challenge == nil {
+		return false
+	}
+
+	// The page loads the challenge (and any required enrolment) with the token
+	c.Redirect(loginRedirectStatus(c), fmt.Sprintf("%s/auth/mfa?mfa_token=%s", getFrontendURL(), challenge.MFAToken))
+	return true
+}
@@ -0,0 +1,309 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"brokle/internal/core/domain/auth"
+	"brokle/internal/ee/sso"
+	"brokle/internal/transport/http/middleware"
+	appErrors "brokle/pkg/errors"
+	"brokle/pkg/response"
+	"brokle/pkg/ulid"
+)
+
+// InitiateSSOLogin redirects to the identity provider for the email's domain
+// @Summary Initiate SSO login
+// @Description Start OIDC or SAML login with the SSO connection of the email's verified domain
+// @Tags Authentication
+// @Param email query string true "Work email address"
+// @Success 302 {string} string "Redirect to identity provider"
+// @Failure 404 {object} response.ErrorResponse "No active SSO connection for the domain"
+// @Failure 402 {object} response.ErrorResponse "SSO requires an Enterprise license"
+// @Router /api/v1/auth/sso/login [get]
+func (h *Handler) InitiateSSOLogin(c *gin.Context) {
+	email := strings.TrimSpace(c.Query("email"))
+	if auth.EmailDomain(email) == "" {
+		response.BadRequest(c, "Invalid email", "email query parameter is required")
+		return
+	}
+
+	loginURL, err := h.ssoProvider.GetLoginURL(c.Request.Context(), email)
+	if err != nil {
+		h.ssoError(c, err)
+		return
+	}
+
+	c.Redirect(http.StatusTemporaryRedirect, loginURL)
+}
+
+// SSOOIDCCallback handles the OIDC authorization response
+// @Summary SSO OIDC callback
+// @Description Validate the id_token, provision the user and create a login session
+// @Tags Authentication
+// @Param code query string true "Authorization code"
+// @Param state query string true "SSO state"
+// @Success 302 {string} string "Redirect to frontend"
+// @Router /api/v1/auth/sso/oidc/callback [get]
+func (h *Handler) SSOOIDCCallback(c *gin.Context) {
+	code := c.Query("code")
+	state := c.Query("state")
+	if code == "" || state == "" {
+		h.logger.Warn("SSO OIDC callback without code", "error", c.Query("error"), "error_description", c.Query("error_description"))
+		c.Redirect(http.StatusTemporaryRedirect, getFrontendURL()+"/auth/signin?error=sso_failed")
+		return
+	}
+
+	ssoUser, err := h.ssoProvider.Authenticate(c.Request.Context(), state, code)
+	if err != nil {
+		h.logger.Warn("SSO OIDC login failed", "error", err)
+		c.Redirect(http.StatusTemporaryRedirect, getFrontendURL()+"/auth/signin?error=sso_failed")
+		return
+	}
+
+	h.completeSSOLogin(c, ssoUser)
+}
+
+// SSOSAMLACS is the SAML assertion consumer service
+// @Summary SSO SAML assertion consumer service
+// @Description Validate the signed SAML response (HTTP-POST binding), provision the user and create a login session
+// @Tags Authentication
+// @Accept x-www-form-urlencoded
+// @Param SAMLResponse formData string true "Base64 SAML response"
+// @Param RelayState formData string true "Relay state from the login request"
+// @Success 302 {string} string "Redirect to frontend"
+// @Router /api/v1/auth/sso/saml/acs [post]
+func (h *Handler) SSOSAMLACS(c *gin.Context) {
+	samlResponse := c.PostForm("SAMLResponse")
+	if samlResponse == "" {
+		c.Redirect(http.StatusSeeOther, getFrontendURL()+"/auth/signin?error=sso_failed")
+		return
+	}
+
+	ssoUser, err := h.ssoProvider.ValidateAssertion(c.Request.Context(), samlResponse, c.PostForm("RelayState"))
+	if err != nil {
+		h.logger.Warn("SSO SAML login failed", "error", err)
+		c.Redirect(http.StatusSeeOther, getFrontendURL()+"/auth/signin?error=sso_failed")
+		return
+	}
+
+	h.completeSSOLogin(c, ssoUser)
+}
+
+// GetSSOMetadata serves SAML service provider metadata
+// @Summary SAML SP metadata
+// @Description Service provider metadata to register Brokle with the identity provider. The URL is also the SP entity ID.
+// @Tags Authentication
+// @Produce xml
+// @Param orgId path string true "Organization ID"
+// @Success 200 {string} string "SAML metadata XML"
+// @Failure 404 {object} response.ErrorResponse "No SSO connection"
+// @Router /api/v1/auth/sso/saml/metadata/{orgId} [get]
+func (h *Handler) GetSSOMetadata(c *gin.Context) {
+	orgID, err := ulid.Parse(c.Param("orgId"))
+	if err != nil {
+		response.BadRequest(c, "Invalid organization ID format", err.Error())
+		return
+	}
+
+	metadata, err := h.ssoProvider.Metadata(c.Request.Context(), orgID)
+	if err != nil {
+		h.ssoError(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/samlmetadata+xml", metadata)
+}
+
+// completeSSOLogin provisions the SSO user and hands tokens to the frontend through the
+// same one-time login session as Google and GitHub login.
+func (h *Handler) completeSSOLogin(c *gin.Context, ssoUser *sso.User) {
+	status := loginRedirectStatus(c)
+
+	userID, err := h.ssoProvider.ProvisionUser(c.Request.Context(), ssoUser)
+	if err != nil {
+		h.logger.Error("Failed to provision SSO user", "error", err, "email", ssoUser.Email, "organization_id", ssoUser.OrganizationID)
+		c.Redirect(status, getFrontendURL()+"/auth/signin?error=sso_failed")
+		return
+	}
+
+	h.logger.Info("SSO user logging in", "email", ssoUser.Email, "provider", ssoUser.Provider, "user_id", userID, "organization_id", ssoUser.OrganizationID)
+
+	// Second factor before any tokens are issued
+	if h.redirectToMFAChallenge(c, userID) {
+		return
+	}
+
+	loginTokens, err := h.authService.GenerateTokensForUser(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to generate login tokens for SSO user", "error", err)
+		c.Redirect(status, getFrontendURL()+"/auth/signin?error=login_failed")
+		return
+	}
+
+	// Create one-time login session to securely pass tokens to frontend
+	loginSessionID, err := h.authService.CreateLoginTokenSession(
+		c.Request.Context(),
+		loginTokens.AccessToken,
+		loginTokens.RefreshToken,
+		loginTokens.ExpiresIn,
+		userID,
+	)
+	if err != nil {
+		h.logger.Error("Failed to create login session", "error", err)
+		c.Redirect(status, getFrontendURL()+"/auth/signin?error=session_failed")
+		return
+	}
+
+	c.Redirect(status, fmt.Sprintf("%s/auth/callback?session=%s&type=login", getFrontendURL(), loginSessionID))
+}
+
+// GetSSOConnection returns the organization's SSO connection
+// @Summary Get SSO connection
+// @Description The organization's OIDC or SAML connection, with the DNS record and callback URLs to configure
+// @Tags Organizations
+// @Produce json
+// @Param orgId path string true "Organization ID"
+// @Success 200 {object} auth.SSOConnectionResponse
+// @Failure 404 {object} response.ErrorResponse "No SSO connection"
+// @Router /api/v1/organizations/{orgId}/sso [get]
+func (h *Handler) GetSSOConnection(c *gin.Context) {
+	orgID, userID, ok := parseOrgManagement(c)
+	if !ok {
+		return
+	}
+
+	connection, err := h.ssoProvider.GetConnection(c.Request.Context(), orgID, userID)
+	if err != nil {
+		h.ssoError(c, err)
+		return
+	}
+
+	response.Success(c, connection)
+}
+
+// ConfigureSSOConnection creates or replaces the organization's SSO connection
+// @Summary Configure SSO connection
+// @Description Set up OIDC (issuer, client ID, client secret) or SAML (IdP SSO URL, certificate). Logins start once the domain is verified.
+// @Description Changing the domain or the identity provider requires verifying the domain again.
+// @Description Users of the domain join the organization with default_role on first login; enforce_sso blocks their password login.
+// @Tags Organizations
+// @Accept json
+// @Produce json
+// @Param orgId path string true "Organization ID"
+// @Param request body auth.SSOConnectionRequest true "Connection settings"
+// @Success 200 {object} auth.SSOConnectionResponse
+// @Failure 400 {object} response.ErrorResponse "Invalid settings"
+// @Failure 403 {object} response.ErrorResponse "Not an admin of the organization"
+// @Failure 409 {object} response.ErrorResponse "Domain used by another organization"
+// @Router /api/v1/organizations/{orgId}/sso [put]
+func (h *Handler) ConfigureSSOConnection(c *gin.Context) {
+	orgID, userID, ok := parseOrgManagement(c)
+	if !ok {
+		return
+	}
+
+	var req auth.SSOConnectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request payload", err.Error())
+		return
+	}
+
+	connection, err := h.ssoProvider.ConfigureProvider(c.Request.Context(), orgID, userID, &req)
+	if err != nil {
+		h.ssoError(c, err)
+		return
+	}
+
+	h.logger.Info("SSO connection configured", "organization_id", orgID, "protocol", req.Protocol, "domain", connection.Domain, "user_id", userID)
+	response.Success(c, connection)
+}
+
+// DeleteSSOConnection removes the organization's SSO connection
+// @Summary Delete SSO connection
+// @Description Remove the connection. Members keep their accounts; password login is no longer blocked.
+// @Tags Organizations
+// @Param orgId path string true "Organization ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} response.ErrorResponse "No SSO connection"
+// @Router /api/v1/organizations/{orgId}/sso [delete]
+func (h *Handler) DeleteSSOConnection(c *gin.Context) {
+	orgID, userID, ok := parseOrgManagement(c)
+	if !ok {
+		return
+	}
+
+	if err := h.ssoProvider.DeleteConnection(c.Request.Context(), orgID, userID); err != nil {
+		h.ssoError(c, err)
+		return
+	}
+
+	h.logger.Info("SSO connection deleted", "organization_id", orgID, "user_id", userID)
+	c.Status(http.StatusNoContent)
+}
+
+// VerifySSODomain checks the DNS TXT record proving control of the connection's domain
+// @Summary Verify SSO domain
+// @Description Look up the dns_record TXT value on the domain and activate the connection when found
+// @Tags Organizations
+// @Produce json
+// @Param orgId path string true "Organization ID"
+// @Success 200 {object} auth.SSOConnectionResponse
+// @Failure 400 {object} response.ErrorResponse "TXT record not found"
+// @Failure 404 {object} response.ErrorResponse "No SSO connection"
+// @Router /api/v1/organizations/{orgId}/sso/verify-domain [post]
+func (h *Handler) VerifySSODomain(c *gin.Context) {
+	orgID, userID, ok := parseOrgManagement(c)
+	if !ok {
+		return
+	}
+
+	connection, err := h.ssoProvider.VerifyDomain(c.Request.Context(), orgID, userID)
+	if err != nil {
+		h.ssoError(c, err)
+		return
+	}
+
+	h.logger.Info("SSO domain verified", "organization_id", orgID, "domain", connection.Domain)
+	response.Success(c, connection)
+}
+
+// loginRedirectStatus is 303 for the SAML POST binding, so the browser follows with a GET
+func loginRedirectStatus(c *gin.Context) int {
+	if c.Request.Method == http.MethodPost {
+		return http.StatusSeeOther
+	}
+	return http.StatusTemporaryRedirect
+}
+
+// ssoError reports unlicensed SSO as payment required; the stub returns plain errors
+func (h *Handler) ssoError(c *gin.Context, err error) {
+	if _, ok := appErrors.IsAppError(err); !ok && strings.Contains(err.Error(), "Enterprise license") {
+		response.PaymentRequired(c, err.Error())
+		return
+	}
+	response.Error(c, err)
+}
+
+func parseOrgID(c *gin.Context) (ulid.ULID, bool) {
+	orgID, err := ulid.Parse(c.Param("orgId"))
+	if err != nil {
+		response.BadRequest(c, "Invalid organization ID format", err.Error())
+		return ulid.ULID{}, false
+	}
+	return orgID, true
+}
+
+// parseOrgManagement returns the organization and the caller managing its connection
+func parseOrgManagement(c *gin.Context) (ulid.ULID, ulid.ULID, bool) {
+	userID, exists := middleware.GetUserIDULID(c)
+	if !exists {
+		response.Unauthorized(c, "Authentication required")
+		return ulid.ULID{}, ulid.ULID{}, false
+	}
+	orgID, ok := parseOrgID(c)
+	return orgID, userID, ok
+}
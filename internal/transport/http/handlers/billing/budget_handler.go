@@ -44,6 +44,10 @@ type CreateBudgetRequest struct {
 	ScoreLimit      *int64   `json:"score_limit,omitempty"`
 	CostLimit       *float64 `json:"cost_limit,omitempty"`
 	AlertThresholds []int64  `json:"alert_thresholds"` // e.g., [50, 80, 100]
+
+	// Ingestion enforcement once a limit is reached (default none = alerts only)
+	EnforcementAction        *string `json:"enforcement_action,omitempty" binding:"omitempty,oneof=none block sample drop_payloads"`
+	EnforcementSamplePercent *int    `json:"enforcement_sample_percent,omitempty" binding:"omitempty,min=1,max=99"` // Required for sample
 }
 
 // UpdateBudgetRequest represents the request body for updating a budget
@@ -55,6 +59,10 @@ type UpdateBudgetRequest struct {
 	CostLimit       *float64 `json:"cost_limit,omitempty"`
 	AlertThresholds []int64  `json:"alert_thresholds,omitempty"` // e.g., [50, 80, 100]
 	IsActive        *bool    `json:"is_active,omitempty"`
+
+	// Ingestion enforcement once a limit is reached
+	EnforcementAction        *string `json:"enforcement_action,omitempty" binding:"omitempty,oneof=none block sample drop_payloads"`
+	EnforcementSamplePercent *int    `json:"enforcement_sample_percent,omitempty" binding:"omitempty,min=1,max=99"`
 }
 
 // ListBudgets handles GET /api/v1/organizations/:orgId/budgets
@@ -213,6 +221,11 @@ func (h *BudgetHandler) CreateBudget(c *gin.Context) {
 		ScoreLimit:      req.ScoreLimit,
 		CostLimit:       costLimit,
 		AlertThresholds: alertThresholds,
+
+		EnforcementSamplePercent: req.EnforcementSamplePercent,
+	}
+	if req.EnforcementAction != nil {
+		budget.EnforcementAction = billing.EnforcementAction(*req.EnforcementAction)
 	}
 
 	if req.ProjectID != nil {
@@ -314,12 +327,22 @@ func (h *BudgetHandler) UpdateBudget(c *gin.Context) {
 	if req.IsActive != nil {
 		budget.IsActive = *req.IsActive
 	}
+	if req.EnforcementAction != nil {
+		budget.EnforcementAction = billing.EnforcementAction(*req.EnforcementAction)
+	}
+	if req.EnforcementSamplePercent != nil {
+		budget.EnforcementSamplePercent = req.EnforcementSamplePercent
+	}
 
 	if err := h.budgetService.UpdateBudget(c.Request.Context(), budget); err != nil {
 		h.logger.Error("failed to update budget",
 			"error", err,
 			"budget_id", budgetID,
 		)
+		if _, ok := appErrors.IsAppError(err); ok {
+			response.Error(c, err)
+			return
+		}
 		response.Error(c, appErrors.NewInternalError("Failed to update budget", err))
 		return
 	}
@@ -4,17 +4,21 @@ import (
 	"log/slog"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"brokle/internal/config"
+	"brokle/internal/core/domain/auth"
 	"brokle/internal/ee/analytics"
 	"brokle/internal/ee/compliance"
 	license "brokle/internal/ee/licensing"
 	"brokle/internal/ee/rbac"
 	"brokle/internal/ee/sso"
 	"brokle/internal/middleware"
+	httpMiddleware "brokle/internal/transport/http/middleware"
 	"brokle/pkg/response"
+	"brokle/pkg/ulid"
 )
 
 // Handler handles enterprise-specific endpoints
@@ -72,13 +76,9 @@ func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
 	roles := r.Group("/rbac")
 	roles.Use(middleware.EnterpriseFeature("advanced_rbac", h.licenseService, h.logger))
 	{
-		roles.GET("/roles", h.ListRoles)
-		roles.POST("/roles", h.CreateRole)
-		roles.PUT("/roles/:id", h.UpdateRole)
-		roles.DELETE("/roles/:id", h.DeleteRole)
-		roles.POST("/roles/:id/assign/:user_id", h.AssignRole)
-		roles.DELETE("/roles/:id/unassign/:user_id", h.UnassignRole)
-		roles.GET("/users/:id/permissions", h.GetUserPermissions)
+		roles.GET("/projects/:projectId/members", h.ListProjectMembers)
+		roles.PUT("/projects/:projectId/members/:userId", h.AssignProjectRole)
+		roles.DELETE("/projects/:projectId/members/:userId", h.RemoveProjectRole)
 	}
 
 	// Enterprise compliance endpoints (requires business+ license)
@@ -193,36 +193,47 @@ func (h *Handler) GetSSOProviders(c *gin.Context) {
 
 func (h *Handler) ConfigureSSO(c *gin.Context) {
 	var req struct {
-		Provider string `json:"provider" binding:"required"`
-		Config   string `json:"config" binding:"required"`
+		OrganizationID string `json:"organization_id" binding:"required"`
+		auth.SSOConnectionRequest
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		response.ValidationError(c, "Invalid request", err.Error())
 		return
 	}
+	orgID, err := ulid.Parse(req.OrganizationID)
+	if err != nil {
+		response.ValidationError(c, "Invalid organization ID", err.Error())
+		return
+	}
 
-	err := h.ssoService.ConfigureProvider(c.Request.Context(), req.Provider, req.Config)
+	userID, exists := httpMiddleware.GetUserIDULID(c)
+	if !exists {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	connection, err := h.ssoService.ConfigureProvider(c.Request.Context(), orgID, userID, &req.SSOConnectionRequest)
 	if err != nil {
 		if strings.Contains(err.Error(), "Enterprise license") {
 			response.PaymentRequired(c, err.Error())
 			return
 		}
-		response.InternalServerError(c, err.Error())
+		response.Error(c, err)
 		return
 	}
 
-	response.Success(c, gin.H{"message": "SSO provider configured successfully"})
+	response.Success(c, connection)
 }
 
 func (h *Handler) GetSSOLoginURL(c *gin.Context) {
-	url, err := h.ssoService.GetLoginURL(c.Request.Context())
+	url, err := h.ssoService.GetLoginURL(c.Request.Context(), c.Query("email"))
 	if err != nil {
 		if strings.Contains(err.Error(), "Enterprise license") {
 			response.PaymentRequired(c, err.Error())
 			return
 		}
-		response.InternalServerError(c, err.Error())
+		response.Error(c, err)
 		return
 	}
 
@@ -236,7 +247,7 @@ func (h *Handler) HandleSSOCallback(c *gin.Context) {
 		return
 	}
 
-	user, err := h.ssoService.ValidateAssertion(c.Request.Context(), assertion)
+	user, err := h.ssoService.ValidateAssertion(c.Request.Context(), assertion, c.PostForm("RelayState"))
 	if err != nil {
 		if strings.Contains(err.Error(), "Enterprise license") {
 			response.PaymentRequired(c, err.Error())
@@ -254,114 +265,75 @@ func (h *Handler) HandleSSOCallback(c *gin.Context) {
 
 // RBAC Handlers
 
-func (h *Handler) ListRoles(c *gin.Context) {
-	roles, err := h.rbacService.ListRoles(c.Request.Context())
+func (h *Handler) ListProjectMembers(c *gin.Context) {
+	projectID, err := ulid.Parse(c.Param("projectId"))
 	if err != nil {
-		response.InternalServerError(c, err.Error())
+		response.ValidationError(c, "Invalid project ID", err.Error())
 		return
 	}
 
-	response.Success(c, gin.H{"roles": roles})
-}
-
-func (h *Handler) CreateRole(c *gin.Context) {
-	var role rbac.Role
-	if err := c.ShouldBindJSON(&role); err != nil {
-		response.ValidationError(c, "Invalid request", err.Error())
-		return
-	}
-
-	err := h.rbacService.CreateRole(c.Request.Context(), &role)
+	members, err := h.rbacService.ListProjectMembers(c.Request.Context(), projectID)
 	if err != nil {
-		if strings.Contains(err.Error(), "Enterprise license") {
-			response.PaymentRequired(c, err.Error())
-			return
-		}
-		response.InternalServerError(c, err.Error())
+		response.Error(c, err)
 		return
 	}
 
-	response.Created(c, gin.H{"message": "Role created successfully", "role": role})
+	response.Success(c, gin.H{"members": members})
 }
 
-func (h *Handler) UpdateRole(c *gin.Context) {
-	roleID := c.Param("id")
-
-	var role rbac.Role
-	if err := c.ShouldBindJSON(&role); err != nil {
-		response.ValidationError(c, "Invalid request", err.Error())
+func (h *Handler) AssignProjectRole(c *gin.Context) {
+	projectID, err := ulid.Parse(c.Param("projectId"))
+	if err != nil {
+		response.ValidationError(c, "Invalid project ID", err.Error())
 		return
 	}
-
-	err := h.rbacService.UpdateRole(c.Request.Context(), roleID, &role)
+	userID, err := ulid.Parse(c.Param("userId"))
 	if err != nil {
-		if strings.Contains(err.Error(), "Enterprise license") {
-			response.PaymentRequired(c, err.Error())
-			return
-		}
-		response.InternalServerError(c, err.Error())
+		response.ValidationError(c, "Invalid user ID", err.Error())
 		return
 	}
 
-	response.Success(c, gin.H{"message": "Role updated successfully"})
-}
-
-func (h *Handler) DeleteRole(c *gin.Context) {
-	roleID := c.Param("id")
+	var req auth.AssignProjectRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Invalid request", err.Error())
+		return
+	}
 
-	err := h.rbacService.DeleteRole(c.Request.Context(), roleID)
+	member, err := h.rbacService.AssignProjectRole(c.Request.Context(), projectID, userID, req.RoleID, nil)
 	if err != nil {
 		if strings.Contains(err.Error(), "Enterprise license") {
 			response.PaymentRequired(c, err.Error())
 			return
 		}
-		response.InternalServerError(c, err.Error())
+		response.Error(c, err)
 		return
 	}
 
-	response.Success(c, gin.H{"message": "Role deleted successfully"})
+	response.Success(c, member)
 }
 
-func (h *Handler) AssignRole(c *gin.Context) {
-	roleID := c.Param("id")
-	userID := c.Param("user_id")
-
-	err := h.rbacService.AssignRoleToUser(c.Request.Context(), userID, roleID)
+func (h *Handler) RemoveProjectRole(c *gin.Context) {
+	projectID, err := ulid.Parse(c.Param("projectId"))
 	if err != nil {
-		if strings.Contains(err.Error(), "Enterprise license") {
-			response.PaymentRequired(c, err.Error())
-			return
-		}
-		response.InternalServerError(c, err.Error())
+		response.ValidationError(c, "Invalid project ID", err.Error())
 		return
 	}
-
-	response.Success(c, gin.H{"message": "Role assigned successfully"})
-}
-
-func (h *Handler) UnassignRole(c *gin.Context) {
-	roleID := c.Param("id")
-	userID := c.Param("user_id")
-
-	err := h.rbacService.RemoveRoleFromUser(c.Request.Context(), userID, roleID)
+	userID, err := ulid.Parse(c.Param("userId"))
 	if err != nil {
-		response.InternalServerError(c, err.Error())
+		response.ValidationError(c, "Invalid user ID", err.Error())
 		return
 	}
 
-	response.Success(c, gin.H{"message": "Role unassigned successfully"})
-}
-
-func (h *Handler) GetUserPermissions(c *gin.Context) {
-	userID := c.Param("id")
-
-	permissions, err := h.rbacService.GetUserPermissions(c.Request.Context(), userID)
-	if err != nil {
-		response.InternalServerError(c, err.Error())
+	if err := h.rbacService.RemoveProjectRole(c.Request.Context(), projectID, userID); err != nil {
+		if strings.Contains(err.Error(), "Enterprise license") {
+			response.PaymentRequired(c, err.Error())
+			return
+		}
+		response.Error(c, err)
 		return
 	}
 
-	response.Success(c, gin.H{"permissions": permissions})
+	response.Success(c, gin.H{"message": "Project role removed successfully"})
 }
 
 // Compliance Handlers
@@ -383,9 +355,31 @@ func (h *Handler) ValidateCompliance(c *gin.Context) {
 }
 
 func (h *Handler) GenerateAuditReport(c *gin.Context) {
-	report, err := h.complianceService.GenerateAuditReport(c.Request.Context())
+	orgID, err := ulid.Parse(c.Query("org_id"))
 	if err != nil {
-		response.InternalServerError(c, err.Error())
+		response.ValidationError(c, "Invalid org_id", err.Error())
+		return
+	}
+
+	// Defaults to the last 30 days
+	to := time.Now().UTC()
+	from := to.AddDate(0, 0, -30)
+	if value := c.Query("start"); value != "" {
+		if from, err = time.Parse(time.RFC3339, value); err != nil {
+			response.ValidationError(c, "Invalid start", err.Error())
+			return
+		}
+	}
+	if value := c.Query("end"); value != "" {
+		if to, err = time.Parse(time.RFC3339, value); err != nil {
+			response.ValidationError(c, "Invalid end", err.Error())
+			return
+		}
+	}
+
+	report, err := h.complianceService.GenerateAuditReport(c.Request.Context(), orgID, from, to)
+	if err != nil {
+		response.Error(c, err)
 		return
 	}
 
@@ -456,7 +450,7 @@ func (h *Handler) CheckGDPRCompliance(c *gin.Context) {
 		"status": map[string]interface{}{
 			"compliant":    compliant,
 			"framework":    "GDPR",
-			"last_checked": "2024-01-01T00:00:00Z",
+			"last_checked": time.Now().UTC(),
 		},
 	})
 }
@@ -75,7 +75,7 @@ func (h *EvaluatorHandler) Create(c *gin.Context) {
 // @Param page query int false "Page number (default 1)"
 // @Param limit query int false "Items per page (10, 25, 50, 100; default 50)"
 // @Param status query string false "Filter by status (active, inactive, paused)"
-// @Param scorer_type query string false "Filter by scorer type (llm, builtin, regex)"
+// @Param scorer_type query string false "Filter by scorer type (llm, builtin, regex, pairwise)"
 // @Param search query string false "Search by name"
 // @Success 200 {object} response.ListResponse{data=[]evaluation.EvaluatorResponse}
 // @Failure 400 {object} response.ErrorResponse
@@ -367,6 +367,47 @@ func (h *EvaluatorHandler) Trigger(c *gin.Context) {
 	response.Accepted(c, result)
 }
 
+// @Summary Trigger pairwise evaluator
+// @Description Runs a pairwise evaluator on every pair of the given experiments (for the dataset items they share) or on explicit span pairs. The judge messages can use {input}, {expected}, {output_a} and {output_b} and must answer {"winner": "A" | "B" | "tie", "reason": "..."}. Each pair is judged in both orders unless scorer_config.position_swap is false, and win/lose/tie scores are written to both sides. Returns 202 Accepted with execution ID for async processing.
+// @Tags Evaluators
+// @Accept json
+// @Produce json
+// @Param projectId path string true "Project ID"
+// @Param evaluatorId path string true "Evaluator ID"
+// @Param request body evaluation.PairwiseTriggerRequest true "Experiments or span pairs to compare"
+// @Success 202 {object} evaluation.TriggerResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/evaluators/{evaluatorId}/pairwise [post]
+func (h *EvaluatorHandler) TriggerPairwise(c *gin.Context) {
+	projectID, err := ulid.Parse(c.Param("projectId"))
+	if err != nil {
+		response.Error(c, appErrors.NewValidationError("projectId", "must be a valid ULID"))
+		return
+	}
+
+	evaluatorID, err := ulid.Parse(c.Param("evaluatorId"))
+	if err != nil {
+		response.Error(c, appErrors.NewValidationError("evaluatorId", "must be a valid ULID"))
+		return
+	}
+
+	var req evaluationDomain.PairwiseTriggerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.service.TriggerPairwise(c.Request.Context(), evaluatorID, projectID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Accepted(c, result)
+}
+
 // @Summary Test evaluator
 // @Description Tests an evaluator against sample spans without persisting scores. Useful for validating evaluator configuration before activation.
 // @Tags Evaluators
@@ -2,6 +2,7 @@ package evaluation
 
 import (
 	"log/slog"
+	"net/http"
 	"strconv"
 	"strings"
 
@@ -296,6 +297,9 @@ func (h *ExperimentHandler) Delete(c *gin.Context) {
 
 // @Summary Compare experiments
 // @Description Compares score metrics across multiple experiments. Optionally specify a baseline for diff calculations.
+// @Description With a baseline, every score is also compared item by item over the dataset items both experiments scored:
+// @Description bootstrap confidence interval, paired t-test and Wilcoxon p-values for numeric scores, McNemar for boolean
+// @Description and McNemar-Bowker for categorical scores, win/tie/loss counts and a significant regression flag.
 // @Tags Experiments, SDK - Experiments
 // @Accept json
 // @Produce json
@@ -388,9 +392,57 @@ func (h *ExperimentHandler) CompareExperiments(c *gin.Context) {
 		}
 	}
 
+	if result.Comparisons != nil {
+		resp.Comparisons = make(map[string]map[string]*PairedComparisonResponse)
+		for scoreName, expComparisons := range result.Comparisons {
+			resp.Comparisons[scoreName] = make(map[string]*PairedComparisonResponse)
+			for expID, comparison := range expComparisons {
+				resp.Comparisons[scoreName][expID] = toPairedComparisonResponse(comparison)
+			}
+		}
+	}
+	resp.SignificantRegression = result.SignificantRegression
+
 	response.Success(c, resp)
 }
 
+// @Summary Get pairwise leaderboard
+// @Description Ranks experiments by the pairwise (A/B preference) judge scores between them, with win/lose/tie counts,
+// @Description an Elo rating and a Bradley-Terry rating on the same scale. Only matches among the given experiments count.
+// @Tags Experiments, SDK - Experiments
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param projectId path string false "Project ID (Dashboard routes)"
+// @Param request body evaluation.PairwiseLeaderboardRequest true "Experiments to rank"
+// @Success 200 {object} evaluation.PairwiseLeaderboard
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse "Experiment not found"
+// @Router /api/v1/projects/{projectId}/experiments/leaderboard [post]
+// @Router /v1/experiments/leaderboard [post]
+func (h *ExperimentHandler) GetPairwiseLeaderboard(c *gin.Context) {
+	projectID, err := extractProjectID(c)
+	if err != nil {
+		response.Error(c, appErrors.NewValidationError("projectId", "must be a valid ULID"))
+		return
+	}
+
+	var req evaluationDomain.PairwiseLeaderboardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Invalid request body", err.Error())
+		return
+	}
+
+	leaderboard, err := h.service.GetPairwiseLeaderboard(c.Request.Context(), projectID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, leaderboard)
+}
+
 // @Summary Batch create experiment items via SDK
 // @Description Creates multiple items for an experiment using API key authentication.
 // @Tags SDK - Experiments
@@ -552,3 +604,127 @@ func (h *ExperimentHandler) GetMetrics(c *gin.Context) {
 
 	response.Success(c, metrics)
 }
+
+// @Summary Set experiment gates
+// @Description Replaces the CI gates of an experiment. Gates are pass/fail assertions evaluated when the experiment finishes:
+// @Description score_mean and score_rate thresholds, cost_per_item, error_rate, and no_regression against a baseline experiment.
+// @Description A finished experiment is evaluated against the new gates right away.
+// @Tags Experiments, SDK - Experiments
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param projectId path string false "Project ID (Dashboard routes)"
+// @Param experimentId path string true "Experiment ID"
+// @Param request body evaluation.SetExperimentGatesRequest true "Gates"
+// @Success 200 {object} evaluation.ExperimentResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/experiments/{experimentId}/gates [put]
+// @Router /v1/experiments/{experimentId}/gates [put]
+func (h *ExperimentHandler) SetGates(c *gin.Context) {
+	projectID, err := extractProjectID(c)
+	if err != nil {
+		response.Error(c, appErrors.NewValidationError("projectId", "must be a valid ULID"))
+		return
+	}
+
+	experimentID, err := ulid.Parse(c.Param("experimentId"))
+	if err != nil {
+		response.Error(c, appErrors.NewValidationError("experimentId", "must be a valid ULID"))
+		return
+	}
+
+	var req evaluationDomain.SetExperimentGatesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Invalid request body", err.Error())
+		return
+	}
+
+	experiment, err := h.service.SetGates(c.Request.Context(), experimentID, projectID, req.Gates)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, experiment.ToResponse())
+}
+
+// @Summary Get experiment gate verdict
+// @Description Returns the pass/fail verdict of the experiment's gates for CI pipelines. The status is pending until the experiment finishes.
+// @Description With format=junit the verdict is returned as JUnit XML, one test case per gate.
+// @Tags Experiments, SDK - Experiments
+// @Produce json,xml
+// @Security ApiKeyAuth
+// @Param projectId path string false "Project ID (Dashboard routes)"
+// @Param experimentId path string true "Experiment ID"
+// @Param format query string false "Response format" Enums(json, junit) default(json)
+// @Success 200 {object} evaluation.GateVerdict
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/experiments/{experimentId}/verdict [get]
+// @Router /v1/experiments/{experimentId}/verdict [get]
+func (h *ExperimentHandler) GetVerdict(c *gin.Context) {
+	projectID, err := extractProjectID(c)
+	if err != nil {
+		response.Error(c, appErrors.NewValidationError("projectId", "must be a valid ULID"))
+		return
+	}
+
+	experimentID, err := ulid.Parse(c.Param("experimentId"))
+	if err != nil {
+		response.Error(c, appErrors.NewValidationError("experimentId", "must be a valid ULID"))
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "junit" {
+		response.Error(c, appErrors.NewValidationError("format", "must be json or junit"))
+		return
+	}
+
+	verdict, err := h.service.GetVerdict(c.Request.Context(), experimentID, projectID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if format == "junit" {
+		data, err := renderJUnitVerdict(verdict)
+		if err != nil {
+			response.Error(c, appErrors.NewInternalError("failed to render JUnit report", err))
+			return
+		}
+		c.Header("Content-Disposition", "attachment; filename=experiment-"+experimentID.String()+"-junit.xml")
+		c.Data(http.StatusOK, "application/xml", data)
+		return
+	}
+
+	response.Success(c, verdict)
+}
+
+func toPairedComparisonResponse(comparison *evaluationDomain.PairedComparison) *PairedComparisonResponse {
+	resp := &PairedComparisonResponse{
+		Type:                  string(comparison.Type),
+		Test:                  comparison.Test,
+		PairedItems:           comparison.PairedItems,
+		MeanDifference:        comparison.MeanDifference,
+		PValue:                comparison.PValue,
+		WilcoxonPValue:        comparison.WilcoxonPValue,
+		Wins:                  comparison.Wins,
+		Ties:                  comparison.Ties,
+		Losses:                comparison.Losses,
+		Changed:               comparison.Changed,
+		Significant:           comparison.Significant,
+		SignificantRegression: comparison.SignificantRegression,
+	}
+	if ci := comparison.ConfidenceInterval; ci != nil {
+		resp.ConfidenceInterval = &ConfidenceIntervalResponse{
+			Level: ci.Level,
+			Lower: ci.Lower,
+			Upper: ci.Upper,
+		}
+	}
+	return resp
+}
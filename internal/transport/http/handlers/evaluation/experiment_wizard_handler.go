@@ -206,3 +206,76 @@ func (h *ExperimentWizardHandler) GetExperimentConfig(c *gin.Context) {
 
 	response.Success(c, config.ToResponse())
 }
+
+// @Summary Cancel experiment
+// @Description Stops a pending or running dashboard experiment. Items already executed are kept.
+// @Tags Experiments
+// @Produce json
+// @Security BearerAuth
+// @Param projectId path string true "Project ID"
+// @Param experimentId path string true "Experiment ID"
+// @Success 200 {object} evaluation.ExperimentResponse
+// @Failure 400 {object} response.ErrorResponse "Validation error"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 404 {object} response.ErrorResponse "Experiment not found"
+// @Failure 409 {object} response.ErrorResponse "Experiment already finished"
+// @Router /api/v1/projects/{projectId}/experiments/{experimentId}/cancel [post]
+func (h *ExperimentWizardHandler) CancelExperiment(c *gin.Context) {
+	projectID, err := extractProjectID(c)
+	if err != nil {
+		response.Error(c, appErrors.NewValidationError("projectId", "must be a valid ULID"))
+		return
+	}
+
+	experimentIDStr := c.Param("experimentId")
+	experimentID, err := ulid.Parse(experimentIDStr)
+	if err != nil {
+		response.Error(c, appErrors.NewValidationError("experimentId", "must be a valid ULID"))
+		return
+	}
+
+	experiment, err := h.service.CancelExperiment(c.Request.Context(), experimentID, projectID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, experiment.ToResponse())
+}
+
+// @Summary Resume experiment
+// @Description Queues a pending, cancelled or failed dashboard experiment for execution.
+// @Description Dataset items that already have a result are skipped.
+// @Tags Experiments
+// @Produce json
+// @Security BearerAuth
+// @Param projectId path string true "Project ID"
+// @Param experimentId path string true "Experiment ID"
+// @Success 200 {object} evaluation.ExperimentResponse
+// @Failure 400 {object} response.ErrorResponse "Validation error"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 404 {object} response.ErrorResponse "Experiment not found"
+// @Failure 409 {object} response.ErrorResponse "Experiment already running or completed"
+// @Router /api/v1/projects/{projectId}/experiments/{experimentId}/resume [post]
+func (h *ExperimentWizardHandler) ResumeExperiment(c *gin.Context) {
+	projectID, err := extractProjectID(c)
+	if err != nil {
+		response.Error(c, appErrors.NewValidationError("projectId", "must be a valid ULID"))
+		return
+	}
+
+	experimentIDStr := c.Param("experimentId")
+	experimentID, err := ulid.Parse(experimentIDStr)
+	if err != nil {
+		response.Error(c, appErrors.NewValidationError("experimentId", "must be a valid ULID"))
+		return
+	}
+
+	experiment, err := h.service.ResumeExperiment(c.Request.Context(), experimentID, projectID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, experiment.ToResponse())
+}
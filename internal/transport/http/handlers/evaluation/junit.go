@@ -0,0 +1,90 @@
+package evaluation
+
+import (
+	"encoding/xml"
+	"fmt"
+
+	evaluationDomain "brokle/internal/core/domain/evaluation"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// renderJUnitVerdict renders a gate verdict as a JUnit XML report with one
+// test case per gate. An unfinished, failed or cancelled experiment adds a
+// failing "experiment" case so CI fails the build until the verdict passes.
+func renderJUnitVerdict(verdict *evaluationDomain.GateVerdict) ([]byte, error) {
+	className := "brokle.experiments." + verdict.ExperimentName
+	suite := junitTestSuite{Name: verdict.ExperimentName}
+	if verdict.EvaluatedAt != nil {
+		suite.Timestamp = verdict.EvaluatedAt.UTC().Format("2006-01-02T15:04:05")
+	}
+
+	if verdict.Status == evaluationDomain.GateVerdictPending || (!verdict.Passed && verdict.Reason != "") {
+		suite.Cases = append(suite.Cases, junitTestCase{
+			Name:      "experiment",
+			ClassName: className,
+			Failure:   &junitFailure{Message: verdict.Reason, Type: string(verdict.Status), Text: verdict.Reason},
+		})
+	}
+
+	for _, result := range verdict.Results {
+		testCase := junitTestCase{Name: result.Name, ClassName: className}
+		if result.Passed {
+			testCase.SystemOut = result.Message
+		} else {
+			testCase.Failure = &junitFailure{
+				Message: result.Message,
+				Type:    "gate_failed",
+				Text:    fmt.Sprintf("%s: %s", result.Name, result.Message),
+			}
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+
+	for _, testCase := range suite.Cases {
+		suite.Tests++
+		if testCase.Failure != nil {
+			suite.Failures++
+		}
+	}
+
+	report := junitTestSuites{
+		Name:     "brokle",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Suites:   []junitTestSuite{suite},
+	}
+
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
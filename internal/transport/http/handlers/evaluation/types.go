@@ -195,6 +195,30 @@ type ScoreDiffResponse struct {
 	Direction  string  `json:"direction,omitempty"`
 }
 
+// @Description Bootstrap confidence interval of the mean paired difference
+type ConfidenceIntervalResponse struct {
+	Level float64 `json:"level"`
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+}
+
+// @Description Paired comparison of a score against the baseline over shared dataset items
+type PairedComparisonResponse struct {
+	Type                  string                      `json:"type"`
+	Test                  string                      `json:"test"`
+	PairedItems           int                         `json:"paired_items"`
+	MeanDifference        float64                     `json:"mean_difference"`
+	ConfidenceInterval    *ConfidenceIntervalResponse `json:"confidence_interval,omitempty"`
+	PValue                *float64                    `json:"p_value,omitempty"`
+	WilcoxonPValue        *float64                    `json:"wilcoxon_p_value,omitempty"`
+	Wins                  int                         `json:"wins"`
+	Ties                  int                         `json:"ties"`
+	Losses                int                         `json:"losses"`
+	Changed               int                         `json:"changed,omitempty"`
+	Significant           bool                        `json:"significant"`
+	SignificantRegression bool                        `json:"significant_regression"`
+}
+
 // @Description Experiment summary for comparison
 type ExperimentSummaryResponse struct {
 	Name   string `json:"name"`
@@ -206,4 +230,6 @@ type CompareExperimentsResponse struct {
 	Experiments map[string]*ExperimentSummaryResponse              `json:"experiments"`
 	Scores      map[string]map[string]*ScoreAggregationResponse    `json:"scores"`
 	Diffs       map[string]map[string]*ScoreDiffResponse           `json:"diffs,omitempty"`
+	Comparisons map[string]map[string]*PairedComparisonResponse    `json:"comparisons,omitempty"`
+	SignificantRegression bool                                     `json:"significant_regression"`
 }
@@ -16,14 +16,18 @@ import (
 	playgroundDomain "brokle/internal/core/domain/playground"
 	promptDomain "brokle/internal/core/domain/prompt"
 	"brokle/internal/core/domain/user"
+	webhookDomain "brokle/internal/core/domain/webhook"
 	authService "brokle/internal/core/services/auth"
 	credentialsService "brokle/internal/core/services/credentials"
 	obsServices "brokle/internal/core/services/observability"
 	"brokle/internal/core/services/registration"
+	eeRBAC "brokle/internal/ee/rbac"
+	"brokle/internal/ee/sso"
 	"brokle/internal/transport/http/handlers/admin"
 	"brokle/internal/transport/http/handlers/analytics"
 	annotationHandler "brokle/internal/transport/http/handlers/annotation"
 	"brokle/internal/transport/http/handlers/apikey"
+	auditHandler "brokle/internal/transport/http/handlers/audit"
 	authHandler "brokle/internal/transport/http/handlers/auth"
 	"brokle/internal/transport/http/handlers/billing"
 	commentHandler "brokle/internal/transport/http/handlers/comment"
@@ -40,7 +44,9 @@ import (
 	"brokle/internal/transport/http/handlers/project"
 	"brokle/internal/transport/http/handlers/prompt"
 	"brokle/internal/transport/http/handlers/rbac"
+	scimHandler "brokle/internal/transport/http/handlers/scim"
 	userHandler "brokle/internal/transport/http/handlers/user"
+	webhookHandler "brokle/internal/transport/http/handlers/webhook"
 	"brokle/internal/transport/http/handlers/websocket"
 )
 
@@ -90,6 +96,12 @@ type Handlers struct {
 	AnnotationAssignment *annotationHandler.AssignmentHandler
 	// Comment handlers
 	Comment *commentHandler.Handler
+	// Webhook subscription handlers
+	Webhook *webhookHandler.Handler
+	// SCIM 2.0 provisioning
+	SCIM *scimHandler.Handler
+	// Organization audit log
+	Audit *auditHandler.Handler
 }
 
 func NewHandlers(
@@ -97,6 +109,7 @@ func NewHandlers(
 	logger *slog.Logger,
 	authSvc auth.AuthService,
 	apiKeyService auth.APIKeyService,
+	rateLimitService auth.RateLimitService,
 	blacklistedTokens auth.BlacklistedTokenService,
 	registrationService registration.RegistrationService,
 	oauthProvider *authService.OAuthProviderService,
@@ -133,6 +146,7 @@ func NewHandlers(
 	// Usage-based billing services
 	usageService billingDomain.BillableUsageService,
 	budgetService billingDomain.BudgetService,
+	enforcementService billingDomain.EnforcementService,
 	// Enterprise custom pricing services
 	contractService billingDomain.ContractService,
 	pricingService billingDomain.PricingService,
@@ -142,23 +156,34 @@ func NewHandlers(
 	annotationAssignmentService annotationDomain.AssignmentService,
 	// Comment service
 	commentService commentDomain.Service,
+	// Webhook subscription service
+	webhookService webhookDomain.Service,
+	// Enterprise SSO (stub when unlicensed)
+	ssoProvider sso.SSOProvider,
+	// SCIM provisioning service
+	scimService auth.SCIMService,
+	// Organization audit log
+	auditLogService auth.AuditLogService,
+	// Per-project roles: effective permissions and enterprise assignment (stub when unlicensed)
+	projectAccessService auth.ProjectAccessService,
+	projectRoles eeRBAC.RBACManager,
 ) *Handlers {
 	return &Handlers{
 		Health:        health.NewHandler(cfg, logger),
 		Metrics:       metrics.NewHandler(cfg, logger),
-		Auth:          authHandler.NewHandler(cfg, logger, authSvc, apiKeyService, userService, registrationService, oauthProvider),
-		User:          userHandler.NewHandler(cfg, logger, userService, profileService, organizationService),
+		Auth:          authHandler.NewHandler(cfg, logger, authSvc, apiKeyService, userService, registrationService, oauthProvider, ssoProvider),
+		User:          userHandler.NewHandler(cfg, logger, userService, profileService, organizationService, projectAccessService),
 		Organization:  organizationHandler.NewHandler(cfg, logger, organizationService, memberService, projectService, invitationService, settingsService, userService, roleService),
-		Project:       project.NewHandler(cfg, logger, projectService, organizationService, memberService),
-		APIKey:        apikey.NewHandler(cfg, logger, apiKeyService),
+		Project:       project.NewHandler(cfg, logger, projectService, organizationService, memberService, projectAccessService),
+		APIKey:        apikey.NewHandler(cfg, logger, apiKeyService, rateLimitService),
 		Analytics:     analytics.NewHandler(cfg, logger),
 		Logs:          logs.NewHandler(cfg, logger),
 		Billing:       billing.NewHandler(cfg, logger),
 		WebSocket:     websocket.NewHandler(cfg, logger),
 		Admin:         admin.NewTokenAdminHandler(authSvc, blacklistedTokens, logger),
-		RBAC:          rbac.NewHandler(cfg, logger, roleService, permissionService, organizationMemberService, scopeService),
-		Observability: observability.NewHandler(cfg, logger, observabilityServices),
-		OTLP:          observability.NewOTLPHandler(observabilityServices.StreamProducer, observabilityServices.DeduplicationService, observabilityServices.OTLPConverterService, logger),
+		RBAC:          rbac.NewHandler(cfg, logger, roleService, permissionService, organizationMemberService, scopeService, projectAccessService, projectRoles),
+		Observability: observability.NewHandler(cfg, logger, observabilityServices, organizationMemberService),
+		OTLP:          observability.NewOTLPHandler(observabilityServices.StreamProducer, observabilityServices.DeduplicationService, observabilityServices.OTLPConverterService, enforcementService, observabilityServices.SamplingService, rateLimitService, logger),
 		OTLPMetrics:   observability.NewOTLPMetricsHandler(observabilityServices.StreamProducer, observabilityServices.OTLPMetricsConverterService, logger),
 		OTLPLogs:      observability.NewOTLPLogsHandler(observabilityServices.StreamProducer, observabilityServices.OTLPLogsConverterService, observabilityServices.OTLPEventsConverterService, logger),
 		Prompt:        prompt.NewHandler(cfg, logger, promptService, compilerService),
@@ -189,5 +214,11 @@ func NewHandlers(
 		AnnotationAssignment: annotationHandler.NewAssignmentHandler(logger, annotationAssignmentService),
 		// Comment handler
 		Comment: commentHandler.NewHandler(commentService),
+		// Webhook handler
+		Webhook: webhookHandler.NewHandler(logger, webhookService),
+		// SCIM provisioning
+		SCIM: scimHandler.NewHandler(logger, scimService),
+		// Audit log
+		Audit: auditHandler.NewHandler(logger, auditLogService, organizationMemberService),
 	}
 }
@@ -100,7 +100,7 @@ type ExportResponse struct {
 // @Accept json
 // @Produce json
 // @Param organization_id query string false "Filter by organization ID" example("org_1234567890")
-// @Param project_id query string false "Filter by project ID" example("proj_1234567890")
+// @Param project_id query string true "Project ID" example("01K4FHGHT3XX9WFM293QPZ5G9V")
 // @Param environment_id query string false "Filter by environment ID" example("env_1234567890")
 // @Param provider query string false "Filter by AI provider" example("openai")
 // @Param model query string false "Filter by AI model" example("gpt-4")
@@ -155,7 +155,7 @@ func (h *Handler) GetRequest(c *gin.Context) {
 // @Param start_time query string true "Start time for export (RFC3339)" example("2024-01-01T00:00:00Z")
 // @Param end_time query string true "End time for export (RFC3339)" example("2024-01-01T23:59:59Z")
 // @Param organization_id query string false "Filter by organization ID" example("org_1234567890")
-// @Param project_id query string false "Filter by project ID" example("proj_1234567890")
+// @Param project_id query string true "Project ID" example("01K4FHGHT3XX9WFM293QPZ5G9V")
 // @Param environment_id query string false "Filter by environment ID" example("env_1234567890")
 // @Param provider query string false "Filter by AI provider" example("openai")
 // @Param model query string false "Filter by AI model" example("gpt-4")
@@ -0,0 +1,241 @@
+package observability
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"brokle/internal/core/domain/observability"
+	"brokle/internal/transport/http/middleware"
+	"brokle/pkg/response"
+)
+
+// CreateAlertRule creates a new alert rule.
+// @Summary Create alert rule
+// @Description Create an alert rule that compares an aggregation over a filter expression or saved filter preset with a threshold
+// @Tags alerts
+// @Accept json
+// @Produce json
+// @Param projectId path string true "Project ID"
+// @Param request body observability.CreateAlertRuleRequest true "Create alert rule request"
+// @Success 201 {object} AlertRuleWithSecret
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/alerts [post]
+func (h *Handler) CreateAlertRule(c *gin.Context) {
+	projectID := c.Param("projectId")
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "user not authenticated")
+		return
+	}
+
+	var req observability.CreateAlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	rule, err := h.services.AlertService.Create(c.Request.Context(), projectID, userID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, &AlertRuleWithSecret{AlertRule: rule, WebhookSecret: rule.WebhookSecret})
+}
+
+// AlertRuleWithSecret is an alert rule with the secret its webhook deliveries are signed with.
+// The secret is only returned when the rule is created or the secret is rotated.
+type AlertRuleWithSecret struct {
+	*observability.AlertRule
+	WebhookSecret string `json:"webhook_secret"`
+}
+
+// GetAlertRule retrieves an alert rule by ID.
+// @Summary Get alert rule
+// @Description Retrieve an alert rule, including its current evaluation state
+// @Tags alerts
+// @Produce json
+// @Param projectId path string true "Project ID"
+// @Param id path string true "Alert Rule ID"
+// @Success 200 {object} observability.AlertRule
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/alerts/{id} [get]
+func (h *Handler) GetAlertRule(c *gin.Context) {
+	rule, err := h.services.AlertService.Get(c.Request.Context(), c.Param("projectId"), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, rule)
+}
+
+// UpdateAlertRule updates an alert rule.
+// @Summary Update alert rule
+// @Description Update an alert rule. Changing the condition or disabling the rule resets its state to ok.
+// @Tags alerts
+// @Accept json
+// @Produce json
+// @Param projectId path string true "Project ID"
+// @Param id path string true "Alert Rule ID"
+// @Param request body observability.UpdateAlertRuleRequest true "Update alert rule request"
+// @Success 200 {object} observability.AlertRule
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/alerts/{id} [patch]
+func (h *Handler) UpdateAlertRule(c *gin.Context) {
+	projectID := c.Param("projectId")
+	ruleID := c.Param("id")
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "user not authenticated")
+		return
+	}
+
+	var req observability.UpdateAlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	rule, err := h.services.AlertService.Update(c.Request.Context(), projectID, ruleID, userID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, rule)
+}
+
+// RotateAlertWebhookSecret replaces the secret the rule's webhook deliveries are signed with.
+// @Summary Rotate alert webhook secret
+// @Description Generates a new signing secret for webhook channels. The previous secret stops working immediately.
+// @Tags alerts
+// @Produce json
+// @Param projectId path string true "Project ID"
+// @Param id path string true "Alert Rule ID"
+// @Success 200 {object} AlertRuleWithSecret
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/alerts/{id}/rotate-secret [post]
+func (h *Handler) RotateAlertWebhookSecret(c *gin.Context) {
+	rule, err := h.services.AlertService.RotateWebhookSecret(c.Request.Context(), c.Param("projectId"), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, &AlertRuleWithSecret{AlertRule: rule, WebhookSecret: rule.WebhookSecret})
+}
+
+// DeleteAlertRule deletes an alert rule and its history.
+// @Summary Delete alert rule
+// @Description Delete an alert rule and its notification history
+// @Tags alerts
+// @Param projectId path string true "Project ID"
+// @Param id path string true "Alert Rule ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/alerts/{id} [delete]
+func (h *Handler) DeleteAlertRule(c *gin.Context) {
+	if err := h.services.AlertService.Delete(c.Request.Context(), c.Param("projectId"), c.Param("id")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListAlertRules lists alert rules for a project.
+// @Summary List alert rules
+// @Description List all alert rules for a project
+// @Tags alerts
+// @Produce json
+// @Param projectId path string true "Project ID"
+// @Success 200 {array} observability.AlertRule
+// @Router /api/v1/projects/{projectId}/alerts [get]
+func (h *Handler) ListAlertRules(c *gin.Context) {
+	rules, err := h.services.AlertService.List(c.Request.Context(), c.Param("projectId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, rules)
+}
+
+// SilenceAlertRule suppresses notifications for an alert rule.
+// @Summary Silence alert rule
+// @Description Suppress notifications for a duration. The rule is still evaluated and transitions are recorded as silenced.
+// @Tags alerts
+// @Accept json
+// @Produce json
+// @Param projectId path string true "Project ID"
+// @Param id path string true "Alert Rule ID"
+// @Param request body observability.SilenceAlertRuleRequest true "Silence request"
+// @Success 200 {object} observability.AlertRule
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/alerts/{id}/silence [post]
+func (h *Handler) SilenceAlertRule(c *gin.Context) {
+	var req observability.SilenceAlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	rule, err := h.services.AlertService.Silence(c.Request.Context(), c.Param("projectId"), c.Param("id"), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, rule)
+}
+
+// UnsilenceAlertRule resumes notifications for an alert rule.
+// @Summary Unsilence alert rule
+// @Description Clear an active silence so notifications resume on the next transition
+// @Tags alerts
+// @Produce json
+// @Param projectId path string true "Project ID"
+// @Param id path string true "Alert Rule ID"
+// @Success 200 {object} observability.AlertRule
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/alerts/{id}/silence [delete]
+func (h *Handler) UnsilenceAlertRule(c *gin.Context) {
+	rule, err := h.services.AlertService.Unsilence(c.Request.Context(), c.Param("projectId"), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, rule)
+}
+
+// ListAlertHistory lists state transitions and notifications for an alert rule.
+// @Summary List alert history
+// @Description List state transitions of an alert rule, newest first, with whether each was notified or silenced
+// @Tags alerts
+// @Produce json
+// @Param projectId path string true "Project ID"
+// @Param id path string true "Alert Rule ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" Enums(10,25,50,100) default(50)
+// @Success 200 {array} observability.AlertEvent
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/alerts/{id}/history [get]
+func (h *Handler) ListAlertHistory(c *gin.Context) {
+	params := response.ParsePaginationParams(c.Query("page"), c.Query("limit"), "", "")
+	offset := (params.Page - 1) * params.Limit
+
+	events, total, err := h.services.AlertService.ListHistory(c.Request.Context(), c.Param("projectId"), c.Param("id"), params.Limit, offset)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithPagination(c, events, response.NewPagination(params.Page, params.Limit, total))
+}
@@ -0,0 +1,57 @@
+package observability
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"brokle/internal/core/domain/observability"
+	appErrors "brokle/pkg/errors"
+	"brokle/pkg/response"
+)
+
+// RehydrateArchive restores archived telemetry of a project from S3 into ClickHouse.
+// @Summary Rehydrate archived telemetry
+// @Description Re-ingest archived Parquet files for a date range of up to 7 days. Spans that still exist are skipped. Use dry_run to count records without writing. Longer ranges are restored with `migrate rehydrate`.
+// @Tags archive
+// @Accept json
+// @Produce json
+// @Param projectId path string true "Project ID"
+// @Param request body observability.RehydrateRequest true "Signals and date range to restore"
+// @Success 200 {object} observability.RehydrateResult
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 503 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/telemetry-archive/rehydrate [post]
+func (h *Handler) RehydrateArchive(c *gin.Context) {
+	if h.services.RehydrationService == nil {
+		response.Error(c, appErrors.NewServiceUnavailableError("Archive storage is not configured"))
+		return
+	}
+
+	projectID, err := parseRetentionID(c, "projectId")
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var req observability.RehydrateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+	req.ProjectID = projectID.String()
+	if req.To.Sub(req.From) > time.Duration(observability.MaxAPIRehydrateDays)*24*time.Hour {
+		response.Error(c, appErrors.NewValidationError("to", fmt.Sprintf("date range must not exceed %d days; use `migrate rehydrate` for longer ranges", observability.MaxAPIRehydrateDays)))
+		return
+	}
+
+	result, err := h.services.RehydrationService.Rehydrate(c.Request.Context(), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
@@ -0,0 +1,167 @@
+package observability
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"brokle/internal/core/domain/observability"
+	"brokle/internal/transport/http/middleware"
+	"brokle/pkg/response"
+)
+
+// CreateDataSubjectErasure queues the erasure of an end user's data.
+// @Summary Request data subject erasure
+// @Description Queue the GDPR erasure of everything stored for an end user (the user.id span attribute) in the project:
+// @Description spans, scores, logs and GenAI events of their traces, comments on the traces, dataset items sourced from them
+// @Description and archived Parquet records. The request runs asynchronously; poll it for progress and the final certificate.
+// @Tags data-subjects
+// @Accept json
+// @Produce json
+// @Param projectId path string true "Project ID"
+// @Param request body observability.CreateDataSubjectRequest true "End user"
+// @Success 202 {object} observability.DataSubjectRequestResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/data-subject-requests/erasure [post]
+func (h *Handler) CreateDataSubjectErasure(c *gin.Context) {
+	h.createDataSubjectRequest(c, observability.DataSubjectRequestErasure)
+}
+
+// CreateDataSubjectExport queues the export of an end user's data.
+// @Summary Request data subject export
+// @Description Queue a GDPR export of everything stored for an end user in the project. Once the request completes,
+// @Description the JSON bundle is downloaded from its bundle endpoint.
+// @Tags data-subjects
+// @Accept json
+// @Produce json
+// @Param projectId path string true "Project ID"
+// @Param request body observability.CreateDataSubjectRequest true "End user"
+// @Success 202 {object} observability.DataSubjectRequestResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/data-subject-requests/export [post]
+func (h *Handler) CreateDataSubjectExport(c *gin.Context) {
+	h.createDataSubjectRequest(c, observability.DataSubjectRequestExport)
+}
+
+func (h *Handler) createDataSubjectRequest(c *gin.Context, reqType observability.DataSubjectRequestType) {
+	projectID, err := parseRetentionID(c, "projectId")
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	userID, exists := middleware.GetUserIDULID(c)
+	if !exists {
+		response.Unauthorized(c, "user not authenticated")
+		return
+	}
+
+	var req observability.CreateDataSubjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	request, err := h.services.DataSubjectService.CreateRequest(c.Request.Context(), projectID, &userID, reqType, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Accepted(c, request)
+}
+
+// ListDataSubjectRequests lists the data subject requests of a project.
+// @Summary List data subject requests
+// @Description List erasure and export requests of a project, newest first
+// @Tags data-subjects
+// @Produce json
+// @Param projectId path string true "Project ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" Enums(10,25,50,100) default(50)
+// @Success 200 {array} observability.DataSubjectRequestResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/data-subject-requests [get]
+func (h *Handler) ListDataSubjectRequests(c *gin.Context) {
+	projectID, err := parseRetentionID(c, "projectId")
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	params := response.ParsePaginationParams(c.Query("page"), c.Query("limit"), "", "")
+	offset := (params.Page - 1) * params.Limit
+
+	requests, total, err := h.services.DataSubjectService.ListRequests(c.Request.Context(), projectID, params.Limit, offset)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithPagination(c, requests, response.NewPagination(params.Page, params.Limit, total))
+}
+
+// GetDataSubjectRequest returns a data subject request with its progress.
+// @Summary Get data subject request
+// @Description Get the progress of a data subject request and, once completed, its certificate
+// @Tags data-subjects
+// @Produce json
+// @Param projectId path string true "Project ID"
+// @Param requestId path string true "Request ID"
+// @Success 200 {object} observability.DataSubjectRequestResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/data-subject-requests/{requestId} [get]
+func (h *Handler) GetDataSubjectRequest(c *gin.Context) {
+	projectID, err := parseRetentionID(c, "projectId")
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	requestID, err := parseRetentionID(c, "requestId")
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	request, err := h.services.DataSubjectService.GetRequest(c.Request.Context(), projectID, requestID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, request)
+}
+
+// GetDataSubjectExportBundle downloads the bundle of a completed export.
+// @Summary Download data subject export
+// @Description Download the JSON bundle of a completed export request
+// @Tags data-subjects
+// @Produce json
+// @Param projectId path string true "Project ID"
+// @Param requestId path string true "Request ID"
+// @Success 200 {object} observability.DataSubjectExport
+// @Failure 400 {object} response.ErrorResponse "Not an export request"
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse "Export not completed"
+// @Router /api/v1/projects/{projectId}/data-subject-requests/{requestId}/bundle [get]
+func (h *Handler) GetDataSubjectExportBundle(c *gin.Context) {
+	projectID, err := parseRetentionID(c, "projectId")
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	requestID, err := parseRetentionID(c, "requestId")
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	bundle, err := h.services.DataSubjectService.GetExportBundle(c.Request.Context(), projectID, requestID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=data-subject-export-%s.json", requestID))
+	c.Data(http.StatusOK, "application/json", bundle)
+}
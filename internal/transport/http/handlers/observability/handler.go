@@ -4,6 +4,7 @@ import (
 	"log/slog"
 
 	"brokle/internal/config"
+	authDomain "brokle/internal/core/domain/auth"
 	obsServices "brokle/internal/core/services/observability"
 )
 
@@ -12,6 +13,7 @@ type Handler struct {
 	config   *config.Config
 	logger   *slog.Logger
 	services *obsServices.ServiceRegistry
+	members  authDomain.OrganizationMemberService
 }
 
 // CreateEventRequest represents telemetry event creation request
@@ -45,10 +47,12 @@ func NewHandler(
 	cfg *config.Config,
 	logger *slog.Logger,
 	services *obsServices.ServiceRegistry,
+	members authDomain.OrganizationMemberService,
 ) *Handler {
 	return &Handler{
 		config:   cfg,
 		logger:   logger,
 		services: services,
+		members:  members,
 	}
 }
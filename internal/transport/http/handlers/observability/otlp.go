@@ -17,6 +17,8 @@ import (
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 
+	"brokle/internal/core/domain/auth"
+	"brokle/internal/core/domain/billing"
 	"brokle/internal/core/domain/observability"
 	obsServices "brokle/internal/core/services/observability"
 	"brokle/internal/infrastructure/streams"
@@ -30,6 +32,9 @@ type OTLPHandler struct {
 	streamProducer       *streams.TelemetryStreamProducer
 	deduplicationService observability.TelemetryDeduplicationService
 	otlpConverter        *obsServices.OTLPConverterService
+	enforcementService   billing.EnforcementService
+	samplingService      *obsServices.SamplingService
+	rateLimitService     auth.RateLimitService
 	logger               *slog.Logger
 }
 
@@ -38,12 +43,18 @@ func NewOTLPHandler(
 	streamProducer *streams.TelemetryStreamProducer,
 	deduplicationService observability.TelemetryDeduplicationService,
 	otlpConverter *obsServices.OTLPConverterService,
+	enforcementService billing.EnforcementService,
+	samplingService *obsServices.SamplingService,
+	rateLimitService auth.RateLimitService,
 	logger *slog.Logger,
 ) *OTLPHandler {
 	return &OTLPHandler{
 		streamProducer:       streamProducer,
 		deduplicationService: deduplicationService,
 		otlpConverter:        otlpConverter,
+		enforcementService:   enforcementService,
+		samplingService:      samplingService,
+		rateLimitService:     rateLimitService,
 		logger:               logger,
 	}
 }
@@ -60,6 +71,7 @@ func NewOTLPHandler(
 // @Success 200 {object} response.APIResponse{data=map[string]interface{}} "Traces accepted"
 // @Failure 400 {object} response.APIResponse{error=response.APIError} "Invalid OTLP request"
 // @Failure 401 {object} response.APIResponse{error=response.APIError} "Invalid or missing API key"
+// @Failure 429 {object} response.APIResponse{error=response.APIError} "Usage budget, rate limit or span quota exceeded (see Retry-After)"
 // @Failure 500 {object} response.APIResponse{error=response.APIError} "Internal server error"
 // @Router /v1/traces [post]
 func (h *OTLPHandler) HandleTraces(c *gin.Context) {
@@ -82,6 +94,18 @@ func (h *OTLPHandler) HandleTraces(c *gin.Context) {
 		return
 	}
 
+	// Check budget enforcement before reading the body (cached in Redis, fails open)
+	var enforcement *billing.IngestionEnforcement
+	if h.enforcementService != nil {
+		enforcement = h.enforcementService.GetIngestionEnforcement(ctx, *organizationIDPtr, *projectIDPtr)
+	}
+	if enforcement.Blocks() {
+		h.logger.Warn("OTLP request rejected by budget enforcement", "project_id", projectID, "budget_id", enforcement.BudgetID.String(), "dimension", enforcement.Dimension)
+		response.ErrorWithStatus(c, http.StatusTooManyRequests, "budget_exceeded", enforcement.Reason(),
+			"Raise the budget limit or change its enforcement action to resume ingestion")
+		return
+	}
+
 	// Validate Content-Type header (OTLP specification requires explicit Content-Type)
 	contentType := c.GetHeader("Content-Type")
 	validContentType := strings.Contains(contentType, "application/x-protobuf") ||
@@ -192,6 +216,16 @@ func (h *OTLPHandler) HandleTraces(c *gin.Context) {
 
 	h.logger.Debug("Received OTLP trace request", "project_id", projectID, "resource_spans", len(otlpReq.ResourceSpans))
 
+	// Enforce the per-key and per-project span quota (requests and bytes are checked by middleware)
+	if apiKeyIDPtr, ok := middleware.GetAPIKeyID(c); ok && apiKeyIDPtr != nil && h.rateLimitService != nil {
+		decision, err := h.rateLimitService.Check(ctx, *projectIDPtr, *apiKeyIDPtr, auth.RateLimitCost{Spans: int64(countSpans(&otlpReq))})
+		if err == nil && !decision.Allowed {
+			h.logger.Warn("OTLP request rejected by span quota", "project_id", projectID, "scope", decision.Scope, "limit", decision.Limit)
+			middleware.RateLimitExceeded(c, decision)
+			return
+		}
+	}
+
 	// Convert OTLP spans to Brokle telemetry events using converter service (with cost calculation)
 	brokleEvents, err := h.otlpConverter.ConvertOTLPToBrokleEvents(c.Request.Context(), &otlpReq, projectID)
 	if err != nil {
@@ -202,6 +236,19 @@ func (h *OTLPHandler) HandleTraces(c *gin.Context) {
 
 	h.logger.Debug("Converted OTLP spans to Brokle events", "project_id", projectID, "otlp_spans", countSpans(&otlpReq), "brokle_events", len(brokleEvents))
 
+	// Apply project head sampling, then soft budget enforcement (trace sampling, payload dropping)
+	brokleEvents, headSampledOut := h.samplingService.ApplyHeadSampling(ctx, *projectIDPtr, brokleEvents)
+	brokleEvents, enforced := obsServices.ApplyIngestionEnforcement(brokleEvents, enforcement)
+	if len(brokleEvents) == 0 {
+		h.logger.Info("All OTLP events sampled out", "project_id", projectID, "head_sampled_out", headSampledOut, "sampled_out", enforced.SampledOut)
+
+		response.Success(c, map[string]interface{}{
+			"status":            "sampled",
+			"sampled_out_spans": headSampledOut + enforced.SampledOut,
+		})
+		return
+	}
+
 	// OTLP-native processing: deduplication + Redis Streams publishing
 
 	// 1. Extract composite dedup IDs for spans (trace_id:span_id)
@@ -318,6 +365,14 @@ func (h *OTLPHandler) HandleTraces(c *gin.Context) {
 		},
 		Timestamp: time.Now(),
 	}
+	if headSampledOut > 0 {
+		streamMsg.Metadata["head_sampled_out"] = headSampledOut
+	}
+	if enforcement.Restricts() {
+		streamMsg.Metadata["budget_enforcement_budget_id"] = enforcement.BudgetID.String()
+		streamMsg.Metadata["budget_enforcement_sampled_out"] = enforced.SampledOut
+		streamMsg.Metadata["budget_enforcement_payloads_dropped"] = enforced.PayloadsDropped
+	}
 
 	streamID, err := h.streamProducer.PublishBatch(ctx, streamMsg)
 	if err != nil {
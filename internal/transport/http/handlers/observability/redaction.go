@@ -0,0 +1,93 @@
+package observability
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"brokle/internal/core/domain/observability"
+	"brokle/pkg/response"
+)
+
+// GetProjectRedaction returns the PII redaction policy of a project.
+// @Summary Get project redaction policy
+// @Description Get the PII redaction policy applied to spans at OTLP ingestion. Projects without a policy store spans verbatim.
+// @Tags redaction
+// @Produce json
+// @Param projectId path string true "Project ID"
+// @Success 200 {object} observability.RedactionPolicy
+// @Failure 400 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/redaction [get]
+func (h *Handler) GetProjectRedaction(c *gin.Context) {
+	projectID, err := parseRetentionID(c, "projectId")
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	policy, err := h.services.RedactionService.GetProjectRedaction(c.Request.Context(), projectID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, policy)
+}
+
+// UpdateProjectRedaction replaces the PII redaction policy of a project.
+// @Summary Update project redaction policy
+// @Description Choose built-in detectors (email, phone, credit_card, iban, ssn, ip_address, secret), custom regexes,
+// @Description attribute allow/deny lists and whether matches are masked, hashed or dropped.
+// @Description Redacted spans record the number of redactions in the brokle.redaction.count attribute.
+// @Tags redaction
+// @Accept json
+// @Produce json
+// @Param projectId path string true "Project ID"
+// @Param request body observability.UpdateRedactionPolicyRequest true "Redaction policy"
+// @Success 200 {object} observability.RedactionPolicy
+// @Failure 400 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/redaction [put]
+func (h *Handler) UpdateProjectRedaction(c *gin.Context) {
+	projectID, err := parseRetentionID(c, "projectId")
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var req observability.UpdateRedactionPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	policy, err := h.services.RedactionService.UpdateProjectRedaction(c.Request.Context(), projectID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, policy)
+}
+
+// DeleteProjectRedaction removes the PII redaction policy of a project.
+// @Summary Delete project redaction policy
+// @Description Remove the policy so spans of the project are stored verbatim
+// @Tags redaction
+// @Param projectId path string true "Project ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/redaction [delete]
+func (h *Handler) DeleteProjectRedaction(c *gin.Context) {
+	projectID, err := parseRetentionID(c, "projectId")
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if err := h.services.RedactionService.DeleteProjectRedaction(c.Request.Context(), projectID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
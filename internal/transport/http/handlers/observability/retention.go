@@ -0,0 +1,211 @@
+package observability
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"brokle/internal/core/domain/observability"
+	"brokle/internal/transport/http/middleware"
+	appErrors "brokle/pkg/errors"
+	"brokle/pkg/response"
+	"brokle/pkg/ulid"
+)
+
+// GetOrganizationRetention returns the organization default retention policy.
+// @Summary Get organization retention policy
+// @Description Get the organization default retention per signal, resolved against system defaults
+// @Tags retention
+// @Produce json
+// @Param orgId path string true "Organization ID"
+// @Success 200 {object} observability.EffectiveRetention
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Router /api/v1/organizations/{orgId}/retention [get]
+func (h *Handler) GetOrganizationRetention(c *gin.Context) {
+	orgID, err := parseRetentionID(c, "orgId")
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if !h.authorizeOrganization(c, orgID, "settings:read") {
+		return
+	}
+
+	retention, err := h.services.RetentionService.GetOrganizationRetention(c.Request.Context(), orgID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, retention)
+}
+
+// UpdateOrganizationRetention replaces the organization default retention policy.
+// @Summary Update organization retention policy
+// @Description Set retention days per signal for all projects without their own override. Omitted signals use system defaults.
+// @Tags retention
+// @Accept json
+// @Produce json
+// @Param orgId path string true "Organization ID"
+// @Param request body observability.UpdateRetentionPolicyRequest true "Retention days per signal"
+// @Success 200 {object} observability.EffectiveRetention
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Router /api/v1/organizations/{orgId}/retention [put]
+func (h *Handler) UpdateOrganizationRetention(c *gin.Context) {
+	orgID, err := parseRetentionID(c, "orgId")
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if !h.authorizeOrganization(c, orgID, "settings:write") {
+		return
+	}
+
+	var req observability.UpdateRetentionPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	retention, err := h.services.RetentionService.UpdateOrganizationRetention(c.Request.Context(), orgID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, retention)
+}
+
+// GetProjectRetention returns the effective retention of a project.
+// @Summary Get project retention policy
+// @Description Get the effective retention per signal and where each value comes from (project, organization, default)
+// @Tags retention
+// @Produce json
+// @Param projectId path string true "Project ID"
+// @Success 200 {object} observability.EffectiveRetention
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/retention [get]
+func (h *Handler) GetProjectRetention(c *gin.Context) {
+	projectID, err := parseRetentionID(c, "projectId")
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	retention, err := h.services.RetentionService.GetProjectRetention(c.Request.Context(), projectID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, retention)
+}
+
+// UpdateProjectRetention replaces the project retention override.
+// @Summary Update project retention policy
+// @Description Override retention days per signal for a project. Omitted signals inherit from the organization.
+// @Tags retention
+// @Accept json
+// @Produce json
+// @Param projectId path string true "Project ID"
+// @Param request body observability.UpdateRetentionPolicyRequest true "Retention days per signal"
+// @Success 200 {object} observability.EffectiveRetention
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/retention [put]
+func (h *Handler) UpdateProjectRetention(c *gin.Context) {
+	projectID, err := parseRetentionID(c, "projectId")
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var req observability.UpdateRetentionPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	retention, err := h.services.RetentionService.UpdateProjectRetention(c.Request.Context(), projectID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, retention)
+}
+
+// DeleteProjectRetention removes the project retention override.
+// @Summary Delete project retention policy
+// @Description Remove the project override so the organization policy applies
+// @Tags retention
+// @Param projectId path string true "Project ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/retention [delete]
+func (h *Handler) DeleteProjectRetention(c *gin.Context) {
+	projectID, err := parseRetentionID(c, "projectId")
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if err := h.services.RetentionService.DeleteProjectRetention(c.Request.Context(), projectID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DryRunProjectRetention reports how many rows the project's policy would purge.
+// @Summary Dry-run project retention
+// @Description Count rows per signal older than the project's effective retention without deleting anything
+// @Tags retention
+// @Produce json
+// @Param projectId path string true "Project ID"
+// @Success 200 {array} observability.RetentionPurgeEstimate
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/retention/dry-run [get]
+func (h *Handler) DryRunProjectRetention(c *gin.Context) {
+	projectID, err := parseRetentionID(c, "projectId")
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	estimates, err := h.services.RetentionService.DryRun(c.Request.Context(), projectID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, estimates)
+}
+
+func parseRetentionID(c *gin.Context, param string) (ulid.ULID, error) {
+	id, err := ulid.Parse(c.Param(param))
+	if err != nil {
+		return ulid.ULID{}, appErrors.NewValidationError("Invalid "+param, param+" must be a valid ULID")
+	}
+	return id, nil
+}
+
+// authorizeOrganization requires the caller to hold permission within orgID;
+// the route middleware only checks the permission in some organization.
+func (h *Handler) authorizeOrganization(c *gin.Context, orgID ulid.ULID, permission string) bool {
+	userID, exists := middleware.GetUserIDULID(c)
+	if !exists {
+		response.Unauthorized(c, "Authentication required")
+		return false
+	}
+	if err := h.members.RequireOrganizationPermission(c.Request.Context(), userID, orgID, permission); err != nil {
+		response.Error(c, err)
+		return false
+	}
+	return true
+}
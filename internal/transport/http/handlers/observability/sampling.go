@@ -0,0 +1,92 @@
+package observability
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"brokle/internal/core/domain/observability"
+	"brokle/pkg/response"
+)
+
+// GetProjectSampling returns the sampling policy of a project.
+// @Summary Get project sampling policy
+// @Description Get the head and tail sampling policy applied to OTLP ingestion. Projects without a policy keep every trace.
+// @Tags sampling
+// @Produce json
+// @Param projectId path string true "Project ID"
+// @Success 200 {object} observability.SamplingPolicy
+// @Failure 400 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/sampling [get]
+func (h *Handler) GetProjectSampling(c *gin.Context) {
+	projectID, err := parseRetentionID(c, "projectId")
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	policy, err := h.services.SamplingService.GetProjectSampling(c.Request.Context(), projectID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, policy)
+}
+
+// UpdateProjectSampling replaces the sampling policy of a project.
+// @Summary Update project sampling policy
+// @Description Set the head sample rate and tail sampling keep rules (errors, cost, duration, tags, span filter).
+// @Description Kept spans record the applied sample rate so aggregate metrics are re-weighted.
+// @Tags sampling
+// @Accept json
+// @Produce json
+// @Param projectId path string true "Project ID"
+// @Param request body observability.UpdateSamplingPolicyRequest true "Sampling policy"
+// @Success 200 {object} observability.SamplingPolicy
+// @Failure 400 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/sampling [put]
+func (h *Handler) UpdateProjectSampling(c *gin.Context) {
+	projectID, err := parseRetentionID(c, "projectId")
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var req observability.UpdateSamplingPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	policy, err := h.services.SamplingService.UpdateProjectSampling(c.Request.Context(), projectID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, policy)
+}
+
+// DeleteProjectSampling removes the sampling policy of a project.
+// @Summary Delete project sampling policy
+// @Description Remove the policy so every trace of the project is kept
+// @Tags sampling
+// @Param projectId path string true "Project ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/sampling [delete]
+func (h *Handler) DeleteProjectSampling(c *gin.Context) {
+	projectID, err := parseRetentionID(c, "projectId")
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if err := h.services.SamplingService.DeleteProjectSampling(c.Request.Context(), projectID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -91,6 +91,7 @@ func (h *Handler) ListProjectScores(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Param project_id query string true "Project ID"
 // @Param trace_id query string false "Filter by trace ID"
 // @Param span_id query string false "Filter by span ID"
 // @Param session_id query string false "Filter by session ID"
@@ -104,7 +105,15 @@ func (h *Handler) ListProjectScores(c *gin.Context) {
 // @Failure 500 {object} response.APIResponse{error=response.APIError} "Internal server error"
 // @Router /api/v1/scores [get]
 func (h *Handler) ListScores(c *gin.Context) {
-	filter := &observability.ScoreFilter{}
+	projectID := c.Query("project_id")
+	if projectID == "" {
+		response.ValidationError(c, "project_id is required", "project_id query parameter is required")
+		return
+	}
+
+	filter := &observability.ScoreFilter{
+		ProjectID: projectID,
+	}
 
 	if traceID := c.Query("trace_id"); traceID != "" {
 		filter.TraceID = &traceID
@@ -155,6 +164,7 @@ func (h *Handler) ListScores(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Score ID"
+// @Param project_id query string true "Project ID"
 // @Success 200 {object} response.APIResponse{data=observability.Score} "Score details"
 // @Failure 404 {object} response.APIResponse{error=response.APIError} "Score not found"
 // @Failure 500 {object} response.APIResponse{error=response.APIError} "Internal server error"
@@ -166,7 +176,7 @@ func (h *Handler) GetScore(c *gin.Context) {
 		return
 	}
 
-	score, err := h.services.GetScoreService().GetScoreByID(c.Request.Context(), scoreID)
+	score, err := h.getProjectScore(c, scoreID)
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -183,6 +193,7 @@ func (h *Handler) GetScore(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Score ID"
+// @Param project_id query string true "Project ID"
 // @Param score body observability.Score true "Updated score data"
 // @Success 200 {object} response.APIResponse{data=observability.Score} "Updated score"
 // @Failure 400 {object} response.APIResponse{error=response.APIError} "Invalid request"
@@ -202,6 +213,12 @@ func (h *Handler) UpdateScore(c *gin.Context) {
 		return
 	}
 
+	// The score must belong to the project the permission was checked in
+	if _, err := h.getProjectScore(c, scoreID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
 	// Ensure ID matches path parameter
 	score.ID = scoreID
 
@@ -219,6 +236,24 @@ func (h *Handler) UpdateScore(c *gin.Context) {
 	response.Success(c, updated)
 }
 
+// getProjectScore returns the score when it belongs to the project_id query,
+// and not found otherwise so scores of other projects stay hidden
+func (h *Handler) getProjectScore(c *gin.Context, scoreID string) (*observability.Score, error) {
+	projectID := c.Query("project_id")
+	if projectID == "" {
+		return nil, appErrors.NewValidationError("project_id", "is required")
+	}
+
+	score, err := h.services.GetScoreService().GetScoreByID(c.Request.Context(), scoreID)
+	if err != nil {
+		return nil, err
+	}
+	if score.ProjectID != projectID {
+		return nil, appErrors.NewNotFoundError("score " + scoreID)
+	}
+	return score, nil
+}
+
 // GetScoreAnalytics handles GET /api/v1/projects/:projectId/scores/analytics
 // @Summary Get score analytics for a project
 // @Description Retrieve comprehensive analytics for a score including statistics, time series, and distribution
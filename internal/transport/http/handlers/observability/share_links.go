@@ -0,0 +1,131 @@
+package observability
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"brokle/internal/core/domain/observability"
+	"brokle/internal/transport/http/middleware"
+	"brokle/pkg/response"
+)
+
+// CreateShareLink shares a trace or session through a public link.
+// @Summary Create share link
+// @Description Mint a signed, revocable link that renders a read-only view of a trace or session without login.
+// @Description Inputs and outputs can be redacted; every view is recorded in the audit log.
+// @Tags share-links
+// @Accept json
+// @Produce json
+// @Param projectId path string true "Project ID"
+// @Param request body observability.CreateShareLinkRequest true "Share link request"
+// @Success 201 {object} observability.ShareLinkResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse "Trace or session not found in the project"
+// @Router /api/v1/projects/{projectId}/share-links [post]
+func (h *Handler) CreateShareLink(c *gin.Context) {
+	projectID, err := parseRetentionID(c, "projectId")
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	userID, exists := middleware.GetUserIDULID(c)
+	if !exists {
+		response.Unauthorized(c, "user not authenticated")
+		return
+	}
+
+	var req observability.CreateShareLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	link, err := h.services.ShareLinkService.Create(c.Request.Context(), projectID, userID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, link)
+}
+
+// ListShareLinks lists the share links of a project.
+// @Summary List share links
+// @Description List share links of a project with their view counts, including revoked and expired links
+// @Tags share-links
+// @Produce json
+// @Param projectId path string true "Project ID"
+// @Success 200 {array} observability.ShareLinkResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/share-links [get]
+func (h *Handler) ListShareLinks(c *gin.Context) {
+	projectID, err := parseRetentionID(c, "projectId")
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	links, err := h.services.ShareLinkService.List(c.Request.Context(), projectID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, links)
+}
+
+// RevokeShareLink revokes a share link.
+// @Summary Revoke share link
+// @Description Disable a share link immediately; the URL stops working everywhere it was pasted
+// @Tags share-links
+// @Param projectId path string true "Project ID"
+// @Param linkId path string true "Share link ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse "Already revoked"
+// @Router /api/v1/projects/{projectId}/share-links/{linkId} [delete]
+func (h *Handler) RevokeShareLink(c *gin.Context) {
+	projectID, err := parseRetentionID(c, "projectId")
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	linkID, err := parseRetentionID(c, "linkId")
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if err := h.services.ShareLinkService.Revoke(c.Request.Context(), projectID, linkID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ViewSharedTrace serves a shared trace or session without authentication.
+// @Summary View shared trace
+// @Description Read-only view of the traces and spans behind a share link. Invalid, revoked and expired links return 404.
+// @Tags share-links
+// @Produce json
+// @Param token path string true "Share token"
+// @Success 200 {object} observability.SharedView
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/public/shares/{token} [get]
+func (h *Handler) ViewSharedTrace(c *gin.Context) {
+	view, err := h.services.ShareLinkService.View(c.Request.Context(), c.Param("token"), observability.ShareViewer{
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	// Shared content must not outlive a revocation in intermediate caches
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Robots-Tag", "noindex")
+	response.Success(c, view)
+}
@@ -38,6 +38,8 @@ type SpanQueryHTTPRequest struct {
 	EndTime   *time.Time `json:"end_time,omitempty" example:"2024-01-31T23:59:59Z"`
 	Limit int `json:"limit,omitempty" example:"100"`
 	Page  int `json:"page,omitempty" example:"1"`
+	// Source selects hot storage (default) or the S3 archive; archive requires start_time and end_time
+	Source string `json:"source,omitempty" binding:"omitempty,oneof=hot archive" example:"hot"`
 }
 
 // SpanQueryHTTPResponse is the HTTP response for span queries.
@@ -48,6 +50,18 @@ type SpanQueryHTTPResponse struct {
 	HasMore    bool                  `json:"has_more"`
 }
 
+// SpanAggregateHTTPRequest is the HTTP request body for span aggregation queries.
+// @Description SDK request for aggregating spans with optional filter, group by and time buckets
+type SpanAggregateHTTPRequest struct {
+	Filter       string     `json:"filter,omitempty" binding:"max=2000" example:"gen_ai.system=openai"`
+	Aggregations []string   `json:"aggregations" binding:"required,min=1,max=10" example:"count(),quantile(0.95)(duration)"`
+	GroupBy      []string   `json:"group_by,omitempty" binding:"max=5" example:"model_name"`
+	Interval     string     `json:"interval,omitempty" example:"1h"`
+	StartTime    *time.Time `json:"start_time,omitempty" example:"2024-01-01T00:00:00Z"`
+	EndTime      *time.Time `json:"end_time,omitempty" example:"2024-01-31T23:59:59Z"`
+	Limit        int        `json:"limit,omitempty" example:"1000"`
+}
+
 // ValidateFilterRequest is the HTTP request body for filter validation.
 // @Description Request to validate a filter expression
 type ValidateFilterRequest struct {
@@ -59,6 +73,9 @@ type ValidateFilterRequest struct {
 // @Description Query production telemetry data using human-readable filter syntax.
 // @Description Supports operators: =, !=, >, <, >=, <=, CONTAINS, IN, EXISTS
 // @Description Supports logical operators: AND, OR with parentheses grouping
+// @Description Set source=archive to query archived Parquet files in S3 without rehydrating them.
+// @Description Trace predicates match spans of traces whose span tree fits: HAS(f), COUNT(f) >= n,
+// @Description DESCENDANT(ancestor, span), CHILD(parent, span), SIBLING(a, b).
 // @Tags SDK - Span Query
 // @Accept json
 // @Produce json
@@ -68,6 +85,7 @@ type ValidateFilterRequest struct {
 // @Failure 400 {object} response.APIResponse{error=response.APIError} "Invalid filter syntax"
 // @Failure 401 {object} response.APIResponse{error=response.APIError} "Invalid or missing API key"
 // @Failure 500 {object} response.APIResponse{error=response.APIError} "Internal server error"
+// @Failure 503 {object} response.APIResponse{error=response.APIError} "Archive storage not configured"
 // @Router /v1/spans/query [post]
 func (h *SpanQueryHandler) HandleQuery(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -102,6 +120,7 @@ func (h *SpanQueryHandler) HandleQuery(c *gin.Context) {
 		EndTime:   req.EndTime,
 		Limit:     limit,
 		Page:      page,
+		Source:    observability.SpanQuerySource(req.Source),
 	}
 
 	result, err := h.spanQueryService.QuerySpans(ctx, projectID, domainReq)
@@ -117,6 +136,53 @@ func (h *SpanQueryHandler) HandleQuery(c *gin.Context) {
 	})
 }
 
+// HandleAggregate handles POST /v1/spans/query/aggregate
+// @Summary Aggregate spans using filter expressions
+// @Description Compute aggregates over spans matching a filter expression.
+// @Description Supports count(), sum(f), avg(f), min(f), max(f), uniq(f) and quantile(q)(f),
+// @Description GROUP BY on span fields, and time bucketing via interval (e.g. 5m, 1h, 1d).
+// @Description Numeric fields: duration (ns), duration_ms, total_cost, tokens.input, tokens.output, tokens.total, or any numeric attribute.
+// @Tags SDK - Span Query
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body SpanAggregateHTTPRequest true "Span aggregate request"
+// @Success 200 {object} response.APIResponse{data=observability.SpanAggregateResponse} "Aggregation rows"
+// @Failure 400 {object} response.APIResponse{error=response.APIError} "Invalid filter or aggregation"
+// @Failure 401 {object} response.APIResponse{error=response.APIError} "Invalid or missing API key"
+// @Failure 500 {object} response.APIResponse{error=response.APIError} "Internal server error"
+// @Router /v1/spans/query/aggregate [post]
+func (h *SpanQueryHandler) HandleAggregate(c *gin.Context) {
+	projectIDPtr, exists := middleware.GetProjectID(c)
+	if !exists || projectIDPtr == nil {
+		h.logger.Error("Project ID not found in context")
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	var req SpanAggregateHTTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "invalid request", err.Error())
+		return
+	}
+
+	result, err := h.spanQueryService.AggregateSpans(c.Request.Context(), projectIDPtr.String(), &observability.SpanAggregateRequest{
+		Filter:       req.Filter,
+		Aggregations: req.Aggregations,
+		GroupBy:      req.GroupBy,
+		Interval:     req.Interval,
+		StartTime:    req.StartTime,
+		EndTime:      req.EndTime,
+		Limit:        req.Limit,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
 // HandleValidate handles POST /v1/spans/query/validate
 // @Summary Validate a filter expression
 // @Description Validates filter syntax without executing the query. Useful for SDK clients
@@ -14,6 +14,7 @@ import (
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Param project_id query string true "Project ID"
 // @Param trace_id query string false "Filter by trace ID"
 // @Param type query string false "Filter by span type"
 // @Param model query string false "Filter by model"
@@ -25,7 +26,15 @@ import (
 // @Failure 500 {object} response.APIResponse{error=response.APIError} "Internal server error"
 // @Router /api/v1/spans [get]
 func (h *Handler) ListSpans(c *gin.Context) {
-	filter := &observability.SpanFilter{}
+	projectID := c.Query("project_id")
+	if projectID == "" {
+		response.ValidationError(c, "project_id is required", "project_id query parameter is required")
+		return
+	}
+
+	filter := &observability.SpanFilter{
+		ProjectID: projectID,
+	}
 
 	if traceID := c.Query("trace_id"); traceID != "" {
 		filter.TraceID = &traceID
@@ -75,18 +84,24 @@ func (h *Handler) ListSpans(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Span ID (OTEL 16-character hex)"
+// @Param project_id query string true "Project ID"
 // @Success 200 {object} response.APIResponse{data=observability.Span} "Span details"
 // @Failure 404 {object} response.APIResponse{error=response.APIError} "Span not found"
 // @Failure 500 {object} response.APIResponse{error=response.APIError} "Internal server error"
 // @Router /api/v1/spans/{id} [get]
 func (h *Handler) GetSpan(c *gin.Context) {
 	spanID := c.Param("id")
+	projectID := c.Query("project_id")
 	if spanID == "" {
 		response.ValidationError(c, "invalid span_id", "span_id is required")
 		return
 	}
+	if projectID == "" {
+		response.ValidationError(c, "project_id is required", "project_id query parameter is required")
+		return
+	}
 
-	span, err := h.services.GetTraceService().GetSpan(c.Request.Context(), spanID)
+	span, err := h.services.GetTraceService().GetSpanByProject(c.Request.Context(), spanID, projectID)
 	if err != nil {
 		h.logger.Error("Failed to get span", "error", err)
 		response.Error(c, err)
@@ -104,16 +119,28 @@ func (h *Handler) GetSpan(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Span ID (OTEL 16-character hex)"
+// @Param project_id query string true "Project ID"
 // @Success 200 {object} response.APIResponse "Span deleted"
 // @Failure 404 {object} response.APIResponse{error=response.APIError} "Span not found"
 // @Failure 500 {object} response.APIResponse{error=response.APIError} "Internal server error"
 // @Router /api/v1/spans/{id} [delete]
 func (h *Handler) DeleteSpan(c *gin.Context) {
 	spanID := c.Param("id")
+	projectID := c.Query("project_id")
 	if spanID == "" {
 		response.ValidationError(c, "invalid span_id", "span_id is required")
 		return
 	}
+	if projectID == "" {
+		response.ValidationError(c, "project_id is required", "project_id query parameter is required")
+		return
+	}
+
+	// The span must belong to the project the permission was checked in
+	if _, err := h.services.GetTraceService().GetSpanByProject(c.Request.Context(), spanID, projectID); err != nil {
+		response.Error(c, err)
+		return
+	}
 
 	if err := h.services.GetTraceService().DeleteSpan(c.Request.Context(), spanID); err != nil {
 		h.logger.Error("Failed to delete span", "error", err)
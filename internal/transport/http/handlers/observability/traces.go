@@ -75,6 +75,7 @@ func filterEmptyStrings(slice []string) []string {
 // @Param min_duration query int64 false "Minimum duration filter (nanoseconds)"
 // @Param max_duration query int64 false "Maximum duration filter (nanoseconds)"
 // @Param has_error query boolean false "Filter traces with errors only"
+// @Param filter query string false "Span query filter expression; supports trace predicates such as HAS(span.type=tool) and CHILD(span_name=agent, has_error=true)"
 // @Param start_time query int64 false "Start time (Unix timestamp)"
 // @Param end_time query int64 false "End time (Unix timestamp)"
 // @Param limit query int false "Limit (default 50, max 1000)"
@@ -125,6 +126,9 @@ func (h *Handler) ListTraces(c *gin.Context) {
 	if searchType := c.Query("search_type"); searchType != "" {
 		filter.SearchType = &searchType
 	}
+	if expr := c.Query("filter"); expr != "" {
+		filter.Filter = &expr
+	}
 
 	// Status filter (comma-separated: "ok,error,unset")
 	if statusStr := c.Query("status"); statusStr != "" {
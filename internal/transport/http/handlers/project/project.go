@@ -9,6 +9,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"brokle/internal/config"
+	"brokle/internal/core/domain/auth"
 	"brokle/internal/core/domain/organization"
 	"brokle/pkg/response"
 	"brokle/pkg/ulid"
@@ -20,6 +21,7 @@ type Handler struct {
 	projectService      organization.ProjectService
 	organizationService organization.OrganizationService
 	memberService       organization.MemberService
+	projectAccess       auth.ProjectAccessService
 }
 
 func NewHandler(
@@ -28,6 +30,7 @@ func NewHandler(
 	projectService organization.ProjectService,
 	organizationService organization.OrganizationService,
 	memberService organization.MemberService,
+	projectAccess auth.ProjectAccessService,
 ) *Handler {
 	return &Handler{
 		config:              config,
@@ -35,6 +38,7 @@ func NewHandler(
 		projectService:      projectService,
 		organizationService: organizationService,
 		memberService:       memberService,
+		projectAccess:       projectAccess,
 	}
 }
 
@@ -189,6 +193,17 @@ func (h *Handler) List(c *gin.Context) {
 	// Apply filtering
 	var filteredProjects []*organization.Project
 	for _, project := range projects {
+		// Project roles can withhold projects the organization role would show
+		access, err := h.projectAccess.CheckUserProjectPermissions(ctx, userULID, project.ID, []string{"projects:read"})
+		if err != nil {
+			h.logger.Error("Failed to check project access", "endpoint", "List", "user_id", userULID.String(), "project_id", project.ID.String(), "error", err.Error())
+			response.InternalServerError(c, "Failed to retrieve projects")
+			return
+		}
+		if !access["projects:read"] {
+			continue
+		}
+
 		// Status filter
 		if req.Status != "" && req.Status != "active" {
 			// For now, all projects are considered "active" - extend this when status field is added
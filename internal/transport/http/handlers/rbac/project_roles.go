@@ -0,0 +1,164 @@
+package rbac
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"brokle/internal/core/domain/auth"
+	"brokle/internal/transport/http/middleware"
+	"brokle/pkg/response"
+	"brokle/pkg/ulid"
+)
+
+// ListProjectMembers lists the role assignments of a project.
+// @Summary List project role assignments
+// @Description List the users assigned a role in the project. Inside the project the assigned role replaces their organization role.
+// @Tags rbac
+// @Produce json
+// @Param projectId path string true "Project ID"
+// @Success 200 {array} auth.ProjectMember
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/members [get]
+func (h *Handler) ListProjectMembers(c *gin.Context) {
+	projectID, ok := parseULIDParam(c, "projectId", "Invalid project ID")
+	if !ok {
+		return
+	}
+
+	members, err := h.projectRoles.ListProjectMembers(c.Request.Context(), projectID)
+	if err != nil {
+		h.logger.Error("Failed to list project members", "error", err, "project_id", projectID)
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, members)
+}
+
+// AssignProjectRole assigns a user a role in a project.
+// @Summary Assign project role
+// @Description Assign an organization member a role template or custom role in the project, e.g. "Prompt Editor" on one
+// @Description project and "viewer" on another. Replaces the role they already hold in the project. Requires advanced RBAC.
+// @Tags rbac
+// @Accept json
+// @Produce json
+// @Param projectId path string true "Project ID"
+// @Param userId path string true "User ID"
+// @Param request body auth.AssignProjectRoleRequest true "Role"
+// @Success 200 {object} auth.ProjectMember
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 402 {object} response.ErrorResponse "License required"
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/members/{userId} [put]
+func (h *Handler) AssignProjectRole(c *gin.Context) {
+	projectID, ok := parseULIDParam(c, "projectId", "Invalid project ID")
+	if !ok {
+		return
+	}
+	userID, ok := parseULIDParam(c, "userId", "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	var req auth.AssignProjectRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	var assignedBy *ulid.ULID
+	if actorID, exists := middleware.GetUserIDULID(c); exists {
+		assignedBy = &actorID
+	}
+
+	member, err := h.projectRoles.AssignProjectRole(c.Request.Context(), projectID, userID, req.RoleID, assignedBy)
+	if err != nil {
+		h.logger.Error("Failed to assign project role", "error", err, "project_id", projectID, "user_id", userID, "role_id", req.RoleID)
+		projectRoleError(c, err)
+		return
+	}
+
+	response.Success(c, member)
+}
+
+// RemoveProjectRole removes a user's role in a project.
+// @Summary Remove project role
+// @Description Remove the user's project role; they act with their organization role in the project again
+// @Tags rbac
+// @Produce json
+// @Param projectId path string true "Project ID"
+// @Param userId path string true "User ID"
+// @Success 200 {object} response.SuccessResponse
+// @Failure 402 {object} response.ErrorResponse "License required"
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/members/{userId} [delete]
+func (h *Handler) RemoveProjectRole(c *gin.Context) {
+	projectID, ok := parseULIDParam(c, "projectId", "Invalid project ID")
+	if !ok {
+		return
+	}
+	userID, ok := parseULIDParam(c, "userId", "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	if err := h.projectRoles.RemoveProjectRole(c.Request.Context(), projectID, userID); err != nil {
+		h.logger.Error("Failed to remove project role", "error", err, "project_id", projectID, "user_id", userID)
+		projectRoleError(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "Project role removed successfully"})
+}
+
+// SimulatePermission explains whether a user holds a permission in a project.
+// @Summary Simulate permission
+// @Description Check whether a user can perform an action in the project and why: the role that applies, where it comes from
+// @Description (organization owner, project role or organization role) and the grant that matched, wildcards included
+// @Tags rbac
+// @Produce json
+// @Param projectId path string true "Project ID"
+// @Param user_id query string true "User ID"
+// @Param permission query string true "Permission in resource:action format" example(prompts:update)
+// @Success 200 {object} auth.PermissionSimulation
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/permissions/simulate [get]
+func (h *Handler) SimulatePermission(c *gin.Context) {
+	projectID, ok := parseULIDParam(c, "projectId", "Invalid project ID")
+	if !ok {
+		return
+	}
+	userID, err := ulid.Parse(c.Query("user_id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", "user_id must be a valid user ID")
+		return
+	}
+
+	simulation, err := h.projectAccess.SimulatePermission(c.Request.Context(), userID, projectID, c.Query("permission"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, simulation)
+}
+
+func parseULIDParam(c *gin.Context, name, message string) (ulid.ULID, bool) {
+	id, err := ulid.Parse(c.Param(name))
+	if err != nil {
+		response.BadRequest(c, message, err.Error())
+		return ulid.ULID{}, false
+	}
+	return id, true
+}
+
+// projectRoleError reports the unlicensed stub as payment required
+func projectRoleError(c *gin.Context, err error) {
+	if strings.Contains(err.Error(), "Enterprise license") {
+		response.PaymentRequired(c, err.Error())
+		return
+	}
+	response.Error(c, err)
+}
@@ -8,6 +8,7 @@ import (
 
 	"brokle/internal/config"
 	"brokle/internal/core/domain/auth"
+	eeRBAC "brokle/internal/ee/rbac"
 	"brokle/pkg/response"
 	"brokle/pkg/ulid"
 )
@@ -20,6 +21,8 @@ type Handler struct {
 	permissionService         auth.PermissionService
 	organizationMemberService auth.OrganizationMemberService
 	scopeService              auth.ScopeService
+	projectAccess             auth.ProjectAccessService
+	projectRoles              eeRBAC.RBACManager
 }
 
 // NewHandler creates a new clean RBAC handler
@@ -30,6 +33,8 @@ func NewHandler(
 	permissionService auth.PermissionService,
 	organizationMemberService auth.OrganizationMemberService,
 	scopeService auth.ScopeService,
+	projectAccess auth.ProjectAccessService,
+	projectRoles eeRBAC.RBACManager,
 ) *Handler {
 	return &Handler{
 		config:                    config,
@@ -38,6 +43,8 @@ func NewHandler(
 		permissionService:         permissionService,
 		organizationMemberService: organizationMemberService,
 		scopeService:              scopeService,
+		projectAccess:             projectAccess,
+		projectRoles:              projectRoles,
 	}
 }
 
@@ -483,12 +490,7 @@ func (h *Handler) CreateCustomRole(c *gin.Context) {
 	if err != nil {
 		h.logger.Error("Failed to create custom role", "error", err, "org_id", orgID, "role_name", req.Name, "scope_type", req.ScopeType)
 
-		if err.Error() == "custom role with name "+req.Name+" already exists in this scope" {
-			response.Conflict(c, err.Error())
-			return
-		}
-
-		response.InternalServerError(c, "Failed to create custom role")
+		response.Error(c, err)
 		return
 	}
 
@@ -568,12 +570,7 @@ func (h *Handler) UpdateCustomRole(c *gin.Context) {
 	if err != nil {
 		h.logger.Error("Failed to update custom role", "error", err, "role_id", roleID)
 
-		if err.Error() == "cannot update system role" {
-			response.Forbidden(c, err.Error())
-			return
-		}
-
-		response.InternalServerError(c, "Failed to update custom role")
+		response.Error(c, err)
 		return
 	}
 
@@ -596,12 +593,7 @@ func (h *Handler) DeleteCustomRole(c *gin.Context) {
 	if err != nil {
 		h.logger.Error("Failed to delete custom role", "error", err, "role_id", roleID)
 
-		if err.Error() == "cannot delete system role" {
-			response.Forbidden(c, err.Error())
-			return
-		}
-
-		response.InternalServerError(c, "Failed to delete custom role")
+		response.Error(c, err)
 		return
 	}
 
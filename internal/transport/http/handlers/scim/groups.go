@@ -0,0 +1,136 @@
+package scim
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	authDomain "brokle/internal/core/domain/auth"
+)
+
+// ListGroups lists SCIM groups
+// @Summary List SCIM groups
+// @Description Groups of the token's organization. Supports filter=displayName eq "..." or externalId eq "...".
+// @Tags SCIM
+// @Produce json
+// @Security BearerAuth
+// @Param filter query string false "Equality filter"
+// @Param startIndex query int false "1-based index of the first result"
+// @Param count query int false "Page size (max 100)"
+// @Success 200 {object} authDomain.SCIMListResponse
+// @Router /scim/v2/Groups [get]
+func (h *Handler) ListGroups(c *gin.Context) {
+	startIndex, count := pagination(c)
+	list, err := h.service.ListGroups(c.Request.Context(), token(c), c.Query("filter"), startIndex, count)
+	if err != nil {
+		h.renderError(c, err)
+		return
+	}
+	render(c, http.StatusOK, list)
+}
+
+// GetGroup returns a SCIM group
+// @Summary Get SCIM group
+// @Tags SCIM
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Success 200 {object} authDomain.SCIMGroupResource
+// @Failure 404 {object} authDomain.SCIMError
+// @Router /scim/v2/Groups/{id} [get]
+func (h *Handler) GetGroup(c *gin.Context) {
+	group, err := h.service.GetGroup(c.Request.Context(), token(c), c.Param("id"))
+	if err != nil {
+		h.renderError(c, err)
+		return
+	}
+	render(c, http.StatusOK, group)
+}
+
+// CreateGroup creates a SCIM group
+// @Summary Create SCIM group
+// @Description A group named after an organization role (admin, developer, viewer or a custom role) grants that role to its members
+// @Tags SCIM
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param group body authDomain.SCIMGroupResource true "SCIM group"
+// @Success 201 {object} authDomain.SCIMGroupResource
+// @Failure 409 {object} authDomain.SCIMError "displayName already used"
+// @Router /scim/v2/Groups [post]
+func (h *Handler) CreateGroup(c *gin.Context) {
+	var req authDomain.SCIMGroupResource
+	if !h.bind(c, &req) {
+		return
+	}
+
+	group, err := h.service.CreateGroup(c.Request.Context(), token(c), &req)
+	if err != nil {
+		h.renderError(c, err)
+		return
+	}
+	render(c, http.StatusCreated, group)
+}
+
+// ReplaceGroup replaces a SCIM group and its member list
+// @Summary Replace SCIM group
+// @Tags SCIM
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Param group body authDomain.SCIMGroupResource true "SCIM group"
+// @Success 200 {object} authDomain.SCIMGroupResource
+// @Router /scim/v2/Groups/{id} [put]
+func (h *Handler) ReplaceGroup(c *gin.Context) {
+	var req authDomain.SCIMGroupResource
+	if !h.bind(c, &req) {
+		return
+	}
+
+	group, err := h.service.ReplaceGroup(c.Request.Context(), token(c), c.Param("id"), &req)
+	if err != nil {
+		h.renderError(c, err)
+		return
+	}
+	render(c, http.StatusOK, group)
+}
+
+// PatchGroup updates a SCIM group's name or members
+// @Summary Patch SCIM group
+// @Tags SCIM
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Param patch body authDomain.SCIMPatchRequest true "PATCH operations"
+// @Success 200 {object} authDomain.SCIMGroupResource
+// @Router /scim/v2/Groups/{id} [patch]
+func (h *Handler) PatchGroup(c *gin.Context) {
+	var req authDomain.SCIMPatchRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	group, err := h.service.PatchGroup(c.Request.Context(), token(c), c.Param("id"), &req)
+	if err != nil {
+		h.renderError(c, err)
+		return
+	}
+	render(c, http.StatusOK, group)
+}
+
+// DeleteGroup deletes a SCIM group
+// @Summary Delete SCIM group
+// @Tags SCIM
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Success 204 "No Content"
+// @Router /scim/v2/Groups/{id} [delete]
+func (h *Handler) DeleteGroup(c *gin.Context) {
+	if err := h.service.DeleteGroup(c.Request.Context(), token(c), c.Param("id")); err != nil {
+		h.renderError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,156 @@
+package scim
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	authDomain "brokle/internal/core/domain/auth"
+	appErrors "brokle/pkg/errors"
+)
+
+const (
+	contentType     = "application/scim+json"
+	tokenContextKey = "scim_token"
+)
+
+// Handler serves the SCIM 2.0 provisioning API (/scim/v2) and its organization settings
+type Handler struct {
+	logger  *slog.Logger
+	service authDomain.SCIMService
+}
+
+// NewHandler creates a new SCIM Handler
+func NewHandler(logger *slog.Logger, service authDomain.SCIMService) *Handler {
+	return &Handler{
+		logger:  logger,
+		service: service,
+	}
+}
+
+// Authenticate resolves the organization-scoped bearer token of SCIM requests
+func (h *Handler) Authenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		bearer, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || strings.TrimSpace(bearer) == "" {
+			h.renderError(c, appErrors.NewUnauthorizedError("SCIM bearer token required"))
+			c.Abort()
+			return
+		}
+
+		token, err := h.service.Authenticate(c.Request.Context(), strings.TrimSpace(bearer))
+		if err != nil {
+			h.renderError(c, err)
+			c.Abort()
+			return
+		}
+
+		c.Set(tokenContextKey, token)
+		c.Next()
+	}
+}
+
+// ServiceProviderConfig describes the supported SCIM features
+// @Summary SCIM service provider configuration
+// @Tags SCIM
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Router /scim/v2/ServiceProviderConfig [get]
+func (h *Handler) ServiceProviderConfig(c *gin.Context) {
+	unsupported := gin.H{"supported": false}
+	render(c, http.StatusOK, gin.H{
+		"schemas":        []string{authDomain.SCIMSchemaServiceProviderConfig},
+		"patch":          gin.H{"supported": true},
+		"bulk":           gin.H{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         gin.H{"supported": true, "maxResults": authDomain.SCIMMaxResults},
+		"changePassword": unsupported,
+		"sort":           unsupported,
+		"etag":           unsupported,
+		"authenticationSchemes": []gin.H{{
+			"type":        "oauthbearertoken",
+			"name":        "Bearer token",
+			"description": "Organization SCIM token created in Brokle organization settings",
+			"primary":     true,
+		}},
+	})
+}
+
+// ResourceTypes lists the provisioned resource types
+// @Summary SCIM resource types
+// @Tags SCIM
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} authDomain.SCIMListResponse
+// @Router /scim/v2/ResourceTypes [get]
+func (h *Handler) ResourceTypes(c *gin.Context) {
+	resourceTypes := []gin.H{
+		{"schemas": []string{authDomain.SCIMSchemaResourceType}, "id": "User", "name": "User", "endpoint": "/Users", "schema": authDomain.SCIMSchemaUser},
+		{"schemas": []string{authDomain.SCIMSchemaResourceType}, "id": "Group", "name": "Group", "endpoint": "/Groups", "schema": authDomain.SCIMSchemaGroup},
+	}
+	render(c, http.StatusOK, &authDomain.SCIMListResponse{
+		Schemas:      []string{authDomain.SCIMSchemaListResponse},
+		TotalResults: int64(len(resourceTypes)),
+		StartIndex:   1,
+		ItemsPerPage: len(resourceTypes),
+		Resources:    resourceTypes,
+	})
+}
+
+// token returns the SCIM token set by Authenticate
+func token(c *gin.Context) *authDomain.SCIMToken {
+	value, _ := c.Get(tokenContextKey)
+	t, _ := value.(*authDomain.SCIMToken)
+	return t
+}
+
+// pagination reads the 1-based startIndex and count query parameters
+func pagination(c *gin.Context) (startIndex, count int) {
+	startIndex, _ = strconv.Atoi(c.Query("startIndex"))
+	count, _ = strconv.Atoi(c.Query("count"))
+	return startIndex, count
+}
+
+// bind decodes a SCIM request body regardless of its JSON content type
+func (h *Handler) bind(c *gin.Context, v interface{}) bool {
+	if err := json.NewDecoder(c.Request.Body).Decode(v); err != nil {
+		render(c, http.StatusBadRequest, authDomain.NewSCIMError(http.StatusBadRequest, "invalidSyntax", "Invalid JSON body: "+err.Error()))
+		return false
+	}
+	return true
+}
+
+func render(c *gin.Context, status int, body interface{}) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	c.Data(status, contentType, payload)
+}
+
+// renderError reports service errors in the SCIM error format. Validation errors
+// carry their SCIM error type in the details.
+func (h *Handler) renderError(c *gin.Context, err error) {
+	appErr, ok := appErrors.IsAppError(err)
+	if !ok {
+		h.logger.Error("SCIM request failed", "error", err, "path", c.FullPath())
+		render(c, http.StatusInternalServerError, authDomain.NewSCIMError(http.StatusInternalServerError, "", "Internal server error"))
+		return
+	}
+
+	scimType := ""
+	switch appErr.Type {
+	case appErrors.ValidationError:
+		scimType = appErr.Details
+	case appErrors.ConflictError:
+		scimType = "uniqueness"
+	case appErrors.InternalError:
+		h.logger.Error("SCIM request failed", "error", err, "path", c.FullPath())
+	}
+	render(c, appErr.StatusCode, authDomain.NewSCIMError(appErr.StatusCode, scimType, appErr.Message))
+}
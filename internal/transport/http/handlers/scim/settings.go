@@ -0,0 +1,193 @@
+package scim
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	authDomain "brokle/internal/core/domain/auth"
+	"brokle/internal/transport/http/middleware"
+	"brokle/pkg/response"
+	"brokle/pkg/ulid"
+)
+
+// CreateToken issues a SCIM token for the organization
+// @Summary Create SCIM token
+// @Description Issue a bearer token for the identity provider's SCIM client. The token is only shown once.
+// @Tags Organizations
+// @Accept json
+// @Produce json
+// @Param orgId path string true "Organization ID"
+// @Param request body authDomain.CreateSCIMTokenRequest true "Token name"
+// @Success 201 {object} authDomain.SCIMTokenResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse "Not an admin of the organization"
+// @Router /api/v1/organizations/{orgId}/scim/tokens [post]
+func (h *Handler) CreateToken(c *gin.Context) {
+	orgID, ok := parseID(c, "orgId")
+	if !ok {
+		return
+	}
+	userID, exists := middleware.GetUserIDULID(c)
+	if !exists {
+		response.Unauthorized(c, "user not authenticated")
+		return
+	}
+
+	var req authDomain.CreateSCIMTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request payload", err.Error())
+		return
+	}
+
+	created, err := h.service.CreateToken(c.Request.Context(), orgID, userID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	h.logger.Info("SCIM token created", "organization_id", orgID, "token_id", created.ID, "created_by", userID)
+	response.Created(c, created)
+}
+
+// ListTokens lists the organization's SCIM tokens
+// @Summary List SCIM tokens
+// @Tags Organizations
+// @Produce json
+// @Param orgId path string true "Organization ID"
+// @Success 200 {array} authDomain.SCIMToken
+// @Router /api/v1/organizations/{orgId}/scim/tokens [get]
+func (h *Handler) ListTokens(c *gin.Context) {
+	orgID, ok := parseID(c, "orgId")
+	if !ok {
+		return
+	}
+
+	userID, exists := middleware.GetUserIDULID(c)
+	if !exists {
+		response.Unauthorized(c, "user not authenticated")
+		return
+	}
+
+	tokens, err := h.service.ListTokens(c.Request.Context(), orgID, userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, tokens)
+}
+
+// DeleteToken revokes a SCIM token
+// @Summary Delete SCIM token
+// @Tags Organizations
+// @Param orgId path string true "Organization ID"
+// @Param tokenId path string true "Token ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/organizations/{orgId}/scim/tokens/{tokenId} [delete]
+func (h *Handler) DeleteToken(c *gin.Context) {
+	orgID, ok := parseID(c, "orgId")
+	if !ok {
+		return
+	}
+	tokenID, ok := parseID(c, "tokenId")
+	if !ok {
+		return
+	}
+
+	userID, exists := middleware.GetUserIDULID(c)
+	if !exists {
+		response.Unauthorized(c, "user not authenticated")
+		return
+	}
+
+	if err := h.service.DeleteToken(c.Request.Context(), orgID, tokenID, userID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	h.logger.Info("SCIM token deleted", "organization_id", orgID, "token_id", tokenID, "deleted_by", userID)
+	c.Status(http.StatusNoContent)
+}
+
+// ListGroupMappings lists SCIM groups and the roles they grant
+// @Summary List SCIM groups
+// @Description Groups pushed by the identity provider with their mapped organization role
+// @Tags Organizations
+// @Produce json
+// @Param orgId path string true "Organization ID"
+// @Success 200 {array} authDomain.SCIMGroup
+// @Router /api/v1/organizations/{orgId}/scim/groups [get]
+func (h *Handler) ListGroupMappings(c *gin.Context) {
+	orgID, ok := parseID(c, "orgId")
+	if !ok {
+		return
+	}
+
+	userID, exists := middleware.GetUserIDULID(c)
+	if !exists {
+		response.Unauthorized(c, "user not authenticated")
+		return
+	}
+
+	groups, err := h.service.ListGroupMappings(c.Request.Context(), orgID, userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, groups)
+}
+
+// SetGroupRole maps a SCIM group to an organization role
+// @Summary Map SCIM group to role
+// @Description Members of the group get the role; with several mapped groups the role with the most permissions wins.
+// @Description Once any group is mapped, provisioned members outside mapped groups get the viewer role. Owners are never changed.
+// @Tags Organizations
+// @Accept json
+// @Produce json
+// @Param orgId path string true "Organization ID"
+// @Param groupId path string true "SCIM group ID"
+// @Param request body authDomain.SetSCIMGroupRoleRequest true "Role, or null to remove the mapping"
+// @Success 200 {object} authDomain.SCIMGroup
+// @Failure 400 {object} response.ErrorResponse "Not an organization role"
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/organizations/{orgId}/scim/groups/{groupId}/role [put]
+func (h *Handler) SetGroupRole(c *gin.Context) {
+	orgID, ok := parseID(c, "orgId")
+	if !ok {
+		return
+	}
+	groupID, ok := parseID(c, "groupId")
+	if !ok {
+		return
+	}
+	userID, exists := middleware.GetUserIDULID(c)
+	if !exists {
+		response.Unauthorized(c, "user not authenticated")
+		return
+	}
+
+	var req authDomain.SetSCIMGroupRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request payload", err.Error())
+		return
+	}
+
+	group, err := h.service.SetGroupRole(c.Request.Context(), orgID, groupID, req.RoleID, userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	h.logger.Info("SCIM group role mapped", "organization_id", orgID, "group_id", groupID, "role_id", req.RoleID)
+	response.Success(c, group)
+}
+
+func parseID(c *gin.Context, param string) (ulid.ULID, bool) {
+	id, err := ulid.Parse(c.Param(param))
+	if err != nil {
+		response.BadRequest(c, "Invalid "+param+" format", err.Error())
+		return ulid.ULID{}, false
+	}
+	return id, true
+}
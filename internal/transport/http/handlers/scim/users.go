@@ -0,0 +1,139 @@
+package scim
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	authDomain "brokle/internal/core/domain/auth"
+)
+
+// ListUsers lists provisioned users
+// @Summary List SCIM users
+// @Description Provisioned users of the token's organization. Supports filter=userName eq "..." or externalId eq "...".
+// @Tags SCIM
+// @Produce json
+// @Security BearerAuth
+// @Param filter query string false "Equality filter"
+// @Param startIndex query int false "1-based index of the first result"
+// @Param count query int false "Page size (max 100)"
+// @Success 200 {object} authDomain.SCIMListResponse
+// @Router /scim/v2/Users [get]
+func (h *Handler) ListUsers(c *gin.Context) {
+	startIndex, count := pagination(c)
+	list, err := h.service.ListUsers(c.Request.Context(), token(c), c.Query("filter"), startIndex, count)
+	if err != nil {
+		h.renderError(c, err)
+		return
+	}
+	render(c, http.StatusOK, list)
+}
+
+// GetUser returns a provisioned user
+// @Summary Get SCIM user
+// @Tags SCIM
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} authDomain.SCIMUser
+// @Failure 404 {object} authDomain.SCIMError
+// @Router /scim/v2/Users/{id} [get]
+func (h *Handler) GetUser(c *gin.Context) {
+	user, err := h.service.GetUser(c.Request.Context(), token(c), c.Param("id"))
+	if err != nil {
+		h.renderError(c, err)
+		return
+	}
+	render(c, http.StatusOK, user)
+}
+
+// CreateUser provisions a user into the organization
+// @Summary Create SCIM user
+// @Description Creates the Brokle account if needed and adds it to the organization with the role of its groups (viewer by default)
+// @Tags SCIM
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param user body authDomain.SCIMUser true "SCIM user"
+// @Success 201 {object} authDomain.SCIMUser
+// @Failure 409 {object} authDomain.SCIMError "Already provisioned"
+// @Router /scim/v2/Users [post]
+func (h *Handler) CreateUser(c *gin.Context) {
+	var req authDomain.SCIMUser
+	if !h.bind(c, &req) {
+		return
+	}
+
+	user, err := h.service.CreateUser(c.Request.Context(), token(c), &req)
+	if err != nil {
+		h.renderError(c, err)
+		return
+	}
+	render(c, http.StatusCreated, user)
+}
+
+// ReplaceUser replaces a provisioned user
+// @Summary Replace SCIM user
+// @Description active=false removes the user from the organization and revokes their sessions
+// @Tags SCIM
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param user body authDomain.SCIMUser true "SCIM user"
+// @Success 200 {object} authDomain.SCIMUser
+// @Router /scim/v2/Users/{id} [put]
+func (h *Handler) ReplaceUser(c *gin.Context) {
+	var req authDomain.SCIMUser
+	if !h.bind(c, &req) {
+		return
+	}
+
+	user, err := h.service.ReplaceUser(c.Request.Context(), token(c), c.Param("id"), &req)
+	if err != nil {
+		h.renderError(c, err)
+		return
+	}
+	render(c, http.StatusOK, user)
+}
+
+// PatchUser updates a provisioned user
+// @Summary Patch SCIM user
+// @Description Supports active and externalId; deactivation removes the user from the organization and revokes their sessions
+// @Tags SCIM
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param patch body authDomain.SCIMPatchRequest true "PATCH operations"
+// @Success 200 {object} authDomain.SCIMUser
+// @Router /scim/v2/Users/{id} [patch]
+func (h *Handler) PatchUser(c *gin.Context) {
+	var req authDomain.SCIMPatchRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	user, err := h.service.PatchUser(c.Request.Context(), token(c), c.Param("id"), &req)
+	if err != nil {
+		h.renderError(c, err)
+		return
+	}
+	render(c, http.StatusOK, user)
+}
+
+// DeleteUser deprovisions a user
+// @Summary Delete SCIM user
+// @Description Removes the user from the organization and revokes their sessions. The Brokle account is kept.
+// @Tags SCIM
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 204 "No Content"
+// @Router /scim/v2/Users/{id} [delete]
+func (h *Handler) DeleteUser(c *gin.Context) {
+	if err := h.service.DeleteUser(c.Request.Context(), token(c), c.Param("id")); err != nil {
+		h.renderError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"brokle/internal/config"
+	"brokle/internal/core/domain/auth"
 	"brokle/internal/core/domain/organization"
 	"brokle/internal/core/domain/user"
 	"brokle/pkg/response"
@@ -21,16 +22,18 @@ type Handler struct {
 	userService         user.UserService
 	profileService      user.ProfileService
 	organizationService organization.OrganizationService
+	projectAccess       auth.ProjectAccessService
 }
 
 // NewHandler creates a new user handler
-func NewHandler(config *config.Config, logger *slog.Logger, userService user.UserService, profileService user.ProfileService, organizationService organization.OrganizationService) *Handler {
+func NewHandler(config *config.Config, logger *slog.Logger, userService user.UserService, profileService user.ProfileService, organizationService organization.OrganizationService, projectAccess auth.ProjectAccessService) *Handler {
 	return &Handler{
 		config:              config,
 		logger:              logger,
 		userService:         userService,
 		profileService:      profileService,
 		organizationService: organizationService,
+		projectAccess:       projectAccess,
 	}
 }
 
@@ -201,6 +204,17 @@ func (h *Handler) GetProfile(c *gin.Context) {
 		// Map projects with composite slugs
 		projectSummaries := make([]ProjectSummary, 0, len(orgData.Projects))
 		for _, proj := range orgData.Projects {
+			// Project roles can withhold projects the organization role would show
+			access, err := h.projectAccess.CheckUserProjectPermissions(c.Request.Context(), userID, proj.ID, []string{"projects:read"})
+			if err != nil {
+				h.logger.Error("Failed to check project access", "error", err, "user_id", userID, "project_id", proj.ID)
+				response.InternalServerError(c, "Failed to load organizations")
+				return
+			}
+			if !access["projects:read"] {
+				continue
+			}
+
 			projectCompositeSlug := utils.GenerateCompositeSlug(proj.Name, proj.ID)
 
 			projectSummaries = append(projectSummaries, ProjectSummary{
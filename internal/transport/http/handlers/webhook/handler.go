@@ -0,0 +1,352 @@
+package webhook
+
+import (
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+
+	webhookDomain "brokle/internal/core/domain/webhook"
+	"brokle/internal/transport/http/middleware"
+	appErrors "brokle/pkg/errors"
+	"brokle/pkg/response"
+	"brokle/pkg/ulid"
+)
+
+// Handler handles webhook subscription HTTP endpoints.
+type Handler struct {
+	logger  *slog.Logger
+	service webhookDomain.Service
+}
+
+// NewHandler creates a new webhook Handler.
+func NewHandler(logger *slog.Logger, service webhookDomain.Service) *Handler {
+	return &Handler{
+		logger:  logger,
+		service: service,
+	}
+}
+
+// @Summary List webhook event types
+// @Description Returns the event types a webhook subscription can filter on.
+// @Tags Webhooks
+// @Produce json
+// @Param projectId path string true "Project ID"
+// @Success 200 {object} EventTypesResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/webhooks/event-types [get]
+func (h *Handler) ListEventTypes(c *gin.Context) {
+	all := webhookDomain.AllEventTypes()
+	eventTypes := make([]string, len(all))
+	for i, t := range all {
+		eventTypes[i] = string(t)
+	}
+	response.Success(c, &EventTypesResponse{EventTypes: eventTypes})
+}
+
+// @Summary Create webhook
+// @Description Registers an endpoint that receives signed events. The signing secret is only returned in this response.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param projectId path string true "Project ID"
+// @Param request body webhook.CreateSubscriptionRequest true "Webhook request"
+// @Success 201 {object} SubscriptionResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse "Name already exists"
+// @Router /api/v1/projects/{projectId}/webhooks [post]
+func (h *Handler) Create(c *gin.Context) {
+	projectID, err := ulid.Parse(c.Param("projectId"))
+	if err != nil {
+		response.Error(c, appErrors.NewValidationError("projectId", "must be a valid ULID"))
+		return
+	}
+
+	var req webhookDomain.CreateSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Invalid request body", err.Error())
+		return
+	}
+
+	userID, exists := middleware.GetUserIDULID(c)
+	var userIDPtr *ulid.ULID
+	if exists {
+		userIDPtr = &userID
+	}
+
+	sub, err := h.service.Create(c.Request.Context(), projectID, userIDPtr, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, toSubscriptionResponse(sub, true))
+}
+
+// @Summary List webhooks
+// @Description Returns all webhook subscriptions for the project.
+// @Tags Webhooks
+// @Produce json
+// @Param projectId path string true "Project ID"
+// @Success 200 {array} SubscriptionResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/webhooks [get]
+func (h *Handler) List(c *gin.Context) {
+	projectID, err := ulid.Parse(c.Param("projectId"))
+	if err != nil {
+		response.Error(c, appErrors.NewValidationError("projectId", "must be a valid ULID"))
+		return
+	}
+
+	subs, err := h.service.List(c.Request.Context(), projectID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	responses := make([]*SubscriptionResponse, len(subs))
+	for i, sub := range subs {
+		responses[i] = toSubscriptionResponse(sub, false)
+	}
+
+	response.Success(c, responses)
+}
+
+// @Summary Get webhook
+// @Description Returns a webhook subscription by ID.
+// @Tags Webhooks
+// @Produce json
+// @Param projectId path string true "Project ID"
+// @Param webhookId path string true "Webhook ID"
+// @Success 200 {object} SubscriptionResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/webhooks/{webhookId} [get]
+func (h *Handler) Get(c *gin.Context) {
+	projectID, webhookID, ok := parseIDs(c)
+	if !ok {
+		return
+	}
+
+	sub, err := h.service.Get(c.Request.Context(), projectID, webhookID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, toSubscriptionResponse(sub, false))
+}
+
+// @Summary Update webhook
+// @Description Updates a webhook subscription. Omitted fields are left unchanged.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param projectId path string true "Project ID"
+// @Param webhookId path string true "Webhook ID"
+// @Param request body webhook.UpdateSubscriptionRequest true "Update request"
+// @Success 200 {object} SubscriptionResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse "Name already exists"
+// @Router /api/v1/projects/{projectId}/webhooks/{webhookId} [patch]
+func (h *Handler) Update(c *gin.Context) {
+	projectID, webhookID, ok := parseIDs(c)
+	if !ok {
+		return
+	}
+
+	var req webhookDomain.UpdateSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Invalid request body", err.Error())
+		return
+	}
+
+	sub, err := h.service.Update(c.Request.Context(), projectID, webhookID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, toSubscriptionResponse(sub, false))
+}
+
+// @Summary Delete webhook
+// @Description Deletes a webhook subscription and its delivery log.
+// @Tags Webhooks
+// @Param projectId path string true "Project ID"
+// @Param webhookId path string true "Webhook ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/webhooks/{webhookId} [delete]
+func (h *Handler) Delete(c *gin.Context) {
+	projectID, webhookID, ok := parseIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), projectID, webhookID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// @Summary Rotate webhook secret
+// @Description Generates a new signing secret. The previous secret stops working immediately.
+// @Tags Webhooks
+// @Produce json
+// @Param projectId path string true "Project ID"
+// @Param webhookId path string true "Webhook ID"
+// @Success 200 {object} SubscriptionResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/webhooks/{webhookId}/rotate-secret [post]
+func (h *Handler) RotateSecret(c *gin.Context) {
+	projectID, webhookID, ok := parseIDs(c)
+	if !ok {
+		return
+	}
+
+	sub, err := h.service.RotateSecret(c.Request.Context(), projectID, webhookID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, toSubscriptionResponse(sub, true))
+}
+
+// @Summary Send test event
+// @Description Queues a ping event for the webhook. The result appears in the delivery log.
+// @Tags Webhooks
+// @Produce json
+// @Param projectId path string true "Project ID"
+// @Param webhookId path string true "Webhook ID"
+// @Success 202 {object} DeliveryResponse
+// @Failure 400 {object} response.ErrorResponse "Webhook is disabled"
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/webhooks/{webhookId}/test [post]
+func (h *Handler) SendTest(c *gin.Context) {
+	projectID, webhookID, ok := parseIDs(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.service.SendTest(c.Request.Context(), projectID, webhookID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Accepted(c, toDeliveryResponse(delivery))
+}
+
+// @Summary List webhook deliveries
+// @Description Returns the delivery log for a webhook, newest first.
+// @Tags Webhooks
+// @Produce json
+// @Param projectId path string true "Project ID"
+// @Param webhookId path string true "Webhook ID"
+// @Param page query int false "Page number (default 1)"
+// @Param limit query int false "Items per page (10, 25, 50, 100; default 50)"
+// @Param status query string false "Filter by status (pending, succeeded, failed)"
+// @Param event_type query string false "Filter by event type"
+// @Success 200 {object} response.ListResponse{data=[]DeliveryResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/webhooks/{webhookId}/deliveries [get]
+func (h *Handler) ListDeliveries(c *gin.Context) {
+	projectID, webhookID, ok := parseIDs(c)
+	if !ok {
+		return
+	}
+
+	params := response.ParsePaginationParams(c.Query("page"), c.Query("limit"), "", "")
+
+	filter := &webhookDomain.DeliveryFilter{}
+	if status := c.Query("status"); status != "" {
+		deliveryStatus := webhookDomain.DeliveryStatus(status)
+		if !deliveryStatus.IsValid() {
+			response.Error(c, appErrors.NewValidationError("status", "must be one of pending, succeeded, failed"))
+			return
+		}
+		filter.Status = &deliveryStatus
+	}
+	if eventType := c.Query("event_type"); eventType != "" {
+		et := webhookDomain.EventType(eventType)
+		filter.EventType = &et
+	}
+
+	deliveries, total, err := h.service.ListDeliveries(c.Request.Context(), projectID, webhookID, filter, params.Page, params.Limit)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	responses := make([]*DeliveryResponse, len(deliveries))
+	for i, d := range deliveries {
+		responses[i] = toDeliveryResponse(d)
+	}
+
+	pag := response.NewPagination(params.Page, params.Limit, total)
+	response.SuccessWithPagination(c, responses, pag)
+}
+
+// @Summary Redeliver webhook event
+// @Description Queues a new delivery of a previous event with the same event ID and payload.
+// @Tags Webhooks
+// @Produce json
+// @Param projectId path string true "Project ID"
+// @Param webhookId path string true "Webhook ID"
+// @Param deliveryId path string true "Delivery ID"
+// @Success 202 {object} DeliveryResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/projects/{projectId}/webhooks/{webhookId}/deliveries/{deliveryId}/redeliver [post]
+func (h *Handler) Redeliver(c *gin.Context) {
+	projectID, webhookID, ok := parseIDs(c)
+	if !ok {
+		return
+	}
+
+	deliveryID, err := ulid.Parse(c.Param("deliveryId"))
+	if err != nil {
+		response.Error(c, appErrors.NewValidationError("deliveryId", "must be a valid ULID"))
+		return
+	}
+
+	delivery, err := h.service.Redeliver(c.Request.Context(), projectID, webhookID, deliveryID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Accepted(c, toDeliveryResponse(delivery))
+}
+
+func parseIDs(c *gin.Context) (ulid.ULID, ulid.ULID, bool) {
+	projectID, err := ulid.Parse(c.Param("projectId"))
+	if err != nil {
+		response.Error(c, appErrors.NewValidationError("projectId", "must be a valid ULID"))
+		return ulid.ULID{}, ulid.ULID{}, false
+	}
+
+	webhookID, err := ulid.Parse(c.Param("webhookId"))
+	if err != nil {
+		response.Error(c, appErrors.NewValidationError("webhookId", "must be a valid ULID"))
+		return ulid.ULID{}, ulid.ULID{}, false
+	}
+
+	return projectID, webhookID, true
+}
@@ -0,0 +1,91 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+
+	webhookDomain "brokle/internal/core/domain/webhook"
+)
+
+// SubscriptionResponse represents a webhook subscription in API responses.
+// @Description Webhook subscription data
+type SubscriptionResponse struct {
+	ID          string    `json:"id"`
+	ProjectID   string    `json:"project_id"`
+	Name        string    `json:"name"`
+	Description *string   `json:"description,omitempty"`
+	URL         string    `json:"url"`
+	EventTypes  []string  `json:"event_types"`
+	Enabled     bool      `json:"enabled"`
+	Secret      string    `json:"secret,omitempty"` // Only returned on create and rotate-secret
+	CreatedBy   *string   `json:"created_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// DeliveryResponse represents a single webhook delivery attempt log entry.
+// @Description Webhook delivery data
+type DeliveryResponse struct {
+	ID             string          `json:"id"`
+	SubscriptionID string          `json:"subscription_id"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	ResponseCode   *int            `json:"response_code,omitempty"`
+	Error          *string         `json:"error,omitempty"`
+	DurationMs     *int64          `json:"duration_ms,omitempty"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// EventTypesResponse lists the event types a subscription can filter on.
+// @Description Available webhook event types
+type EventTypesResponse struct {
+	EventTypes []string `json:"event_types"`
+}
+
+func toSubscriptionResponse(sub *webhookDomain.Subscription, includeSecret bool) *SubscriptionResponse {
+	resp := &SubscriptionResponse{
+		ID:          sub.ID.String(),
+		ProjectID:   sub.ProjectID.String(),
+		Name:        sub.Name,
+		Description: sub.Description,
+		URL:         sub.URL,
+		EventTypes:  sub.EventTypes,
+		Enabled:     sub.Enabled,
+		CreatedAt:   sub.CreatedAt,
+		UpdatedAt:   sub.UpdatedAt,
+	}
+	if resp.EventTypes == nil {
+		resp.EventTypes = []string{}
+	}
+	if includeSecret {
+		resp.Secret = sub.Secret
+	}
+	if sub.CreatedBy != nil {
+		createdBy := sub.CreatedBy.String()
+		resp.CreatedBy = &createdBy
+	}
+	return resp
+}
+
+func toDeliveryResponse(d *webhookDomain.Delivery) *DeliveryResponse {
+	return &DeliveryResponse{
+		ID:             d.ID.String(),
+		SubscriptionID: d.SubscriptionID.String(),
+		EventID:        d.EventID.String(),
+		EventType:      string(d.EventType),
+		Payload:        d.Payload,
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		ResponseCode:   d.ResponseCode,
+		Error:          d.Error,
+		DurationMs:     d.DurationMs,
+		NextAttemptAt:  d.NextAttemptAt,
+		DeliveredAt:    d.DeliveredAt,
+		CreatedAt:      d.CreatedAt,
+	}
+}
@@ -3,10 +3,12 @@ package middleware
 import (
 	"log/slog"
 	"errors"
+	"fmt"
 
 	"github.com/gin-gonic/gin"
 
 	"brokle/internal/core/domain/auth"
+	appErrors "brokle/pkg/errors"
 	"brokle/pkg/response"
 	"brokle/pkg/ulid"
 )
@@ -16,6 +18,7 @@ type AuthMiddleware struct {
 	jwtService        auth.JWTService
 	blacklistedTokens auth.BlacklistedTokenService
 	orgMemberService  auth.OrganizationMemberService
+	projectAccess     auth.ProjectAccessService
 	logger            *slog.Logger
 }
 
@@ -24,12 +27,14 @@ func NewAuthMiddleware(
 	jwtService auth.JWTService,
 	blacklistedTokens auth.BlacklistedTokenService,
 	orgMemberService auth.OrganizationMemberService,
+	projectAccess auth.ProjectAccessService,
 	logger *slog.Logger,
 ) *AuthMiddleware {
 	return &AuthMiddleware{
 		jwtService:        jwtService,
 		blacklistedTokens: blacklistedTokens,
 		orgMemberService:  orgMemberService,
+		projectAccess:     projectAccess,
 		logger:            logger,
 	}
 }
@@ -101,6 +106,7 @@ func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 		c.Set(AuthContextKey, authContext)
 		c.Set(UserIDKey, claims.UserID)
 		c.Set(TokenClaimsKey, claims)
+		setAuditActor(c, auth.AuditActor{UserID: &claims.UserID})
 
 		// Log successful authentication
 		m.logger.Debug("Authentication successful", "user_id", claims.UserID, "jti", claims.JWTID)
@@ -112,33 +118,8 @@ func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 // RequirePermission middleware ensures user has specific permission with effective permissions
 func (m *AuthMiddleware) RequirePermission(permission string) gin.HandlerFunc {
 	return gin.HandlerFunc(func(c *gin.Context) {
-		// Get user ID from context
-		userID, exists := GetUserID(c)
-		if !exists {
-			m.logger.Warn("Permission check attempted without authentication")
-			response.Unauthorized(c, "Authentication required")
-			c.Abort()
-			return
-		}
-
-		userIDParsed, err := ulid.Parse(userID)
-		if err != nil {
-			m.logger.Error("Invalid user ID format in context", "error", err)
-			response.InternalServerError(c, "Authentication error")
-			c.Abort()
-			return
-		}
-
-		// Check permission using user's effective permissions
-		hasPermission, err := m.orgMemberService.CheckUserPermissions(
-			c.Request.Context(),
-			userIDParsed,
-			[]string{permission},
-		)
-		if err != nil {
-			m.logger.Error("Failed to check user permissions", "error", err, "user_id", userID, "permission", permission)
-			response.InternalServerError(c, "Permission verification failed")
-			c.Abort()
+		userID, hasPermission, ok := m.checkPermissions(c, []string{permission})
+		if !ok {
 			return
 		}
 
@@ -157,32 +138,8 @@ func (m *AuthMiddleware) RequirePermission(permission string) gin.HandlerFunc {
 // RequireAnyPermission middleware ensures user has at least one of the specified permissions
 func (m *AuthMiddleware) RequireAnyPermission(permissions []string) gin.HandlerFunc {
 	return gin.HandlerFunc(func(c *gin.Context) {
-		// Get user ID from context
-		userID, exists := GetUserID(c)
-		if !exists {
-			response.Unauthorized(c, "Authentication required")
-			c.Abort()
-			return
-		}
-
-		userIDParsed, err := ulid.Parse(userID)
-		if err != nil {
-			m.logger.Error("Invalid user ID format in context", "error", err)
-			response.InternalServerError(c, "Authentication error")
-			c.Abort()
-			return
-		}
-
-		// Check if user has ANY of the permissions using effective permissions API
-		hasPermission, err := m.orgMemberService.CheckUserPermissions(
-			c.Request.Context(),
-			userIDParsed,
-			permissions,
-		)
-		if err != nil {
-			m.logger.Error("Failed to check user permissions", "error", err, "user_id", userID, "permissions", permissions)
-			response.InternalServerError(c, "Permission verification failed")
-			c.Abort()
+		userID, hasPermission, ok := m.checkPermissions(c, permissions)
+		if !ok {
 			return
 		}
 
@@ -209,32 +166,8 @@ func (m *AuthMiddleware) RequireAnyPermission(permissions []string) gin.HandlerF
 // RequireAllPermissions middleware ensures user has ALL specified permissions
 func (m *AuthMiddleware) RequireAllPermissions(permissions []string) gin.HandlerFunc {
 	return gin.HandlerFunc(func(c *gin.Context) {
-		// Get user ID from context
-		userID, exists := GetUserID(c)
-		if !exists {
-			response.Unauthorized(c, "Authentication required")
-			c.Abort()
-			return
-		}
-
-		userIDParsed, err := ulid.Parse(userID)
-		if err != nil {
-			m.logger.Error("Invalid user ID format in context", "error", err)
-			response.InternalServerError(c, "Authentication error")
-			c.Abort()
-			return
-		}
-
-		// Check all permissions using effective permissions API
-		hasPermission, err := m.orgMemberService.CheckUserPermissions(
-			c.Request.Context(),
-			userIDParsed,
-			permissions,
-		)
-		if err != nil {
-			m.logger.Error("Failed to check user permissions", "error", err, "user_id", userID, "permissions", permissions)
-			response.InternalServerError(c, "Permission verification failed")
-			c.Abort()
+		userID, hasPermission, ok := m.checkPermissions(c, permissions)
+		if !ok {
 			return
 		}
 
@@ -252,6 +185,122 @@ func (m *AuthMiddleware) RequireAllPermissions(permissions []string) gin.Handler
 	})
 }
 
+// RequireProjectPermission middleware ensures user has a specific permission in the
+// project the request acts on. It is for routes outside /projects/:projectId that take
+// the project from the X-Project-ID header or project_id query: the request must name
+// exactly one project, so the check can never fall back to organization roles.
+func (m *AuthMiddleware) RequireProjectPermission(permission string) gin.HandlerFunc {
+	return gin.HandlerFunc(func(c *gin.Context) {
+		projectID, err := requestProjectID(c)
+		if err == nil && projectID == nil {
+			err = errors.New("project_id is required")
+		}
+		if err != nil {
+			response.BadRequest(c, "Invalid project context", err.Error())
+			c.Abort()
+			return
+		}
+
+		userID, hasPermission, ok := m.checkProjectPermissions(c, projectID, []string{permission})
+		if !ok {
+			return
+		}
+
+		if !hasPermission[permission] {
+			m.logger.Warn("Insufficient project permissions", "user_id", userID, "project_id", projectID, "permission", permission)
+			response.Forbidden(c, "Insufficient permissions")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	})
+}
+
+// checkPermissions resolves which of the permissions the authenticated user holds.
+// On project routes they come from the user's role in that project, elsewhere from
+// their organization roles. It responds and aborts on failure.
+func (m *AuthMiddleware) checkPermissions(c *gin.Context, permissions []string) (ulid.ULID, map[string]bool, bool) {
+	return m.checkProjectPermissions(c, permissionProjectID(c), permissions)
+}
+
+// checkProjectPermissions checks the permissions in the project, or against the
+// organization roles when projectID is nil. It responds and aborts on failure.
+func (m *AuthMiddleware) checkProjectPermissions(c *gin.Context, projectID *ulid.ULID, permissions []string) (ulid.ULID, map[string]bool, bool) {
+	userID, exists := GetUserIDULID(c)
+	if !exists {
+		m.logger.Warn("Permission check attempted without authentication")
+		response.Unauthorized(c, "Authentication required")
+		c.Abort()
+		return userID, nil, false
+	}
+
+	var hasPermission map[string]bool
+	var err error
+	if projectID != nil {
+		hasPermission, err = m.projectAccess.CheckUserProjectPermissions(c.Request.Context(), userID, *projectID, permissions)
+		if appErrors.IsNotFound(err) {
+			// Unknown projects grant nothing; the handler never runs
+			m.logger.Warn("Permission check on unknown project", "user_id", userID, "project_id", projectID, "permissions", permissions)
+			response.Forbidden(c, "Insufficient permissions")
+			c.Abort()
+			return userID, nil, false
+		}
+	} else {
+		// Check permission using user's effective permissions
+		hasPermission, err = m.orgMemberService.CheckUserPermissions(c.Request.Context(), userID, permissions)
+	}
+	if err != nil {
+		m.logger.Error("Failed to check user permissions", "error", err, "user_id", userID, "permissions", permissions)
+		response.InternalServerError(c, "Permission verification failed")
+		c.Abort()
+		return userID, nil, false
+	}
+
+	return userID, hasPermission, true
+}
+
+// permissionProjectID returns the project a RequirePermission check is scoped to.
+// Only the path counts there: those routes are either project routes, which act on
+// :projectId, or organization routes, which a project role must not reach through
+// a header. Routes that take the project elsewhere use RequireProjectPermission.
+func permissionProjectID(c *gin.Context) *ulid.ULID {
+	projectID, err := ulid.Parse(c.Param("projectId"))
+	if err != nil {
+		return nil
+	}
+	return &projectID
+}
+
+// requestProjectID resolves the project like ResolveProjectID, from the X-Project-ID
+// header or the projectId path parameter, and also from the project_id query the
+// dashboard routes use. Every place that names a project must name the same one, so
+// the project checked is the project the handler acts on.
+func requestProjectID(c *gin.Context) (*ulid.ULID, error) {
+	var projectID *ulid.ULID
+	for _, value := range []string{c.GetHeader("X-Project-ID"), c.Param("projectId"), c.Query("project_id")} {
+		if value == "" {
+			continue
+		}
+		parsed, err := ulid.Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid project ID %q: %w", value, err)
+		}
+		if projectID != nil && *projectID != parsed {
+			return nil, fmt.Errorf("conflicting project IDs %s and %s", *projectID, parsed)
+		}
+		projectID = &parsed
+	}
+	return projectID, nil
+}
+
+// setAuditActor attributes the mutations made by this request in the audit log
+func setAuditActor(c *gin.Context, actor auth.AuditActor) {
+	actor.IPAddress = c.ClientIP()
+	actor.UserAgent = c.Request.UserAgent()
+	c.Request = c.Request.WithContext(auth.WithAuditActor(c.Request.Context(), actor))
+}
+
 // OptionalAuth middleware extracts auth info if present but doesn't require it
 func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 	return gin.HandlerFunc(func(c *gin.Context) {
@@ -284,6 +333,7 @@ func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 		c.Set(AuthContextKey, authContext)
 		c.Set(UserIDKey, claims.UserID)
 		c.Set(TokenClaimsKey, claims)
+		setAuditActor(c, auth.AuditActor{UserID: &claims.UserID})
 
 		c.Next()
 	})
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"brokle/internal/core/domain/auth"
+	"brokle/pkg/ulid"
+)
+
+type fakeProjectAccess struct {
+	auth.ProjectAccessService
+	granted map[ulid.ULID][]string
+}
+
+func (f *fakeProjectAccess) CheckUserProjectPermissions(ctx context.Context, userID, projectID ulid.ULID, permissions []string) (map[string]bool, error) {
+	result := make(map[string]bool, len(permissions))
+	for _, permission := range permissions {
+		for _, granted := range f.granted[projectID] {
+			if granted == permission {
+				result[permission] = true
+			}
+		}
+	}
+	return result, nil
+}
+
+// fakeOrgMembers grants every permission, so a fallback to organization roles shows up as a 200
+type fakeOrgMembers struct {
+	auth.OrganizationMemberService
+}
+
+func (fakeOrgMembers) CheckUserPermissions(ctx context.Context, userID ulid.ULID, permissions []string) (map[string]bool, error) {
+	result := make(map[string]bool, len(permissions))
+	for _, permission := range permissions {
+		result[permission] = true
+	}
+	return result, nil
+}
+
+func TestRequireProjectPermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	readable, other := ulid.New(), ulid.New()
+	m := NewAuthMiddleware(nil, nil, fakeOrgMembers{}, &fakeProjectAccess{granted: map[ulid.ULID][]string{
+		readable: {"traces:read"},
+	}}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	tests := []struct {
+		name   string
+		query  string
+		header string
+		want   int
+	}{
+		{"query", "?project_id=" + readable.String(), "", http.StatusOK},
+		{"header", "", readable.String(), http.StatusOK},
+		{"no role in project", "?project_id=" + other.String(), "", http.StatusForbidden},
+		{"header and query disagree", "?project_id=" + readable.String(), other.String(), http.StatusBadRequest},
+		{"invalid project", "?project_id=nope", "", http.StatusBadRequest},
+		{"no project never falls back to organization roles", "", "", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(func(c *gin.Context) { c.Set(UserIDKey, ulid.New()) })
+			router.GET("/traces", m.RequireProjectPermission("traces:read"), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/traces"+tt.query, nil)
+			if tt.header != "" {
+				req.Header.Set("X-Project-ID", tt.header)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.want, w.Code)
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"log/slog"
 	"context"
 	"fmt"
+	"math"
+	"net/http"
 	"strconv"
 	"time"
 
@@ -11,27 +13,31 @@ import (
 	"github.com/redis/go-redis/v9"
 
 	"brokle/internal/config"
+	"brokle/internal/core/domain/auth"
+	appErrors "brokle/pkg/errors"
 	"brokle/pkg/response"
-	"brokle/pkg/ulid"
 )
 
 // RateLimitMiddleware handles Redis-based rate limiting
 type RateLimitMiddleware struct {
-	redis  *redis.Client
-	config *config.AuthConfig
-	logger *slog.Logger
+	redis            *redis.Client
+	config           *config.AuthConfig
+	rateLimitService auth.RateLimitService
+	logger           *slog.Logger
 }
 
 // NewRateLimitMiddleware creates a new rate limiting middleware
 func NewRateLimitMiddleware(
 	redis *redis.Client,
 	config *config.AuthConfig,
+	rateLimitService auth.RateLimitService,
 	logger *slog.Logger,
 ) *RateLimitMiddleware {
 	return &RateLimitMiddleware{
-		redis:  redis,
-		config: config,
-		logger: logger,
+		redis:            redis,
+		config:           config,
+		rateLimitService: rateLimitService,
+		logger:           logger,
 	}
 }
 
@@ -112,7 +118,8 @@ func (m *RateLimitMiddleware) RateLimitByUser() gin.HandlerFunc {
 	}
 }
 
-// RateLimitByAPIKey implements API key-based rate limiting
+// RateLimitByAPIKey enforces the per-key and per-project request rate and daily payload
+// quota (from Content-Length) shared across replicas. Must run after RequireSDKAuth.
 func (m *RateLimitMiddleware) RateLimitByAPIKey() gin.HandlerFunc {
 	if !m.config.RateLimitEnabled {
 		return func(c *gin.Context) {
@@ -121,29 +128,28 @@ func (m *RateLimitMiddleware) RateLimitByAPIKey() gin.HandlerFunc {
 	}
 
 	return func(c *gin.Context) {
-		var apiKeyID string
-
-		// Try new SDK auth context first (preferred)
-		if keyID, exists := c.Get(APIKeyIDKey); exists {
-			if ulidKey, ok := keyID.(*ulid.ULID); ok {
-				apiKeyID = ulidKey.String()
-			}
-		} else if oldKey, exists := c.Get("api_key"); exists {
-			// Fallback for any remaining old usage (temporary compatibility)
-			apiKeyID = fmt.Sprintf("%v", oldKey)
-		} else {
+		apiKeyID, keyExists := GetAPIKeyID(c)
+		projectID, projectExists := GetProjectID(c)
+		if !keyExists || !projectExists || apiKeyID == nil || projectID == nil {
 			// No API key context found, skip rate limiting
 			m.logger.Debug("No API key context found for rate limiting")
 			c.Next()
 			return
 		}
 
-		key := "rate_limit:apikey:" + apiKeyID
-
-		// API keys typically have higher limits (5x user limits)
-		apiKeyLimit := m.config.RateLimitPerUser * 5
+		cost := auth.RateLimitCost{Requests: 1}
+		if c.Request.ContentLength > 0 {
+			// net/http stops reading the body at Content-Length, so it is the payload size
+			cost.Bytes = c.Request.ContentLength
+		} else if c.Request.ContentLength < 0 && m.rateLimitService.EnforcesPayloadQuota(c.Request.Context(), *projectID, *apiKeyID) {
+			// A chunked body of unknown size would bypass the payload quota
+			response.ErrorWithStatus(c, http.StatusLengthRequired, string(appErrors.BadRequestError),
+				"Content-Length is required while a payload quota applies", "")
+			c.Abort()
+			return
+		}
 
-		allowed, err := m.checkRateLimit(c.Request.Context(), key, apiKeyLimit, m.config.RateLimitWindow)
+		decision, err := m.rateLimitService.Check(c.Request.Context(), *projectID, *apiKeyID, cost)
 		if err != nil {
 			m.logger.Error("API key rate limit check failed", "error", err, "api_key_id", apiKeyID)
 			// On error, allow request to continue (fail open for availability)
@@ -151,20 +157,54 @@ func (m *RateLimitMiddleware) RateLimitByAPIKey() gin.HandlerFunc {
 			return
 		}
 
-		if !allowed {
-			m.logger.Warn("Rate limit exceeded for API key", "api_key_id", apiKeyID)
-			response.TooManyRequests(c, "API key rate limit exceeded. Please try again later.")
+		if !decision.Allowed {
+			m.logger.Warn("Rate limit exceeded for API key", "api_key_id", apiKeyID, "project_id", projectID, "scope", decision.Scope, "dimension", decision.Dimension, "limit", decision.Limit)
+			RateLimitExceeded(c, decision)
 			c.Abort()
 			return
 		}
 
-		// Log successful rate limit check
-		m.logger.Debug("API key rate limit check passed", "api_key_id", apiKeyID, "limit", apiKeyLimit)
-
+		WriteRateLimitHeaders(c, decision)
 		c.Next()
 	}
 }
 
+// WriteRateLimitHeaders sets X-RateLimit-* headers describing the limit closest to exhaustion
+func WriteRateLimitHeaders(c *gin.Context, decision *auth.RateLimitDecision) {
+	if !decision.Limited() {
+		return
+	}
+	c.Header("X-RateLimit-Limit", strconv.FormatInt(decision.Limit, 10))
+	c.Header("X-RateLimit-Remaining", strconv.FormatInt(decision.Remaining, 10))
+	c.Header("X-RateLimit-Reset", strconv.FormatInt(decision.ResetAt.Unix(), 10))
+	c.Header("X-RateLimit-Resource", fmt.Sprintf("%s:%s", decision.Scope, decision.Dimension))
+}
+
+// RateLimitExceeded responds 429 with Retry-After (whole seconds), which OTLP exporters
+// honour when backing off. Request rates map to rate_limit_error, quotas to quota_exceeded.
+func RateLimitExceeded(c *gin.Context, decision *auth.RateLimitDecision) {
+	WriteRateLimitHeaders(c, decision)
+
+	retryAfter := int64(math.Ceil(decision.RetryAfter.Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	c.Header("Retry-After", strconv.FormatInt(retryAfter, 10))
+
+	scope := "API key"
+	if decision.Scope == auth.RateLimitScopeProject {
+		scope = "Project"
+	}
+	switch decision.Dimension {
+	case auth.RateLimitSpans:
+		response.QuotaExceeded(c, fmt.Sprintf("%s span quota of %d spans per minute exceeded", scope, decision.Limit))
+	case auth.RateLimitBytes:
+		response.QuotaExceeded(c, fmt.Sprintf("%s payload quota of %d bytes per day exceeded", scope, decision.Limit))
+	default:
+		response.TooManyRequests(c, fmt.Sprintf("%s rate limit of %d requests per second exceeded. Please try again later.", scope, decision.Limit))
+	}
+}
+
 // checkRateLimit implements sliding window rate limiting using Redis
 func (m *RateLimitMiddleware) checkRateLimit(ctx context.Context, key string, limit int, window time.Duration) (bool, error) {
 	now := time.Now()
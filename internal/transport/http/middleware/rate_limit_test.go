@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"brokle/internal/config"
+	"brokle/internal/core/domain/auth"
+	"brokle/pkg/ulid"
+)
+
+// fakeRateLimitService allows every request and records the cost it was charged
+type fakeRateLimitService struct {
+	auth.RateLimitService
+	payloadQuota bool
+	charged      *auth.RateLimitCost
+}
+
+func (f *fakeRateLimitService) Check(ctx context.Context, projectID, keyID ulid.ULID, cost auth.RateLimitCost) (*auth.RateLimitDecision, error) {
+	f.charged = &cost
+	return &auth.RateLimitDecision{Allowed: true}, nil
+}
+
+func (f *fakeRateLimitService) EnforcesPayloadQuota(ctx context.Context, projectID, keyID ulid.ULID) bool {
+	return f.payloadQuota
+}
+
+func TestRateLimitByAPIKey_PayloadBytes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		chunked      bool
+		payloadQuota bool
+		want         int
+		wantBytes    int64
+	}{
+		{"content length is charged", false, true, http.StatusOK, 5},
+		{"chunked body without payload quota", true, false, http.StatusOK, 0},
+		{"chunked body with payload quota", true, true, http.StatusLengthRequired, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &fakeRateLimitService{payloadQuota: tt.payloadQuota}
+			m := NewRateLimitMiddleware(nil, &config.AuthConfig{RateLimitEnabled: true}, service, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			keyID, projectID := ulid.New(), ulid.New()
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				c.Set(APIKeyIDKey, &keyID)
+				c.Set(ProjectIDKey, &projectID)
+			})
+			router.POST("/v1/traces", m.RateLimitByAPIKey(), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/traces", strings.NewReader("spans"))
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.want, w.Code)
+			if tt.want == http.StatusOK {
+				assert.Equal(t, tt.wantBytes, service.charged.Bytes)
+			} else {
+				assert.Nil(t, service.charged)
+			}
+		})
+	}
+}
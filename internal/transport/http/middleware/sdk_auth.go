@@ -75,6 +75,7 @@ func (m *SDKAuthMiddleware) RequireSDKAuth() gin.HandlerFunc {
 		c.Set(ProjectIDKey, &projectID)
 		organizationID := validateResp.OrganizationID
 		c.Set(OrganizationIDKey, &organizationID)
+		setAuditActor(c, auth.AuditActor{APIKeyID: validateResp.AuthContext.APIKeyID})
 
 		// Log successful SDK authentication
 		m.logger.Debug("SDK authentication successful", "api_key_id", validateResp.AuthContext.APIKeyID, "project_id", validateResp.ProjectID, "organization_id", validateResp.OrganizationID)
@@ -83,6 +84,35 @@ func (m *SDKAuthMiddleware) RequireSDKAuth() gin.HandlerFunc {
 	})
 }
 
+// RequireAPIKeyScope middleware ensures the authenticated API key was granted all
+// of the given scopes. Must run after RequireSDKAuth.
+//
+// Usage:
+//
+//	prompts.GET("/:name", sdkAuthMiddleware.RequireAPIKeyScope("prompts:read"), handler.GetPromptByName)
+func (m *SDKAuthMiddleware) RequireAPIKeyScope(scopes ...string) gin.HandlerFunc {
+	return gin.HandlerFunc(func(c *gin.Context) {
+		authContext, ok := GetSDKAuthContext(c)
+		if !ok {
+			m.logger.Warn("API key scope check attempted without SDK authentication")
+			response.Unauthorized(c, "API key required")
+			c.Abort()
+			return
+		}
+
+		for _, scope := range scopes {
+			if !authContext.HasAPIKeyScope(scope) {
+				m.logger.Warn("API key missing required scope", "api_key_id", authContext.APIKeyID, "scope", scope, "path", c.FullPath())
+				response.Forbidden(c, "API key is missing required scope: "+scope)
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	})
+}
+
 // Helper functions to get SDK auth data from Gin context
 
 // GetSDKAuthContext retrieves SDK authentication context from Gin context
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"brokle/internal/core/domain/auth"
+)
+
+func TestRequireAPIKeyScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := NewSDKAuthMiddleware(nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	tests := []struct {
+		name     string
+		scopes   []string
+		required []string
+		want     int
+	}{
+		{"granted", []string{"traces:create"}, []string{"traces:create"}, http.StatusOK},
+		{"ingestion key reading traces", []string{"traces:create"}, []string{"traces:read"}, http.StatusForbidden},
+		{"ingestion key fetching prompts", []string{"traces:create"}, []string{"prompts:read"}, http.StatusForbidden},
+		{"trace ingestion key sending logs", []string{"traces:create"}, []string{"logs:create"}, http.StatusForbidden},
+		{"metrics-only key", []string{"metrics:create"}, []string{"metrics:create"}, http.StatusOK},
+		{"requires all scopes", []string{"datasets:write"}, []string{"datasets:write", "traces:read"}, http.StatusForbidden},
+		{"unauthenticated", nil, []string{"traces:create"}, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				if tt.scopes != nil {
+					c.Set(SDKAuthContextKey, &auth.AuthContext{Scopes: tt.scopes})
+				}
+			})
+			router.GET("/resource", m.RequireAPIKeyScope(tt.required...), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/resource", nil))
+			assert.Equal(t, tt.want, w.Code)
+		})
+	}
+}
@@ -43,13 +43,16 @@ func NewServer(
 	jwtService auth.JWTService,
 	blacklistedTokens auth.BlacklistedTokenService,
 	orgMemberService auth.OrganizationMemberService,
+	projectAccessService auth.ProjectAccessService,
 	apiKeyService auth.APIKeyService,
+	rateLimitService auth.RateLimitService,
 	redisClient *redis.Client,
 ) *Server {
 	authMiddleware := middleware.NewAuthMiddleware(
 		jwtService,
 		blacklistedTokens,
 		orgMemberService,
+		projectAccessService,
 		logger,
 	)
 
@@ -61,6 +64,7 @@ func NewServer(
 	rateLimitMiddleware := middleware.NewRateLimitMiddleware(
 		redisClient,
 		&cfg.Auth,
+		rateLimitService,
 		logger,
 	)
 
@@ -180,6 +184,12 @@ func (s *Server) setupRoutes() {
 	dashboard := s.engine.Group("/api/v1")
 	s.setupDashboardRoutes(dashboard)
 
+	// SCIM 2.0 provisioning, authenticated by organization SCIM tokens
+	scim := s.engine.Group("/scim/v2")
+	scim.Use(s.rateLimitMiddleware.RateLimitByIP())
+	scim.Use(s.handlers.SCIM.Authenticate())
+	s.setupSCIMRoutes(scim)
+
 	s.engine.GET("/ws", s.handlers.WebSocket.Handle)
 }
 
@@ -199,9 +209,18 @@ func (s *Server) setupDashboardRoutes(router *gin.RouterGroup) {
 		auth.GET("/google/callback", s.handlers.Auth.GoogleCallback)
 		auth.GET("/github", s.handlers.Auth.InitiateGitHubOAuth)
 		auth.GET("/github/callback", s.handlers.Auth.GitHubCallback)
+		auth.GET("/mfa/challenge/:mfa_token", s.handlers.Auth.GetMFAChallenge)
+		auth.POST("/mfa/verify", s.handlers.Auth.VerifyMFA) // Second login step, authenticated by the MFA token
+		auth.GET("/sso/login", s.handlers.Auth.InitiateSSOLogin)
+		auth.GET("/sso/oidc/callback", s.handlers.Auth.SSOOIDCCallback)
+		auth.POST("/sso/saml/acs", s.handlers.Auth.SSOSAMLACS) // Cross-site POST from the IdP, authenticated by the signed assertion
+		auth.GET("/sso/saml/metadata/:orgId", s.handlers.Auth.GetSSOMetadata)
 	}
 
 	router.GET("/invitations/validate/:token", s.handlers.Organization.ValidateInvitationToken)
+
+	// Public trace share links (signed token, no login)
+	router.GET("/public/shares/:token", s.handlers.Observability.ViewSharedTrace)
 	router.POST("/invitations/decline", s.handlers.Organization.DeclineInvitation)
 
 	// Protected routes: JWT → CSRF → rate limit
@@ -232,6 +251,12 @@ func (s *Server) setupDashboardRoutes(router *gin.RouterGroup) {
 		authSessions.GET("/sessions/:session_id", s.handlers.Auth.GetSession)
 		authSessions.POST("/sessions/:session_id/revoke", s.handlers.Auth.RevokeSession)
 		authSessions.POST("/sessions/revoke-all", s.handlers.Auth.RevokeAllSessions)
+
+		authSessions.GET("/mfa", s.handlers.Auth.GetMFAStatus)
+		authSessions.DELETE("/mfa", s.handlers.Auth.DisableMFA)
+		authSessions.POST("/mfa/enroll", s.handlers.Auth.BeginMFAEnrollment)
+		authSessions.POST("/mfa/enroll/verify", s.handlers.Auth.ConfirmMFAEnrollment)
+		authSessions.POST("/mfa/recovery-codes", s.handlers.Auth.RegenerateMFARecoveryCodes)
 	}
 
 	orgs := protected.Group("/organizations")
@@ -244,6 +269,24 @@ func (s *Server) setupDashboardRoutes(router *gin.RouterGroup) {
 		orgs.GET("/:orgId/members", s.authMiddleware.RequirePermission("members:read"), s.handlers.Organization.ListMembers)
 		orgs.POST("/:orgId/members", s.authMiddleware.RequirePermission("members:invite"), s.handlers.Organization.InviteMember)
 		orgs.DELETE("/:orgId/members/:userId", s.authMiddleware.RequirePermission("members:remove"), s.handlers.Organization.RemoveMember)
+		orgs.POST("/:orgId/members/:userId/mfa/reset", s.authMiddleware.RequirePermission("members:update"), s.handlers.Auth.ResetMemberMFA)
+
+		// Enterprise SSO connection
+		orgs.GET("/:orgId/sso", s.authMiddleware.RequirePermission("settings:security"), s.handlers.Auth.GetSSOConnection)
+		orgs.PUT("/:orgId/sso", s.authMiddleware.RequirePermission("settings:security"), s.handlers.Auth.ConfigureSSOConnection)
+		orgs.DELETE("/:orgId/sso", s.authMiddleware.RequirePermission("settings:security"), s.handlers.Auth.DeleteSSOConnection)
+		orgs.POST("/:orgId/sso/verify-domain", s.authMiddleware.RequirePermission("settings:security"), s.handlers.Auth.VerifySSODomain)
+
+		// Audit log
+		orgs.GET("/:orgId/audit-logs", s.authMiddleware.RequirePermission("audit-logs:read"), s.handlers.Audit.List)
+		orgs.GET("/:orgId/audit-logs/export", s.authMiddleware.RequirePermission("audit-logs:export"), s.handlers.Audit.Export)
+
+		// SCIM provisioning tokens and group role mappings
+		orgs.GET("/:orgId/scim/tokens", s.authMiddleware.RequirePermission("settings:security"), s.handlers.SCIM.ListTokens)
+		orgs.POST("/:orgId/scim/tokens", s.authMiddleware.RequirePermission("settings:security"), s.handlers.SCIM.CreateToken)
+		orgs.DELETE("/:orgId/scim/tokens/:tokenId", s.authMiddleware.RequirePermission("settings:security"), s.handlers.SCIM.DeleteToken)
+		orgs.GET("/:orgId/scim/groups", s.authMiddleware.RequirePermission("settings:security"), s.handlers.SCIM.ListGroupMappings)
+		orgs.PUT("/:orgId/scim/groups/:groupId/role", s.authMiddleware.RequirePermission("settings:security"), s.handlers.SCIM.SetGroupRole)
 
 		// Invitation management routes
 		orgs.GET("/:orgId/invitations", s.authMiddleware.RequirePermission("members:read"), s.handlers.Organization.GetPendingInvitations)
@@ -311,6 +354,10 @@ func (s *Server) setupDashboardRoutes(router *gin.RouterGroup) {
 
 		// Enterprise custom pricing: Effective pricing
 		orgs.GET("/:orgId/effective-pricing", s.handlers.Contract.GetEffectivePricing)
+
+		// Telemetry retention (organization default, overridable per project)
+		orgs.GET("/:orgId/retention", s.authMiddleware.RequirePermission("settings:read"), s.handlers.Observability.GetOrganizationRetention)
+		orgs.PUT("/:orgId/retention", s.authMiddleware.RequirePermission("settings:write"), s.handlers.Observability.UpdateOrganizationRetention)
 	}
 
 	projects := protected.Group("/projects")
@@ -326,9 +373,19 @@ func (s *Server) setupDashboardRoutes(router *gin.RouterGroup) {
 		// Project overview
 		projects.GET("/:projectId/overview", s.authMiddleware.RequirePermission("projects:read"), s.handlers.Overview.GetOverview)
 
+		// Project roles (assignment requires advanced RBAC) and the permission simulator
+		projects.GET("/:projectId/members", s.authMiddleware.RequirePermission("members:read"), s.handlers.RBAC.ListProjectMembers)
+		projects.PUT("/:projectId/members/:userId", s.authMiddleware.RequirePermission("roles:assign"), s.handlers.RBAC.AssignProjectRole)
+		projects.DELETE("/:projectId/members/:userId", s.authMiddleware.RequirePermission("roles:assign"), s.handlers.RBAC.RemoveProjectRole)
+		projects.GET("/:projectId/permissions/simulate", s.authMiddleware.RequirePermission("roles:read"), s.handlers.RBAC.SimulatePermission)
+
 		projects.GET("/:projectId/api-keys", s.authMiddleware.RequirePermission("api-keys:read"), s.handlers.APIKey.List)
 		projects.POST("/:projectId/api-keys", s.authMiddleware.RequirePermission("api-keys:create"), s.handlers.APIKey.Create)
+		projects.GET("/:projectId/api-keys/:keyId", s.authMiddleware.RequirePermission("api-keys:read"), s.handlers.APIKey.Get)
+		projects.PUT("/:projectId/api-keys/:keyId/rate-limits", s.authMiddleware.RequirePermission("api-keys:update"), s.handlers.APIKey.UpdateRateLimits)
 		projects.DELETE("/:projectId/api-keys/:keyId", s.authMiddleware.RequirePermission("api-keys:delete"), s.handlers.APIKey.Delete)
+		projects.GET("/:projectId/rate-limits", s.authMiddleware.RequirePermission("api-keys:read"), s.handlers.APIKey.GetProjectRateLimits)
+		projects.PUT("/:projectId/rate-limits", s.authMiddleware.RequirePermission("api-keys:update"), s.handlers.APIKey.UpdateProjectRateLimits)
 
 		prompts := projects.Group("/:projectId/prompts")
 		{
@@ -410,14 +467,19 @@ func (s *Server) setupDashboardRoutes(router *gin.RouterGroup) {
 			experiments.GET("", s.authMiddleware.RequirePermission("projects:read"), s.handlers.Experiment.List)
 			experiments.POST("", s.authMiddleware.RequirePermission("projects:write"), s.handlers.Experiment.Create)
 			experiments.POST("/compare", s.authMiddleware.RequirePermission("projects:read"), s.handlers.Experiment.CompareExperiments)
+			experiments.POST("/leaderboard", s.authMiddleware.RequirePermission("projects:read"), s.handlers.Experiment.GetPairwiseLeaderboard)
 			experiments.GET("/:experimentId", s.authMiddleware.RequirePermission("projects:read"), s.handlers.Experiment.Get)
 			experiments.PUT("/:experimentId", s.authMiddleware.RequirePermission("projects:write"), s.handlers.Experiment.Update)
 			experiments.DELETE("/:experimentId", s.authMiddleware.RequirePermission("projects:write"), s.handlers.Experiment.Delete)
 			experiments.POST("/:experimentId/rerun", s.authMiddleware.RequirePermission("projects:write"), s.handlers.Experiment.Rerun)
 			experiments.GET("/:experimentId/items", s.authMiddleware.RequirePermission("projects:read"), s.handlers.ExperimentItem.List)
 			experiments.GET("/:experimentId/config", s.authMiddleware.RequirePermission("projects:read"), s.handlers.ExperimentWizard.GetExperimentConfig)
+			experiments.POST("/:experimentId/cancel", s.authMiddleware.RequirePermission("projects:write"), s.handlers.ExperimentWizard.CancelExperiment)
+			experiments.POST("/:experimentId/resume", s.authMiddleware.RequirePermission("projects:write"), s.handlers.ExperimentWizard.ResumeExperiment)
 			experiments.GET("/:experimentId/progress", s.authMiddleware.RequirePermission("projects:read"), s.handlers.Experiment.GetProgress)
 			experiments.GET("/:experimentId/metrics", s.authMiddleware.RequirePermission("projects:read"), s.handlers.Experiment.GetMetrics)
+			experiments.PUT("/:experimentId/gates", s.authMiddleware.RequirePermission("projects:write"), s.handlers.Experiment.SetGates)
+			experiments.GET("/:experimentId/verdict", s.authMiddleware.RequirePermission("projects:read"), s.handlers.Experiment.GetVerdict)
 
 			// Experiment wizard routes
 			experiments.POST("/wizard", s.authMiddleware.RequirePermission("projects:write"), s.handlers.ExperimentWizard.CreateFromWizard)
@@ -435,6 +497,7 @@ func (s *Server) setupDashboardRoutes(router *gin.RouterGroup) {
 			evaluators.POST("/:evaluatorId/activate", s.authMiddleware.RequirePermission("projects:write"), s.handlers.Evaluator.Activate)
 			evaluators.POST("/:evaluatorId/deactivate", s.authMiddleware.RequirePermission("projects:write"), s.handlers.Evaluator.Deactivate)
 			evaluators.POST("/:evaluatorId/trigger", s.authMiddleware.RequirePermission("projects:write"), s.handlers.Evaluator.Trigger)
+			evaluators.POST("/:evaluatorId/pairwise", s.authMiddleware.RequirePermission("projects:write"), s.handlers.Evaluator.TriggerPairwise)
 			evaluators.POST("/:evaluatorId/test", s.authMiddleware.RequirePermission("projects:read"), s.handlers.Evaluator.Test)
 			evaluators.GET("/:evaluatorId/analytics", s.authMiddleware.RequirePermission("projects:read"), s.handlers.Evaluator.GetAnalytics)
 
@@ -485,6 +548,79 @@ func (s *Server) setupDashboardRoutes(router *gin.RouterGroup) {
 			filterPresets.DELETE("/:id", s.authMiddleware.RequirePermission("projects:write"), s.handlers.Observability.DeleteFilterPreset)
 		}
 
+		alerts := projects.Group("/:projectId/alerts")
+		{
+			alerts.GET("", s.authMiddleware.RequirePermission("projects:read"), s.handlers.Observability.ListAlertRules)
+			alerts.POST("", s.authMiddleware.RequirePermission("projects:write"), s.handlers.Observability.CreateAlertRule)
+			alerts.GET("/:id", s.authMiddleware.RequirePermission("projects:read"), s.handlers.Observability.GetAlertRule)
+			alerts.PATCH("/:id", s.authMiddleware.RequirePermission("projects:write"), s.handlers.Observability.UpdateAlertRule)
+			alerts.DELETE("/:id", s.authMiddleware.RequirePermission("projects:write"), s.handlers.Observability.DeleteAlertRule)
+			alerts.POST("/:id/silence", s.authMiddleware.RequirePermission("projects:write"), s.handlers.Observability.SilenceAlertRule)
+			alerts.DELETE("/:id/silence", s.authMiddleware.RequirePermission("projects:write"), s.handlers.Observability.UnsilenceAlertRule)
+			alerts.POST("/:id/rotate-secret", s.authMiddleware.RequirePermission("projects:write"), s.handlers.Observability.RotateAlertWebhookSecret)
+			alerts.GET("/:id/history", s.authMiddleware.RequirePermission("projects:read"), s.handlers.Observability.ListAlertHistory)
+		}
+
+		// Webhook subscriptions (outbound platform events)
+		webhooks := projects.Group("/:projectId/webhooks")
+		{
+			webhooks.GET("", s.authMiddleware.RequirePermission("projects:read"), s.handlers.Webhook.List)
+			webhooks.POST("", s.authMiddleware.RequirePermission("projects:write"), s.handlers.Webhook.Create)
+			webhooks.GET("/event-types", s.authMiddleware.RequirePermission("projects:read"), s.handlers.Webhook.ListEventTypes)
+			webhooks.GET("/:webhookId", s.authMiddleware.RequirePermission("projects:read"), s.handlers.Webhook.Get)
+			webhooks.PATCH("/:webhookId", s.authMiddleware.RequirePermission("projects:write"), s.handlers.Webhook.Update)
+			webhooks.DELETE("/:webhookId", s.authMiddleware.RequirePermission("projects:write"), s.handlers.Webhook.Delete)
+			webhooks.POST("/:webhookId/rotate-secret", s.authMiddleware.RequirePermission("projects:write"), s.handlers.Webhook.RotateSecret)
+			webhooks.POST("/:webhookId/test", s.authMiddleware.RequirePermission("projects:write"), s.handlers.Webhook.SendTest)
+			webhooks.GET("/:webhookId/deliveries", s.authMiddleware.RequirePermission("projects:read"), s.handlers.Webhook.ListDeliveries)
+			webhooks.POST("/:webhookId/deliveries/:deliveryId/redeliver", s.authMiddleware.RequirePermission("projects:write"), s.handlers.Webhook.Redeliver)
+		}
+
+		retention := projects.Group("/:projectId/retention")
+		{
+			retention.GET("", s.authMiddleware.RequirePermission("projects:read"), s.handlers.Observability.GetProjectRetention)
+			retention.PUT("", s.authMiddleware.RequirePermission("settings:write"), s.handlers.Observability.UpdateProjectRetention)
+			retention.DELETE("", s.authMiddleware.RequirePermission("settings:write"), s.handlers.Observability.DeleteProjectRetention)
+			retention.GET("/dry-run", s.authMiddleware.RequirePermission("projects:read"), s.handlers.Observability.DryRunProjectRetention)
+		}
+
+		// Head and tail sampling for OTLP ingestion
+		sampling := projects.Group("/:projectId/sampling")
+		{
+			sampling.GET("", s.authMiddleware.RequirePermission("projects:read"), s.handlers.Observability.GetProjectSampling)
+			sampling.PUT("", s.authMiddleware.RequirePermission("projects:write"), s.handlers.Observability.UpdateProjectSampling)
+			sampling.DELETE("", s.authMiddleware.RequirePermission("projects:write"), s.handlers.Observability.DeleteProjectSampling)
+		}
+
+		// PII redaction of ingested spans
+		redaction := projects.Group("/:projectId/redaction")
+		{
+			redaction.GET("", s.authMiddleware.RequirePermission("projects:read"), s.handlers.Observability.GetProjectRedaction)
+			redaction.PUT("", s.authMiddleware.RequirePermission("projects:write"), s.handlers.Observability.UpdateProjectRedaction)
+			redaction.DELETE("", s.authMiddleware.RequirePermission("projects:write"), s.handlers.Observability.DeleteProjectRedaction)
+		}
+
+		// GDPR erasure and export of an end user's data
+		dataSubjects := projects.Group("/:projectId/data-subject-requests")
+		{
+			dataSubjects.GET("", s.authMiddleware.RequirePermission("traces:read"), s.handlers.Observability.ListDataSubjectRequests)
+			dataSubjects.POST("/erasure", s.authMiddleware.RequirePermission("traces:delete"), s.handlers.Observability.CreateDataSubjectErasure)
+			dataSubjects.POST("/export", s.authMiddleware.RequirePermission("traces:export"), s.handlers.Observability.CreateDataSubjectExport)
+			dataSubjects.GET("/:requestId", s.authMiddleware.RequirePermission("traces:read"), s.handlers.Observability.GetDataSubjectRequest)
+			dataSubjects.GET("/:requestId/bundle", s.authMiddleware.RequirePermission("traces:export"), s.handlers.Observability.GetDataSubjectExportBundle)
+		}
+
+		// Public read-only links to traces and sessions
+		shareLinks := projects.Group("/:projectId/share-links")
+		{
+			shareLinks.GET("", s.authMiddleware.RequirePermission("traces:read"), s.handlers.Observability.ListShareLinks)
+			shareLinks.POST("", s.authMiddleware.RequirePermission("traces:share"), s.handlers.Observability.CreateShareLink)
+			shareLinks.DELETE("/:linkId", s.authMiddleware.RequirePermission("traces:share"), s.handlers.Observability.RevokeShareLink)
+		}
+
+		// Restore archived S3 telemetry into ClickHouse
+		projects.POST("/:projectId/telemetry-archive/rehydrate", s.authMiddleware.RequirePermission("projects:write"), s.handlers.Observability.RehydrateArchive)
+
 		// Observability sessions (aggregated from traces by session_id)
 		projects.GET("/:projectId/sessions", s.authMiddleware.RequirePermission("projects:read"), s.handlers.Observability.ListSessions)
 
@@ -511,13 +647,17 @@ func (s *Server) setupDashboardRoutes(router *gin.RouterGroup) {
 		}
 	}
 
+	// Dashboard routes below name their project in the X-Project-ID header or the
+	// project_id query, so permissions come from the user's role in that project
+	requireProject := s.authMiddleware.RequireProjectPermission
+
 	analytics := protected.Group("/analytics")
 	{
-		analytics.GET("/overview", s.handlers.Analytics.Overview)
-		analytics.GET("/requests", s.handlers.Analytics.Requests)
-		analytics.GET("/costs", s.handlers.Analytics.Costs)
-		analytics.GET("/providers", s.handlers.Analytics.Providers)
-		analytics.GET("/models", s.handlers.Analytics.Models)
+		analytics.GET("/overview", requireProject("analytics:read"), s.handlers.Analytics.Overview)
+		analytics.GET("/requests", requireProject("analytics:read"), s.handlers.Analytics.Requests)
+		analytics.GET("/costs", requireProject("costs:read"), s.handlers.Analytics.Costs)
+		analytics.GET("/providers", requireProject("analytics:read"), s.handlers.Analytics.Providers)
+		analytics.GET("/models", requireProject("analytics:read"), s.handlers.Analytics.Models)
 	}
 
 	// Dashboard query builder view definitions
@@ -532,50 +672,50 @@ func (s *Server) setupDashboardRoutes(router *gin.RouterGroup) {
 
 	traces := protected.Group("/traces")
 	{
-		traces.GET("", s.handlers.Observability.ListTraces)
-		traces.GET("/filter-options", s.handlers.Observability.GetTraceFilterOptions) // Must be before /:id
-		traces.GET("/attributes", s.handlers.Observability.DiscoverAttributes)        // Must be before /:id
-		traces.GET("/:id", s.handlers.Observability.GetTrace)
-		traces.GET("/:id/spans", s.handlers.Observability.GetTraceWithSpans)
-		traces.GET("/:id/scores", s.handlers.Observability.GetTraceWithScores)
-		traces.POST("/:id/scores", s.handlers.Observability.CreateTraceScore)
-		traces.DELETE("/:id/scores/:score_id", s.handlers.Observability.DeleteTraceScore)
-		traces.DELETE("/:id", s.handlers.Observability.DeleteTrace)
-		traces.PUT("/:id/tags", s.handlers.Observability.UpdateTraceTags)
-		traces.PUT("/:id/bookmark", s.handlers.Observability.UpdateTraceBookmark)
+		traces.GET("", requireProject("traces:read"), s.handlers.Observability.ListTraces)
+		traces.GET("/filter-options", requireProject("traces:read"), s.handlers.Observability.GetTraceFilterOptions) // Must be before /:id
+		traces.GET("/attributes", requireProject("traces:read"), s.handlers.Observability.DiscoverAttributes)        // Must be before /:id
+		traces.GET("/:id", requireProject("traces:read"), s.handlers.Observability.GetTrace)
+		traces.GET("/:id/spans", requireProject("traces:read"), s.handlers.Observability.GetTraceWithSpans)
+		traces.GET("/:id/scores", requireProject("traces:read"), s.handlers.Observability.GetTraceWithScores)
+		traces.POST("/:id/scores", requireProject("projects:write"), s.handlers.Observability.CreateTraceScore)
+		traces.DELETE("/:id/scores/:score_id", requireProject("projects:write"), s.handlers.Observability.DeleteTraceScore)
+		traces.DELETE("/:id", requireProject("traces:delete"), s.handlers.Observability.DeleteTrace)
+		traces.PUT("/:id/tags", requireProject("projects:write"), s.handlers.Observability.UpdateTraceTags)
+		traces.PUT("/:id/bookmark", requireProject("projects:write"), s.handlers.Observability.UpdateTraceBookmark)
 
 		// Trace comments
 		traceComments := traces.Group("/:id/comments")
 		{
-			traceComments.POST("", s.handlers.Comment.CreateComment)
-			traceComments.GET("", s.handlers.Comment.ListComments)
-			traceComments.GET("/count", s.handlers.Comment.GetCommentCount)
-			traceComments.PUT("/:comment_id", s.handlers.Comment.UpdateComment)
-			traceComments.DELETE("/:comment_id", s.handlers.Comment.DeleteComment)
-			traceComments.POST("/:comment_id/reactions", s.handlers.Comment.ToggleReaction)
-			traceComments.POST("/:comment_id/replies", s.handlers.Comment.CreateReply)
+			traceComments.POST("", requireProject("projects:write"), s.handlers.Comment.CreateComment)
+			traceComments.GET("", requireProject("traces:read"), s.handlers.Comment.ListComments)
+			traceComments.GET("/count", requireProject("traces:read"), s.handlers.Comment.GetCommentCount)
+			traceComments.PUT("/:comment_id", requireProject("projects:write"), s.handlers.Comment.UpdateComment)
+			traceComments.DELETE("/:comment_id", requireProject("projects:write"), s.handlers.Comment.DeleteComment)
+			traceComments.POST("/:comment_id/reactions", requireProject("projects:write"), s.handlers.Comment.ToggleReaction)
+			traceComments.POST("/:comment_id/replies", requireProject("projects:write"), s.handlers.Comment.CreateReply)
 		}
 	}
 
 	spans := protected.Group("/spans")
 	{
-		spans.GET("", s.handlers.Observability.ListSpans)
-		spans.GET("/:id", s.handlers.Observability.GetSpan)
-		spans.DELETE("/:id", s.handlers.Observability.DeleteSpan)
+		spans.GET("", requireProject("traces:read"), s.handlers.Observability.ListSpans)
+		spans.GET("/:id", requireProject("traces:read"), s.handlers.Observability.GetSpan)
+		spans.DELETE("/:id", requireProject("traces:delete"), s.handlers.Observability.DeleteSpan)
 	}
 
 	scores := protected.Group("/scores")
 	{
-		scores.GET("", s.handlers.Observability.ListScores)
-		scores.GET("/:id", s.handlers.Observability.GetScore)
-		scores.PUT("/:id", s.handlers.Observability.UpdateScore)
+		scores.GET("", requireProject("traces:read"), s.handlers.Observability.ListScores)
+		scores.GET("/:id", requireProject("traces:read"), s.handlers.Observability.GetScore)
+		scores.PUT("/:id", requireProject("projects:write"), s.handlers.Observability.UpdateScore)
 	}
 
 	logs := protected.Group("/logs")
 	{
-		logs.GET("/requests", s.handlers.Logs.ListRequests)
-		logs.GET("/requests/:requestId", s.handlers.Logs.GetRequest)
-		logs.GET("/export", s.handlers.Logs.Export)
+		logs.GET("/requests", requireProject("traces:read"), s.handlers.Logs.ListRequests)
+		logs.GET("/requests/:requestId", requireProject("traces:read"), s.handlers.Logs.GetRequest)
+		logs.GET("/export", requireProject("traces:export"), s.handlers.Logs.Export)
 	}
 
 	billing := protected.Group("/billing")
@@ -635,80 +775,108 @@ func (s *Server) setupDashboardRoutes(router *gin.RouterGroup) {
 }
 
 func (s *Server) setupSDKRoutes(router *gin.RouterGroup) {
+	// Every SDK route requires an API key scope (see auth.APIKeyScopes)
+	scope := s.sdkAuthMiddleware.RequireAPIKeyScope
+
 	// OTLP ingestion - supports Protobuf + JSON, gzip compression
-	router.POST("/traces", s.handlers.OTLP.HandleTraces)
-	router.POST("/metrics", s.handlers.OTLPMetrics.HandleMetrics)
-	router.POST("/logs", s.handlers.OTLPLogs.HandleLogs)
+	router.POST("/traces", scope("traces:create"), s.handlers.OTLP.HandleTraces)
+	router.POST("/metrics", scope("metrics:create"), s.handlers.OTLPMetrics.HandleMetrics)
+	router.POST("/logs", scope("logs:create"), s.handlers.OTLPLogs.HandleLogs)
 
 	prompts := router.Group("/prompts")
 	{
-		prompts.GET("", s.handlers.Prompt.ListPromptsSDK)
-		prompts.POST("", s.handlers.Prompt.UpsertPrompt)
-		prompts.GET("/:name", s.handlers.Prompt.GetPromptByName)
+		prompts.GET("", scope("prompts:read"), s.handlers.Prompt.ListPromptsSDK)
+		prompts.POST("", scope("prompts:create"), s.handlers.Prompt.UpsertPrompt)
+		prompts.GET("/:name", scope("prompts:read"), s.handlers.Prompt.GetPromptByName)
 	}
 
 	scores := router.Group("/scores")
 	{
-		scores.POST("", s.handlers.SDKScore.Create)
-		scores.POST("/batch", s.handlers.SDKScore.CreateBatch)
+		scores.POST("", scope("scores:create"), s.handlers.SDKScore.Create)
+		scores.POST("/batch", scope("scores:create"), s.handlers.SDKScore.CreateBatch)
 	}
 
 	sdkDatasets := router.Group("/datasets")
 	{
-		sdkDatasets.GET("", s.handlers.Dataset.List)
-		sdkDatasets.POST("", s.handlers.Dataset.Create)
-		sdkDatasets.GET("/:datasetId", s.handlers.Dataset.Get)
-		sdkDatasets.PATCH("/:datasetId", s.handlers.Dataset.Update)
-		sdkDatasets.DELETE("/:datasetId", s.handlers.Dataset.Delete)
-		sdkDatasets.POST("/:datasetId/items", s.handlers.Dataset.CreateItems)
-		sdkDatasets.GET("/:datasetId/items", s.handlers.Dataset.ListItems)
-		sdkDatasets.GET("/:datasetId/items/export", s.handlers.Dataset.ExportItems)
+		sdkDatasets.GET("", scope("datasets:read"), s.handlers.Dataset.List)
+		sdkDatasets.POST("", scope("datasets:write"), s.handlers.Dataset.Create)
+		sdkDatasets.GET("/:datasetId", scope("datasets:read"), s.handlers.Dataset.Get)
+		sdkDatasets.PATCH("/:datasetId", scope("datasets:write"), s.handlers.Dataset.Update)
+		sdkDatasets.DELETE("/:datasetId", scope("datasets:write"), s.handlers.Dataset.Delete)
+		sdkDatasets.POST("/:datasetId/items", scope("datasets:write"), s.handlers.Dataset.CreateItems)
+		sdkDatasets.GET("/:datasetId/items", scope("datasets:read"), s.handlers.Dataset.ListItems)
+		sdkDatasets.GET("/:datasetId/items/export", scope("datasets:read"), s.handlers.Dataset.ExportItems)
 		// Dataset import SDK routes
-		sdkDatasets.POST("/:datasetId/items/import-json", s.handlers.Dataset.ImportFromJSON)
-		sdkDatasets.POST("/:datasetId/items/import-csv", s.handlers.Dataset.ImportFromCSV)
-		sdkDatasets.POST("/:datasetId/items/from-traces", s.handlers.Dataset.CreateFromTraces)
-		sdkDatasets.POST("/:datasetId/items/from-spans", s.handlers.Dataset.CreateFromSpans)
+		sdkDatasets.POST("/:datasetId/items/import-json", scope("datasets:write"), s.handlers.Dataset.ImportFromJSON)
+		sdkDatasets.POST("/:datasetId/items/import-csv", scope("datasets:write"), s.handlers.Dataset.ImportFromCSV)
+		// Copying trace data into a dataset also exposes it, so it needs traces:read
+		sdkDatasets.POST("/:datasetId/items/from-traces", scope("datasets:write", "traces:read"), s.handlers.Dataset.CreateFromTraces)
+		sdkDatasets.POST("/:datasetId/items/from-spans", scope("datasets:write", "traces:read"), s.handlers.Dataset.CreateFromSpans)
 		// Dataset versioning SDK routes
-		sdkDatasets.POST("/:datasetId/versions", s.handlers.DatasetVersion.CreateVersion)
-		sdkDatasets.GET("/:datasetId/versions", s.handlers.DatasetVersion.ListVersions)
-		sdkDatasets.GET("/:datasetId/versions/:versionId", s.handlers.DatasetVersion.GetVersion)
-		sdkDatasets.GET("/:datasetId/versions/:versionId/items", s.handlers.DatasetVersion.GetVersionItems)
-		sdkDatasets.POST("/:datasetId/pin", s.handlers.DatasetVersion.PinVersion)
-		sdkDatasets.GET("/:datasetId/info", s.handlers.DatasetVersion.GetDatasetWithVersionInfo)
+		sdkDatasets.POST("/:datasetId/versions", scope("datasets:write"), s.handlers.DatasetVersion.CreateVersion)
+		sdkDatasets.GET("/:datasetId/versions", scope("datasets:read"), s.handlers.DatasetVersion.ListVersions)
+		sdkDatasets.GET("/:datasetId/versions/:versionId", scope("datasets:read"), s.handlers.DatasetVersion.GetVersion)
+		sdkDatasets.GET("/:datasetId/versions/:versionId/items", scope("datasets:read"), s.handlers.DatasetVersion.GetVersionItems)
+		sdkDatasets.POST("/:datasetId/pin", scope("datasets:write"), s.handlers.DatasetVersion.PinVersion)
+		sdkDatasets.GET("/:datasetId/info", scope("datasets:read"), s.handlers.DatasetVersion.GetDatasetWithVersionInfo)
 	}
 
 	sdkExperiments := router.Group("/experiments")
 	{
-		sdkExperiments.GET("", s.handlers.Experiment.List)
-		sdkExperiments.POST("", s.handlers.Experiment.Create)
-		sdkExperiments.POST("/compare", s.handlers.Experiment.CompareExperiments)
-		sdkExperiments.GET("/:experimentId", s.handlers.Experiment.Get)
-		sdkExperiments.PATCH("/:experimentId", s.handlers.Experiment.Update)
-		sdkExperiments.POST("/:experimentId/items", s.handlers.Experiment.CreateItems)
-		sdkExperiments.POST("/:experimentId/rerun", s.handlers.Experiment.Rerun)
+		sdkExperiments.GET("", scope("experiments:read"), s.handlers.Experiment.List)
+		sdkExperiments.POST("", scope("experiments:write"), s.handlers.Experiment.Create)
+		sdkExperiments.POST("/compare", scope("experiments:read"), s.handlers.Experiment.CompareExperiments)
+		sdkExperiments.POST("/leaderboard", scope("experiments:read"), s.handlers.Experiment.GetPairwiseLeaderboard)
+		sdkExperiments.GET("/:experimentId", scope("experiments:read"), s.handlers.Experiment.Get)
+		sdkExperiments.PATCH("/:experimentId", scope("experiments:write"), s.handlers.Experiment.Update)
+		sdkExperiments.POST("/:experimentId/items", scope("experiments:write"), s.handlers.Experiment.CreateItems)
+		sdkExperiments.POST("/:experimentId/rerun", scope("experiments:write"), s.handlers.Experiment.Rerun)
+		sdkExperiments.PUT("/:experimentId/gates", scope("experiments:write"), s.handlers.Experiment.SetGates)
+		sdkExperiments.GET("/:experimentId/verdict", scope("experiments:read"), s.handlers.Experiment.GetVerdict)
 	}
 
 	spans := router.Group("/spans")
 	{
-		spans.POST("/query", s.handlers.SpanQuery.HandleQuery)
-		spans.POST("/query/validate", s.handlers.SpanQuery.HandleValidate)
+		spans.POST("/query", scope("traces:read"), s.handlers.SpanQuery.HandleQuery)
+		spans.POST("/query/validate", scope("traces:read"), s.handlers.SpanQuery.HandleValidate)
+		spans.POST("/query/aggregate", scope("traces:read"), s.handlers.SpanQuery.HandleAggregate)
 	}
 
 	// Playground execution for SDK (LLMScorer)
 	playground := router.Group("/playground")
 	{
-		playground.POST("/execute", s.handlers.SDKPlayground.Execute)
+		playground.POST("/execute", scope("playground:execute"), s.handlers.SDKPlayground.Execute)
 	}
 
 	// Annotation queues SDK routes (programmatic item management)
 	sdkAnnotationQueues := router.Group("/annotation-queues")
 	{
-		sdkAnnotationQueues.GET("/:queueId/items", s.handlers.AnnotationItem.ListItemsSDK)
-		sdkAnnotationQueues.POST("/:queueId/items", s.handlers.AnnotationItem.AddItemsSDK)
-		sdkAnnotationQueues.POST("/:queueId/items/batch", s.handlers.AnnotationItem.AddItemsSDK) // Alias for batch
+		sdkAnnotationQueues.GET("/:queueId/items", scope("annotations:read"), s.handlers.AnnotationItem.ListItemsSDK)
+		sdkAnnotationQueues.POST("/:queueId/items", scope("annotations:write"), s.handlers.AnnotationItem.AddItemsSDK)
+		sdkAnnotationQueues.POST("/:queueId/items/batch", scope("annotations:write"), s.handlers.AnnotationItem.AddItemsSDK) // Alias for batch
 	}
 }
 
+// setupSCIMRoutes configures the SCIM 2.0 endpoints identity providers call
+func (s *Server) setupSCIMRoutes(router *gin.RouterGroup) {
+	router.GET("/ServiceProviderConfig", s.handlers.SCIM.ServiceProviderConfig)
+	router.GET("/ResourceTypes", s.handlers.SCIM.ResourceTypes)
+
+	router.GET("/Users", s.handlers.SCIM.ListUsers)
+	router.POST("/Users", s.handlers.SCIM.CreateUser)
+	router.GET("/Users/:id", s.handlers.SCIM.GetUser)
+	router.PUT("/Users/:id", s.handlers.SCIM.ReplaceUser)
+	router.PATCH("/Users/:id", s.handlers.SCIM.PatchUser)
+	router.DELETE("/Users/:id", s.handlers.SCIM.DeleteUser)
+
+	router.GET("/Groups", s.handlers.SCIM.ListGroups)
+	router.POST("/Groups", s.handlers.SCIM.CreateGroup)
+	router.GET("/Groups/:id", s.handlers.SCIM.GetGroup)
+	router.PUT("/Groups/:id", s.handlers.SCIM.ReplaceGroup)
+	router.PATCH("/Groups/:id", s.handlers.SCIM.PatchGroup)
+	router.DELETE("/Groups/:id", s.handlers.SCIM.DeleteGroup)
+}
+
 func (s *Server) Shutdown(ctx context.Context) error {
 	return s.server.Shutdown(ctx)
 }
@@ -0,0 +1,222 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"brokle/internal/config"
+	"brokle/internal/core/domain/observability"
+	observabilitySvc "brokle/internal/core/services/observability"
+)
+
+// AlertWorker periodically evaluates alert rules and queues notifications for state changes
+type AlertWorker struct {
+	config             *config.Config
+	logger             *slog.Logger
+	alertService       *observabilitySvc.AlertService
+	notificationWorker *NotificationWorker
+	quit               chan struct{}
+	wg                 sync.WaitGroup
+	ticker             *time.Ticker
+}
+
+// NewAlertWorker creates a new alert worker
+func NewAlertWorker(
+	config *config.Config,
+	logger *slog.Logger,
+	alertService *observabilitySvc.AlertService,
+	notificationWorker *NotificationWorker,
+) *AlertWorker {
+	return &AlertWorker{
+		config:             config,
+		logger:             logger,
+		alertService:       alertService,
+		notificationWorker: notificationWorker,
+		quit:               make(chan struct{}),
+	}
+}
+
+// Start starts the alert worker
+func (w *AlertWorker) Start() {
+	w.logger.Info("Starting alert worker", "interval_seconds", w.config.Alerting.IntervalSeconds)
+
+	w.wg.Add(1)
+	go w.mainLoop()
+}
+
+// Stop stops the alert worker and waits for graceful shutdown
+func (w *AlertWorker) Stop() {
+	w.logger.Info("Stopping alert worker")
+	close(w.quit)
+	w.wg.Wait()
+}
+
+// mainLoop evaluates on the configured interval
+func (w *AlertWorker) mainLoop() {
+	defer w.wg.Done()
+
+	interval := time.Duration(w.config.Alerting.IntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	w.ticker = time.NewTicker(interval)
+	for {
+		select {
+		case <-w.ticker.C:
+			w.run(interval)
+		case <-w.quit:
+			w.ticker.Stop()
+			w.logger.Info("Alert worker stopped")
+			return
+		}
+	}
+}
+
+// run executes a single evaluation pass and dispatches its notifications
+func (w *AlertWorker) run(interval time.Duration) {
+	// A pass must finish before the next tick so rule state is never evaluated concurrently
+	ctx, cancel := context.WithTimeout(context.Background(), interval)
+	defer cancel()
+
+	startTime := time.Now()
+
+	summary, err := w.alertService.EvaluateAll(ctx, startTime)
+	if err != nil {
+		w.logger.Error("alert evaluation failed", "error", err)
+		return
+	}
+
+	for _, notification := range summary.Notifications {
+		w.dispatch(notification)
+	}
+
+	w.logger.Debug("Alert evaluation completed",
+		"rules_evaluated", summary.RulesEvaluated,
+		"rules_failed", summary.RulesFailed,
+		"transitions", summary.Transitions,
+		"notifications", len(summary.Notifications),
+		"duration_ms", time.Since(startTime).Milliseconds(),
+	)
+}
+
+// dispatch queues one notification job per channel configured on the rule
+func (w *AlertWorker) dispatch(notification *observability.AlertNotification) {
+	rule := notification.Rule
+	event := notification.Event
+	eventType := "alert." + string(event.State)
+	message := alertMessage(rule, event)
+
+	for _, channel := range rule.Channels {
+		switch channel.Type {
+		case observability.AlertChannelEmail:
+			w.notificationWorker.QueueEmail(EmailJob{
+				To:       []string{channel.Target},
+				Subject:  alertSubject(rule, event),
+				Body:     message,
+				Template: "alert",
+				TemplateData: map[string]interface{}{
+					"rule_name": rule.Name,
+					"state":     string(event.State),
+					"value":     formatAlertValue(event.Value),
+					"threshold": event.Threshold,
+					"link":      w.alertLink(rule),
+				},
+				Priority: "high",
+			})
+		case observability.AlertChannelWebhook:
+			w.notificationWorker.QueueWebhook(WebhookJob{
+				URL:       channel.Target,
+				Method:    "POST",
+				Payload:   alertPayload(rule, event),
+				Secret:    rule.WebhookSecret,
+				EventType: eventType,
+			})
+		case observability.AlertChannelSlack:
+			// Channel names would post through the platform's own Slack webhook
+			if !strings.HasPrefix(channel.Target, "https://") {
+				w.logger.Warn("skipping slack channel without an incoming webhook URL", "rule_id", rule.ID)
+				continue
+			}
+			w.notificationWorker.QueueSlack(SlackJob{
+				Channel:   channel.Target,
+				Message:   alertEmoji(event.State) + " " + message,
+				Username:  "Brokle Alerts",
+				IconEmoji: alertEmoji(event.State),
+				EventType: eventType,
+			})
+		default:
+			w.logger.Warn("unknown alert channel type", "rule_id", rule.ID, "type", channel.Type)
+		}
+	}
+}
+
+// alertLink returns the dashboard URL for the rule, or an empty string if no app URL is configured
+func (w *AlertWorker) alertLink(rule *observability.AlertRule) string {
+	if w.config.Server.AppURL == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/projects/%s/alerts/%s", strings.TrimRight(w.config.Server.AppURL, "/"), rule.ProjectID, rule.ID)
+}
+
+func alertPayload(rule *observability.AlertRule, event *observability.AlertEvent) map[string]interface{} {
+	payload := map[string]interface{}{
+		"type":           "alert." + string(event.State),
+		"event_id":       event.ID,
+		"rule_id":        rule.ID,
+		"rule_name":      rule.Name,
+		"project_id":     rule.ProjectID,
+		"state":          string(event.State),
+		"previous_state": string(event.PreviousState),
+		"aggregation":    rule.Aggregation,
+		"operator":       string(rule.Operator),
+		"threshold":      event.Threshold,
+		"window_minutes": rule.WindowMinutes,
+		"timestamp":      event.CreatedAt.Unix(),
+	}
+	if event.Value != nil {
+		payload["value"] = *event.Value
+	}
+	if rule.FilterPresetID != nil {
+		payload["filter_preset_id"] = *rule.FilterPresetID
+	} else if rule.Filter != "" {
+		payload["filter"] = rule.Filter
+	}
+	return payload
+}
+
+func alertSubject(rule *observability.AlertRule, event *observability.AlertEvent) string {
+	if event.State == observability.AlertStateResolved {
+		return fmt.Sprintf("[Resolved] %s", rule.Name)
+	}
+	return fmt.Sprintf("[Firing] %s", rule.Name)
+}
+
+func alertMessage(rule *observability.AlertRule, event *observability.AlertEvent) string {
+	return fmt.Sprintf("%s\n%s over the last %d minutes is %s (threshold %s %g)",
+		alertSubject(rule, event),
+		rule.Aggregation,
+		rule.WindowMinutes,
+		formatAlertValue(event.Value),
+		rule.Operator,
+		event.Threshold,
+	)
+}
+
+func alertEmoji(state observability.AlertState) string {
+	if state == observability.AlertStateResolved {
+		return ":white_check_mark:"
+	}
+	return ":rotating_light:"
+}
+
+func formatAlertValue(value *float64) string {
+	if value == nil {
+		return "no data"
+	}
+	return fmt.Sprintf("%g", *value)
+}
@@ -0,0 +1,100 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"brokle/internal/config"
+	"brokle/internal/core/domain/observability"
+	observabilitySvc "brokle/internal/core/services/observability"
+)
+
+// maxDataSubjectRequestsPerPass bounds the requests one pass works through
+const maxDataSubjectRequestsPerPass = 10
+
+// DataSubjectWorker processes queued GDPR erasure and export requests
+type DataSubjectWorker struct {
+	config             *config.Config
+	logger             *slog.Logger
+	dataSubjectService *observabilitySvc.DataSubjectService
+	quit               chan struct{}
+	wg                 sync.WaitGroup
+	ticker             *time.Ticker
+}
+
+// NewDataSubjectWorker creates a new data subject worker
+func NewDataSubjectWorker(
+	config *config.Config,
+	logger *slog.Logger,
+	dataSubjectService *observabilitySvc.DataSubjectService,
+) *DataSubjectWorker {
+	return &DataSubjectWorker{
+		config:             config,
+		logger:             logger,
+		dataSubjectService: dataSubjectService,
+		quit:               make(chan struct{}),
+	}
+}
+
+// Start starts the data subject worker
+func (w *DataSubjectWorker) Start() {
+	w.logger.Info("Starting data subject worker", "poll_interval_seconds", w.config.DataSubjects.PollIntervalSeconds)
+
+	w.wg.Add(1)
+	go w.mainLoop()
+}
+
+// Stop stops the data subject worker and waits for the request in progress
+func (w *DataSubjectWorker) Stop() {
+	w.logger.Info("Stopping data subject worker")
+	close(w.quit)
+	w.wg.Wait()
+}
+
+// mainLoop polls for queued requests on the configured interval
+func (w *DataSubjectWorker) mainLoop() {
+	defer w.wg.Done()
+
+	interval := time.Duration(w.config.DataSubjects.PollIntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	w.ticker = time.NewTicker(interval)
+	for {
+		select {
+		case <-w.ticker.C:
+			w.run()
+		case <-w.quit:
+			w.ticker.Stop()
+			w.logger.Info("Data subject worker stopped")
+			return
+		}
+	}
+}
+
+// run processes queued requests one at a time until none is left
+func (w *DataSubjectWorker) run() {
+	for i := 0; i < maxDataSubjectRequestsPerPass; i++ {
+		select {
+		case <-w.quit:
+			return
+		default:
+		}
+
+		// Claimed requests are leased and resume from their last completed stage,
+		// so a request cut short by the timeout is picked up again once the lease expires
+		ctx, cancel := context.WithTimeout(context.Background(), observability.DataSubjectLease)
+		processed, err := w.dataSubjectService.ProcessNext(ctx)
+		cancel()
+
+		if err != nil {
+			w.logger.Error("data subject request processing failed", "error", err)
+		}
+		if !processed {
+			return
+		}
+	}
+}
@@ -51,6 +51,28 @@ func (s *BuiltinScorer) Execute(ctx context.Context, job *EvaluationJob) (*Score
 		return s.executeEquals(text, config.Config)
 	case "not_empty":
 		return s.executeNotEmpty(text)
+	case "levenshtein":
+		return s.executeLevenshtein(job, text, config.Config)
+	case "jaro":
+		return s.executeJaro(job, text, config.Config, false)
+	case "jaro_winkler":
+		return s.executeJaro(job, text, config.Config, true)
+	case "bleu":
+		return s.executeBLEU(job, text, config.Config)
+	case "rouge_l":
+		return s.executeRougeL(job, text, config.Config)
+	case "token_f1":
+		return s.executeTokenF1(job, text, config.Config)
+	case "jaccard":
+		return s.executeJaccard(job, text, config.Config)
+	case "json_schema":
+		return s.executeJSONSchema(text, config.Config)
+	case "json_path_equals":
+		return s.executeJSONPathEquals(job, text, config.Config)
+	case "numeric_match":
+		return s.executeNumericMatch(job, text, config.Config)
+	case "tool_call":
+		return s.executeToolCall(job, text, config.Config)
 	default:
 		return nil, fmt.Errorf("unknown builtin scorer: %s", config.ScorerName)
 	}
@@ -0,0 +1,396 @@
+package evaluation
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Reference-based builtin scorers compare the output with the expected value,
+// taken from config.expected, the "expected" variable or the span data. They
+// are case insensitive unless config.case_sensitive is true.
+
+const defaultBLEUMaxN = 4
+
+// executeLevenshtein scores the normalized edit distance similarity
+func (s *BuiltinScorer) executeLevenshtein(job *EvaluationJob, text string, config map[string]any) (*ScorerResult, error) {
+	expected, err := s.requireExpected(job, config, "levenshtein")
+	if err != nil {
+		return nil, err
+	}
+	a, b := []rune(normalizeCase(text, config)), []rune(normalizeCase(expected, config))
+
+	distance := levenshteinDistance(a, b)
+	similarity := 1.0
+	if longest := max(len(a), len(b)); longest > 0 {
+		similarity = 1 - float64(distance)/float64(longest)
+	}
+
+	reason := fmt.Sprintf("Edit distance %d over %d characters", distance, max(len(a), len(b)))
+	return numericResult(builtinScoreName(config, "levenshtein"), similarity, reason), nil
+}
+
+// executeJaro scores the Jaro similarity; jaro_winkler also rewards a common
+// prefix of up to 4 characters by prefix_scale (default 0.1)
+func (s *BuiltinScorer) executeJaro(job *EvaluationJob, text string, config map[string]any, winkler bool) (*ScorerResult, error) {
+	name := "jaro"
+	if winkler {
+		name = "jaro_winkler"
+	}
+	expected, err := s.requireExpected(job, config, name)
+	if err != nil {
+		return nil, err
+	}
+	a, b := []rune(normalizeCase(text, config)), []rune(normalizeCase(expected, config))
+
+	similarity := jaroSimilarity(a, b)
+	if winkler {
+		scale := 0.1
+		if v, ok := config["prefix_scale"].(float64); ok {
+			if v < 0 || v > 0.25 {
+				return nil, fmt.Errorf("prefix_scale must be between 0 and 0.25")
+			}
+			scale = v
+		}
+		prefix := 0
+		for prefix < min(4, len(a), len(b)) && a[prefix] == b[prefix] {
+			prefix++
+		}
+		similarity += float64(prefix) * scale * (1 - similarity)
+	}
+
+	reason := fmt.Sprintf("%s similarity %.4f", strings.ReplaceAll(name, "_", "-"), similarity)
+	return numericResult(builtinScoreName(config, name), similarity, reason), nil
+}
+
+// executeBLEU scores sentence BLEU up to max_n-grams (default 4), with add-one
+// smoothing of the higher order precisions
+func (s *BuiltinScorer) executeBLEU(job *EvaluationJob, text string, config map[string]any) (*ScorerResult, error) {
+	expected, err := s.requireExpected(job, config, "bleu")
+	if err != nil {
+		return nil, err
+	}
+	maxN := defaultBLEUMaxN
+	if v, ok := config["max_n"].(float64); ok {
+		if v < 1 || v > 8 {
+			return nil, fmt.Errorf("max_n must be between 1 and 8")
+		}
+		maxN = int(v)
+	}
+
+	candidate, reference := tokenize(text, config), tokenize(expected, config)
+	score := bleuScore(candidate, reference, maxN)
+
+	reason := fmt.Sprintf("BLEU-%d %.4f (%d candidate, %d reference tokens)", maxN, score, len(candidate), len(reference))
+	return numericResult(builtinScoreName(config, "bleu"), score, reason), nil
+}
+
+// executeRougeL scores the longest common subsequence F1 over tokens
+func (s *BuiltinScorer) executeRougeL(job *EvaluationJob, text string, config map[string]any) (*ScorerResult, error) {
+	expected, err := s.requireExpected(job, config, "rouge_l")
+	if err != nil {
+		return nil, err
+	}
+	candidate, reference := tokenize(text, config), tokenize(expected, config)
+
+	lcs := longestCommonSubsequence(candidate, reference)
+	f1 := f1Score(lcs, len(candidate), len(reference))
+
+	reason := fmt.Sprintf("Longest common subsequence %d (%d candidate, %d reference tokens)", lcs, len(candidate), len(reference))
+	return numericResult(builtinScoreName(config, "rouge_l"), f1, reason), nil
+}
+
+// executeTokenF1 scores the bag-of-tokens overlap F1
+func (s *BuiltinScorer) executeTokenF1(job *EvaluationJob, text string, config map[string]any) (*ScorerResult, error) {
+	expected, err := s.requireExpected(job, config, "token_f1")
+	if err != nil {
+		return nil, err
+	}
+	candidate, reference := tokenize(text, config), tokenize(expected, config)
+
+	counts := make(map[string]int, len(reference))
+	for _, token := range reference {
+		counts[token]++
+	}
+	common := 0
+	for _, token := range candidate {
+		if counts[token] > 0 {
+			counts[token]--
+			common++
+		}
+	}
+	f1 := f1Score(common, len(candidate), len(reference))
+
+	reason := fmt.Sprintf("%d shared tokens (%d candidate, %d reference)", common, len(candidate), len(reference))
+	return numericResult(builtinScoreName(config, "token_f1"), f1, reason), nil
+}
+
+// executeJaccard scores the overlap of the items listed in the output and the
+// expected value: JSON arrays, or text split on the delimiter (default: commas
+// and newlines)
+func (s *BuiltinScorer) executeJaccard(job *EvaluationJob, text string, config map[string]any) (*ScorerResult, error) {
+	expected, err := s.requireExpected(job, config, "jaccard")
+	if err != nil {
+		return nil, err
+	}
+	actualSet := toSet(extractList(text, config))
+	expectedSet := toSet(extractList(expected, config))
+
+	intersection := 0
+	for item := range actualSet {
+		if _, ok := expectedSet[item]; ok {
+			intersection++
+		}
+	}
+	union := len(actualSet) + len(expectedSet) - intersection
+	similarity := 1.0
+	if union > 0 {
+		similarity = float64(intersection) / float64(union)
+	}
+
+	reason := fmt.Sprintf("%d of %d distinct items shared", intersection, union)
+	return numericResult(builtinScoreName(config, "jaccard"), similarity, reason), nil
+}
+
+// getExpectedText returns the reference value the output is compared with
+func (s *BuiltinScorer) getExpectedText(job *EvaluationJob, config map[string]any) (string, bool) {
+	if v, ok := config["expected"]; ok && v != nil {
+		return stringifyValue(v), true
+	}
+	if expected, ok := job.Variables["expected"]; ok {
+		return expected, true
+	}
+	if v, ok := job.SpanData["expected"]; ok && v != nil {
+		if fields, ok := v.(map[string]interface{}); ok {
+			return stringifyDatasetField(fields), true
+		}
+		return stringifyValue(v), true
+	}
+	return "", false
+}
+
+func (s *BuiltinScorer) requireExpected(job *EvaluationJob, config map[string]any, scorer string) (string, error) {
+	expected, ok := s.getExpectedText(job, config)
+	if !ok {
+		return "", fmt.Errorf("expected value is required for %s scorer: set config.expected or map the expected variable", scorer)
+	}
+	return expected, nil
+}
+
+func builtinScoreName(config map[string]any, fallback string) string {
+	if name, ok := config["score_name"].(string); ok && name != "" {
+		return name
+	}
+	return fallback
+}
+
+func numericResult(name string, value float64, reason string) *ScorerResult {
+	return &ScorerResult{
+		Scores: []ScoreOutput{
+			{
+				Name:   name,
+				Value:  &value,
+				Type:   "NUMERIC",
+				Reason: &reason,
+			},
+		},
+	}
+}
+
+func booleanResult(name string, passed bool, reason string) *ScorerResult {
+	value := 0.0
+	if passed {
+		value = 1.0
+	}
+	return &ScorerResult{
+		Scores: []ScoreOutput{
+			{
+				Name:   name,
+				Value:  &value,
+				Type:   "BOOLEAN",
+				Reason: &reason,
+			},
+		},
+	}
+}
+
+func normalizeCase(text string, config map[string]any) string {
+	if cs, ok := config["case_sensitive"].(bool); ok && cs {
+		return text
+	}
+	return strings.ToLower(text)
+}
+
+// tokenize splits text into words, dropping punctuation
+func tokenize(text string, config map[string]any) []string {
+	return strings.FieldsFunc(normalizeCase(text, config), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// extractList reads a JSON array, or splits text on config.delimiter
+func extractList(text string, config map[string]any) []string {
+	var items []string
+
+	var array []interface{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(text)), &array); err == nil {
+		for _, item := range array {
+			items = append(items, stringifyValue(item))
+		}
+	} else if delimiter, ok := config["delimiter"].(string); ok && delimiter != "" {
+		items = strings.Split(text, delimiter)
+	} else {
+		items = strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == '\n' })
+	}
+
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		item = strings.TrimSpace(normalizeCase(item, config))
+		if item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func toSet(items []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		set[item] = struct{}{}
+	}
+	return set
+}
+
+func f1Score(common, candidateLen, referenceLen int) float64 {
+	if candidateLen == 0 && referenceLen == 0 {
+		return 1
+	}
+	if common == 0 {
+		return 0
+	}
+	precision := float64(common) / float64(candidateLen)
+	recall := float64(common) / float64(referenceLen)
+	return 2 * precision * recall / (precision + recall)
+}
+
+func levenshteinDistance(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func jaroSimilarity(a, b []rune) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	window := max(max(len(a), len(b))/2-1, 0)
+	matchedA := make([]bool, len(a))
+	matchedB := make([]bool, len(b))
+	matches := 0
+	for i := range a {
+		for j := max(0, i-window); j <= min(len(b)-1, i+window); j++ {
+			if !matchedB[j] && a[i] == b[j] {
+				matchedA[i], matchedB[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions, k := 0, 0
+	for i := range a {
+		if !matchedA[i] {
+			continue
+		}
+		for !matchedB[k] {
+			k++
+		}
+		if a[i] != b[k] {
+			transpositions++
+		}
+		k++
+	}
+
+	m := float64(matches)
+	return (m/float64(len(a)) + m/float64(len(b)) + (m-float64(transpositions)/2)/m) / 3
+}
+
+func bleuScore(candidate, reference []string, maxN int) float64 {
+	if len(candidate) == 0 {
+		if len(reference) == 0 {
+			return 1
+		}
+		return 0
+	}
+
+	logPrecision := 0.0
+	for n := 1; n <= maxN; n++ {
+		referenceCounts := ngramCounts(reference, n)
+		clipped := 0
+		for gram, count := range ngramCounts(candidate, n) {
+			clipped += min(count, referenceCounts[gram])
+		}
+		numerator, denominator := float64(clipped), float64(max(len(candidate)-n+1, 0))
+		if n > 1 {
+			numerator++
+			denominator++
+		}
+		if numerator == 0 {
+			return 0
+		}
+		logPrecision += math.Log(numerator / denominator)
+	}
+
+	brevityPenalty := 1.0
+	if len(candidate) < len(reference) {
+		brevityPenalty = math.Exp(1 - float64(len(reference))/float64(len(candidate)))
+	}
+	return brevityPenalty * math.Exp(logPrecision/float64(maxN))
+}
+
+func ngramCounts(tokens []string, n int) map[string]int {
+	counts := make(map[string]int)
+	for i := 0; i+n <= len(tokens); i++ {
+		counts[strings.Join(tokens[i:i+n], "\x00")]++
+	}
+	return counts
+}
+
+func longestCommonSubsequence(a, b []string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			if a[i-1] == b[j-1] {
+				current[j] = previous[j-1] + 1
+			} else {
+				current[j] = max(previous[j], current[j-1])
+			}
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
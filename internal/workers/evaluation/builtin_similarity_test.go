@@ -0,0 +1,162 @@
+package evaluation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runBuiltin executes a builtin scorer on an output and expected value
+func runBuiltin(t *testing.T, scorerName string, config map[string]any, output, expected string) ScoreOutput {
+	t.Helper()
+	if config == nil {
+		config = map[string]any{}
+	}
+	job := &EvaluationJob{
+		ScorerConfig: map[string]any{"scorer_name": scorerName, "config": config},
+		Variables:    map[string]string{"output": output, "expected": expected},
+	}
+	result, err := NewBuiltinScorer(newTestLogger()).Execute(context.Background(), job)
+	require.NoError(t, err)
+	require.Len(t, result.Scores, 1)
+	require.NotNil(t, result.Scores[0].Value)
+	return result.Scores[0]
+}
+
+func TestBuiltinScorer_Levenshtein(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected string
+		config   map[string]any
+		want     float64
+	}{
+		{"identical", "kitten", "kitten", nil, 1},
+		{"classic example", "kitten", "sitting", nil, 1 - 3.0/7},
+		{"case insensitive by default", "Paris", "paris", nil, 1},
+		{"case sensitive", "Paris", "paris", map[string]any{"case_sensitive": true}, 0.8},
+		{"unicode", "naïve", "naive", nil, 0.8},
+		{"completely different", "abc", "xyz", nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := runBuiltin(t, "levenshtein", tt.config, tt.output, tt.expected)
+			assert.Equal(t, "levenshtein", score.Name)
+			assert.Equal(t, "NUMERIC", score.Type)
+			assert.InDelta(t, tt.want, *score.Value, 1e-9)
+		})
+	}
+}
+
+func TestBuiltinScorer_Jaro(t *testing.T) {
+	// Reference values from Winkler (1990)
+	jaro := runBuiltin(t, "jaro", nil, "MARTHA", "MARHTA")
+	assert.InDelta(t, 0.9444, *jaro.Value, 1e-4)
+	assert.Equal(t, "jaro", jaro.Name)
+
+	winkler := runBuiltin(t, "jaro_winkler", nil, "MARTHA", "MARHTA")
+	assert.InDelta(t, 0.9611, *winkler.Value, 1e-4)
+
+	assert.InDelta(t, 0.8133, *runBuiltin(t, "jaro_winkler", nil, "DIXON", "DICKSONX").Value, 1e-4)
+	assert.Equal(t, 0.0, *runBuiltin(t, "jaro", nil, "abc", "xyz").Value)
+	assert.Equal(t, 1.0, *runBuiltin(t, "jaro_winkler", nil, "", "").Value)
+}
+
+func TestBuiltinScorer_BLEU(t *testing.T) {
+	identical := runBuiltin(t, "bleu", nil, "the cat sat on the mat", "The cat sat on the mat.")
+	assert.InDelta(t, 1.0, *identical.Value, 1e-9)
+
+	partial := runBuiltin(t, "bleu", nil, "the cat is on the mat", "the cat sat on the mat")
+	assert.Greater(t, *partial.Value, 0.3)
+	assert.Less(t, *partial.Value, 1.0)
+
+	// Shorter candidates pay the brevity penalty
+	short := runBuiltin(t, "bleu", nil, "the cat", "the cat sat on the mat")
+	assert.Less(t, *short.Value, *partial.Value)
+
+	assert.Equal(t, 0.0, *runBuiltin(t, "bleu", nil, "dogs bark", "the cat sat").Value)
+
+	unigram := runBuiltin(t, "bleu", map[string]any{"max_n": 1.0}, "mat the on sat cat the", "the cat sat on the mat")
+	assert.InDelta(t, 1.0, *unigram.Value, 1e-9)
+}
+
+func TestBuiltinScorer_RougeL(t *testing.T) {
+	score := runBuiltin(t, "rouge_l", nil, "police killed the gunman", "the police kill the gunman")
+	// LCS "police the gunman" = 3; P = 3/4, R = 3/5
+	assert.InDelta(t, 2*0.75*0.6/(0.75+0.6), *score.Value, 1e-9)
+	assert.Equal(t, "rouge_l", score.Name)
+
+	assert.Equal(t, 1.0, *runBuiltin(t, "rouge_l", nil, "same words", "Same, words!").Value)
+	assert.Equal(t, 0.0, *runBuiltin(t, "rouge_l", nil, "nothing", "in common").Value)
+}
+
+func TestBuiltinScorer_TokenF1(t *testing.T) {
+	score := runBuiltin(t, "token_f1", nil, "Barack Obama was president", "Obama")
+	// P = 1/4, R = 1
+	assert.InDelta(t, 0.4, *score.Value, 1e-9)
+
+	assert.Equal(t, 1.0, *runBuiltin(t, "token_f1", nil, "the the cat", "cat the the").Value)
+	assert.InDelta(t, 0.8, *runBuiltin(t, "token_f1", nil, "the cat", "the the cat").Value, 1e-9)
+}
+
+func TestBuiltinScorer_Jaccard(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected string
+		config   map[string]any
+		want     float64
+	}{
+		{"json arrays", `["paris", "london", "rome"]`, `["Paris", "Berlin", "Rome"]`, nil, 0.5},
+		{"comma separated", "red, green, blue", "blue,green,red", nil, 1},
+		{"newline separated", "a\nb\n", "a\nc", nil, 1.0 / 3},
+		{"custom delimiter", "a|b|b", "b|a", map[string]any{"delimiter": "|"}, 1},
+		{"both empty", "", "", nil, 1},
+		{"disjoint", "x", "y", nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := runBuiltin(t, "jaccard", tt.config, tt.output, tt.expected)
+			assert.InDelta(t, tt.want, *score.Value, 1e-9)
+		})
+	}
+}
+
+func TestBuiltinScorer_ExpectedSources(t *testing.T) {
+	scorer := NewBuiltinScorer(newTestLogger())
+	ctx := context.Background()
+
+	t.Run("config expected wins", func(t *testing.T) {
+		job := &EvaluationJob{
+			ScorerConfig: map[string]any{"scorer_name": "token_f1", "config": map[string]any{"expected": "yes"}},
+			Variables:    map[string]string{"output": "yes", "expected": "no"},
+		}
+		result, err := scorer.Execute(ctx, job)
+		require.NoError(t, err)
+		assert.Equal(t, 1.0, *result.Scores[0].Value)
+	})
+
+	t.Run("span data expected", func(t *testing.T) {
+		job := &EvaluationJob{
+			ScorerConfig: map[string]any{"scorer_name": "token_f1"},
+			SpanData:     map[string]interface{}{"output": "yes", "expected": map[string]interface{}{"answer": "yes"}},
+		}
+		result, err := scorer.Execute(ctx, job)
+		require.NoError(t, err)
+		assert.Equal(t, 1.0, *result.Scores[0].Value)
+	})
+
+	t.Run("missing expected", func(t *testing.T) {
+		job := &EvaluationJob{
+			ScorerConfig: map[string]any{"scorer_name": "bleu"},
+			Variables:    map[string]string{"output": "yes"},
+		}
+		_, err := scorer.Execute(ctx, job)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "expected value is required for bleu scorer")
+	})
+}
@@ -0,0 +1,725 @@
+package evaluation
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxSchemaErrors = 3
+	maxSchemaDepth  = 64
+)
+
+var numberPattern = regexp.MustCompile(`[-+]?(?:\d+\.?\d*|\.\d+)(?:[eE][-+]?\d+)?`)
+
+// executeJSONSchema validates the output against config.schema, a JSON Schema
+// object. Supported keywords: type, enum, const, properties, required,
+// additionalProperties, items, minItems, maxItems, uniqueItems, minLength,
+// maxLength, pattern, minimum, maximum, exclusiveMinimum, exclusiveMaximum,
+// multipleOf, allOf, anyOf, oneOf, not and local $ref.
+func (s *BuiltinScorer) executeJSONSchema(text string, config map[string]any) (*ScorerResult, error) {
+	schema, err := parseSchemaConfig(config["schema"])
+	if err != nil {
+		return nil, err
+	}
+
+	name := builtinScoreName(config, "json_schema")
+	var document interface{}
+	if err := json.Unmarshal([]byte(text), &document); err != nil {
+		return booleanResult(name, false, fmt.Sprintf("Invalid JSON: %s", err.Error())), nil
+	}
+
+	validator := &schemaValidator{root: schema}
+	validator.validate(schema, document, "$", 0)
+	if len(validator.errors) == 0 {
+		return booleanResult(name, true, "Output matches the schema"), nil
+	}
+
+	errs := validator.errors
+	more := ""
+	if len(errs) > maxSchemaErrors {
+		more = fmt.Sprintf(" (and %d more)", len(errs)-maxSchemaErrors)
+		errs = errs[:maxSchemaErrors]
+	}
+	return booleanResult(name, false, "Schema violations: "+strings.Join(errs, "; ")+more), nil
+}
+
+// executeJSONPathEquals compares the value at config.path in the output with
+// config.value, or with the value at the same path (or config.expected_path)
+// in the expected JSON
+func (s *BuiltinScorer) executeJSONPathEquals(job *EvaluationJob, text string, config map[string]any) (*ScorerResult, error) {
+	path, ok := config["path"].(string)
+	if !ok || path == "" {
+		return nil, fmt.Errorf("path is required for json_path_equals scorer")
+	}
+	segments, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	want, hasValue := config["value"]
+	if !hasValue {
+		expected, err := s.requireExpected(job, config, "json_path_equals")
+		if err != nil {
+			return nil, err
+		}
+		expectedPath := path
+		if p, ok := config["expected_path"].(string); ok && p != "" {
+			expectedPath = p
+		}
+		expectedSegments, err := parseJSONPath(expectedPath)
+		if err != nil {
+			return nil, err
+		}
+		var expectedDoc interface{}
+		if err := json.Unmarshal([]byte(expected), &expectedDoc); err != nil {
+			return nil, fmt.Errorf("expected value is not valid JSON: %w", err)
+		}
+		if want, ok = lookupJSONPath(expectedDoc, expectedSegments); !ok {
+			return nil, fmt.Errorf("expected value has no %s", expectedPath)
+		}
+	}
+
+	name := builtinScoreName(config, "json_path_equals")
+	var document interface{}
+	if err := json.Unmarshal([]byte(text), &document); err != nil {
+		return booleanResult(name, false, fmt.Sprintf("Invalid JSON: %s", err.Error())), nil
+	}
+	got, found := lookupJSONPath(document, segments)
+	if !found {
+		return booleanResult(name, false, fmt.Sprintf("Output has no %s", path)), nil
+	}
+
+	equal := jsonEqual(got, want)
+	reason := fmt.Sprintf("%s is %s, expected %s", path, compactJSON(got), compactJSON(want))
+	if equal {
+		reason = fmt.Sprintf("%s equals %s", path, compactJSON(want))
+	}
+	return booleanResult(name, equal, reason), nil
+}
+
+// executeNumericMatch checks that the number in the output (the whole text,
+// the value at config.path, or else the first number found) is within
+// tolerance (absolute) or relative_tolerance of config.value or the expected value
+func (s *BuiltinScorer) executeNumericMatch(job *EvaluationJob, text string, config map[string]any) (*ScorerResult, error) {
+	var want float64
+	if v, ok := config["value"]; ok {
+		n, ok := toNumber(v)
+		if !ok {
+			return nil, fmt.Errorf("value must be a number for numeric_match scorer")
+		}
+		want = n
+	} else {
+		expected, err := s.requireExpected(job, config, "numeric_match")
+		if err != nil {
+			return nil, err
+		}
+		n, ok := extractNumber(expected)
+		if !ok {
+			return nil, fmt.Errorf("expected value %q is not a number", expected)
+		}
+		want = n
+	}
+
+	tolerance, _ := config["tolerance"].(float64)
+	relativeTolerance, _ := config["relative_tolerance"].(float64)
+	if tolerance < 0 || relativeTolerance < 0 {
+		return nil, fmt.Errorf("tolerance and relative_tolerance must not be negative")
+	}
+	allowed := math.Max(tolerance, relativeTolerance*math.Abs(want))
+
+	name := builtinScoreName(config, "numeric_match")
+	var got float64
+	var found bool
+	if path, ok := config["path"].(string); ok && path != "" {
+		segments, err := parseJSONPath(path)
+		if err != nil {
+			return nil, err
+		}
+		var document interface{}
+		if err := json.Unmarshal([]byte(text), &document); err != nil {
+			return booleanResult(name, false, fmt.Sprintf("Invalid JSON: %s", err.Error())), nil
+		}
+		if v, ok := lookupJSONPath(document, segments); ok {
+			got, found = toNumber(v)
+		}
+	} else {
+		got, found = extractNumber(text)
+	}
+	if !found {
+		return booleanResult(name, false, fmt.Sprintf("No number found, expected %g", want)), nil
+	}
+
+	passed := math.Abs(got-want) <= allowed
+	reason := fmt.Sprintf("Got %g, expected %g (±%g)", got, want, allowed)
+	return booleanResult(name, passed, reason), nil
+}
+
+// executeToolCall checks that the span called the expected function with the
+// expected arguments. Calls are read from the gen_ai.tool.* span attributes,
+// a mapped tool_calls variable, or tool calls in the output (OpenAI
+// tool_calls, Anthropic tool_use or {"name", "arguments"} objects).
+// config.name and config.arguments give the expected call, otherwise the
+// expected value is read as {"name": ..., "arguments": {...}}. Arguments
+// match when every expected argument is equal, or all of them with exact_arguments.
+func (s *BuiltinScorer) executeToolCall(job *EvaluationJob, text string, config map[string]any) (*ScorerResult, error) {
+	want, err := s.expectedToolCall(job, config)
+	if err != nil {
+		return nil, err
+	}
+	exact, _ := config["exact_arguments"].(bool)
+	name := builtinScoreName(config, "tool_call")
+
+	calls := spanToolCalls(job.SpanData)
+	if raw, ok := job.Variables["tool_calls"]; ok {
+		calls = append(calls, parseToolCallsJSON(raw)...)
+	}
+	calls = append(calls, parseToolCallsJSON(text)...)
+	if len(calls) == 0 {
+		return booleanResult(name, false, fmt.Sprintf("No tool call found, expected %s", want.name)), nil
+	}
+
+	var called []string
+	var mismatched []string
+	for _, call := range calls {
+		if call.name != want.name {
+			called = append(called, call.name)
+			continue
+		}
+		diff := diffToolArguments(want.arguments, call.arguments, exact)
+		if len(diff) == 0 {
+			return booleanResult(name, true, fmt.Sprintf("Called %s with matching arguments", want.name)), nil
+		}
+		mismatched = diff
+	}
+
+	if mismatched != nil {
+		return booleanResult(name, false, fmt.Sprintf("Called %s with mismatched arguments: %s", want.name, strings.Join(mismatched, ", "))), nil
+	}
+	return booleanResult(name, false, fmt.Sprintf("Expected %s, got %s", want.name, strings.Join(called, ", "))), nil
+}
+
+type toolCall struct {
+	name      string
+	arguments map[string]interface{}
+}
+
+func (s *BuiltinScorer) expectedToolCall(job *EvaluationJob, config map[string]any) (*toolCall, error) {
+	if name, ok := config["name"].(string); ok && name != "" {
+		call := &toolCall{name: name}
+		if args, ok := config["arguments"].(map[string]any); ok {
+			call.arguments = args
+		}
+		return call, nil
+	}
+
+	expected, ok := s.getExpectedText(job, config)
+	if !ok {
+		return nil, fmt.Errorf("name is required for tool_call scorer: set config.name or map the expected variable")
+	}
+	calls := parseToolCallsJSON(expected)
+	if len(calls) != 1 {
+		return nil, fmt.Errorf("expected value must describe one tool call as {\"name\": ..., \"arguments\": {...}}")
+	}
+	return &calls[0], nil
+}
+
+// spanToolCalls reads the gen_ai.tool.* attributes of a tool span
+func spanToolCalls(spanData map[string]interface{}) []toolCall {
+	var name, params string
+	switch attrs := spanData["span_attributes"].(type) {
+	case map[string]string:
+		name, params = attrs["gen_ai.tool.name"], attrs["gen_ai.tool.parameters"]
+	case map[string]interface{}:
+		name, _ = attrs["gen_ai.tool.name"].(string)
+		if p, ok := attrs["gen_ai.tool.parameters"]; ok {
+			params = stringifyValue(p)
+		}
+	}
+	if name == "" {
+		return nil
+	}
+	return []toolCall{{name: name, arguments: parseToolArguments(params)}}
+}
+
+func parseToolCallsJSON(raw string) []toolCall {
+	var value interface{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(raw)), &value); err != nil {
+		return nil
+	}
+	return collectToolCalls(value)
+}
+
+func collectToolCalls(value interface{}) []toolCall {
+	switch v := value.(type) {
+	case []interface{}:
+		var calls []toolCall
+		for _, item := range v {
+			calls = append(calls, collectToolCalls(item)...)
+		}
+		return calls
+	case map[string]interface{}:
+		for _, key := range []string{"tool_calls", "toolCalls", "content"} {
+			if nested, ok := v[key].([]interface{}); ok {
+				return collectToolCalls(nested)
+			}
+		}
+		if function, ok := v["function"].(map[string]interface{}); ok {
+			return collectToolCalls(function)
+		}
+		name, _ := v["name"].(string)
+		if name == "" {
+			name, _ = v["toolName"].(string)
+		}
+		if name == "" {
+			return nil
+		}
+		for _, key := range []string{"arguments", "args", "input", "parameters"} {
+			if args, ok := v[key]; ok {
+				return []toolCall{{name: name, arguments: parseToolArguments(args)}}
+			}
+		}
+		return []toolCall{{name: name}}
+	}
+	return nil
+}
+
+// parseToolArguments accepts arguments as an object or a JSON encoded object
+func parseToolArguments(value interface{}) map[string]interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v
+	case string:
+		var args map[string]interface{}
+		if err := json.Unmarshal([]byte(v), &args); err == nil {
+			return args
+		}
+	}
+	return nil
+}
+
+// diffToolArguments lists the expected arguments the call got wrong, and with
+// exact also the arguments it should not have passed
+func diffToolArguments(want, got map[string]interface{}, exact bool) []string {
+	var diff []string
+	for key, value := range want {
+		actual, ok := got[key]
+		if !ok || !jsonEqual(actual, value) {
+			diff = append(diff, key)
+		}
+	}
+	if exact {
+		for key := range got {
+			if _, ok := want[key]; !ok {
+				diff = append(diff, key)
+			}
+		}
+	}
+	sort.Strings(diff)
+	return diff
+}
+
+// parseJSONPath splits "$.a.b[0]['c d']" (or "a.b.0") into its segments
+func parseJSONPath(path string) ([]string, error) {
+	path = strings.TrimPrefix(strings.TrimSpace(path), "$")
+	var segments []string
+	for i := 0; i < len(path); {
+		switch path[i] {
+		case '.':
+			i++
+		case '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path %q: unclosed bracket", path)
+			}
+			segments = append(segments, strings.Trim(path[i+1:i+end], `'"`))
+			i += end + 1
+		default:
+			end := strings.IndexAny(path[i:], ".[")
+			if end < 0 {
+				end = len(path) - i
+			}
+			segments = append(segments, path[i:i+end])
+			i += end
+		}
+	}
+	return segments, nil
+}
+
+func lookupJSONPath(document interface{}, segments []string) (interface{}, bool) {
+	current := document
+	for _, segment := range segments {
+		switch v := current.(type) {
+		case map[string]interface{}:
+			next, ok := v[segment]
+			if !ok {
+				return nil, false
+			}
+			current = next
+		case []interface{}:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(v) {
+				return nil, false
+			}
+			current = v[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// jsonEqual compares values as JSON, so 1 and 1.0 or int and float64 are equal
+func jsonEqual(a, b interface{}) bool {
+	return reflect.DeepEqual(normalizeJSONValue(a), normalizeJSONValue(b))
+}
+
+func normalizeJSONValue(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return v
+	}
+	return normalized
+}
+
+func compactJSON(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
+
+func toNumber(v interface{}) (float64, bool) {
+	if n, ok := toFloat(v); ok {
+		return n, true
+	}
+	if s, ok := v.(string); ok {
+		n, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// extractNumber parses the text as a number, or else takes the first number in it
+func extractNumber(text string) (float64, bool) {
+	trimmed := strings.TrimSpace(text)
+	if n, err := strconv.ParseFloat(trimmed, 64); err == nil {
+		return n, true
+	}
+	match := numberPattern.FindString(strings.ReplaceAll(trimmed, ",", ""))
+	if match == "" {
+		return 0, false
+	}
+	n, err := strconv.ParseFloat(match, 64)
+	return n, err == nil
+}
+
+func parseSchemaConfig(raw interface{}) (interface{}, error) {
+	switch v := raw.(type) {
+	case map[string]interface{}:
+		return v, nil
+	case bool:
+		return v, nil
+	case string:
+		var schema interface{}
+		if err := json.Unmarshal([]byte(v), &schema); err != nil {
+			return nil, fmt.Errorf("schema is not valid JSON: %w", err)
+		}
+		return parseSchemaConfig(schema)
+	case nil:
+		return nil, fmt.Errorf("schema is required for json_schema scorer")
+	}
+	return nil, fmt.Errorf("schema must be a JSON Schema object")
+}
+
+// schemaValidator checks a document against a subset of JSON Schema
+type schemaValidator struct {
+	root   interface{}
+	errors []string
+}
+
+func (v *schemaValidator) fail(path, format string, args ...interface{}) {
+	v.errors = append(v.errors, path+": "+fmt.Sprintf(format, args...))
+}
+
+// matches reports whether value is valid against schema without recording errors
+func (v *schemaValidator) matches(schema, value interface{}, path string, depth int) bool {
+	nested := &schemaValidator{root: v.root}
+	nested.validate(schema, value, path, depth)
+	return len(nested.errors) == 0
+}
+
+func (v *schemaValidator) validate(schema, value interface{}, path string, depth int) {
+	if depth > maxSchemaDepth {
+		v.fail(path, "schema nesting too deep")
+		return
+	}
+
+	switch s := schema.(type) {
+	case bool:
+		if !s {
+			v.fail(path, "not allowed")
+		}
+		return
+	case map[string]interface{}:
+		v.validateObjectSchema(s, value, path, depth)
+	}
+}
+
+func (v *schemaValidator) validateObjectSchema(s map[string]interface{}, value interface{}, path string, depth int) {
+	if ref, ok := s["$ref"].(string); ok {
+		target, found := v.resolveRef(ref)
+		if !found {
+			v.fail(path, "unresolved $ref %s", ref)
+			return
+		}
+		v.validate(target, value, path, depth+1)
+	}
+
+	if t, ok := s["type"]; ok && !matchesSchemaType(t, value) {
+		v.fail(path, "expected %s, got %s", compactJSON(t), jsonTypeName(value))
+		return
+	}
+	if enum, ok := s["enum"].([]interface{}); ok {
+		found := false
+		for _, option := range enum {
+			if jsonEqual(option, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.fail(path, "must be one of %s", compactJSON(enum))
+		}
+	}
+	if constant, ok := s["const"]; ok && !jsonEqual(constant, value) {
+		v.fail(path, "must be %s", compactJSON(constant))
+	}
+
+	switch val := value.(type) {
+	case map[string]interface{}:
+		v.validateObject(s, val, path, depth)
+	case []interface{}:
+		v.validateArray(s, val, path, depth)
+	case string:
+		v.validateString(s, val, path)
+	case float64:
+		v.validateNumber(s, val, path)
+	}
+
+	if all, ok := s["allOf"].([]interface{}); ok {
+		for _, sub := range all {
+			v.validate(sub, value, path, depth+1)
+		}
+	}
+	if anyOf, ok := s["anyOf"].([]interface{}); ok {
+		matched := false
+		for _, sub := range anyOf {
+			if v.matches(sub, value, path, depth+1) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.fail(path, "does not match any of anyOf")
+		}
+	}
+	if oneOf, ok := s["oneOf"].([]interface{}); ok {
+		count := 0
+		for _, sub := range oneOf {
+			if v.matches(sub, value, path, depth+1) {
+				count++
+			}
+		}
+		if count != 1 {
+			v.fail(path, "matches %d of oneOf, expected exactly 1", count)
+		}
+	}
+	if not, ok := s["not"]; ok && v.matches(not, value, path, depth+1) {
+		v.fail(path, "must not match the not schema")
+	}
+}
+
+func (v *schemaValidator) validateObject(s map[string]interface{}, obj map[string]interface{}, path string, depth int) {
+	if required, ok := s["required"].([]interface{}); ok {
+		for _, key := range required {
+			if name, ok := key.(string); ok {
+				if _, present := obj[name]; !present {
+					v.fail(path, "missing required property %q", name)
+				}
+			}
+		}
+	}
+
+	properties, _ := s["properties"].(map[string]interface{})
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		childPath := path + "." + key
+		if propertySchema, ok := properties[key]; ok {
+			v.validate(propertySchema, obj[key], childPath, depth+1)
+			continue
+		}
+		if additional, ok := s["additionalProperties"]; ok {
+			if allowed, isBool := additional.(bool); isBool && !allowed {
+				v.fail(path, "unexpected property %q", key)
+				continue
+			}
+			v.validate(additional, obj[key], childPath, depth+1)
+		}
+	}
+}
+
+func (v *schemaValidator) validateArray(s map[string]interface{}, arr []interface{}, path string, depth int) {
+	if n, ok := s["minItems"].(float64); ok && float64(len(arr)) < n {
+		v.fail(path, "expected at least %g items, got %d", n, len(arr))
+	}
+	if n, ok := s["maxItems"].(float64); ok && float64(len(arr)) > n {
+		v.fail(path, "expected at most %g items, got %d", n, len(arr))
+	}
+	if unique, ok := s["uniqueItems"].(bool); ok && unique {
+		seen := make(map[string]struct{}, len(arr))
+		for _, item := range arr {
+			key := compactJSON(normalizeJSONValue(item))
+			if _, dup := seen[key]; dup {
+				v.fail(path, "items must be unique, %s repeats", key)
+				break
+			}
+			seen[key] = struct{}{}
+		}
+	}
+
+	switch items := s["items"].(type) {
+	case []interface{}:
+		for i, itemSchema := range items {
+			if i < len(arr) {
+				v.validate(itemSchema, arr[i], fmt.Sprintf("%s[%d]", path, i), depth+1)
+			}
+		}
+	case nil:
+	default:
+		for i, item := range arr {
+			v.validate(items, item, fmt.Sprintf("%s[%d]", path, i), depth+1)
+		}
+	}
+}
+
+func (v *schemaValidator) validateString(s map[string]interface{}, str, path string) {
+	length := utf8.RuneCountInString(str)
+	if n, ok := s["minLength"].(float64); ok && float64(length) < n {
+		v.fail(path, "expected at least %g characters, got %d", n, length)
+	}
+	if n, ok := s["maxLength"].(float64); ok && float64(length) > n {
+		v.fail(path, "expected at most %g characters, got %d", n, length)
+	}
+	if pattern, ok := s["pattern"].(string); ok {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			v.fail(path, "invalid pattern %q", pattern)
+		} else if !re.MatchString(str) {
+			v.fail(path, "does not match pattern %q", pattern)
+		}
+	}
+}
+
+func (v *schemaValidator) validateNumber(s map[string]interface{}, n float64, path string) {
+	if bound, ok := s["minimum"].(float64); ok && n < bound {
+		v.fail(path, "%g is less than minimum %g", n, bound)
+	}
+	if bound, ok := s["maximum"].(float64); ok && n > bound {
+		v.fail(path, "%g is greater than maximum %g", n, bound)
+	}
+	if bound, ok := s["exclusiveMinimum"].(float64); ok && n <= bound {
+		v.fail(path, "%g must be greater than %g", n, bound)
+	}
+	if bound, ok := s["exclusiveMaximum"].(float64); ok && n >= bound {
+		v.fail(path, "%g must be less than %g", n, bound)
+	}
+	if divisor, ok := s["multipleOf"].(float64); ok && divisor > 0 {
+		quotient := n / divisor
+		if math.Abs(quotient-math.Round(quotient)) > 1e-9 {
+			v.fail(path, "%g is not a multiple of %g", n, divisor)
+		}
+	}
+}
+
+// resolveRef follows a local reference such as "#/$defs/address"
+func (v *schemaValidator) resolveRef(ref string) (interface{}, bool) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, false
+	}
+	current := v.root
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#"), "/") {
+		if token == "" {
+			continue
+		}
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		obj, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = obj[token]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func matchesSchemaType(t interface{}, value interface{}) bool {
+	switch types := t.(type) {
+	case string:
+		return matchesTypeName(types, value)
+	case []interface{}:
+		for _, item := range types {
+			if name, ok := item.(string); ok && matchesTypeName(name, value) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func matchesTypeName(name string, value interface{}) bool {
+	actual := jsonTypeName(value)
+	if name == "number" && actual == "integer" {
+		return true
+	}
+	return name == actual
+}
+
+func jsonTypeName(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if v == math.Trunc(v) && !math.IsInf(v, 0) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
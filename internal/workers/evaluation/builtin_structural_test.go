@@ -0,0 +1,245 @@
+package evaluation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltinScorer_JSONSchema(t *testing.T) {
+	schema := map[string]any{
+		"type":     "object",
+		"required": []interface{}{"answer", "confidence"},
+		"properties": map[string]interface{}{
+			"answer":     map[string]interface{}{"type": "string", "minLength": 1.0},
+			"confidence": map[string]interface{}{"type": "number", "minimum": 0.0, "maximum": 1.0},
+			"sources": map[string]interface{}{
+				"type":        "array",
+				"items":       map[string]interface{}{"$ref": "#/$defs/source"},
+				"uniqueItems": true,
+			},
+			"label": map[string]interface{}{"enum": []interface{}{"yes", "no"}},
+		},
+		"additionalProperties": false,
+		"$defs": map[string]interface{}{
+			"source": map[string]interface{}{"type": "string", "pattern": "^https?://"},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		output        string
+		want          float64
+		reasonContain string
+	}{
+		{"valid", `{"answer": "Paris", "confidence": 0.9, "sources": ["https://a.example"], "label": "yes"}`, 1, "matches"},
+		{"integer is a number", `{"answer": "Paris", "confidence": 1}`, 1, "matches"},
+		{"missing required", `{"answer": "Paris"}`, 0, `missing required property "confidence"`},
+		{"wrong type", `{"answer": 42, "confidence": 0.5}`, 0, `$.answer: expected "string", got integer`},
+		{"out of range", `{"answer": "Paris", "confidence": 1.5}`, 0, "greater than maximum"},
+		{"additional property", `{"answer": "Paris", "confidence": 0.5, "extra": true}`, 0, `unexpected property "extra"`},
+		{"ref pattern", `{"answer": "Paris", "confidence": 0.5, "sources": ["ftp://a"]}`, 0, "$.sources[0]: does not match pattern"},
+		{"duplicate items", `{"answer": "Paris", "confidence": 0.5, "sources": ["https://a", "https://a"]}`, 0, "items must be unique"},
+		{"enum", `{"answer": "Paris", "confidence": 0.5, "label": "maybe"}`, 0, "must be one of"},
+		{"invalid json", `Paris`, 0, "Invalid JSON"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := runBuiltin(t, "json_schema", map[string]any{"schema": schema}, tt.output, "")
+			assert.Equal(t, "json_schema", score.Name)
+			assert.Equal(t, "BOOLEAN", score.Type)
+			assert.Equal(t, tt.want, *score.Value)
+			require.NotNil(t, score.Reason)
+			assert.Contains(t, *score.Reason, tt.reasonContain)
+		})
+	}
+}
+
+func TestBuiltinScorer_JSONSchemaCombinators(t *testing.T) {
+	schema := `{"oneOf": [{"type": "integer"}, {"type": "string", "maxLength": 3}], "not": {"const": "bad"}}`
+
+	assert.Equal(t, 1.0, *runBuiltin(t, "json_schema", map[string]any{"schema": schema}, `7`, "").Value)
+	assert.Equal(t, 1.0, *runBuiltin(t, "json_schema", map[string]any{"schema": schema}, `"abc"`, "").Value)
+	assert.Equal(t, 0.0, *runBuiltin(t, "json_schema", map[string]any{"schema": schema}, `"abcd"`, "").Value)
+	assert.Equal(t, 0.0, *runBuiltin(t, "json_schema", map[string]any{"schema": schema}, `"bad"`, "").Value)
+	assert.Equal(t, 0.0, *runBuiltin(t, "json_schema", map[string]any{"schema": schema}, `1.5`, "").Value)
+
+	_, err := NewBuiltinScorer(newTestLogger()).executeJSONSchema(`{}`, map[string]any{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "schema is required")
+}
+
+func TestBuiltinScorer_JSONPathEquals(t *testing.T) {
+	output := `{"order": {"id": 42, "items": [{"sku": "A-1"}, {"sku": "B-2"}], "status": "shipped"}}`
+
+	tests := []struct {
+		name     string
+		config   map[string]any
+		expected string
+		want     float64
+	}{
+		{"configured value", map[string]any{"path": "$.order.status", "value": "shipped"}, "", 1},
+		{"number", map[string]any{"path": "order.id", "value": 42.0}, "", 1},
+		{"array index", map[string]any{"path": "$.order.items[1].sku", "value": "B-2"}, "", 1},
+		{"bracket key", map[string]any{"path": "$['order']['items'][0]", "value": map[string]any{"sku": "A-1"}}, "", 1},
+		{"mismatch", map[string]any{"path": "$.order.status", "value": "pending"}, "", 0},
+		{"missing path", map[string]any{"path": "$.order.carrier", "value": "ups"}, "", 0},
+		{"same path in expected", map[string]any{"path": "$.order.id"}, `{"order": {"id": 42}}`, 1},
+		{"expected path", map[string]any{"path": "$.order.status", "expected_path": "$.status"}, `{"status": "shipped"}`, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := runBuiltin(t, "json_path_equals", tt.config, output, tt.expected)
+			assert.Equal(t, tt.want, *score.Value)
+		})
+	}
+}
+
+func TestBuiltinScorer_NumericMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected string
+		config   map[string]any
+		want     float64
+	}{
+		{"exact", "42", "42", nil, 1},
+		{"number in text", "The total is $1,234.50.", "1234.5", nil, 1},
+		{"absolute tolerance", "3.14", "3.14159", map[string]any{"tolerance": 0.01}, 1},
+		{"outside tolerance", "3.1", "3.14159", map[string]any{"tolerance": 0.01}, 0},
+		{"relative tolerance", "105", "100", map[string]any{"relative_tolerance": 0.05}, 1},
+		{"configured value", "-2.5e3", "", map[string]any{"value": -2500.0}, 1},
+		{"json path", `{"result": {"value": "7.0"}}`, "7", map[string]any{"path": "$.result.value"}, 1},
+		{"no number", "unknown", "7", nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := runBuiltin(t, "numeric_match", tt.config, tt.output, tt.expected)
+			assert.Equal(t, "numeric_match", score.Name)
+			assert.Equal(t, tt.want, *score.Value)
+		})
+	}
+
+	_, err := NewBuiltinScorer(newTestLogger()).Execute(context.Background(), &EvaluationJob{
+		ScorerConfig: map[string]any{"scorer_name": "numeric_match", "config": map[string]any{"value": "abc"}},
+		Variables:    map[string]string{"output": "1"},
+	})
+	require.Error(t, err)
+}
+
+func TestBuiltinScorer_ToolCall(t *testing.T) {
+	expectWeather := map[string]any{
+		"name":      "get_weather",
+		"arguments": map[string]any{"city": "Paris", "days": 3.0},
+	}
+
+	tests := []struct {
+		name          string
+		job           *EvaluationJob
+		want          float64
+		reasonContain string
+	}{
+		{
+			name: "openai tool_calls with json arguments",
+			job: &EvaluationJob{
+				ScorerConfig: map[string]any{"scorer_name": "tool_call", "config": expectWeather},
+				Variables: map[string]string{"output": `{"tool_calls": [{"id": "call_1", "type": "function",
+					"function": {"name": "get_weather", "arguments": "{\"city\": \"Paris\", \"days\": 3, \"units\": \"metric\"}"}}]}`},
+			},
+			want:          1,
+			reasonContain: "Called get_weather with matching arguments",
+		},
+		{
+			name: "exact arguments reject extra arguments",
+			job: &EvaluationJob{
+				ScorerConfig: map[string]any{"scorer_name": "tool_call", "config": map[string]any{
+					"name": "get_weather", "arguments": map[string]any{"city": "Paris", "days": 3.0}, "exact_arguments": true,
+				}},
+				Variables: map[string]string{"output": `[{"name": "get_weather", "arguments": {"city": "Paris", "days": 3, "units": "metric"}}]`},
+			},
+			want:          0,
+			reasonContain: "mismatched arguments: units",
+		},
+		{
+			name: "anthropic tool_use content",
+			job: &EvaluationJob{
+				ScorerConfig: map[string]any{"scorer_name": "tool_call", "config": expectWeather},
+				Variables: map[string]string{"output": `{"role": "assistant", "content": [{"type": "text", "text": "Checking"},
+					{"type": "tool_use", "id": "tu_1", "name": "get_weather", "input": {"city": "Paris", "days": 2}}]}`},
+			},
+			want:          0,
+			reasonContain: "mismatched arguments: days",
+		},
+		{
+			name: "gen_ai.tool span attributes",
+			job: &EvaluationJob{
+				ScorerConfig: map[string]any{"scorer_name": "tool_call", "config": expectWeather},
+				SpanData: map[string]interface{}{
+					"output": "sunny",
+					"span_attributes": map[string]interface{}{
+						"gen_ai.tool.name":       "get_weather",
+						"gen_ai.tool.parameters": `{"city": "Paris", "days": 3}`,
+					},
+				},
+			},
+			want: 1,
+		},
+		{
+			name: "wrong function",
+			job: &EvaluationJob{
+				ScorerConfig: map[string]any{"scorer_name": "tool_call", "config": expectWeather},
+				Variables:    map[string]string{"output": `{"name": "search_flights", "arguments": {}}`},
+			},
+			want:          0,
+			reasonContain: "Expected get_weather, got search_flights",
+		},
+		{
+			name: "no tool call",
+			job: &EvaluationJob{
+				ScorerConfig: map[string]any{"scorer_name": "tool_call", "config": expectWeather},
+				Variables:    map[string]string{"output": "It will be sunny."},
+			},
+			want:          0,
+			reasonContain: "No tool call found",
+		},
+		{
+			name: "expected call from expected variable",
+			job: &EvaluationJob{
+				ScorerConfig: map[string]any{"scorer_name": "tool_call"},
+				Variables: map[string]string{
+					"output":   `{"function": {"name": "lookup", "arguments": "{\"id\": 7}"}}`,
+					"expected": `{"name": "lookup", "arguments": {"id": 7}}`,
+				},
+			},
+			want: 1,
+		},
+	}
+
+	scorer := NewBuiltinScorer(newTestLogger())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := scorer.Execute(context.Background(), tt.job)
+			require.NoError(t, err)
+			require.Len(t, result.Scores, 1)
+			assert.Equal(t, "tool_call", result.Scores[0].Name)
+			assert.Equal(t, tt.want, *result.Scores[0].Value)
+			if tt.reasonContain != "" {
+				assert.Contains(t, *result.Scores[0].Reason, tt.reasonContain)
+			}
+		})
+	}
+}
+
+func TestParseJSONPath(t *testing.T) {
+	segments, err := parseJSONPath(`$.a.b[0]['c d']["e"].f`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "0", "c d", "e", "f"}, segments)
+
+	_, err = parseJSONPath("$.a[0")
+	assert.Error(t, err)
+}
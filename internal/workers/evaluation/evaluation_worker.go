@@ -59,6 +59,7 @@ type EvaluationWorker struct {
 	llmScorer        Scorer
 	builtinScorer    Scorer
 	regexScorer      Scorer
+	pairwiseScorer   Scorer
 	logger           *slog.Logger
 
 	// Consumer configuration
@@ -86,6 +87,7 @@ type EvaluationWorker struct {
 	llmCalls      int64
 	builtinCalls  int64
 	regexCalls    int64
+	pairwiseCalls int64
 }
 
 // executionProgress tracks progress for a single evaluator execution
@@ -105,6 +107,7 @@ func NewEvaluationWorker(
 	llmScorer Scorer,
 	builtinScorer Scorer,
 	regexScorer Scorer,
+	pairwiseScorer Scorer,
 	logger *slog.Logger,
 	config *EvaluationWorkerConfig,
 ) *EvaluationWorker {
@@ -127,6 +130,7 @@ func NewEvaluationWorker(
 		llmScorer:        llmScorer,
 		builtinScorer:    builtinScorer,
 		regexScorer:      regexScorer,
+		pairwiseScorer:   pairwiseScorer,
 		logger:           logger,
 		consumerGroup:    config.ConsumerGroup,
 		consumerID:       config.ConsumerID,
@@ -180,6 +184,7 @@ func (w *EvaluationWorker) Stop() {
 		"llm_calls", atomic.LoadInt64(&w.llmCalls),
 		"builtin_calls", atomic.LoadInt64(&w.builtinCalls),
 		"regex_calls", atomic.LoadInt64(&w.regexCalls),
+		"pairwise_calls", atomic.LoadInt64(&w.pairwiseCalls),
 	)
 }
 
@@ -295,6 +300,9 @@ func (w *EvaluationWorker) processJob(ctx context.Context, msg redis.XMessage) e
 	case evaluation.ScorerTypeRegex:
 		scorer = w.regexScorer
 		atomic.AddInt64(&w.regexCalls, 1)
+	case evaluation.ScorerTypePairwise:
+		scorer = w.pairwiseScorer
+		atomic.AddInt64(&w.pairwiseCalls, 1)
 	default:
 		w.trackExecutionError(ctx, &job)
 		return fmt.Errorf("unknown scorer type: %s", job.ScorerType)
@@ -340,23 +348,11 @@ func (w *EvaluationWorker) processJob(ctx context.Context, msg redis.XMessage) e
 		return nil
 	}
 
-	scores := make([]*observability.Score, 0, len(result.Scores))
-	for _, output := range result.Scores {
-		score := &observability.Score{
-			ID:          ulid.New().String(),
-			ProjectID:   job.ProjectID.String(),
-			TraceID:     &job.TraceID,
-			SpanID:      &job.SpanID,
-			Name:        output.Name,
-			Value:       output.Value,
-			StringValue: output.StringValue,
-			Type:        output.Type,
-			Source:      observability.ScoreSourceEval,
-			Reason:      output.Reason,
-			Metadata:    w.buildScoreMetadata(job),
-			Timestamp:   time.Now(),
-		}
-		scores = append(scores, score)
+	var scores []*observability.Score
+	if job.Pairwise != nil {
+		scores = w.buildPairwiseScores(job, result.Scores)
+	} else {
+		scores = w.buildScores(job, result.Scores)
 	}
 
 	if err := w.scoreService.CreateScoreBatch(ctx, scores); err != nil {
@@ -487,6 +483,97 @@ func (w *EvaluationWorker) FlushExecutionStats(ctx context.Context) {
 	}
 }
 
+// buildScores attaches the scorer outputs to the evaluated span
+func (w *EvaluationWorker) buildScores(job EvaluationJob, outputs []ScoreOutput) []*observability.Score {
+	scores := make([]*observability.Score, 0, len(outputs))
+	for _, output := range outputs {
+		score := &observability.Score{
+			ID:          ulid.New().String(),
+			ProjectID:   job.ProjectID.String(),
+			TraceID:     &job.TraceID,
+			SpanID:      &job.SpanID,
+			Name:        output.Name,
+			Value:       output.Value,
+			StringValue: output.StringValue,
+			Type:        output.Type,
+			Source:      observability.ScoreSourceEval,
+			Reason:      output.Reason,
+			Metadata:    w.buildScoreMetadata(job),
+			Timestamp:   time.Now(),
+		}
+		scores = append(scores, score)
+	}
+	return scores
+}
+
+// buildPairwiseScores writes each pairwise outcome to both sides: side A gets
+// the outcome as judged, side B the inverted outcome. Side A is written first
+// so leaderboards can replay matches in score ID order.
+func (w *EvaluationWorker) buildPairwiseScores(job EvaluationJob, outputs []ScoreOutput) []*observability.Score {
+	scores := make([]*observability.Score, 0, 2*len(outputs))
+	for _, output := range outputs {
+		if output.StringValue == nil {
+			continue
+		}
+		outcome := evaluation.PairwiseOutcome(*output.StringValue)
+		sides := []struct {
+			name     string
+			self     PairwiseSide
+			opponent PairwiseSide
+			outcome  evaluation.PairwiseOutcome
+		}{
+			{"a", job.Pairwise.A, job.Pairwise.B, outcome},
+			{"b", job.Pairwise.B, job.Pairwise.A, outcome.Invert()},
+		}
+		for _, side := range sides {
+			value := string(side.outcome)
+			scores = append(scores, &observability.Score{
+				ID:               ulid.New().String(),
+				ProjectID:        job.ProjectID.String(),
+				TraceID:          optionalString(side.self.TraceID),
+				SpanID:           optionalString(side.self.SpanID),
+				ExperimentID:     optionalString(side.self.ExperimentID),
+				ExperimentItemID: optionalString(side.self.ExperimentItemID),
+				Name:             output.Name,
+				StringValue:      &value,
+				Type:             output.Type,
+				Source:           observability.ScoreSourceEval,
+				Reason:           output.Reason,
+				Metadata:         w.buildPairwiseScoreMetadata(job, side.name, side.opponent),
+				Timestamp:        time.Now(),
+			})
+		}
+	}
+	return scores
+}
+
+func (w *EvaluationWorker) buildPairwiseScoreMetadata(job EvaluationJob, side string, opponent PairwiseSide) string {
+	metadata := map[string]interface{}{
+		"evaluator_id":  job.EvaluatorID.String(),
+		"scorer_type":   string(job.ScorerType),
+		"job_id":        job.JobID.String(),
+		"pairwise_side": side,
+	}
+	if opponent.ExperimentID != "" {
+		metadata["opponent_experiment_id"] = opponent.ExperimentID
+	}
+	if opponent.ExperimentItemID != "" {
+		metadata["opponent_experiment_item_id"] = opponent.ExperimentItemID
+	}
+	if opponent.SpanID != "" {
+		metadata["opponent_span_id"] = opponent.SpanID
+	}
+	data, _ := json.Marshal(metadata)
+	return string(data)
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
 func (w *EvaluationWorker) buildScoreMetadata(job EvaluationJob) string {
 	metadata := map[string]interface{}{
 		"evaluator_id": job.EvaluatorID.String(),
@@ -506,5 +593,6 @@ func (w *EvaluationWorker) GetStats() map[string]int64 {
 		"llm_calls":      atomic.LoadInt64(&w.llmCalls),
 		"builtin_calls":  atomic.LoadInt64(&w.builtinCalls),
 		"regex_calls":    atomic.LoadInt64(&w.regexCalls),
+		"pairwise_calls": atomic.LoadInt64(&w.pairwiseCalls),
 	}
 }
@@ -39,6 +39,7 @@ type EvaluationJob struct {
 	ScorerType   evaluation.ScorerType  `json:"scorer_type"`
 	ScorerConfig map[string]any         `json:"scorer_config"`
 	Variables    map[string]string      `json:"variables"` // Extracted variables from span
+	Pairwise     *PairwiseTarget        `json:"pairwise,omitempty"` // Only for pairwise jobs: where the A and B scores are written
 	CreatedAt    time.Time              `json:"created_at"`
 }
 
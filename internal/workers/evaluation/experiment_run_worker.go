@@ -0,0 +1,951 @@
+package evaluation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"brokle/internal/core/domain/credentials"
+	"brokle/internal/core/domain/evaluation"
+	"brokle/internal/core/domain/organization"
+	"brokle/internal/core/domain/prompt"
+	"brokle/internal/infrastructure/database"
+	"brokle/pkg/ulid"
+)
+
+const (
+	experimentRunStream   = "evaluation:experiment-runs"
+	experimentRunPageSize = 500
+)
+
+// ExperimentRunWorkerConfig holds configuration for the experiment run worker
+type ExperimentRunWorkerConfig struct {
+	ConsumerGroup     string
+	ConsumerID        string
+	BlockDuration     time.Duration
+	MaxConcurrentRuns int           // Experiments executed at once
+	ItemConcurrency   int           // Dataset items in flight per experiment
+	MaxRetries        int           // Retries of a rate limited (429) model call per item
+	RetryBackoff      time.Duration // First 429 backoff, doubled per retry
+	MaxRetryBackoff   time.Duration // Cap on the 429 backoff
+}
+
+// ExperimentRunWorker executes experiments created from the dashboard wizard.
+// For every item of the configured dataset (or pinned dataset version) it
+// compiles the prompt version with the variable mapping, calls the model with
+// the project's credential, scores the output with the configured evaluators
+// and records an experiment item. Items that already have a result are
+// skipped, so a cancelled or interrupted run resumes where it stopped.
+type ExperimentRunWorker struct {
+	redis                 *database.RedisDB
+	experimentService     evaluation.ExperimentService
+	wizardService         evaluation.ExperimentWizardService
+	itemService           evaluation.ExperimentItemService
+	datasetItemService    evaluation.DatasetItemService
+	datasetVersionService evaluation.DatasetVersionService
+	promptService         prompt.PromptService
+	executionService      prompt.ExecutionService
+	credentialsService    credentials.ProviderCredentialService
+	projectRepo           organization.ProjectRepository
+	llmScorer             Scorer
+	builtinScorer         Scorer
+	regexScorer           Scorer
+	logger                *slog.Logger
+
+	// Consumer configuration
+	consumerGroup     string
+	consumerID        string
+	blockDuration     time.Duration
+	maxConcurrentRuns int
+	itemConcurrency   int
+	maxRetries        int
+	retryBackoff      time.Duration
+	maxRetryBackoff   time.Duration
+
+	// State management
+	quit    chan struct{}
+	wg      sync.WaitGroup
+	running int64
+
+	// Metrics
+	runsProcessed    int64
+	itemsCompleted   int64
+	itemsFailed      int64
+	rateLimitRetries int64
+	errorsCount      int64
+}
+
+// experimentRun is the resolved execution plan of one experiment
+type experimentRun struct {
+	experimentID ulid.ULID
+	projectID    ulid.ULID
+	config       *evaluation.ExperimentConfig
+	prompt       *prompt.PromptResponse
+	modelConfig  *prompt.ModelConfig
+	items        []*evaluation.DatasetItem // items without a result yet
+}
+
+// NewExperimentRunWorker creates a new experiment run worker
+func NewExperimentRunWorker(
+	redisDB *database.RedisDB,
+	experimentService evaluation.ExperimentService,
+	wizardService evaluation.ExperimentWizardService,
+	itemService evaluation.ExperimentItemService,
+	datasetItemService evaluation.DatasetItemService,
+	datasetVersionService evaluation.DatasetVersionService,
+	promptService prompt.PromptService,
+	executionService prompt.ExecutionService,
+	credentialsService credentials.ProviderCredentialService,
+	projectRepo organization.ProjectRepository,
+	llmScorer Scorer,
+	builtinScorer Scorer,
+	regexScorer Scorer,
+	logger *slog.Logger,
+	config *ExperimentRunWorkerConfig,
+) *ExperimentRunWorker {
+	if config == nil {
+		config = &ExperimentRunWorkerConfig{
+			ConsumerGroup:     "experiment-run-workers",
+			ConsumerID:        "experiment-run-" + ulid.New().String(),
+			BlockDuration:     time.Second,
+			MaxConcurrentRuns: 2,
+			ItemConcurrency:   5,
+			MaxRetries:        5,
+			RetryBackoff:      time.Second,
+			MaxRetryBackoff:   30 * time.Second,
+		}
+	}
+
+	return &ExperimentRunWorker{
+		redis:                 redisDB,
+		experimentService:     experimentService,
+		wizardService:         wizardService,
+		itemService:           itemService,
+		datasetItemService:    datasetItemService,
+		datasetVersionService: datasetVersionService,
+		promptService:         promptService,
+		executionService:      executionService,
+		credentialsService:    credentialsService,
+		projectRepo:           projectRepo,
+		llmScorer:             llmScorer,
+		builtinScorer:         builtinScorer,
+		regexScorer:           regexScorer,
+		logger:                logger,
+		consumerGroup:         config.ConsumerGroup,
+		consumerID:            config.ConsumerID,
+		blockDuration:         config.BlockDuration,
+		maxConcurrentRuns:     max(config.MaxConcurrentRuns, 1),
+		itemConcurrency:       max(config.ItemConcurrency, 1),
+		maxRetries:            config.MaxRetries,
+		retryBackoff:          config.RetryBackoff,
+		maxRetryBackoff:       config.MaxRetryBackoff,
+		quit:                  make(chan struct{}),
+	}
+}
+
+// Start begins the experiment run worker
+func (w *ExperimentRunWorker) Start(ctx context.Context) error {
+	if !atomic.CompareAndSwapInt64(&w.running, 0, 1) {
+		return errors.New("experiment run worker already running")
+	}
+
+	w.logger.Info("Starting experiment run worker",
+		"consumer_group", w.consumerGroup,
+		"consumer_id", w.consumerID,
+		"max_concurrent_runs", w.maxConcurrentRuns,
+		"item_concurrency", w.itemConcurrency,
+	)
+
+	if err := w.ensureConsumerGroup(ctx); err != nil {
+		return fmt.Errorf("failed to ensure consumer group: %w", err)
+	}
+
+	w.wg.Add(1)
+	go w.consumeLoop(ctx)
+
+	return nil
+}
+
+// Stop gracefully stops the experiment run worker. Runs in progress finish
+// their in-flight items and are queued again to resume on the next start.
+func (w *ExperimentRunWorker) Stop() {
+	if !atomic.CompareAndSwapInt64(&w.running, 1, 0) {
+		return
+	}
+
+	w.logger.Info("Stopping experiment run worker")
+	close(w.quit)
+	w.wg.Wait()
+
+	w.logger.Info("Experiment run worker stopped",
+		"runs_processed", atomic.LoadInt64(&w.runsProcessed),
+		"items_completed", atomic.LoadInt64(&w.itemsCompleted),
+		"items_failed", atomic.LoadInt64(&w.itemsFailed),
+		"rate_limit_retries", atomic.LoadInt64(&w.rateLimitRetries),
+		"errors_count", atomic.LoadInt64(&w.errorsCount),
+	)
+}
+
+func (w *ExperimentRunWorker) ensureConsumerGroup(ctx context.Context) error {
+	err := w.redis.Client.XGroupCreateMkStream(ctx, experimentRunStream, w.consumerGroup, "0").Err()
+	if err != nil && !isGroupExistsError(err) {
+		return err
+	}
+	return nil
+}
+
+func (w *ExperimentRunWorker) consumeLoop(ctx context.Context) {
+	defer w.wg.Done()
+
+	// A run slot is taken before reading so a message is only claimed when it can start
+	slots := make(chan struct{}, w.maxConcurrentRuns)
+
+	for {
+		select {
+		case slots <- struct{}{}:
+		case <-w.quit:
+			return
+		case <-ctx.Done():
+			return
+		}
+
+		message, err := w.readMessage(ctx)
+		if err != nil || message == nil {
+			<-slots
+			if err != nil && !errors.Is(err, context.Canceled) {
+				w.logger.Error("Error consuming experiment runs", "error", err)
+				atomic.AddInt64(&w.errorsCount, 1)
+				select {
+				case <-time.After(w.retryBackoff):
+				case <-w.quit:
+					return
+				}
+			}
+			continue
+		}
+
+		w.wg.Add(1)
+		go func(message redis.XMessage) {
+			defer w.wg.Done()
+			defer func() { <-slots }()
+			w.handleMessage(ctx, message)
+		}(*message)
+	}
+}
+
+func (w *ExperimentRunWorker) readMessage(ctx context.Context) (*redis.XMessage, error) {
+	streams, err := w.redis.Client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    w.consumerGroup,
+		Consumer: w.consumerID,
+		Streams:  []string{experimentRunStream, ">"},
+		Count:    1,
+		Block:    w.blockDuration,
+	}).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	for _, stream := range streams {
+		if len(stream.Messages) > 0 {
+			return &stream.Messages[0], nil
+		}
+	}
+	return nil, nil
+}
+
+func (w *ExperimentRunWorker) handleMessage(ctx context.Context, message redis.XMessage) {
+	dataStr, ok := message.Values["data"].(string)
+	var run ExperimentRunMessageData
+	if !ok {
+		w.logger.Error("Missing or invalid data field in experiment run message", "message_id", message.ID)
+		atomic.AddInt64(&w.errorsCount, 1)
+	} else if err := json.Unmarshal([]byte(dataStr), &run); err != nil {
+		w.logger.Error("Failed to unmarshal experiment run message", "message_id", message.ID, "error", err)
+		atomic.AddInt64(&w.errorsCount, 1)
+	} else {
+		interrupted, err := w.runExperiment(ctx, &run)
+		if err != nil {
+			w.logger.Error("Experiment run failed",
+				"experiment_id", run.ExperimentID,
+				"project_id", run.ProjectID,
+				"error", err,
+			)
+			atomic.AddInt64(&w.errorsCount, 1)
+		}
+		if interrupted {
+			w.requeue(ctx, message)
+		}
+	}
+
+	if err := w.redis.Client.XAck(ctx, experimentRunStream, w.consumerGroup, message.ID).Err(); err != nil {
+		w.logger.Error("Failed to ack message", "message_id", message.ID, "error", err)
+	}
+}
+
+// requeue publishes an interrupted run again so it resumes after a restart
+func (w *ExperimentRunWorker) requeue(ctx context.Context, message redis.XMessage) {
+	if err := w.redis.Client.XAdd(ctx, &redis.XAddArgs{
+		Stream: experimentRunStream,
+		Values: message.Values,
+	}).Err(); err != nil {
+		w.logger.Error("Failed to requeue interrupted experiment run", "message_id", message.ID, "error", err)
+	}
+}
+
+// runExperiment executes the remaining items of a running experiment. It
+// reports interrupted when the worker stopped before every item was recorded.
+func (w *ExperimentRunWorker) runExperiment(ctx context.Context, msg *ExperimentRunMessageData) (bool, error) {
+	experiment, err := w.experimentService.GetByID(ctx, msg.ExperimentID, msg.ProjectID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get experiment: %w", err)
+	}
+	if experiment.Status != evaluation.ExperimentStatusRunning {
+		w.logger.Info("Skipping experiment run, experiment is not running",
+			"experiment_id", experiment.ID,
+			"status", experiment.Status,
+		)
+		return false, nil
+	}
+
+	run, err := w.prepareRun(ctx, experiment)
+	if err != nil {
+		w.failExperiment(ctx, experiment, err)
+		return false, err
+	}
+
+	w.logger.Info("Running experiment",
+		"experiment_id", run.experimentID,
+		"project_id", run.projectID,
+		"remaining_items", len(run.items),
+	)
+
+	if len(run.items) == 0 {
+		// Every item was recorded by an earlier attempt; settle the final status
+		if _, err := w.experimentService.IncrementAndCheckCompletion(ctx, run.experimentID, run.projectID, 0, 0); err != nil {
+			return false, fmt.Errorf("failed to complete experiment: %w", err)
+		}
+		atomic.AddInt64(&w.runsProcessed, 1)
+		return false, nil
+	}
+
+	sem := make(chan struct{}, w.itemConcurrency)
+	var wg sync.WaitGroup
+	var interrupted atomic.Bool
+	stoppedBy := ""
+
+dispatch:
+	for _, item := range run.items {
+		select {
+		case sem <- struct{}{}:
+		case <-w.quit:
+			interrupted.Store(true)
+			break dispatch
+		case <-ctx.Done():
+			interrupted.Store(true)
+			break dispatch
+		}
+
+		// Cancellation is picked up between items; in-flight items are still recorded
+		if status, err := w.currentStatus(ctx, run); err != nil {
+			w.logger.Warn("Failed to check experiment status", "experiment_id", run.experimentID, "error", err)
+		} else if status != evaluation.ExperimentStatusRunning {
+			<-sem
+			stoppedBy = string(status)
+			break
+		}
+
+		wg.Add(1)
+		go func(item *evaluation.DatasetItem) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if !w.runItem(ctx, run, item) {
+				interrupted.Store(true)
+			}
+		}(item)
+	}
+	wg.Wait()
+
+	if interrupted.Load() {
+		w.logger.Info("Experiment run interrupted, it will resume on restart", "experiment_id", run.experimentID)
+		return true, nil
+	}
+
+	atomic.AddInt64(&w.runsProcessed, 1)
+	if stoppedBy != "" {
+		w.logger.Info("Experiment run stopped", "experiment_id", run.experimentID, "status", stoppedBy)
+		return false, nil
+	}
+
+	if progress, err := w.experimentService.GetProgress(ctx, run.experimentID, run.projectID); err == nil {
+		w.logger.Info("Experiment run finished",
+			"experiment_id", run.experimentID,
+			"status", progress.Status,
+			"completed_items", progress.CompletedItems,
+			"failed_items", progress.FailedItems,
+		)
+	}
+	return false, nil
+}
+
+// prepareRun resolves the prompt, model configuration and dataset items of an experiment
+func (w *ExperimentRunWorker) prepareRun(ctx context.Context, experiment *evaluation.Experiment) (*experimentRun, error) {
+	config, err := w.wizardService.GetExperimentConfig(ctx, experiment.ID, experiment.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get experiment config: %w", err)
+	}
+
+	promptResp, err := w.loadPrompt(ctx, experiment.ProjectID, config)
+	if err != nil {
+		return nil, err
+	}
+
+	modelConfig, err := w.resolveModelConfig(ctx, experiment.ProjectID, promptResp.Config, config.ModelConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := w.loadDatasetItems(ctx, experiment.ProjectID, config)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, errors.New("dataset has no items")
+	}
+
+	if err := w.experimentService.SetTotalItems(ctx, experiment.ID, experiment.ProjectID, len(items)); err != nil {
+		return nil, fmt.Errorf("failed to set total items: %w", err)
+	}
+
+	recorded, err := w.itemService.ListDatasetItemIDs(ctx, experiment.ID, experiment.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recorded items: %w", err)
+	}
+	done := make(map[ulid.ULID]struct{}, len(recorded))
+	for _, id := range recorded {
+		done[id] = struct{}{}
+	}
+	remaining := make([]*evaluation.DatasetItem, 0, len(items))
+	for _, item := range items {
+		if _, ok := done[item.ID]; !ok {
+			remaining = append(remaining, item)
+		}
+	}
+
+	return &experimentRun{
+		experimentID: experiment.ID,
+		projectID:    experiment.ProjectID,
+		config:       config,
+		prompt:       promptResp,
+		modelConfig:  modelConfig,
+		items:        remaining,
+	}, nil
+}
+
+func (w *ExperimentRunWorker) loadPrompt(ctx context.Context, projectID ulid.ULID, config *evaluation.ExperimentConfig) (*prompt.PromptResponse, error) {
+	p, err := w.promptService.GetPromptByID(ctx, projectID, config.PromptID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prompt: %w", err)
+	}
+	version, err := w.promptService.GetVersionEntity(ctx, projectID, config.PromptID, config.PromptVersionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prompt version: %w", err)
+	}
+
+	var template interface{}
+	if err := json.Unmarshal(version.Template, &template); err != nil {
+		return nil, fmt.Errorf("invalid prompt template: %w", err)
+	}
+
+	return &prompt.PromptResponse{
+		ID:        p.ID.String(),
+		ProjectID: p.ProjectID.String(),
+		Name:      p.Name,
+		Type:      p.Type,
+		Version:   version.Version,
+		VersionID: version.ID.String(),
+		Template:  template,
+		Config:    version.Config,
+		Variables: []string(version.Variables),
+	}, nil
+}
+
+// resolveModelConfig overlays the wizard's model overrides on the prompt
+// version config and attaches the decrypted credential
+func (w *ExperimentRunWorker) resolveModelConfig(ctx context.Context, projectID ulid.ULID, base *prompt.ModelConfig, overrides map[string]any) (*prompt.ModelConfig, error) {
+	merged := map[string]any{}
+	if base != nil {
+		data, err := json.Marshal(base)
+		if err != nil {
+			return nil, fmt.Errorf("invalid prompt model config: %w", err)
+		}
+		if err := json.Unmarshal(data, &merged); err != nil {
+			return nil, fmt.Errorf("invalid prompt model config: %w", err)
+		}
+	}
+	for key, value := range overrides {
+		merged[key] = value
+	}
+
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return nil, fmt.Errorf("invalid model config: %w", err)
+	}
+	var modelConfig prompt.ModelConfig
+	if err := json.Unmarshal(data, &modelConfig); err != nil {
+		return nil, fmt.Errorf("invalid model config: %w", err)
+	}
+
+	if modelConfig.Provider == "" || modelConfig.Model == "" {
+		return nil, errors.New("model config must specify a provider and model")
+	}
+	if modelConfig.CredentialID == nil || *modelConfig.CredentialID == "" {
+		return nil, errors.New("model config must specify a credential_id")
+	}
+	credentialID, err := ulid.Parse(*modelConfig.CredentialID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid credential_id: %w", err)
+	}
+
+	project, err := w.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	keyConfig, err := w.credentialsService.GetExecutionConfig(ctx, project.OrganizationID, credentialID, credentials.Provider(modelConfig.Provider))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve credentials: %w", err)
+	}
+
+	modelConfig.APIKey = keyConfig.APIKey
+	if keyConfig.BaseURL != "" {
+		modelConfig.ResolvedBaseURL = &keyConfig.BaseURL
+	}
+	modelConfig.ProviderConfig = keyConfig.Config
+	modelConfig.CustomHeaders = keyConfig.Headers
+
+	return &modelConfig, nil
+}
+
+// loadDatasetItems returns the items of the pinned dataset version, or of the
+// live dataset when no version is pinned
+func (w *ExperimentRunWorker) loadDatasetItems(ctx context.Context, projectID ulid.ULID, config *evaluation.ExperimentConfig) ([]*evaluation.DatasetItem, error) {
+	var items []*evaluation.DatasetItem
+	for offset := 0; ; offset += experimentRunPageSize {
+		var page []*evaluation.DatasetItem
+		var total int64
+		var err error
+		if config.DatasetVersionID != nil {
+			page, total, err = w.datasetVersionService.GetVersionItems(ctx, *config.DatasetVersionID, config.DatasetID, projectID, experimentRunPageSize, offset)
+		} else {
+			page, total, err = w.datasetItemService.List(ctx, config.DatasetID, projectID, experimentRunPageSize, offset)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load dataset items: %w", err)
+		}
+
+		items = append(items, page...)
+		if len(page) < experimentRunPageSize || int64(len(items)) >= total {
+			return items, nil
+		}
+	}
+}
+
+func (w *ExperimentRunWorker) currentStatus(ctx context.Context, run *experimentRun) (evaluation.ExperimentStatus, error) {
+	progress, err := w.experimentService.GetProgress(ctx, run.experimentID, run.projectID)
+	if err != nil {
+		return "", err
+	}
+	return progress.Status, nil
+}
+
+// failExperiment marks an experiment that could not be started as failed
+func (w *ExperimentRunWorker) failExperiment(ctx context.Context, experiment *evaluation.Experiment, cause error) {
+	metadata := make(map[string]interface{}, len(experiment.Metadata)+1)
+	for key, value := range experiment.Metadata {
+		metadata[key] = value
+	}
+	metadata["run_error"] = cause.Error()
+
+	status := evaluation.ExperimentStatusFailed
+	if _, err := w.experimentService.Update(ctx, experiment.ID, experiment.ProjectID, &evaluation.UpdateExperimentRequest{
+		Status:   &status,
+		Metadata: metadata,
+	}); err != nil {
+		w.logger.Error("Failed to mark experiment as failed", "experiment_id", experiment.ID, "error", err)
+	}
+}
+
+// runItem generates, scores and records one dataset item. It returns false
+// when the worker stopped or the result could not be saved; the item is then
+// left unrecorded and picked up when the requeued run resumes.
+func (w *ExperimentRunWorker) runItem(ctx context.Context, run *experimentRun, item *evaluation.DatasetItem) bool {
+	variables := promptVariables(run.config.VariableMapping, item)
+
+	execResp, ok := w.executeWithRetry(ctx, run, variables)
+	if !ok {
+		return false
+	}
+
+	datasetItemID := item.ID.String()
+	req := evaluation.CreateExperimentItemRequest{
+		DatasetItemID: &datasetItemID,
+		Input:         item.Input,
+		Metadata: map[string]interface{}{
+			"latency_ms": execResp.LatencyMs,
+		},
+	}
+	if item.Expected != nil {
+		req.Expected = item.Expected
+	}
+
+	failed := execResp.Error != "" || execResp.Response == nil
+	if failed {
+		errMsg := execResp.Error
+		if errMsg == "" {
+			errMsg = "model returned no response"
+		}
+		req.Error = &errMsg
+	} else {
+		llmResp := execResp.Response
+		req.Output = llmResp.Content
+		req.Metadata["model"] = llmResp.Model
+		if llmResp.Usage != nil {
+			req.Metadata["prompt_tokens"] = llmResp.Usage.PromptTokens
+			req.Metadata["completion_tokens"] = llmResp.Usage.CompletionTokens
+			req.Metadata["total_tokens"] = llmResp.Usage.TotalTokens
+		}
+		if llmResp.Cost != nil {
+			req.Metadata["cost"] = *llmResp.Cost
+		}
+
+		scores, evaluatorErrors := w.runEvaluators(ctx, run, item, llmResp.Content)
+		req.Scores = scores
+		if len(evaluatorErrors) > 0 {
+			req.Metadata["evaluator_errors"] = evaluatorErrors
+		}
+	}
+
+	if _, err := w.itemService.CreateBatch(ctx, run.experimentID, run.projectID, &evaluation.CreateExperimentItemsBatchRequest{
+		Items: []evaluation.CreateExperimentItemRequest{req},
+	}); err != nil {
+		w.logger.Error("Failed to record experiment item",
+			"experiment_id", run.experimentID,
+			"dataset_item_id", item.ID,
+			"error", err,
+		)
+		atomic.AddInt64(&w.errorsCount, 1)
+		return false
+	}
+
+	completed, failedCount := 1, 0
+	if failed {
+		completed, failedCount = 0, 1
+		atomic.AddInt64(&w.itemsFailed, 1)
+	} else {
+		atomic.AddInt64(&w.itemsCompleted, 1)
+	}
+
+	if _, err := w.experimentService.IncrementAndCheckCompletion(ctx, run.experimentID, run.projectID, completed, failedCount); err != nil {
+		w.logger.Error("Failed to update experiment progress",
+			"experiment_id", run.experimentID,
+			"dataset_item_id", item.ID,
+			"error", err,
+		)
+		atomic.AddInt64(&w.errorsCount, 1)
+	}
+
+	return true
+}
+
+// executeWithRetry calls the model, backing off while the provider answers
+// 429. It returns false when the worker stopped during a backoff.
+func (w *ExperimentRunWorker) executeWithRetry(ctx context.Context, run *experimentRun, variables map[string]string) (*prompt.ExecutePromptResponse, bool) {
+	for attempt := 0; ; attempt++ {
+		resp, err := w.executionService.Execute(ctx, run.prompt, variables, run.modelConfig)
+		if err != nil {
+			resp = &prompt.ExecutePromptResponse{Error: err.Error()}
+		}
+		if !resp.RateLimited || attempt >= w.maxRetries {
+			return resp, true
+		}
+
+		atomic.AddInt64(&w.rateLimitRetries, 1)
+		delay := w.retryDelay(attempt)
+		w.logger.Debug("Model call rate limited, backing off",
+			"experiment_id", run.experimentID,
+			"attempt", attempt+1,
+			"delay", delay,
+		)
+
+		select {
+		case <-time.After(delay):
+		case <-w.quit:
+			return nil, false
+		case <-ctx.Done():
+			return nil, false
+		}
+	}
+}
+
+// retryDelay doubles the backoff per attempt up to the cap, with jitter so
+// concurrent items do not retry in lockstep
+func (w *ExperimentRunWorker) retryDelay(attempt int) time.Duration {
+	delay := w.retryBackoff << attempt
+	if delay <= 0 || (w.maxRetryBackoff > 0 && delay > w.maxRetryBackoff) {
+		delay = w.maxRetryBackoff
+	}
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int64N(int64(half)+1))
+}
+
+// runEvaluators scores an output with every configured evaluator. A failing
+// evaluator is recorded as a failed score and reported in the returned errors.
+func (w *ExperimentRunWorker) runEvaluators(ctx context.Context, run *experimentRun, item *evaluation.DatasetItem, output string) ([]evaluation.ExperimentItemScore, []string) {
+	var scores []evaluation.ExperimentItemScore
+	var evaluatorErrors []string
+
+	for _, evaluator := range run.config.Evaluators {
+		metadata := map[string]interface{}{
+			"evaluator":   evaluator.Name,
+			"scorer_type": string(evaluator.ScorerType),
+		}
+
+		outputs, err := w.runEvaluator(ctx, run, evaluator, item, output)
+		if err != nil {
+			scoringFailed := true
+			reason := err.Error()
+			scores = append(scores, evaluation.ExperimentItemScore{
+				Name:          evaluator.Name,
+				Reason:        &reason,
+				Metadata:      metadata,
+				ScoringFailed: &scoringFailed,
+			})
+			evaluatorErrors = append(evaluatorErrors, fmt.Sprintf("%s: %s", evaluator.Name, reason))
+			continue
+		}
+
+		for _, out := range outputs {
+			scores = append(scores, evaluation.ExperimentItemScore{
+				Name:        out.Name,
+				Value:       out.Value,
+				Type:        out.Type,
+				StringValue: out.StringValue,
+				Reason:      out.Reason,
+				Metadata:    metadata,
+			})
+		}
+	}
+
+	return scores, evaluatorErrors
+}
+
+func (w *ExperimentRunWorker) runEvaluator(ctx context.Context, run *experimentRun, evaluator evaluation.ExperimentEvaluator, item *evaluation.DatasetItem, output string) ([]ScoreOutput, error) {
+	var scorer Scorer
+	switch evaluator.ScorerType {
+	case evaluation.ScorerTypeLLM:
+		scorer = w.llmScorer
+	case evaluation.ScorerTypeBuiltin:
+		scorer = w.builtinScorer
+	case evaluation.ScorerTypeRegex:
+		scorer = w.regexScorer
+	}
+	if scorer == nil {
+		return nil, fmt.Errorf("scorer not configured for type: %s", evaluator.ScorerType)
+	}
+
+	job := &EvaluationJob{
+		JobID:     ulid.New(),
+		ProjectID: run.projectID,
+		SpanData: map[string]interface{}{
+			"input":    item.Input,
+			"output":   output,
+			"expected": item.Expected,
+			"metadata": item.Metadata,
+		},
+		ScorerType:   evaluator.ScorerType,
+		ScorerConfig: evaluator.ScorerConfig,
+		Variables:    evaluatorVariables(evaluator.VariableMapping, item, output),
+		CreatedAt:    time.Now(),
+	}
+
+	result, err := scorer.Execute(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, nil
+	}
+	if result.Error != nil {
+		return nil, errors.New(*result.Error)
+	}
+	return result.Scores, nil
+}
+
+// GetStats returns current worker statistics
+func (w *ExperimentRunWorker) GetStats() map[string]int64 {
+	return map[string]int64{
+		"runs_processed":     atomic.LoadInt64(&w.runsProcessed),
+		"items_completed":    atomic.LoadInt64(&w.itemsCompleted),
+		"items_failed":       atomic.LoadInt64(&w.itemsFailed),
+		"rate_limit_retries": atomic.LoadInt64(&w.rateLimitRetries),
+		"errors_count":       atomic.LoadInt64(&w.errorsCount),
+	}
+}
+
+// promptVariables resolves the prompt template variables of a dataset item
+// from the wizard's variable mapping. Unresolved variables are left out so
+// template compilation reports them.
+func promptVariables(mappings []evaluation.ExperimentVariableMapping, item *evaluation.DatasetItem) map[string]string {
+	variables := make(map[string]string, len(mappings))
+	for _, mapping := range mappings {
+		var source map[string]interface{}
+		switch mapping.Source {
+		case evaluation.VariableMappingSourceInput:
+			source = item.Input
+		case evaluation.VariableMappingSourceExpected:
+			source = item.Expected
+		case evaluation.VariableMappingSourceMetadata:
+			source = item.Metadata
+		}
+		if source == nil {
+			continue
+		}
+		if value := resolveFieldPath(source, mapping.FieldPath); value != nil {
+			variables[mapping.VariableName] = stringifyValue(value)
+		}
+	}
+	return variables
+}
+
+// evaluatorVariables exposes input, output and expected to the scorers, plus
+// the evaluator's own variable mapping. Span sources of evaluator mappings are
+// read as their dataset counterparts: span_input is the item input and
+// span_output the model output.
+func evaluatorVariables(mappings []evaluation.VariableMap, item *evaluation.DatasetItem, output string) map[string]string {
+	variables := map[string]string{
+		"input":  stringifyDatasetField(item.Input),
+		"output": output,
+	}
+	if item.Expected != nil {
+		variables["expected"] = stringifyDatasetField(item.Expected)
+	}
+
+	for _, mapping := range mappings {
+		var source interface{}
+		switch mapping.Source {
+		case "span_input", "trace_input", string(evaluation.VariableMappingSourceInput):
+			source = item.Input
+		case "span_output", "output":
+			source = output
+			if mapping.JSONPath != "" {
+				// Structured outputs are addressed by path like any other field
+				var parsed interface{}
+				if err := json.Unmarshal([]byte(output), &parsed); err == nil {
+					source = parsed
+				}
+			}
+		case string(evaluation.VariableMappingSourceExpected), "expected":
+			if item.Expected != nil {
+				source = item.Expected
+			}
+		case "span_metadata", string(evaluation.VariableMappingSourceMetadata):
+			if item.Metadata != nil {
+				source = item.Metadata
+			}
+		}
+		if source == nil {
+			continue
+		}
+		if value := resolveFieldPath(source, mapping.JSONPath); value != nil {
+			variables[mapping.VariableName] = stringifyValue(value)
+		}
+	}
+
+	return variables
+}
+
+// resolveFieldPath reads a dot separated path with optional list indexes,
+// e.g. "messages[0].content", returning nil when the path does not exist
+func resolveFieldPath(value interface{}, path string) interface{} {
+	if path == "" {
+		return value
+	}
+
+	for _, segment := range strings.Split(path, ".") {
+		name, indexes := segment, ""
+		if i := strings.IndexByte(segment, '['); i >= 0 {
+			name, indexes = segment[:i], segment[i:]
+		}
+
+		if name != "" {
+			fields, ok := value.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			value = fields[name]
+		}
+
+		for indexes != "" {
+			end := strings.IndexByte(indexes, ']')
+			if indexes[0] != '[' || end < 0 {
+				return nil
+			}
+			index, err := strconv.Atoi(indexes[1:end])
+			list, ok := value.([]interface{})
+			if err != nil || !ok || index < 0 || index >= len(list) {
+				return nil
+			}
+			value = list[index]
+			indexes = indexes[end+1:]
+		}
+	}
+
+	return value
+}
+
+// stringifyDatasetField unwraps single string fields such as {"answer": "..."}
+// so scorers compare against the text rather than its JSON encoding
+func stringifyDatasetField(fields map[string]interface{}) string {
+	if len(fields) == 1 {
+		for _, value := range fields {
+			if s, ok := value.(string); ok {
+				return s
+			}
+		}
+	}
+	return stringifyValue(fields)
+}
+
+func stringifyValue(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
+// ExperimentRunMessageData is the internal struct for parsing experiment run messages
+type ExperimentRunMessageData struct {
+	ExperimentID ulid.ULID `json:"experiment_id"`
+	ProjectID    ulid.ULID `json:"project_id"`
+	CreatedAt    time.Time `json:"created_at"`
+}
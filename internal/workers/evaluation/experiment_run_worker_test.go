@@ -0,0 +1,431 @@
+package evaluation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"brokle/internal/core/domain/credentials"
+	"brokle/internal/core/domain/evaluation"
+	"brokle/internal/core/domain/organization"
+	"brokle/internal/core/domain/prompt"
+	"brokle/pkg/ulid"
+)
+
+type fakeRunExperimentService struct {
+	evaluation.ExperimentService
+	mu         sync.Mutex
+	experiment *evaluation.Experiment
+	updates    []*evaluation.UpdateExperimentRequest
+}
+
+func (s *fakeRunExperimentService) GetByID(ctx context.Context, id, projectID ulid.ULID) (*evaluation.Experiment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	experiment := *s.experiment
+	return &experiment, nil
+}
+
+func (s *fakeRunExperimentService) GetProgress(ctx context.Context, id, projectID ulid.ULID) (*evaluation.ExperimentProgressResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &evaluation.ExperimentProgressResponse{
+		Status:         s.experiment.Status,
+		TotalItems:     s.experiment.TotalItems,
+		CompletedItems: s.experiment.CompletedItems,
+		FailedItems:    s.experiment.FailedItems,
+	}, nil
+}
+
+func (s *fakeRunExperimentService) SetTotalItems(ctx context.Context, id, projectID ulid.ULID, total int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.experiment.TotalItems = total
+	return nil
+}
+
+func (s *fakeRunExperimentService) IncrementAndCheckCompletion(ctx context.Context, id, projectID ulid.ULID, completed, failed int) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.experiment.CompletedItems += completed
+	s.experiment.FailedItems += failed
+	done := s.experiment.CompletedItems+s.experiment.FailedItems >= s.experiment.TotalItems
+	if done && s.experiment.Status != evaluation.ExperimentStatusCancelled {
+		s.experiment.Status = evaluation.ExperimentStatusCompleted
+		if s.experiment.FailedItems > 0 {
+			s.experiment.Status = evaluation.ExperimentStatusPartial
+		}
+	}
+	return done, nil
+}
+
+func (s *fakeRunExperimentService) Update(ctx context.Context, id, projectID ulid.ULID, req *evaluation.UpdateExperimentRequest) (*evaluation.Experiment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.updates = append(s.updates, req)
+	if req.Status != nil {
+		s.experiment.Status = *req.Status
+	}
+	return s.experiment, nil
+}
+
+func (s *fakeRunExperimentService) setStatus(status evaluation.ExperimentStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.experiment.Status = status
+}
+
+type fakeRunWizardService struct {
+	evaluation.ExperimentWizardService
+	config *evaluation.ExperimentConfig
+}
+
+func (s *fakeRunWizardService) GetExperimentConfig(ctx context.Context, experimentID, projectID ulid.ULID) (*evaluation.ExperimentConfig, error) {
+	return s.config, nil
+}
+
+type fakeRunItemService struct {
+	evaluation.ExperimentItemService
+	mu        sync.Mutex
+	recorded  []ulid.ULID
+	items     []evaluation.CreateExperimentItemRequest
+	createErr error
+}
+
+func (s *fakeRunItemService) ListDatasetItemIDs(ctx context.Context, experimentID, projectID ulid.ULID) ([]ulid.ULID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]ulid.ULID(nil), s.recorded...), nil
+}
+
+func (s *fakeRunItemService) CreateBatch(ctx context.Context, experimentID, projectID ulid.ULID, req *evaluation.CreateExperimentItemsBatchRequest) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.createErr != nil {
+		return 0, s.createErr
+	}
+	for _, item := range req.Items {
+		s.items = append(s.items, item)
+		if item.DatasetItemID != nil {
+			s.recorded = append(s.recorded, ulid.MustParse(*item.DatasetItemID))
+		}
+	}
+	return len(req.Items), nil
+}
+
+type fakeRunDatasetItemService struct {
+	evaluation.DatasetItemService
+	items []*evaluation.DatasetItem
+}
+
+func (s *fakeRunDatasetItemService) List(ctx context.Context, datasetID, projectID ulid.ULID, limit, offset int) ([]*evaluation.DatasetItem, int64, error) {
+	if offset >= len(s.items) {
+		return nil, int64(len(s.items)), nil
+	}
+	return s.items[offset:min(offset+limit, len(s.items))], int64(len(s.items)), nil
+}
+
+type fakeRunPromptService struct {
+	prompt.PromptService
+	prompt  *prompt.Prompt
+	version *prompt.Version
+}
+
+func (s *fakeRunPromptService) GetPromptByID(ctx context.Context, projectID, promptID ulid.ULID) (*prompt.Prompt, error) {
+	return s.prompt, nil
+}
+
+func (s *fakeRunPromptService) GetVersionEntity(ctx context.Context, projectID, promptID, versionID ulid.ULID) (*prompt.Version, error) {
+	return s.version, nil
+}
+
+type fakeRunExecutionService struct {
+	prompt.ExecutionService
+	mu        sync.Mutex
+	calls     []map[string]string
+	configs   []*prompt.ModelConfig
+	onExecute func(call int, variables map[string]string) *prompt.ExecutePromptResponse
+}
+
+func (s *fakeRunExecutionService) Execute(ctx context.Context, p *prompt.PromptResponse, variables map[string]string, overrides *prompt.ModelConfig) (*prompt.ExecutePromptResponse, error) {
+	s.mu.Lock()
+	call := len(s.calls)
+	s.calls = append(s.calls, variables)
+	s.configs = append(s.configs, overrides)
+	s.mu.Unlock()
+	return s.onExecute(call, variables), nil
+}
+
+type fakeRunCredentialService struct {
+	credentials.ProviderCredentialService
+}
+
+func (s *fakeRunCredentialService) GetExecutionConfig(ctx context.Context, orgID, credentialID ulid.ULID, adapter credentials.Provider) (*credentials.DecryptedKeyConfig, error) {
+	return &credentials.DecryptedKeyConfig{Provider: adapter, APIKey: "sk-test"}, nil
+}
+
+type fakeRunProjectRepository struct {
+	organization.ProjectRepository
+}
+
+func (r *fakeRunProjectRepository) GetByID(ctx context.Context, id ulid.ULID) (*organization.Project, error) {
+	return &organization.Project{ID: id, OrganizationID: ulid.New()}, nil
+}
+
+type experimentRunFixture struct {
+	worker      *ExperimentRunWorker
+	experiments *fakeRunExperimentService
+	items       *fakeRunItemService
+	execution   *fakeRunExecutionService
+	dataset     []*evaluation.DatasetItem
+	message     *ExperimentRunMessageData
+}
+
+func newExperimentRunFixture(t *testing.T, itemCount int) *experimentRunFixture {
+	t.Helper()
+
+	projectID := ulid.New()
+	experiment := &evaluation.Experiment{ID: ulid.New(), ProjectID: projectID, Status: evaluation.ExperimentStatusRunning}
+
+	dataset := make([]*evaluation.DatasetItem, itemCount)
+	for i := range dataset {
+		dataset[i] = &evaluation.DatasetItem{
+			ID:       ulid.New(),
+			Input:    map[string]interface{}{"question": "capital of France?"},
+			Expected: map[string]interface{}{"answer": "Paris"},
+		}
+	}
+
+	credentialID := ulid.New().String()
+	template, err := json.Marshal(map[string]any{"content": "Answer: {{question}}"})
+	require.NoError(t, err)
+
+	f := &experimentRunFixture{
+		experiments: &fakeRunExperimentService{experiment: experiment},
+		items:       &fakeRunItemService{},
+		execution: &fakeRunExecutionService{
+			onExecute: func(call int, variables map[string]string) *prompt.ExecutePromptResponse {
+				return &prompt.ExecutePromptResponse{
+					LatencyMs: 42,
+					Response: &prompt.LLMResponse{
+						Content: "The capital is Paris",
+						Model:   "gpt-4o-mini",
+						Usage:   &prompt.LLMUsage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+						Cost:    ptr(0.0001),
+					},
+				}
+			},
+		},
+		dataset: dataset,
+		message: &ExperimentRunMessageData{ExperimentID: experiment.ID, ProjectID: projectID},
+	}
+
+	config := &evaluation.ExperimentConfig{
+		PromptID:        ulid.New(),
+		PromptVersionID: ulid.New(),
+		ModelConfig:     map[string]any{"model": "gpt-4o-mini", "credential_id": credentialID},
+		DatasetID:       ulid.New(),
+		VariableMapping: []evaluation.ExperimentVariableMapping{
+			{VariableName: "question", Source: evaluation.VariableMappingSourceInput, FieldPath: "question"},
+		},
+		Evaluators: []evaluation.ExperimentEvaluator{
+			{
+				Name:         "mentions_answer",
+				ScorerType:   evaluation.ScorerTypeBuiltin,
+				ScorerConfig: map[string]any{"scorer_name": "contains", "config": map[string]any{"substring": "Paris"}},
+			},
+		},
+	}
+
+	f.worker = NewExperimentRunWorker(
+		nil,
+		f.experiments,
+		&fakeRunWizardService{config: config},
+		f.items,
+		&fakeRunDatasetItemService{items: dataset},
+		nil,
+		&fakeRunPromptService{
+			prompt: &prompt.Prompt{ID: config.PromptID, ProjectID: projectID, Name: "qa", Type: prompt.PromptTypeText},
+			version: &prompt.Version{
+				ID:       config.PromptVersionID,
+				PromptID: config.PromptID,
+				Version:  3,
+				Template: template,
+				Config:   &prompt.ModelConfig{Provider: "openai", Model: "gpt-4o", Temperature: ptr(0.2)},
+			},
+		},
+		f.execution,
+		&fakeRunCredentialService{},
+		&fakeRunProjectRepository{},
+		nil,
+		NewBuiltinScorer(newTestLogger()),
+		nil,
+		newTestLogger(),
+		&ExperimentRunWorkerConfig{
+			MaxConcurrentRuns: 1,
+			ItemConcurrency:   1,
+			MaxRetries:        3,
+			RetryBackoff:      time.Millisecond,
+			MaxRetryBackoff:   5 * time.Millisecond,
+		},
+	)
+	return f
+}
+
+func TestExperimentRunWorker_RunExperiment(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("records outputs, scores and progress", func(t *testing.T) {
+		f := newExperimentRunFixture(t, 3)
+
+		interrupted, err := f.worker.runExperiment(ctx, f.message)
+		require.NoError(t, err)
+		assert.False(t, interrupted)
+
+		assert.Equal(t, evaluation.ExperimentStatusCompleted, f.experiments.experiment.Status)
+		assert.Equal(t, 3, f.experiments.experiment.TotalItems)
+		assert.Equal(t, 3, f.experiments.experiment.CompletedItems)
+		require.Len(t, f.items.items, 3)
+
+		item := f.items.items[0]
+		assert.Equal(t, "The capital is Paris", item.Output)
+		assert.Nil(t, item.Error)
+		assert.Equal(t, int64(42), item.Metadata["latency_ms"])
+		assert.Equal(t, 0.0001, item.Metadata["cost"])
+		assert.Equal(t, 15, item.Metadata["total_tokens"])
+		require.Len(t, item.Scores, 1)
+		assert.Equal(t, 1.0, *item.Scores[0].Value)
+		assert.Equal(t, "mentions_answer", item.Scores[0].Metadata["evaluator"])
+
+		assert.Equal(t, map[string]string{"question": "capital of France?"}, f.execution.calls[0])
+		modelConfig := f.execution.configs[0]
+		assert.Equal(t, "openai", modelConfig.Provider)
+		assert.Equal(t, "gpt-4o-mini", modelConfig.Model)
+		assert.Equal(t, 0.2, *modelConfig.Temperature)
+		assert.Equal(t, "sk-test", modelConfig.APIKey)
+	})
+
+	t.Run("retries rate limited calls", func(t *testing.T) {
+		f := newExperimentRunFixture(t, 1)
+		success := f.execution.onExecute
+		f.execution.onExecute = func(call int, variables map[string]string) *prompt.ExecutePromptResponse {
+			if call < 2 {
+				return &prompt.ExecutePromptResponse{Error: "LLM provider rate limit exceeded", RateLimited: true}
+			}
+			return success(call, variables)
+		}
+
+		_, err := f.worker.runExperiment(ctx, f.message)
+		require.NoError(t, err)
+
+		assert.Len(t, f.execution.calls, 3)
+		assert.Equal(t, int64(2), f.worker.GetStats()["rate_limit_retries"])
+		assert.Equal(t, evaluation.ExperimentStatusCompleted, f.experiments.experiment.Status)
+	})
+
+	t.Run("reports failed items once retries are exhausted", func(t *testing.T) {
+		f := newExperimentRunFixture(t, 2)
+		f.execution.onExecute = func(call int, variables map[string]string) *prompt.ExecutePromptResponse {
+			return &prompt.ExecutePromptResponse{Error: "LLM provider rate limit exceeded", RateLimited: true}
+		}
+
+		_, err := f.worker.runExperiment(ctx, f.message)
+		require.NoError(t, err)
+
+		assert.Len(t, f.execution.calls, 8)
+		assert.Equal(t, evaluation.ExperimentStatusPartial, f.experiments.experiment.Status)
+		assert.Equal(t, 2, f.experiments.experiment.FailedItems)
+		require.Len(t, f.items.items, 2)
+		assert.Equal(t, "LLM provider rate limit exceeded", *f.items.items[0].Error)
+	})
+
+	t.Run("stops when the experiment is cancelled", func(t *testing.T) {
+		f := newExperimentRunFixture(t, 3)
+		success := f.execution.onExecute
+		f.execution.onExecute = func(call int, variables map[string]string) *prompt.ExecutePromptResponse {
+			f.experiments.setStatus(evaluation.ExperimentStatusCancelled)
+			return success(call, variables)
+		}
+
+		interrupted, err := f.worker.runExperiment(ctx, f.message)
+		require.NoError(t, err)
+		assert.False(t, interrupted)
+
+		assert.Len(t, f.items.items, 1)
+		assert.Equal(t, evaluation.ExperimentStatusCancelled, f.experiments.experiment.Status)
+	})
+
+	t.Run("resume skips recorded items", func(t *testing.T) {
+		f := newExperimentRunFixture(t, 3)
+		f.items.recorded = []ulid.ULID{f.dataset[0].ID, f.dataset[1].ID}
+		f.experiments.experiment.CompletedItems = 2
+
+		_, err := f.worker.runExperiment(ctx, f.message)
+		require.NoError(t, err)
+
+		require.Len(t, f.items.items, 1)
+		assert.Equal(t, f.dataset[2].ID.String(), *f.items.items[0].DatasetItemID)
+		assert.Equal(t, evaluation.ExperimentStatusCompleted, f.experiments.experiment.Status)
+	})
+
+	t.Run("leaves items that could not be recorded for the resume", func(t *testing.T) {
+		f := newExperimentRunFixture(t, 2)
+		f.items.createErr = errors.New("connection refused")
+
+		interrupted, err := f.worker.runExperiment(ctx, f.message)
+		require.NoError(t, err)
+		assert.True(t, interrupted)
+
+		assert.Zero(t, f.experiments.experiment.CompletedItems)
+		assert.Zero(t, f.experiments.experiment.FailedItems)
+		assert.Equal(t, evaluation.ExperimentStatusRunning, f.experiments.experiment.Status)
+
+		f.items.createErr = nil
+		_, err = f.worker.runExperiment(ctx, f.message)
+		require.NoError(t, err)
+		assert.Equal(t, 2, f.experiments.experiment.CompletedItems)
+		assert.Equal(t, evaluation.ExperimentStatusCompleted, f.experiments.experiment.Status)
+	})
+
+	t.Run("marks the experiment failed when it cannot start", func(t *testing.T) {
+		f := newExperimentRunFixture(t, 0)
+
+		_, err := f.worker.runExperiment(ctx, f.message)
+		require.Error(t, err)
+
+		assert.Equal(t, evaluation.ExperimentStatusFailed, f.experiments.experiment.Status)
+		require.Len(t, f.experiments.updates, 1)
+		assert.Equal(t, "dataset has no items", f.experiments.updates[0].Metadata["run_error"])
+		assert.Empty(t, f.execution.calls)
+	})
+
+	t.Run("ignores experiments that are not running", func(t *testing.T) {
+		f := newExperimentRunFixture(t, 1)
+		f.experiments.experiment.Status = evaluation.ExperimentStatusCancelled
+
+		_, err := f.worker.runExperiment(ctx, f.message)
+		require.NoError(t, err)
+		assert.Empty(t, f.execution.calls)
+	})
+}
+
+func TestResolveFieldPath(t *testing.T) {
+	value := map[string]interface{}{
+		"messages": []interface{}{
+			map[string]interface{}{"role": "user", "content": "hi"},
+		},
+		"meta": map[string]interface{}{"lang": "en"},
+	}
+
+	assert.Equal(t, "hi", resolveFieldPath(value, "messages[0].content"))
+	assert.Equal(t, "en", resolveFieldPath(value, "meta.lang"))
+	assert.Equal(t, value, resolveFieldPath(value, ""))
+	assert.Nil(t, resolveFieldPath(value, "messages[3].content"))
+	assert.Nil(t, resolveFieldPath(value, "meta.lang.code"))
+}
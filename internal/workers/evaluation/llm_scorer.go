@@ -44,50 +44,9 @@ func (s *LLMScorer) Execute(ctx context.Context, job *EvaluationJob) (*ScorerRes
 		return nil, fmt.Errorf("invalid LLM scorer config: %w", err)
 	}
 
-	// Resolve credentials
-	credentialID, err := ulid.Parse(config.CredentialID)
-	if err != nil {
-		return nil, fmt.Errorf("invalid credential_id: %w", err)
-	}
-
-	keyConfig, err := s.credentialsService.GetDecryptedByID(ctx, credentialID, job.ProjectID)
+	execResp, err := s.complete(ctx, job.ProjectID, config, job.Variables)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get credentials: %w", err)
-	}
-
-	// Build the compiled prompt from messages
-	compiledPrompt := s.buildPrompt(config.Messages, job.Variables)
-
-	// Prepare prompt for execution
-	promptResp := &prompt.PromptResponse{
-		Type:      "chat",
-		Template:  compiledPrompt,
-		Variables: []string{}, // Variables already substituted
-	}
-
-	// Build model config
-	modelConfig := &prompt.ModelConfig{
-		Provider:     string(keyConfig.Provider),
-		Model:        config.Model,
-		Temperature:  &config.Temperature,
-		APIKey:       keyConfig.APIKey,
-		CredentialID: &config.CredentialID,
-	}
-
-	if keyConfig.BaseURL != "" {
-		modelConfig.ResolvedBaseURL = &keyConfig.BaseURL
-	}
-	if keyConfig.Config != nil {
-		modelConfig.ProviderConfig = keyConfig.Config
-	}
-	if keyConfig.Headers != nil {
-		modelConfig.CustomHeaders = keyConfig.Headers
-	}
-
-	// Execute the prompt
-	execResp, err := s.executionService.Execute(ctx, promptResp, map[string]string{}, modelConfig)
-	if err != nil {
-		return nil, fmt.Errorf("LLM execution failed: %w", err)
+		return nil, err
 	}
 
 	if execResp.Error != "" {
@@ -133,6 +92,57 @@ func (s *LLMScorer) Execute(ctx context.Context, job *EvaluationJob) (*ScorerRes
 	return &ScorerResult{Scores: scores}, nil
 }
 
+// complete resolves the credential, renders the judge messages with the
+// variables and runs them against the configured model
+func (s *LLMScorer) complete(ctx context.Context, projectID ulid.ULID, config *evaluation.LLMScorerConfig, variables map[string]string) (*prompt.ExecutePromptResponse, error) {
+	// Resolve credentials
+	credentialID, err := ulid.Parse(config.CredentialID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid credential_id: %w", err)
+	}
+
+	keyConfig, err := s.credentialsService.GetDecryptedByID(ctx, credentialID, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credentials: %w", err)
+	}
+
+	// Build the compiled prompt from messages
+	compiledPrompt := s.buildPrompt(config.Messages, variables)
+
+	// Prepare prompt for execution
+	promptResp := &prompt.PromptResponse{
+		Type:      "chat",
+		Template:  compiledPrompt,
+		Variables: []string{}, // Variables already substituted
+	}
+
+	// Build model config
+	modelConfig := &prompt.ModelConfig{
+		Provider:     string(keyConfig.Provider),
+		Model:        config.Model,
+		Temperature:  &config.Temperature,
+		APIKey:       keyConfig.APIKey,
+		CredentialID: &config.CredentialID,
+	}
+
+	if keyConfig.BaseURL != "" {
+		modelConfig.ResolvedBaseURL = &keyConfig.BaseURL
+	}
+	if keyConfig.Config != nil {
+		modelConfig.ProviderConfig = keyConfig.Config
+	}
+	if keyConfig.Headers != nil {
+		modelConfig.CustomHeaders = keyConfig.Headers
+	}
+
+	// Execute the prompt
+	execResp, err := s.executionService.Execute(ctx, promptResp, map[string]string{}, modelConfig)
+	if err != nil {
+		return nil, fmt.Errorf("LLM execution failed: %w", err)
+	}
+	return execResp, nil
+}
+
 func (s *LLMScorer) parseConfig(config map[string]any) (*evaluation.LLMScorerConfig, error) {
 	credentialID, ok := config["credential_id"].(string)
 	if !ok || credentialID == "" {
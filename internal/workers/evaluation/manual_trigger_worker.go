@@ -43,6 +43,7 @@ type ManualTriggerWorker struct {
 	redis            *database.RedisDB
 	traceService     observability.TraceService
 	executionService evaluation.EvaluatorExecutionService
+	itemService      evaluation.ExperimentItemService
 	logger           *slog.Logger
 
 	// Consumer configuration
@@ -70,6 +71,7 @@ func NewManualTriggerWorker(
 	redisDB *database.RedisDB,
 	traceService observability.TraceService,
 	executionService evaluation.EvaluatorExecutionService,
+	itemService evaluation.ExperimentItemService,
 	logger *slog.Logger,
 	config *ManualTriggerWorkerConfig,
 ) *ManualTriggerWorker {
@@ -89,6 +91,7 @@ func NewManualTriggerWorker(
 		redis:            redisDB,
 		traceService:     traceService,
 		executionService: executionService,
+		itemService:      itemService,
 		logger:           logger,
 		consumerGroup:    config.ConsumerGroup,
 		consumerID:       config.ConsumerID,
@@ -218,7 +221,13 @@ func (w *ManualTriggerWorker) processMessage(ctx context.Context, message redis.
 		"sample_limit", trigger.SampleLimit,
 	)
 
-	spansMatched, jobsEnqueued, enqueueErrors, err := w.processTrigger(ctx, &trigger)
+	var spansMatched, jobsEnqueued, enqueueErrors int
+	var err error
+	if trigger.ScorerType == evaluation.ScorerTypePairwise {
+		spansMatched, jobsEnqueued, enqueueErrors, err = w.processPairwiseTrigger(ctx, &trigger)
+	} else {
+		spansMatched, jobsEnqueued, enqueueErrors, err = w.processTrigger(ctx, &trigger)
+	}
 
 	if err != nil {
 		failErr := w.executionService.FailExecution(ctx, trigger.ExecutionID, trigger.ProjectID, err.Error())
@@ -333,13 +342,32 @@ func (w *ManualTriggerWorker) processTrigger(ctx context.Context, trigger *Manua
 	}
 
 	// Calculate final job count BEFORE enqueueing
-	jobCount := len(spans)
-	if jobCount == 0 {
+	if len(spans) == 0 {
 		// Sampling reduced to zero - this is valid, not an error
 		// Return early before UpdateSpansMatched to avoid setting a target of 0
 		return spansMatched, 0, 0, nil
 	}
 
+	jobs := make([]*EvaluationJob, 0, len(spans))
+	for _, span := range spans {
+		jobs = append(jobs, w.createEvaluationJob(trigger, span))
+	}
+
+	spansScored, errorCount, err = w.enqueueJobs(ctx, trigger, jobs)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	atomic.AddInt64(&w.spansProcessed, int64(spansMatched))
+
+	return spansMatched, spansScored, errorCount, nil
+}
+
+// enqueueJobs sets the execution target and emits the jobs. It returns the
+// jobs emitted and the enqueue failures, which are already counted as errors.
+func (w *ManualTriggerWorker) enqueueJobs(ctx context.Context, trigger *ManualTriggerMessageData, jobs []*EvaluationJob) (emitted, errorCount int, err error) {
+	jobCount := len(jobs)
+
 	// ════════════════════════════════════════════════════════════════════
 	// PHASE 2: SET TARGET - Update spans_matched BEFORE any jobs are enqueued
 	// ════════════════════════════════════════════════════════════════════
@@ -351,23 +379,22 @@ func (w *ManualTriggerWorker) processTrigger(ctx context.Context, trigger *Manua
 	); err != nil {
 		// Critical: If we can't set the target, don't enqueue jobs
 		// Otherwise we'll have the same race condition
-		return 0, 0, 0, fmt.Errorf("failed to set spans_matched before enqueueing: %w", err)
+		return 0, 0, fmt.Errorf("failed to set spans_matched before enqueueing: %w", err)
 	}
 
 	// ════════════════════════════════════════════════════════════════════
 	// PHASE 3: ENQUEUE - Now safe to emit jobs
 	// ════════════════════════════════════════════════════════════════════
-	for _, span := range spans {
-		job := w.createEvaluationJob(trigger, span)
+	for _, job := range jobs {
 		if err := w.emitJob(ctx, job); err != nil {
 			w.logger.Error("Failed to emit evaluation job",
-				"span_id", span.SpanID,
+				"span_id", job.SpanID,
 				"error", err,
 			)
 			errorCount++
 			continue
 		}
-		spansScored++
+		emitted++
 		atomic.AddInt64(&w.jobsEmitted, 1)
 	}
 
@@ -388,9 +415,7 @@ func (w *ManualTriggerWorker) processTrigger(ctx context.Context, trigger *Manua
 		}
 	}
 
-	atomic.AddInt64(&w.spansProcessed, int64(spansMatched))
-
-	return spansMatched, spansScored, errorCount, nil
+	return emitted, errorCount, nil
 }
 
 func (w *ManualTriggerWorker) buildSpanFilter(trigger *ManualTriggerMessageData) *observability.SpanFilter {
@@ -843,6 +868,10 @@ type ManualTriggerMessageData struct {
 	SpanIDs         []string                  `json:"span_ids,omitempty"`
 	SampleLimit     int                       `json:"sample_limit"`
 	CreatedAt       time.Time                 `json:"created_at"`
+
+	// Pairwise evaluators judge either every pair of these experiments or the span pairs
+	ExperimentIDs []string                      `json:"experiment_ids,omitempty"`
+	SpanPairs     []evaluation.PairwiseSpanPair `json:"span_pairs,omitempty"`
 }
 
 // Helper functions
@@ -0,0 +1,211 @@
+package evaluation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"brokle/internal/core/domain/evaluation"
+)
+
+// PairwiseTarget identifies the two outputs a pairwise job judges
+type PairwiseTarget struct {
+	A PairwiseSide `json:"a"`
+	B PairwiseSide `json:"b"`
+}
+
+// PairwiseSide is the experiment item or span a pairwise score is written to
+type PairwiseSide struct {
+	ExperimentID     string `json:"experiment_id,omitempty"`
+	ExperimentItemID string `json:"experiment_item_id,omitempty"`
+	TraceID          string `json:"trace_id,omitempty"`
+	SpanID           string `json:"span_id,omitempty"`
+}
+
+// pairwiseVerdict is one judge call, from the point of view of output A
+type pairwiseVerdict struct {
+	outcome evaluation.PairwiseOutcome
+	reason  string
+}
+
+// PairwiseScorer asks an LLM judge which of two outputs is better. The judge
+// messages reference {output_a} and {output_b} (plus {input} and {expected});
+// with position swapping the pair is judged in both orders and a verdict that
+// flips with the order is recorded as a tie. It reuses the LLM scorer's config,
+// message templating and credential resolution.
+type PairwiseScorer struct {
+	llm    *LLMScorer
+	logger *slog.Logger
+}
+
+// NewPairwiseScorer creates a new pairwise scorer
+func NewPairwiseScorer(llmScorer *LLMScorer, logger *slog.Logger) *PairwiseScorer {
+	return &PairwiseScorer{
+		llm:    llmScorer,
+		logger: logger,
+	}
+}
+
+func (s *PairwiseScorer) Type() evaluation.ScorerType {
+	return evaluation.ScorerTypePairwise
+}
+
+func (s *PairwiseScorer) Execute(ctx context.Context, job *EvaluationJob) (*ScorerResult, error) {
+	config, err := s.llm.parseConfig(job.ScorerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pairwise scorer config: %w", err)
+	}
+
+	scoreName := getString(job.ScorerConfig, "score_name")
+	if scoreName == "" {
+		scoreName = evaluation.DefaultPairwiseScoreName
+	}
+	swap := true
+	if v, ok := job.ScorerConfig["position_swap"].(bool); ok {
+		swap = v
+	}
+
+	first, errMsg, err := s.judge(ctx, job, config, job.Variables)
+	if err != nil {
+		return nil, err
+	}
+	if errMsg != nil {
+		return &ScorerResult{Scores: []ScoreOutput{}, Error: errMsg}, nil
+	}
+
+	outcome := first.outcome
+	reason := first.reason
+	if swap {
+		swapped := make(map[string]string, len(job.Variables))
+		for k, v := range job.Variables {
+			swapped[k] = v
+		}
+		swapped["output_a"], swapped["output_b"] = job.Variables["output_b"], job.Variables["output_a"]
+
+		second, errMsg, err := s.judge(ctx, job, config, swapped)
+		if err != nil {
+			return nil, err
+		}
+		if errMsg != nil {
+			return &ScorerResult{Scores: []ScoreOutput{}, Error: errMsg}, nil
+		}
+
+		outcome, reason = combinePairwiseVerdicts(*first, pairwiseVerdict{
+			outcome: second.outcome.Invert(),
+			reason:  second.reason,
+		})
+	}
+
+	value := string(outcome)
+	output := ScoreOutput{
+		Name:        scoreName,
+		StringValue: &value,
+		Type:        "CATEGORICAL",
+	}
+	if reason != "" {
+		output.Reason = &reason
+	}
+
+	s.logger.Debug("Pairwise scorer executed",
+		"job_id", job.JobID,
+		"model", config.Model,
+		"outcome", outcome,
+		"position_swap", swap,
+	)
+
+	return &ScorerResult{Scores: []ScoreOutput{output}}, nil
+}
+
+// judge runs the judge once. A provider error is returned as errMsg so the
+// job is not retried, matching the LLM scorer.
+func (s *PairwiseScorer) judge(ctx context.Context, job *EvaluationJob, config *evaluation.LLMScorerConfig, variables map[string]string) (*pairwiseVerdict, *string, error) {
+	execResp, err := s.llm.complete(ctx, job.ProjectID, config, variables)
+	if err != nil {
+		return nil, nil, err
+	}
+	if execResp.Error != "" {
+		errStr := execResp.Error
+		return nil, &errStr, nil
+	}
+	if execResp.Response == nil || execResp.Response.Content == "" {
+		errStr := "judge returned an empty response"
+		return nil, &errStr, nil
+	}
+
+	verdict, err := parsePairwiseVerdict(execResp.Response.Content)
+	if err != nil {
+		s.logger.Warn("Failed to parse pairwise judge response",
+			"error", err,
+			"job_id", job.JobID,
+			"response", execResp.Response.Content,
+		)
+		errStr := "Failed to parse judge response: " + err.Error()
+		return nil, &errStr, nil
+	}
+	return verdict, nil, nil
+}
+
+// combinePairwiseVerdicts merges the verdicts of both orders, already mapped
+// to the original A/B positions. Disagreement means the judge followed the
+// position rather than the content, so the pair is a tie.
+func combinePairwiseVerdicts(first, second pairwiseVerdict) (evaluation.PairwiseOutcome, string) {
+	reasons := make([]string, 0, 2)
+	for _, r := range []string{first.reason, second.reason} {
+		if r != "" {
+			reasons = append(reasons, r)
+		}
+	}
+	reason := strings.Join(reasons, " | ")
+
+	if first.outcome == second.outcome {
+		return first.outcome, reason
+	}
+	prefix := fmt.Sprintf("Inconsistent across positions (%s, then %s after swapping)", first.outcome, second.outcome)
+	if reason != "" {
+		prefix += ": " + reason
+	}
+	return evaluation.PairwiseOutcomeTie, prefix
+}
+
+// parsePairwiseVerdict reads {"winner": "A" | "B" | "tie", "reason": "..."}
+// from the judge, or a bare A/B/tie answer
+func parsePairwiseVerdict(content string) (*pairwiseVerdict, error) {
+	var parsed map[string]interface{}
+	if err := json.Unmarshal([]byte(content), &parsed); err != nil {
+		if err := json.Unmarshal([]byte(extractJSON(content)), &parsed); err != nil {
+			if outcome, ok := pairwiseOutcomeFromAnswer(content); ok {
+				return &pairwiseVerdict{outcome: outcome}, nil
+			}
+			return nil, fmt.Errorf("response is not valid JSON: %w", err)
+		}
+	}
+
+	for _, key := range []string{"winner", "preference", "choice", "verdict"} {
+		answer, ok := parsed[key].(string)
+		if !ok {
+			continue
+		}
+		outcome, ok := pairwiseOutcomeFromAnswer(answer)
+		if !ok {
+			return nil, fmt.Errorf("unrecognized %s %q, expected A, B or tie", key, answer)
+		}
+		reason, _ := parsed["reason"].(string)
+		return &pairwiseVerdict{outcome: outcome, reason: reason}, nil
+	}
+	return nil, fmt.Errorf("response has no winner field")
+}
+
+func pairwiseOutcomeFromAnswer(answer string) (evaluation.PairwiseOutcome, bool) {
+	normalized := strings.ToLower(strings.Trim(strings.TrimSpace(answer), `"'.`))
+	switch normalized {
+	case "a", "output_a", "output a", "response a", "1":
+		return evaluation.PairwiseOutcomeWin, true
+	case "b", "output_b", "output b", "response b", "2":
+		return evaluation.PairwiseOutcomeLose, true
+	case "tie", "draw", "equal", "none", "both":
+		return evaluation.PairwiseOutcomeTie, true
+	}
+	return "", false
+}
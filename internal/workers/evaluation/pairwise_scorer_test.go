@@ -0,0 +1,170 @@
+package evaluation
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"brokle/internal/core/domain/credentials"
+	"brokle/internal/core/domain/evaluation"
+	"brokle/internal/core/domain/prompt"
+	"brokle/pkg/ulid"
+)
+
+type fakeJudgeCredentialService struct {
+	credentials.ProviderCredentialService
+}
+
+func (s *fakeJudgeCredentialService) GetDecryptedByID(ctx context.Context, credentialID, orgID ulid.ULID) (*credentials.DecryptedKeyConfig, error) {
+	return &credentials.DecryptedKeyConfig{Provider: credentials.Provider("openai"), APIKey: "sk-test"}, nil
+}
+
+// fakeJudgeExecutionService answers from the compiled judge prompt
+type fakeJudgeExecutionService struct {
+	prompt.ExecutionService
+	prompts []string
+	answer  func(compiled string) string
+}
+
+func (s *fakeJudgeExecutionService) Execute(ctx context.Context, p *prompt.PromptResponse, variables map[string]string, overrides *prompt.ModelConfig) (*prompt.ExecutePromptResponse, error) {
+	compiled, _ := p.Template.(string)
+	s.prompts = append(s.prompts, compiled)
+	return &prompt.ExecutePromptResponse{Response: &prompt.LLMResponse{Content: s.answer(compiled)}}, nil
+}
+
+// prefersContent picks whichever position shows the marker
+func prefersContent(marker string) func(string) string {
+	return func(compiled string) string {
+		a := strings.Index(compiled, "A: ")
+		b := strings.Index(compiled, "B: ")
+		if strings.HasPrefix(compiled[a+3:], marker) {
+			return `{"winner": "A", "reason": "A is correct"}`
+		}
+		if strings.HasPrefix(compiled[b+3:], marker) {
+			return `{"winner": "B", "reason": "B is correct"}`
+		}
+		return `{"winner": "tie"}`
+	}
+}
+
+func newPairwiseTestScorer(execution *fakeJudgeExecutionService) *PairwiseScorer {
+	llm := NewLLMScorer(&fakeJudgeCredentialService{}, execution, newTestLogger())
+	return NewPairwiseScorer(llm, newTestLogger())
+}
+
+func newPairwiseTestJob(config map[string]any) *EvaluationJob {
+	scorerConfig := map[string]any{
+		"credential_id": ulid.New().String(),
+		"model":         "gpt-4o",
+		"messages": []interface{}{
+			map[string]interface{}{"role": "user", "content": "Question: {input}\nA: {output_a}\nB: {output_b}"},
+		},
+	}
+	for k, v := range config {
+		scorerConfig[k] = v
+	}
+	return &EvaluationJob{
+		JobID:        ulid.New(),
+		ProjectID:    ulid.New(),
+		ScorerType:   evaluation.ScorerTypePairwise,
+		ScorerConfig: scorerConfig,
+		Variables: map[string]string{
+			"input":    "2+2?",
+			"output_a": "4",
+			"output_b": "5",
+		},
+	}
+}
+
+func TestPairwiseScorer_ConsistentVerdict(t *testing.T) {
+	execution := &fakeJudgeExecutionService{answer: prefersContent("4")}
+	scorer := newPairwiseTestScorer(execution)
+
+	result, err := scorer.Execute(context.Background(), newPairwiseTestJob(nil))
+	require.NoError(t, err)
+	require.Nil(t, result.Error)
+	require.Len(t, result.Scores, 1)
+
+	score := result.Scores[0]
+	assert.Equal(t, evaluation.DefaultPairwiseScoreName, score.Name)
+	assert.Equal(t, "CATEGORICAL", score.Type)
+	require.NotNil(t, score.StringValue)
+	assert.Equal(t, string(evaluation.PairwiseOutcomeWin), *score.StringValue)
+
+	// Judged in both orders
+	require.Len(t, execution.prompts, 2)
+	assert.Contains(t, execution.prompts[0], "A: 4\nB: 5")
+	assert.Contains(t, execution.prompts[1], "A: 5\nB: 4")
+}
+
+func TestPairwiseScorer_PositionBiasIsTie(t *testing.T) {
+	execution := &fakeJudgeExecutionService{answer: func(string) string { return `{"winner": "A"}` }}
+	scorer := newPairwiseTestScorer(execution)
+
+	result, err := scorer.Execute(context.Background(), newPairwiseTestJob(map[string]any{"score_name": "helpfulness"}))
+	require.NoError(t, err)
+	require.Len(t, result.Scores, 1)
+
+	score := result.Scores[0]
+	assert.Equal(t, "helpfulness", score.Name)
+	assert.Equal(t, string(evaluation.PairwiseOutcomeTie), *score.StringValue)
+	require.NotNil(t, score.Reason)
+	assert.Contains(t, *score.Reason, "Inconsistent across positions")
+}
+
+func TestPairwiseScorer_WithoutPositionSwap(t *testing.T) {
+	execution := &fakeJudgeExecutionService{answer: func(string) string { return `{"winner": "B"}` }}
+	scorer := newPairwiseTestScorer(execution)
+
+	result, err := scorer.Execute(context.Background(), newPairwiseTestJob(map[string]any{"position_swap": false}))
+	require.NoError(t, err)
+	require.Len(t, result.Scores, 1)
+	assert.Equal(t, string(evaluation.PairwiseOutcomeLose), *result.Scores[0].StringValue)
+	assert.Len(t, execution.prompts, 1)
+}
+
+func TestPairwiseScorer_UnparseableVerdict(t *testing.T) {
+	execution := &fakeJudgeExecutionService{answer: func(string) string { return "I cannot decide." }}
+	scorer := newPairwiseTestScorer(execution)
+
+	result, err := scorer.Execute(context.Background(), newPairwiseTestJob(nil))
+	require.NoError(t, err)
+	assert.Empty(t, result.Scores)
+	require.NotNil(t, result.Error)
+	assert.Contains(t, *result.Error, "Failed to parse judge response")
+}
+
+func TestParsePairwiseVerdict(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    evaluation.PairwiseOutcome
+		reason  string
+		wantErr bool
+	}{
+		{"json winner a", `{"winner": "A", "reason": "more accurate"}`, evaluation.PairwiseOutcomeWin, "more accurate", false},
+		{"json winner b", `{"winner": "b"}`, evaluation.PairwiseOutcomeLose, "", false},
+		{"json tie", `{"preference": "tie"}`, evaluation.PairwiseOutcomeTie, "", false},
+		{"markdown block", "```json\n{\"winner\": \"Output B\"}\n```", evaluation.PairwiseOutcomeLose, "", false},
+		{"bare answer", "A.", evaluation.PairwiseOutcomeWin, "", false},
+		{"unknown winner", `{"winner": "C"}`, "", "", true},
+		{"no winner field", `{"score": 1}`, "", "", true},
+		{"prose", "Both are fine really", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict, err := parsePairwiseVerdict(tt.content)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, verdict.outcome)
+			assert.Equal(t, tt.reason, verdict.reason)
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package evaluation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"brokle/internal/core/domain/evaluation"
+	"brokle/internal/core/domain/observability"
+	"brokle/pkg/ulid"
+)
+
+const pairwiseItemPageSize = 1000
+
+// processPairwiseTrigger emits one pairwise job per pair of outputs: every
+// pair of experiments on each dataset item they share, or each span pair.
+func (w *ManualTriggerWorker) processPairwiseTrigger(ctx context.Context, trigger *ManualTriggerMessageData) (pairsMatched, jobsEnqueued, errorCount int, err error) {
+	var jobs []*EvaluationJob
+	if len(trigger.SpanPairs) > 0 {
+		jobs = w.buildSpanPairJobs(ctx, trigger)
+	} else {
+		jobs, err = w.buildExperimentPairJobs(ctx, trigger)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+	}
+
+	pairsMatched = len(jobs)
+	if pairsMatched == 0 {
+		w.logger.Info("No output pairs matched for pairwise trigger",
+			"execution_id", trigger.ExecutionID,
+			"evaluator_id", trigger.EvaluatorID,
+		)
+		return 0, 0, 0, nil
+	}
+	if trigger.SampleLimit > 0 && len(jobs) > trigger.SampleLimit {
+		jobs = jobs[:trigger.SampleLimit]
+	}
+
+	jobsEnqueued, errorCount, err = w.enqueueJobs(ctx, trigger, jobs)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	atomic.AddInt64(&w.spansProcessed, int64(pairsMatched))
+
+	return pairsMatched, jobsEnqueued, errorCount, nil
+}
+
+// buildExperimentPairJobs pairs the experiments' outputs for the dataset items
+// they share. Items without output or with an error are skipped, and only the
+// first trial of each item is judged.
+func (w *ManualTriggerWorker) buildExperimentPairJobs(ctx context.Context, trigger *ManualTriggerMessageData) ([]*EvaluationJob, error) {
+	if w.itemService == nil {
+		return nil, fmt.Errorf("experiment item service not configured")
+	}
+
+	itemsByExperiment := make([]map[ulid.ULID]*evaluation.ExperimentItem, len(trigger.ExperimentIDs))
+	for i, rawID := range trigger.ExperimentIDs {
+		experimentID, err := ulid.Parse(rawID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid experiment ID %s: %w", rawID, err)
+		}
+		items, err := w.listPairwiseItems(ctx, experimentID, trigger.ProjectID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list items of experiment %s: %w", rawID, err)
+		}
+		itemsByExperiment[i] = items
+	}
+
+	var jobs []*EvaluationJob
+	for i := 0; i < len(itemsByExperiment); i++ {
+		for j := i + 1; j < len(itemsByExperiment); j++ {
+			shared := make([]ulid.ULID, 0, len(itemsByExperiment[i]))
+			for datasetItemID := range itemsByExperiment[i] {
+				if _, ok := itemsByExperiment[j][datasetItemID]; ok {
+					shared = append(shared, datasetItemID)
+				}
+			}
+			sort.Slice(shared, func(a, b int) bool { return shared[a].String() < shared[b].String() })
+
+			for _, datasetItemID := range shared {
+				jobs = append(jobs, w.createExperimentPairJob(trigger,
+					itemsByExperiment[i][datasetItemID],
+					itemsByExperiment[j][datasetItemID],
+				))
+			}
+		}
+	}
+	return jobs, nil
+}
+
+// listPairwiseItems returns the judgeable items of an experiment keyed by dataset item
+func (w *ManualTriggerWorker) listPairwiseItems(ctx context.Context, experimentID, projectID ulid.ULID) (map[ulid.ULID]*evaluation.ExperimentItem, error) {
+	items := make(map[ulid.ULID]*evaluation.ExperimentItem)
+	for offset := 0; ; offset += pairwiseItemPageSize {
+		page, total, err := w.itemService.List(ctx, experimentID, projectID, pairwiseItemPageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range page {
+			if item.DatasetItemID == nil || item.Output == nil || item.Error != nil {
+				continue
+			}
+			if existing, ok := items[*item.DatasetItemID]; ok && existing.TrialNumber <= item.TrialNumber {
+				continue
+			}
+			items[*item.DatasetItemID] = item
+		}
+		if len(page) < pairwiseItemPageSize || int64(offset+len(page)) >= total {
+			return items, nil
+		}
+	}
+}
+
+func (w *ManualTriggerWorker) createExperimentPairJob(trigger *ManualTriggerMessageData, a, b *evaluation.ExperimentItem) *EvaluationJob {
+	variables := map[string]string{
+		"input":    stringifyDatasetField(a.Input),
+		"output_a": stringifyValue(a.Output),
+		"output_b": stringifyValue(b.Output),
+	}
+	if a.Expected != nil {
+		variables["expected"] = stringifyValue(a.Expected)
+	}
+
+	target := &PairwiseTarget{
+		A: experimentItemSide(a),
+		B: experimentItemSide(b),
+	}
+	return w.createPairwiseJob(trigger, target, variables)
+}
+
+func experimentItemSide(item *evaluation.ExperimentItem) PairwiseSide {
+	side := PairwiseSide{
+		ExperimentID:     item.ExperimentID.String(),
+		ExperimentItemID: item.ID.String(),
+	}
+	if item.TraceID != nil {
+		side.TraceID = *item.TraceID
+	}
+	return side
+}
+
+// buildSpanPairJobs fetches both spans of each pair. Pairs with a missing
+// span are skipped, like missing spans of a span ID trigger.
+func (w *ManualTriggerWorker) buildSpanPairJobs(ctx context.Context, trigger *ManualTriggerMessageData) []*EvaluationJob {
+	projectID := trigger.ProjectID.String()
+	jobs := make([]*EvaluationJob, 0, len(trigger.SpanPairs))
+
+	for _, pair := range trigger.SpanPairs {
+		spanA, err := w.traceService.GetSpanByProject(ctx, pair.SpanA, projectID)
+		if err != nil || spanA == nil {
+			w.logger.Warn("Failed to fetch span of pair", "span_id", pair.SpanA, "project_id", projectID, "error", err)
+			continue
+		}
+		spanB, err := w.traceService.GetSpanByProject(ctx, pair.SpanB, projectID)
+		if err != nil || spanB == nil {
+			w.logger.Warn("Failed to fetch span of pair", "span_id", pair.SpanB, "project_id", projectID, "error", err)
+			continue
+		}
+
+		variables := extractVariablesFromSpan(trigger.VariableMapping, spanA)
+		if _, ok := variables["input"]; !ok && spanA.Input != nil {
+			variables["input"] = *spanA.Input
+		}
+		variables["output_a"] = pairwiseSpanOutput(trigger.VariableMapping, spanA)
+		variables["output_b"] = pairwiseSpanOutput(trigger.VariableMapping, spanB)
+
+		target := &PairwiseTarget{
+			A: PairwiseSide{TraceID: spanA.TraceID, SpanID: spanA.SpanID},
+			B: PairwiseSide{TraceID: spanB.TraceID, SpanID: spanB.SpanID},
+		}
+		jobs = append(jobs, w.createPairwiseJob(trigger, target, variables))
+	}
+	return jobs
+}
+
+// pairwiseSpanOutput is the span's mapped "output" variable, or its raw output
+func pairwiseSpanOutput(mapping []evaluation.VariableMap, span *observability.Span) string {
+	if output, ok := extractVariablesFromSpan(mapping, span)["output"]; ok {
+		return output
+	}
+	if span.Output != nil {
+		return *span.Output
+	}
+	return ""
+}
+
+func (w *ManualTriggerWorker) createPairwiseJob(trigger *ManualTriggerMessageData, target *PairwiseTarget, variables map[string]string) *EvaluationJob {
+	return &EvaluationJob{
+		JobID:        ulid.New(),
+		EvaluatorID:  trigger.EvaluatorID,
+		ProjectID:    trigger.ProjectID,
+		ExecutionID:  &trigger.ExecutionID,
+		TraceID:      target.A.TraceID,
+		SpanID:       target.A.SpanID,
+		ScorerType:   trigger.ScorerType,
+		ScorerConfig: trigger.ScorerConfig,
+		Variables:    variables,
+		Pairwise:     target,
+		CreatedAt:    time.Now(),
+	}
+}
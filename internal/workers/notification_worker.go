@@ -1,31 +1,78 @@
 package workers
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/redis/go-redis/v9"
+
 	"brokle/internal/config"
+	"brokle/internal/core/domain/webhook"
+	webhookService "brokle/internal/core/services/webhook"
+	"brokle/internal/infrastructure/database"
+	"brokle/pkg/email"
+	"brokle/pkg/ulid"
+)
+
+const (
+	notificationStream        = "notifications:jobs"
+	notificationRetrySet      = "notifications:retry" // Sorted set scored by next attempt (unix ms)
+	notificationDLQStream     = "notifications:dlq"
+	notificationConsumerGroup = "notification-workers"
+	notificationDLQMaxLength  = 10000
+	notificationReclaimIdle   = 5 * time.Minute // Pending jobs of a crashed consumer are reclaimed after this
+	notificationMaxBackoff    = time.Hour
+	slackSectionMaxLength     = 3000     // Slack rejects section text longer than this
+	notificationDrainLimit    = 64 << 10 // Response bytes read to reuse the connection
 )
 
-// NotificationWorker handles async notification processing
+// Webhook signature headers. The signature is an HMAC-SHA256 over "<timestamp>.<body>"
+// so receivers can reject replays by checking the timestamp.
+const (
+	WebhookSignatureHeader = webhook.SignatureHeader
+	WebhookTimestampHeader = webhook.TimestampHeader
+	WebhookEventHeader     = webhook.EventHeader
+	WebhookDeliveryHeader  = webhook.DeliveryHeader
+)
+
+// NotificationWorker delivers notification jobs persisted in a Redis stream.
+// Failed jobs are retried with exponential backoff and dead-lettered after MaxRetries.
 type NotificationWorker struct {
-	config *config.Config
-	logger *slog.Logger
-	queue  chan NotificationJob
-	quit   chan struct{}
-	wg     sync.WaitGroup
+	config      *config.Config
+	logger      *slog.Logger
+	redis       *database.RedisDB
+	emailSender email.EmailSender
+	httpClient  *http.Client
+	consumerID  string
+	ctx         context.Context
+	cancel      context.CancelFunc
+	quit        chan struct{}
+	wg          sync.WaitGroup
+
+	// Metrics
+	delivered    int64
+	retried      int64
+	deadLettered int64
 }
 
 // NotificationJob represents a notification processing job
 type NotificationJob struct {
 	Timestamp time.Time   `json:"timestamp"`
 	Data      interface{} `json:"data"`
+	ID        string      `json:"id"`
 	Type      string      `json:"type"`
+	LastError string      `json:"last_error,omitempty"`
 	Retry     int         `json:"retry"`
 }
 
@@ -49,13 +96,15 @@ type WebhookJob struct {
 	Payload    map[string]interface{} `json:"payload"`
 	URL        string                 `json:"url"`
 	Method     string                 `json:"method"`
+	Secret     string                 `json:"secret,omitempty"` // HMAC key; unsigned when empty
 	UserID     string                 `json:"user_id,omitempty"`
 	EventType  string                 `json:"event_type,omitempty"`
 	Timeout    int                    `json:"timeout,omitempty"`
 	RetryCount int                    `json:"retry_count,omitempty"`
 }
 
-// SlackJob represents a Slack notification job
+// SlackJob represents a Slack notification job.
+// Channel is either an incoming webhook URL or a channel name posted through the configured webhook.
 type SlackJob struct {
 	Channel   string                   `json:"channel"`
 	Message   string                   `json:"message"`
@@ -84,22 +133,58 @@ type PushJob struct {
 	Badge        int                    `json:"badge,omitempty"`
 }
 
+// permanentError marks a delivery failure that retrying cannot fix (bad URL, 4xx response,
+// unknown template). Such jobs go straight to the dead letter stream.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+func permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+func isPermanent(err error) bool {
+	var perm *permanentError
+	return errors.As(err, &perm)
+}
+
 // NewNotificationWorker creates a new notification worker
 func NewNotificationWorker(
 	config *config.Config,
 	logger *slog.Logger,
+	redisDB *database.RedisDB,
+	emailSender email.EmailSender,
 ) *NotificationWorker {
+	timeout := time.Duration(config.Notifications.WebhookTimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
 	return &NotificationWorker{
-		config: config,
-		logger: logger,
-		queue:  make(chan NotificationJob, 500), // Buffer for 500 notifications
-		quit:   make(chan struct{}),
+		config:      config,
+		logger:      logger,
+		redis:       redisDB,
+		emailSender: emailSender,
+		httpClient:  webhookService.NewDeliveryClient(timeout),
+		consumerID:  "notification-" + ulid.New().String(),
+		ctx:         ctx,
+		cancel:      cancel,
+		quit:        make(chan struct{}),
 	}
 }
 
 // Start starts the notification worker
 func (w *NotificationWorker) Start() {
-	w.logger.Info("Starting notification worker")
+	w.logger.Info("Starting notification worker", "consumer_id", w.consumerID)
+
+	err := w.redis.Client.XGroupCreateMkStream(w.ctx, notificationStream, notificationConsumerGroup, "0").Err()
+	if err != nil && !strings.Contains(err.Error(), "BUSYGROUP") {
+		w.logger.Error("Failed to create notification consumer group", "error", err)
+	}
 
 	// Start multiple worker goroutines
 	numWorkers := w.config.Workers.NotificationWorkers
@@ -111,30 +196,44 @@ func (w *NotificationWorker) Start() {
 		w.wg.Add(1)
 		go w.worker(i)
 	}
+
+	w.wg.Add(1)
+	go w.scheduler()
 }
 
-// Stop stops the notification worker and waits for graceful shutdown
+// Stop stops the notification worker and waits for graceful shutdown.
+// Jobs not yet acknowledged stay pending in the stream and are reclaimed on restart.
 func (w *NotificationWorker) Stop() {
 	w.logger.Info("Stopping notification worker")
 	close(w.quit)
+	w.cancel()
 	w.wg.Wait()
+
+	w.logger.Info("Notification worker stopped",
+		"delivered", atomic.LoadInt64(&w.delivered),
+		"retried", atomic.LoadInt64(&w.retried),
+		"dead_lettered", atomic.LoadInt64(&w.deadLettered),
+	)
 }
 
-// QueueJob queues a notification job for processing
+// QueueJob persists a notification job to the Redis stream
 func (w *NotificationWorker) QueueJob(jobType string, data interface{}) {
 	job := NotificationJob{
+		ID:        ulid.New().String(),
 		Type:      jobType,
 		Data:      data,
 		Timestamp: time.Now(),
 		Retry:     0,
 	}
 
-	select {
-	case w.queue <- job:
-		w.logger.Debug("Notification job queued", "type", jobType)
-	default:
-		w.logger.Warn("Notification queue full, dropping job", "type", jobType)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := w.enqueue(ctx, &job); err != nil {
+		w.logger.Error("Failed to queue notification job", "type", jobType, "error", err)
+		return
 	}
+	w.logger.Debug("Notification job queued", "type", jobType, "job_id", job.ID)
 }
 
 // QueueEmail queues an email notification
@@ -162,38 +261,264 @@ func (w *NotificationWorker) QueuePush(push PushJob) {
 	w.QueueJob("push", push)
 }
 
-// worker processes jobs from the queue
+func (w *NotificationWorker) enqueue(ctx context.Context, job *NotificationJob) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification job: %w", err)
+	}
+	return w.redis.Client.XAdd(ctx, &redis.XAddArgs{
+		Stream: notificationStream,
+		Values: map[string]interface{}{"data": string(data)},
+	}).Err()
+}
+
+// worker reads jobs from the stream through the consumer group
 func (w *NotificationWorker) worker(id int) {
 	defer w.wg.Done()
 	w.logger.Info("Notification worker started", "worker_id", id)
 
+	consumer := fmt.Sprintf("%s-%d", w.consumerID, id)
 	for {
 		select {
-		case job := <-w.queue:
-			w.processJob(id, job)
-
 		case <-w.quit:
 			w.logger.Info("Notification worker stopping", "worker_id", id)
 			return
+		default:
+		}
+
+		streams, err := w.redis.Client.XReadGroup(w.ctx, &redis.XReadGroupArgs{
+			Group:    notificationConsumerGroup,
+			Consumer: consumer,
+			Streams:  []string{notificationStream, ">"},
+			Count:    10,
+			Block:    time.Second,
+		}).Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) || errors.Is(err, context.Canceled) {
+				continue
+			}
+			w.logger.Error("Failed to read notification jobs", "worker_id", id, "error", err)
+			time.Sleep(time.Second)
+			continue
+		}
+
+		for _, stream := range streams {
+			for _, msg := range stream.Messages {
+				w.handleMessage(msg)
+			}
 		}
 	}
 }
 
-// processJob processes a single notification job
-func (w *NotificationWorker) processJob(workerID int, job NotificationJob) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+// scheduler moves due retries back onto the stream and reclaims jobs abandoned by crashed consumers
+func (w *NotificationWorker) scheduler() {
+	defer w.wg.Done()
+
+	retryTicker := time.NewTicker(time.Second)
+	reclaimTicker := time.NewTicker(time.Minute)
+	defer retryTicker.Stop()
+	defer reclaimTicker.Stop()
+
+	for {
+		select {
+		case <-retryTicker.C:
+			w.promoteDueRetries()
+		case <-reclaimTicker.C:
+			w.reclaimStaleJobs()
+		case <-w.quit:
+			return
+		}
+	}
+}
+
+func (w *NotificationWorker) promoteDueRetries() {
+	ctx, cancel := context.WithTimeout(w.ctx, 10*time.Second)
 	defer cancel()
 
-	logger := w.logger
+	due, err := w.redis.Client.ZRangeByScore(ctx, notificationRetrySet, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(time.Now().UnixMilli(), 10),
+		Count: 100,
+	}).Result()
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			w.logger.Error("Failed to read notification retries", "error", err)
+		}
+		return
+	}
 
-	logger.Debug("Processing notification job")
+	for _, member := range due {
+		// ZRem decides ownership when several workers promote concurrently
+		removed, err := w.redis.Client.ZRem(ctx, notificationRetrySet, member).Result()
+		if err != nil || removed == 0 {
+			continue
+		}
+		err = w.redis.Client.XAdd(ctx, &redis.XAddArgs{
+			Stream: notificationStream,
+			Values: map[string]interface{}{"data": member},
+		}).Err()
+		if err != nil {
+			w.logger.Error("Failed to requeue notification retry", "error", err)
+			w.redis.Client.ZAdd(ctx, notificationRetrySet, redis.Z{Score: float64(time.Now().UnixMilli()), Member: member})
+		}
+	}
+}
+
+func (w *NotificationWorker) reclaimStaleJobs() {
+	ctx, cancel := context.WithTimeout(w.ctx, 30*time.Second)
+	defer cancel()
+
+	messages, _, err := w.redis.Client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   notificationStream,
+		Group:    notificationConsumerGroup,
+		Consumer: w.consumerID + "-reclaim",
+		MinIdle:  notificationReclaimIdle,
+		Start:    "0",
+		Count:    50,
+	}).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) && !errors.Is(err, context.Canceled) {
+			w.logger.Error("Failed to reclaim notification jobs", "error", err)
+		}
+		return
+	}
+
+	if len(messages) > 0 {
+		w.logger.Info("Reclaimed stale notification jobs", "count", len(messages))
+	}
+	for _, msg := range messages {
+		w.handleMessage(msg)
+	}
+}
+
+// handleMessage processes one stream entry. The entry is acknowledged once the job is delivered,
+// scheduled for retry, or dead-lettered; otherwise it stays pending and is reclaimed later.
+func (w *NotificationWorker) handleMessage(msg redis.XMessage) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var job NotificationJob
+	dataStr, ok := msg.Values["data"].(string)
+	if !ok {
+		w.logger.Error("Invalid notification message: missing data field", "message_id", msg.ID)
+		w.ack(ctx, msg.ID)
+		return
+	}
+	if err := json.Unmarshal([]byte(dataStr), &job); err != nil {
+		w.logger.Error("Invalid notification message", "message_id", msg.ID, "error", err)
+		if w.deadLetter(ctx, dataStr, job.Type, err) == nil {
+			w.ack(ctx, msg.ID)
+		}
+		return
+	}
+
+	if err := w.processJob(&job); err != nil {
+		if !w.handleFailure(ctx, &job, err) {
+			return // Leave pending
+		}
+	}
+	w.ack(ctx, msg.ID)
+}
+
+// ack acknowledges and deletes a handled entry so the stream only holds pending jobs.
+// Retries are re-added as new entries and dead letters live in their own stream.
+func (w *NotificationWorker) ack(ctx context.Context, messageID string) {
+	if err := w.redis.Client.XAck(ctx, notificationStream, notificationConsumerGroup, messageID).Err(); err != nil {
+		w.logger.Error("Failed to ack notification job", "message_id", messageID, "error", err)
+		return
+	}
+	if err := w.redis.Client.XDel(ctx, notificationStream, messageID).Err(); err != nil {
+		w.logger.Error("Failed to delete notification job", "message_id", messageID, "error", err)
+	}
+}
+
+// handleFailure schedules a retry or dead-letters the job. It reports whether the job was handed off.
+func (w *NotificationWorker) handleFailure(ctx context.Context, job *NotificationJob, jobErr error) bool {
+	logger := w.logger.With("job_id", job.ID, "type", job.Type, "retry", job.Retry)
+	job.LastError = jobErr.Error()
+
+	maxRetries := w.config.Notifications.MaxRetries
+	if isPermanent(jobErr) || job.Retry >= maxRetries {
+		data, _ := json.Marshal(job)
+		if err := w.deadLetter(ctx, string(data), job.Type, jobErr); err != nil {
+			logger.Error("Failed to dead-letter notification job", "error", err)
+			return false
+		}
+		logger.Error("Notification job dead-lettered", "error", jobErr, "permanent", isPermanent(jobErr))
+		return true
+	}
+
+	job.Retry++
+	delay := notificationBackoff(w.retryBase(), job.Retry)
+	data, err := json.Marshal(job)
+	if err != nil {
+		logger.Error("Failed to marshal notification retry", "error", err)
+		return false
+	}
+	err = w.redis.Client.ZAdd(ctx, notificationRetrySet, redis.Z{
+		Score:  float64(time.Now().Add(delay).UnixMilli()),
+		Member: string(data),
+	}).Err()
+	if err != nil {
+		logger.Error("Failed to schedule notification retry", "error", err)
+		return false
+	}
+
+	atomic.AddInt64(&w.retried, 1)
+	logger.Warn("Notification delivery failed, retry scheduled", "error", jobErr, "delay", delay)
+	return true
+}
+
+func (w *NotificationWorker) deadLetter(ctx context.Context, data, jobType string, jobErr error) error {
+	err := w.redis.Client.XAdd(ctx, &redis.XAddArgs{
+		Stream: notificationDLQStream,
+		MaxLen: notificationDLQMaxLength,
+		Approx: true,
+		Values: map[string]interface{}{
+			"type":          jobType,
+			"error_message": jobErr.Error(),
+			"failed_at":     time.Now().Unix(),
+			"original_data": data,
+		},
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("failed to add notification to DLQ: %w", err)
+	}
+	atomic.AddInt64(&w.deadLettered, 1)
+	return nil
+}
+
+func (w *NotificationWorker) retryBase() time.Duration {
+	base := time.Duration(w.config.Notifications.RetryBackoffSeconds) * time.Second
+	if base <= 0 {
+		base = 30 * time.Second
+	}
+	return base
+}
+
+// notificationBackoff returns base * 2^(retry-1), capped at notificationMaxBackoff
+func notificationBackoff(base time.Duration, retry int) time.Duration {
+	delay := base
+	for i := 1; i < retry; i++ {
+		delay *= 2
+		if delay >= notificationMaxBackoff {
+			return notificationMaxBackoff
+		}
+	}
+	return delay
+}
+
+// processJob processes a single notification job
+func (w *NotificationWorker) processJob(job *NotificationJob) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
 
 	var err error
 	switch job.Type {
 	case "email":
 		err = w.processEmail(ctx, job.Data)
 	case "webhook":
-		err = w.processWebhook(ctx, job.Data)
+		err = w.processWebhook(ctx, job.ID, job.Data)
 	case "slack":
 		err = w.processSlack(ctx, job.Data)
 	case "sms":
@@ -201,134 +526,311 @@ func (w *NotificationWorker) processJob(workerID int, job NotificationJob) {
 	case "push":
 		err = w.processPush(ctx, job.Data)
 	default:
-		logger.Warn("Unknown notification job type")
-		return
+		err = permanent(fmt.Errorf("unknown notification job type: %s", job.Type))
+	}
+
+	if err == nil {
+		atomic.AddInt64(&w.delivered, 1)
+		w.logger.Debug("Notification job processed successfully", "job_id", job.ID, "type", job.Type)
+	}
+	return err
+}
+
+// decodeJobData converts queued job data, which arrives as a map after the stream round trip
+func decodeJobData[T any](data interface{}, kind string) (T, error) {
+	if typed, ok := data.(T); ok {
+		return typed, nil
 	}
 
+	var out T
+	mapData, ok := data.(map[string]interface{})
+	if !ok {
+		return out, permanent(fmt.Errorf("invalid %s data type", kind))
+	}
+	jsonData, err := json.Marshal(mapData)
 	if err != nil {
-		logger.Error("Failed to process notification job", "error", err)
-
-		// Retry logic with exponential backoff
-		if job.Retry < 3 {
-			job.Retry++
-			delay := time.Duration(job.Retry*job.Retry) * time.Minute
-			time.Sleep(delay)
-			w.queue <- job
-			logger.Info("Retrying notification job", "retry", job.Retry)
-		} else {
-			logger.Error("Max retries exceeded, dropping notification job")
-		}
-	} else {
-		logger.Debug("Notification job processed successfully")
+		return out, permanent(fmt.Errorf("failed to marshal %s data: %w", kind, err))
 	}
+	if err := json.Unmarshal(jsonData, &out); err != nil {
+		return out, permanent(fmt.Errorf("failed to unmarshal %s data: %w", kind, err))
+	}
+	return out, nil
 }
 
-// processEmail processes an email notification
+// processEmail sends an email through the configured provider
 func (w *NotificationWorker) processEmail(ctx context.Context, data interface{}) error {
-	jobData, ok := data.(EmailJob)
-	if !ok {
-		// Try to unmarshal if it's a map
-		if mapData, ok := data.(map[string]interface{}); ok {
-			jsonData, err := json.Marshal(mapData)
-			if err != nil {
-				return fmt.Errorf("failed to marshal email data: %w", err)
-			}
-			if err := json.Unmarshal(jsonData, &jobData); err != nil {
-				return fmt.Errorf("failed to unmarshal email data: %w", err)
-			}
-		} else {
-			return errors.New("invalid email data type")
-		}
+	jobData, err := decodeJobData[EmailJob](data, "email")
+	if err != nil {
+		return err
 	}
 
-	w.logger.Info("Sending email", "to", jobData.To, "subject", jobData.Subject, "template", jobData.Template)
+	if _, noop := w.emailSender.(*email.NoOpEmailSender); w.emailSender == nil || noop {
+		w.logger.Warn("Email provider not configured, dropping email", "subject", jobData.Subject, "template", jobData.Template)
+		return nil
+	}
 
-	// TODO: Implement actual email sending logic
-	// This would integrate with services like SendGrid, AWS SES, etc.
+	params, err := buildEmailParams(jobData, w.config.Server.AppURL)
+	if err != nil {
+		return permanent(err)
+	}
+	params.ReplyTo = w.config.External.Email.ReplyEmail
+	params.To = append(append([]string{}, jobData.To...), jobData.CC...)
 
-	// Simulate email sending
-	time.Sleep(100 * time.Millisecond)
+	if len(params.To) > 0 {
+		if err := w.emailSender.Send(ctx, params); err != nil {
+			return err
+		}
+	}
+	// BCC recipients get their own copy so they stay hidden from each other
+	for _, bcc := range jobData.BCC {
+		params.To = []string{bcc}
+		if err := w.emailSender.Send(ctx, params); err != nil {
+			return err
+		}
+	}
 
-	w.logger.Info("Email sent successfully", "to", jobData.To)
+	w.logger.Info("Email sent successfully", "to", jobData.To, "template", jobData.Template)
 	return nil
 }
 
-// processWebhook processes a webhook notification
-func (w *NotificationWorker) processWebhook(ctx context.Context, data interface{}) error {
-	jobData, ok := data.(WebhookJob)
-	if !ok {
-		// Try to unmarshal if it's a map
-		if mapData, ok := data.(map[string]interface{}); ok {
-			jsonData, err := json.Marshal(mapData)
-			if err != nil {
-				return fmt.Errorf("failed to marshal webhook data: %w", err)
-			}
-			if err := json.Unmarshal(jsonData, &jobData); err != nil {
-				return fmt.Errorf("failed to unmarshal webhook data: %w", err)
-			}
-		} else {
-			return errors.New("invalid webhook data type")
+// buildEmailParams renders the email body. Explicit bodies take precedence over templates.
+func buildEmailParams(job EmailJob, appURL string) (email.SendEmailParams, error) {
+	params := email.SendEmailParams{
+		Subject: job.Subject,
+		HTML:    job.BodyHTML,
+		Text:    job.Body,
+	}
+	if job.Template != "" {
+		params.Tags = map[string]string{"template": job.Template}
+	}
+	if params.HTML != "" {
+		return params, nil
+	}
+
+	var content email.NotificationEmailParams
+	if params.Text != "" {
+		content = email.NotificationEmailParams{Title: job.Subject, Paragraphs: strings.Split(params.Text, "\n")}
+		if link, ok := job.TemplateData["link"].(string); ok && link != "" {
+			content.ActionURL = link
+		}
+	} else {
+		var err error
+		content, err = notificationTemplateContent(job, appURL)
+		if err != nil {
+			return params, err
 		}
 	}
 
-	w.logger.Info("Sending webhook", "url", jobData.URL, "method", jobData.Method, "event_type", jobData.EventType)
+	html, text, err := email.BuildNotificationEmail(content)
+	if err != nil {
+		return params, err
+	}
+	params.HTML = html
+	if params.Text == "" {
+		params.Text = text
+	}
+	return params, nil
+}
+
+// notificationTemplateContent maps the named email templates to their content
+func notificationTemplateContent(job EmailJob, appURL string) (email.NotificationEmailParams, error) {
+	data := job.TemplateData
+	value := func(key string) string {
+		if v, ok := data[key]; ok && v != nil {
+			return fmt.Sprint(v)
+		}
+		return ""
+	}
+
+	switch job.Template {
+	case "welcome":
+		return email.NotificationEmailParams{
+			Title:      "Welcome to Brokle",
+			Paragraphs: []string{fmt.Sprintf("Hi %s, your account is ready.", value("name")), "Create a project and send your first traces to get started."},
+			ActionURL:  appURL,
+		}, nil
+	case "password_reset":
+		return email.NotificationEmailParams{
+			Title:      "Reset your password",
+			Paragraphs: []string{"Use this code to reset your Brokle password:", value("reset_token"), "If you didn't request a password reset, you can safely ignore this email."},
+		}, nil
+	case "billing_alert":
+		return email.NotificationEmailParams{
+			Title:      "Usage threshold exceeded",
+			Paragraphs: []string{fmt.Sprintf("Your current usage of %s has exceeded the alert threshold of %s.", value("amount"), value("threshold"))},
+			ActionURL:  appURL,
+		}, nil
+	case "usage_alert":
+		return email.NotificationEmailParams{
+			Title: fmt.Sprintf("%s usage alert", value("budget_name")),
+			Paragraphs: []string{
+				fmt.Sprintf("%s has used %s%% of its %s budget (%s).", value("organization_name"), value("percent_used"), value("dimension"), value("current_value")),
+				fmt.Sprintf("Severity: %s", value("severity")),
+			},
+			ActionURL: appURL,
+		}, nil
+	}
+	return email.NotificationEmailParams{}, fmt.Errorf("unknown email template %q with no body", job.Template)
+}
+
+// processWebhook delivers a signed JSON webhook
+func (w *NotificationWorker) processWebhook(ctx context.Context, deliveryID string, data interface{}) error {
+	jobData, err := decodeJobData[WebhookJob](data, "webhook")
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(jobData.Payload)
+	if err != nil {
+		return permanent(fmt.Errorf("failed to marshal webhook payload: %w", err))
+	}
+
+	method := jobData.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+	if jobData.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(jobData.Timeout)*time.Second)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, jobData.URL, bytes.NewReader(body))
+	if err != nil {
+		return permanent(fmt.Errorf("invalid webhook request: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Brokle-Webhooks/1.0")
+	req.Header.Set(WebhookDeliveryHeader, deliveryID)
+	if jobData.EventType != "" {
+		req.Header.Set(WebhookEventHeader, jobData.EventType)
+	}
+	for k, v := range jobData.Headers {
+		req.Header.Set(k, v)
+	}
 
-	// TODO: Implement actual webhook sending logic
-	// This would make HTTP requests to the specified URLs
+	if jobData.Secret != "" {
+		timestamp := time.Now().Unix()
+		req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+		req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(jobData.Secret, timestamp, body))
+	}
 
-	// Simulate webhook sending
-	time.Sleep(200 * time.Millisecond)
+	if err := w.do(req); err != nil {
+		return fmt.Errorf("webhook %s: %w", jobData.URL, err)
+	}
 
-	w.logger.Info("Webhook sent successfully", "url", jobData.URL)
+	w.logger.Info("Webhook sent successfully", "url", jobData.URL, "event_type", jobData.EventType)
 	return nil
 }
 
-// processSlack processes a Slack notification
-func (w *NotificationWorker) processSlack(ctx context.Context, data interface{}) error {
-	jobData, ok := data.(SlackJob)
-	if !ok {
-		// Try to unmarshal if it's a map
-		if mapData, ok := data.(map[string]interface{}); ok {
-			jsonData, err := json.Marshal(mapData)
-			if err != nil {
-				return fmt.Errorf("failed to marshal slack data: %w", err)
-			}
-			if err := json.Unmarshal(jsonData, &jobData); err != nil {
-				return fmt.Errorf("failed to unmarshal slack data: %w", err)
-			}
-		} else {
-			return errors.New("invalid slack data type")
+// SignWebhookPayload returns the signature header value for a webhook body.
+// Receivers recompute HMAC-SHA256(secret, "<timestamp>.<body>") and compare in constant time.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	return webhook.SignPayload(secret, timestamp, body)
+}
+
+// do executes an outbound request. Timeouts, 408, 429 and 5xx are retryable; internal
+// addresses, redirects and other 4xx responses are permanent. Response bodies are
+// discarded so receivers cannot feed content back into logs and the dead letter stream.
+func (w *NotificationWorker) do(req *http.Request) error {
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		if errors.Is(err, webhookService.ErrInternalAddress) {
+			return permanent(webhookService.ErrInternalAddress)
 		}
+		return err
 	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, notificationDrainLimit))
 
-	w.logger.Info("Sending Slack message", "channel", jobData.Channel, "event_type", jobData.EventType)
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	statusErr := fmt.Errorf("unexpected status %d", resp.StatusCode)
+	if resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return statusErr
+	}
+	return permanent(statusErr)
+}
+
+// processSlack posts a message to a Slack incoming webhook
+func (w *NotificationWorker) processSlack(ctx context.Context, data interface{}) error {
+	jobData, err := decodeJobData[SlackJob](data, "slack")
+	if err != nil {
+		return err
+	}
+
+	webhookURL := w.config.Notifications.SlackWebhookURL
+	if strings.HasPrefix(jobData.Channel, "https://") {
+		webhookURL = jobData.Channel
+	}
+	if webhookURL == "" {
+		return permanent(errors.New("slack webhook URL is not configured"))
+	}
+
+	body, err := json.Marshal(slackPayload(jobData))
+	if err != nil {
+		return permanent(fmt.Errorf("failed to marshal slack payload: %w", err))
+	}
 
-	// TODO: Implement actual Slack API integration
-	// This would use Slack's Web API to send messages
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return permanent(fmt.Errorf("invalid slack webhook: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/json")
 
-	// Simulate Slack sending
-	time.Sleep(150 * time.Millisecond)
+	if err := w.do(req); err != nil {
+		return fmt.Errorf("slack: %w", err)
+	}
 
-	w.logger.Info("Slack message sent successfully", "channel", jobData.Channel)
+	w.logger.Info("Slack message sent successfully", "channel", jobData.Channel, "event_type", jobData.EventType)
 	return nil
 }
 
+// slackPayload builds the incoming-webhook body. Text is kept as the notification fallback;
+// messages without explicit blocks get a mrkdwn section and an event context line.
+func slackPayload(job SlackJob) map[string]interface{} {
+	payload := map[string]interface{}{
+		"text": job.Message,
+	}
+	if job.Username != "" {
+		payload["username"] = job.Username
+	}
+	if job.IconEmoji != "" {
+		payload["icon_emoji"] = job.IconEmoji
+	}
+	if job.Channel != "" && !strings.HasPrefix(job.Channel, "https://") {
+		payload["channel"] = job.Channel
+	}
+
+	blocks := job.Blocks
+	if len(blocks) == 0 {
+		text := job.Message
+		if len(text) > slackSectionMaxLength {
+			text = text[:slackSectionMaxLength-3] + "..."
+		}
+		blocks = []map[string]interface{}{{
+			"type": "section",
+			"text": map[string]interface{}{"type": "mrkdwn", "text": text},
+		}}
+		if job.EventType != "" {
+			blocks = append(blocks, map[string]interface{}{
+				"type": "context",
+				"elements": []map[string]interface{}{
+					{"type": "mrkdwn", "text": fmt.Sprintf("Event: `%s`", job.EventType)},
+				},
+			})
+		}
+	}
+	payload["blocks"] = blocks
+	return payload
+}
+
 // processSMS processes an SMS notification
 func (w *NotificationWorker) processSMS(ctx context.Context, data interface{}) error {
-	jobData, ok := data.(SMSJob)
-	if !ok {
-		// Try to unmarshal if it's a map
-		if mapData, ok := data.(map[string]interface{}); ok {
-			jsonData, err := json.Marshal(mapData)
-			if err != nil {
-				return fmt.Errorf("failed to marshal sms data: %w", err)
-			}
-			if err := json.Unmarshal(jsonData, &jobData); err != nil {
-				return fmt.Errorf("failed to unmarshal sms data: %w", err)
-			}
-		} else {
-			return errors.New("invalid sms data type")
-		}
+	jobData, err := decodeJobData[SMSJob](data, "sms")
+	if err != nil {
+		return err
 	}
 
 	w.logger.Info("Sending SMS", "to", jobData.To)
@@ -345,20 +847,9 @@ func (w *NotificationWorker) processSMS(ctx context.Context, data interface{}) e
 
 // processPush processes a push notification
 func (w *NotificationWorker) processPush(ctx context.Context, data interface{}) error {
-	jobData, ok := data.(PushJob)
-	if !ok {
-		// Try to unmarshal if it's a map
-		if mapData, ok := data.(map[string]interface{}); ok {
-			jsonData, err := json.Marshal(mapData)
-			if err != nil {
-				return fmt.Errorf("failed to marshal push data: %w", err)
-			}
-			if err := json.Unmarshal(jsonData, &jobData); err != nil {
-				return fmt.Errorf("failed to unmarshal push data: %w", err)
-			}
-		} else {
-			return errors.New("invalid push data type")
-		}
+	jobData, err := decodeJobData[PushJob](data, "push")
+	if err != nil {
+		return err
 	}
 
 	w.logger.Info("Sending push notifications", "devices", len(jobData.DeviceTokens), "title", jobData.Title)
@@ -373,17 +864,26 @@ func (w *NotificationWorker) processPush(ctx context.Context, data interface{})
 	return nil
 }
 
-// GetQueueLength returns the current queue length
+// GetQueueLength returns the number of jobs in the stream
 func (w *NotificationWorker) GetQueueLength() int {
-	return len(w.queue)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	length, err := w.redis.Client.XLen(ctx, notificationStream).Result()
+	if err != nil {
+		return 0
+	}
+	return int(length)
 }
 
 // GetStats returns worker statistics
 func (w *NotificationWorker) GetStats() map[string]interface{} {
 	return map[string]interface{}{
-		"queue_length":   w.GetQueueLength(),
-		"queue_capacity": cap(w.queue),
-		"workers":        w.config.Workers.NotificationWorkers,
+		"queue_length":  w.GetQueueLength(),
+		"workers":       w.config.Workers.NotificationWorkers,
+		"delivered":     atomic.LoadInt64(&w.delivered),
+		"retried":       atomic.LoadInt64(&w.retried),
+		"dead_lettered": atomic.LoadInt64(&w.deadLettered),
 	}
 }
 
@@ -451,6 +951,7 @@ func (w *NotificationWorker) SendSystemAlert(message, severity string) {
 				"message":   message,
 				"timestamp": time.Now().Unix(),
 			},
+			Secret:    w.config.Notifications.WebhookSigningSecret,
 			EventType: "system_alert",
 		})
 	}
@@ -0,0 +1,276 @@
+package workers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"brokle/internal/config"
+)
+
+func newTestNotificationWorker(cfg *config.Config) *NotificationWorker {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	worker := NewNotificationWorker(cfg, logger, nil, nil)
+	worker.httpClient.Transport = http.DefaultTransport // Allow loopback receivers
+	return worker
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	sig := SignWebhookPayload("secret", 1700000000, []byte(`{"a":1}`))
+	assert.True(t, strings.HasPrefix(sig, "sha256="))
+	assert.Len(t, sig, len("sha256=")+64)
+
+	// Deterministic for the same input, sensitive to timestamp and secret
+	assert.Equal(t, sig, SignWebhookPayload("secret", 1700000000, []byte(`{"a":1}`)))
+	assert.NotEqual(t, sig, SignWebhookPayload("secret", 1700000001, []byte(`{"a":1}`)))
+	assert.NotEqual(t, sig, SignWebhookPayload("other", 1700000000, []byte(`{"a":1}`)))
+}
+
+func TestNotificationWorker_ProcessWebhook_Signed(t *testing.T) {
+	var gotHeaders http.Header
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeaders = r.Header.Clone()
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	worker := newTestNotificationWorker(&config.Config{})
+	err := worker.processWebhook(context.Background(), "delivery-1", WebhookJob{
+		URL:       server.URL,
+		Payload:   map[string]interface{}{"type": "alert.firing"},
+		Secret:    "whsec",
+		EventType: "alert.firing",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "application/json", gotHeaders.Get("Content-Type"))
+	assert.Equal(t, "alert.firing", gotHeaders.Get(WebhookEventHeader))
+	assert.Equal(t, "delivery-1", gotHeaders.Get(WebhookDeliveryHeader))
+
+	ts, err := strconv.ParseInt(gotHeaders.Get(WebhookTimestampHeader), 10, 64)
+	require.NoError(t, err)
+	assert.Equal(t, SignWebhookPayload("whsec", ts, gotBody), gotHeaders.Get(WebhookSignatureHeader))
+}
+
+func TestNotificationWorker_ProcessWebhook_NoConfiguredSecretFallback(t *testing.T) {
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature = r.Header.Get(WebhookSignatureHeader)
+	}))
+	defer server.Close()
+
+	// The platform secret is for system alerts only; jobs without their own secret go unsigned
+	cfg := &config.Config{Notifications: config.NotificationsConfig{WebhookSigningSecret: "global"}}
+	err := newTestNotificationWorker(cfg).processWebhook(context.Background(), "d", WebhookJob{URL: server.URL})
+	require.NoError(t, err)
+	assert.Empty(t, signature)
+
+	err = newTestNotificationWorker(cfg).processWebhook(context.Background(), "d", WebhookJob{URL: server.URL, Secret: "whsec"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, signature)
+}
+
+func TestNotificationWorker_ProcessWebhook_StatusClassification(t *testing.T) {
+	tests := []struct {
+		status        int
+		wantErr       bool
+		wantPermanent bool
+	}{
+		{http.StatusOK, false, false},
+		{http.StatusNoContent, false, false},
+		{http.StatusBadRequest, true, true},
+		{http.StatusNotFound, true, true},
+		{http.StatusRequestTimeout, true, false},
+		{http.StatusTooManyRequests, true, false},
+		{http.StatusInternalServerError, true, false},
+		{http.StatusServiceUnavailable, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			err := newTestNotificationWorker(&config.Config{}).processWebhook(context.Background(), "d", WebhookJob{URL: server.URL})
+			assert.Equal(t, tt.wantErr, err != nil)
+			if err != nil {
+				assert.Equal(t, tt.wantPermanent, isPermanent(err))
+			}
+		})
+	}
+}
+
+func TestNotificationWorker_ProcessWebhook_RefusesInternalAddresses(t *testing.T) {
+	var hits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+	}))
+	defer server.Close()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	worker := NewNotificationWorker(&config.Config{}, logger, nil, nil)
+	err := worker.processWebhook(context.Background(), "d", WebhookJob{URL: server.URL})
+	require.Error(t, err)
+	assert.True(t, isPermanent(err))
+	assert.Contains(t, err.Error(), "internal address")
+	assert.Zero(t, hits)
+}
+
+func TestNotificationWorker_ProcessWebhook_IgnoresResponse(t *testing.T) {
+	var followed bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/internal":
+			followed = true
+		case "/redirect":
+			http.Redirect(w, r, "/internal", http.StatusTemporaryRedirect)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("secret-token"))
+		}
+	}))
+	defer server.Close()
+
+	worker := newTestNotificationWorker(&config.Config{})
+	err := worker.processWebhook(context.Background(), "d", WebhookJob{URL: server.URL + "/redirect"})
+	require.Error(t, err)
+	assert.True(t, isPermanent(err))
+	assert.False(t, followed)
+
+	err = worker.processWebhook(context.Background(), "d", WebhookJob{URL: server.URL})
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret-token")
+}
+
+func TestNotificationWorker_ProcessWebhook_DecodesStreamData(t *testing.T) {
+	var called bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	// Jobs read back from the stream carry their data as a generic map
+	raw, _ := json.Marshal(NotificationJob{Type: "webhook", Data: WebhookJob{URL: server.URL, Method: "POST"}})
+	var job NotificationJob
+	require.NoError(t, json.Unmarshal(raw, &job))
+
+	require.NoError(t, newTestNotificationWorker(&config.Config{}).processJob(&job))
+	assert.True(t, called)
+
+	err := newTestNotificationWorker(&config.Config{}).processJob(&NotificationJob{Type: "fax"})
+	assert.True(t, isPermanent(err))
+}
+
+func TestNotificationWorker_ProcessSlack(t *testing.T) {
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{Notifications: config.NotificationsConfig{SlackWebhookURL: server.URL}}
+	err := newTestNotificationWorker(cfg).processSlack(context.Background(), SlackJob{
+		Channel:   "#alerts",
+		Message:   "*Firing* error rate",
+		Username:  "Brokle Alerts",
+		EventType: "alert.firing",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "*Firing* error rate", payload["text"])
+	assert.Equal(t, "#alerts", payload["channel"])
+	assert.Equal(t, "Brokle Alerts", payload["username"])
+	blocks := payload["blocks"].([]interface{})
+	require.Len(t, blocks, 2)
+	assert.Equal(t, "section", blocks[0].(map[string]interface{})["type"])
+	assert.Equal(t, "context", blocks[1].(map[string]interface{})["type"])
+
+	// Channel-name targets need a configured webhook
+	err = newTestNotificationWorker(&config.Config{}).processSlack(context.Background(), SlackJob{Channel: "#alerts", Message: "x"})
+	assert.True(t, isPermanent(err))
+}
+
+func TestSlackPayload_WebhookURLChannel(t *testing.T) {
+	payload := slackPayload(SlackJob{
+		Channel: "https://hooks.slack.com/services/T/B/X",
+		Message: strings.Repeat("a", slackSectionMaxLength+10),
+		Blocks:  nil,
+	})
+
+	_, hasChannel := payload["channel"]
+	assert.False(t, hasChannel, "webhook URL targets must not be sent as channel override")
+
+	blocks := payload["blocks"].([]map[string]interface{})
+	require.Len(t, blocks, 1)
+	text := blocks[0]["text"].(map[string]interface{})["text"].(string)
+	assert.Len(t, text, slackSectionMaxLength)
+}
+
+func TestNotificationBackoff(t *testing.T) {
+	base := 30 * time.Second
+	assert.Equal(t, 30*time.Second, notificationBackoff(base, 1))
+	assert.Equal(t, 60*time.Second, notificationBackoff(base, 2))
+	assert.Equal(t, 120*time.Second, notificationBackoff(base, 3))
+	assert.Equal(t, notificationMaxBackoff, notificationBackoff(base, 20))
+}
+
+func TestBuildEmailParams(t *testing.T) {
+	t.Run("explicit body", func(t *testing.T) {
+		params, err := buildEmailParams(EmailJob{
+			Subject:      "[Firing] Error rate",
+			Body:         "line one\nline two",
+			Template:     "alert",
+			TemplateData: map[string]interface{}{"link": "https://app.example.com/alerts/1"},
+		}, "")
+		require.NoError(t, err)
+		assert.Equal(t, "line one\nline two", params.Text)
+		assert.Contains(t, params.HTML, "line two")
+		assert.Contains(t, params.HTML, "https://app.example.com/alerts/1")
+		assert.Equal(t, "alert", params.Tags["template"])
+	})
+
+	t.Run("named template", func(t *testing.T) {
+		params, err := buildEmailParams(EmailJob{
+			Subject:  "Usage Alert",
+			Template: "usage_alert",
+			TemplateData: map[string]interface{}{
+				"organization_name": "Acme",
+				"budget_name":       "Monthly",
+				"dimension":         "spans",
+				"percent_used":      85,
+				"current_value":     "850K",
+				"severity":          "warning",
+			},
+		}, "https://app.example.com")
+		require.NoError(t, err)
+		assert.Contains(t, params.Text, "Acme has used 85% of its spans budget (850K).")
+		assert.Contains(t, params.HTML, "https://app.example.com")
+	})
+
+	t.Run("html is escaped", func(t *testing.T) {
+		params, err := buildEmailParams(EmailJob{Subject: "s", Body: "<script>x</script>"}, "")
+		require.NoError(t, err)
+		assert.NotContains(t, params.HTML, "<script>")
+	})
+
+	t.Run("unknown template without body", func(t *testing.T) {
+		_, err := buildEmailParams(EmailJob{Subject: "s", Template: "missing"}, "")
+		assert.Error(t, err)
+	})
+}
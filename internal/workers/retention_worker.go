@@ -0,0 +1,99 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"brokle/internal/config"
+	observabilitySvc "brokle/internal/core/services/observability"
+)
+
+// RetentionWorker periodically purges telemetry older than each project's retention policy
+type RetentionWorker struct {
+	config           *config.Config
+	logger           *slog.Logger
+	retentionService *observabilitySvc.RetentionService
+	quit             chan struct{}
+	wg               sync.WaitGroup
+	ticker           *time.Ticker
+}
+
+// NewRetentionWorker creates a new retention worker
+func NewRetentionWorker(
+	config *config.Config,
+	logger *slog.Logger,
+	retentionService *observabilitySvc.RetentionService,
+) *RetentionWorker {
+	return &RetentionWorker{
+		config:           config,
+		logger:           logger,
+		retentionService: retentionService,
+		quit:             make(chan struct{}),
+	}
+}
+
+// Start starts the retention worker
+func (w *RetentionWorker) Start() {
+	w.logger.Info("Starting retention worker", "dry_run", w.config.Retention.DryRun)
+
+	w.wg.Add(1)
+	go w.mainLoop()
+}
+
+// Stop stops the retention worker and waits for graceful shutdown
+func (w *RetentionWorker) Stop() {
+	w.logger.Info("Stopping retention worker")
+	close(w.quit)
+	w.wg.Wait()
+}
+
+// mainLoop runs immediately on start, then on the configured interval
+func (w *RetentionWorker) mainLoop() {
+	defer w.wg.Done()
+
+	w.run()
+
+	interval := time.Duration(w.config.Retention.IntervalMinutes) * time.Minute
+	if interval <= 0 {
+		interval = 6 * time.Hour
+	}
+
+	w.ticker = time.NewTicker(interval)
+	for {
+		select {
+		case <-w.ticker.C:
+			w.run()
+		case <-w.quit:
+			w.ticker.Stop()
+			w.logger.Info("Retention worker stopped")
+			return
+		}
+	}
+}
+
+// run executes a single enforcement pass
+func (w *RetentionWorker) run() {
+	// Partition drops are cheap, but DELETE mutations are only submitted here, not awaited
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	w.logger.Info("Starting retention enforcement")
+	startTime := time.Now()
+
+	summary, err := w.retentionService.Enforce(ctx, w.config.Retention.DryRun)
+	if err != nil {
+		w.logger.Error("retention enforcement failed", "error", err)
+		return
+	}
+
+	w.logger.Info("Retention enforcement completed",
+		"dry_run", summary.DryRun,
+		"projects_checked", summary.ProjectsChecked,
+		"rows_expired", summary.RowsExpired,
+		"partitions_dropped", summary.PartitionsDropped,
+		"delete_mutations", summary.DeleteMutations,
+		"duration_ms", time.Since(startTime).Milliseconds(),
+	)
+}
@@ -16,6 +16,7 @@ import (
 
 	"brokle/internal/config"
 	"brokle/internal/core/domain/observability"
+	"brokle/internal/core/domain/webhook"
 	observabilitySvc "brokle/internal/core/services/observability"
 	"brokle/internal/infrastructure/database"
 	"brokle/internal/infrastructure/streams"
@@ -42,6 +43,9 @@ type TelemetryStreamConsumer struct {
 	genaiEventsService  observability.GenAIEventsService
 	archiveService      *observabilitySvc.ArchiveService
 	archiveConfig       *config.ArchiveConfig
+	webhookPublisher    webhook.Publisher
+	samplingService     *observabilitySvc.SamplingService
+	samplingConfig      *config.SamplingConfig
 	redis               *database.RedisDB
 	logger              *slog.Logger
 	activeStreams       map[string]bool
@@ -61,6 +65,9 @@ type TelemetryStreamConsumer struct {
 	errorsCount         int64
 	dlqMessagesCount    int64
 	archiveErrorsCount  int64
+	tailBufferedSpans   int64
+	tailKeptSpans       int64
+	tailDroppedSpans    int64
 	batchSize           int
 	discoveryBackoff    time.Duration
 	streamRotation      int
@@ -93,6 +100,9 @@ func NewTelemetryStreamConsumer(
 	genaiEventsService observability.GenAIEventsService,
 	archiveService *observabilitySvc.ArchiveService,
 	archiveConfig *config.ArchiveConfig,
+	webhookPublisher webhook.Publisher,
+	samplingService *observabilitySvc.SamplingService,
+	samplingConfig *config.SamplingConfig,
 ) *TelemetryStreamConsumer {
 	if consumerConfig == nil {
 		consumerConfig = &TelemetryStreamConsumerConfig{
@@ -118,6 +128,9 @@ func NewTelemetryStreamConsumer(
 		genaiEventsService:  genaiEventsService,
 		archiveService:      archiveService,
 		archiveConfig:       archiveConfig,
+		webhookPublisher:    webhookPublisher,
+		samplingService:     samplingService,
+		samplingConfig:      samplingConfig,
 		consumerGroup:       consumerConfig.ConsumerGroup,
 		consumerID:          consumerConfig.ConsumerID,
 		batchSize:           consumerConfig.BatchSize,
@@ -149,6 +162,12 @@ func (c *TelemetryStreamConsumer) Start(ctx context.Context) error {
 	c.wg.Add(1)
 	go c.discoveryLoop(ctx)
 
+	// Start tail sampling decision loop
+	if c.isTailSamplingEnabled() {
+		c.wg.Add(1)
+		go c.tailSamplingLoop(ctx)
+	}
+
 	return nil
 }
 
@@ -575,6 +594,10 @@ func (c *TelemetryStreamConsumer) processBatch(ctx context.Context, batch *strea
 	// This ensures parent entities exist before children are created
 	sortedEvents := c.sortEventsByDependency(batch.Events)
 
+	// Tail sampling holds back spans of undecided traces until their decision wait elapses
+	sortedEvents, held := c.holdForTailSampling(ctx, batch, sortedEvents)
+	processedCount += held
+
 	// Group events by type for batch insertion
 	spans := make([]*observability.Span, 0, len(sortedEvents))
 	scores := make([]*observability.Score, 0)
@@ -693,6 +716,7 @@ func (c *TelemetryStreamConsumer) processBatch(ctx context.Context, batch *strea
 			lastError = err
 		} else {
 			processedCount += len(spans)
+			c.publishTraceCreated(ctx, projectID, spans)
 		}
 	}
 
@@ -896,6 +920,9 @@ func (c *TelemetryStreamConsumer) GetStats() map[string]int64 {
 		"errors_count":      atomic.LoadInt64(&c.errorsCount),
 		"dlq_messages":      atomic.LoadInt64(&c.dlqMessagesCount),
 		"archive_errors":    atomic.LoadInt64(&c.archiveErrorsCount),
+		"tail_buffered":     atomic.LoadInt64(&c.tailBufferedSpans),
+		"tail_kept":         atomic.LoadInt64(&c.tailKeptSpans),
+		"tail_dropped":      atomic.LoadInt64(&c.tailDroppedSpans),
 		"active_streams":    activeStreamCount,
 	}
 }
@@ -1030,6 +1057,7 @@ func (c *TelemetryStreamConsumer) extractRawRecords(batch *streams.TelemetryStre
 			SpanID:      spanID,
 			SpanJSONRaw: string(rawJSON),
 			ArchivedAt:  now,
+			EventType:   event.EventType,
 		})
 	}
 
@@ -1182,3 +1210,27 @@ func (c *TelemetryStreamConsumer) isArchiveEnabled() bool {
 		c.archiveConfig.Enabled &&
 		c.archiveService.IsEnabled()
 }
+
+// publishTraceCreated emits trace.created for each root span in the batch.
+// Ingestion is the only publisher: rehydrated archives are not new traces.
+func (c *TelemetryStreamConsumer) publishTraceCreated(ctx context.Context, projectID ulid.ULID, spans []*observability.Span) {
+	if c.webhookPublisher == nil {
+		return
+	}
+	for _, span := range spans {
+		if span.ParentSpanID != nil && *span.ParentSpanID != "" {
+			continue
+		}
+		c.webhookPublisher.Publish(ctx, projectID, webhook.EventTraceCreated, map[string]interface{}{
+			"trace_id":     span.TraceID,
+			"root_span_id": span.SpanID,
+			"name":         span.SpanName,
+			"service_name": span.ServiceName,
+			"start_time":   span.StartTime,
+			"end_time":     span.EndTime,
+			"duration":     span.Duration,
+			"status_code":  span.StatusCode,
+			"total_cost":   span.TotalCost,
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package workers
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"brokle/internal/core/domain/observability"
+	observabilitySvc "brokle/internal/core/services/observability"
+	"brokle/internal/infrastructure/streams"
+)
+
+// holdForTailSampling removes spans of tail-sampled projects from a batch.
+// Spans of traces that already have a decision follow it; the rest are buffered
+// until the trace's decision wait elapses. Returns the events to process now and
+// the number of spans buffered. Buffering fails open: on error spans are ingested.
+func (c *TelemetryStreamConsumer) holdForTailSampling(ctx context.Context, batch *streams.TelemetryStreamMessage, events []streams.TelemetryEventData) ([]streams.TelemetryEventData, int) {
+	if !c.samplingService.Enabled() {
+		return events, 0
+	}
+	policy := c.samplingService.PolicyFor(ctx, batch.ProjectID)
+	if !policy.TailSamples() {
+		return events, 0
+	}
+
+	byTrace := make(map[string][]streams.TelemetryEventData)
+	traceIDs := make([]string, 0)
+	passThrough := make([]streams.TelemetryEventData, 0, len(events))
+	for _, event := range events {
+		if observability.TelemetryEventType(event.EventType) != observability.TelemetryEventTypeSpan || event.TraceID == "" {
+			passThrough = append(passThrough, event)
+			continue
+		}
+		if _, ok := byTrace[event.TraceID]; !ok {
+			traceIDs = append(traceIDs, event.TraceID)
+		}
+		byTrace[event.TraceID] = append(byTrace[event.TraceID], event)
+	}
+	if len(traceIDs) == 0 {
+		return events, 0
+	}
+
+	decisions, err := c.samplingService.GetDecisions(ctx, batch.ProjectID, traceIDs)
+	if err != nil {
+		c.logger.Warn("Failed to read tail sampling decisions, ingesting spans", "error", err, "batch_id", batch.BatchID.String())
+		return events, 0
+	}
+
+	held := 0
+	for _, traceID := range traceIDs {
+		traceEvents := byTrace[traceID]
+
+		if decision, ok := decisions[traceID]; ok {
+			if !decision.Keep {
+				atomic.AddInt64(&c.tailDroppedSpans, int64(len(traceEvents)))
+				continue
+			}
+			for _, event := range traceEvents {
+				observabilitySvc.ScalePayloadSampleRate(event.EventPayload, decision.SampleRate)
+			}
+			passThrough = append(passThrough, traceEvents...)
+			continue
+		}
+
+		buffered := make([]*observability.BufferedSpan, len(traceEvents))
+		for i, event := range traceEvents {
+			buffered[i] = &observability.BufferedSpan{
+				OrganizationID: batch.OrganizationID,
+				EventID:        event.EventID,
+				Payload:        event.EventPayload,
+			}
+		}
+		if err := c.samplingService.BufferSpans(ctx, policy, traceID, buffered); err != nil {
+			c.logger.Warn("Failed to buffer spans for tail sampling, ingesting spans", "error", err, "trace_id", traceID, "batch_id", batch.BatchID.String())
+			passThrough = append(passThrough, traceEvents...)
+			continue
+		}
+		held += len(traceEvents)
+	}
+
+	atomic.AddInt64(&c.tailBufferedSpans, int64(held))
+	return passThrough, held
+}
+
+// tailSamplingLoop decides buffered traces whose decision wait has elapsed
+func (c *TelemetryStreamConsumer) tailSamplingLoop(ctx context.Context) {
+	defer c.wg.Done()
+
+	interval := time.Duration(c.samplingConfig.TailFlushIntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.quit:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.flushTailSampling(ctx)
+		}
+	}
+}
+
+// flushTailSampling claims due traces until none are left, deciding and ingesting each
+func (c *TelemetryStreamConsumer) flushTailSampling(ctx context.Context) {
+	limit := c.samplingConfig.TailFlushBatchSize
+	if limit <= 0 {
+		limit = 200
+	}
+
+	for {
+		traces, err := c.samplingService.ClaimDueTraces(ctx, limit)
+		if err != nil {
+			c.logger.Error("Failed to claim buffered traces for tail sampling", "error", err)
+		}
+		for _, trace := range traces {
+			c.decideTrace(ctx, trace)
+		}
+		if err != nil || len(traces) < limit {
+			return
+		}
+	}
+}
+
+// decideTrace applies the project's tail sampling policy to a buffered trace
+func (c *TelemetryStreamConsumer) decideTrace(ctx context.Context, trace *observability.BufferedTrace) {
+	spans := make([]*observability.Span, 0, len(trace.Spans))
+	for _, buffered := range trace.Spans {
+		var span observability.Span
+		if err := mapToStruct(buffered.Payload, &span); err != nil {
+			c.logger.Error("Failed to unmarshal buffered span payload", "error", err, "event_id", buffered.EventID.String(), "trace_id", trace.TraceID)
+			continue
+		}
+		span.ProjectID = trace.ProjectID.String()
+		span.OrganizationID = buffered.OrganizationID.String()
+		spans = append(spans, &span)
+	}
+	if len(spans) == 0 {
+		return
+	}
+
+	// The policy may have been removed or disabled while the trace was buffered
+	decision := &observability.TailDecision{Keep: true, SampleRate: 1}
+	if policy := c.samplingService.PolicyFor(ctx, trace.ProjectID); policy.TailSamples() {
+		decision = c.samplingService.DecideTail(policy, trace.TraceID, spans)
+	}
+
+	if err := c.samplingService.RecordDecision(ctx, trace.ProjectID, trace.TraceID, decision); err != nil {
+		c.logger.Warn("Failed to record tail sampling decision", "error", err, "trace_id", trace.TraceID)
+	}
+
+	if !decision.Keep {
+		atomic.AddInt64(&c.tailDroppedSpans, int64(len(spans)))
+		return
+	}
+
+	for _, span := range spans {
+		span.SampleRate = span.GetSampleRate() * decision.SampleRate
+	}
+
+	if err := c.traceService.IngestSpanBatch(ctx, spans); err != nil {
+		c.logger.Error("Failed to ingest tail sampled trace", "error", err, "trace_id", trace.TraceID, "span_count", len(spans))
+		c.incrementErrors()
+		return
+	}
+
+	atomic.AddInt64(&c.tailKeptSpans, int64(len(spans)))
+	c.incrementStats(0, int64(len(spans)))
+	c.publishTraceCreated(ctx, trace.ProjectID, spans)
+}
+
+// isTailSamplingEnabled returns true if the consumer runs the tail sampling loop
+func (c *TelemetryStreamConsumer) isTailSamplingEnabled() bool {
+	return c.samplingService.Enabled() && c.samplingConfig != nil
+}
@@ -15,6 +15,7 @@ import (
 	"brokle/internal/core/domain/billing"
 	"brokle/internal/core/domain/common"
 	"brokle/internal/core/domain/organization"
+	"brokle/internal/core/domain/webhook"
 	appErrors "brokle/pkg/errors"
 	"brokle/pkg/pagination"
 	"brokle/pkg/ulid"
@@ -34,6 +35,8 @@ type UsageAggregationWorker struct {
 	orgRepo                  organization.OrganizationRepository
 	pricingService           billing.PricingService
 	notificationWorker       *NotificationWorker
+	webhookPublisher         webhook.Publisher
+	enforcementService       billing.EnforcementService
 	quit                     chan struct{}
 	wg                       sync.WaitGroup
 	ticker                   *time.Ticker
@@ -52,6 +55,8 @@ func NewUsageAggregationWorker(
 	orgRepo organization.OrganizationRepository,
 	pricingService billing.PricingService,
 	notificationWorker *NotificationWorker,
+	webhookPublisher webhook.Publisher,
+	enforcementService billing.EnforcementService,
 ) *UsageAggregationWorker {
 	// Get alert deduplication window from config (default 24 hours)
 	alertDeduplicationHours := config.Workers.AlertDeduplicationHours
@@ -70,6 +75,8 @@ func NewUsageAggregationWorker(
 		orgRepo:                  orgRepo,
 		pricingService:           pricingService,
 		notificationWorker:       notificationWorker,
+		webhookPublisher:         webhookPublisher,
+		enforcementService:       enforcementService,
 		quit:                     make(chan struct{}),
 		alertDeduplicationWindow: time.Duration(alertDeduplicationHours) * time.Hour,
 	}
@@ -162,6 +169,16 @@ func (w *UsageAggregationWorker) run() {
 			}
 			syncedCount++
 
+			// Re-evaluate ingestion enforcement against the freshly synced budget usage
+			if w.enforcementService != nil {
+				if err := w.enforcementService.Refresh(ctx, org.ID); err != nil {
+					w.logger.Error("failed to refresh budget enforcement",
+						"error", err,
+						"organization_id", org.ID,
+					)
+				}
+			}
+
 			// Check budgets and trigger alerts
 			alerts, err := w.checkBudgets(ctx, org.ID)
 			if err != nil {
@@ -175,6 +192,7 @@ func (w *UsageAggregationWorker) run() {
 
 			// Send notifications for new alerts
 			for _, alert := range alerts {
+				w.publishAlert(ctx, alert)
 				w.sendAlertNotification(ctx, org, alert)
 			}
 		}
@@ -545,6 +563,19 @@ func (w *UsageAggregationWorker) sendAlertNotification(ctx context.Context, org
 	}
 }
 
+// publishAlert emits budget.alert.triggered to the alert's project webhooks,
+// or to every project in the organization for organization-level budgets
+func (w *UsageAggregationWorker) publishAlert(ctx context.Context, alert *billing.UsageAlert) {
+	if w.webhookPublisher == nil {
+		return
+	}
+	if alert.ProjectID != nil {
+		w.webhookPublisher.Publish(ctx, *alert.ProjectID, webhook.EventBudgetAlertTriggered, alert)
+		return
+	}
+	w.webhookPublisher.PublishToOrganization(ctx, alert.OrganizationID, webhook.EventBudgetAlertTriggered, alert)
+}
+
 // calculateCost computes total cost from three billable dimensions with tier support
 func (w *UsageAggregationWorker) calculateCost(usage *billing.BillableUsageSummary, pricing *billing.EffectivePricing) decimal.Decimal {
 	if pricing.HasVolumeTiers {
@@ -0,0 +1,97 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"brokle/internal/config"
+	"brokle/internal/core/domain/webhook"
+)
+
+// WebhookDeliveryWorker periodically sends queued webhook subscription deliveries
+type WebhookDeliveryWorker struct {
+	config         *config.Config
+	logger         *slog.Logger
+	webhookService webhook.Service
+	quit           chan struct{}
+	wg             sync.WaitGroup
+	ticker         *time.Ticker
+}
+
+// NewWebhookDeliveryWorker creates a new webhook delivery worker
+func NewWebhookDeliveryWorker(
+	config *config.Config,
+	logger *slog.Logger,
+	webhookService webhook.Service,
+) *WebhookDeliveryWorker {
+	return &WebhookDeliveryWorker{
+		config:         config,
+		logger:         logger,
+		webhookService: webhookService,
+		quit:           make(chan struct{}),
+	}
+}
+
+// Start starts the webhook delivery worker
+func (w *WebhookDeliveryWorker) Start() {
+	w.logger.Info("Starting webhook delivery worker", "poll_interval_seconds", w.config.Webhooks.PollIntervalSeconds)
+
+	w.wg.Add(1)
+	go w.mainLoop()
+}
+
+// Stop stops the webhook delivery worker and waits for in-flight deliveries
+func (w *WebhookDeliveryWorker) Stop() {
+	w.logger.Info("Stopping webhook delivery worker")
+	close(w.quit)
+	w.wg.Wait()
+}
+
+// mainLoop polls for due deliveries on the configured interval
+func (w *WebhookDeliveryWorker) mainLoop() {
+	defer w.wg.Done()
+
+	interval := time.Duration(w.config.Webhooks.PollIntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	w.ticker = time.NewTicker(interval)
+	for {
+		select {
+		case <-w.ticker.C:
+			w.run()
+		case <-w.quit:
+			w.ticker.Stop()
+			w.logger.Info("Webhook delivery worker stopped")
+			return
+		}
+	}
+}
+
+// run executes a single delivery pass
+func (w *WebhookDeliveryWorker) run() {
+	// Claimed rows are leased, so a pass cut short by shutdown is retried by the next worker
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	startTime := time.Now()
+
+	summary, err := w.webhookService.ProcessDueDeliveries(ctx, startTime)
+	if err != nil {
+		w.logger.Error("webhook delivery pass failed", "error", err)
+		return
+	}
+
+	if summary.Claimed > 0 {
+		w.logger.Debug("Webhook delivery pass completed",
+			"claimed", summary.Claimed,
+			"succeeded", summary.Succeeded,
+			"retried", summary.Retried,
+			"failed", summary.Failed,
+			"duration_ms", time.Since(startTime).Milliseconds(),
+		)
+	}
+}
@@ -0,0 +1,11 @@
+-- ClickHouse Migration: remove_signal_ttls (rollback)
+-- Restores the TTLs the signal tables were created with.
+
+ALTER TABLE otel_traces MODIFY TTL start_time + toIntervalDay(365);
+ALTER TABLE otel_logs MODIFY TTL timestamp + toIntervalDay(90);
+ALTER TABLE otel_metrics_sum MODIFY TTL time_unix + toIntervalDay(365);
+ALTER TABLE otel_metrics_gauge MODIFY TTL time_unix + toIntervalDay(365);
+ALTER TABLE otel_metrics_histogram MODIFY TTL time_unix + toIntervalDay(365);
+ALTER TABLE otel_metrics_exponential_histogram MODIFY TTL time_unix + toIntervalDay(365);
+ALTER TABLE otel_genai_events MODIFY TTL timestamp + toIntervalDay(365);
+ALTER TABLE scores MODIFY TTL timestamp + INTERVAL 365 DAY;
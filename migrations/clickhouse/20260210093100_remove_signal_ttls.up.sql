@@ -0,0 +1,13 @@
+-- ClickHouse Migration: remove_signal_ttls
+-- Purpose: Retention is now configured per project and signal (data_retention_policies in
+--          PostgreSQL) and enforced by the retention worker via partition drops and
+--          ALTER TABLE ... DELETE. A table-level TTL would cap every project at the old value.
+
+ALTER TABLE otel_traces REMOVE TTL;
+ALTER TABLE otel_logs REMOVE TTL;
+ALTER TABLE otel_metrics_sum REMOVE TTL;
+ALTER TABLE otel_metrics_gauge REMOVE TTL;
+ALTER TABLE otel_metrics_histogram REMOVE TTL;
+ALTER TABLE otel_metrics_exponential_histogram REMOVE TTL;
+ALTER TABLE otel_genai_events REMOVE TTL;
+ALTER TABLE scores REMOVE TTL;
@@ -0,0 +1,2 @@
+-- Remove sample_rate column
+ALTER TABLE otel_traces DROP COLUMN IF EXISTS sample_rate;
@@ -0,0 +1,4 @@
+-- Add sample_rate column for head/tail sampling.
+-- Each stored span stands for 1 / sample_rate ingested spans; aggregates re-weight by it.
+ALTER TABLE otel_traces ADD COLUMN IF NOT EXISTS
+    sample_rate Float64 DEFAULT 1 CODEC(ZSTD(1));
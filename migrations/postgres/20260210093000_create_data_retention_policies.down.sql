@@ -0,0 +1,6 @@
+-- PostgreSQL Migration: create_data_retention_policies (rollback)
+-- Created: 2026-02-10
+
+DROP INDEX IF EXISTS idx_data_retention_policies_project;
+DROP INDEX IF EXISTS idx_data_retention_policies_org_default;
+DROP TABLE IF EXISTS data_retention_policies;
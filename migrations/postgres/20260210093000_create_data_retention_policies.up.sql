@@ -0,0 +1,28 @@
+-- PostgreSQL Migration: create_data_retention_policies
+-- Created: 2026-02-10
+-- Purpose: Per-organization and per-project telemetry retention, per signal.
+--          Enforced by the retention worker against ClickHouse.
+
+CREATE TABLE IF NOT EXISTS data_retention_policies (
+    id CHAR(26) PRIMARY KEY,
+    organization_id CHAR(26) NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    project_id CHAR(26) REFERENCES projects(id) ON DELETE CASCADE, -- NULL = organization default
+
+    -- Retention in days per signal (NULL = inherit from organization or system default)
+    spans_days INTEGER CHECK (spans_days > 0),
+    logs_days INTEGER CHECK (logs_days > 0),
+    metrics_days INTEGER CHECK (metrics_days > 0),
+    scores_days INTEGER CHECK (scores_days > 0),
+    genai_events_days INTEGER CHECK (genai_events_days > 0),
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- One organization default and one override per project
+CREATE UNIQUE INDEX IF NOT EXISTS idx_data_retention_policies_org_default
+    ON data_retention_policies(organization_id) WHERE project_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_data_retention_policies_project
+    ON data_retention_policies(project_id) WHERE project_id IS NOT NULL;
+
+COMMENT ON TABLE data_retention_policies IS 'Telemetry retention per organization (project_id NULL) or project, per signal';
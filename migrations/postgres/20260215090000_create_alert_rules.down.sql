@@ -0,0 +1,8 @@
+-- PostgreSQL Migration: create_alert_rules (rollback)
+-- Created: 2026-02-15
+
+DROP INDEX IF EXISTS idx_alert_events_rule_created;
+DROP INDEX IF EXISTS idx_alert_rules_enabled;
+DROP INDEX IF EXISTS idx_alert_rules_project_id;
+DROP TABLE IF EXISTS alert_events;
+DROP TABLE IF EXISTS alert_rules;
@@ -0,0 +1,61 @@
+-- PostgreSQL Migration: create_alert_rules
+-- Created: 2026-02-15
+-- Purpose: Query-based alert rules over spans, evaluated by the alert worker,
+--          with a history of state transitions and notifications.
+
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id VARCHAR(26) PRIMARY KEY,
+    project_id VARCHAR(26) NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+
+    -- What to evaluate: a saved preset (takes precedence) or a span query expression
+    filter_preset_id VARCHAR(26) REFERENCES filter_presets(id) ON DELETE SET NULL,
+    filter TEXT NOT NULL DEFAULT '',
+    aggregation VARCHAR(200) NOT NULL,             -- e.g. avg(has_error), quantile(0.95)(duration_ms)
+    operator VARCHAR(2) NOT NULL CHECK (operator IN ('>', '<', '>=', '<=')),
+    threshold DOUBLE PRECISION NOT NULL,
+    window_minutes INTEGER NOT NULL CHECK (window_minutes > 0),
+    pending_minutes INTEGER NOT NULL DEFAULT 0 CHECK (pending_minutes >= 0),
+
+    -- Notification targets: [{"type": "email|webhook|slack", "target": "..."}]
+    channels JSONB NOT NULL DEFAULT '[]',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+
+    -- Evaluation state
+    state VARCHAR(20) NOT NULL DEFAULT 'ok' CHECK (state IN ('ok', 'pending', 'firing', 'resolved')),
+    state_changed_at TIMESTAMPTZ,
+    last_value DOUBLE PRECISION,
+    last_evaluated_at TIMESTAMPTZ,
+
+    -- Silencing suppresses notifications; evaluation and history continue
+    silenced_until TIMESTAMPTZ,
+    silence_reason TEXT,
+
+    created_by VARCHAR(26) REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT alert_rules_project_name_unique UNIQUE(project_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_rules_project_id ON alert_rules(project_id);
+CREATE INDEX IF NOT EXISTS idx_alert_rules_enabled ON alert_rules(enabled) WHERE enabled = TRUE;
+
+CREATE TABLE IF NOT EXISTS alert_events (
+    id VARCHAR(26) PRIMARY KEY,
+    rule_id VARCHAR(26) NOT NULL REFERENCES alert_rules(id) ON DELETE CASCADE,
+    project_id VARCHAR(26) NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    state VARCHAR(20) NOT NULL,
+    previous_state VARCHAR(20) NOT NULL,
+    value DOUBLE PRECISION,
+    threshold DOUBLE PRECISION NOT NULL,
+    silenced BOOLEAN NOT NULL DEFAULT FALSE,
+    notified BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_events_rule_created ON alert_events(rule_id, created_at DESC);
+
+COMMENT ON TABLE alert_rules IS 'Threshold alerts on span aggregations over a filter preset or expression';
+COMMENT ON TABLE alert_events IS 'Alert rule state transitions and notification history';
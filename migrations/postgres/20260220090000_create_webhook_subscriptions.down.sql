@@ -0,0 +1,8 @@
+-- PostgreSQL Migration: create_webhook_subscriptions (rollback)
+-- Created: 2026-02-20
+
+DROP INDEX IF EXISTS idx_webhook_deliveries_due;
+DROP INDEX IF EXISTS idx_webhook_deliveries_subscription_created;
+DROP INDEX IF EXISTS idx_webhook_subscriptions_project_id;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_subscriptions;
@@ -0,0 +1,52 @@
+-- PostgreSQL Migration: create_webhook_subscriptions
+-- Created: 2026-02-20
+-- Purpose: Project webhook endpoints subscribed to platform events, with a
+--          delivery log that doubles as the retry queue for the webhook worker.
+
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id VARCHAR(26) PRIMARY KEY,
+    project_id VARCHAR(26) NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    url TEXT NOT NULL,
+    secret VARCHAR(100) NOT NULL,              -- HMAC-SHA256 signing secret (whsec_...)
+
+    -- Event filter: ["trace.created", "score.created", ...]
+    event_types JSONB NOT NULL DEFAULT '[]',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+
+    created_by VARCHAR(26) REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT webhook_subscriptions_project_name_unique UNIQUE(project_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_project_id ON webhook_subscriptions(project_id);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id VARCHAR(26) PRIMARY KEY,
+    subscription_id VARCHAR(26) NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    project_id VARCHAR(26) NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    event_id VARCHAR(26) NOT NULL,              -- Shared by every delivery of one event
+    event_type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'succeeded', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    response_code INTEGER,
+    response_body TEXT,                         -- Truncated receiver response from the latest attempt
+    error TEXT,
+    duration_ms BIGINT,
+    next_attempt_at TIMESTAMPTZ,                -- NULL once the delivery is terminal
+    delivered_at TIMESTAMPTZ,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription_created ON webhook_deliveries(subscription_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+
+COMMENT ON TABLE webhook_subscriptions IS 'Project webhook endpoints and the platform events they receive';
+COMMENT ON TABLE webhook_deliveries IS 'Webhook delivery log and retry queue with receiver response codes';
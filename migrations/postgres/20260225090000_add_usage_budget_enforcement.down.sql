@@ -0,0 +1,6 @@
+-- PostgreSQL Migration: add_usage_budget_enforcement (rollback)
+-- Created: 2026-02-25
+
+ALTER TABLE usage_budgets
+    DROP COLUMN IF EXISTS enforcement_sample_percent,
+    DROP COLUMN IF EXISTS enforcement_action;
@@ -0,0 +1,17 @@
+-- PostgreSQL Migration: add_usage_budget_enforcement
+-- Created: 2026-02-25
+-- Purpose: Let budgets restrict OTLP ingestion instead of only alerting.
+
+-- Ingestion enforcement once any budget limit is reached:
+--   none          alerts only (previous behaviour)
+--   block         OTLP requests are rejected with 429
+--   sample        only enforcement_sample_percent of traces are kept
+--   drop_payloads spans are kept without input/output bodies
+ALTER TABLE usage_budgets
+    ADD COLUMN enforcement_action VARCHAR(20) NOT NULL DEFAULT 'none'
+        CHECK (enforcement_action IN ('none', 'block', 'sample', 'drop_payloads')),
+    ADD COLUMN enforcement_sample_percent INTEGER
+        CHECK (enforcement_sample_percent IS NULL OR (enforcement_sample_percent >= 1 AND enforcement_sample_percent <= 99));
+
+COMMENT ON COLUMN usage_budgets.enforcement_action IS 'Ingestion action applied once any limit is reached: none, block, sample, drop_payloads';
+COMMENT ON COLUMN usage_budgets.enforcement_sample_percent IS 'Share of traces kept (1-99) when enforcement_action is sample';
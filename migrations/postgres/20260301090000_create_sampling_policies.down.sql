@@ -0,0 +1,4 @@
+-- PostgreSQL Migration: create_sampling_policies (rollback)
+-- Created: 2026-03-01
+
+DROP TABLE IF EXISTS sampling_policies;
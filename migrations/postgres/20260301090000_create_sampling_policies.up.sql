@@ -0,0 +1,29 @@
+-- PostgreSQL Migration: create_sampling_policies
+-- Created: 2026-03-01
+-- Purpose: Per-project head and tail sampling for OTLP ingestion.
+--          Head sampling runs in the OTLP handlers, tail sampling in the telemetry stream consumer.
+
+CREATE TABLE IF NOT EXISTS sampling_policies (
+    id CHAR(26) PRIMARY KEY,
+    project_id CHAR(26) NOT NULL UNIQUE REFERENCES projects(id) ON DELETE CASCADE,
+
+    -- Head sampling: fraction of traces kept at ingestion, keyed on trace ID
+    head_sample_rate DOUBLE PRECISION NOT NULL DEFAULT 1 CHECK (head_sample_rate > 0 AND head_sample_rate <= 1),
+
+    -- Tail sampling: buffer each trace, then keep it if a rule matches
+    tail_enabled BOOLEAN NOT NULL DEFAULT false,
+    decision_wait_seconds INTEGER NOT NULL DEFAULT 30 CHECK (decision_wait_seconds BETWEEN 1 AND 300),
+    tail_sample_rate DOUBLE PRECISION NOT NULL DEFAULT 1 CHECK (tail_sample_rate > 0 AND tail_sample_rate <= 1),
+
+    -- Keep rules (NULL / empty = rule disabled)
+    keep_errors BOOLEAN NOT NULL DEFAULT true,
+    keep_min_cost DECIMAL(18,6) CHECK (keep_min_cost >= 0),
+    keep_min_duration_ms BIGINT CHECK (keep_min_duration_ms >= 0),
+    keep_tags TEXT[] NOT NULL DEFAULT '{}',
+    keep_filter TEXT NOT NULL DEFAULT '',
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE sampling_policies IS 'Trace sampling per project; kept spans record the applied rate in otel_traces.sample_rate';
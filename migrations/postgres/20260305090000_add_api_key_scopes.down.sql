@@ -0,0 +1,4 @@
+-- PostgreSQL Migration: add_api_key_scopes (rollback)
+-- Created: 2026-03-05
+
+ALTER TABLE api_keys DROP COLUMN IF EXISTS scopes;
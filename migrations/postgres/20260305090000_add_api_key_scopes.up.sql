@@ -0,0 +1,20 @@
+-- PostgreSQL Migration: add_api_key_scopes
+-- Created: 2026-03-05
+-- Purpose: Least-privilege API keys. Each key is limited to a set of scopes
+--          enforced on every SDK route. Existing keys keep full SDK access.
+
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS scopes TEXT[] NOT NULL DEFAULT '{}';
+
+UPDATE api_keys
+SET scopes = ARRAY[
+    'traces:create', 'traces:read',
+    'prompts:read', 'prompts:create',
+    'scores:create',
+    'datasets:read', 'datasets:write',
+    'experiments:read', 'experiments:write',
+    'annotations:read', 'annotations:write',
+    'playground:execute'
+]
+WHERE scopes = '{}';
+
+COMMENT ON COLUMN api_keys.scopes IS 'Scopes granted to the key (subset of auth.APIKeyScopes)';
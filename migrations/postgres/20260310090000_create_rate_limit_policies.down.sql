@@ -0,0 +1,4 @@
+-- PostgreSQL Migration: create_rate_limit_policies (rollback)
+-- Created: 2026-03-10
+
+DROP TABLE IF EXISTS rate_limit_policies;
@@ -0,0 +1,26 @@
+-- PostgreSQL Migration: create_rate_limit_policies
+-- Created: 2026-03-10
+-- Purpose: Per-project and per-API-key rate limits and quotas for SDK traffic.
+--          Counters live in Redis sliding windows shared across replicas.
+
+CREATE TABLE IF NOT EXISTS rate_limit_policies (
+    id CHAR(26) PRIMARY KEY,
+    project_id CHAR(26) NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    -- NULL = limits shared by every key of the project
+    api_key_id CHAR(26) REFERENCES api_keys(id) ON DELETE CASCADE,
+
+    -- Limits (NULL = default, 0 = unlimited)
+    requests_per_second BIGINT CHECK (requests_per_second >= 0),
+    spans_per_minute BIGINT CHECK (spans_per_minute >= 0),
+    payload_bytes_per_day BIGINT CHECK (payload_bytes_per_day >= 0),
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_rate_limit_policies_project
+    ON rate_limit_policies(project_id) WHERE api_key_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_rate_limit_policies_api_key
+    ON rate_limit_policies(api_key_id) WHERE api_key_id IS NOT NULL;
+
+COMMENT ON TABLE rate_limit_policies IS 'Rate limit overrides; keys without a policy use the RATE_LIMIT_API_KEY_* defaults';
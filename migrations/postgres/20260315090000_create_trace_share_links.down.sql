@@ -0,0 +1,4 @@
+-- PostgreSQL Migration: create_trace_share_links (rollback)
+-- Created: 2026-03-15
+
+DROP TABLE IF EXISTS trace_share_links;
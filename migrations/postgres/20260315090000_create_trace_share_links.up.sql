@@ -0,0 +1,28 @@
+-- PostgreSQL Migration: create_trace_share_links
+-- Created: 2026-03-15
+-- Purpose: Public, read-only links to a trace or session. Tokens are signed with the
+--          server secret and not stored; revoking or expiring a link invalidates it.
+
+CREATE TABLE IF NOT EXISTS trace_share_links (
+    id CHAR(26) PRIMARY KEY,
+    project_id CHAR(26) NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    resource_type VARCHAR(20) NOT NULL CHECK (resource_type IN ('trace', 'session')),
+    resource_id VARCHAR(255) NOT NULL, -- Trace ID or session ID
+
+    redact_inputs BOOLEAN NOT NULL DEFAULT false,
+    redact_outputs BOOLEAN NOT NULL DEFAULT false,
+
+    expires_at TIMESTAMPTZ, -- NULL = never expires
+    revoked_at TIMESTAMPTZ,
+    created_by CHAR(26) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+
+    view_count BIGINT NOT NULL DEFAULT 0,
+    last_viewed_at TIMESTAMPTZ,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_trace_share_links_project ON trace_share_links(project_id, created_at DESC);
+
+COMMENT ON TABLE trace_share_links IS 'Public trace/session share links; each view is recorded in audit_logs as trace_share.viewed';
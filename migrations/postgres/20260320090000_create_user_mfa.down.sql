@@ -0,0 +1,4 @@
+-- PostgreSQL Migration: create_user_mfa (rollback)
+-- Created: 2026-03-20
+
+DROP TABLE IF EXISTS user_mfa;
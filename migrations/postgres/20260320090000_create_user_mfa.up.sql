@@ -0,0 +1,19 @@
+-- PostgreSQL Migration: create_user_mfa
+-- Created: 2026-03-20
+-- Purpose: TOTP multi-factor enrolment for dashboard users. Secrets are encrypted with the
+--          server encryption key; recovery codes are stored as SHA-256 hashes.
+
+CREATE TABLE IF NOT EXISTS user_mfa (
+    user_id CHAR(26) PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret_encrypted TEXT NOT NULL,
+    recovery_code_hashes TEXT[] NOT NULL DEFAULT '{}',
+
+    enabled BOOLEAN NOT NULL DEFAULT false, -- false = enrolment pending confirmation
+    enabled_at TIMESTAMPTZ,
+    last_used_step BIGINT NOT NULL DEFAULT 0, -- Last accepted TOTP time step, rejects replays
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE user_mfa IS 'TOTP enrolments; organizations enforce MFA with the security.require_mfa setting';
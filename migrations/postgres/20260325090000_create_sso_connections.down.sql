@@ -0,0 +1,4 @@
+-- PostgreSQL Migration: create_sso_connections (rollback)
+-- Created: 2026-03-25
+
+DROP TABLE IF EXISTS sso_connections;
@@ -0,0 +1,32 @@
+-- PostgreSQL Migration: create_sso_connections
+-- Created: 2026-03-25
+-- Purpose: Per-organization OIDC or SAML 2.0 identity provider. Logins from the verified
+--          email domain auto-join the organization; enforce_sso blocks password login.
+
+CREATE TABLE IF NOT EXISTS sso_connections (
+    id CHAR(26) PRIMARY KEY,
+    organization_id CHAR(26) NOT NULL UNIQUE REFERENCES organizations(id) ON DELETE CASCADE,
+    protocol VARCHAR(10) NOT NULL CHECK (protocol IN ('oidc', 'saml')),
+
+    domain VARCHAR(255) NOT NULL UNIQUE, -- Lower-cased email domain
+    verification_token VARCHAR(64) NOT NULL,
+    domain_verified_at TIMESTAMPTZ, -- NULL until the DNS TXT record is found
+
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    enforce_sso BOOLEAN NOT NULL DEFAULT false,
+    default_role VARCHAR(50) NOT NULL DEFAULT 'viewer',
+    attribute_mapping JSONB NOT NULL DEFAULT '{}',
+
+    oidc_issuer VARCHAR(500),
+    oidc_client_id VARCHAR(255),
+    oidc_client_secret_encrypted TEXT,
+
+    saml_idp_entity_id VARCHAR(500),
+    saml_idp_sso_url VARCHAR(1000),
+    saml_idp_certificate TEXT,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE sso_connections IS 'Enterprise SSO identity providers, one per organization';
@@ -0,0 +1,7 @@
+-- PostgreSQL Migration: create_scim_provisioning (rollback)
+-- Created: 2026-03-30
+
+DROP TABLE IF EXISTS scim_group_members;
+DROP TABLE IF EXISTS scim_groups;
+DROP TABLE IF EXISTS scim_users;
+DROP TABLE IF EXISTS scim_tokens;
@@ -0,0 +1,53 @@
+-- PostgreSQL Migration: create_scim_provisioning
+-- Created: 2026-03-30
+-- Purpose: SCIM 2.0 provisioning. Organization-scoped bearer tokens, users provisioned
+--          into an organization, and identity provider groups mapped to organization roles.
+
+CREATE TABLE IF NOT EXISTS scim_tokens (
+    id CHAR(26) PRIMARY KEY,
+    organization_id CHAR(26) NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE, -- SHA-256 of the bk_scim_ token
+    token_preview VARCHAR(20) NOT NULL,
+    created_by CHAR(26) NOT NULL, -- Recorded as the actor of SCIM membership changes
+    last_used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_scim_tokens_organization ON scim_tokens(organization_id);
+
+CREATE TABLE IF NOT EXISTS scim_users (
+    organization_id CHAR(26) NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id CHAR(26) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    external_id VARCHAR(255),
+    active BOOLEAN NOT NULL DEFAULT true, -- false once deprovisioned; the membership is removed
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (organization_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_scim_users_external_id ON scim_users(organization_id, external_id);
+
+CREATE TABLE IF NOT EXISTS scim_groups (
+    id CHAR(26) PRIMARY KEY,
+    organization_id CHAR(26) NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    display_name VARCHAR(255) NOT NULL,
+    external_id VARCHAR(255),
+    role_id CHAR(26) REFERENCES roles(id) ON DELETE SET NULL, -- NULL when the group grants no role
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_scim_groups_display_name ON scim_groups(organization_id, LOWER(display_name));
+
+CREATE TABLE IF NOT EXISTS scim_group_members (
+    group_id CHAR(26) NOT NULL REFERENCES scim_groups(id) ON DELETE CASCADE,
+    user_id CHAR(26) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (group_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_scim_group_members_user ON scim_group_members(user_id);
+
+COMMENT ON TABLE scim_tokens IS 'Bearer tokens identity providers use to call /scim/v2';
+COMMENT ON TABLE scim_groups IS 'Identity provider groups; role_id grants an organization role to members';
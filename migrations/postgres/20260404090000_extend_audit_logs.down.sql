@@ -0,0 +1,10 @@
+-- PostgreSQL Migration: extend_audit_logs (rollback)
+-- Created: 2026-04-04
+
+DROP INDEX IF EXISTS idx_audit_logs_org_created_at;
+DROP INDEX IF EXISTS idx_audit_logs_project_id;
+
+ALTER TABLE audit_logs
+    DROP COLUMN IF EXISTS changes,
+    DROP COLUMN IF EXISTS api_key_id,
+    DROP COLUMN IF EXISTS project_id;
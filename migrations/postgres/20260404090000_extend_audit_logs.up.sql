@@ -0,0 +1,12 @@
+-- PostgreSQL Migration: extend_audit_logs
+-- Created: 2026-04-04
+-- Purpose: Cross-cutting audit trail. Records the acting API key, the project a change
+--          belongs to, and a before/after diff of the changed fields.
+
+ALTER TABLE audit_logs
+    ADD COLUMN project_id CHAR(26),
+    ADD COLUMN api_key_id CHAR(26),
+    ADD COLUMN changes JSONB;
+
+CREATE INDEX idx_audit_logs_project_id ON audit_logs(project_id);
+CREATE INDEX idx_audit_logs_org_created_at ON audit_logs(organization_id, created_at DESC);
@@ -0,0 +1,4 @@
+-- PostgreSQL Migration: create_redaction_policies (rollback)
+-- Created: 2026-04-08
+
+DROP TABLE IF EXISTS redaction_policies;
@@ -0,0 +1,25 @@
+-- PostgreSQL Migration: create_redaction_policies
+-- Created: 2026-04-08
+-- Purpose: Per-project PII redaction of spans, applied by the OTLP converter before storage.
+
+CREATE TABLE IF NOT EXISTS redaction_policies (
+    id CHAR(26) PRIMARY KEY,
+    project_id CHAR(26) NOT NULL UNIQUE REFERENCES projects(id) ON DELETE CASCADE,
+
+    enabled BOOLEAN NOT NULL DEFAULT false,
+    mode VARCHAR(10) NOT NULL DEFAULT 'mask' CHECK (mode IN ('mask', 'hash', 'drop')),
+
+    -- Built-in detectors: email, phone, credit_card, iban, ssn, ip_address, secret
+    detectors TEXT[] NOT NULL DEFAULT '{}',
+    -- [{"name": "...", "pattern": "..."}], RE2 syntax
+    custom_patterns JSONB NOT NULL DEFAULT '[]',
+
+    -- Attribute keys never scanned / always redacted whole; a trailing * matches a prefix
+    allow_attributes TEXT[] NOT NULL DEFAULT '{}',
+    deny_attributes TEXT[] NOT NULL DEFAULT '{}',
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE redaction_policies IS 'PII redaction per project; redacted spans record the count in span_attributes[''brokle.redaction.count'']';
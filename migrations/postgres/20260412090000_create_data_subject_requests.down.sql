@@ -0,0 +1,4 @@
+-- PostgreSQL Migration: create_data_subject_requests (rollback)
+-- Created: 2026-04-12
+
+DROP TABLE IF EXISTS data_subject_requests;
@@ -0,0 +1,37 @@
+-- PostgreSQL Migration: create_data_subject_requests
+-- Created: 2026-04-12
+-- Purpose: GDPR erasure and export requests for an end user, processed asynchronously by the worker.
+
+CREATE TABLE IF NOT EXISTS data_subject_requests (
+    id CHAR(26) PRIMARY KEY,
+    project_id CHAR(26) NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+
+    -- The user.id span attribute of the data subject
+    user_id VARCHAR(256) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('erasure', 'export')),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+
+    -- Completed stages and running counts; the certificate is set on completion
+    progress JSONB NOT NULL DEFAULT '{}',
+    certificate JSONB,
+    -- Traces found by the discover stage, cleared on completion
+    trace_ids TEXT[],
+    -- Gzipped JSON bundle of a completed export
+    bundle BYTEA,
+    error TEXT,
+
+    attempts INTEGER NOT NULL DEFAULT 0,
+    requested_by CHAR(26) REFERENCES users(id) ON DELETE SET NULL,
+    lease_expires_at TIMESTAMPTZ,
+    started_at TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_data_subject_requests_project ON data_subject_requests(project_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_data_subject_requests_queue ON data_subject_requests(status, created_at)
+    WHERE status IN ('pending', 'running');
+
+COMMENT ON TABLE data_subject_requests IS 'GDPR data subject erasure and export requests; erasures must complete within 30 days';
@@ -0,0 +1,9 @@
+-- PostgreSQL Migration: project_role_assignments (rollback)
+-- Created: 2026-04-16
+
+ALTER TABLE project_members
+    DROP CONSTRAINT IF EXISTS fk_project_members_project,
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS assigned_by;
+
+COMMENT ON TABLE project_members IS 'Single role per user per project (future)';
@@ -0,0 +1,15 @@
+-- PostgreSQL Migration: project_role_assignments
+-- Created: 2026-04-16
+-- Purpose: Assign users per-project roles that replace their organization role inside the project
+
+-- Rows can only reference projects that still exist
+DELETE FROM project_members pm
+WHERE NOT EXISTS (SELECT 1 FROM projects p WHERE p.id = pm.project_id);
+
+ALTER TABLE project_members
+    ADD COLUMN assigned_by CHAR(26) REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN updated_at TIMESTAMP DEFAULT NOW(),
+    ADD CONSTRAINT fk_project_members_project FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE;
+
+COMMENT ON TABLE project_members IS 'Single role per user per project; replaces the organization role inside the project';
+COMMENT ON COLUMN project_members.assigned_by IS 'User who assigned the role';
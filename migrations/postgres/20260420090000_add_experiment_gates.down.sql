@@ -0,0 +1,6 @@
+-- PostgreSQL Migration: add_experiment_gates (rollback)
+-- Created: 2026-04-20
+
+ALTER TABLE experiments
+DROP COLUMN IF EXISTS gates,
+DROP COLUMN IF EXISTS gate_verdict;
@@ -0,0 +1,7 @@
+-- PostgreSQL Migration: add_experiment_gates
+-- Created: 2026-04-20
+-- Purpose: CI evaluation gates on experiments and the verdict computed when the experiment finishes.
+
+ALTER TABLE experiments
+ADD COLUMN IF NOT EXISTS gates JSONB NOT NULL DEFAULT '[]',
+ADD COLUMN IF NOT EXISTS gate_verdict JSONB;
@@ -0,0 +1,8 @@
+-- PostgreSQL Migration: add_pairwise_scorer_type (rollback)
+-- Created: 2026-04-24
+
+DELETE FROM evaluators WHERE scorer_type = 'pairwise';
+
+ALTER TABLE evaluators DROP CONSTRAINT IF EXISTS evaluators_scorer_type_check;
+ALTER TABLE evaluators ADD CONSTRAINT evaluators_scorer_type_check
+    CHECK (scorer_type IN ('llm', 'builtin', 'regex'));
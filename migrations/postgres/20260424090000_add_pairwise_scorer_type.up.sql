@@ -0,0 +1,7 @@
+-- PostgreSQL Migration: add_pairwise_scorer_type
+-- Created: 2026-04-24
+-- Purpose: Allow pairwise (A/B preference) LLM judge evaluators.
+
+ALTER TABLE evaluators DROP CONSTRAINT IF EXISTS evaluators_scorer_type_check;
+ALTER TABLE evaluators ADD CONSTRAINT evaluators_scorer_type_check
+    CHECK (scorer_type IN ('llm', 'builtin', 'regex', 'pairwise'));
@@ -0,0 +1,4 @@
+-- PostgreSQL Migration: drop_webhook_delivery_response_body (rollback)
+-- Created: 2026-04-26
+
+ALTER TABLE webhook_deliveries ADD COLUMN IF NOT EXISTS response_body TEXT;
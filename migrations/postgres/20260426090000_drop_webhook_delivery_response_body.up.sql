@@ -0,0 +1,6 @@
+-- PostgreSQL Migration: drop_webhook_delivery_response_body
+-- Created: 2026-04-26
+-- Purpose: Stop keeping receiver responses; they were shown to project members and
+-- could expose whatever the endpoint returned.
+
+ALTER TABLE webhook_deliveries DROP COLUMN IF EXISTS response_body;
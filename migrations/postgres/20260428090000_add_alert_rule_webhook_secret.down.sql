@@ -0,0 +1,4 @@
+-- PostgreSQL Migration: add_alert_rule_webhook_secret (rollback)
+-- Created: 2026-04-28
+
+ALTER TABLE alert_rules DROP COLUMN IF EXISTS webhook_secret;
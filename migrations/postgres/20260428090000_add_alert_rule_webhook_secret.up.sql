@@ -0,0 +1,6 @@
+-- PostgreSQL Migration: add_alert_rule_webhook_secret
+-- Created: 2026-04-28
+-- Purpose: Sign alert webhooks with a per-rule secret instead of the platform-wide one.
+-- Existing rules deliver unsigned until their secret is rotated.
+
+ALTER TABLE alert_rules ADD COLUMN IF NOT EXISTS webhook_secret VARCHAR(100) NOT NULL DEFAULT '';
@@ -0,0 +1,5 @@
+-- PostgreSQL Migration: add_metrics_logs_api_key_scopes (rollback)
+-- Created: 2026-04-28
+
+UPDATE api_keys
+SET scopes = array_remove(array_remove(scopes, 'metrics:create'), 'logs:create');
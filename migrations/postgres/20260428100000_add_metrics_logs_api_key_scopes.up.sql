@@ -0,0 +1,9 @@
+-- PostgreSQL Migration: add_metrics_logs_api_key_scopes
+-- Created: 2026-04-28
+-- Purpose: OTLP metrics and logs ingestion get their own API key scopes instead of
+--          traces:create. Keys that could ingest traces keep ingesting all signals.
+
+UPDATE api_keys
+SET scopes = scopes || ARRAY['metrics:create', 'logs:create']
+WHERE 'traces:create' = ANY(scopes)
+  AND NOT scopes @> ARRAY['metrics:create', 'logs:create'];
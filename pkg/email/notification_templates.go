@@ -0,0 +1,109 @@
+package email
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	textTemplate "text/template"
+)
+
+// NotificationEmailParams contains parameters for generic notification emails
+// (alerts, usage warnings, account messages)
+type NotificationEmailParams struct {
+	Title       string   // Heading shown at the top of the email
+	Paragraphs  []string // Body paragraphs, rendered in order
+	ActionLabel string   // Optional call-to-action button label
+	ActionURL   string   // Optional call-to-action link
+	AppName     string   // Application name (e.g., "Brokle")
+}
+
+// BuildNotificationEmail generates the HTML and plain text bodies for a notification email
+func BuildNotificationEmail(params NotificationEmailParams) (html, text string, err error) {
+	htmlTmpl := template.Must(template.New("notification_html").Parse(notificationHTMLTemplate))
+	textTmpl := textTemplate.Must(textTemplate.New("notification_text").Parse(notificationTextTemplate))
+
+	if params.AppName == "" {
+		params.AppName = "Brokle"
+	}
+	if params.ActionLabel == "" {
+		params.ActionLabel = "Open " + params.AppName
+	}
+
+	var htmlBuf bytes.Buffer
+	if err := htmlTmpl.Execute(&htmlBuf, params); err != nil {
+		return "", "", fmt.Errorf("failed to generate HTML email: %w", err)
+	}
+
+	var textBuf bytes.Buffer
+	if err := textTmpl.Execute(&textBuf, params); err != nil {
+		return "", "", fmt.Errorf("failed to generate text email: %w", err)
+	}
+
+	return htmlBuf.String(), textBuf.String(), nil
+}
+
+const notificationHTMLTemplate = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1.0">
+  <title>{{.Title}}</title>
+</head>
+<body style="margin: 0; padding: 0; font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, 'Helvetica Neue', Arial, sans-serif; background-color: #f4f4f5;">
+  <table role="presentation" style="width: 100%; border-collapse: collapse;">
+    <tr>
+      <td align="center" style="padding: 40px 0;">
+        <table role="presentation" style="width: 100%; max-width: 600px; border-collapse: collapse; background-color: #ffffff; border-radius: 8px; box-shadow: 0 1px 3px rgba(0,0,0,0.1);">
+          <!-- Header -->
+          <tr>
+            <td style="padding: 40px 40px 20px 40px; text-align: center;">
+              <h1 style="margin: 0; font-size: 24px; font-weight: 600; color: #18181b;">
+                {{.Title}}
+              </h1>
+            </td>
+          </tr>
+
+          <!-- Body -->
+          <tr>
+            <td style="padding: 20px 40px;">
+              {{range .Paragraphs}}
+              <p style="margin: 0 0 16px 0; font-size: 16px; line-height: 24px; color: #3f3f46;">
+                {{.}}
+              </p>
+              {{end}}
+            </td>
+          </tr>
+          {{if .ActionURL}}
+          <!-- CTA Button -->
+          <tr>
+            <td style="padding: 10px 40px 30px 40px; text-align: center;">
+              <a href="{{.ActionURL}}" style="display: inline-block; padding: 14px 32px; font-size: 16px; font-weight: 600; color: #ffffff; background-color: #18181b; text-decoration: none; border-radius: 6px;">
+                {{.ActionLabel}}
+              </a>
+            </td>
+          </tr>
+          {{end}}
+          <!-- Footer -->
+          <tr>
+            <td style="padding: 20px 40px 40px 40px; border-top: 1px solid #e4e4e7;">
+              <p style="margin: 0; font-size: 12px; line-height: 18px; color: #a1a1aa; text-align: center;">
+                Sent by {{.AppName}} - AI Observability Platform
+              </p>
+            </td>
+          </tr>
+        </table>
+      </td>
+    </tr>
+  </table>
+</body>
+</html>`
+
+const notificationTextTemplate = `{{.Title}}
+{{range .Paragraphs}}
+{{.}}
+{{end}}{{if .ActionURL}}
+{{.ActionLabel}}:
+{{.ActionURL}}
+{{end}}
+---
+Sent by {{.AppName}} - AI Observability Platform`
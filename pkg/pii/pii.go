@@ -0,0 +1,364 @@
+// Package pii detects and redacts personal data and credentials in free text and
+// attribute maps: emails, phone numbers, payment card numbers (Luhn checked),
+// IBANs (mod-97 checked), US social security numbers, IP addresses and API-key-like
+// secrets, plus caller-supplied regular expressions.
+package pii
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+)
+
+// Mode selects how a detected value is replaced.
+type Mode string
+
+const (
+	// ModeMask replaces a value with [REDACTED:<detector>]
+	ModeMask Mode = "mask"
+	// ModeHash replaces a value with [<detector>:<keyed hash>], so equal values stay correlatable
+	ModeHash Mode = "hash"
+	// ModeDrop removes the value; denied attributes are removed entirely
+	ModeDrop Mode = "drop"
+)
+
+// IsValid returns true for a known mode.
+func (m Mode) IsValid() bool {
+	return m == ModeMask || m == ModeHash || m == ModeDrop
+}
+
+// Detector names a built-in detector.
+type Detector string
+
+const (
+	DetectorEmail      Detector = "email"
+	DetectorPhone      Detector = "phone"
+	DetectorCreditCard Detector = "credit_card"
+	DetectorIBAN       Detector = "iban"
+	DetectorSSN        Detector = "ssn"
+	DetectorIPAddress  Detector = "ip_address"
+	DetectorSecret     Detector = "secret"
+)
+
+// Detectors lists the built-in detectors in the order they run. Earlier detectors
+// claim overlapping text first, so an IP address is never read as a phone number.
+var Detectors = []Detector{
+	DetectorSecret,
+	DetectorEmail,
+	DetectorIBAN,
+	DetectorCreditCard,
+	DetectorSSN,
+	DetectorIPAddress,
+	DetectorPhone,
+}
+
+// IsValid returns true for a built-in detector.
+func (d Detector) IsValid() bool {
+	_, ok := builtins[d]
+	return ok
+}
+
+// Pattern is a custom detector. Name labels masked values.
+type Pattern struct {
+	Name    string `json:"name"`
+	Pattern string `json:"pattern"`
+}
+
+// Config configures a Redactor.
+type Config struct {
+	Mode      Mode
+	Detectors []Detector
+	Patterns  []Pattern
+	// Allow lists attribute keys that are never redacted. A trailing * matches a prefix.
+	Allow []string
+	// Deny lists attribute keys whose whole value is redacted. A trailing * matches a prefix.
+	Deny []string
+	// HashKey keys ModeHash, so hashes cannot be matched against another key's
+	HashKey []byte
+}
+
+type rule struct {
+	re    *regexp.Regexp
+	valid func(match string) bool // Filters regex candidates; nil accepts every match
+	name  string
+}
+
+var builtins = map[Detector]rule{
+	DetectorSecret: {name: string(DetectorSecret), re: regexp.MustCompile(strings.Join([]string{
+		`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`,
+		`\bsk-(?:proj-|ant-)?[A-Za-z0-9_-]{20,}`,
+		`\bsk_(?:live|test)_[A-Za-z0-9]{16,}`,
+		`\bbk_[A-Za-z0-9]{40}\b`,
+		`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`,
+		`\bgh[pousr]_[A-Za-z0-9]{36,}\b`,
+		`\bxox[abprs]-[A-Za-z0-9-]{10,}`,
+		`\bAIza[0-9A-Za-z_-]{35}`,
+		`\beyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}`,
+		`(?i)\bbearer\s+[A-Za-z0-9._~+/-]{20,}=*`,
+	}, "|"))},
+	DetectorEmail: {name: string(DetectorEmail), re: regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}\b`)},
+	DetectorIBAN: {
+		name:  string(DetectorIBAN),
+		re:    regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]{4}){2,7}(?: ?[A-Z0-9]{1,3})?\b`),
+		valid: validIBAN,
+	},
+	DetectorCreditCard: {
+		name:  string(DetectorCreditCard),
+		re:    regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),
+		valid: validCardNumber,
+	},
+	DetectorSSN: {
+		name:  string(DetectorSSN),
+		re:    regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`),
+		valid: validSSN,
+	},
+	DetectorIPAddress: {
+		name:  string(DetectorIPAddress),
+		re:    regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b|\b(?i:[0-9a-f]{1,4}:{1,2}){2,7}(?i:[0-9a-f]{1,4})\b`),
+		valid: func(match string) bool { return net.ParseIP(match) != nil },
+	},
+	DetectorPhone: {
+		name: string(DetectorPhone),
+		re: regexp.MustCompile(strings.Join([]string{
+			`\+\d{1,3}[ .-]?(?:\(\d{1,4}\)[ .-]?)?\d{2,4}[ .-]?\d{3,4}[ .-]?\d{3,5}\b`,
+			`\(\d{2,4}\)[ .-]?\d{3,4}[ .-]\d{3,5}\b`,
+			`\b\d{3}([.-]|\s)\d{3}([.-]|\s)\d{4}\b`,
+		}, "|")),
+		valid: func(match string) bool {
+			digits := len(onlyDigits(match))
+			return digits >= 7 && digits <= 15
+		},
+	},
+}
+
+// Redactor applies one redaction configuration. It is safe for concurrent use.
+type Redactor struct {
+	rules   []rule
+	allow   []string
+	deny    []string
+	hashKey []byte
+	mode    Mode
+}
+
+// New compiles a configuration. Built-in detectors run in Detectors order, then custom patterns.
+func New(cfg Config) (*Redactor, error) {
+	mode := cfg.Mode
+	if mode == "" {
+		mode = ModeMask
+	}
+	if !mode.IsValid() {
+		return nil, fmt.Errorf("unknown redaction mode %q", cfg.Mode)
+	}
+
+	enabled := make(map[Detector]bool, len(cfg.Detectors))
+	for _, detector := range cfg.Detectors {
+		if !detector.IsValid() {
+			return nil, fmt.Errorf("unknown detector %q", detector)
+		}
+		enabled[detector] = true
+	}
+
+	r := &Redactor{
+		mode:    mode,
+		allow:   cfg.Allow,
+		deny:    cfg.Deny,
+		hashKey: cfg.HashKey,
+	}
+	for _, detector := range Detectors {
+		if enabled[detector] {
+			r.rules = append(r.rules, builtins[detector])
+		}
+	}
+	for _, pattern := range cfg.Patterns {
+		re, err := regexp.Compile(pattern.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("pattern %q: %w", pattern.Name, err)
+		}
+		r.rules = append(r.rules, rule{name: pattern.Name, re: re})
+	}
+	return r, nil
+}
+
+// RedactString replaces every detected value in s and returns the number replaced.
+func (r *Redactor) RedactString(s string) (string, int) {
+	if r == nil || s == "" {
+		return s, 0
+	}
+
+	count := 0
+	for _, rl := range r.rules {
+		s = rl.re.ReplaceAllStringFunc(s, func(match string) string {
+			if rl.valid != nil && !rl.valid(match) {
+				return match
+			}
+			count++
+			return r.replacement(rl.name, match)
+		})
+	}
+	return s, count
+}
+
+// RedactValue redacts the strings of a decoded JSON-like value: strings, slices
+// and maps, recursively. Other values are returned unchanged.
+func (r *Redactor) RedactValue(value interface{}) (interface{}, int) {
+	if r == nil {
+		return value, 0
+	}
+
+	switch v := value.(type) {
+	case string:
+		return r.RedactString(v)
+	case []interface{}:
+		count := 0
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			var n int
+			redacted[i], n = r.RedactValue(item)
+			count += n
+		}
+		return redacted, count
+	case map[string]interface{}:
+		count := 0
+		redacted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			var n int
+			redacted[key], n = r.RedactValue(item)
+			count += n
+		}
+		return redacted, count
+	default:
+		return value, 0
+	}
+}
+
+// RedactAttributes redacts an attribute map in place and returns the number of
+// redactions. Allow and deny lists apply to its top-level keys: denied keys are
+// redacted whole, allowed keys are left untouched.
+func (r *Redactor) RedactAttributes(attrs map[string]interface{}) int {
+	if r == nil {
+		return 0
+	}
+
+	count := 0
+	for key, value := range attrs {
+		switch {
+		case matchesKey(r.allow, key):
+			continue
+		case matchesKey(r.deny, key):
+			count++
+			if r.mode == ModeDrop {
+				delete(attrs, key)
+				continue
+			}
+			attrs[key] = r.replacement("attribute", stringify(value))
+		default:
+			var n int
+			attrs[key], n = r.RedactValue(value)
+			count += n
+		}
+	}
+	return count
+}
+
+func (r *Redactor) replacement(name, match string) string {
+	switch r.mode {
+	case ModeDrop:
+		return ""
+	case ModeHash:
+		mac := hmac.New(sha256.New, r.hashKey)
+		mac.Write([]byte(match))
+		return "[" + name + ":" + hex.EncodeToString(mac.Sum(nil))[:16] + "]"
+	default:
+		return "[REDACTED:" + name + "]"
+	}
+}
+
+func matchesKey(patterns []string, key string) bool {
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		} else if key == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+func stringify(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(encoded)
+}
+
+func onlyDigits(s string) string {
+	var b strings.Builder
+	for _, c := range s {
+		if c >= '0' && c <= '9' {
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+// validCardNumber applies the Luhn checksum to 13-19 digit numbers.
+func validCardNumber(match string) bool {
+	digits := onlyDigits(match)
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// validIBAN applies the ISO 13616 mod-97 check.
+func validIBAN(match string) bool {
+	iban := strings.ReplaceAll(match, " ", "")
+	if len(iban) < 15 || len(iban) > 34 {
+		return false
+	}
+
+	rearranged := iban[4:] + iban[:4]
+	remainder := 0
+	for _, c := range rearranged {
+		switch {
+		case c >= '0' && c <= '9':
+			remainder = (remainder*10 + int(c-'0')) % 97
+		case c >= 'A' && c <= 'Z':
+			remainder = (remainder*100 + int(c-'A') + 10) % 97
+		default:
+			return false
+		}
+	}
+	return remainder == 1
+}
+
+// validSSN rejects numbers the SSA never issues: area 000, 666 or 9xx, group 00, serial 0000.
+func validSSN(match string) bool {
+	area, group, serial := match[0:3], match[4:6], match[7:11]
+	return area != "000" && area != "666" && area[0] != '9' && group != "00" && serial != "0000"
+}
@@ -0,0 +1,28 @@
+// Package sampling makes deterministic keep/drop decisions for traces. Every span
+// of a trace maps to the same value, so a decision holds across batches, transports
+// and processes; different salts give independent decisions for the same trace.
+package sampling
+
+import "hash/fnv"
+
+// TraceValue maps a trace ID to a uniform value in [0, 1).
+func TraceValue(traceID, salt string) float64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(salt))
+	_, _ = h.Write([]byte{':'})
+	_, _ = h.Write([]byte(traceID))
+
+	// FNV barely mixes trailing bytes into the high bits; finalize before scaling
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return float64(x>>11) / float64(uint64(1)<<53)
+}
+
+// KeepTrace reports whether a trace is kept at the given rate in [0, 1].
+func KeepTrace(traceID, salt string, rate float64) bool {
+	return TraceValue(traceID, salt) < rate
+}
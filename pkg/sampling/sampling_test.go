@@ -0,0 +1,42 @@
+package sampling
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestTraceValue_Deterministic(t *testing.T) {
+	if TraceValue("4bf92f3577b34da6a3ce929d0e0e4736", "head") != TraceValue("4bf92f3577b34da6a3ce929d0e0e4736", "head") {
+		t.Fatal("same trace and salt must map to the same value")
+	}
+}
+
+func TestKeepTrace_Rate(t *testing.T) {
+	const traces = 20000
+	for _, rate := range []float64{0, 0.1, 0.5, 1} {
+		kept := 0
+		for i := range traces {
+			if KeepTrace(fmt.Sprintf("%032x", i), "budget", rate) {
+				kept++
+			}
+		}
+		got := float64(kept) / traces
+		if got < rate-0.02 || got > rate+0.02 {
+			t.Errorf("rate %.1f kept %.3f of traces", rate, got)
+		}
+	}
+}
+
+func TestKeepTrace_SaltsAreIndependent(t *testing.T) {
+	const traces = 20000
+	both := 0
+	for i := range traces {
+		id := fmt.Sprintf("%032x", i)
+		if KeepTrace(id, "head", 0.5) && KeepTrace(id, "budget", 0.5) {
+			both++
+		}
+	}
+	if got := float64(both) / traces; got < 0.23 || got > 0.27 {
+		t.Errorf("expected about a quarter of traces kept by both salts, got %.3f", got)
+	}
+}
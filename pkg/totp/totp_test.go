@@ -0,0 +1,73 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// RFC 6238 appendix B test vectors (SHA1), truncated to 6 digits
+func TestCode_RFC6238(t *testing.T) {
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		got, err := Code(secret, Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("Code(%d) error: %v", tt.unix, err)
+		}
+		if got != tt.want {
+			t.Errorf("Code(%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+
+	previous, _ := Code(secret, Step(now)-1)
+	if step, ok := Validate(secret, previous, now, 1); !ok || step != Step(now)-1 {
+		t.Errorf("expected code of the previous step to validate with skew 1")
+	}
+	if _, ok := Validate(secret, previous, now, 0); ok {
+		t.Errorf("expected code of the previous step to fail without skew")
+	}
+
+	current, _ := Code(secret, Step(now))
+	if _, ok := Validate(secret, current[:3]+" "+current[3:], now, 0); !ok {
+		t.Errorf("expected spaced code to validate")
+	}
+	for _, code := range []string{"", "12345", "1234567", "abcdef"} {
+		if _, ok := Validate(secret, code, now, 1); ok {
+			t.Errorf("expected %q to fail", code)
+		}
+	}
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := ProvisioningURI("Brokle", "jane@example.com", "JBSWY3DPEHPK3PXP")
+	if !strings.HasPrefix(uri, "otpauth://totp/Brokle:jane@example.com?") {
+		t.Errorf("unexpected label in %s", uri)
+	}
+	for _, param := range []string{"secret=JBSWY3DPEHPK3PXP", "issuer=Brokle", "digits=6", "period=30"} {
+		if !strings.Contains(uri, param) {
+			t.Errorf("expected %s in %s", param, uri)
+		}
+	}
+}
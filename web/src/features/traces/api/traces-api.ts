@@ -500,9 +500,8 @@ export const updateSpan = async (
   spanId: string,
   data: UpdateSpanData
 ): Promise<Span> => {
-  const response = await client.put<any>(`/v1/spans/${spanId}`, {
-    project_id: projectId,
-    ...data,
+  const response = await client.put<any>(`/v1/spans/${spanId}`, data, {
+    params: { project_id: projectId },
   })
   return transformSpan(response)
 }
@@ -575,9 +574,8 @@ export const updateScore = async (
   scoreId: string,
   data: UpdateScoreData
 ): Promise<Score> => {
-  const response = await client.put<any>(`/v1/scores/${scoreId}`, {
-    project_id: projectId,
-    ...data,
+  const response = await client.put<any>(`/v1/scores/${scoreId}`, data, {
+    params: { project_id: projectId },
   })
   return transformScore(response)
 }